@@ -0,0 +1,102 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// AccessTokenScope is the scope of an access token.
+type AccessTokenScope string
+
+const (
+	// AccessTokenScopeReadOnly allows the token to read the resources only.
+	AccessTokenScopeReadOnly AccessTokenScope = "read-only"
+	// AccessTokenScopeSQLReview allows the token to read the resources and to run the SQL review, e.g. from the CI.
+	AccessTokenScopeSQLReview AccessTokenScope = "sql-review"
+	// AccessTokenScopeIssueCreate allows the token to read the resources and to create the issues.
+	AccessTokenScopeIssueCreate AccessTokenScope = "issue-create"
+)
+
+// AccessToken is the API message for the long-lived access tokens of the API.
+// It's a personal access token of an end user, or a key of a service account.
+type AccessToken struct {
+	ID int `jsonapi:"primary,accessToken"`
+
+	// Standard fields
+	CreatorID int   `jsonapi:"attr,creatorId"`
+	CreatedTs int64 `jsonapi:"attr,createdTs"`
+	UpdatedTs int64 `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	PrincipalID int `jsonapi:"attr,principalId"`
+	// ProjectID restricts the token to the project and its databases, issues, pipelines and sheets, 0 means no restriction.
+	ProjectID int `jsonapi:"attr,projectId"`
+
+	// Domain specific fields
+	Name string `jsonapi:"attr,name"`
+	// ScopeList is the list of AccessTokenScope, the token has the full access of the principal if empty.
+	ScopeList []string `jsonapi:"attr,scopeList"`
+	// ExpiresTs is the expiration time of the token, 0 means the token never expires.
+	ExpiresTs  int64 `jsonapi:"attr,expiresTs"`
+	LastUsedTs int64 `jsonapi:"attr,lastUsedTs"`
+	// TokenHash is the SHA-256 hash of the token, it's never returned to the client.
+	TokenHash string
+	// Token is only returned for the first time after the creation.
+	Token string `jsonapi:"attr,token"`
+}
+
+// AccessTokenCreate is the API message for creating an access token.
+type AccessTokenCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Related fields
+	PrincipalID int
+	ProjectID   int `jsonapi:"attr,projectId"`
+
+	// Domain specific fields
+	Name      string   `jsonapi:"attr,name"`
+	ScopeList []string `jsonapi:"attr,scopeList"`
+	ExpiresTs int64    `jsonapi:"attr,expiresTs"`
+	TokenHash string
+}
+
+// AccessTokenFind is the API message for finding access tokens.
+type AccessTokenFind struct {
+	ID *int
+
+	// Related fields
+	PrincipalID *int
+
+	// Domain specific fields
+	TokenHash *string
+}
+
+func (find *AccessTokenFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// AccessTokenPatch is the API message for patching an access token.
+type AccessTokenPatch struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Domain specific fields
+	LastUsedTs *int64
+}
+
+// AccessTokenDelete is the API message for deleting an access token.
+type AccessTokenDelete struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	DeleterID int
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 
 	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/db"
 	"github.com/bytebase/bytebase/plugin/vcs"
 )
 
@@ -63,6 +64,30 @@ const (
 
 	// ActivityDatabaseRecoveryPITRDone is the type for performing PITR on the database successfully.
 	ActivityDatabaseRecoveryPITRDone ActivityType = "bb.database.recovery.pitr.done"
+
+	// Instance related.
+
+	// ActivityInstanceCreate is the type for creating instances.
+	ActivityInstanceCreate ActivityType = "bb.instance.create"
+	// ActivityInstanceUpdate is the type for updating instances, including archiving and restoring.
+	ActivityInstanceUpdate ActivityType = "bb.instance.update"
+
+	// Environment related.
+
+	// ActivityEnvironmentCreate is the type for creating environments.
+	ActivityEnvironmentCreate ActivityType = "bb.environment.create"
+	// ActivityEnvironmentUpdate is the type for updating environments, including archiving and restoring.
+	ActivityEnvironmentUpdate ActivityType = "bb.environment.update"
+
+	// Policy related.
+
+	// ActivityPolicyUpdate is the type for creating or updating policies.
+	ActivityPolicyUpdate ActivityType = "bb.policy.update"
+
+	// Anomaly related.
+
+	// ActivityAnomalyCreate is the type for detecting new anomalies.
+	ActivityAnomalyCreate ActivityType = "bb.anomaly.create"
 )
 
 // ActivityLevel is the level of activities.
@@ -208,6 +233,53 @@ type ActivitySQLEditorQueryPayload struct {
 	AdviceList             []advisor.Advice `json:"adviceList"`
 }
 
+// ActivityInstanceCreatePayload is the API message payloads for creating instances.
+type ActivityInstanceCreatePayload struct {
+	// Used by activity table to display info without paying the join cost
+	InstanceName string  `json:"instanceName"`
+	Engine       db.Type `json:"engine"`
+}
+
+// ActivityInstanceUpdatePayload is the API message payloads for updating instances.
+type ActivityInstanceUpdatePayload struct {
+	// Used by activity table to display info without paying the join cost
+	InstanceName string    `json:"instanceName"`
+	RowStatus    RowStatus `json:"rowStatus"`
+}
+
+// ActivityEnvironmentCreatePayload is the API message payloads for creating environments.
+type ActivityEnvironmentCreatePayload struct {
+	// Used by activity table to display info without paying the join cost
+	EnvironmentName string `json:"environmentName"`
+}
+
+// ActivityEnvironmentUpdatePayload is the API message payloads for updating environments.
+type ActivityEnvironmentUpdatePayload struct {
+	// Used by activity table to display info without paying the join cost
+	EnvironmentName string    `json:"environmentName"`
+	RowStatus       RowStatus `json:"rowStatus"`
+}
+
+// ActivityPolicyUpdatePayload is the API message payloads for creating or updating policies.
+type ActivityPolicyUpdatePayload struct {
+	ResourceType PolicyResourceType `json:"resourceType"`
+	ResourceID   int                `json:"resourceId"`
+	PolicyType   PolicyType         `json:"policyType"`
+	// Payload is the new policy payload.
+	Payload string `json:"payload"`
+}
+
+// ActivityAnomalyCreatePayload is the API message payloads for detecting new anomalies.
+type ActivityAnomalyCreatePayload struct {
+	AnomalyType AnomalyType `json:"anomalyType"`
+	// Used by activity table to display info without paying the join cost
+	InstanceID   int    `json:"instanceId"`
+	InstanceName string `json:"instanceName"`
+	// DatabaseID and DatabaseName are empty for the instance anomalies.
+	DatabaseID   int    `json:"databaseId,omitempty"`
+	DatabaseName string `json:"databaseName,omitempty"`
+}
+
 // Activity is the API message for an activity.
 type Activity struct {
 	ID int `jsonapi:"primary,activity"`
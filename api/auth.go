@@ -24,11 +24,49 @@ type VCSLogin struct {
 	Code string `jsonapi:"attr,code"`
 }
 
+// OIDCProvider is the API message for the OpenID Connect single sign-on provider displayed on the login page.
+type OIDCProvider struct {
+	Name string `jsonapi:"attr,name"`
+}
+
+// OIDCAuthorizationCreate is the API message for starting the OpenID Connect authorization.
+type OIDCAuthorizationCreate struct {
+	// State is generated by the client to validate the OAuth callback.
+	State string `jsonapi:"attr,state"`
+}
+
+// OIDCAuthorization is the API message for the OpenID Connect authorization.
+type OIDCAuthorization struct {
+	// AuthorizeURL is the URL of the OpenID provider, which the user is redirected to.
+	AuthorizeURL string `jsonapi:"attr,authorizeUrl"`
+}
+
+// OIDCLogin is the API message for logging in via OpenID Connect.
+type OIDCLogin struct {
+	// Code is the authorization code granted by the OpenID provider.
+	Code string `jsonapi:"attr,code"`
+}
+
+// LDAPProvider is the API message for the LDAP directory displayed on the login page.
+type LDAPProvider struct {
+	Name string `jsonapi:"attr,name"`
+}
+
+// LDAPLogin is the API message for logging in via LDAP.
+type LDAPLogin struct {
+	// Username is matched by the user filter of the LDAP setting, e.g. the uid or sAMAccountName.
+	Username string `jsonapi:"attr,username"`
+	Password string `jsonapi:"attr,password"`
+}
+
 // Login is the API message for logins.
 type Login struct {
 	// Domain specific fields
 	Email    string `jsonapi:"attr,email"`
 	Password string `jsonapi:"attr,password"`
+	// OTPCode or RecoveryCode is required if the user has enabled the two-factor authentication.
+	OTPCode      string `jsonapi:"attr,otpCode"`
+	RecoveryCode string `jsonapi:"attr,recoveryCode"`
 }
 
 // SignUp is the API message for sign-ups.
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// CustomRole is the API message for a workspace-defined role composed of permissions.
+// The custom role can be assigned to the workspace members and the project members along with the built-in roles.
+type CustomRole struct {
+	ID int `jsonapi:"primary,customRole"`
+
+	// Standard fields
+	CreatorID int   `jsonapi:"attr,creatorId"`
+	CreatedTs int64 `jsonapi:"attr,createdTs"`
+	UpdaterID int   `jsonapi:"attr,updaterId"`
+	UpdatedTs int64 `jsonapi:"attr,updatedTs"`
+
+	// Domain specific fields
+	// Role is the unique key of the role stored in the memberships, e.g. "RELEASE_MANAGER".
+	Role        Role   `jsonapi:"attr,role"`
+	Name        string `jsonapi:"attr,name"`
+	Description string `jsonapi:"attr,description"`
+	// PermissionList is the list of Permission granted in the workspace and ProjectPermissionType granted in the projects.
+	PermissionList []string `jsonapi:"attr,permissionList"`
+}
+
+// HasPermission returns whether the custom role grants the workspace or project permission.
+func (role *CustomRole) HasPermission(permission string) bool {
+	for _, p := range role.PermissionList {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// CustomRoleCreate is the API message for creating a custom role.
+type CustomRoleCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Domain specific fields
+	Role           Role     `jsonapi:"attr,role"`
+	Name           string   `jsonapi:"attr,name"`
+	Description    string   `jsonapi:"attr,description"`
+	PermissionList []string `jsonapi:"attr,permissionList"`
+}
+
+// CustomRoleFind is the API message for finding custom roles.
+type CustomRoleFind struct {
+	ID *int
+
+	// Domain specific fields
+	Role *Role
+}
+
+func (find *CustomRoleFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// CustomRolePatch is the API message for patching a custom role.
+type CustomRolePatch struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Domain specific fields
+	Name        *string `jsonapi:"attr,name"`
+	Description *string `jsonapi:"attr,description"`
+	// PermissionList is not changed if nil.
+	PermissionList []string `jsonapi:"attr,permissionList"`
+}
+
+// CustomRoleDelete is the API message for deleting a custom role.
+type CustomRoleDelete struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	DeleterID int
+}
@@ -3,8 +3,13 @@ package api
 // ExternalApprovalType is the type of the ExternalApproval.
 type ExternalApprovalType string
 
-// ExternalApprovalTypeFeishu is the ExternalApproval from feishu.
-const ExternalApprovalTypeFeishu = "bb.plugin.app.feishu"
+const (
+	// ExternalApprovalTypeFeishu is the ExternalApproval from feishu.
+	ExternalApprovalTypeFeishu = "bb.plugin.app.feishu"
+	// ExternalApprovalTypeSlack is the interactive approval from Slack messages.
+	// Unlike Feishu, it's only used in the ExternalApprovalEvent and there is no persisted ExternalApproval.
+	ExternalApprovalTypeSlack = "bb.plugin.app.slack"
+)
 
 // ExternalApproval is the API message of ExternalApproval.
 // It only lives in the backend.
@@ -0,0 +1,21 @@
+package api
+
+// LDAPUser is the API message for the principal linked to an entry of the LDAP directory.
+// The principal is linked on the LDAP login, and the directory sync deactivates it once the entry is removed.
+type LDAPUser struct {
+	PrincipalID int
+
+	// Standard fields
+	CreatedTs int64
+	UpdatedTs int64
+
+	// Domain specific fields
+	// DN is the distinguished name of the directory entry.
+	DN string
+}
+
+// LDAPUserUpsert is the API message for linking a principal to an entry of the LDAP directory.
+type LDAPUserUpsert struct {
+	PrincipalID int
+	DN          string
+}
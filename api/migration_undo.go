@@ -0,0 +1,51 @@
+package api
+
+// MigrationUndo is the API message for the undo script of a schema migration version of a database.
+// The undo scripts are committed in the VCS along with the forward migration files, and are used to
+// create the rollback issues for the schema migrations.
+type MigrationUndo struct {
+	ID int `jsonapi:"primary,migrationUndo"`
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64 `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	UpdatedTs int64 `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	DatabaseID int `jsonapi:"attr,databaseId"`
+
+	// Domain specific fields
+	// Version is the schema version of the forward migration to undo.
+	Version   string `jsonapi:"attr,version"`
+	Statement string `jsonapi:"attr,statement"`
+	// FilePath is the path of the undo script in the repository.
+	FilePath string `jsonapi:"attr,filePath"`
+	// CommitID is the commit where the undo script is pushed.
+	CommitID string `jsonapi:"attr,commitId"`
+}
+
+// MigrationUndoUpsert is the API message for upserting a MigrationUndo.
+// The undo script is replaced if it's pushed again for the same version.
+type MigrationUndoUpsert struct {
+	// Standard fields
+	UpdaterID int
+
+	// Related fields
+	DatabaseID int
+
+	// Domain specific fields
+	Version   string
+	Statement string
+	FilePath  string
+	CommitID  string
+}
+
+// MigrationUndoFind is the API message for finding MigrationUndos.
+type MigrationUndoFind struct {
+	// Related fields
+	DatabaseID *int
+
+	// Domain specific fields
+	Version *string
+}
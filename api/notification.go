@@ -0,0 +1,144 @@
+package api
+
+// NotificationReason is the reason why a user receives the email notification of an activity.
+type NotificationReason string
+
+const (
+	// NotificationReasonApprovalNeeded is the reason that the user needs to approve the tasks of the issue.
+	NotificationReasonApprovalNeeded NotificationReason = "APPROVAL_NEEDED"
+	// NotificationReasonAssigned is the reason that the user is assigned to the issue.
+	NotificationReasonAssigned NotificationReason = "ASSIGNED"
+	// NotificationReasonMentioned is the reason that the user is mentioned in the comment.
+	NotificationReasonMentioned NotificationReason = "MENTIONED"
+	// NotificationReasonSubscribed is the reason that the user creates, is assigned to or subscribes the issue.
+	NotificationReasonSubscribed NotificationReason = "SUBSCRIBED"
+)
+
+// NotificationPreference is the API message for the email notification preference of a user.
+// The user receives all the email notifications immediately if the preference is not set.
+type NotificationPreference struct {
+	// PrincipalID is the ID of the user, which is also the ID of the preference.
+	PrincipalID int `jsonapi:"primary,notificationPreference"`
+
+	// Domain specific fields
+	EmailApprovalNeeded bool `jsonapi:"attr,emailApprovalNeeded"`
+	EmailAssigned       bool `jsonapi:"attr,emailAssigned"`
+	EmailMentioned      bool `jsonapi:"attr,emailMentioned"`
+	EmailSubscribed     bool `jsonapi:"attr,emailSubscribed"`
+	// EmailDigest batches the email notifications into a digest sent periodically.
+	EmailDigest bool `jsonapi:"attr,emailDigest"`
+}
+
+// DefaultNotificationPreference returns the default notification preference of the user.
+func DefaultNotificationPreference(principalID int) *NotificationPreference {
+	return &NotificationPreference{
+		PrincipalID:         principalID,
+		EmailApprovalNeeded: true,
+		EmailAssigned:       true,
+		EmailMentioned:      true,
+		EmailSubscribed:     true,
+	}
+}
+
+// IsEmailEnabled returns true if the user receives the email notification for the reason.
+func (p *NotificationPreference) IsEmailEnabled(reason NotificationReason) bool {
+	switch reason {
+	case NotificationReasonApprovalNeeded:
+		return p.EmailApprovalNeeded
+	case NotificationReasonAssigned:
+		return p.EmailAssigned
+	case NotificationReasonMentioned:
+		return p.EmailMentioned
+	case NotificationReasonSubscribed:
+		return p.EmailSubscribed
+	}
+	return false
+}
+
+// NotificationPreferenceUpsert is the API message for upserting the notification preference of a user.
+type NotificationPreferenceUpsert struct {
+	PrincipalID int
+
+	// Domain specific fields
+	EmailApprovalNeeded *bool `jsonapi:"attr,emailApprovalNeeded"`
+	EmailAssigned       *bool `jsonapi:"attr,emailAssigned"`
+	EmailMentioned      *bool `jsonapi:"attr,emailMentioned"`
+	EmailSubscribed     *bool `jsonapi:"attr,emailSubscribed"`
+	EmailDigest         *bool `jsonapi:"attr,emailDigest"`
+}
+
+// EmailNotificationStatus is the status of an email notification.
+type EmailNotificationStatus string
+
+const (
+	// EmailNotificationPending is the status of the email notifications waiting to be sent, or to be retried.
+	EmailNotificationPending EmailNotificationStatus = "PENDING"
+	// EmailNotificationSent is the status of the email notifications which have been sent.
+	EmailNotificationSent EmailNotificationStatus = "SENT"
+	// EmailNotificationFailed is the status of the email notifications failed to be sent after all the attempts.
+	EmailNotificationFailed EmailNotificationStatus = "FAILED"
+)
+
+// EmailNotification is the email notification of an activity to a user, which is sent by the mail runner.
+type EmailNotification struct {
+	ID int
+
+	// Standard fields
+	CreatedTs int64
+	UpdatedTs int64
+
+	// Related fields
+	ReceiverID int
+	ActivityID int
+
+	// Domain specific fields
+	Reason NotificationReason
+	Status EmailNotificationStatus
+	Error  string
+	// AttemptCount is the number of the failed attempts to send the notification.
+	AttemptCount int
+	// NextAttemptTs is the time of the next attempt after a failed one.
+	NextAttemptTs int64
+	// Payload is the JSON encoded EmailNotificationPayload.
+	Payload string
+}
+
+// EmailNotificationPayload is the content of the email notification.
+// It's captured when the activity is created, so that the email reflects the issue at that time.
+type EmailNotificationPayload struct {
+	Title       string `json:"title"`
+	IssueName   string `json:"issueName"`
+	ProjectName string `json:"projectName"`
+	Description string `json:"description"`
+	Link        string `json:"link"`
+	CreatorName string `json:"creatorName"`
+	CreatedTs   int64  `json:"createdTs"`
+}
+
+// EmailNotificationCreate is the API message for creating an email notification.
+type EmailNotificationCreate struct {
+	// Related fields
+	ReceiverID int
+	ActivityID int
+
+	// Domain specific fields
+	Reason  NotificationReason
+	Payload string
+}
+
+// EmailNotificationFind is the API message for finding email notifications.
+type EmailNotificationFind struct {
+	ReceiverID *int
+	Status     *EmailNotificationStatus
+}
+
+// EmailNotificationPatch is the API message for patching email notifications after sending them.
+type EmailNotificationPatch struct {
+	IDList []int
+
+	// Domain specific fields
+	Status        EmailNotificationStatus
+	Error         string
+	AttemptCount  int
+	NextAttemptTs int64
+}
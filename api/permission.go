@@ -0,0 +1,260 @@
+package api
+
+// Permission is the name of a workspace permission granted by a role.
+type Permission string
+
+const (
+	// PermissionActivityGet allows the member to get the activities.
+	PermissionActivityGet Permission = "activity.get"
+	// PermissionActivityCreate allows the member to create the activities, e.g. comment on the issues.
+	PermissionActivityCreate Permission = "activity.create"
+
+	// PermissionAnomalyGet allows the member to get the anomalies.
+	PermissionAnomalyGet Permission = "anomaly.get"
+
+	// PermissionDatabaseGet allows the member to get the databases, including the schemas, backups and data sources.
+	PermissionDatabaseGet Permission = "database.get"
+	// PermissionDatabaseUpdate allows the member to update the databases, including the backup settings and data sources.
+	PermissionDatabaseUpdate Permission = "database.update"
+	// PermissionDatabaseBackup allows the member to take the database backups.
+	PermissionDatabaseBackup Permission = "database.backup"
+	// PermissionDatabaseQuery allows the member to run the read-only queries against the databases.
+	PermissionDatabaseQuery Permission = "database.query"
+	// PermissionDatabaseAdminQuery allows the member to run the administrative queries, e.g. "SHOW PROCESSLIST".
+	PermissionDatabaseAdminQuery Permission = "database.admin-query"
+
+	// PermissionDebugGet allows the member to get the debug mode and the debug logs.
+	PermissionDebugGet Permission = "debug.get"
+	// PermissionDebugManage allows the member to switch the debug mode.
+	PermissionDebugManage Permission = "debug.manage"
+
+	// PermissionEnvironmentGet allows the member to get the environments.
+	PermissionEnvironmentGet Permission = "environment.get"
+	// PermissionEnvironmentCreate allows the member to create the environments.
+	PermissionEnvironmentCreate Permission = "environment.create"
+	// PermissionEnvironmentUpdate allows the member to update and reorder the environments.
+	PermissionEnvironmentUpdate Permission = "environment.update"
+	// PermissionEnvironmentDelete allows the member to delete the environments.
+	PermissionEnvironmentDelete Permission = "environment.delete"
+
+	// PermissionInstanceGet allows the member to get the instances, including the migration histories and users.
+	PermissionInstanceGet Permission = "instance.get"
+	// PermissionInstanceCreate allows the member to create the instances.
+	PermissionInstanceCreate Permission = "instance.create"
+	// PermissionInstanceCreateEmbedded allows the member to start an embedded PostgreSQL instance on the Bytebase host.
+	PermissionInstanceCreateEmbedded Permission = "instance.create-embedded"
+	// PermissionInstanceUpdate allows the member to update the instances, set up the migration schema and sync the schemas.
+	PermissionInstanceUpdate Permission = "instance.update"
+	// PermissionInstanceDelete allows the member to delete the instances.
+	PermissionInstanceDelete Permission = "instance.delete"
+
+	// PermissionIssueGet allows the member to get the issues.
+	PermissionIssueGet Permission = "issue.get"
+	// PermissionIssueCreate allows the member to create the issues.
+	PermissionIssueCreate Permission = "issue.create"
+	// PermissionIssueUpdate allows the member to update the issues and their pipelines.
+	// The task status change is still subject to the approval policy of the environment.
+	PermissionIssueUpdate Permission = "issue.update"
+	// PermissionIssueApprove allows the member to approve and run the tasks of any issue regardless of the approval policy.
+	PermissionIssueApprove Permission = "issue.approve"
+
+	// PermissionLabelGet allows the member to get the labels.
+	PermissionLabelGet Permission = "label.get"
+	// PermissionLabelUpdate allows the member to update the labels.
+	PermissionLabelUpdate Permission = "label.update"
+
+	// PermissionMemberGet allows the member to get the workspace members.
+	PermissionMemberGet Permission = "member.get"
+	// PermissionMemberManage allows the member to add the workspace members and to change their roles.
+	PermissionMemberManage Permission = "member.manage"
+
+	// PermissionPolicyGet allows the member to get the policies.
+	PermissionPolicyGet Permission = "policy.get"
+	// PermissionPolicyUpdate allows the member to update and delete the policies.
+	PermissionPolicyUpdate Permission = "policy.update"
+
+	// PermissionPrincipalGet allows the member to get the users.
+	PermissionPrincipalGet Permission = "principal.get"
+	// PermissionPrincipalCreate allows the member to create the users.
+	PermissionPrincipalCreate Permission = "principal.create"
+	// PermissionPrincipalUpdate allows the member to update other users, including their access tokens and two-factor authentication.
+	// The users can always update themselves.
+	PermissionPrincipalUpdate Permission = "principal.update"
+
+	// PermissionProjectGet allows the member to get the projects.
+	PermissionProjectGet Permission = "project.get"
+	// PermissionProjectCreate allows the member to create the projects.
+	PermissionProjectCreate Permission = "project.create"
+	// PermissionProjectUpdate allows the member to update the projects, subject to the project role of the member.
+	PermissionProjectUpdate Permission = "project.update"
+	// PermissionProjectAdmin allows the member to act as the project owner in all projects.
+	PermissionProjectAdmin Permission = "project.admin"
+
+	// PermissionRoleGet allows the member to get the custom roles.
+	PermissionRoleGet Permission = "role.get"
+	// PermissionRoleManage allows the member to create, update and delete the custom roles.
+	PermissionRoleManage Permission = "role.manage"
+
+	// PermissionSettingGet allows the member to get the workspace settings.
+	PermissionSettingGet Permission = "setting.get"
+	// PermissionSettingUpdate allows the member to update the workspace settings.
+	PermissionSettingUpdate Permission = "setting.update"
+
+	// PermissionSheetGet allows the member to get the sheets.
+	PermissionSheetGet Permission = "sheet.get"
+	// PermissionSheetCreate allows the member to create the sheets.
+	PermissionSheetCreate Permission = "sheet.create"
+	// PermissionSheetUpdate allows the member to update and organize the sheets, subject to the sheet visibility.
+	PermissionSheetUpdate Permission = "sheet.update"
+	// PermissionSheetDelete allows the member to delete the sheets, subject to the sheet visibility.
+	PermissionSheetDelete Permission = "sheet.delete"
+
+	// PermissionSubscriptionTrial allows the member to start a trial of the paid plans.
+	PermissionSubscriptionTrial Permission = "subscription.trial"
+	// PermissionSubscriptionUpdate allows the member to update the subscription license.
+	PermissionSubscriptionUpdate Permission = "subscription.update"
+
+	// PermissionVCSGet allows the member to get the VCS providers and their external repositories.
+	PermissionVCSGet Permission = "vcs.get"
+	// PermissionVCSGetRepository allows the member to get the repositories linked with the VCS providers.
+	PermissionVCSGetRepository Permission = "vcs.get-repository"
+	// PermissionVCSManage allows the member to create, update and delete the VCS providers.
+	PermissionVCSManage Permission = "vcs.manage"
+
+	// PermissionWorkspaceWebhookManage allows the member to manage the workspace webhooks.
+	PermissionWorkspaceWebhookManage Permission = "workspace-webhook.manage"
+)
+
+// PermissionList is the list of all workspace permissions.
+var PermissionList = []Permission{
+	PermissionActivityGet,
+	PermissionActivityCreate,
+	PermissionAnomalyGet,
+	PermissionDatabaseGet,
+	PermissionDatabaseUpdate,
+	PermissionDatabaseBackup,
+	PermissionDatabaseQuery,
+	PermissionDatabaseAdminQuery,
+	PermissionDebugGet,
+	PermissionDebugManage,
+	PermissionEnvironmentGet,
+	PermissionEnvironmentCreate,
+	PermissionEnvironmentUpdate,
+	PermissionEnvironmentDelete,
+	PermissionInstanceGet,
+	PermissionInstanceCreate,
+	PermissionInstanceCreateEmbedded,
+	PermissionInstanceUpdate,
+	PermissionInstanceDelete,
+	PermissionIssueGet,
+	PermissionIssueCreate,
+	PermissionIssueUpdate,
+	PermissionIssueApprove,
+	PermissionLabelGet,
+	PermissionLabelUpdate,
+	PermissionMemberGet,
+	PermissionMemberManage,
+	PermissionPolicyGet,
+	PermissionPolicyUpdate,
+	PermissionPrincipalGet,
+	PermissionPrincipalCreate,
+	PermissionPrincipalUpdate,
+	PermissionProjectGet,
+	PermissionProjectCreate,
+	PermissionProjectUpdate,
+	PermissionProjectAdmin,
+	PermissionRoleGet,
+	PermissionRoleManage,
+	PermissionSettingGet,
+	PermissionSettingUpdate,
+	PermissionSheetGet,
+	PermissionSheetCreate,
+	PermissionSheetUpdate,
+	PermissionSheetDelete,
+	PermissionSubscriptionTrial,
+	PermissionSubscriptionUpdate,
+	PermissionVCSGet,
+	PermissionVCSGetRepository,
+	PermissionVCSManage,
+	PermissionWorkspaceWebhookManage,
+}
+
+// ownerOnlyPermissionList is the list of permissions granted to the built-in OWNER role only.
+var ownerOnlyPermissionList = []Permission{
+	PermissionInstanceCreateEmbedded,
+	PermissionMemberManage,
+	PermissionPrincipalCreate,
+	PermissionPrincipalUpdate,
+	PermissionRoleManage,
+	PermissionSettingUpdate,
+	PermissionSubscriptionUpdate,
+	PermissionVCSManage,
+	PermissionWorkspaceWebhookManage,
+}
+
+// dbaPermissionList is the list of permissions granted to the built-in OWNER and DBA roles, but not the DEVELOPER role.
+var dbaPermissionList = []Permission{
+	PermissionDatabaseAdminQuery,
+	PermissionDebugManage,
+	PermissionEnvironmentCreate,
+	PermissionEnvironmentUpdate,
+	PermissionEnvironmentDelete,
+	PermissionInstanceCreate,
+	PermissionInstanceUpdate,
+	PermissionInstanceDelete,
+	PermissionIssueApprove,
+	PermissionLabelUpdate,
+	PermissionPolicyUpdate,
+	PermissionProjectAdmin,
+	PermissionSubscriptionTrial,
+	PermissionVCSGetRepository,
+}
+
+// IsBuiltInRole returns whether the role is one of the built-in OWNER, DBA and DEVELOPER roles.
+func IsBuiltInRole(role Role) bool {
+	return role == Owner || role == DBA || role == Developer
+}
+
+// BuiltInRolePermissionList returns the permissions of the built-in role, and nil if the role is not built-in.
+func BuiltInRolePermissionList(role Role) []Permission {
+	excluded := map[Permission]bool{}
+	switch role {
+	case Owner:
+	case DBA:
+		for _, permission := range ownerOnlyPermissionList {
+			excluded[permission] = true
+		}
+	case Developer:
+		for _, permission := range ownerOnlyPermissionList {
+			excluded[permission] = true
+		}
+		for _, permission := range dbaPermissionList {
+			excluded[permission] = true
+		}
+	default:
+		return nil
+	}
+
+	var permissionList []Permission
+	for _, permission := range PermissionList {
+		if !excluded[permission] {
+			permissionList = append(permissionList, permission)
+		}
+	}
+	return permissionList
+}
+
+// IsValidPermission returns whether the permission is a known workspace or project permission.
+func IsValidPermission(permission string) bool {
+	for _, p := range PermissionList {
+		if string(p) == permission {
+			return true
+		}
+	}
+	for _, p := range ProjectPermissionList {
+		if string(p) == permission {
+			return true
+		}
+	}
+	return false
+}
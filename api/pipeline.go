@@ -34,6 +34,9 @@ type Pipeline struct {
 	// Domain specific fields
 	Name   string         `jsonapi:"attr,name"`
 	Status PipelineStatus `jsonapi:"attr,status"`
+	// Held means the tasks of the pipeline are not scheduled until it's released, e.g. the pipeline of the
+	// draft issue created from an open pull request.
+	Held bool `jsonapi:"attr,held"`
 }
 
 // PipelineCreate is the API message for creating a pipeline.
@@ -47,6 +50,7 @@ type PipelineCreate struct {
 
 	// Domain specific fields
 	Name string `jsonapi:"attr,name"`
+	Held bool
 }
 
 // PipelineFind is the API message for finding pipelines.
@@ -75,4 +79,5 @@ type PipelinePatch struct {
 
 	// Domain specific fields
 	Status *PipelineStatus `jsonapi:"attr,status"`
+	Held   *bool
 }
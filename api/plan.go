@@ -59,6 +59,9 @@ const (
 	// - Workspace level RBAC
 	// - Project level RBAC.
 	FeatureRBAC FeatureType = "bb.feature.rbac"
+	// FeatureCustomRole allows user to define the custom roles composed of permissions,
+	// and to assign them to the workspace and project members.
+	FeatureCustomRole FeatureType = "bb.feature.custom-role"
 
 	// Branding.
 
@@ -150,6 +153,8 @@ func (e FeatureType) Name() string {
 		return "3rd party auth"
 	case FeatureRBAC:
 		return "RBAC"
+	case FeatureCustomRole:
+		return "Custom role"
 	// Branding
 	case FeatureBranding:
 		return "Branding"
@@ -217,6 +222,7 @@ var featureMatrix = map[FeatureType][3]bool{
 	// Admin & Security
 	Feature3rdPartyAuth: {false, true, true},
 	FeatureRBAC:         {false, true, true},
+	FeatureCustomRole:   {false, false, true},
 	// Branding
 	FeatureBranding: {false, false, true},
 	// Change Workflow
@@ -84,7 +84,7 @@ var (
 	allowedResourceTypes = map[PolicyType][]PolicyResourceType{
 		PolicyTypePipelineApproval: {PolicyResourceTypeEnvironment},
 		PolicyTypeBackupPlan:       {PolicyResourceTypeEnvironment},
-		PolicyTypeSQLReview:        {PolicyResourceTypeEnvironment},
+		PolicyTypeSQLReview:        {PolicyResourceTypeEnvironment, PolicyResourceTypeProject},
 		PolicyTypeEnvironmentTier:  {PolicyResourceTypeEnvironment},
 		PolicyTypeSensitiveData:    {PolicyResourceTypeDatabase},
 		PolicyTypeAccessControl:    {PolicyResourceTypeEnvironment, PolicyResourceTypeDatabase},
@@ -388,6 +388,13 @@ func ValidatePolicy(resourceType PolicyResourceType, pType PolicyType, payload *
 		if err != nil {
 			return err
 		}
+		// The project SQL review policy only contains the rules to override the environment policy.
+		if resourceType == PolicyResourceTypeProject {
+			if err := sr.ValidateOverride(); err != nil {
+				return errors.Wrap(err, "invalid project SQL review policy")
+			}
+			return nil
+		}
 		if err := sr.Validate(); err != nil {
 			return errors.Wrap(err, "invalid SQL review policy")
 		}
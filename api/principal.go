@@ -35,6 +35,18 @@ const (
 	PrincipalAuthProviderGitlabSelfHost PrincipalAuthProvider = "GITLAB_SELF_HOST"
 	// PrincipalAuthProviderGitHubCom is the GitHub.com authentication provider.
 	PrincipalAuthProviderGitHubCom PrincipalAuthProvider = "GITHUB_COM"
+	// PrincipalAuthProviderGitHubEnterprise is the GitHub Enterprise Server authentication provider.
+	PrincipalAuthProviderGitHubEnterprise PrincipalAuthProvider = "GITHUB_ENTERPRISE"
+	// PrincipalAuthProviderBitbucketCloud is the Bitbucket Cloud authentication provider.
+	PrincipalAuthProviderBitbucketCloud PrincipalAuthProvider = "BITBUCKET_CLOUD"
+	// PrincipalAuthProviderBitbucketDataCenter is the Bitbucket Data Center authentication provider.
+	PrincipalAuthProviderBitbucketDataCenter PrincipalAuthProvider = "BITBUCKET_DATA_CENTER"
+	// PrincipalAuthProviderAzureDevOps is the Azure DevOps authentication provider.
+	PrincipalAuthProviderAzureDevOps PrincipalAuthProvider = "AZURE_DEVOPS"
+	// PrincipalAuthProviderOIDC is the OpenID Connect single sign-on authentication provider.
+	PrincipalAuthProviderOIDC PrincipalAuthProvider = "OIDC"
+	// PrincipalAuthProviderLDAP is the LDAP authentication provider.
+	PrincipalAuthProviderLDAP PrincipalAuthProvider = "LDAP"
 )
 
 // Principal is the API message for principals.
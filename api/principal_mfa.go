@@ -0,0 +1,76 @@
+package api
+
+// PrincipalMFA is the API message for the two-factor authentication of a principal.
+// The time-based one-time password (TOTP) is verified on the password login if it's enabled.
+type PrincipalMFA struct {
+	PrincipalID int `jsonapi:"primary,principalMfa"`
+
+	// Standard fields
+	CreatedTs int64 `jsonapi:"attr,createdTs"`
+	UpdatedTs int64 `jsonapi:"attr,updatedTs"`
+
+	// Domain specific fields
+	Enabled bool `jsonapi:"attr,enabled"`
+	// RecoveryCodeCount is the number of the unused recovery codes.
+	RecoveryCodeCount int `jsonapi:"attr,recoveryCodeCount"`
+	// RecoveryCodeList is only returned for the first time after the recovery codes are generated.
+	RecoveryCodeList []string `jsonapi:"attr,recoveryCodeList"`
+	// OTPSecret is the secret of the enabled TOTP, it's never returned to the client.
+	OTPSecret string
+	// TempOTPSecret is the secret of the pending enrollment, it becomes the OTPSecret once verified.
+	TempOTPSecret string
+	// RecoveryCodeHashList is the SHA-256 hashes of the unused recovery codes, each of them can be used once
+	// instead of the TOTP code.
+	RecoveryCodeHashList []string
+	// LastOTPCounter is the time step counter of the last accepted TOTP code, so that the code can't be replayed.
+	LastOTPCounter int64
+	// FailedAttemptCount is the number of the consecutive failed verifications.
+	FailedAttemptCount int
+	// LockedUntilTs is the time until which the verification is locked after too many failed attempts.
+	LockedUntilTs int64
+}
+
+// PrincipalMFAUpsert is the API message for upserting the two-factor authentication of a principal.
+type PrincipalMFAUpsert struct {
+	PrincipalID int
+
+	// Domain specific fields
+	OTPSecret            string
+	TempOTPSecret        string
+	RecoveryCodeHashList []string
+}
+
+// MFAEnrollmentCreate is the API message for starting the enrollment of the two-factor authentication.
+// The password is required, because the enrollment may happen before login if the workspace requires it.
+type MFAEnrollmentCreate struct {
+	Email    string `jsonapi:"attr,email"`
+	Password string `jsonapi:"attr,password"`
+}
+
+// MFAEnrollment is the API message for the pending enrollment of the two-factor authentication.
+type MFAEnrollment struct {
+	// OTPSecret is the base32 encoded secret to be entered into the authenticator app manually.
+	OTPSecret string `jsonapi:"attr,otpSecret"`
+	// OTPAuthURL is the otpauth URI of the secret, which is rendered as a QR code.
+	OTPAuthURL string `jsonapi:"attr,otpAuthUrl"`
+}
+
+// MFAVerify is the API message for completing the enrollment of the two-factor authentication.
+type MFAVerify struct {
+	Email    string `jsonapi:"attr,email"`
+	Password string `jsonapi:"attr,password"`
+	// OTPCode is generated by the authenticator app from the pending secret.
+	OTPCode string `jsonapi:"attr,otpCode"`
+}
+
+// MFARecoveryCodeCreate is the API message for regenerating the recovery codes.
+type MFARecoveryCodeCreate struct {
+	OTPCode string `jsonapi:"attr,otpCode"`
+}
+
+// PrincipalMFADelete is the API message for disabling the two-factor authentication of a principal.
+// The user disabling their own two-factor authentication proves the second factor by either code.
+type PrincipalMFADelete struct {
+	OTPCode      string `jsonapi:"attr,otpCode"`
+	RecoveryCode string `jsonapi:"attr,recoveryCode"`
+}
@@ -17,6 +17,16 @@ const (
 	ProjectRoleProviderGitLabSelfHost ProjectRoleProvider = "GITLAB_SELF_HOST"
 	// ProjectRoleProviderGitHubCom indicates the role provider is the GitHub.com.
 	ProjectRoleProviderGitHubCom ProjectRoleProvider = "GITHUB_COM"
+	// ProjectRoleProviderGitHubEnterprise indicates the role provider is the
+	// GitHub Enterprise Server.
+	ProjectRoleProviderGitHubEnterprise ProjectRoleProvider = "GITHUB_ENTERPRISE"
+	// ProjectRoleProviderBitbucketCloud indicates the role provider is the Bitbucket Cloud.
+	ProjectRoleProviderBitbucketCloud ProjectRoleProvider = "BITBUCKET_CLOUD"
+	// ProjectRoleProviderBitbucketDataCenter indicates the role provider is the
+	// Bitbucket Data Center.
+	ProjectRoleProviderBitbucketDataCenter ProjectRoleProvider = "BITBUCKET_DATA_CENTER"
+	// ProjectRoleProviderAzureDevOps indicates the role provider is the Azure DevOps.
+	ProjectRoleProviderAzureDevOps ProjectRoleProvider = "AZURE_DEVOPS"
 )
 
 // ProjectRoleProviderPayload is the payload for role provider.
@@ -147,8 +157,25 @@ const (
 	ProjectPermissionCreateDatabase ProjectPermissionType = "bb.permission.project.create-database"
 	// ProjectPermissionTransferDatabase allows user to transfer database out of/into the project.
 	ProjectPermissionTransferDatabase ProjectPermissionType = "bb.permission.project.transfer-database"
+	// ProjectPermissionApproveIssue allows user to approve the issues in the project if the approval policy allows the project owner to approve.
+	ProjectPermissionApproveIssue ProjectPermissionType = "bb.permission.project.approve-issue"
 )
 
+// ProjectPermissionList is the list of all project permissions, which can be granted to the custom roles in the projects.
+var ProjectPermissionList = []ProjectPermissionType{
+	ProjectPermissionManageGeneral,
+	ProjectPermissionManageMember,
+	ProjectPermissionCreateSheet,
+	ProjectPermissionAdminSheet,
+	ProjectPermissionOrganizeSheet,
+	ProjectPermissionSyncSheet,
+	ProjectPermissionChangeDatabase,
+	ProjectPermissionAdminDatabase,
+	ProjectPermissionCreateDatabase,
+	ProjectPermissionTransferDatabase,
+	ProjectPermissionApproveIssue,
+}
+
 // ProjectPermission returns whether a particular permission is granted to a particular project role in a particular plan.
 func ProjectPermission(permission ProjectPermissionType, plan PlanType, role common.ProjectRole) bool {
 	// a map from the a particular feature to the respective enablement of a project developer and owner.
@@ -165,6 +192,7 @@ func ProjectPermission(permission ProjectPermissionType, plan PlanType, role com
 		ProjectPermissionCreateDatabase: {!Feature(FeatureDBAWorkflow, plan), true},
 		// If dba-workflow is disabled, then project developer can also transfer database.
 		ProjectPermissionTransferDatabase: {!Feature(FeatureDBAWorkflow, plan), true},
+		ProjectPermissionApproveIssue:     {false, true},
 	}
 
 	switch role {
@@ -25,6 +25,8 @@ type ProjectWebhook struct {
 	Name         string   `jsonapi:"attr,name"`
 	URL          string   `jsonapi:"attr,url"`
 	ActivityList []string `jsonapi:"attr,activityList"`
+	// Secret is used to sign the custom webhook request body with HMAC-SHA256, it's never returned to the client.
+	Secret string
 }
 
 // ProjectWebhookCreate is the API message for creating a project webhook.
@@ -41,6 +43,7 @@ type ProjectWebhookCreate struct {
 	Name         string   `jsonapi:"attr,name"`
 	URL          string   `jsonapi:"attr,url"`
 	ActivityList []string `jsonapi:"attr,activityList"`
+	Secret       string   `jsonapi:"attr,secret"`
 }
 
 // ProjectWebhookFind is the API message for finding project webhooks.
@@ -72,6 +75,7 @@ type ProjectWebhookPatch struct {
 	Name         *string `jsonapi:"attr,name"`
 	URL          *string `jsonapi:"attr,url"`
 	ActivityList *string `jsonapi:"attr,activityList"`
+	Secret       *string `jsonapi:"attr,secret"`
 }
 
 // ProjectWebhookDelete is the API message for deleting a project webhook.
@@ -0,0 +1,91 @@
+package api
+
+// ProjectWebhookDeliveryStatus is the status of an outbound project webhook delivery.
+type ProjectWebhookDeliveryStatus string
+
+const (
+	// ProjectWebhookDeliveryPending means the delivery is waiting for the first attempt or the next retry.
+	ProjectWebhookDeliveryPending ProjectWebhookDeliveryStatus = "PENDING"
+	// ProjectWebhookDeliverySending means the delivery is claimed by the runner and the attempt is in progress.
+	ProjectWebhookDeliverySending ProjectWebhookDeliveryStatus = "SENDING"
+	// ProjectWebhookDeliverySucceeded means the delivery is accepted by the webhook endpoint.
+	ProjectWebhookDeliverySucceeded ProjectWebhookDeliveryStatus = "SUCCEEDED"
+	// ProjectWebhookDeliveryFailed means the delivery has used up all the attempts, and it can be redelivered manually.
+	ProjectWebhookDeliveryFailed ProjectWebhookDeliveryStatus = "FAILED"
+)
+
+// ProjectWebhookDelivery is the API message for an outbound project webhook delivery.
+type ProjectWebhookDelivery struct {
+	ID int `jsonapi:"primary,projectWebhookDelivery"`
+
+	// Standard fields
+	CreatedTs int64 `jsonapi:"attr,createdTs"`
+	UpdatedTs int64 `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	ProjectWebhookID int `jsonapi:"attr,projectWebhookId"`
+
+	// Domain specific fields
+	ActivityType ActivityType                 `jsonapi:"attr,activityType"`
+	Status       ProjectWebhookDeliveryStatus `jsonapi:"attr,status"`
+	// AttemptCount is the number of the attempts that have been made.
+	AttemptCount int `jsonapi:"attr,attemptCount"`
+	// NextAttemptTs is the time of the next attempt if the status is PENDING.
+	NextAttemptTs int64 `jsonapi:"attr,nextAttemptTs"`
+	// ResponseCode is the HTTP status code of the last attempt, it's 0 if no response is received.
+	ResponseCode int    `jsonapi:"attr,responseCode"`
+	ResponseBody string `jsonapi:"attr,responseBody"`
+	Error        string `jsonapi:"attr,error"`
+	// Payload is the webhook context to post, it's the same for all the attempts.
+	Payload string `jsonapi:"attr,payload"`
+}
+
+// ProjectWebhookDeliveryCreate is the API message for creating a ProjectWebhookDelivery.
+type ProjectWebhookDeliveryCreate struct {
+	// Related fields
+	ProjectWebhookID int
+
+	// Domain specific fields
+	ActivityType ActivityType
+	Payload      string
+}
+
+// ProjectWebhookDeliveryFind is the API message for finding ProjectWebhookDeliveries.
+type ProjectWebhookDeliveryFind struct {
+	ID *int
+
+	// Related fields
+	ProjectWebhookID *int
+
+	// Domain specific fields
+	Status *ProjectWebhookDeliveryStatus
+	// MaxNextAttemptTs finds the deliveries which are due to attempt before the time.
+	MaxNextAttemptTs *int64
+	// Limit limits the number of the returned deliveries, which are sorted by ID in descending order.
+	Limit *int
+}
+
+// WebhookDeliveryClaim is the API message for claiming the due ProjectWebhookDeliveries or WorkspaceWebhookDeliveries
+// to attempt. The claimed deliveries are transited from PENDING to SENDING, so that each of them is sent only once.
+type WebhookDeliveryClaim struct {
+	// MaxNextAttemptTs claims the pending deliveries which are due to attempt before the time.
+	MaxNextAttemptTs int64
+	// MaxSendingUpdatedTs reclaims the sending deliveries which haven't been updated since the time, e.g. the
+	// runner is stopped in the middle of the attempt.
+	MaxSendingUpdatedTs int64
+	// Limit limits the number of the claimed deliveries, the earlier ones are claimed first.
+	Limit int
+}
+
+// WebhookDeliveryPatch is the API message for patching a ProjectWebhookDelivery or WorkspaceWebhookDelivery after an attempt.
+type WebhookDeliveryPatch struct {
+	ID int
+
+	// Domain specific fields
+	Status        ProjectWebhookDeliveryStatus
+	AttemptCount  int
+	NextAttemptTs int64
+	ResponseCode  int
+	ResponseBody  string
+	Error         string
+}
@@ -2,6 +2,9 @@ package api
 
 import (
 	"encoding/json"
+	"path/filepath"
+
+	"github.com/pkg/errors"
 )
 
 // Repository is the API message for a repository.
@@ -23,16 +26,20 @@ type Repository struct {
 	Project   *Project `jsonapi:"relation,project"`
 
 	// Domain specific fields
-	Name          string `jsonapi:"attr,name"`
-	FullPath      string `jsonapi:"attr,fullPath"`
-	WebURL        string `jsonapi:"attr,webUrl"`
-	BranchFilter  string `jsonapi:"attr,branchFilter"`
-	BaseDirectory string `jsonapi:"attr,baseDirectory"`
+	Name         string `jsonapi:"attr,name"`
+	FullPath     string `jsonapi:"attr,fullPath"`
+	WebURL       string `jsonapi:"attr,webUrl"`
+	BranchFilter string `jsonapi:"attr,branchFilter"`
+	// The rules mapping the branches to the environments, in addition to the BranchFilter.
+	BranchEnvironmentSetting BranchEnvironmentSetting `jsonapi:"attr,branchEnvironmentSetting"`
+	BaseDirectory            string                   `jsonapi:"attr,baseDirectory"`
 	// The file path template for matching the committed migration script.
 	FilePathTemplate string `jsonapi:"attr,filePathTemplate"`
 	// The file path template for storing the latest schema auto-generated by Bytebase after migration.
 	// If empty, then Bytebase won't auto generate it.
 	SchemaPathTemplate string `jsonapi:"attr,schemaPathTemplate"`
+	// The way to write back the latest schema to the branch.
+	SchemaWriteBackType SchemaWriteBackType `jsonapi:"attr,schemaWriteBackType"`
 	// The file path template for matching the sql files for sheet.
 	SheetPathTemplate string `jsonapi:"attr,sheetPathTemplate"`
 	// Setup CI to do SQL review for all PRs.
@@ -48,6 +55,69 @@ type Repository struct {
 	RefreshToken string
 }
 
+// SchemaWriteBackType is the way to write back the latest schema to the branch after migration.
+type SchemaWriteBackType string
+
+const (
+	// SchemaWriteBackCommit commits the latest schema to the branch directly.
+	SchemaWriteBackCommit SchemaWriteBackType = "COMMIT"
+	// SchemaWriteBackPullRequest commits the latest schema to a bot branch and opens a pull request to the branch,
+	// which is used when the branch is protected from direct pushes. The latest schema of multiple databases are
+	// batched in the same pull request until the branch moves on.
+	SchemaWriteBackPullRequest SchemaWriteBackType = "PULL_REQUEST"
+)
+
+// BranchEnvironmentRule maps the branches matching the filter to an environment, so that the migration files
+// pushed to the branches are applied to the databases in the environment.
+type BranchEnvironmentRule struct {
+	// BranchFilter is the branch name with wildcard support, e.g. "release/*".
+	BranchFilter  string `json:"branchFilter" jsonapi:"attr,branchFilter"`
+	EnvironmentID int    `json:"environmentId" jsonapi:"attr,environmentId"`
+}
+
+// BranchEnvironmentSetting is the setting of the branch-to-environment rules of the repository,
+// e.g. "develop" to dev, "release/*" to staging and "main" to prod. The same migration files
+// progress through the environments as they are merged across the branches.
+type BranchEnvironmentSetting struct {
+	// RuleList is matched in order, and the first matched rule wins.
+	RuleList []BranchEnvironmentRule `json:"ruleList" jsonapi:"attr,ruleList"`
+}
+
+// Scan implements database/sql Scanner interface, converts JSONB to BranchEnvironmentSetting struct.
+func (s *BranchEnvironmentSetting) Scan(src interface{}) error {
+	if bs, ok := src.([]byte); ok {
+		return json.Unmarshal(bs, s)
+	}
+	return errors.New("failed to scan branch_environment_setting")
+}
+
+// Validate validates the branch filters of the rules.
+func (s *BranchEnvironmentSetting) Validate() error {
+	for _, rule := range s.RuleList {
+		if rule.BranchFilter == "" {
+			return errors.New("branch filter of the branch environment rule must be specified")
+		}
+		if _, err := filepath.Match(rule.BranchFilter, ""); err != nil {
+			return errors.Wrapf(err, "invalid branch filter %q of the branch environment rule", rule.BranchFilter)
+		}
+	}
+	return nil
+}
+
+// FindRule returns the first rule matching the branch, or nil if no rule matches.
+func (s *BranchEnvironmentSetting) FindRule(branch string) (*BranchEnvironmentRule, error) {
+	for i, rule := range s.RuleList {
+		ok, err := filepath.Match(rule.BranchFilter, branch)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to match branch filter %q", rule.BranchFilter)
+		}
+		if ok {
+			return &s.RuleList[i], nil
+		}
+	}
+	return nil, nil
+}
+
 // SQLReviewCISetup is the API message for set up repository SQL review CI.
 type SQLReviewCISetup struct {
 	// PullRequestURL is the pull request URL to setup the SQL review CI.
@@ -68,14 +138,16 @@ type RepositoryCreate struct {
 	ProjectID int
 
 	// Domain specific fields
-	Name               string `jsonapi:"attr,name"`
-	FullPath           string `jsonapi:"attr,fullPath"`
-	WebURL             string `jsonapi:"attr,webUrl"`
-	BranchFilter       string `jsonapi:"attr,branchFilter"`
-	BaseDirectory      string `jsonapi:"attr,baseDirectory"`
-	FilePathTemplate   string `jsonapi:"attr,filePathTemplate"`
-	SchemaPathTemplate string `jsonapi:"attr,schemaPathTemplate"`
-	SheetPathTemplate  string `jsonapi:"attr,sheetPathTemplate"`
+	Name                     string                   `jsonapi:"attr,name"`
+	FullPath                 string                   `jsonapi:"attr,fullPath"`
+	WebURL                   string                   `jsonapi:"attr,webUrl"`
+	BranchFilter             string                   `jsonapi:"attr,branchFilter"`
+	BranchEnvironmentSetting BranchEnvironmentSetting `jsonapi:"attr,branchEnvironmentSetting"`
+	BaseDirectory            string                   `jsonapi:"attr,baseDirectory"`
+	FilePathTemplate         string                   `jsonapi:"attr,filePathTemplate"`
+	SchemaPathTemplate       string                   `jsonapi:"attr,schemaPathTemplate"`
+	SchemaWriteBackType      SchemaWriteBackType      `jsonapi:"attr,schemaWriteBackType"`
+	SheetPathTemplate        string                   `jsonapi:"attr,sheetPathTemplate"`
 	// EnableSQLReviewCI is only supported in the patch API.
 	ExternalID string `jsonapi:"attr,externalId"`
 	// Token belonged by the user linking the project to the VCS repository. We store this token together
@@ -122,15 +194,17 @@ type RepositoryPatch struct {
 	UpdaterID int
 
 	// Domain specific fields
-	BranchFilter       *string `jsonapi:"attr,branchFilter"`
-	BaseDirectory      *string `jsonapi:"attr,baseDirectory"`
-	FilePathTemplate   *string `jsonapi:"attr,filePathTemplate"`
-	SchemaPathTemplate *string `jsonapi:"attr,schemaPathTemplate"`
-	SheetPathTemplate  *string `jsonapi:"attr,sheetPathTemplate"`
-	EnableSQLReviewCI  *bool   `jsonapi:"attr,enableSQLReviewCI"`
-	AccessToken        *string
-	ExpiresTs          *int64
-	RefreshToken       *string
+	BranchFilter             *string                   `jsonapi:"attr,branchFilter"`
+	BranchEnvironmentSetting *BranchEnvironmentSetting `jsonapi:"attr,branchEnvironmentSetting"`
+	BaseDirectory            *string                   `jsonapi:"attr,baseDirectory"`
+	FilePathTemplate         *string                   `jsonapi:"attr,filePathTemplate"`
+	SchemaPathTemplate       *string                   `jsonapi:"attr,schemaPathTemplate"`
+	SchemaWriteBackType      *SchemaWriteBackType      `jsonapi:"attr,schemaWriteBackType"`
+	SheetPathTemplate        *string                   `jsonapi:"attr,sheetPathTemplate"`
+	EnableSQLReviewCI        *bool                     `jsonapi:"attr,enableSQLReviewCI"`
+	AccessToken              *string
+	ExpiresTs                *int64
+	RefreshToken             *string
 }
 
 // RepositoryDelete is the API message for deleting a repository.
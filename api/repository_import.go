@@ -0,0 +1,60 @@
+package api
+
+// RepositoryImportAction is the action of importing the migration files in the repository.
+type RepositoryImportAction string
+
+const (
+	// RepositoryImportActionReport only reports the status of the migration files against the migration history of the databases.
+	RepositoryImportActionReport RepositoryImportAction = "REPORT"
+	// RepositoryImportActionBaseline creates an issue to establish the baseline of the databases at the latest unapplied version,
+	// which is used when the databases already have the changes of the migration files applied outside of Bytebase.
+	RepositoryImportActionBaseline RepositoryImportAction = "BASELINE"
+	// RepositoryImportActionIssue creates issues to apply the unapplied migration files.
+	RepositoryImportActionIssue RepositoryImportAction = "ISSUE"
+)
+
+// RepositoryImportFileStatus is the status of a migration file for a database.
+type RepositoryImportFileStatus string
+
+const (
+	// RepositoryImportFileApplied means the version of the file has been applied to the database.
+	RepositoryImportFileApplied RepositoryImportFileStatus = "APPLIED"
+	// RepositoryImportFilePending means the version of the file hasn't been applied to the database.
+	RepositoryImportFilePending RepositoryImportFileStatus = "PENDING"
+	// RepositoryImportFileOutOfOrder means the version of the file hasn't been applied to the database,
+	// but a higher version has been applied, so it cannot be applied as a migration.
+	RepositoryImportFileOutOfOrder RepositoryImportFileStatus = "OUT_OF_ORDER"
+	// RepositoryImportFileFailed means the migration of the version is failed or still in progress.
+	RepositoryImportFileFailed RepositoryImportFileStatus = "FAILED"
+	// RepositoryImportFileIgnored means the file cannot be imported, e.g. the database is not found.
+	RepositoryImportFileIgnored RepositoryImportFileStatus = "IGNORED"
+)
+
+// RepositoryImport is the API message for importing the migration files in the repository.
+type RepositoryImport struct {
+	// Ref is the branch to scan the migration files. It's the branch filter of the repository if empty.
+	Ref    string                 `jsonapi:"attr,ref"`
+	Action RepositoryImportAction `jsonapi:"attr,action"`
+}
+
+// RepositoryImportFile is the status of a migration file in the repository for a database.
+type RepositoryImportFile struct {
+	FilePath string                     `json:"filePath"`
+	Version  string                     `json:"version"`
+	Status   RepositoryImportFileStatus `json:"status"`
+	// DatabaseID is 0 if the file is ignored before finding the databases.
+	DatabaseID      int    `json:"databaseId"`
+	DatabaseName    string `json:"databaseName"`
+	EnvironmentName string `json:"environmentName"`
+	Message         string `json:"message"`
+}
+
+// RepositoryImportResult is the API message for the result of importing the migration files in the repository.
+type RepositoryImportResult struct {
+	Ref string `jsonapi:"attr,ref"`
+	// CommitID is the last commit of the branch when the repository is scanned.
+	CommitID string                 `jsonapi:"attr,commitId"`
+	FileList []RepositoryImportFile `jsonapi:"attr,fileList"`
+	// IssueIDList is the issues created for the BASELINE and ISSUE actions.
+	IssueIDList []int `jsonapi:"attr,issueIdList"`
+}
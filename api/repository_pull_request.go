@@ -0,0 +1,83 @@
+package api
+
+// RepositoryPullRequestStatus is the status of the pull request in the VCS repository.
+type RepositoryPullRequestStatus string
+
+const (
+	// RepositoryPullRequestOpen means the pull request is open, and the issue created from it is a draft.
+	RepositoryPullRequestOpen RepositoryPullRequestStatus = "OPEN"
+	// RepositoryPullRequestMerged means the pull request is merged, and the issue created from it is ready.
+	RepositoryPullRequestMerged RepositoryPullRequestStatus = "MERGED"
+	// RepositoryPullRequestClosed means the pull request is closed without being merged, or the
+	// issue created from it is superseded by the new commits of the pull request.
+	RepositoryPullRequestClosed RepositoryPullRequestStatus = "CLOSED"
+)
+
+// RepositoryPullRequest is the API message for the issue created from a pull request in the VCS repository.
+// It only lives in the backend.
+type RepositoryPullRequest struct {
+	ID int
+
+	// Standard fields
+	CreatedTs int64
+	UpdatedTs int64
+
+	// Related fields
+	RepositoryID int
+	IssueID      int
+
+	// Domain specific fields
+	// PullRequestID is the pull request ID from the external VCS system, e.g. the pull request number in GitHub.
+	PullRequestID string
+	// BaseRef is the full ref of the target branch of the pull request, e.g. "refs/heads/main".
+	BaseRef string
+	Status  RepositoryPullRequestStatus
+	// HeadSHA is the last commit of the pull request when the issue is created.
+	HeadSHA string
+	Payload string
+}
+
+// RepositoryPullRequestPayload is the payload of the RepositoryPullRequest.
+type RepositoryPullRequestPayload struct {
+	URL string `json:"url"`
+	// FileList is the migration files added in the pull request. It's used to skip these files in the
+	// push event after the pull request is merged, because the issue has been created for them.
+	FileList []string `json:"fileList"`
+}
+
+// RepositoryPullRequestCreate is the API message for creating a RepositoryPullRequest.
+type RepositoryPullRequestCreate struct {
+	// Related fields
+	RepositoryID int
+	IssueID      int
+
+	// Domain specific fields
+	PullRequestID string
+	BaseRef       string
+	HeadSHA       string
+	Payload       string
+}
+
+// RepositoryPullRequestFind is the API message for finding RepositoryPullRequests.
+type RepositoryPullRequestFind struct {
+	// Related fields
+	RepositoryID *int
+	IssueID      *int
+
+	// Domain specific fields
+	PullRequestID *string
+	BaseRef       *string
+	// StatusList finds the pull requests in any of the statuses.
+	StatusList []RepositoryPullRequestStatus
+	Status     *RepositoryPullRequestStatus
+	// FileList finds the pull requests adding any of the files.
+	FileList []string
+}
+
+// RepositoryPullRequestPatch is the API message for patching a RepositoryPullRequest.
+type RepositoryPullRequestPatch struct {
+	ID int
+
+	// Domain specific fields
+	Status *RepositoryPullRequestStatus
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBranchEnvironmentSetting_FindRule(t *testing.T) {
+	setting := &BranchEnvironmentSetting{
+		RuleList: []BranchEnvironmentRule{
+			{BranchFilter: "develop", EnvironmentID: 101},
+			{BranchFilter: "release/*", EnvironmentID: 102},
+			{BranchFilter: "main", EnvironmentID: 103},
+			{BranchFilter: "*", EnvironmentID: 104},
+		},
+	}
+	tests := []struct {
+		branch string
+		want   int
+	}{
+		{branch: "develop", want: 101},
+		{branch: "release/1.0", want: 102},
+		{branch: "main", want: 103},
+		{branch: "feature", want: 104},
+		{branch: "feature/foo", want: 0},
+	}
+	for _, test := range tests {
+		rule, err := setting.FindRule(test.branch)
+		require.NoError(t, err)
+		if test.want == 0 {
+			assert.Nil(t, rule, test.branch)
+			continue
+		}
+		require.NotNil(t, rule, test.branch)
+		assert.Equal(t, test.want, rule.EnvironmentID, test.branch)
+	}
+}
+
+func TestBranchEnvironmentSetting_Validate(t *testing.T) {
+	assert.NoError(t, (&BranchEnvironmentSetting{}).Validate())
+	assert.NoError(t, (&BranchEnvironmentSetting{RuleList: []BranchEnvironmentRule{{BranchFilter: "release/*", EnvironmentID: 101}}}).Validate())
+	assert.Error(t, (&BranchEnvironmentSetting{RuleList: []BranchEnvironmentRule{{EnvironmentID: 101}}}).Validate())
+	assert.Error(t, (&BranchEnvironmentSetting{RuleList: []BranchEnvironmentRule{{BranchFilter: "release/[", EnvironmentID: 101}}}).Validate())
+}
@@ -0,0 +1,111 @@
+package api
+
+// RepositoryWebhookDeliveryStatus is the outcome of a webhook delivery received from the VCS.
+type RepositoryWebhookDeliveryStatus string
+
+const (
+	// RepositoryWebhookDeliveryProcessing means the event is being processed, it claims the event so that the concurrent redeliveries are skipped.
+	RepositoryWebhookDeliveryProcessing RepositoryWebhookDeliveryStatus = "PROCESSING"
+	// RepositoryWebhookDeliveryProcessed means the event is processed, e.g. issues are created from the push event.
+	RepositoryWebhookDeliveryProcessed RepositoryWebhookDeliveryStatus = "PROCESSED"
+	// RepositoryWebhookDeliveryIgnored means the event is processed but no applicable file is found.
+	RepositoryWebhookDeliveryIgnored RepositoryWebhookDeliveryStatus = "IGNORED"
+	// RepositoryWebhookDeliveryDuplicate means the event has been delivered before and is skipped.
+	RepositoryWebhookDeliveryDuplicate RepositoryWebhookDeliveryStatus = "DUPLICATE"
+	// RepositoryWebhookDeliveryFailed means the event fails to be processed, and it can be redelivered.
+	RepositoryWebhookDeliveryFailed RepositoryWebhookDeliveryStatus = "FAILED"
+)
+
+// RepositoryWebhookDeliveryPayload is the payload of the RepositoryWebhookDelivery.
+type RepositoryWebhookDeliveryPayload struct {
+	// ActivityList is the activities created for the ignored files when processing the event.
+	ActivityList []RepositoryWebhookDeliveryActivity `json:"activityList,omitempty"`
+}
+
+// RepositoryWebhookDeliveryActivity is the activity created when processing the webhook delivery.
+type RepositoryWebhookDeliveryActivity struct {
+	ID      int           `json:"id"`
+	Level   ActivityLevel `json:"level"`
+	Comment string        `json:"comment"`
+}
+
+// RepositoryWebhookDelivery is the API message for a webhook delivery received from the VCS for a repository.
+type RepositoryWebhookDelivery struct {
+	ID int `jsonapi:"primary,repositoryWebhookDelivery"`
+
+	// Standard fields
+	CreatedTs int64 `jsonapi:"attr,createdTs"`
+
+	// Related fields
+	RepositoryID int `jsonapi:"attr,repositoryId"`
+
+	// Domain specific fields
+	// EventUUID is the unique ID of the event from the VCS, e.g. the X-Gitlab-Event-UUID header. It may be empty.
+	EventUUID string `jsonapi:"attr,eventUuid"`
+	// EventType is the type of the event, e.g. "push" and "merge_request.open".
+	EventType string `jsonapi:"attr,eventType"`
+	// Ref is the full ref of the branch of the event, e.g. "refs/heads/main". The same commit may be pushed to
+	// several branches, and each of them is a different event.
+	Ref string `jsonapi:"attr,ref"`
+	// CommitSHA is the commit of the event, e.g. the after commit of the push event.
+	CommitSHA string                          `jsonapi:"attr,commitSha"`
+	Status    RepositoryWebhookDeliveryStatus `jsonapi:"attr,status"`
+	Message   string                          `jsonapi:"attr,message"`
+	Payload   string                          `jsonapi:"attr,payload"`
+}
+
+// RepositoryWebhookDeliveryCreate is the API message for creating a RepositoryWebhookDelivery.
+type RepositoryWebhookDeliveryCreate struct {
+	// Related fields
+	RepositoryID int
+
+	// Domain specific fields
+	EventUUID string
+	EventType string
+	Ref       string
+	CommitSHA string
+	Status    RepositoryWebhookDeliveryStatus
+	Message   string
+	Payload   string
+}
+
+// RepositoryWebhookDeliveryPatch is the API message for patching a RepositoryWebhookDelivery.
+type RepositoryWebhookDeliveryPatch struct {
+	ID int
+
+	// Domain specific fields
+	Status  RepositoryWebhookDeliveryStatus
+	Message string
+	Payload string
+}
+
+// RepositoryWebhookDeliveryFind is the API message for finding RepositoryWebhookDeliveries.
+type RepositoryWebhookDeliveryFind struct {
+	// Related fields
+	RepositoryID *int
+
+	// Domain specific fields
+	EventUUID  *string
+	EventType  *string
+	Ref        *string
+	CommitSHA  *string
+	StatusList *[]RepositoryWebhookDeliveryStatus
+	// Limit limits the number of the returned deliveries, which are sorted by the created time in descending order.
+	Limit *int
+}
+
+// RepositoryWebhookDeliveryExpire is the API message for expiring the stale processing deliveries of an event.
+// The processing delivery claims the event, and it's left processing if Bytebase restarts in the middle.
+type RepositoryWebhookDeliveryExpire struct {
+	// Related fields
+	RepositoryID int
+
+	// Domain specific fields
+	EventUUID string
+	EventType string
+	Ref       string
+	CommitSHA string
+	// UpdatedBefore expires the processing deliveries which haven't been updated since the timestamp.
+	UpdatedBefore int64
+	Message       string
+}
@@ -2,6 +2,8 @@ package api
 
 import (
 	"encoding/json"
+
+	"github.com/bytebase/bytebase/common"
 )
 
 // SettingName is the name of a setting.
@@ -20,6 +22,19 @@ const (
 	SettingEnterpriseTrial SettingName = "bb.enterprise.trial"
 	// SettingAppIM is the setting name for IM applications.
 	SettingAppIM SettingName = "bb.app.im"
+	// SettingMailDelivery is the setting name for the SMTP server delivering the email notifications.
+	SettingMailDelivery SettingName = "bb.workspace.mail-delivery"
+	// SettingAppSlack is the setting name for the Slack app handling the interactive approvals.
+	SettingAppSlack SettingName = "bb.app.slack"
+	// SettingAuthOIDC is the setting name for the OpenID Connect single sign-on.
+	SettingAuthOIDC SettingName = "bb.auth.oidc"
+	// SettingAuthLDAP is the setting name for the LDAP authentication and directory sync.
+	SettingAuthLDAP SettingName = "bb.auth.ldap"
+	// SettingAuthMFA is the setting name for the two-factor authentication policy of the workspace.
+	SettingAuthMFA SettingName = "bb.auth.mfa"
+	// SettingRepositoryWebhookPullRequest is the setting name for whether the webhooks of the repositories created
+	// before the pull request driven migrations are patched to subscribe to the pull request events.
+	SettingRepositoryWebhookPullRequest SettingName = "bb.repository.webhook-pull-request"
 )
 
 // IMType is the type of IM.
@@ -87,3 +102,110 @@ type SettingAppIMValue struct {
 		ApprovalDefinitionID string `json:"approvalDefinitionID"`
 	} `json:"externalApproval"`
 }
+
+// SettingMailDeliveryValue is the setting value of SettingMailDelivery type setting.
+type SettingMailDeliveryValue struct {
+	Enabled      bool   `json:"enabled"`
+	SMTPHost     string `json:"smtpHost"`
+	SMTPPort     int    `json:"smtpPort"`
+	SMTPUsername string `json:"smtpUsername"`
+	// SMTPPassword is never returned to the client.
+	SMTPPassword string `json:"smtpPassword"`
+	// SMTPFrom is the sender address of the emails.
+	SMTPFrom string `json:"smtpFrom"`
+	// SMTPEncryption is one of NONE, STARTTLS and SSL/TLS.
+	SMTPEncryption string `json:"smtpEncryption"`
+	// DigestIntervalSeconds is the interval of sending the digest emails to the users preferring digest.
+	DigestIntervalSeconds int `json:"digestIntervalSeconds"`
+}
+
+// SettingAppSlackValue is the setting value of SettingAppSlack type setting.
+// The interactive approvals require the Interactivity Request URL of the Slack app to be {{externalURL}}/hook/slack/interaction.
+type SettingAppSlackValue struct {
+	// SigningSecret is used to verify the requests from Slack, it's never returned to the client.
+	SigningSecret string `json:"signingSecret"`
+	// BotToken is used to get the email of the Slack user, it requires the users:read.email scope.
+	// It's never returned to the client.
+	BotToken string `json:"botToken"`
+}
+
+// SettingAuthOIDCValue is the setting value of SettingAuthOIDC type setting.
+// The redirect URI registered in the OpenID provider should be {{externalURL}}/oauth/callback.
+type SettingAuthOIDCValue struct {
+	Enabled bool `json:"enabled"`
+	// Name is the name of the provider displayed on the login page, e.g. Okta.
+	Name      string `json:"name"`
+	IssuerURL string `json:"issuerUrl"`
+	ClientID  string `json:"clientId"`
+	// ClientSecret is never returned to the client.
+	ClientSecret string   `json:"clientSecret"`
+	Scopes       []string `json:"scopes"`
+	// EmailClaim, NameClaim and GroupsClaim are the claims mapped to the user, default to email, name and groups.
+	EmailClaim  string `json:"emailClaim"`
+	NameClaim   string `json:"nameClaim"`
+	GroupsClaim string `json:"groupsClaim"`
+	// AutoProvision creates the user with the DefaultRole on the first login.
+	AutoProvision bool `json:"autoProvision"`
+	DefaultRole   Role `json:"defaultRole"`
+	// GroupRoleMapping maps the groups claim to the workspace role, which is synced on every login.
+	GroupRoleMapping []SSOGroupRoleMapping `json:"groupRoleMapping"`
+}
+
+// SettingAuthLDAPValue is the setting value of SettingAuthLDAP type setting.
+type SettingAuthLDAPValue struct {
+	Enabled bool `json:"enabled"`
+	// Name is the name of the directory displayed on the login page, e.g. Active Directory.
+	Name string `json:"name"`
+	Host string `json:"host"`
+	Port int    `json:"port"`
+	// SecurityProtocol is one of NONE, STARTTLS and LDAPS.
+	SecurityProtocol   string `json:"securityProtocol"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify"`
+	// BindDN and BindPassword are the credentials of the service account searching the users.
+	// BindPassword is never returned to the client.
+	BindDN       string `json:"bindDn"`
+	BindPassword string `json:"bindPassword"`
+	BaseDN       string `json:"baseDn"`
+	// UserFilter is the filter of the user, e.g. (uid=%s) or (sAMAccountName=%s), the %s is replaced by the username.
+	UserFilter string `json:"userFilter"`
+	// EmailAttribute, NameAttribute and GroupAttribute are the attributes mapped to the user, default to mail, cn and memberOf.
+	EmailAttribute string `json:"emailAttribute"`
+	NameAttribute  string `json:"nameAttribute"`
+	GroupAttribute string `json:"groupAttribute"`
+	// AutoProvision creates the user with the DefaultRole on the first login.
+	AutoProvision bool `json:"autoProvision"`
+	DefaultRole   Role `json:"defaultRole"`
+	// GroupRoleMapping maps the common names of the groups to the workspace role. If it's set, the users in none of
+	// the mapped groups fall back to the DefaultRole.
+	GroupRoleMapping []SSOGroupRoleMapping `json:"groupRoleMapping"`
+	// GroupProjectMapping maps the common names of the groups to the project membership.
+	GroupProjectMapping []LDAPGroupProjectMapping `json:"groupProjectMapping"`
+	// SyncIntervalSeconds is the interval of syncing the roles and project memberships of the LDAP users from the directory,
+	// and deactivating the users removed from the directory. It defaults to one hour.
+	SyncIntervalSeconds int `json:"syncIntervalSeconds"`
+}
+
+// SettingAuthMFAValue is the setting value of SettingAuthMFA type setting.
+type SettingAuthMFAValue struct {
+	// RequiredRoleList is the list of the workspace roles required to enable the two-factor authentication
+	// before logging in with the password, e.g. OWNER and DBA.
+	RequiredRoleList []Role `json:"requiredRoleList"`
+}
+
+// LDAPGroupProjectMapping maps a group of the LDAP directory to the project membership.
+type LDAPGroupProjectMapping struct {
+	Group     string             `json:"group"`
+	ProjectID int                `json:"projectId"`
+	Role      common.ProjectRole `json:"role"`
+}
+
+// SSOGroupRoleMapping maps a group of the single sign-on provider to the workspace role.
+type SSOGroupRoleMapping struct {
+	Group string `json:"group"`
+	Role  Role   `json:"role"`
+}
+
+// MailDeliveryTestResult is the result of sending a test email with the mail delivery setting.
+type MailDeliveryTestResult struct {
+	Error string `jsonapi:"attr,error"`
+}
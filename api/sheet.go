@@ -26,6 +26,14 @@ const (
 	SheetFromGitLabSelfHost SheetSource = "GITLAB_SELF_HOST"
 	// SheetFromGitHubCom is the sheet synced from github.com.
 	SheetFromGitHubCom SheetSource = "GITHUB_COM"
+	// SheetFromGitHubEnterprise is the sheet synced from GitHub Enterprise Server.
+	SheetFromGitHubEnterprise SheetSource = "GITHUB_ENTERPRISE"
+	// SheetFromBitbucketCloud is the sheet synced from Bitbucket Cloud.
+	SheetFromBitbucketCloud SheetSource = "BITBUCKET_CLOUD"
+	// SheetFromBitbucketDataCenter is the sheet synced from Bitbucket Data Center.
+	SheetFromBitbucketDataCenter SheetSource = "BITBUCKET_DATA_CENTER"
+	// SheetFromAzureDevOps is the sheet synced from Azure DevOps.
+	SheetFromAzureDevOps SheetSource = "AZURE_DEVOPS"
 )
 
 // SheetType is the type of sheet.
@@ -37,7 +45,7 @@ const (
 )
 
 // SheetVCSPayload is the additional data payload of the VCS sheet.
-// The sheet source should be one of SheetFromGitLabSelfHost and SheetFromGitHubCom.
+// The sheet source should be one of the VCS sheet sources, e.g. SheetFromGitLabSelfHost.
 type SheetVCSPayload struct {
 	FileName     string `json:"fileName"`
 	FilePath     string `json:"filePath"`
@@ -108,6 +108,10 @@ type TaskDatabaseSchemaUpdatePayload struct {
 	Statement     string         `json:"statement,omitempty"`
 	SchemaVersion string         `json:"schemaVersion,omitempty"`
 	VCSPushEvent  *vcs.PushEvent `json:"pushEvent,omitempty"`
+	// RollbackFromIssueID is the issue ID containing the original task whose undo script is applied by this task.
+	RollbackFromIssueID int `json:"rollbackFromIssueId,omitempty"`
+	// RollbackFromTaskID is the task ID whose undo script is applied by this task.
+	RollbackFromTaskID int `json:"rollbackFromTaskId,omitempty"`
 }
 
 // TaskDatabaseSchemaUpdateSDLPayload is the task payload for database schema update (SDL).
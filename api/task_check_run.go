@@ -124,6 +124,12 @@ type TaskCheckResult struct {
 	Status    TaskCheckStatus `json:"status,omitempty"`
 	Title     string          `json:"title,omitempty"`
 	Content   string          `json:"content,omitempty"`
+	// Line, StartPosition and EndPosition are the location of the advice in the statement for the SQL review check.
+	Line          int               `json:"line,omitempty"`
+	StartPosition *advisor.Position `json:"startPosition,omitempty"`
+	EndPosition   *advisor.Position `json:"endPosition,omitempty"`
+	// Excerpt is the offending statement of the advice for the SQL review check.
+	Excerpt string `json:"excerpt,omitempty"`
 }
 
 // TaskCheckRunResultPayload is the result payload of a task check run.
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// WorkspaceWebhook is the API message for workspace webhooks.
+// Unlike ProjectWebhook which only receives the activities in the project, it receives the activities of the whole
+// workspace, e.g. the member, instance, environment, policy, anomaly and SQL editor activities.
+type WorkspaceWebhook struct {
+	ID int `jsonapi:"primary,workspaceWebhook"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Domain specific fields
+	Type         string   `jsonapi:"attr,type"`
+	Name         string   `jsonapi:"attr,name"`
+	URL          string   `jsonapi:"attr,url"`
+	ActivityList []string `jsonapi:"attr,activityList"`
+	// LevelList is the list of the activity levels to receive, it receives all levels if empty.
+	LevelList []string `jsonapi:"attr,levelList"`
+	// Secret is used to sign the custom webhook request body with HMAC-SHA256, it's never returned to the client.
+	Secret string
+}
+
+// WorkspaceWebhookCreate is the API message for creating a workspace webhook.
+type WorkspaceWebhookCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Domain specific fields
+	Type         string   `jsonapi:"attr,type"`
+	Name         string   `jsonapi:"attr,name"`
+	URL          string   `jsonapi:"attr,url"`
+	ActivityList []string `jsonapi:"attr,activityList"`
+	LevelList    []string `jsonapi:"attr,levelList"`
+	Secret       string   `jsonapi:"attr,secret"`
+}
+
+// WorkspaceWebhookFind is the API message for finding workspace webhooks.
+type WorkspaceWebhookFind struct {
+	ID *int
+
+	// Domain specific fields
+	ActivityType *ActivityType
+	// Level finds the webhooks receiving the level, including the ones receiving all levels.
+	Level *ActivityLevel
+}
+
+func (find *WorkspaceWebhookFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// WorkspaceWebhookPatch is the API message for patching a workspace webhook.
+type WorkspaceWebhookPatch struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Domain specific fields
+	Name *string `jsonapi:"attr,name"`
+	URL  *string `jsonapi:"attr,url"`
+	// ActivityList and LevelList are comma separated, because jsonapi doesn't support pointer to slice.
+	ActivityList *string `jsonapi:"attr,activityList"`
+	LevelList    *string `jsonapi:"attr,levelList"`
+	Secret       *string `jsonapi:"attr,secret"`
+}
+
+// WorkspaceWebhookDelete is the API message for deleting a workspace webhook.
+type WorkspaceWebhookDelete struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	DeleterID int
+}
+
+// WorkspaceWebhookDelivery is the API message for an outbound workspace webhook delivery.
+// It shares the status and the retry strategy with ProjectWebhookDelivery.
+type WorkspaceWebhookDelivery struct {
+	ID int `jsonapi:"primary,workspaceWebhookDelivery"`
+
+	// Standard fields
+	CreatedTs int64 `jsonapi:"attr,createdTs"`
+	UpdatedTs int64 `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	WorkspaceWebhookID int `jsonapi:"attr,workspaceWebhookId"`
+
+	// Domain specific fields
+	ActivityType  ActivityType                 `jsonapi:"attr,activityType"`
+	Status        ProjectWebhookDeliveryStatus `jsonapi:"attr,status"`
+	AttemptCount  int                          `jsonapi:"attr,attemptCount"`
+	NextAttemptTs int64                        `jsonapi:"attr,nextAttemptTs"`
+	ResponseCode  int                          `jsonapi:"attr,responseCode"`
+	ResponseBody  string                       `jsonapi:"attr,responseBody"`
+	Error         string                       `jsonapi:"attr,error"`
+	Payload       string                       `jsonapi:"attr,payload"`
+}
+
+// WorkspaceWebhookDeliveryCreate is the API message for creating a WorkspaceWebhookDelivery.
+type WorkspaceWebhookDeliveryCreate struct {
+	// Related fields
+	WorkspaceWebhookID int
+
+	// Domain specific fields
+	ActivityType ActivityType
+	Payload      string
+}
+
+// WorkspaceWebhookDeliveryFind is the API message for finding WorkspaceWebhookDeliveries.
+type WorkspaceWebhookDeliveryFind struct {
+	ID *int
+
+	// Related fields
+	WorkspaceWebhookID *int
+
+	// Domain specific fields
+	Status           *ProjectWebhookDeliveryStatus
+	MaxNextAttemptTs *int64
+	// Limit limits the number of the returned deliveries, which are sorted by ID in descending order.
+	Limit *int
+}
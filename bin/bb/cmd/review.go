@@ -0,0 +1,381 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/xo/dburl"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/catalog"
+	advisorDB "github.com/bytebase/bytebase/plugin/advisor/db"
+	"github.com/bytebase/bytebase/plugin/db"
+
+	// Register mysql advisors.
+	_ "github.com/bytebase/bytebase/plugin/advisor/mysql"
+	// Register postgres advisors.
+	_ "github.com/bytebase/bytebase/plugin/advisor/pg"
+)
+
+const (
+	reviewOutputText  = "text"
+	reviewOutputJSON  = "json"
+	reviewOutputSARIF = "sarif"
+	reviewOutputJUnit = "junit"
+
+	// reviewStdinFilePath is the file path in the review output for the statements read from stdin.
+	reviewStdinFilePath = "stdin"
+)
+
+func newReviewCmd() *cobra.Command {
+	var (
+		dsn       string
+		dbType    string
+		config    string
+		output    string
+		charset   string
+		collation string
+	)
+	reviewCmd := &cobra.Command{
+		Use:   "review [FILE]...",
+		Short: "Review SQL statements with the SQL review rules.",
+		Long: `Review SQL statements in the files, or from stdin if no file is given, with the SQL review rules.
+
+The rule config is in the same YAML format as the SQL review templates, or extends a template with the rule list to override.
+If the dsn is specified, the review connects to the database to check catalog-aware rules.
+The command exits with non-zero code if any error is found, so that it can be used in pre-commit hooks.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if config == "" {
+				return errors.Errorf("the SQL review rule config is required")
+			}
+			switch output {
+			case reviewOutputText, reviewOutputJSON, reviewOutputSARIF, reviewOutputJUnit:
+			default:
+				return errors.Errorf("unsupported output format %q; supported formats: text, json, sarif, junit", output)
+			}
+			configData, err := os.ReadFile(config)
+			if err != nil {
+				return errors.Wrapf(err, "failed to read SQL review rule config %s", config)
+			}
+			ruleList, err := advisor.UnmarshalSQLReviewRuleConfig(configData)
+			if err != nil {
+				return err
+			}
+
+			statementMap := make(map[string]string)
+			if len(args) == 0 {
+				data, err := io.ReadAll(cmd.InOrStdin())
+				if err != nil {
+					return errors.Wrap(err, "failed to read statements from stdin")
+				}
+				statementMap[reviewStdinFilePath] = string(data)
+			}
+			for _, file := range args {
+				data, err := os.ReadFile(file)
+				if err != nil {
+					return errors.Wrapf(err, "failed to read file %s", file)
+				}
+				statementMap[file] = string(data)
+			}
+
+			ctx := context.Background()
+			reviewer := &sqlReviewer{
+				ruleList:  ruleList,
+				charset:   charset,
+				collation: collation,
+			}
+			if dsn != "" {
+				u, err := dburl.Parse(dsn)
+				if err != nil {
+					return errors.Wrap(err, "failed to parse dsn")
+				}
+				driver, err := open(ctx, u)
+				if err != nil {
+					return err
+				}
+				defer driver.Close(ctx)
+				if err := reviewer.connect(ctx, driver, u); err != nil {
+					return err
+				}
+			} else {
+				if dbType == "" {
+					return errors.Errorf("the database type is required if the dsn is not specified")
+				}
+				advisorDBType, err := advisorDB.ConvertToAdvisorDBType(dbType)
+				if err != nil {
+					return err
+				}
+				reviewer.dbType = advisorDBType
+			}
+
+			adviceMap := make(map[string][]advisor.Advice)
+			for file, statement := range statementMap {
+				adviceList, err := reviewer.review(ctx, statement)
+				if err != nil {
+					return errors.Wrapf(err, "failed to review %s", file)
+				}
+				adviceMap[file] = adviceList
+			}
+
+			if err := writeReviewOutput(cmd.OutOrStdout(), output, adviceMap); err != nil {
+				return err
+			}
+			if count := countAdvice(adviceMap, advisor.Error); count > 0 {
+				return errors.Errorf("SQL review found %d error(s)", count)
+			}
+			return nil
+		},
+	}
+
+	reviewCmd.Flags().StringVar(&dsn, "dsn", "", dsnUsage)
+	reviewCmd.Flags().StringVar(&dbType, "type", "", "Database type of the statements, one of mysql, postgres and tidb. Required if the dsn is not specified.")
+	reviewCmd.Flags().StringVar(&config, "config", "", "SQL review rule config file in YAML.")
+	reviewCmd.Flags().StringVarP(&output, "output", "o", reviewOutputText, "Output format, one of text, json, sarif and junit.")
+	reviewCmd.Flags().StringVar(&charset, "charset", "utf8mb4", "Database charset used by the charset rules if the dsn is not specified.")
+	reviewCmd.Flags().StringVar(&collation, "collation", "utf8mb4_general_ci", "Database collation used by the collation rules if the dsn is not specified.")
+	return reviewCmd
+}
+
+// sqlReviewer reviews the statements with the rule list.
+// The database is optional. Without it, the catalog is empty and the integrity is not checked.
+type sqlReviewer struct {
+	ruleList  []*advisor.SQLReviewRule
+	dbType    advisorDB.Type
+	charset   string
+	collation string
+
+	// database is the catalog synced from the connected database.
+	database *catalog.Database
+	driver   db.Driver
+	dbName   string
+}
+
+// reviewCatalog implements the catalog.Catalog interface.
+type reviewCatalog struct {
+	finder *catalog.Finder
+}
+
+// GetFinder implements the catalog.Catalog interface.
+func (c *reviewCatalog) GetFinder() *catalog.Finder {
+	return c.finder
+}
+
+func (r *sqlReviewer) connect(ctx context.Context, driver db.Driver, u *dburl.URL) error {
+	r.dbName = getDatabase(u)
+	if r.dbName == "" {
+		return errors.Errorf("the database name is required in the dsn for SQL review")
+	}
+	switch u.Driver {
+	case "mysql":
+		r.dbType = advisorDB.MySQL
+	case "postgres":
+		r.dbType = advisorDB.Postgres
+	default:
+		return errors.Errorf("database type %q not supported for SQL review", u.Driver)
+	}
+
+	schema, err := driver.SyncDBSchema(ctx, r.dbName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to sync schema for database %s", r.dbName)
+	}
+	r.database = convertSchemaToCatalog(schema, r.dbType)
+	r.charset = schema.CharacterSet
+	r.collation = schema.Collation
+	r.driver = driver
+	return nil
+}
+
+func (r *sqlReviewer) review(ctx context.Context, statement string) ([]advisor.Advice, error) {
+	// The finder walks through the statements, so we need a new finder for each review.
+	c := &reviewCatalog{}
+	if r.database != nil {
+		c.finder = catalog.NewFinder(r.database, &catalog.FinderContext{CheckIntegrity: true})
+	} else {
+		c.finder = catalog.NewEmptyFinder(&catalog.FinderContext{CheckIntegrity: false}, r.dbType)
+	}
+
+	checkContext := advisor.SQLReviewCheckContext{
+		Charset:   r.charset,
+		Collation: r.collation,
+		DbType:    r.dbType,
+		Catalog:   c,
+		Context:   ctx,
+	}
+	if r.driver != nil {
+		connection, err := r.driver.GetDBConnection(ctx, r.dbName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get connection for database %s", r.dbName)
+		}
+		checkContext.Driver = connection
+	}
+	return advisor.SQLReviewCheck(statement, r.ruleList, checkContext)
+}
+
+func convertSchemaToCatalog(schema *db.Schema, dbType advisorDB.Type) *catalog.Database {
+	database := &catalog.Database{
+		Name:         schema.Name,
+		CharacterSet: schema.CharacterSet,
+		Collation:    schema.Collation,
+		DbType:       dbType,
+	}
+	schemaMap := make(map[string]*catalog.Schema)
+	getOrCreateSchema := func(name string) *catalog.Schema {
+		if dbType != advisorDB.Postgres {
+			name = ""
+		}
+		if s, ok := schemaMap[name]; ok {
+			return s
+		}
+		s := &catalog.Schema{Name: name}
+		schemaMap[name] = s
+		database.SchemaList = append(database.SchemaList, s)
+		return s
+	}
+
+	for _, table := range schema.TableList {
+		tableData := &catalog.Table{
+			Name:          table.ShortName,
+			Type:          table.Type,
+			Engine:        table.Engine,
+			Collation:     table.Collation,
+			RowCount:      table.RowCount,
+			DataSize:      table.DataSize,
+			IndexSize:     table.IndexSize,
+			DataFree:      table.DataFree,
+			CreateOptions: table.CreateOptions,
+			Comment:       table.Comment,
+		}
+		if tableData.Name == "" {
+			tableData.Name = table.Name
+		}
+		for _, column := range table.ColumnList {
+			tableData.ColumnList = append(tableData.ColumnList, &catalog.Column{
+				Name:         column.Name,
+				Position:     column.Position,
+				Default:      column.Default,
+				Nullable:     column.Nullable,
+				Type:         column.Type,
+				CharacterSet: column.CharacterSet,
+				Collation:    column.Collation,
+				Comment:      column.Comment,
+			})
+		}
+		tableData.IndexList = convertIndexListToCatalog(table.IndexList)
+		s := getOrCreateSchema(table.Schema)
+		s.TableList = append(s.TableList, tableData)
+	}
+	for _, view := range schema.ViewList {
+		name := view.ShortName
+		if name == "" {
+			name = view.Name
+		}
+		s := getOrCreateSchema(view.Schema)
+		s.ViewList = append(s.ViewList, &catalog.View{
+			Name:       name,
+			Definition: view.Definition,
+			Comment:    view.Comment,
+		})
+	}
+	for _, extension := range schema.ExtensionList {
+		s := getOrCreateSchema(extension.Schema)
+		s.ExtensionList = append(s.ExtensionList, &catalog.Extension{
+			Name:        extension.Name,
+			Version:     extension.Version,
+			Description: extension.Description,
+		})
+	}
+	return database
+}
+
+func convertIndexListToCatalog(list []db.Index) []*catalog.Index {
+	indexMap := make(map[string][]db.Index)
+	var nameList []string
+	for _, index := range list {
+		if _, ok := indexMap[index.Name]; !ok {
+			nameList = append(nameList, index.Name)
+		}
+		indexMap[index.Name] = append(indexMap[index.Name], index)
+	}
+	sort.Strings(nameList)
+
+	var res []*catalog.Index
+	for _, name := range nameList {
+		expressionList := indexMap[name]
+		sort.Slice(expressionList, func(i, j int) bool {
+			return expressionList[i].Position < expressionList[j].Position
+		})
+		index := &catalog.Index{
+			Name:    name,
+			Type:    expressionList[0].Type,
+			Unique:  expressionList[0].Unique,
+			Primary: expressionList[0].Primary,
+			Visible: expressionList[0].Visible,
+			Comment: expressionList[0].Comment,
+		}
+		for _, expression := range expressionList {
+			index.ExpressionList = append(index.ExpressionList, expression.Expression)
+		}
+		res = append(res, index)
+	}
+	return res
+}
+
+func writeReviewOutput(out io.Writer, output string, adviceMap map[string][]advisor.Advice) error {
+	switch output {
+	case reviewOutputSARIF, reviewOutputJUnit:
+		report, err := advisor.GenerateReport(advisor.ReportFormat(output), adviceMap)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, report)
+		return err
+	case reviewOutputJSON:
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(adviceMap)
+	}
+
+	var fileList []string
+	for file := range adviceMap {
+		fileList = append(fileList, file)
+	}
+	sort.Strings(fileList)
+	for _, file := range fileList {
+		for _, advice := range adviceMap[file] {
+			if advice.Status == advisor.Success {
+				continue
+			}
+			line, column := advice.Line, 1
+			if line <= 0 {
+				line = 1
+			}
+			if advice.StartPosition != nil {
+				line, column = advice.StartPosition.Line, advice.StartPosition.Column
+			}
+			if _, err := fmt.Fprintf(out, "%s:%d:%d: [%s] %s (%d): %s\n", file, line, column, advice.Status, advice.Title, advice.Code, advice.Content); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := fmt.Fprintf(out, "%d error(s), %d warning(s)\n", countAdvice(adviceMap, advisor.Error), countAdvice(adviceMap, advisor.Warn))
+	return err
+}
+
+func countAdvice(adviceMap map[string][]advisor.Advice, status advisor.Status) int {
+	count := 0
+	for _, adviceList := range adviceMap {
+		for _, advice := range adviceList {
+			if advice.Status == status {
+				count++
+			}
+		}
+	}
+	return count
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+func runReviewCmd(args ...string) (string, error) {
+	out := &bytes.Buffer{}
+	rootCmd := NewRootCmd()
+	rootCmd.SetOut(out)
+	rootCmd.SetErr(&bytes.Buffer{})
+	rootCmd.SetArgs(append([]string{"review", "--type", "mysql", "--config", filepath.Join("testdata", "review", "config.yaml")}, args...))
+	err := rootCmd.Execute()
+	return out.String(), err
+}
+
+func TestReviewCmd(t *testing.T) {
+	errorFile := filepath.Join("testdata", "review", "error.sql")
+	warningFile := filepath.Join("testdata", "review", "warning.sql")
+
+	// The bb exits with non-zero code if the command returns an error.
+	out, err := runReviewCmd(errorFile, warningFile)
+	require.EqualError(t, err, "SQL review found 1 error(s)")
+	require.Equal(t, errorFile+":2:1: [ERROR] statement.select.no-select-all (203): \"SELECT * FROM book;\" uses SELECT all\n"+
+		errorFile+":1:14: [WARN] naming.table (301): `Book` mismatches table naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"\n"+
+		warningFile+":1:14: [WARN] naming.table (301): `Author` mismatches table naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"\n"+
+		"1 error(s), 2 warning(s)\n", out)
+
+	// Warnings don't fail the review.
+	out, err = runReviewCmd(warningFile)
+	require.NoError(t, err)
+	require.Equal(t, warningFile+":1:14: [WARN] naming.table (301): `Author` mismatches table naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"\n"+
+		"0 error(s), 1 warning(s)\n", out)
+
+	out, err = runReviewCmd("--output", "json", errorFile)
+	require.Error(t, err)
+	adviceMap := make(map[string][]advisor.Advice)
+	require.NoError(t, json.Unmarshal([]byte(out), &adviceMap))
+	require.Len(t, adviceMap[errorFile], 2)
+	require.Equal(t, advisor.Error, adviceMap[errorFile][0].Status)
+	require.Equal(t, advisor.StatementSelectAll, adviceMap[errorFile][0].Code)
+	require.Equal(t, advisor.Warn, adviceMap[errorFile][1].Status)
+	require.Equal(t, advisor.NamingTableConventionMismatch, adviceMap[errorFile][1].Code)
+
+	_, err = runReviewCmd("--output", "xml", errorFile)
+	require.EqualError(t, err, `unsupported output format "xml"; supported formats: text, json, sarif, junit`)
+}
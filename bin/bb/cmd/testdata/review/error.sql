@@ -0,0 +1,2 @@
+CREATE TABLE Book(id INT PRIMARY KEY);
+SELECT * FROM book;
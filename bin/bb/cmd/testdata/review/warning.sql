@@ -0,0 +1,2 @@
+CREATE TABLE Author(id INT PRIMARY KEY);
+SELECT id FROM author;
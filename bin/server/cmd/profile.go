@@ -6,6 +6,7 @@ import (
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/plugin/app/feishu"
+	"github.com/bytebase/bytebase/plugin/app/slack"
 	"github.com/bytebase/bytebase/server/component/config"
 )
 
@@ -41,5 +42,6 @@ func getBaseProfile() config.Profile {
 		BackupBucket:         flags.backupBucket,
 		BackupCredentialFile: flags.backupCredential,
 		FeishuAPIURL:         feishu.APIPath,
+		SlackAPIURL:          slack.APIPath,
 	}
 }
@@ -12,6 +12,10 @@ import {
   unknown,
   PrincipalId,
   AuthProvider,
+  OIDCProvider,
+  LDAPProvider,
+  MFAEnrollment,
+  PrincipalMFA,
 } from "@/types";
 import { getIntCookie } from "@/utils";
 import { usePrincipalStore } from "./principal";
@@ -49,6 +53,66 @@ export const useAuthStore = defineStore("auth", {
       this.setAuthProviderList(convertedProviderList);
       return convertedProviderList;
     },
+    async fetchOIDCProvider() {
+      try {
+        const provider = (await axios.get("/api/auth/oidc")).data.data;
+        this.oidcProvider = { ...provider.attributes } as OIDCProvider;
+      } catch {
+        // The OIDC single sign-on is not enabled.
+        this.oidcProvider = undefined;
+      }
+      return this.oidcProvider;
+    },
+    async fetchLDAPProvider() {
+      try {
+        const provider = (await axios.get("/api/auth/ldap")).data.data;
+        this.ldapProvider = { ...provider.attributes } as LDAPProvider;
+      } catch {
+        // The LDAP authentication is not enabled.
+        this.ldapProvider = undefined;
+      }
+      return this.ldapProvider;
+    },
+    // The server keeps the nonce and the PKCE code verifier in the cookie for the following login.
+    async fetchOIDCAuthorizeUrl(state: string): Promise<string> {
+      const authorization = (
+        await axios.post("/api/auth/oidc/authorize", {
+          data: { type: "oidcAuthorizationCreate", attributes: { state } },
+        })
+      ).data.data;
+      return authorization.attributes.authorizeUrl;
+    },
+    // The enrollment is authenticated by the password, so that it can happen before the first login.
+    async enrollMFA(email: string, password: string): Promise<MFAEnrollment> {
+      const enrollment = (
+        await axios.post("/api/auth/mfa/enroll", {
+          data: {
+            type: "mfaEnrollmentCreate",
+            attributes: { email, password },
+          },
+        })
+      ).data.data;
+      return { ...enrollment.attributes } as MFAEnrollment;
+    },
+    // Returns the recovery codes, which are only available once.
+    async verifyMFA(
+      email: string,
+      password: string,
+      otpCode: string
+    ): Promise<PrincipalMFA> {
+      const principalMFA = (
+        await axios.post("/api/auth/mfa/verify", {
+          data: {
+            type: "mfaVerify",
+            attributes: { email, password, otpCode },
+          },
+        })
+      ).data.data;
+      return {
+        principalId: parseInt(principalMFA.id, 10),
+        ...principalMFA.attributes,
+      } as PrincipalMFA;
+    },
     async login(loginInfo: LoginInfo) {
       const loggedInUser = (
         await axios.post(`/api/auth/login/${loginInfo.authProvider}`, {
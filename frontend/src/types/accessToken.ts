@@ -0,0 +1,37 @@
+import { PrincipalId, ProjectId } from "./id";
+
+export type AccessTokenScope = "read-only" | "sql-review" | "issue-create";
+
+// AccessToken is a long-lived personal access token of an end user, or a key of a service account.
+export type AccessToken = {
+  id: number;
+
+  // Standard fields
+  creatorId: PrincipalId;
+  createdTs: number;
+  updatedTs: number;
+
+  // Related fields
+  principalId: PrincipalId;
+  // Restricts the token to the project, 0 means no restriction.
+  projectId: ProjectId;
+
+  // Domain specific fields
+  name: string;
+  // The token has the full access of the principal if empty.
+  scopeList: AccessTokenScope[];
+  // 0 means the token never expires.
+  expiresTs: number;
+  // 0 means the token has never been used.
+  lastUsedTs: number;
+  // Only returned for the first time after the creation.
+  token: string;
+};
+
+export type AccessTokenCreate = {
+  // Domain specific fields
+  name: string;
+  scopeList: AccessTokenScope[];
+  projectId: ProjectId;
+  expiresTs: number;
+};
@@ -1,4 +1,8 @@
 import { ExternalApprovalEvent } from "./externalApproval";
+import { AnomalyType } from "./anomaly";
+import { RowStatus } from "./common";
+import { EngineType } from "./instance";
+import { PolicyResourceType, PolicyType } from "./policy";
 import { FieldId } from "../plugins";
 import {
   ActivityId,
@@ -43,12 +47,26 @@ export type DatabaseActivityType = "bb.database.recovery.pitr.done";
 
 export type SQLEditorActivityType = "bb.sql-editor.query";
 
+export type InstanceActivityType = "bb.instance.create" | "bb.instance.update";
+
+export type EnvironmentActivityType =
+  | "bb.environment.create"
+  | "bb.environment.update";
+
+export type PolicyActivityType = "bb.policy.update";
+
+export type AnomalyActivityType = "bb.anomaly.create";
+
 export type ActivityType =
   | IssueActivityType
   | MemberActivityType
   | ProjectActivityType
   | DatabaseActivityType
-  | SQLEditorActivityType;
+  | SQLEditorActivityType
+  | InstanceActivityType
+  | EnvironmentActivityType
+  | PolicyActivityType
+  | AnomalyActivityType;
 
 export function activityName(type: ActivityType): string {
   switch (type) {
@@ -88,6 +106,18 @@ export function activityName(type: ActivityType): string {
       return t("activity.type.project-member-role-update");
     case "bb.database.recovery.pitr.done":
       return t("activity.type.database-recovery-pitr-done");
+    case "bb.instance.create":
+      return t("activity.type.instance-create");
+    case "bb.instance.update":
+      return t("activity.type.instance-update");
+    case "bb.environment.create":
+      return t("activity.type.environment-create");
+    case "bb.environment.update":
+      return t("activity.type.environment-update");
+    case "bb.policy.update":
+      return t("activity.type.policy-update");
+    case "bb.anomaly.create":
+      return t("activity.type.anomaly-create");
   }
   console.assert(false, `undefined text for activity type "${type}"`);
   return "";
@@ -195,6 +225,40 @@ export type ActivitySQLEditorQueryPayload = {
   adviceList: Advice[];
 };
 
+export type ActivityInstanceCreatePayload = {
+  instanceName: string;
+  engine: EngineType;
+};
+
+export type ActivityInstanceUpdatePayload = {
+  instanceName: string;
+  rowStatus: RowStatus;
+};
+
+export type ActivityEnvironmentCreatePayload = {
+  environmentName: string;
+};
+
+export type ActivityEnvironmentUpdatePayload = {
+  environmentName: string;
+  rowStatus: RowStatus;
+};
+
+export type ActivityPolicyUpdatePayload = {
+  resourceType: PolicyResourceType;
+  resourceId: number;
+  policyType: PolicyType;
+  payload: string;
+};
+
+export type ActivityAnomalyCreatePayload = {
+  anomalyType: AnomalyType;
+  instanceId: InstanceId;
+  instanceName: string;
+  databaseId?: DatabaseId;
+  databaseName?: string;
+};
+
 export type ActionPayloadType =
   | ActivityIssueCreatePayload
   | ActivityIssueCommentCreatePayload
@@ -209,7 +273,13 @@ export type ActionPayloadType =
   | ActivityMemberActivateDeactivatePayload
   | ActivityProjectRepositoryPushPayload
   | ActivityProjectDatabaseTransferPayload
-  | ActivitySQLEditorQueryPayload;
+  | ActivitySQLEditorQueryPayload
+  | ActivityInstanceCreatePayload
+  | ActivityInstanceUpdatePayload
+  | ActivityEnvironmentCreatePayload
+  | ActivityEnvironmentUpdatePayload
+  | ActivityPolicyUpdatePayload
+  | ActivityAnomalyCreatePayload;
 
 export type Activity = {
   id: ActivityId;
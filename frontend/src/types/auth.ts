@@ -5,9 +5,13 @@ import { VCSId } from "./id";
 // For now, a single user's auth provider should either belong to GITLAB_SELF_HOST, GITHUB_COM or BYTEBASE
 export type AuthProviderType = "GITLAB_SELF_HOST" | "GITHUB_COM" | "BYTEBASE";
 
+// OIDC is the OpenID Connect single sign-on and LDAP is the LDAP directory,
+// both are configured in the workspace setting instead of a VCS.
+export type LoginAuthProviderType = AuthProviderType | "OIDC" | "LDAP";
+
 export type LoginInfo = {
-  authProvider: AuthProviderType;
-  payload: VCSLoginInfo | BytebaseLoginInfo;
+  authProvider: LoginAuthProviderType;
+  payload: VCSLoginInfo | BytebaseLoginInfo | OIDCLoginInfo | LDAPLoginInfo;
 };
 
 export type SignupInfo = {
@@ -26,6 +30,22 @@ export type ActivateInfo = {
 export type BytebaseLoginInfo = {
   email: string;
   password: string;
+  // Either is required if the two-factor authentication is enabled.
+  otpCode?: string;
+  recoveryCode?: string;
+};
+
+// The login response messages prompting for the two-factor authentication.
+export const MFA_CODE_REQUIRED_MESSAGE =
+  "Two-factor authentication code is required";
+export const MFA_ENROLLMENT_REQUIRED_MESSAGE =
+  "Two-factor authentication is required for your role, please enroll first";
+
+export type MFAEnrollment = {
+  // Entered into the authenticator app manually.
+  otpSecret: string;
+  // The otpauth URI rendered as a QR code.
+  otpAuthUrl: string;
 };
 
 export type AuthProvider = {
@@ -51,3 +71,20 @@ export type VCSLoginInfo = {
   name: string;
   code: string;
 };
+
+export type OIDCLoginInfo = {
+  code: string;
+};
+
+export type OIDCProvider = {
+  name: string;
+};
+
+export type LDAPLoginInfo = {
+  username: string;
+  password: string;
+};
+
+export type LDAPProvider = {
+  name: string;
+};
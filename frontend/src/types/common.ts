@@ -452,8 +452,10 @@ const makeUnknown = (type: ResourceType) => {
     webUrl: "",
     baseDirectory: "",
     branchFilter: "",
+    branchEnvironmentSetting: { ruleList: [] },
     filePathTemplate: "",
     schemaPathTemplate: "",
+    schemaWriteBackType: "COMMIT",
     sheetPathTemplate: "",
     externalId: UNKNOWN_ID.toString(),
   };
@@ -904,8 +906,10 @@ const makeEmpty = (type: ResourceType) => {
     webUrl: "",
     baseDirectory: "",
     branchFilter: "",
+    branchEnvironmentSetting: { ruleList: [] },
     filePathTemplate: "",
     schemaPathTemplate: "",
+    schemaWriteBackType: "COMMIT",
     sheetPathTemplate: "",
     externalId: EMPTY_ID.toString(),
   };
@@ -0,0 +1,89 @@
+import { PrincipalId } from "./id";
+
+// Check api/permission.go to understand what each permission means.
+export type Permission =
+  | "activity.get"
+  | "activity.create"
+  | "anomaly.get"
+  | "database.get"
+  | "database.update"
+  | "database.backup"
+  | "database.query"
+  | "database.admin-query"
+  | "debug.get"
+  | "debug.manage"
+  | "environment.get"
+  | "environment.create"
+  | "environment.update"
+  | "environment.delete"
+  | "instance.get"
+  | "instance.create"
+  | "instance.create-embedded"
+  | "instance.update"
+  | "instance.delete"
+  | "issue.get"
+  | "issue.create"
+  | "issue.update"
+  | "issue.approve"
+  | "label.get"
+  | "label.update"
+  | "member.get"
+  | "member.manage"
+  | "policy.get"
+  | "policy.update"
+  | "principal.get"
+  | "principal.create"
+  | "principal.update"
+  | "project.get"
+  | "project.create"
+  | "project.update"
+  | "project.admin"
+  | "role.get"
+  | "role.manage"
+  | "setting.get"
+  | "setting.update"
+  | "sheet.get"
+  | "sheet.create"
+  | "sheet.update"
+  | "sheet.delete"
+  | "subscription.trial"
+  | "subscription.update"
+  | "vcs.get"
+  | "vcs.get-repository"
+  | "vcs.manage"
+  | "workspace-webhook.manage";
+
+// CustomRole is a workspace-defined role composed of permissions, which can be
+// assigned to the workspace and project members along with the built-in roles.
+export type CustomRole = {
+  id: number;
+
+  // Standard fields
+  creatorId: PrincipalId;
+  createdTs: number;
+  updaterId: PrincipalId;
+  updatedTs: number;
+
+  // Domain specific fields
+  // The unique key of the role stored in the memberships, e.g. "RELEASE_MANAGER".
+  role: string;
+  name: string;
+  description: string;
+  // The workspace permissions, and the project permissions such as "bb.permission.project.manage-member".
+  permissionList: string[];
+};
+
+export type CustomRoleCreate = {
+  // Domain specific fields
+  role: string;
+  name: string;
+  description: string;
+  permissionList: string[];
+};
+
+export type CustomRolePatch = {
+  // Domain specific fields
+  name?: string;
+  description?: string;
+  permissionList?: string[];
+};
@@ -1,4 +1,6 @@
-export type ExternalApprovalType = "bb.plugin.app.feishu";
+export type ExternalApprovalType =
+  | "bb.plugin.app.feishu"
+  | "bb.plugin.app.slack";
 
 export type ExternalApprovalEvent = {
   type: ExternalApprovalType;
@@ -1,3 +1,4 @@
+export * from "./accessToken";
 export * from "./activity";
 export * from "./actuator";
 export * from "./anomaly";
@@ -6,6 +7,7 @@ export * from "./backup";
 export * from "./bookmark";
 export * from "./column";
 export * from "./common";
+export * from "./customRole";
 export * from "./const";
 export * from "./database";
 export * from "./dataSource";
@@ -22,11 +24,13 @@ export * from "./issueSubscriber";
 export * from "./jsonapi";
 export * from "./member";
 export * from "./notification";
+export * from "./notificationPreference";
 export * from "./oauth";
 export * from "./pipeline";
 export * from "./plan";
 export * from "./policy";
 export * from "./principal";
+export * from "./principalMfa";
 export * from "./project";
 export * from "./projectWebhook";
 export * from "./repository";
@@ -37,6 +41,7 @@ export * from "./table";
 export * from "./tableIndex";
 export * from "./vcs";
 export * from "./view";
+export * from "./workspaceWebhook";
 export * from "./db_extension";
 export * from "./label";
 export * from "./deployment";
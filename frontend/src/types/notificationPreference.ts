@@ -0,0 +1,28 @@
+import { PrincipalId } from "./id";
+
+export type NotificationReason =
+  | "APPROVAL_NEEDED"
+  | "ASSIGNED"
+  | "MENTIONED"
+  | "SUBSCRIBED";
+
+export type NotificationPreference = {
+  // Use the principal id as the notification preference id.
+  id: PrincipalId;
+
+  // Domain specific fields
+  emailApprovalNeeded: boolean;
+  emailAssigned: boolean;
+  emailMentioned: boolean;
+  emailSubscribed: boolean;
+  // Send the email notifications as a periodic digest instead of one email per activity.
+  emailDigest: boolean;
+};
+
+export type NotificationPreferencePatch = {
+  emailApprovalNeeded?: boolean;
+  emailAssigned?: boolean;
+  emailMentioned?: boolean;
+  emailSubscribed?: boolean;
+  emailDigest?: boolean;
+};
@@ -44,7 +44,7 @@ export function openWindowForOAuth(
   const stateQueryParameter = `${type}-${randomString(20)}`;
   sessionStorage.setItem(OAuthStateSessionKey, stateQueryParameter);
 
-  if (vcsType == "GITHUB_COM") {
+  if (vcsType == "GITHUB_COM" || vcsType == "GITHUB_ENTERPRISE") {
     // GitHub OAuth App scopes: https://docs.github.com/en/developers/apps/building-oauth-apps/scopes-for-oauth-apps
     // We need the workflow scope to update GitHub action files.
     return window.open(
@@ -55,6 +55,19 @@ export function openWindowForOAuth(
       "location=yes,left=200,top=200,height=640,width=480,scrollbars=yes,status=yes"
     );
   }
+  if (vcsType == "AZURE_DEVOPS") {
+    // Azure DevOps OAuth App scopes: https://learn.microsoft.com/en-us/azure/devops/integrate/get-started/authentication/oauth#scopes
+    // The scopes should be the same as the ones registered in the app.
+    return window.open(
+      `${endpoint}?client_id=${applicationId}&redirect_uri=${encodeURIComponent(
+        redirectUrl()
+      )}&state=${stateQueryParameter}&response_type=Assertion&scope=${encodeURIComponent(
+        "vso.code_manage vso.hooks_write vso.profile vso.project vso.variablegroups_manage"
+      )}`,
+      "oauth",
+      "location=yes,left=200,top=200,height=640,width=480,scrollbars=yes,status=yes"
+    );
+  }
   // GITLAB_SELF_HOST
   // GitLab OAuth App scopes: https://docs.gitlab.com/ee/integration/oauth_provider.html#authorized-applications
   return window.open(
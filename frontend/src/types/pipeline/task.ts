@@ -61,6 +61,9 @@ export type TaskDatabaseSchemaBaselinePayload = {
 export type TaskDatabaseSchemaUpdatePayload = {
   statement: string;
   pushEvent?: VCSPushEvent;
+  // Set when the task applies the undo script of another schema update task.
+  rollbackFromIssueId?: IssueId;
+  rollbackFromTaskId?: TaskId;
 };
 
 export type TaskDatabaseSchemaUpdateSDLPayload = {
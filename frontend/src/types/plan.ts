@@ -6,6 +6,7 @@ export type FeatureType =
   // Admin & Security
   | "bb.feature.3rd-party-auth"
   | "bb.feature.rbac"
+  | "bb.feature.custom-role"
   // Branding
   | "bb.feature.branding"
   // Change Workflow
@@ -80,6 +81,7 @@ export const FEATURE_MATRIX: Map<FeatureType, boolean[]> = new Map([
   // Admin & Security
   ["bb.feature.3rd-party-auth", [false, true, true]],
   ["bb.feature.rbac", [false, true, true]],
+  ["bb.feature.custom-role", [false, false, true]],
   // Branding
   ["bb.feature.branding", [false, false, true]],
   // Change Workflow
@@ -0,0 +1,17 @@
+import { PrincipalId } from "./id";
+
+// PrincipalMFA is the two-factor authentication of a principal.
+export type PrincipalMFA = {
+  principalId: PrincipalId;
+
+  // Standard fields
+  createdTs: number;
+  updatedTs: number;
+
+  // Domain specific fields
+  enabled: boolean;
+  // The number of the unused recovery codes.
+  recoveryCodeCount: number;
+  // Only returned for the first time after the recovery codes are generated.
+  recoveryCodeList: string[];
+};
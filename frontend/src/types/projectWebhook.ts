@@ -110,6 +110,8 @@ export type ProjectWebhookCreate = {
   name: string;
   url: string;
   activityList: ActivityType[];
+  // Used to sign the custom webhook request body with HMAC-SHA256.
+  secret?: string;
 };
 
 export type ProjectWebhookPatch = {
@@ -118,8 +120,38 @@ export type ProjectWebhookPatch = {
   url?: string;
   // Comma separated list. Server doesn't support deserialize into pointer to string array (*[]string in Golang)
   activityList?: string;
+  secret?: string;
 };
 
 export type ProjectWebhookTestResult = {
   error?: string;
 };
+
+export type ProjectWebhookDeliveryStatus =
+  | "PENDING"
+  | "SENDING"
+  | "SUCCEEDED"
+  | "FAILED";
+
+export type ProjectWebhookDelivery = {
+  id: number;
+
+  // Standard fields
+  createdTs: number;
+  updatedTs: number;
+
+  // Related fields
+  projectWebhookId: number;
+
+  // Domain specific fields
+  activityType: ActivityType;
+  status: ProjectWebhookDeliveryStatus;
+  attemptCount: number;
+  // The time of the next attempt if the status is PENDING.
+  nextAttemptTs: number;
+  // 0 if no response is received in the last attempt.
+  responseCode: number;
+  responseBody: string;
+  error: string;
+  payload: string;
+};
@@ -1,9 +1,26 @@
 import isEmpty from "lodash-es/isEmpty";
-import { RepositoryId, VCSId } from "./id";
+import { EnvironmentId, RepositoryId, VCSId } from "./id";
 import { Principal } from "./principal";
 import { Project } from "./project";
 import { VCS } from "./vcs";
 
+// BranchEnvironmentRule maps the branches matching the filter to an environment.
+export type BranchEnvironmentRule = {
+  // e.g. release/*
+  branchFilter: string;
+  environmentId: EnvironmentId;
+};
+
+export type BranchEnvironmentSetting = {
+  // The first matched rule wins.
+  ruleList: BranchEnvironmentRule[];
+};
+
+// COMMIT commits the latest schema to the branch directly.
+// PULL_REQUEST commits the latest schema to a bot branch and opens a pull request,
+// which is required if the branch is protected.
+export type SchemaWriteBackType = "COMMIT" | "PULL_REQUEST";
+
 export type Repository = {
   id: RepositoryId;
 
@@ -26,8 +43,10 @@ export type Repository = {
   webUrl: string;
   baseDirectory: string;
   branchFilter: string;
+  branchEnvironmentSetting: BranchEnvironmentSetting;
   filePathTemplate: string;
   schemaPathTemplate: string;
+  schemaWriteBackType: SchemaWriteBackType;
   sheetPathTemplate: string;
   enableSQLReviewCI: boolean;
   sqlReviewCIPullRequestURL: string;
@@ -39,6 +58,38 @@ export type SQLReviewCISetup = {
   pullRequestURL: string;
 };
 
+export type RepositoryImportAction = "REPORT" | "BASELINE" | "ISSUE";
+
+export type RepositoryImport = {
+  // Empty to use the branch filter of the repository.
+  ref: string;
+  action: RepositoryImportAction;
+};
+
+export type RepositoryImportFileStatus =
+  | "APPLIED"
+  | "PENDING"
+  | "OUT_OF_ORDER"
+  | "FAILED"
+  | "IGNORED";
+
+export type RepositoryImportFile = {
+  filePath: string;
+  version: string;
+  status: RepositoryImportFileStatus;
+  databaseId: number;
+  databaseName: string;
+  environmentName: string;
+  message: string;
+};
+
+export type RepositoryImportResult = {
+  ref: string;
+  commitId: string;
+  fileList: RepositoryImportFile[];
+  issueIdList: number[];
+};
+
 export type RepositoryCreate = {
   // Related fields
   vcsId: VCSId;
@@ -51,6 +102,7 @@ export type RepositoryCreate = {
   baseDirectory: string;
   filePathTemplate: string;
   schemaPathTemplate: string;
+  schemaWriteBackType?: SchemaWriteBackType;
   sheetPathTemplate: string;
   externalId: string;
   accessToken: string;
@@ -61,8 +113,10 @@ export type RepositoryCreate = {
 export type RepositoryPatch = {
   baseDirectory?: string;
   branchFilter?: string;
+  branchEnvironmentSetting?: BranchEnvironmentSetting;
   filePathTemplate?: string;
   schemaPathTemplate?: string;
+  schemaWriteBackType?: SchemaWriteBackType;
   sheetPathTemplate?: string;
   enableSQLReviewCI?: boolean;
 };
@@ -108,7 +162,10 @@ export function baseDirectoryWebUrl(
     if (!isEmpty(repository.baseDirectory)) {
       url += `/${repository.baseDirectory}`;
     }
-  } else if (repository.vcs.type == "GITHUB_COM") {
+  } else if (
+    repository.vcs.type == "GITHUB_COM" ||
+    repository.vcs.type == "GITHUB_ENTERPRISE"
+  ) {
     url = `${repository.webUrl}/tree/${repository.branchFilter}`;
     if (!isEmpty(repository.baseDirectory)) {
       url += `/${repository.baseDirectory}`;
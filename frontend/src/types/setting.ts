@@ -1,7 +1,16 @@
-import { SettingId } from "./id";
+import { ProjectId, SettingId } from "./id";
 import { Principal } from "./principal";
+import { RoleType } from "./member";
+import { ProjectRoleType } from "./project";
 
-export type SettingName = "bb.branding.logo" | "bb.app.im";
+export type SettingName =
+  | "bb.branding.logo"
+  | "bb.app.im"
+  | "bb.workspace.mail-delivery"
+  | "bb.app.slack"
+  | "bb.auth.oidc"
+  | "bb.auth.ldap"
+  | "bb.auth.mfa";
 
 export type Setting = {
   id: SettingId;
@@ -28,3 +37,85 @@ export interface SettingAppIMValue {
     enabled: boolean;
   };
 }
+
+export type SMTPEncryption = "NONE" | "STARTTLS" | "SSL/TLS";
+
+export interface SettingMailDeliveryValue {
+  enabled: boolean;
+  smtpHost: string;
+  smtpPort: number;
+  smtpUsername: string;
+  // smtpPassword is always empty in the response, and is kept unchanged if empty in the patch.
+  smtpPassword: string;
+  smtpFrom: string;
+  smtpEncryption: SMTPEncryption;
+  digestIntervalSeconds: number;
+}
+
+// SettingAppSlackValue is the Slack app handling the interactive approvals.
+// The signingSecret and botToken are always empty in the response, and are kept unchanged if empty in the patch.
+export interface SettingAppSlackValue {
+  signingSecret: string;
+  botToken: string;
+}
+
+export type SSOGroupRoleMapping = {
+  group: string;
+  role: RoleType;
+};
+
+// SettingAuthOIDCValue is the OpenID Connect single sign-on provider.
+// The clientSecret is always empty in the response, and is kept unchanged if empty in the patch.
+export interface SettingAuthOIDCValue {
+  enabled: boolean;
+  name: string;
+  issuerUrl: string;
+  clientId: string;
+  clientSecret: string;
+  scopes: string[];
+  emailClaim: string;
+  nameClaim: string;
+  groupsClaim: string;
+  autoProvision: boolean;
+  defaultRole: RoleType | "";
+  groupRoleMapping: SSOGroupRoleMapping[];
+}
+
+export type LDAPGroupProjectMapping = {
+  group: string;
+  projectId: ProjectId;
+  role: ProjectRoleType;
+};
+
+// SettingAuthLDAPValue is the LDAP directory for the authentication and the member sync.
+// The bindPassword is always empty in the response, and is kept unchanged if empty in the patch.
+export interface SettingAuthLDAPValue {
+  enabled: boolean;
+  name: string;
+  host: string;
+  port: number;
+  securityProtocol: "NONE" | "STARTTLS" | "LDAPS";
+  insecureSkipVerify: boolean;
+  bindDn: string;
+  bindPassword: string;
+  baseDn: string;
+  userFilter: string;
+  emailAttribute: string;
+  nameAttribute: string;
+  groupAttribute: string;
+  autoProvision: boolean;
+  defaultRole: RoleType | "";
+  groupRoleMapping: SSOGroupRoleMapping[];
+  groupProjectMapping: LDAPGroupProjectMapping[];
+  syncIntervalSeconds: number;
+}
+
+// SettingAuthMFAValue is the two-factor authentication policy of the workspace.
+export interface SettingAuthMFAValue {
+  // The roles required to enable the two-factor authentication before logging in with the password.
+  requiredRoleList: RoleType[];
+}
+
+export type MailDeliveryTestResult = {
+  error: string;
+};
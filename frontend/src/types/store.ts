@@ -1,5 +1,7 @@
 import {
   AuthProvider,
+  OIDCProvider,
+  LDAPProvider,
   DeploymentConfig,
   EnvironmentId,
   MigrationHistoryId,
@@ -56,6 +58,8 @@ export interface ActuatorState {
 
 export interface AuthState {
   authProviderList: AuthProvider[];
+  oidcProvider?: OIDCProvider;
+  ldapProvider?: LDAPProvider;
   currentUser: Principal;
 }
 
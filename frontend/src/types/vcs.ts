@@ -1,7 +1,13 @@
 import { VCSId } from "./id";
 import { Principal } from "./principal";
 
-export type VCSType = "GITLAB_SELF_HOST" | "GITHUB_COM";
+export type VCSType =
+  | "GITLAB_SELF_HOST"
+  | "GITHUB_COM"
+  | "GITHUB_ENTERPRISE"
+  | "BITBUCKET_CLOUD"
+  | "BITBUCKET_DATA_CENTER"
+  | "AZURE_DEVOPS";
 
 export interface VCSConfig {
   type: VCSType;
@@ -67,6 +73,9 @@ export type VCSPushEvent = {
   repositoryUrl: string;
   repositoryFullPath: string;
   authorName: string;
+  // Set if the event is derived from a pull request.
+  pullRequestId?: string;
+  pullRequestUrl?: string;
   fileCommit: VCSFileCommit;
 };
 
@@ -0,0 +1,65 @@
+import { ActivityLevel, ActivityType } from "./activity";
+import { Principal } from "./principal";
+import { ProjectWebhookDeliveryStatus } from "./projectWebhook";
+
+export type WorkspaceWebhook = {
+  id: number;
+
+  // Standard fields
+  creator: Principal;
+  createdTs: number;
+  updater: Principal;
+  updatedTs: number;
+
+  // Domain specific fields
+  type: string;
+  name: string;
+  url: string;
+  activityList: ActivityType[];
+  // Receives all levels if empty.
+  levelList: ActivityLevel[];
+};
+
+export type WorkspaceWebhookCreate = {
+  // Domain specific fields
+  type: string;
+  name: string;
+  url: string;
+  activityList: ActivityType[];
+  levelList: ActivityLevel[];
+  // Used to sign the custom webhook request body with HMAC-SHA256.
+  secret?: string;
+};
+
+export type WorkspaceWebhookPatch = {
+  // Domain specific fields
+  name?: string;
+  url?: string;
+  // Comma separated list. Server doesn't support deserialize into pointer to string array (*[]string in Golang)
+  activityList?: string;
+  levelList?: string;
+  secret?: string;
+};
+
+export type WorkspaceWebhookDelivery = {
+  id: number;
+
+  // Standard fields
+  createdTs: number;
+  updatedTs: number;
+
+  // Related fields
+  workspaceWebhookId: number;
+
+  // Domain specific fields
+  activityType: ActivityType;
+  status: ProjectWebhookDeliveryStatus;
+  attemptCount: number;
+  // The time of the next attempt if the status is PENDING.
+  nextAttemptTs: number;
+  // 0 if no response is received in the last attempt.
+  responseCode: number;
+  responseBody: string;
+  error: string;
+  payload: string;
+};
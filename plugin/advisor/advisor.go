@@ -273,6 +273,15 @@ type Advice struct {
 	Title   string `json:"title"`
 	Content string `json:"content"`
 	Line    int    `json:"line"`
+	// StartPosition and EndPosition are the range of the offending token or statement, the end is exclusive.
+	// They are filled by the advisors from the statement nodes and can be nil if we cannot locate the advice.
+	StartPosition *Position `json:"startPosition,omitempty"`
+	EndPosition   *Position `json:"endPosition,omitempty"`
+	// Excerpt is the offending statement.
+	Excerpt string `json:"excerpt,omitempty"`
+	// Suggestion is the rewritten statement fixing the advice.
+	// It's only provided by the advisors for the mechanically fixable rules, and can be empty if we cannot fix it.
+	Suggestion string `json:"suggestion,omitempty"`
 }
 
 // MarshalLogObject constructs a field that carries Advice.
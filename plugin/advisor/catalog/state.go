@@ -5,6 +5,7 @@ package catalog
 //   2. the underlying implementation of Finder
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/bytebase/bytebase/plugin/advisor/db"
@@ -314,6 +315,29 @@ func (table *TableState) CountIndex() int {
 	return len(table.indexSet)
 }
 
+// ColumnNameList returns the column names in the position order.
+// It returns nil if we don't know the exact positions of the columns, e.g. the incomplete table during walk-through.
+func (table *TableState) ColumnNameList() []string {
+	var columnList []*ColumnState
+	positionSet := make(map[int]bool)
+	for _, column := range table.columnSet {
+		if column.position == nil || positionSet[*column.position] {
+			return nil
+		}
+		positionSet[*column.position] = true
+		columnList = append(columnList, column)
+	}
+	sort.Slice(columnList, func(i, j int) bool {
+		return *columnList[i].position < *columnList[j].position
+	})
+
+	var result []string
+	for _, column := range columnList {
+		result = append(result, column.name)
+	}
+	return result
+}
+
 func (table *TableState) copy() *TableState {
 	return &TableState{
 		name:      table.name,
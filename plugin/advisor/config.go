@@ -14,6 +14,9 @@ var sqlReviewDevTemplateStr string
 //go:embed config/sql-review.prod.yaml
 var sqlReviewProdTemplateStr string
 
+//go:embed config/sql-review.postgres.prod.yaml
+var sqlReviewPostgreSQLProdTemplateStr string
+
 // SQLReviewTemplateID is the template id for SQL review rules.
 type SQLReviewTemplateID string
 
@@ -22,6 +25,8 @@ const (
 	TemplateForMySQLProd SQLReviewTemplateID = "bb.sql-review.prod"
 	// TemplateForMySQLDev is the template id for mysql dev template.
 	TemplateForMySQLDev SQLReviewTemplateID = "bb.sql-review.dev"
+	// TemplateForPostgreSQLProd is the template id for postgresql prod template.
+	TemplateForPostgreSQLProd SQLReviewTemplateID = "bb.sql-review.postgres.prod"
 )
 
 // SQLReviewTemplateData is the API message for SQL review rule template.
@@ -74,9 +79,60 @@ func MergeSQLReviewRules(override *SQLReviewConfigOverride) ([]*SQLReviewRule, e
 	return res, nil
 }
 
+// sqlReviewConfig is the SQL review rule configuration file.
+// It's either a full rule list in the same format as the SQL review templates,
+// or a template ID with the rule list to override in the same format as SQLReviewConfigOverride.
+type sqlReviewConfig struct {
+	Template SQLReviewTemplateID  `yaml:"template"`
+	RuleList []*SQLReviewRuleData `yaml:"ruleList"`
+}
+
+// UnmarshalSQLReviewRuleConfig will unmarshal the YAML SQL review rule configuration to the rule list.
+// If the configuration provides the template, the rule list is merged into the template.
+func UnmarshalSQLReviewRuleConfig(data []byte) ([]*SQLReviewRule, error) {
+	config := &sqlReviewConfig{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal SQL review rule config")
+	}
+	if config.Template != "" {
+		return MergeSQLReviewRules(&SQLReviewConfigOverride{
+			Template: config.Template,
+			RuleList: config.RuleList,
+		})
+	}
+
+	var res []*SQLReviewRule
+	for _, ruleData := range config.RuleList {
+		switch ruleData.Level {
+		case SchemaRuleLevelError, SchemaRuleLevelWarning, SchemaRuleLevelDisabled:
+		default:
+			return nil, errors.Errorf("invalid level %q for rule %s", ruleData.Level, ruleData.Type)
+		}
+		payload := "{}"
+		if len(ruleData.Payload) > 0 {
+			str, err := json.Marshal(ruleData.Payload)
+			if err != nil {
+				return nil, err
+			}
+			payload = string(str)
+		}
+		rule := &SQLReviewRule{
+			Type:    ruleData.Type,
+			Level:   ruleData.Level,
+			Payload: payload,
+		}
+		if err := rule.Validate(); err != nil {
+			return nil, err
+		}
+		res = append(res, rule)
+	}
+	return res, nil
+}
+
 func parseSQLReviewTemplateList() ([]*SQLReviewTemplateData, error) {
 	prodTemplate := &SQLReviewTemplateData{}
 	devTemplate := &SQLReviewTemplateData{}
+	postgresProdTemplate := &SQLReviewTemplateData{}
 
 	if err := yaml.Unmarshal([]byte(sqlReviewProdTemplateStr), prodTemplate); err != nil {
 		return nil, err
@@ -84,10 +140,14 @@ func parseSQLReviewTemplateList() ([]*SQLReviewTemplateData, error) {
 	if err := yaml.Unmarshal([]byte(sqlReviewDevTemplateStr), devTemplate); err != nil {
 		return nil, err
 	}
+	if err := yaml.Unmarshal([]byte(sqlReviewPostgreSQLProdTemplateStr), postgresProdTemplate); err != nil {
+		return nil, err
+	}
 
 	return []*SQLReviewTemplateData{
 		prodTemplate,
 		devTemplate,
+		postgresProdTemplate,
 	}, nil
 }
 
@@ -77,3 +77,35 @@ func TestConfigOverride(t *testing.T) {
 		}
 	}
 }
+
+func TestUnmarshalSQLReviewRuleConfig(t *testing.T) {
+	ruleList, err := UnmarshalSQLReviewRuleConfig([]byte(mockConfigOverrideYAMLStr))
+	require.NoError(t, err)
+	override := &SQLReviewConfigOverride{}
+	require.NoError(t, yaml.Unmarshal([]byte(mockConfigOverrideYAMLStr), override))
+	expect, err := MergeSQLReviewRules(override)
+	require.NoError(t, err)
+	assert.Equal(t, expect, ruleList)
+
+	ruleList, err = UnmarshalSQLReviewRuleConfig([]byte(`
+id: custom
+ruleList:
+  - type: statement.select.no-select-all
+    level: ERROR
+  - type: naming.table
+    level: WARNING
+    payload:
+      format: "^[a-z]+$"
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []*SQLReviewRule{
+		{Type: SchemaRuleStatementNoSelectAll, Level: SchemaRuleLevelError, Payload: "{}"},
+		{Type: SchemaRuleTableNaming, Level: SchemaRuleLevelWarning, Payload: `{"format":"^[a-z]+$"}`},
+	}, ruleList)
+
+	_, err = UnmarshalSQLReviewRuleConfig([]byte(`
+ruleList:
+  - type: statement.select.no-select-all
+`))
+	require.Error(t, err)
+}
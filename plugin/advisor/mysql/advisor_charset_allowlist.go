@@ -64,7 +64,7 @@ func (*CharsetAllowlistAdvisor) Check(ctx advisor.Context, statement string) ([]
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmtList, checker.adviceList), nil
 }
 
 type charsetAllowlistChecker struct {
@@ -64,7 +64,7 @@ func (*CollationAllowlistAdvisor) Check(ctx advisor.Context, statement string) (
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmtList, checker.adviceList), nil
 }
 
 type collationAllowlistChecker struct {
@@ -57,7 +57,7 @@ func (*ColumRequireDefaultAdvisor) Check(ctx advisor.Context, statement string)
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmtList, checker.adviceList), nil
 }
 
 type columRequireDefaultChecker struct {
@@ -60,7 +60,7 @@ func (*ColumnAutoIncrementInitialValueAdvisor) Check(ctx advisor.Context, statem
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmtList, checker.adviceList), nil
 }
 
 type columnAutoIncrementInitialValueChecker struct {
@@ -57,7 +57,7 @@ func (*ColumnAutoIncrementMustIntegerAdvisor) Check(ctx advisor.Context, stateme
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmtList, checker.adviceList), nil
 }
 
 type columnAutoIncrementMustIntegerChecker struct {
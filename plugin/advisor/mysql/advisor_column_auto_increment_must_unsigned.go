@@ -56,7 +56,7 @@ func (*ColumnAutoIncrementMustUnsignedAdvisor) Check(ctx advisor.Context, statem
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmtList, checker.adviceList), nil
 }
 
 type columnAutoIncrementMustUnsignedChecker struct {
@@ -62,7 +62,7 @@ func (*ColumnCommentConventionAdvisor) Check(ctx advisor.Context, statement stri
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmtList, checker.adviceList), nil
 }
 
 type columnCommentConventionChecker struct {
@@ -81,6 +81,7 @@ type columnCommentData struct {
 	table   string
 	column  string
 	line    int
+	def     *ast.ColumnDef
 }
 
 // Enter implements the ast.Visitor interface.
@@ -96,6 +97,7 @@ func (checker *columnCommentConventionChecker) Enter(in ast.Node) (ast.Node, boo
 				table:   node.Table.Name.O,
 				column:  column.Name.Name.O,
 				line:    column.OriginTextPosition(),
+				def:     column,
 			})
 		}
 	case *ast.AlterTableStmt:
@@ -111,6 +113,7 @@ func (checker *columnCommentConventionChecker) Enter(in ast.Node) (ast.Node, boo
 						table:   table,
 						column:  column.Name.Name.O,
 						line:    checker.line,
+						def:     column,
 					})
 				}
 			case ast.AlterTableChangeColumn, ast.AlterTableModifyColumn:
@@ -121,6 +124,7 @@ func (checker *columnCommentConventionChecker) Enter(in ast.Node) (ast.Node, boo
 					table:   table,
 					column:  spec.NewColumns[0].Name.Name.O,
 					line:    checker.line,
+					def:     spec.NewColumns[0],
 				})
 			}
 		}
@@ -129,20 +133,22 @@ func (checker *columnCommentConventionChecker) Enter(in ast.Node) (ast.Node, boo
 	for _, column := range columnList {
 		if checker.required && !column.exist {
 			checker.adviceList = append(checker.adviceList, advisor.Advice{
-				Status:  checker.level,
-				Code:    advisor.NoColumnComment,
-				Title:   checker.title,
-				Content: fmt.Sprintf("Column `%s`.`%s` requires comments", column.table, column.column),
-				Line:    column.line,
+				Status:     checker.level,
+				Code:       advisor.NoColumnComment,
+				Title:      checker.title,
+				Content:    fmt.Sprintf("Column `%s`.`%s` requires comments", column.table, column.column),
+				Line:       column.line,
+				Suggestion: suggestColumnComment(in.(ast.StmtNode), column.def, ""),
 			})
 		}
 		if checker.maxLength >= 0 && len(column.comment) > checker.maxLength {
 			checker.adviceList = append(checker.adviceList, advisor.Advice{
-				Status:  checker.level,
-				Code:    advisor.ColumnCommentTooLong,
-				Title:   checker.title,
-				Content: fmt.Sprintf("The length of column `%s`.`%s` comment should be within %d characters", column.table, column.column, checker.maxLength),
-				Line:    column.line,
+				Status:     checker.level,
+				Code:       advisor.ColumnCommentTooLong,
+				Title:      checker.title,
+				Content:    fmt.Sprintf("The length of column `%s`.`%s` comment should be within %d characters", column.table, column.column, checker.maxLength),
+				Line:       column.line,
+				Suggestion: suggestColumnComment(in.(ast.StmtNode), column.def, truncateComment(column.comment, checker.maxLength)),
 			})
 		}
 	}
@@ -174,3 +180,20 @@ func (checker *columnCommentConventionChecker) columnComment(column *ast.ColumnD
 
 	return false, ""
 }
+
+// suggestColumnComment returns the statement with the given comment for the column.
+func suggestColumnComment(node ast.StmtNode, column *ast.ColumnDef, comment string) string {
+	originOptions := column.Options
+	defer func() {
+		column.Options = originOptions
+	}()
+
+	var options []*ast.ColumnOption
+	for _, option := range originOptions {
+		if option.Tp != ast.ColumnOptionComment {
+			options = append(options, option)
+		}
+	}
+	column.Options = append(options, &ast.ColumnOption{Tp: ast.ColumnOptionComment, Expr: ast.NewValueExpr(comment, "", "")})
+	return restoreSuggestion(node)
+}
@@ -31,18 +31,20 @@ func TestColumnCommentConvention(t *testing.T) {
 				c int)`,
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.NoColumnComment,
-					Title:   "column.comment",
-					Content: "Column `t`.`b` requires comments",
-					Line:    3,
+					Status:     advisor.Warn,
+					Code:       advisor.NoColumnComment,
+					Title:      "column.comment",
+					Content:    "Column `t`.`b` requires comments",
+					Line:       3,
+					Suggestion: "CREATE TABLE `t` (`a` INT COMMENT 'some comments',`b` INT COMMENT '',`c` INT);",
 				},
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.NoColumnComment,
-					Title:   "column.comment",
-					Content: "Column `t`.`c` requires comments",
-					Line:    4,
+					Status:     advisor.Warn,
+					Code:       advisor.NoColumnComment,
+					Title:      "column.comment",
+					Content:    "Column `t`.`c` requires comments",
+					Line:       4,
+					Suggestion: "CREATE TABLE `t` (`a` INT COMMENT 'some comments',`b` INT,`c` INT COMMENT '');",
 				},
 			},
 		},
@@ -52,11 +54,12 @@ func TestColumnCommentConvention(t *testing.T) {
 				ALTER TABLE t ADD COLUMN b int`,
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.NoColumnComment,
-					Title:   "column.comment",
-					Content: "Column `t`.`b` requires comments",
-					Line:    3,
+					Status:     advisor.Warn,
+					Code:       advisor.NoColumnComment,
+					Title:      "column.comment",
+					Content:    "Column `t`.`b` requires comments",
+					Line:       3,
+					Suggestion: "ALTER TABLE `t` ADD COLUMN `b` INT COMMENT '';",
 				},
 			},
 		},
@@ -66,11 +69,12 @@ func TestColumnCommentConvention(t *testing.T) {
 				ALTER TABLE t CHANGE COLUMN a b int`,
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.NoColumnComment,
-					Title:   "column.comment",
-					Content: "Column `t`.`b` requires comments",
-					Line:    3,
+					Status:     advisor.Warn,
+					Code:       advisor.NoColumnComment,
+					Title:      "column.comment",
+					Content:    "Column `t`.`b` requires comments",
+					Line:       3,
+					Suggestion: "ALTER TABLE `t` CHANGE COLUMN `a` `b` INT COMMENT '';",
 				},
 			},
 		},
@@ -80,11 +84,12 @@ func TestColumnCommentConvention(t *testing.T) {
 				ALTER TABLE t MODIFY COLUMN b int`,
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.NoColumnComment,
-					Title:   "column.comment",
-					Content: "Column `t`.`b` requires comments",
-					Line:    3,
+					Status:     advisor.Warn,
+					Code:       advisor.NoColumnComment,
+					Title:      "column.comment",
+					Content:    "Column `t`.`b` requires comments",
+					Line:       3,
+					Suggestion: "ALTER TABLE `t` MODIFY COLUMN `b` INT COMMENT '';",
 				},
 			},
 		},
@@ -94,11 +99,12 @@ func TestColumnCommentConvention(t *testing.T) {
 				ALTER TABLE t MODIFY COLUMN b int COMMENT 'abcdefghiakljhakljdsfalugelkhnabsdguelkadf'`,
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.ColumnCommentTooLong,
-					Title:   "column.comment",
-					Content: "The length of column `t`.`b` comment should be within 20 characters",
-					Line:    3,
+					Status:     advisor.Warn,
+					Code:       advisor.ColumnCommentTooLong,
+					Title:      "column.comment",
+					Content:    "The length of column `t`.`b` comment should be within 20 characters",
+					Line:       3,
+					Suggestion: "ALTER TABLE `t` MODIFY COLUMN `b` INT COMMENT 'abcdefghiakljhakljds';",
 				},
 			},
 		},
@@ -56,7 +56,7 @@ func (*ColumnCurrentTimeCountLimitAdvisor) Check(ctx advisor.Context, statement
 		(stmt).Accept(checker)
 	}
 
-	return checker.generateAdvice(), nil
+	return fillAdvicePosition(statement, stmtList, checker.generateAdvice()), nil
 }
 
 type columnCurrentTimeCountLimitChecker struct {
@@ -55,7 +55,7 @@ func (*ColumnDisallowChangingAdvisor) Check(ctx advisor.Context, statement strin
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmtList, checker.adviceList), nil
 }
 
 type columnDisallowChangingChecker struct {
@@ -55,7 +55,7 @@ func (*ColumnDisallowChangingOrderAdvisor) Check(ctx advisor.Context, statement
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmtList, checker.adviceList), nil
 }
 
 type columnDisallowChangingOrderChecker struct {
@@ -58,7 +58,7 @@ func (*ColumnDisallowChangingTypeAdvisor) Check(ctx advisor.Context, statement s
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmtList, checker.adviceList), nil
 }
 
 type columnDisallowChangingTypeChecker struct {
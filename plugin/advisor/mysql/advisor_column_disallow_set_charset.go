@@ -55,7 +55,7 @@ func (*ColumnDisallowSetCharsetAdvisor) Check(ctx advisor.Context, statement str
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmtList, checker.adviceList), nil
 }
 
 type columnDisallowSetCharsetChecker struct {
@@ -61,7 +61,7 @@ func (*ColumnMaximumCharacterLengthAdvisor) Check(ctx advisor.Context, statement
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmtList, checker.adviceList), nil
 }
 
 type columnMaximumCharacterLengthChecker struct {
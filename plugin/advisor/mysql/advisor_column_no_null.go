@@ -47,7 +47,7 @@ func (*ColumnNoNullAdvisor) Check(ctx advisor.Context, statement string) ([]advi
 		(stmtNode).Accept(checker)
 	}
 
-	return checker.generateAdvice(), nil
+	return fillAdvicePosition(statement, root, checker.generateAdvice()), nil
 }
 
 type columnNoNullChecker struct {
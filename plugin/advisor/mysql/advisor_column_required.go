@@ -56,7 +56,7 @@ func (*ColumnRequirementAdvisor) Check(ctx advisor.Context, statement string) ([
 		(stmtNode).Accept(checker)
 	}
 
-	return checker.generateAdviceList(), nil
+	return fillAdvicePosition(statement, root, checker.generateAdviceList()), nil
 }
 
 type columnRequirementChecker struct {
@@ -6,6 +6,8 @@ import (
 	"fmt"
 
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
 
 	"github.com/bytebase/bytebase/plugin/advisor"
 	"github.com/bytebase/bytebase/plugin/advisor/db"
@@ -54,7 +56,7 @@ func (*ColumnSetDefaultForNotNullAdvisor) Check(ctx advisor.Context, statement s
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmtList, checker.adviceList), nil
 }
 
 type columnSetDefaultForNotNullChecker struct {
@@ -66,7 +68,13 @@ type columnSetDefaultForNotNullChecker struct {
 
 // Enter implements the ast.Visitor interface.
 func (checker *columnSetDefaultForNotNullChecker) Enter(in ast.Node) (ast.Node, bool) {
-	var notNullColumnWithNoDefault []columnName
+	type columnData struct {
+		tableName  string
+		columnName string
+		line       int
+		def        *ast.ColumnDef
+	}
+	var notNullColumnWithNoDefault []columnData
 	switch node := in.(type) {
 	// CREATE TABLE
 	case *ast.CreateTableStmt:
@@ -83,10 +91,11 @@ func (checker *columnSetDefaultForNotNullChecker) Enter(in ast.Node) (ast.Node,
 			_, ok := pkColumn[column.Name.Name.O]
 			notNull := ok || !canNull(column)
 			if notNull && !setDefault(column) && needDefault(column) {
-				notNullColumnWithNoDefault = append(notNullColumnWithNoDefault, columnName{
+				notNullColumnWithNoDefault = append(notNullColumnWithNoDefault, columnData{
 					tableName:  node.Table.Name.O,
 					columnName: column.Name.Name.O,
 					line:       column.OriginTextPosition(),
+					def:        column,
 				})
 			}
 		}
@@ -98,20 +107,22 @@ func (checker *columnSetDefaultForNotNullChecker) Enter(in ast.Node) (ast.Node,
 			case ast.AlterTableAddColumns:
 				for _, column := range spec.NewColumns {
 					if !canNull(column) && !setDefault(column) && needDefault(column) {
-						notNullColumnWithNoDefault = append(notNullColumnWithNoDefault, columnName{
+						notNullColumnWithNoDefault = append(notNullColumnWithNoDefault, columnData{
 							tableName:  node.Table.Name.O,
 							columnName: column.Name.Name.O,
 							line:       node.OriginTextPosition(),
+							def:        column,
 						})
 					}
 				}
 			// CHANGE COLUMN and MODIFY COLUMN
 			case ast.AlterTableChangeColumn, ast.AlterTableModifyColumn:
 				if !canNull(spec.NewColumns[0]) && !setDefault(spec.NewColumns[0]) && needDefault(spec.NewColumns[0]) {
-					notNullColumnWithNoDefault = append(notNullColumnWithNoDefault, columnName{
+					notNullColumnWithNoDefault = append(notNullColumnWithNoDefault, columnData{
 						tableName:  node.Table.Name.O,
 						columnName: spec.NewColumns[0].Name.Name.O,
 						line:       node.OriginTextPosition(),
+						def:        spec.NewColumns[0],
 					})
 				}
 			}
@@ -120,11 +131,12 @@ func (checker *columnSetDefaultForNotNullChecker) Enter(in ast.Node) (ast.Node,
 
 	for _, column := range notNullColumnWithNoDefault {
 		checker.adviceList = append(checker.adviceList, advisor.Advice{
-			Status:  checker.level,
-			Code:    advisor.NotNullColumnWithNoDefault,
-			Title:   checker.title,
-			Content: fmt.Sprintf("Column `%s`.`%s` is NOT NULL but doesn't have DEFAULT", column.tableName, column.columnName),
-			Line:    column.line,
+			Status:     checker.level,
+			Code:       advisor.NotNullColumnWithNoDefault,
+			Title:      checker.title,
+			Content:    fmt.Sprintf("Column `%s`.`%s` is NOT NULL but doesn't have DEFAULT", column.tableName, column.columnName),
+			Line:       column.line,
+			Suggestion: suggestDefaultValue(in.(ast.StmtNode), column.def),
 		})
 	}
 
@@ -144,3 +156,37 @@ func setDefault(column *ast.ColumnDef) bool {
 	}
 	return false
 }
+
+// suggestDefaultValue returns the statement with the zero value as the default value for the column.
+// It returns the empty string if we don't know the zero value for the column type.
+func suggestDefaultValue(node ast.StmtNode, column *ast.ColumnDef) string {
+	var value ast.ExprNode
+	switch column.Tp.GetType() {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong,
+		mysql.TypeFloat, mysql.TypeDouble, mysql.TypeNewDecimal, mysql.TypeBit, mysql.TypeYear:
+		value = ast.NewValueExpr(0, "", "")
+	case mysql.TypeString, mysql.TypeVarchar, mysql.TypeVarString, mysql.TypeSet:
+		value = ast.NewValueExpr("", "", "")
+	case mysql.TypeEnum:
+		if len(column.Tp.GetElems()) == 0 {
+			return ""
+		}
+		value = ast.NewValueExpr(column.Tp.GetElems()[0], "", "")
+	case mysql.TypeDatetime, mysql.TypeTimestamp:
+		currentTimestamp := &ast.FuncCallExpr{FnName: model.NewCIStr(ast.CurrentTimestamp)}
+		// The fractional seconds precision of the default value must be the same as the column.
+		if column.Tp.GetDecimal() > 0 {
+			currentTimestamp.Args = []ast.ExprNode{ast.NewValueExpr(column.Tp.GetDecimal(), "", "")}
+		}
+		value = currentTimestamp
+	default:
+		return ""
+	}
+
+	originOptions := column.Options
+	defer func() {
+		column.Options = originOptions
+	}()
+	column.Options = append(append([]*ast.ColumnOption{}, originOptions...), &ast.ColumnOption{Tp: ast.ColumnOptionDefaultValue, Expr: value})
+	return restoreSuggestion(node)
+}
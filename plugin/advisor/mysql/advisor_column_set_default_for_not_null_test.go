@@ -42,11 +42,12 @@ func TestColumnSetDefaultForNotNull(t *testing.T) {
 			)`,
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.NotNullColumnWithNoDefault,
-					Title:   "column.set-default-for-not-null",
-					Content: "Column `book`.`id` is NOT NULL but doesn't have DEFAULT",
-					Line:    2,
+					Status:     advisor.Warn,
+					Code:       advisor.NotNullColumnWithNoDefault,
+					Title:      "column.set-default-for-not-null",
+					Content:    "Column `book`.`id` is NOT NULL but doesn't have DEFAULT",
+					Line:       2,
+					Suggestion: "CREATE TABLE `book` (`id` INT NOT NULL DEFAULT 0);",
 				},
 			},
 		},
@@ -57,11 +58,12 @@ func TestColumnSetDefaultForNotNull(t *testing.T) {
 			)`,
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.NotNullColumnWithNoDefault,
-					Title:   "column.set-default-for-not-null",
-					Content: "Column `book`.`id` is NOT NULL but doesn't have DEFAULT",
-					Line:    2,
+					Status:     advisor.Warn,
+					Code:       advisor.NotNullColumnWithNoDefault,
+					Title:      "column.set-default-for-not-null",
+					Content:    "Column `book`.`id` is NOT NULL but doesn't have DEFAULT",
+					Line:       2,
+					Suggestion: "CREATE TABLE `book` (`id` INT DEFAULT 0,PRIMARY KEY(`id`));",
 				},
 			},
 		},
@@ -84,11 +86,12 @@ func TestColumnSetDefaultForNotNull(t *testing.T) {
 				ALTER TABLE book ADD COLUMN id int NOT NULL`,
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.NotNullColumnWithNoDefault,
-					Title:   "column.set-default-for-not-null",
-					Content: "Column `book`.`id` is NOT NULL but doesn't have DEFAULT",
-					Line:    3,
+					Status:     advisor.Warn,
+					Code:       advisor.NotNullColumnWithNoDefault,
+					Title:      "column.set-default-for-not-null",
+					Content:    "Column `book`.`id` is NOT NULL but doesn't have DEFAULT",
+					Line:       3,
+					Suggestion: "ALTER TABLE `book` ADD COLUMN `id` INT NOT NULL DEFAULT 0;",
 				},
 			},
 		},
@@ -98,11 +101,12 @@ func TestColumnSetDefaultForNotNull(t *testing.T) {
 				ALTER TABLE book MODIFY COLUMN id int NOT NULL`,
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.NotNullColumnWithNoDefault,
-					Title:   "column.set-default-for-not-null",
-					Content: "Column `book`.`id` is NOT NULL but doesn't have DEFAULT",
-					Line:    3,
+					Status:     advisor.Warn,
+					Code:       advisor.NotNullColumnWithNoDefault,
+					Title:      "column.set-default-for-not-null",
+					Content:    "Column `book`.`id` is NOT NULL but doesn't have DEFAULT",
+					Line:       3,
+					Suggestion: "ALTER TABLE `book` MODIFY COLUMN `id` INT NOT NULL DEFAULT 0;",
 				},
 			},
 		},
@@ -138,11 +142,12 @@ func TestColumnSetDefaultForNotNull(t *testing.T) {
 				ALTER TABLE book CHANGE COLUMN uid id int NOT NULL`,
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.NotNullColumnWithNoDefault,
-					Title:   "column.set-default-for-not-null",
-					Content: "Column `book`.`id` is NOT NULL but doesn't have DEFAULT",
-					Line:    3,
+					Status:     advisor.Warn,
+					Code:       advisor.NotNullColumnWithNoDefault,
+					Title:      "column.set-default-for-not-null",
+					Content:    "Column `book`.`id` is NOT NULL but doesn't have DEFAULT",
+					Line:       3,
+					Suggestion: "ALTER TABLE `book` CHANGE COLUMN `uid` `id` INT NOT NULL DEFAULT 0;",
 				},
 			},
 		},
@@ -163,6 +168,39 @@ func TestColumnSetDefaultForNotNull(t *testing.T) {
 				},
 			},
 		},
+		{
+			Statement: `
+				CREATE TABLE book(
+					created_ts datetime(3) NOT NULL,
+					status enum('DRAFT', 'PUBLISHED') NOT NULL,
+					published_date date NOT NULL
+				)`,
+			Want: []advisor.Advice{
+				{
+					Status:     advisor.Warn,
+					Code:       advisor.NotNullColumnWithNoDefault,
+					Title:      "column.set-default-for-not-null",
+					Content:    "Column `book`.`created_ts` is NOT NULL but doesn't have DEFAULT",
+					Line:       3,
+					Suggestion: "CREATE TABLE `book` (`created_ts` DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),`status` ENUM('DRAFT','PUBLISHED') NOT NULL,`published_date` DATE NOT NULL);",
+				},
+				{
+					Status:     advisor.Warn,
+					Code:       advisor.NotNullColumnWithNoDefault,
+					Title:      "column.set-default-for-not-null",
+					Content:    "Column `book`.`status` is NOT NULL but doesn't have DEFAULT",
+					Line:       4,
+					Suggestion: "CREATE TABLE `book` (`created_ts` DATETIME(3) NOT NULL,`status` ENUM('DRAFT','PUBLISHED') NOT NULL DEFAULT 'DRAFT',`published_date` DATE NOT NULL);",
+				},
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.NotNullColumnWithNoDefault,
+					Title:   "column.set-default-for-not-null",
+					Content: "Column `book`.`published_date` is NOT NULL but doesn't have DEFAULT",
+					Line:    5,
+				},
+			},
+		},
 	}
 
 	advisor.RunSQLReviewRuleTests(t, tests, &ColumnSetDefaultForNotNullAdvisor{}, &advisor.SQLReviewRule{
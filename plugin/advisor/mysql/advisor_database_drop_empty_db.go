@@ -53,7 +53,7 @@ func (*DatabaseAllowDropIfEmptyAdvisor) Check(ctx advisor.Context, statement str
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, root, checker.adviceList), nil
 }
 
 type allowDropEmptyDBChecker struct {
@@ -60,7 +60,7 @@ func (*IndexKeyNumberLimitAdvisor) Check(ctx advisor.Context, statement string)
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmtList, checker.adviceList), nil
 }
 
 type indexKeyNumberLimitChecker struct {
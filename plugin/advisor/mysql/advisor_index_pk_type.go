@@ -60,7 +60,7 @@ func (*IndexPkTypeAdvisor) Check(ctx advisor.Context, statement string) ([]advis
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmtList, checker.adviceList), nil
 }
 
 type columnNameToColumnDef map[string]*ast.ColumnDef
@@ -56,7 +56,7 @@ func (*IndexTotalNumberLimitAdvisor) Check(ctx advisor.Context, statement string
 		(stmt).Accept(checker)
 	}
 
-	return checker.generateAdvice(), nil
+	return fillAdvicePosition(statement, stmtList, checker.generateAdvice()), nil
 }
 
 type indexTotalNumberLimitChecker struct {
@@ -62,7 +62,7 @@ func (*IndexTypeNoBlobAdvisor) Check(ctx advisor.Context, statement string) ([]a
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmtList, checker.adviceList), nil
 }
 
 type indexTypeNoBlobChecker struct {
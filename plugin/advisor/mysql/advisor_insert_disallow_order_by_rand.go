@@ -55,7 +55,7 @@ func (*InsertDisallowOrderByRandAdvisor) Check(ctx advisor.Context, statement st
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmtList, checker.adviceList), nil
 }
 
 type insertDisallowOrderByRandChecker struct {
@@ -6,8 +6,10 @@ import (
 	"fmt"
 
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
 
 	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/catalog"
 	"github.com/bytebase/bytebase/plugin/advisor/db"
 )
 
@@ -37,8 +39,9 @@ func (*InsertMustSpecifyColumnAdvisor) Check(ctx advisor.Context, statement stri
 		return nil, err
 	}
 	checker := &insertMustSpecifyColumnChecker{
-		level: level,
-		title: string(ctx.Rule.Type),
+		level:   level,
+		title:   string(ctx.Rule.Type),
+		catalog: ctx.Catalog,
 	}
 
 	for _, stmt := range stmtList {
@@ -55,7 +58,7 @@ func (*InsertMustSpecifyColumnAdvisor) Check(ctx advisor.Context, statement stri
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmtList, checker.adviceList), nil
 }
 
 type insertMustSpecifyColumnChecker struct {
@@ -64,6 +67,7 @@ type insertMustSpecifyColumnChecker struct {
 	title      string
 	text       string
 	line       int
+	catalog    *catalog.Finder
 }
 
 // Enter implements the ast.Visitor interface.
@@ -71,11 +75,12 @@ func (checker *insertMustSpecifyColumnChecker) Enter(in ast.Node) (ast.Node, boo
 	if node, ok := in.(*ast.InsertStmt); ok {
 		if node.Columns == nil {
 			checker.adviceList = append(checker.adviceList, advisor.Advice{
-				Status:  checker.level,
-				Code:    advisor.InsertNotSpecifyColumn,
-				Title:   checker.title,
-				Content: fmt.Sprintf("The INSERT statement must specify columns but \"%s\" does not", checker.text),
-				Line:    checker.line,
+				Status:     checker.level,
+				Code:       advisor.InsertNotSpecifyColumn,
+				Title:      checker.title,
+				Content:    fmt.Sprintf("The INSERT statement must specify columns but \"%s\" does not", checker.text),
+				Line:       checker.line,
+				Suggestion: checker.suggestColumnList(node),
 			})
 		}
 	}
@@ -87,3 +92,40 @@ func (checker *insertMustSpecifyColumnChecker) Enter(in ast.Node) (ast.Node, boo
 func (*insertMustSpecifyColumnChecker) Leave(in ast.Node) (ast.Node, bool) {
 	return in, true
 }
+
+// suggestColumnList returns the INSERT statement with the column list of the table in the catalog.
+// We only suggest it for INSERT ... VALUES statements whose values match the columns exactly.
+func (checker *insertMustSpecifyColumnChecker) suggestColumnList(node *ast.InsertStmt) string {
+	if checker.catalog == nil || node.Table == nil || len(node.Lists) == 0 || len(node.Setlist) != 0 {
+		return ""
+	}
+	tableSource, ok := node.Table.TableRefs.Left.(*ast.TableSource)
+	if !ok {
+		return ""
+	}
+	tableName, ok := tableSource.Source.(*ast.TableName)
+	if !ok {
+		return ""
+	}
+	table := checker.catalog.Final.FindTable(&catalog.TableFind{TableName: tableName.Name.O})
+	if table == nil {
+		return ""
+	}
+	columnNameList := table.ColumnNameList()
+	if len(columnNameList) == 0 {
+		return ""
+	}
+	for _, list := range node.Lists {
+		if len(list) != len(columnNameList) {
+			return ""
+		}
+	}
+
+	defer func() {
+		node.Columns = nil
+	}()
+	for _, name := range columnNameList {
+		node.Columns = append(node.Columns, &ast.ColumnName{Name: model.NewCIStr(name)})
+	}
+	return restoreSuggestion(node)
+}
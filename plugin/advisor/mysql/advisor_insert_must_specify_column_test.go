@@ -25,11 +25,12 @@ func TestInsertMustSpecifyColumn(t *testing.T) {
 			Statement: `INSERT INTO tech_book VALUES (1, '1')`,
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.InsertNotSpecifyColumn,
-					Title:   "statement.insert.must-specify-column",
-					Content: "The INSERT statement must specify columns but \"INSERT INTO tech_book VALUES (1, '1')\" does not",
-					Line:    1,
+					Status:     advisor.Warn,
+					Code:       advisor.InsertNotSpecifyColumn,
+					Title:      "statement.insert.must-specify-column",
+					Content:    "The INSERT statement must specify columns but \"INSERT INTO tech_book VALUES (1, '1')\" does not",
+					Line:       1,
+					Suggestion: "INSERT INTO `tech_book` (`id`,`name`) VALUES (1,'1');",
 				},
 			},
 		},
@@ -67,7 +67,7 @@ func (*InsertRowLimitAdvisor) Check(ctx advisor.Context, statement string) ([]ad
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmtList, checker.adviceList), nil
 }
 
 type insertRowLimitChecker struct {
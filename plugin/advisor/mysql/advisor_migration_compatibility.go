@@ -51,7 +51,7 @@ func (*CompatibilityAdvisor) Check(ctx advisor.Context, statement string) ([]adv
 			Content: "",
 		})
 	}
-	return c.adviceList, nil
+	return fillAdvicePosition(statement, root, c.adviceList), nil
 }
 
 type compatibilityChecker struct {
@@ -62,7 +62,7 @@ func (*NamingAutoIncrementColumnAdvisor) Check(ctx advisor.Context, statement st
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmtList, checker.adviceList), nil
 }
 
 type namingAutoIncrementColumnChecker struct {
@@ -5,6 +5,7 @@ import (
 	"regexp"
 
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
 
 	"github.com/bytebase/bytebase/plugin/advisor"
 	"github.com/bytebase/bytebase/plugin/advisor/db"
@@ -60,7 +61,7 @@ func (*NamingColumnConventionAdvisor) Check(ctx advisor.Context, statement strin
 		})
 	}
 
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, root, checker.adviceList), nil
 }
 
 type namingColumnConventionChecker struct {
@@ -77,6 +78,8 @@ func (v *namingColumnConventionChecker) Enter(in ast.Node) (ast.Node, bool) {
 	type columnData struct {
 		name string
 		line int
+		// ref is the pointer to the column name in the statement to rename it in the suggestion.
+		ref *model.CIStr
 	}
 	var columnList []columnData
 	var tableName string
@@ -88,6 +91,7 @@ func (v *namingColumnConventionChecker) Enter(in ast.Node) (ast.Node, bool) {
 			columnList = append(columnList, columnData{
 				name: column.Name.Name.O,
 				line: column.OriginTextPosition(),
+				ref:  &column.Name.Name,
 			})
 		}
 	// ALTER TABLE
@@ -100,6 +104,7 @@ func (v *namingColumnConventionChecker) Enter(in ast.Node) (ast.Node, bool) {
 				columnList = append(columnList, columnData{
 					name: spec.NewColumnName.Name.O,
 					line: in.OriginTextPosition(),
+					ref:  &spec.NewColumnName.Name,
 				})
 			// ADD COLUMNS
 			case ast.AlterTableAddColumns:
@@ -107,6 +112,7 @@ func (v *namingColumnConventionChecker) Enter(in ast.Node) (ast.Node, bool) {
 					columnList = append(columnList, columnData{
 						name: column.Name.Name.O,
 						line: in.OriginTextPosition(),
+						ref:  &column.Name.Name,
 					})
 				}
 			// CHANGE COLUMN
@@ -114,6 +120,7 @@ func (v *namingColumnConventionChecker) Enter(in ast.Node) (ast.Node, bool) {
 				columnList = append(columnList, columnData{
 					name: spec.NewColumns[0].Name.Name.O,
 					line: in.OriginTextPosition(),
+					ref:  &spec.NewColumns[0].Name.Name,
 				})
 			}
 		}
@@ -122,20 +129,22 @@ func (v *namingColumnConventionChecker) Enter(in ast.Node) (ast.Node, bool) {
 	for _, column := range columnList {
 		if !v.format.MatchString(column.name) {
 			v.adviceList = append(v.adviceList, advisor.Advice{
-				Status:  v.level,
-				Code:    advisor.NamingColumnConventionMismatch,
-				Title:   v.title,
-				Content: fmt.Sprintf("`%s`.`%s` mismatches column naming convention, naming format should be %q", tableName, column.name, v.format),
-				Line:    column.line,
+				Status:     v.level,
+				Code:       advisor.NamingColumnConventionMismatch,
+				Title:      v.title,
+				Content:    fmt.Sprintf("`%s`.`%s` mismatches column naming convention, naming format should be %q", tableName, column.name, v.format),
+				Line:       column.line,
+				Suggestion: v.suggestName(in.(ast.StmtNode), column.ref),
 			})
 		}
 		if v.maxLength > 0 && len(column.name) > v.maxLength {
 			v.adviceList = append(v.adviceList, advisor.Advice{
-				Status:  v.level,
-				Code:    advisor.NamingColumnConventionMismatch,
-				Title:   v.title,
-				Content: fmt.Sprintf("`%s`.`%s` mismatches column naming convention, its length should be within %d characters", tableName, column.name, v.maxLength),
-				Line:    column.line,
+				Status:     v.level,
+				Code:       advisor.NamingColumnConventionMismatch,
+				Title:      v.title,
+				Content:    fmt.Sprintf("`%s`.`%s` mismatches column naming convention, its length should be within %d characters", tableName, column.name, v.maxLength),
+				Line:       column.line,
+				Suggestion: v.suggestName(in.(ast.StmtNode), column.ref),
 			})
 		}
 	}
@@ -147,3 +156,17 @@ func (v *namingColumnConventionChecker) Enter(in ast.Node) (ast.Node, bool) {
 func (*namingColumnConventionChecker) Leave(in ast.Node) (ast.Node, bool) {
 	return in, true
 }
+
+// suggestName returns the statement with the column renamed to match the naming convention.
+func (v *namingColumnConventionChecker) suggestName(node ast.StmtNode, name *model.CIStr) string {
+	suggestion, ok := advisor.SuggestName(name.O, v.format, v.maxLength)
+	if !ok {
+		return ""
+	}
+	origin := *name
+	defer func() {
+		*name = origin
+	}()
+	*name = model.NewCIStr(suggestion)
+	return restoreSuggestion(node)
+}
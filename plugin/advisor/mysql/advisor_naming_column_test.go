@@ -18,11 +18,12 @@ func TestNamingColumnConvention(t *testing.T) {
 			Statement: "CREATE TABLE book(id int, creatorId int)",
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.NamingColumnConventionMismatch,
-					Title:   "naming.column",
-					Content: "`book`.`creatorId` mismatches column naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
-					Line:    1,
+					Status:     advisor.Warn,
+					Code:       advisor.NamingColumnConventionMismatch,
+					Title:      "naming.column",
+					Content:    "`book`.`creatorId` mismatches column naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
+					Line:       1,
+					Suggestion: "CREATE TABLE `book` (`id` INT,`creator_id` INT);",
 				},
 			},
 		},
@@ -30,11 +31,12 @@ func TestNamingColumnConvention(t *testing.T) {
 			Statement: fmt.Sprintf("CREATE TABLE book(id int, %s int)", invalidColumnName),
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.NamingColumnConventionMismatch,
-					Title:   "naming.column",
-					Content: fmt.Sprintf("`book`.`%s` mismatches column naming convention, its length should be within 64 characters", invalidColumnName),
-					Line:    1,
+					Status:     advisor.Warn,
+					Code:       advisor.NamingColumnConventionMismatch,
+					Title:      "naming.column",
+					Content:    fmt.Sprintf("`book`.`%s` mismatches column naming convention, its length should be within 64 characters", invalidColumnName),
+					Line:       1,
+					Suggestion: fmt.Sprintf("CREATE TABLE `book` (`id` INT,`%s` INT);", invalidColumnName[:64]),
 				},
 			},
 		},
@@ -54,11 +56,12 @@ func TestNamingColumnConvention(t *testing.T) {
 						ALTER TABLE book RENAME COLUMN creator_id TO creatorId`,
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.NamingColumnConventionMismatch,
-					Title:   "naming.column",
-					Content: "`book`.`creatorId` mismatches column naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
-					Line:    2,
+					Status:     advisor.Warn,
+					Code:       advisor.NamingColumnConventionMismatch,
+					Title:      "naming.column",
+					Content:    "`book`.`creatorId` mismatches column naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
+					Line:       2,
+					Suggestion: "ALTER TABLE `book` RENAME COLUMN `creator_id` TO `creator_id`;",
 				},
 			},
 		},
@@ -83,11 +86,12 @@ func TestNamingColumnConvention(t *testing.T) {
 						ALTER TABLE book CHANGE COLUMN creator_id creatorId int;`,
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.NamingColumnConventionMismatch,
-					Title:   "naming.column",
-					Content: "`book`.`creatorId` mismatches column naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
-					Line:    7,
+					Status:     advisor.Warn,
+					Code:       advisor.NamingColumnConventionMismatch,
+					Title:      "naming.column",
+					Content:    "`book`.`creatorId` mismatches column naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
+					Line:       7,
+					Suggestion: "ALTER TABLE `book` CHANGE COLUMN `creator_id` `creator_id` INT;",
 				},
 			},
 		},
@@ -122,11 +126,12 @@ func TestNamingColumnConvention(t *testing.T) {
 						ALTER TABLE book ADD COLUMN contentString varchar(255);`,
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.NamingColumnConventionMismatch,
-					Title:   "naming.column",
-					Content: "`book`.`contentString` mismatches column naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
-					Line:    6,
+					Status:     advisor.Warn,
+					Code:       advisor.NamingColumnConventionMismatch,
+					Title:      "naming.column",
+					Content:    "`book`.`contentString` mismatches column naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
+					Line:       6,
+					Suggestion: "ALTER TABLE `book` ADD COLUMN `content_string` VARCHAR(255);",
 				},
 			},
 		},
@@ -142,32 +147,36 @@ func TestNamingColumnConvention(t *testing.T) {
 							updatedTs timestamp);`,
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.NamingColumnConventionMismatch,
-					Title:   "naming.column",
-					Content: "`book`.`createdTs` mismatches column naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
-					Line:    3,
+					Status:     advisor.Warn,
+					Code:       advisor.NamingColumnConventionMismatch,
+					Title:      "naming.column",
+					Content:    "`book`.`createdTs` mismatches column naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
+					Line:       3,
+					Suggestion: "CREATE TABLE `book` (`id` INT,`created_ts` TIMESTAMP,`updaterId` INT,`updated_ts` TIMESTAMP);",
 				},
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.NamingColumnConventionMismatch,
-					Title:   "naming.column",
-					Content: "`book`.`updaterId` mismatches column naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
-					Line:    4,
+					Status:     advisor.Warn,
+					Code:       advisor.NamingColumnConventionMismatch,
+					Title:      "naming.column",
+					Content:    "`book`.`updaterId` mismatches column naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
+					Line:       4,
+					Suggestion: "CREATE TABLE `book` (`id` INT,`createdTs` TIMESTAMP,`updater_id` INT,`updated_ts` TIMESTAMP);",
 				},
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.NamingColumnConventionMismatch,
-					Title:   "naming.column",
-					Content: "`student`.`createdTs` mismatches column naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
-					Line:    8,
+					Status:     advisor.Warn,
+					Code:       advisor.NamingColumnConventionMismatch,
+					Title:      "naming.column",
+					Content:    "`student`.`createdTs` mismatches column naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
+					Line:       8,
+					Suggestion: "CREATE TABLE `student` (`id` INT,`created_ts` TIMESTAMP,`updatedTs` TIMESTAMP);",
 				},
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.NamingColumnConventionMismatch,
-					Title:   "naming.column",
-					Content: "`student`.`updatedTs` mismatches column naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
-					Line:    9,
+					Status:     advisor.Warn,
+					Code:       advisor.NamingColumnConventionMismatch,
+					Title:      "naming.column",
+					Content:    "`student`.`updatedTs` mismatches column naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
+					Line:       9,
+					Suggestion: "CREATE TABLE `student` (`id` INT,`createdTs` TIMESTAMP,`updated_ts` TIMESTAMP);",
 				},
 			},
 		},
@@ -60,7 +60,7 @@ func (*NamingFKConventionAdvisor) Check(ctx advisor.Context, statement string) (
 		})
 	}
 
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, root, checker.adviceList), nil
 }
 
 type namingFKConventionChecker struct {
@@ -63,7 +63,7 @@ func (*NamingIndexConventionAdvisor) Check(ctx advisor.Context, statement string
 		})
 	}
 
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, root, checker.adviceList), nil
 }
 
 type namingIndexConventionChecker struct {
@@ -5,6 +5,7 @@ import (
 	"regexp"
 
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
 
 	"github.com/bytebase/bytebase/plugin/advisor"
 	"github.com/bytebase/bytebase/plugin/advisor/db"
@@ -57,7 +58,7 @@ func (*NamingTableConventionAdvisor) Check(ctx advisor.Context, statement string
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, root, checker.adviceList), nil
 }
 
 type namingTableConventionChecker struct {
@@ -70,44 +71,48 @@ type namingTableConventionChecker struct {
 
 // Enter implements the ast.Visitor interface.
 func (v *namingTableConventionChecker) Enter(in ast.Node) (ast.Node, bool) {
-	var tableNames []string
+	// We collect the pointers to the table names to rename them in the suggestion.
+	var tableNames []*model.CIStr
 	switch node := in.(type) {
 	// CREATE TABLE
 	case *ast.CreateTableStmt:
 		// Original string
-		tableNames = append(tableNames, node.Table.Name.O)
+		tableNames = append(tableNames, &node.Table.Name)
 	// ALTER TABLE
 	case *ast.AlterTableStmt:
 		for _, spec := range node.Specs {
 			// RENAME TABLE
 			if spec.Tp == ast.AlterTableRenameTable {
-				tableNames = append(tableNames, spec.NewTable.Name.O)
+				tableNames = append(tableNames, &spec.NewTable.Name)
 			}
 		}
 	// RENAME TABLE
 	case *ast.RenameTableStmt:
 		for _, table2Table := range node.TableToTables {
-			tableNames = append(tableNames, table2Table.NewTable.Name.O)
+			tableNames = append(tableNames, &table2Table.NewTable.Name)
 		}
 	}
 
-	for _, tableName := range tableNames {
+	for _, name := range tableNames {
+		tableName := name.O
 		if !v.format.MatchString(tableName) {
 			v.adviceList = append(v.adviceList, advisor.Advice{
-				Status:  v.level,
-				Code:    advisor.NamingTableConventionMismatch,
-				Title:   v.title,
-				Content: fmt.Sprintf("`%s` mismatches table naming convention, naming format should be %q", tableName, v.format),
-				Line:    in.OriginTextPosition(),
+				Status:     v.level,
+				Code:       advisor.NamingTableConventionMismatch,
+				Title:      v.title,
+				Content:    fmt.Sprintf("`%s` mismatches table naming convention, naming format should be %q", tableName, v.format),
+				Line:       in.OriginTextPosition(),
+				Suggestion: v.suggestName(in.(ast.StmtNode), name),
 			})
 		}
 		if v.maxLength > 0 && len(tableName) > v.maxLength {
 			v.adviceList = append(v.adviceList, advisor.Advice{
-				Status:  v.level,
-				Code:    advisor.NamingTableConventionMismatch,
-				Title:   v.title,
-				Content: fmt.Sprintf("`%s` mismatches table naming convention, its length should be within %d characters", tableName, v.maxLength),
-				Line:    in.OriginTextPosition(),
+				Status:     v.level,
+				Code:       advisor.NamingTableConventionMismatch,
+				Title:      v.title,
+				Content:    fmt.Sprintf("`%s` mismatches table naming convention, its length should be within %d characters", tableName, v.maxLength),
+				Line:       in.OriginTextPosition(),
+				Suggestion: v.suggestName(in.(ast.StmtNode), name),
 			})
 		}
 	}
@@ -118,3 +123,17 @@ func (v *namingTableConventionChecker) Enter(in ast.Node) (ast.Node, bool) {
 func (*namingTableConventionChecker) Leave(in ast.Node) (ast.Node, bool) {
 	return in, true
 }
+
+// suggestName returns the statement with the table renamed to match the naming convention.
+func (v *namingTableConventionChecker) suggestName(node ast.StmtNode, name *model.CIStr) string {
+	suggestion, ok := advisor.SuggestName(name.O, v.format, v.maxLength)
+	if !ok {
+		return ""
+	}
+	origin := *name
+	defer func() {
+		*name = origin
+	}()
+	*name = model.NewCIStr(suggestion)
+	return restoreSuggestion(node)
+}
@@ -18,11 +18,12 @@ func TestMySQLNamingTableConvention(t *testing.T) {
 			Statement: "CREATE TABLE techBook(id int, name varchar(255))",
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Error,
-					Code:    advisor.NamingTableConventionMismatch,
-					Title:   "naming.table",
-					Content: "`techBook` mismatches table naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
-					Line:    1,
+					Status:     advisor.Error,
+					Code:       advisor.NamingTableConventionMismatch,
+					Title:      "naming.table",
+					Content:    "`techBook` mismatches table naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
+					Line:       1,
+					Suggestion: "CREATE TABLE `tech_book` (`id` INT,`name` VARCHAR(255));",
 				},
 			},
 		},
@@ -41,11 +42,12 @@ func TestMySQLNamingTableConvention(t *testing.T) {
 			Statement: fmt.Sprintf("CREATE TABLE %s(id int, name varchar(255))", invalidTableName),
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Error,
-					Code:    advisor.NamingTableConventionMismatch,
-					Title:   "naming.table",
-					Content: fmt.Sprintf("`%s` mismatches table naming convention, its length should be within 64 characters", invalidTableName),
-					Line:    1,
+					Status:     advisor.Error,
+					Code:       advisor.NamingTableConventionMismatch,
+					Title:      "naming.table",
+					Content:    fmt.Sprintf("`%s` mismatches table naming convention, its length should be within 64 characters", invalidTableName),
+					Line:       1,
+					Suggestion: fmt.Sprintf("CREATE TABLE `%s` (`id` INT,`name` VARCHAR(255));", invalidTableName[:64]),
 				},
 			},
 		},
@@ -53,11 +55,12 @@ func TestMySQLNamingTableConvention(t *testing.T) {
 			Statement: "ALTER TABLE tech_book RENAME TO TechBook",
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Error,
-					Code:    advisor.NamingTableConventionMismatch,
-					Title:   "naming.table",
-					Content: "`TechBook` mismatches table naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
-					Line:    1,
+					Status:     advisor.Error,
+					Code:       advisor.NamingTableConventionMismatch,
+					Title:      "naming.table",
+					Content:    "`TechBook` mismatches table naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
+					Line:       1,
+					Suggestion: "ALTER TABLE `tech_book` RENAME AS `tech_book`;",
 				},
 			},
 		},
@@ -76,11 +79,12 @@ func TestMySQLNamingTableConvention(t *testing.T) {
 			Statement: "RENAME TABLE tech_book TO tech_book_copy, tech_book_copy TO LiteraryBook",
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Error,
-					Code:    advisor.NamingTableConventionMismatch,
-					Title:   "naming.table",
-					Content: "`LiteraryBook` mismatches table naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
-					Line:    1,
+					Status:     advisor.Error,
+					Code:       advisor.NamingTableConventionMismatch,
+					Title:      "naming.table",
+					Content:    "`LiteraryBook` mismatches table naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
+					Line:       1,
+					Suggestion: "RENAME TABLE `tech_book` TO `tech_book_copy`, `tech_book_copy` TO `literary_book`;",
 				},
 			},
 		},
@@ -88,18 +92,20 @@ func TestMySQLNamingTableConvention(t *testing.T) {
 			Statement: "CREATE TABLE literary_book(a int);RENAME TABLE tech_book TO TechBook, literary_book TO LiteraryBook",
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Error,
-					Code:    advisor.NamingTableConventionMismatch,
-					Title:   "naming.table",
-					Content: "`TechBook` mismatches table naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
-					Line:    1,
+					Status:     advisor.Error,
+					Code:       advisor.NamingTableConventionMismatch,
+					Title:      "naming.table",
+					Content:    "`TechBook` mismatches table naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
+					Line:       1,
+					Suggestion: "RENAME TABLE `tech_book` TO `tech_book`, `literary_book` TO `LiteraryBook`;",
 				},
 				{
-					Status:  advisor.Error,
-					Code:    advisor.NamingTableConventionMismatch,
-					Title:   "naming.table",
-					Content: "`LiteraryBook` mismatches table naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
-					Line:    1,
+					Status:     advisor.Error,
+					Code:       advisor.NamingTableConventionMismatch,
+					Title:      "naming.table",
+					Content:    "`LiteraryBook` mismatches table naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
+					Line:       1,
+					Suggestion: "RENAME TABLE `tech_book` TO `TechBook`, `literary_book` TO `literary_book`;",
 				},
 			},
 		},
@@ -61,7 +61,7 @@ func (*NamingUKConventionAdvisor) Check(ctx advisor.Context, statement string) (
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, root, checker.adviceList), nil
 }
 
 type namingUKConventionChecker struct {
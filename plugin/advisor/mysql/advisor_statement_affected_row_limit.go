@@ -68,7 +68,7 @@ func (*StatementAffectedRowLimitAdvisor) Check(ctx advisor.Context, statement st
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmtList, checker.adviceList), nil
 }
 
 type statementAffectedRowLimitChecker struct {
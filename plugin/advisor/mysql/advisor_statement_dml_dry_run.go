@@ -61,7 +61,7 @@ func (*StatementDmlDryRunAdvisor) Check(ctx advisor.Context, statement string) (
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmtList, checker.adviceList), nil
 }
 
 type statementDmlDryRunChecker struct {
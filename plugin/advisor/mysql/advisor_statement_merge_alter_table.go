@@ -49,7 +49,7 @@ func (*StatementMergeAlterTableAdvisor) Check(ctx advisor.Context, statement str
 		(stmt).Accept(checker)
 	}
 
-	return checker.generateAdvice(), nil
+	return fillAdvicePosition(statement, stmtList, checker.generateAdvice()), nil
 }
 
 type statementMergeAlterTableChecker struct {
@@ -65,6 +65,10 @@ type tableStatement struct {
 	name     string
 	count    int
 	lastLine int
+	// createTable is the CREATE TABLE statement for the table, and it's nil if the table isn't created in the statements.
+	createTable *ast.CreateTableStmt
+	// alterTableList is the ALTER TABLE statement list for the table.
+	alterTableList []*ast.AlterTableStmt
 }
 
 // Enter implements the ast.Visitor interface.
@@ -72,9 +76,10 @@ func (checker *statementMergeAlterTableChecker) Enter(in ast.Node) (ast.Node, bo
 	switch node := in.(type) {
 	case *ast.CreateTableStmt:
 		data := tableStatement{
-			name:     node.Table.Name.O,
-			count:    1,
-			lastLine: checker.line,
+			name:        node.Table.Name.O,
+			count:       1,
+			lastLine:    checker.line,
+			createTable: node,
 		}
 		checker.tableMap[node.Table.Name.O] = data
 	case *ast.AlterTableStmt:
@@ -87,6 +92,7 @@ func (checker *statementMergeAlterTableChecker) Enter(in ast.Node) (ast.Node, bo
 		}
 		data.count++
 		data.lastLine = checker.line
+		data.alterTableList = append(data.alterTableList, node)
 		checker.tableMap[node.Table.Name.O] = data
 	}
 
@@ -110,11 +116,12 @@ func (checker *statementMergeAlterTableChecker) generateAdvice() []advisor.Advic
 	for _, table := range tableList {
 		if table.count > 1 {
 			checker.adviceList = append(checker.adviceList, advisor.Advice{
-				Status:  checker.level,
-				Code:    advisor.StatementRedundantAlterTable,
-				Title:   checker.title,
-				Content: fmt.Sprintf("There are %d statements to modify table `%s`", table.count, table.name),
-				Line:    table.lastLine,
+				Status:     checker.level,
+				Code:       advisor.StatementRedundantAlterTable,
+				Title:      checker.title,
+				Content:    fmt.Sprintf("There are %d statements to modify table `%s`", table.count, table.name),
+				Line:       table.lastLine,
+				Suggestion: table.suggestMergedStatement(),
 			})
 		}
 	}
@@ -129,3 +136,67 @@ func (checker *statementMergeAlterTableChecker) generateAdvice() []advisor.Advic
 	}
 	return checker.adviceList
 }
+
+// suggestMergedStatement returns the statements merging the ALTER TABLE statements for the table.
+// If the table is created in the statements and all the ALTER TABLE statements only add columns or constraints,
+// we merge them into the CREATE TABLE statement. Otherwise, we merge all the ALTER TABLE statements into one.
+func (t tableStatement) suggestMergedStatement() string {
+	if t.createTable != nil && canMergeIntoCreateTable(t.alterTableList) {
+		originCols, originConstraints := t.createTable.Cols, t.createTable.Constraints
+		defer func() {
+			t.createTable.Cols, t.createTable.Constraints = originCols, originConstraints
+		}()
+		cols := append([]*ast.ColumnDef{}, originCols...)
+		constraints := append([]*ast.Constraint{}, originConstraints...)
+		for _, alterTable := range t.alterTableList {
+			for _, spec := range alterTable.Specs {
+				switch spec.Tp {
+				case ast.AlterTableAddColumns:
+					cols = append(cols, spec.NewColumns...)
+				case ast.AlterTableAddConstraint:
+					constraints = append(constraints, spec.Constraint)
+				}
+			}
+		}
+		t.createTable.Cols, t.createTable.Constraints = cols, constraints
+		return restoreSuggestion(t.createTable)
+	}
+
+	// There is nothing to merge for the CREATE TABLE statement with only one ALTER TABLE statement.
+	if len(t.alterTableList) < 2 {
+		return ""
+	}
+	alterTable := &ast.AlterTableStmt{
+		Table: t.alterTableList[0].Table,
+	}
+	for _, node := range t.alterTableList {
+		alterTable.Specs = append(alterTable.Specs, node.Specs...)
+	}
+	suggestion := restoreSuggestion(alterTable)
+	if suggestion == "" || t.createTable == nil {
+		return suggestion
+	}
+	createTable := restoreSuggestion(t.createTable)
+	if createTable == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s\n%s", createTable, suggestion)
+}
+
+func canMergeIntoCreateTable(alterTableList []*ast.AlterTableStmt) bool {
+	for _, alterTable := range alterTableList {
+		for _, spec := range alterTable.Specs {
+			switch spec.Tp {
+			case ast.AlterTableAddColumns:
+				// The column position cannot be specified in the CREATE TABLE statement.
+				if spec.Position != nil && spec.Position.Tp != ast.ColumnPositionNone {
+					return false
+				}
+			case ast.AlterTableAddConstraint:
+			default:
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -28,11 +28,12 @@ func TestStatementMergeAlterTable(t *testing.T) {
 				`,
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.StatementRedundantAlterTable,
-					Title:   "statement.merge-alter-table",
-					Content: "There are 2 statements to modify table `tech_book`",
-					Line:    3,
+					Status:     advisor.Warn,
+					Code:       advisor.StatementRedundantAlterTable,
+					Title:      "statement.merge-alter-table",
+					Content:    "There are 2 statements to modify table `tech_book`",
+					Line:       3,
+					Suggestion: "ALTER TABLE `tech_book` ADD COLUMN `a` INT, ADD COLUMN `b` INT;",
 				},
 			},
 		},
@@ -45,18 +46,20 @@ func TestStatementMergeAlterTable(t *testing.T) {
 				`,
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.StatementRedundantAlterTable,
-					Title:   "statement.merge-alter-table",
-					Content: "There are 2 statements to modify table `t`",
-					Line:    4,
+					Status:     advisor.Warn,
+					Code:       advisor.StatementRedundantAlterTable,
+					Title:      "statement.merge-alter-table",
+					Content:    "There are 2 statements to modify table `t`",
+					Line:       4,
+					Suggestion: "CREATE TABLE `t` (`a` INT,`b` INT);",
 				},
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.StatementRedundantAlterTable,
-					Title:   "statement.merge-alter-table",
-					Content: "There are 2 statements to modify table `tech_book`",
-					Line:    5,
+					Status:     advisor.Warn,
+					Code:       advisor.StatementRedundantAlterTable,
+					Title:      "statement.merge-alter-table",
+					Content:    "There are 2 statements to modify table `tech_book`",
+					Line:       5,
+					Suggestion: "ALTER TABLE `tech_book` ADD COLUMN `a` INT, ADD COLUMN `b` INT;",
 				},
 			},
 		},
@@ -69,18 +72,38 @@ func TestStatementMergeAlterTable(t *testing.T) {
 				`,
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.StatementRedundantAlterTable,
-					Title:   "statement.merge-alter-table",
-					Content: "There are 2 statements to modify table `tech_book`",
-					Line:    4,
+					Status:     advisor.Warn,
+					Code:       advisor.StatementRedundantAlterTable,
+					Title:      "statement.merge-alter-table",
+					Content:    "There are 2 statements to modify table `tech_book`",
+					Line:       4,
+					Suggestion: "ALTER TABLE `tech_book` ADD COLUMN `a` INT, ADD COLUMN `b` INT;",
 				},
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.StatementRedundantAlterTable,
-					Title:   "statement.merge-alter-table",
-					Content: "There are 2 statements to modify table `t`",
-					Line:    5,
+					Status:     advisor.Warn,
+					Code:       advisor.StatementRedundantAlterTable,
+					Title:      "statement.merge-alter-table",
+					Content:    "There are 2 statements to modify table `t`",
+					Line:       5,
+					Suggestion: "CREATE TABLE `t` (`a` INT,`b` INT);",
+				},
+			},
+		},
+		{
+			Statement: `
+				CREATE TABLE t(a int);
+				ALTER TABLE t ADD COLUMN b int;
+				ALTER TABLE t ADD INDEX idx_b(b);
+				ALTER TABLE t DROP COLUMN a;
+				`,
+			Want: []advisor.Advice{
+				{
+					Status:     advisor.Warn,
+					Code:       advisor.StatementRedundantAlterTable,
+					Title:      "statement.merge-alter-table",
+					Content:    "There are 4 statements to modify table `t`",
+					Line:       5,
+					Suggestion: "CREATE TABLE `t` (`a` INT);\nALTER TABLE `t` ADD COLUMN `b` INT, ADD INDEX `idx_b`(`b`), DROP COLUMN `a`;",
 				},
 			},
 		},
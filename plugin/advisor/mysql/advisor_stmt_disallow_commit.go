@@ -55,7 +55,7 @@ func (*StatementDisallowCommitAdvisor) Check(ctx advisor.Context, statement stri
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmtList, checker.adviceList), nil
 }
 
 type statementDisallowCommitChecker struct {
@@ -55,7 +55,7 @@ func (*DisallowLimitAdvisor) Check(ctx advisor.Context, statement string) ([]adv
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmtList, checker.adviceList), nil
 }
 
 type disallowLimitChecker struct {
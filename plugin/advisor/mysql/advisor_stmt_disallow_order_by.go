@@ -55,7 +55,7 @@ func (*DisallowOrderByAdvisor) Check(ctx advisor.Context, statement string) ([]a
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmtList, checker.adviceList), nil
 }
 
 type disallowOrderByChecker struct {
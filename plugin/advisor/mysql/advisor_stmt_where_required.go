@@ -52,7 +52,7 @@ func (*WhereRequirementAdvisor) Check(ctx advisor.Context, statement string) ([]
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, root, checker.adviceList), nil
 }
 
 type whereRequirementChecker struct {
@@ -61,7 +61,7 @@ func (*TableCommentConventionAdvisor) Check(ctx advisor.Context, statement strin
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmtList, checker.adviceList), nil
 }
 
 type tableCommentConventionChecker struct {
@@ -80,20 +80,22 @@ func (checker *tableCommentConventionChecker) Enter(in ast.Node) (ast.Node, bool
 		exist, comment := tableComment(node.Options)
 		if checker.required && !exist {
 			checker.adviceList = append(checker.adviceList, advisor.Advice{
-				Status:  checker.level,
-				Code:    advisor.NoTableComment,
-				Title:   checker.title,
-				Content: fmt.Sprintf("Table `%s` requires comments", node.Table.Name.O),
-				Line:    checker.line,
+				Status:     checker.level,
+				Code:       advisor.NoTableComment,
+				Title:      checker.title,
+				Content:    fmt.Sprintf("Table `%s` requires comments", node.Table.Name.O),
+				Line:       checker.line,
+				Suggestion: suggestTableComment(node, ""),
 			})
 		}
 		if checker.maxLength >= 0 && len(comment) > checker.maxLength {
 			checker.adviceList = append(checker.adviceList, advisor.Advice{
-				Status:  checker.level,
-				Code:    advisor.TableCommentTooLong,
-				Title:   checker.title,
-				Content: fmt.Sprintf("The length of table `%s` comment should be within %d characters", node.Table.Name.O, checker.maxLength),
-				Line:    checker.line,
+				Status:     checker.level,
+				Code:       advisor.TableCommentTooLong,
+				Title:      checker.title,
+				Content:    fmt.Sprintf("The length of table `%s` comment should be within %d characters", node.Table.Name.O, checker.maxLength),
+				Line:       checker.line,
+				Suggestion: suggestTableComment(node, truncateComment(comment, checker.maxLength)),
 			})
 		}
 	}
@@ -115,3 +117,20 @@ func tableComment(options []*ast.TableOption) (bool, string) {
 
 	return false, ""
 }
+
+// suggestTableComment returns the CREATE TABLE statement with the given table comment.
+func suggestTableComment(node *ast.CreateTableStmt, comment string) string {
+	originOptions := node.Options
+	defer func() {
+		node.Options = originOptions
+	}()
+
+	var options []*ast.TableOption
+	for _, option := range originOptions {
+		if option.Tp != ast.TableOptionComment {
+			options = append(options, option)
+		}
+	}
+	node.Options = append(options, &ast.TableOption{Tp: ast.TableOptionComment, StrValue: comment})
+	return restoreSuggestion(node)
+}
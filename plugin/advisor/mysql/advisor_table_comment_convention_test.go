@@ -28,11 +28,12 @@ func TestTableCommentConvention(t *testing.T) {
 			Statement: `CREATE TABLE t(a int)`,
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.NoTableComment,
-					Title:   "table.comment",
-					Content: "Table `t` requires comments",
-					Line:    1,
+					Status:     advisor.Warn,
+					Code:       advisor.NoTableComment,
+					Title:      "table.comment",
+					Content:    "Table `t` requires comments",
+					Line:       1,
+					Suggestion: "CREATE TABLE `t` (`a` INT) COMMENT = '';",
 				},
 			},
 		},
@@ -40,11 +41,12 @@ func TestTableCommentConvention(t *testing.T) {
 			Statement: `CREATE TABLE t(a int) COMMENT 'sdlfkjalkseblkjduafelbnlsdfkljayue'`,
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.TableCommentTooLong,
-					Title:   "table.comment",
-					Content: "The length of table `t` comment should be within 20 characters",
-					Line:    1,
+					Status:     advisor.Warn,
+					Code:       advisor.TableCommentTooLong,
+					Title:      "table.comment",
+					Content:    "The length of table `t` comment should be within 20 characters",
+					Line:       1,
+					Suggestion: "CREATE TABLE `t` (`a` INT) COMMENT = 'sdlfkjalkseblkjduafe';",
 				},
 			},
 		},
@@ -57,7 +57,7 @@ func (*TableDropNamingConventionAdvisor) Check(ctx advisor.Context, statement st
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, root, checker.adviceList), nil
 }
 
 type namingDropTableConventionChecker struct {
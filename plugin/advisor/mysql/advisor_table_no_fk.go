@@ -52,7 +52,7 @@ func (*TableNoFKAdvisor) Check(ctx advisor.Context, statement string) ([]advisor
 		})
 	}
 
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, root, checker.adviceList), nil
 }
 
 type tableNoFKChecker struct {
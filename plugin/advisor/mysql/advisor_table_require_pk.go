@@ -52,7 +52,7 @@ func (*TableRequirePKAdvisor) Check(ctx advisor.Context, statement string) ([]ad
 		(stmtNode).Accept(checker)
 	}
 
-	return checker.generateAdviceList(), nil
+	return fillAdvicePosition(statement, root, checker.generateAdviceList()), nil
 }
 
 type tableRequirePKChecker struct {
@@ -58,7 +58,7 @@ func (*UseInnoDBAdvisor) Check(ctx advisor.Context, statement string) ([]advisor
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, root, checker.adviceList), nil
 }
 
 type useInnoDBChecker struct {
@@ -5,6 +5,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
 )
 
 func TestMysql8WindowFunction(t *testing.T) {
@@ -13,3 +15,61 @@ func TestMysql8WindowFunction(t *testing.T) {
 	require.NoError(t, err)
 	assert.Empty(t, warns)
 }
+
+func TestFillAdvicePosition(t *testing.T) {
+	statement := "SELECT 1;\n" +
+		"CREATE TABLE book(\n" +
+		"  id int,\n" +
+		"  name varchar(255) NOT NULL\n" +
+		");\n" +
+		"DELETE FROM book;"
+	root, errAdvice := parseStatement(statement, "", "")
+	require.Nil(t, errAdvice)
+
+	adviceList := fillAdvicePosition(statement, root, []advisor.Advice{
+		// The column level advice.
+		{Line: 3},
+		// The statement level advice.
+		{Line: 6},
+	})
+	require.Equal(t, &advisor.Position{Line: 3, Column: 3}, adviceList[0].StartPosition)
+	require.Equal(t, &advisor.Position{Line: 3, Column: 9}, adviceList[0].EndPosition)
+	require.Equal(t, "CREATE TABLE book(\n  id int,\n  name varchar(255) NOT NULL\n);", adviceList[0].Excerpt)
+	require.Equal(t, &advisor.Position{Line: 6, Column: 1}, adviceList[1].StartPosition)
+	require.Equal(t, &advisor.Position{Line: 6, Column: 18}, adviceList[1].EndPosition)
+	require.Equal(t, "DELETE FROM book;", adviceList[1].Excerpt)
+}
+
+func TestTokenize(t *testing.T) {
+	text := "CREATE TABLE `my``book` ( -- comment\n" +
+		"  id int /* comment */ DEFAULT 'it''s',\n" +
+		"  # comment\n" +
+		"  名字 varchar(255));"
+	var tokenTextList []string
+	for _, token := range tokenize(text) {
+		tokenTextList = append(tokenTextList, text[token.Start:token.End])
+	}
+	require.Equal(t, []string{
+		"CREATE", "TABLE", "`my``book`", "(",
+		"id", "int", "DEFAULT", "'it''s'", ",",
+		"名字", "varchar", "(", "255", ")", ")", ";",
+	}, tokenTextList)
+}
+
+func TestFillAdvicePositionToken(t *testing.T) {
+	statement := "CREATE TABLE book(\n" +
+		"  id int,\n" +
+		"  `userName` varchar(255) NOT NULL\n" +
+		");"
+	root, errAdvice := parseStatement(statement, "", "")
+	require.Nil(t, errAdvice)
+
+	adviceList := fillAdvicePosition(statement, root, []advisor.Advice{
+		{Content: "`book`.`userName` mismatches column naming convention", Line: 3},
+		{Content: "Table `book` requires PRIMARY KEY", Line: 4},
+	})
+	require.Equal(t, &advisor.Position{Line: 3, Column: 3}, adviceList[0].StartPosition)
+	require.Equal(t, &advisor.Position{Line: 3, Column: 13}, adviceList[0].EndPosition)
+	require.Equal(t, &advisor.Position{Line: 1, Column: 14}, adviceList[1].StartPosition)
+	require.Equal(t, &advisor.Position{Line: 1, Column: 18}, adviceList[1].EndPosition)
+}
@@ -4,11 +4,97 @@ package mysql
 import (
 	"sort"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/format"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
 )
 
+// fillAdvicePosition fills the positions of the advice list by the text, the line and the tokens of the statement nodes.
+// The line of the statement node is set to its last line by parseStatement.
+func fillAdvicePosition(statement string, root []ast.StmtNode, adviceList []advisor.Advice) []advisor.Advice {
+	var nodeList []advisor.StatementNode
+	for _, node := range root {
+		nodeList = append(nodeList, advisor.StatementNode{
+			Text:      node.Text(),
+			LastLine:  node.OriginTextPosition(),
+			TokenList: tokenize(node.Text()),
+		})
+	}
+	return advisor.FillAdvicePosition(statement, nodeList, adviceList)
+}
+
+// tokenize returns the tokens of the MySQL statement text, the blank characters and the comments are skipped.
+// The quoted identifiers and strings are a single token including the quotes.
+// The TiDB scanner doesn't expose the token offsets, so we follow its lexical rules here.
+func tokenize(text string) []advisor.Token {
+	var tokenList []advisor.Token
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r == '#' || strings.HasPrefix(text[i:], "-- ") || strings.HasPrefix(text[i:], "--\t") || strings.HasPrefix(text[i:], "--\n"):
+			end := strings.IndexByte(text[i:], '\n')
+			if end < 0 {
+				return tokenList
+			}
+			i += end + 1
+		case strings.HasPrefix(text[i:], "/*"):
+			end := strings.Index(text[i+2:], "*/")
+			if end < 0 {
+				return tokenList
+			}
+			i += end + 4
+		case r == '`' || r == '\'' || r == '"':
+			end := scanQuoted(text, i)
+			tokenList = append(tokenList, advisor.Token{Start: i, End: end})
+			i = end
+		case isIdentifierRune(r):
+			end := i + size
+			for end < len(text) {
+				next, nextSize := utf8.DecodeRuneInString(text[end:])
+				if !isIdentifierRune(next) {
+					break
+				}
+				end += nextSize
+			}
+			tokenList = append(tokenList, advisor.Token{Start: i, End: end})
+			i = end
+		default:
+			tokenList = append(tokenList, advisor.Token{Start: i, End: i + size})
+			i += size
+		}
+	}
+	return tokenList
+}
+
+// scanQuoted returns the end offset of the quoted identifier or string starting at the start offset.
+// The quote is escaped by doubling it, and the backslash escapes the next character in the strings.
+func scanQuoted(text string, start int) int {
+	quote := text[start]
+	for i := start + 1; i < len(text); i++ {
+		switch {
+		case text[i] == '\\' && quote != '`':
+			i++
+		case text[i] == quote:
+			if i+1 < len(text) && text[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(text)
+}
+
+func isIdentifierRune(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
 type columnSet map[string]bool
 
 func newColumnSet(columns []string) columnSet {
@@ -51,3 +137,28 @@ func restoreNode(node ast.Node, flag format.RestoreFlags) (string, error) {
 	}
 	return buffer.String(), nil
 }
+
+// restoreSuggestion restores the statement node as the suggestion for the advice.
+// It returns the empty string if we fail to restore the node because the suggestion is optional.
+func restoreSuggestion(node ast.StmtNode) string {
+	text, err := restoreNode(node, format.DefaultRestoreFlags|format.RestoreStringWithoutCharset)
+	if err != nil {
+		return ""
+	}
+	return text + ";"
+}
+
+// truncateComment truncates the comment within the max length in bytes without breaking the characters.
+func truncateComment(comment string, maxLength int) string {
+	if len(comment) <= maxLength {
+		return comment
+	}
+	end := 0
+	for i := range comment {
+		if i > maxLength {
+			break
+		}
+		end = i
+	}
+	return comment[:end]
+}
@@ -46,7 +46,7 @@ func (*ColumnNoNullAdvisor) Check(ctx advisor.Context, statement string) ([]advi
 		ast.Walk(checker, stmt)
 	}
 
-	return checker.generateAdviceList(), nil
+	return fillAdvicePosition(statement, stmts, checker.generateAdviceList()), nil
 }
 
 type columnNoNullChecker struct {
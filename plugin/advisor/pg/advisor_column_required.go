@@ -63,7 +63,7 @@ func (*ColumnRequirementAdvisor) Check(ctx advisor.Context, statement string) ([
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmts, checker.adviceList), nil
 }
 
 type columnRequirementChecker struct {
@@ -56,7 +56,7 @@ func (*ColumnTypeDisallowListAdvisor) Check(ctx advisor.Context, statement strin
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmtList, checker.adviceList), nil
 }
 
 type columnTypeDisallowListChecker struct {
@@ -56,7 +56,7 @@ func (*CommentConventionAdvisor) Check(ctx advisor.Context, statement string) ([
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmtList, checker.adviceList), nil
 }
 
 type commentConventionChecker struct {
@@ -61,7 +61,7 @@ func (*EncodingAllowlistAdvisor) Check(ctx advisor.Context, statement string) ([
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmtList, checker.adviceList), nil
 }
 
 type encodingAllowlistChecker struct {
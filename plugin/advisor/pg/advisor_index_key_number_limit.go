@@ -55,7 +55,7 @@ func (*IndexKeyNumberLimitAdvisor) Check(ctx advisor.Context, statement string)
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmts, checker.adviceList), nil
 }
 
 type indexKeyNumberLimitChecker struct {
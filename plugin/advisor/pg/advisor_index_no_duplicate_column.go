@@ -47,7 +47,7 @@ func (*IndexNoDuplicateColumnAdvisor) Check(ctx advisor.Context, statement strin
 		})
 	}
 
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmtList, checker.adviceList), nil
 }
 
 type indexNoDuplicateColumnChecker struct {
@@ -58,7 +58,7 @@ func (*InsertRowLimitAdvisor) Check(ctx advisor.Context, statement string) ([]ad
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmts, checker.adviceList), nil
 }
 
 type insertRowLimitChecker struct {
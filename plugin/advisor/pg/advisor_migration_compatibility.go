@@ -50,7 +50,7 @@ func (*CompatibilityAdvisor) Check(ctx advisor.Context, statement string) ([]adv
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmts, checker.adviceList), nil
 }
 
 type compatibilityChecker struct {
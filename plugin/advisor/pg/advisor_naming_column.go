@@ -46,6 +46,7 @@ func (*NamingColumnConventionAdvisor) Check(ctx advisor.Context, statement strin
 	}
 
 	for _, stmt := range stmts {
+		checker.statement = stmt
 		ast.Walk(checker, stmt)
 	}
 
@@ -57,7 +58,7 @@ func (*NamingColumnConventionAdvisor) Check(ctx advisor.Context, statement strin
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmts, checker.adviceList), nil
 }
 
 type namingColumnConventionChecker struct {
@@ -66,6 +67,7 @@ type namingColumnConventionChecker struct {
 	title      string
 	format     *regexp.Regexp
 	maxLength  int
+	statement  ast.Node
 }
 
 // Visit implements the ast.Visitor interface.
@@ -73,6 +75,8 @@ func (checker *namingColumnConventionChecker) Visit(node ast.Node) ast.Visitor {
 	type columnData struct {
 		name string
 		line int
+		// ref is the pointer to the column name in the statement to rename it in the suggestion.
+		ref *string
 	}
 	var columnList []columnData
 	var tableName string
@@ -85,6 +89,7 @@ func (checker *namingColumnConventionChecker) Visit(node ast.Node) ast.Visitor {
 			columnList = append(columnList, columnData{
 				name: col.ColumnName,
 				line: col.LastLine(),
+				ref:  &col.ColumnName,
 			})
 		}
 	// ALTER TABLE ADD COLUMN
@@ -94,6 +99,7 @@ func (checker *namingColumnConventionChecker) Visit(node ast.Node) ast.Visitor {
 			columnList = append(columnList, columnData{
 				name: col.ColumnName,
 				line: n.LastLine(),
+				ref:  &col.ColumnName,
 			})
 		}
 	// ALTER TABLE RENAME COLUMN
@@ -102,30 +108,47 @@ func (checker *namingColumnConventionChecker) Visit(node ast.Node) ast.Visitor {
 		columnList = append(columnList, columnData{
 			name: n.NewName,
 			line: n.LastLine(),
+			ref:  &n.NewName,
 		})
 	}
 
 	for _, column := range columnList {
 		if !checker.format.MatchString(column.name) {
 			checker.adviceList = append(checker.adviceList, advisor.Advice{
-				Status:  checker.level,
-				Code:    advisor.NamingColumnConventionMismatch,
-				Title:   checker.title,
-				Content: fmt.Sprintf("\"%s\".\"%s\" mismatches column naming convention, naming format should be %q", tableName, column.name, checker.format),
-				Line:    column.line,
+				Status:     checker.level,
+				Code:       advisor.NamingColumnConventionMismatch,
+				Title:      checker.title,
+				Content:    fmt.Sprintf("\"%s\".\"%s\" mismatches column naming convention, naming format should be %q", tableName, column.name, checker.format),
+				Line:       column.line,
+				Suggestion: checker.suggestName(column.ref),
 			})
 		}
 
 		if checker.maxLength > 0 && len(column.name) > checker.maxLength {
 			checker.adviceList = append(checker.adviceList, advisor.Advice{
-				Status:  checker.level,
-				Code:    advisor.NamingColumnConventionMismatch,
-				Title:   checker.title,
-				Content: fmt.Sprintf("\"%s\".\"%s\" mismatches column naming convention, its length should be within %d characters", tableName, column.name, checker.maxLength),
-				Line:    column.line,
+				Status:     checker.level,
+				Code:       advisor.NamingColumnConventionMismatch,
+				Title:      checker.title,
+				Content:    fmt.Sprintf("\"%s\".\"%s\" mismatches column naming convention, its length should be within %d characters", tableName, column.name, checker.maxLength),
+				Line:       column.line,
+				Suggestion: checker.suggestName(column.ref),
 			})
 		}
 	}
 
 	return checker
 }
+
+// suggestName returns the statement with the column renamed to match the naming convention.
+func (checker *namingColumnConventionChecker) suggestName(name *string) string {
+	suggestion, ok := advisor.SuggestName(*name, checker.format, checker.maxLength)
+	if !ok {
+		return ""
+	}
+	origin := *name
+	defer func() {
+		*name = origin
+	}()
+	*name = suggestion
+	return deparseSuggestion(checker.statement)
+}
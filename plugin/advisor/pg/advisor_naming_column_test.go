@@ -18,11 +18,12 @@ func TestNamingColumnConvention(t *testing.T) {
 			Statement: "CREATE TABLE book(id int, \"creatorId\" int)",
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.NamingColumnConventionMismatch,
-					Title:   "naming.column",
-					Content: "\"book\".\"creatorId\" mismatches column naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
-					Line:    1,
+					Status:     advisor.Warn,
+					Code:       advisor.NamingColumnConventionMismatch,
+					Title:      "naming.column",
+					Content:    "\"book\".\"creatorId\" mismatches column naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
+					Line:       1,
+					Suggestion: "CREATE TABLE \"book\" (\n    \"id\" integer,\n    \"creator_id\" integer\n);",
 				},
 			},
 		},
@@ -30,11 +31,12 @@ func TestNamingColumnConvention(t *testing.T) {
 			Statement: fmt.Sprintf("CREATE TABLE book(id int, %s int)", invalidColumnName),
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.NamingColumnConventionMismatch,
-					Title:   "naming.column",
-					Content: fmt.Sprintf("\"book\".\"%s\" mismatches column naming convention, its length should be within 32 characters", invalidColumnName),
-					Line:    1,
+					Status:     advisor.Warn,
+					Code:       advisor.NamingColumnConventionMismatch,
+					Title:      "naming.column",
+					Content:    fmt.Sprintf("\"book\".\"%s\" mismatches column naming convention, its length should be within 32 characters", invalidColumnName),
+					Line:       1,
+					Suggestion: fmt.Sprintf("CREATE TABLE \"book\" (\n    \"id\" integer,\n    \"%s\" integer\n);", invalidColumnName[:32]),
 				},
 			},
 		},
@@ -66,11 +68,12 @@ func TestNamingColumnConvention(t *testing.T) {
 						ALTER TABLE book ADD COLUMN "creatorId" int`,
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.NamingColumnConventionMismatch,
-					Title:   "naming.column",
-					Content: "\"book\".\"creatorId\" mismatches column naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
-					Line:    2,
+					Status:     advisor.Warn,
+					Code:       advisor.NamingColumnConventionMismatch,
+					Title:      "naming.column",
+					Content:    "\"book\".\"creatorId\" mismatches column naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
+					Line:       2,
+					Suggestion: "ALTER TABLE \"book\"\n    ADD COLUMN \"creator_id\" integer;",
 				},
 			},
 		},
@@ -91,11 +94,12 @@ func TestNamingColumnConvention(t *testing.T) {
 						ALTER TABLE book RENAME COLUMN creator_id TO "creatorId"`,
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    advisor.NamingColumnConventionMismatch,
-					Title:   "naming.column",
-					Content: "\"book\".\"creatorId\" mismatches column naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
-					Line:    2,
+					Status:     advisor.Warn,
+					Code:       advisor.NamingColumnConventionMismatch,
+					Title:      "naming.column",
+					Content:    "\"book\".\"creatorId\" mismatches column naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
+					Line:       2,
+					Suggestion: "ALTER TABLE \"book\"\n    RENAME COLUMN \"creator_id\" TO \"creator_id\";",
 				},
 			},
 		},
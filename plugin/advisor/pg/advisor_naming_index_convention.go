@@ -61,7 +61,7 @@ func (*NamingIndexConventionAdvisor) Check(ctx advisor.Context, statement string
 		})
 	}
 
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmts, checker.adviceList), nil
 }
 
 type namingIndexConventionChecker struct {
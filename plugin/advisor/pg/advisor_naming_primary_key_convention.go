@@ -62,7 +62,7 @@ func (*NamingPKConventionAdvisor) Check(ctx advisor.Context, statement string) (
 		})
 	}
 
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmts, checker.adviceList), nil
 }
 
 type namingPKConventionChecker struct {
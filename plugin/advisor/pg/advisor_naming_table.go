@@ -46,6 +46,7 @@ func (*NamingTableConventionAdvisor) Check(ctx advisor.Context, statement string
 	}
 
 	for _, stmt := range stmts {
+		checker.statement = stmt
 		ast.Walk(checker, stmt)
 	}
 
@@ -57,7 +58,7 @@ func (*NamingTableConventionAdvisor) Check(ctx advisor.Context, statement string
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmts, checker.adviceList), nil
 }
 
 type namingTableConventionChecker struct {
@@ -66,41 +67,60 @@ type namingTableConventionChecker struct {
 	title      string
 	format     *regexp.Regexp
 	maxLength  int
+	statement  ast.Node
 }
 
 // Visit implements the ast.Visitor interface.
 func (checker *namingTableConventionChecker) Visit(node ast.Node) ast.Visitor {
-	var tableNames []string
+	// We collect the pointers to the table names to rename them in the suggestion.
+	var tableNames []*string
 
 	switch n := node.(type) {
 	// CREATE TABLE
 	case *ast.CreateTableStmt:
-		tableNames = append(tableNames, n.Name.Name)
+		tableNames = append(tableNames, &n.Name.Name)
 	// ALTER TABLE RENAME TABLE
 	case *ast.RenameTableStmt:
-		tableNames = append(tableNames, n.NewName)
+		tableNames = append(tableNames, &n.NewName)
 	}
 
-	for _, tableName := range tableNames {
+	for _, name := range tableNames {
+		tableName := *name
 		if !checker.format.MatchString(tableName) {
 			checker.adviceList = append(checker.adviceList, advisor.Advice{
-				Status:  checker.level,
-				Code:    advisor.NamingTableConventionMismatch,
-				Title:   checker.title,
-				Content: fmt.Sprintf(`"%s" mismatches table naming convention, naming format should be %q`, tableName, checker.format),
-				Line:    node.LastLine(),
+				Status:     checker.level,
+				Code:       advisor.NamingTableConventionMismatch,
+				Title:      checker.title,
+				Content:    fmt.Sprintf(`"%s" mismatches table naming convention, naming format should be %q`, tableName, checker.format),
+				Line:       node.LastLine(),
+				Suggestion: checker.suggestName(name),
 			})
 		}
 		if checker.maxLength > 0 && len(tableName) > checker.maxLength {
 			checker.adviceList = append(checker.adviceList, advisor.Advice{
-				Status:  checker.level,
-				Code:    advisor.NamingTableConventionMismatch,
-				Title:   checker.title,
-				Content: fmt.Sprintf("\"%s\" mismatches table naming convention, its length should be within %d characters", tableName, checker.maxLength),
-				Line:    node.LastLine(),
+				Status:     checker.level,
+				Code:       advisor.NamingTableConventionMismatch,
+				Title:      checker.title,
+				Content:    fmt.Sprintf("\"%s\" mismatches table naming convention, its length should be within %d characters", tableName, checker.maxLength),
+				Line:       node.LastLine(),
+				Suggestion: checker.suggestName(name),
 			})
 		}
 	}
 
 	return checker
 }
+
+// suggestName returns the statement with the table renamed to match the naming convention.
+func (checker *namingTableConventionChecker) suggestName(name *string) string {
+	suggestion, ok := advisor.SuggestName(*name, checker.format, checker.maxLength)
+	if !ok {
+		return ""
+	}
+	origin := *name
+	defer func() {
+		*name = origin
+	}()
+	*name = suggestion
+	return deparseSuggestion(checker.statement)
+}
@@ -18,11 +18,12 @@ func TestPostgreSQLNamingTableConvention(t *testing.T) {
 			Statement: "CREATE TABLE \"techBook\"(id int, name varchar(255))",
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Error,
-					Code:    advisor.NamingTableConventionMismatch,
-					Title:   "naming.table",
-					Content: "\"techBook\" mismatches table naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
-					Line:    1,
+					Status:     advisor.Error,
+					Code:       advisor.NamingTableConventionMismatch,
+					Title:      "naming.table",
+					Content:    "\"techBook\" mismatches table naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
+					Line:       1,
+					Suggestion: "CREATE TABLE \"tech_book\" (\n    \"id\" integer,\n    \"name\" character varying(255)\n);",
 				},
 			},
 		},
@@ -30,11 +31,12 @@ func TestPostgreSQLNamingTableConvention(t *testing.T) {
 			Statement: fmt.Sprintf("CREATE TABLE \"%s\"(id int, name varchar(255))", invalidTableName),
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Error,
-					Code:    advisor.NamingTableConventionMismatch,
-					Title:   "naming.table",
-					Content: fmt.Sprintf("\"%s\" mismatches table naming convention, its length should be within 32 characters", invalidTableName),
-					Line:    1,
+					Status:     advisor.Error,
+					Code:       advisor.NamingTableConventionMismatch,
+					Title:      "naming.table",
+					Content:    fmt.Sprintf("\"%s\" mismatches table naming convention, its length should be within 32 characters", invalidTableName),
+					Line:       1,
+					Suggestion: fmt.Sprintf("CREATE TABLE \"%s\" (\n    \"id\" integer,\n    \"name\" character varying(255)\n);", invalidTableName[:32]),
 				},
 			},
 		},
@@ -42,11 +44,12 @@ func TestPostgreSQLNamingTableConvention(t *testing.T) {
 			Statement: "CREATE TABLE _techBook(id int, name varchar(255))",
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Error,
-					Code:    advisor.NamingTableConventionMismatch,
-					Title:   "naming.table",
-					Content: "\"_techbook\" mismatches table naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
-					Line:    1,
+					Status:     advisor.Error,
+					Code:       advisor.NamingTableConventionMismatch,
+					Title:      "naming.table",
+					Content:    "\"_techbook\" mismatches table naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
+					Line:       1,
+					Suggestion: "CREATE TABLE \"techbook\" (\n    \"id\" integer,\n    \"name\" character varying(255)\n);",
 				},
 			},
 		},
@@ -77,11 +80,12 @@ func TestPostgreSQLNamingTableConvention(t *testing.T) {
 			Statement: "ALTER TABLE tech_book RENAME TO \"TechBook\"",
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Error,
-					Code:    advisor.NamingTableConventionMismatch,
-					Title:   "naming.table",
-					Content: "\"TechBook\" mismatches table naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
-					Line:    1,
+					Status:     advisor.Error,
+					Code:       advisor.NamingTableConventionMismatch,
+					Title:      "naming.table",
+					Content:    "\"TechBook\" mismatches table naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
+					Line:       1,
+					Suggestion: "ALTER TABLE \"tech_book\"\n    RENAME TO \"tech_book\";",
 				},
 			},
 		},
@@ -101,18 +105,20 @@ func TestPostgreSQLNamingTableConvention(t *testing.T) {
 						ALTER TABLE tech_book RENAME TO "TechBook";`,
 			Want: []advisor.Advice{
 				{
-					Status:  advisor.Error,
-					Code:    advisor.NamingTableConventionMismatch,
-					Title:   "naming.table",
-					Content: "\"_techbook\" mismatches table naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
-					Line:    1,
+					Status:     advisor.Error,
+					Code:       advisor.NamingTableConventionMismatch,
+					Title:      "naming.table",
+					Content:    "\"_techbook\" mismatches table naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
+					Line:       1,
+					Suggestion: "CREATE TABLE \"techbook\" (\n    \"id\" integer,\n    \"name\" character varying(255)\n);",
 				},
 				{
-					Status:  advisor.Error,
-					Code:    advisor.NamingTableConventionMismatch,
-					Title:   "naming.table",
-					Content: "\"TechBook\" mismatches table naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
-					Line:    2,
+					Status:     advisor.Error,
+					Code:       advisor.NamingTableConventionMismatch,
+					Title:      "naming.table",
+					Content:    "\"TechBook\" mismatches table naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\"",
+					Line:       2,
+					Suggestion: "ALTER TABLE \"tech_book\"\n    RENAME TO \"tech_book\";",
 				},
 			},
 		},
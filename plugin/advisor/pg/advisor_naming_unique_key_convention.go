@@ -62,7 +62,7 @@ func (*NamingUKConventionAdvisor) Check(ctx advisor.Context, statement string) (
 		})
 	}
 
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, root, checker.adviceList), nil
 }
 
 type namingUKConventionChecker struct {
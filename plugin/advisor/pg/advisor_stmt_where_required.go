@@ -51,7 +51,7 @@ func (*WhereRequirementAdvisor) Check(ctx advisor.Context, statement string) ([]
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmts, checker.adviceList), nil
 }
 
 type whereRequirementChecker struct {
@@ -51,7 +51,7 @@ func (*TableNoFKAdvisor) Check(ctx advisor.Context, statement string) ([]advisor
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmts, checker.adviceList), nil
 }
 
 type tableNoFKChecker struct {
@@ -53,7 +53,7 @@ func (*TableRequirePKAdvisor) Check(ctx advisor.Context, statement string) ([]ad
 			Content: "",
 		})
 	}
-	return checker.adviceList, nil
+	return fillAdvicePosition(statement, stmts, checker.adviceList), nil
 }
 
 type tableRequirePKChecker struct {
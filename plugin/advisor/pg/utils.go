@@ -3,6 +3,12 @@ package pg
 
 import (
 	"fmt"
+
+	pgquery "github.com/pganalyze/pg_query_go/v2"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/parser"
+	"github.com/bytebase/bytebase/plugin/parser/ast"
 )
 
 const (
@@ -32,3 +38,40 @@ func normalizeSchemaName(name string) string {
 	}
 	return "public"
 }
+
+// fillAdvicePosition fills the positions of the advice list by the text, the last line and the tokens of the statement nodes.
+func fillAdvicePosition(statement string, nodeList []ast.Node, adviceList []advisor.Advice) []advisor.Advice {
+	var statementNodeList []advisor.StatementNode
+	for _, node := range nodeList {
+		statementNodeList = append(statementNodeList, advisor.StatementNode{
+			Text:      node.Text(),
+			LastLine:  node.LastLine(),
+			TokenList: tokenize(node.Text()),
+		})
+	}
+	return advisor.FillAdvicePosition(statement, statementNodeList, adviceList)
+}
+
+// tokenize returns the tokens of the statement text lexed by the PostgreSQL scanner.
+// It returns nil if we fail to scan the text because the tokens are only used to narrow the advice range.
+func tokenize(text string) []advisor.Token {
+	res, err := pgquery.Scan(text)
+	if err != nil {
+		return nil
+	}
+	var tokenList []advisor.Token
+	for _, token := range res.Tokens {
+		tokenList = append(tokenList, advisor.Token{Start: int(token.Start), End: int(token.End)})
+	}
+	return tokenList
+}
+
+// deparseSuggestion deparses the statement node as the suggestion for the advice.
+// It returns the empty string if we fail to deparse the node because the suggestion is optional.
+func deparseSuggestion(node ast.Node) string {
+	text, err := parser.Deparse(parser.Postgres, parser.DeparseContext{}, node)
+	if err != nil {
+		return ""
+	}
+	return text
+}
@@ -0,0 +1,28 @@
+package pg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/parser"
+)
+
+func TestFillAdvicePositionToken(t *testing.T) {
+	statement := "CREATE TABLE book(\n" +
+		"  id int,\n" +
+		"  \"userName\" varchar(255) NOT NULL -- comment\n" +
+		");"
+	nodeList, err := parser.Parse(parser.Postgres, parser.ParseContext{}, statement)
+	require.NoError(t, err)
+
+	adviceList := fillAdvicePosition(statement, nodeList, []advisor.Advice{
+		{Content: "\"book\".\"userName\" mismatches column naming convention", Line: 3},
+		{Content: "Table \"book\" requires PRIMARY KEY", Line: 4},
+	})
+	require.Equal(t, &advisor.Position{Line: 3, Column: 3}, adviceList[0].StartPosition)
+	require.Equal(t, &advisor.Position{Line: 3, Column: 13}, adviceList[0].EndPosition)
+	require.Equal(t, &advisor.Position{Line: 1, Column: 14}, adviceList[1].StartPosition)
+	require.Equal(t, &advisor.Position{Line: 1, Column: 18}, adviceList[1].EndPosition)
+}
@@ -0,0 +1,219 @@
+package advisor
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// maxExcerptLength is the maximum rune count of the statement excerpt in the advice.
+	maxExcerptLength = 512
+)
+
+// Position is the position in the statements.
+// Both line and column are 1-based, and the column is counted in characters.
+type Position struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// quotedNameRegex matches the names quoted by backticks or double quotes in the advice content.
+var quotedNameRegex = regexp.MustCompile("`([^`]+)`|\"([^\"]+)\"")
+
+// Token is a token lexed from the statement text by the parser of the engine.
+// Start and End are the byte offsets in the statement text, the end is exclusive.
+type Token struct {
+	Start int
+	End   int
+}
+
+// StatementNode is the text, the last line and the tokens of a statement node parsed from the statements.
+// The text is the statement in the statements without the leading and trailing blank characters.
+type StatementNode struct {
+	Text     string
+	LastLine int
+	// TokenList is used to narrow the advice range to the offending token, it can be empty if the statement cannot be lexed.
+	TokenList []Token
+}
+
+// positionLocator converts the byte offsets to the positions in the statements.
+type positionLocator struct {
+	statements string
+	// lineStartList is the byte offset of the start of each line.
+	lineStartList []int
+}
+
+func newPositionLocator(statements string) *positionLocator {
+	lineStartList := []int{0}
+	for i := 0; i < len(statements); i++ {
+		if statements[i] == '\n' {
+			lineStartList = append(lineStartList, i+1)
+		}
+	}
+	return &positionLocator{
+		statements:    statements,
+		lineStartList: lineStartList,
+	}
+}
+
+// position returns the position of the byte offset.
+func (l *positionLocator) position(offset int) Position {
+	line := 0
+	for line+1 < len(l.lineStartList) && l.lineStartList[line+1] <= offset {
+		line++
+	}
+	return Position{
+		Line:   line + 1,
+		Column: utf8.RuneCountInString(l.statements[l.lineStartList[line]:offset]) + 1,
+	}
+}
+
+// lineRange returns the byte offsets of the 1-based line, the end is exclusive and doesn't contain the line break.
+func (l *positionLocator) lineRange(line int) (int, int) {
+	start := l.lineStartList[line-1]
+	end := len(l.statements)
+	if line < len(l.lineStartList) {
+		end = l.lineStartList[line] - 1
+	}
+	return start, end
+}
+
+// FillAdvicePosition fills the start and end positions and the statement excerpt for the advice list by the statement nodes.
+// The advisors report the line of the offending element, or the last line of the statement for the statement level advice.
+// For the former, the range is narrowed to the element line, otherwise the range covers the whole statement.
+// Then the range is narrowed to the token of the name quoted in the advice content if it's found in the range,
+// e.g. the column name for the column naming advice.
+// The nodes are located in order of the statements, so that the same statements are located to their own ranges.
+func FillAdvicePosition(statements string, nodeList []StatementNode, adviceList []Advice) []Advice {
+	type statementRange struct {
+		// start and end are the byte offsets in the statements, the end is exclusive.
+		start     int
+		end       int
+		startLine int
+		endLine   int
+		tokenList []Token
+	}
+
+	locator := newPositionLocator(statements)
+	var rangeList []statementRange
+	cursor := 0
+	for _, node := range nodeList {
+		if node.Text == "" {
+			continue
+		}
+		index := strings.Index(statements[cursor:], node.Text)
+		if index < 0 {
+			// It's OK to return the advice list without the position.
+			return adviceList
+		}
+		start := cursor + index
+		end := start + len(node.Text)
+		cursor = end
+		rangeList = append(rangeList, statementRange{
+			start:     start,
+			end:       end,
+			startLine: node.LastLine - strings.Count(node.Text, "\n"),
+			endLine:   node.LastLine,
+			tokenList: node.TokenList,
+		})
+	}
+
+	for i := range adviceList {
+		advice := &adviceList[i]
+		if advice.Line <= 0 || advice.StartPosition != nil {
+			continue
+		}
+		for _, stmtRange := range rangeList {
+			if advice.Line < stmtRange.startLine || advice.Line > stmtRange.endLine {
+				continue
+			}
+			start, end := stmtRange.start, stmtRange.end
+			if advice.Line != stmtRange.endLine {
+				lineStart, lineEnd := locator.lineRange(advice.Line)
+				start, end = trimRange(statements, maxInt(lineStart, stmtRange.start), minInt(lineEnd, stmtRange.end))
+			}
+			if tokenStart, tokenEnd, ok := findQuotedNameToken(statements, stmtRange.start, stmtRange.tokenList, start, end, advice.Content); ok {
+				start, end = tokenStart, tokenEnd
+			}
+			startPosition := locator.position(start)
+			endPosition := locator.position(end)
+			advice.StartPosition = &startPosition
+			advice.EndPosition = &endPosition
+			advice.Excerpt = excerpt(statements[stmtRange.start:stmtRange.end])
+			break
+		}
+	}
+	return adviceList
+}
+
+// findQuotedNameToken finds the token of the name quoted in the content within the range [start, end) of the statements.
+// The tokens are offset by the statement start. The later quoted names are preferred, because the content usually quotes
+// the offending element after its parent, e.g. "`book`.`id` cannot have NULL value".
+func findQuotedNameToken(statements string, statementStart int, tokenList []Token, start int, end int, content string) (int, int, bool) {
+	matchList := quotedNameRegex.FindAllStringSubmatch(content, -1)
+	for i := len(matchList) - 1; i >= 0; i-- {
+		name := matchList[i][1]
+		if name == "" {
+			name = matchList[i][2]
+		}
+		for _, token := range tokenList {
+			tokenStart, tokenEnd := statementStart+token.Start, statementStart+token.End
+			if tokenStart < start || tokenEnd > end {
+				continue
+			}
+			if strings.EqualFold(unquoteToken(statements[tokenStart:tokenEnd]), name) {
+				return tokenStart, tokenEnd, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// unquoteToken removes the backticks or double quotes around the token text.
+func unquoteToken(text string) string {
+	if len(text) >= 2 {
+		for _, quote := range []string{"`", `"`} {
+			if strings.HasPrefix(text, quote) && strings.HasSuffix(text, quote) {
+				return strings.ReplaceAll(text[1:len(text)-1], quote+quote, quote)
+			}
+		}
+	}
+	return text
+}
+
+// trimRange trims the blank characters and the trailing comma in the range.
+func trimRange(statements string, start int, end int) (int, int) {
+	text := statements[start:end]
+	trimmed := strings.TrimLeftFunc(text, unicode.IsSpace)
+	start += len(text) - len(trimmed)
+	trimmed = strings.TrimRightFunc(trimmed, func(r rune) bool {
+		return unicode.IsSpace(r) || r == ','
+	})
+	if trimmed == "" {
+		return start, start
+	}
+	return start, start + len(trimmed)
+}
+
+func excerpt(text string) string {
+	if utf8.RuneCountInString(text) <= maxExcerptLength {
+		return text
+	}
+	return string([]rune(text)[:maxExcerptLength]) + "..."
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,139 @@
+package advisor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFillAdvicePosition(t *testing.T) {
+	statements := "SELECT * FROM t;\n" +
+		"CREATE TABLE `book` (\n" +
+		"  id int,\n" +
+		"  name varchar(255)\n" +
+		");\n" +
+		"DELETE\n" +
+		"  FROM t;\n" +
+		"SELECT * FROM t;"
+	nodeList := []StatementNode{
+		{Text: "SELECT * FROM t;", LastLine: 1},
+		{Text: "CREATE TABLE `book` (\n  id int,\n  name varchar(255)\n);", LastLine: 5},
+		{Text: "DELETE\n  FROM t;", LastLine: 7},
+		{Text: "SELECT * FROM t;", LastLine: 8},
+	}
+
+	adviceList := FillAdvicePosition(statements, nodeList, []Advice{
+		{
+			Title:   "statement.select.no-select-all",
+			Content: `"SELECT * FROM t;" uses SELECT all`,
+			Line:    1,
+		},
+		{
+			Title:   "column.no-null",
+			Content: "`book`.`id` cannot have NULL value",
+			Line:    3,
+		},
+		{
+			Title:   "column.comment",
+			Content: "Column requires comments",
+			Line:    4,
+		},
+		{
+			Title:   "statement.where.require",
+			Content: "requires WHERE clause",
+			Line:    7,
+		},
+		{
+			Title:   "statement.select.no-select-all",
+			Content: `"SELECT * FROM t;" uses SELECT all`,
+			Line:    8,
+		},
+		{
+			Title: "OK",
+			Line:  0,
+		},
+	})
+
+	require.Equal(t, &Position{Line: 1, Column: 1}, adviceList[0].StartPosition)
+	require.Equal(t, &Position{Line: 1, Column: 17}, adviceList[0].EndPosition)
+	require.Equal(t, "SELECT * FROM t;", adviceList[0].Excerpt)
+
+	// The column definition line without the blank characters and the trailing comma.
+	require.Equal(t, &Position{Line: 3, Column: 3}, adviceList[1].StartPosition)
+	require.Equal(t, &Position{Line: 3, Column: 9}, adviceList[1].EndPosition)
+	require.Equal(t, "CREATE TABLE `book` (\n  id int,\n  name varchar(255)\n);", adviceList[1].Excerpt)
+
+	// The element line without the blank characters and the trailing comma.
+	require.Equal(t, &Position{Line: 4, Column: 3}, adviceList[2].StartPosition)
+	require.Equal(t, &Position{Line: 4, Column: 20}, adviceList[2].EndPosition)
+
+	// The statement level advice covers the whole multi-line statement.
+	require.Equal(t, &Position{Line: 6, Column: 1}, adviceList[3].StartPosition)
+	require.Equal(t, &Position{Line: 7, Column: 10}, adviceList[3].EndPosition)
+	require.Equal(t, "DELETE\n  FROM t;", adviceList[3].Excerpt)
+
+	// The same statement is located in order of the statement nodes.
+	require.Equal(t, &Position{Line: 8, Column: 1}, adviceList[4].StartPosition)
+	require.Equal(t, &Position{Line: 8, Column: 17}, adviceList[4].EndPosition)
+	require.Equal(t, "SELECT * FROM t;", adviceList[4].Excerpt)
+
+	require.Nil(t, adviceList[5].StartPosition)
+	require.Nil(t, adviceList[5].EndPosition)
+}
+
+func TestFillAdvicePositionToken(t *testing.T) {
+	statements := "CREATE TABLE `book` (\n" +
+		"  id int,\n" +
+		"  `Name` varchar(255)\n" +
+		");"
+	// The tokens of the statement text lexed by the parser.
+	tokenList := []Token{
+		{Start: 0, End: 6}, {Start: 7, End: 12}, {Start: 13, End: 19}, {Start: 20, End: 21},
+		{Start: 24, End: 26}, {Start: 27, End: 30}, {Start: 30, End: 31},
+		{Start: 34, End: 40}, {Start: 41, End: 48}, {Start: 48, End: 49}, {Start: 49, End: 52}, {Start: 52, End: 53},
+		{Start: 54, End: 55}, {Start: 55, End: 56},
+	}
+	nodeList := []StatementNode{
+		{Text: statements, LastLine: 4, TokenList: tokenList},
+	}
+
+	adviceList := FillAdvicePosition(statements, nodeList, []Advice{
+		{
+			Title:   "column.no-null",
+			Content: "`book`.`id` cannot have NULL value",
+			Line:    2,
+		},
+		{
+			Title:   "naming.column",
+			Content: "`book`.`Name` mismatches column naming convention",
+			Line:    3,
+		},
+		{
+			Title:   "table.require-pk",
+			Content: "Table `book` requires PRIMARY KEY",
+			Line:    4,
+		},
+		{
+			Title:   "column.comment",
+			Content: "Column `other` requires comments",
+			Line:    3,
+		},
+	})
+
+	// The later quoted name on the element line.
+	require.Equal(t, &Position{Line: 2, Column: 3}, adviceList[0].StartPosition)
+	require.Equal(t, &Position{Line: 2, Column: 5}, adviceList[0].EndPosition)
+
+	// The quoted identifier token including the quotes.
+	require.Equal(t, &Position{Line: 3, Column: 3}, adviceList[1].StartPosition)
+	require.Equal(t, &Position{Line: 3, Column: 9}, adviceList[1].EndPosition)
+
+	// The statement level advice is narrowed to the token in the statement.
+	require.Equal(t, &Position{Line: 1, Column: 14}, adviceList[2].StartPosition)
+	require.Equal(t, &Position{Line: 1, Column: 20}, adviceList[2].EndPosition)
+
+	// The element line is kept if the quoted name is not found.
+	require.Equal(t, &Position{Line: 3, Column: 3}, adviceList[3].StartPosition)
+	require.Equal(t, &Position{Line: 3, Column: 22}, adviceList[3].EndPosition)
+	require.Equal(t, statements, adviceList[3].Excerpt)
+}
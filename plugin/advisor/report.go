@@ -0,0 +1,281 @@
+package advisor
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// ReportFormat is the output format of the SQL review report.
+type ReportFormat string
+
+const (
+	// ReportFormatSARIF is the SARIF 2.1.0 format, which is consumed by GitHub code scanning.
+	// Docs: https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+	ReportFormatSARIF ReportFormat = "sarif"
+	// ReportFormatJUnit is the JUnit XML format, which is consumed by Jenkins, GitLab CI and most CI systems.
+	ReportFormatJUnit ReportFormat = "junit"
+
+	// ErrorCodeDocsURL is the URL for the SQL review error code docs.
+	ErrorCodeDocsURL = "https://www.bytebase.com/docs/reference/error-code/advisor"
+
+	sarifSchemaURL   = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion     = "2.1.0"
+	sarifToolName    = "Bytebase SQL Review"
+	sarifToolInfoURI = "https://www.bytebase.com/docs/sql-review/overview"
+)
+
+// ValidateReportFormat validates the report format.
+func ValidateReportFormat(format ReportFormat) error {
+	switch format {
+	case ReportFormatSARIF, ReportFormatJUnit:
+		return nil
+	}
+	return errors.Errorf("unsupported SQL review report format %q", format)
+}
+
+// GenerateReport generates the SQL review report in the format for the advice map from file path to advice list.
+func GenerateReport(format ReportFormat, adviceMap map[string][]Advice) (string, error) {
+	switch format {
+	case ReportFormatSARIF:
+		return GenerateSARIFReport(adviceMap)
+	case ReportFormatJUnit:
+		return GenerateJUnitReport(adviceMap)
+	}
+	return "", ValidateReportFormat(format)
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string            `json:"id"`
+	ShortDescription sarifMessage      `json:"shortDescription"`
+	HelpURI          string            `json:"helpUri"`
+	Properties       map[string]string `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int           `json:"startLine"`
+	StartColumn int           `json:"startColumn,omitempty"`
+	EndLine     int           `json:"endLine,omitempty"`
+	EndColumn   int           `json:"endColumn,omitempty"`
+	Snippet     *sarifMessage `json:"snippet,omitempty"`
+}
+
+// GenerateSARIFReport generates the SARIF 2.1.0 report for the advice map from file path to advice list.
+// Each advice title maps to a SARIF rule, and the rule help URI links to the error code docs.
+func GenerateSARIFReport(adviceMap map[string][]Advice) (string, error) {
+	driver := sarifDriver{
+		Name:           sarifToolName,
+		InformationURI: sarifToolInfoURI,
+		Rules:          []sarifRule{},
+	}
+	results := []sarifResult{}
+	ruleIndex := make(map[string]int)
+
+	for _, filePath := range sortedFileList(adviceMap) {
+		for _, advice := range adviceMap[filePath] {
+			if advice.Code == Ok || advice.Status == Success {
+				continue
+			}
+			index, ok := ruleIndex[advice.Title]
+			if !ok {
+				index = len(driver.Rules)
+				ruleIndex[advice.Title] = index
+				driver.Rules = append(driver.Rules, sarifRule{
+					ID:               advice.Title,
+					ShortDescription: sarifMessage{Text: advice.Title},
+					HelpURI:          fmt.Sprintf("%s#%d", ErrorCodeDocsURL, advice.Code),
+					Properties: map[string]string{
+						"code": fmt.Sprintf("%d", advice.Code),
+					},
+				})
+			}
+			level := "warning"
+			if advice.Status == Error {
+				level = "error"
+			}
+			results = append(results, sarifResult{
+				RuleID:    advice.Title,
+				RuleIndex: index,
+				Level:     level,
+				Message:   sarifMessage{Text: advice.Content},
+				Locations: []sarifLocation{
+					{
+						PhysicalLocation: sarifPhysicalLocation{
+							ArtifactLocation: sarifArtifactLocation{URI: filePath},
+							Region:           newSARIFRegion(advice),
+						},
+					},
+				},
+			})
+		}
+	}
+
+	report := sarifLog{
+		Schema:  sarifSchemaURL,
+		Version: sarifVersion,
+		Runs: []sarifRun{
+			{
+				Tool:    sarifTool{Driver: driver},
+				Results: results,
+			},
+		},
+	}
+	bytes, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
+type junitTestSuites struct {
+	XMLName    xml.Name         `xml:"testsuites"`
+	Name       string           `xml:"name,attr"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	TestSuites []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr"`
+	Line      int           `xml:"line,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Content string `xml:",chardata"`
+}
+
+// GenerateJUnitReport generates the JUnit XML report for the advice map from file path to advice list.
+// Each file maps to a test suite, and each warning or error advice maps to a failed test case.
+func GenerateJUnitReport(adviceMap map[string][]Advice) (string, error) {
+	report := junitTestSuites{
+		Name:       "SQL Review",
+		TestSuites: []junitTestSuite{},
+	}
+
+	for _, filePath := range sortedFileList(adviceMap) {
+		suite := junitTestSuite{
+			Name: filePath,
+		}
+		for _, advice := range adviceMap[filePath] {
+			if advice.Code == Ok || advice.Status == Success {
+				continue
+			}
+			suite.TestCases = append(suite.TestCases, junitTestCase{
+				Name:      advice.Title,
+				ClassName: filePath,
+				File:      filePath,
+				Line:      adviceLine(advice),
+				Failure: &junitFailure{
+					Message: advice.Content,
+					Type:    string(advice.Status),
+					Content: fmt.Sprintf("%s\nYou can check the docs at %s#%d", advice.Content, ErrorCodeDocsURL, advice.Code),
+				},
+			})
+		}
+		if len(suite.TestCases) == 0 {
+			continue
+		}
+		suite.Tests = len(suite.TestCases)
+		suite.Failures = len(suite.TestCases)
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.TestSuites = append(report.TestSuites, suite)
+	}
+
+	bytes, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(bytes), nil
+}
+
+func newSARIFRegion(advice Advice) sarifRegion {
+	region := sarifRegion{StartLine: adviceLine(advice)}
+	if advice.StartPosition != nil && advice.EndPosition != nil {
+		region.StartLine = advice.StartPosition.Line
+		region.StartColumn = advice.StartPosition.Column
+		region.EndLine = advice.EndPosition.Line
+		region.EndColumn = advice.EndPosition.Column
+	}
+	if advice.Excerpt != "" {
+		region.Snippet = &sarifMessage{Text: advice.Excerpt}
+	}
+	return region
+}
+
+func sortedFileList(adviceMap map[string][]Advice) []string {
+	var fileList []string
+	for filePath := range adviceMap {
+		fileList = append(fileList, filePath)
+	}
+	sort.Strings(fileList)
+	return fileList
+}
+
+func adviceLine(advice Advice) int {
+	if advice.Line <= 0 {
+		return 1
+	}
+	return advice.Line
+}
@@ -0,0 +1,94 @@
+package advisor
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var mockReportAdviceMap = map[string][]Advice{
+	"file2.sql": {
+		{
+			Status:  Error,
+			Code:    NamingTableConventionMismatch,
+			Title:   string(SchemaRuleTableNaming),
+			Content: `"techBook" mismatches table naming convention`,
+			Line:    3,
+		},
+	},
+	"file1.sql": {
+		{
+			Status:  Success,
+			Code:    Ok,
+			Title:   "OK",
+			Content: "",
+		},
+		{
+			Status:  Warn,
+			Code:    ColumnCannotNull,
+			Title:   string(SchemaRuleColumnNotNull),
+			Content: `Column "id" in "public"."book" cannot have NULL value & <default>`,
+			Line:    0,
+		},
+	},
+}
+
+func TestGenerateSARIFReport(t *testing.T) {
+	report, err := GenerateSARIFReport(mockReportAdviceMap)
+	require.NoError(t, err)
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal([]byte(report), &log))
+	require.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+
+	run := log.Runs[0]
+	require.Equal(t, []sarifRule{
+		{
+			ID:               "column.no-null",
+			ShortDescription: sarifMessage{Text: "column.no-null"},
+			HelpURI:          "https://www.bytebase.com/docs/reference/error-code/advisor#402",
+			Properties:       map[string]string{"code": "402"},
+		},
+		{
+			ID:               "naming.table",
+			ShortDescription: sarifMessage{Text: "naming.table"},
+			HelpURI:          "https://www.bytebase.com/docs/reference/error-code/advisor#301",
+			Properties:       map[string]string{"code": "301"},
+		},
+	}, run.Tool.Driver.Rules)
+	require.Len(t, run.Results, 2)
+	require.Equal(t, "column.no-null", run.Results[0].RuleID)
+	require.Equal(t, "warning", run.Results[0].Level)
+	require.Equal(t, "file1.sql", run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	require.Equal(t, 1, run.Results[0].Locations[0].PhysicalLocation.Region.StartLine)
+	require.Equal(t, "naming.table", run.Results[1].RuleID)
+	require.Equal(t, 1, run.Results[1].RuleIndex)
+	require.Equal(t, "error", run.Results[1].Level)
+	require.Equal(t, 3, run.Results[1].Locations[0].PhysicalLocation.Region.StartLine)
+}
+
+func TestGenerateJUnitReport(t *testing.T) {
+	expect := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="SQL Review" tests="2" failures="2">
+  <testsuite name="file1.sql" tests="1" failures="1">
+    <testcase name="column.no-null" classname="file1.sql" file="file1.sql" line="1">
+      <failure message="Column &#34;id&#34; in &#34;public&#34;.&#34;book&#34; cannot have NULL value &amp; &lt;default&gt;" type="WARN">Column &#34;id&#34; in &#34;public&#34;.&#34;book&#34; cannot have NULL value &amp; &lt;default&gt;&#xA;You can check the docs at https://www.bytebase.com/docs/reference/error-code/advisor#402</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="file2.sql" tests="1" failures="1">
+    <testcase name="naming.table" classname="file2.sql" file="file2.sql" line="3">
+      <failure message="&#34;techBook&#34; mismatches table naming convention" type="ERROR">&#34;techBook&#34; mismatches table naming convention&#xA;You can check the docs at https://www.bytebase.com/docs/reference/error-code/advisor#301</failure>
+    </testcase>
+  </testsuite>
+</testsuites>`
+	report, err := GenerateJUnitReport(mockReportAdviceMap)
+	require.NoError(t, err)
+	require.Equal(t, expect, report)
+}
+
+func TestGenerateReportInvalidFormat(t *testing.T) {
+	_, err := GenerateReport(ReportFormat("html"), mockReportAdviceMap)
+	require.Error(t, err)
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"log"
 	"regexp"
+	"sync"
 
 	"github.com/pkg/errors"
 
@@ -207,6 +208,155 @@ func (policy *SQLReviewPolicy) Validate() error {
 	return nil
 }
 
+// ValidateOverride validates the SQLReviewPolicy used to override another policy.
+// Different from Validate, the name can be empty, and the rule level or payload can be empty to keep the value of the overridden rule.
+func (policy *SQLReviewPolicy) ValidateOverride() error {
+	if len(policy.RuleList) == 0 {
+		return errors.Errorf("invalid payload, rule list cannot be empty")
+	}
+	for _, rule := range policy.RuleList {
+		switch rule.Level {
+		case "", SchemaRuleLevelError, SchemaRuleLevelWarning, SchemaRuleLevelDisabled:
+		default:
+			return errors.Errorf("invalid level %q for rule %s", rule.Level, rule.Type)
+		}
+		if rule.Payload == "" {
+			continue
+		}
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MergeSQLReviewPolicy merges the override policy into the base policy and returns a new policy.
+// Rules in the override policy replace the level and payload of the base rules with the same type,
+// and rules which don't exist in the base policy are appended.
+// An empty level or payload in the override rule means keeping the value of the base rule.
+// If there is no base rule to keep, the empty level or payload is filled by the prod template of the engine.
+func MergeSQLReviewPolicy(base *SQLReviewPolicy, override *SQLReviewPolicy, engine db.Type) (*SQLReviewPolicy, error) {
+	if override == nil {
+		return base, nil
+	}
+	if base == nil {
+		base = &SQLReviewPolicy{}
+	}
+
+	overrideMap := make(map[SQLReviewRuleType]*SQLReviewRule)
+	for _, rule := range override.RuleList {
+		overrideMap[rule.Type] = rule
+	}
+
+	res := &SQLReviewPolicy{
+		Name: override.Name,
+	}
+	if res.Name == "" {
+		res.Name = base.Name
+	}
+	for _, rule := range base.RuleList {
+		merged := &SQLReviewRule{
+			Type:    rule.Type,
+			Level:   rule.Level,
+			Payload: rule.Payload,
+		}
+		if overrideRule, ok := overrideMap[rule.Type]; ok {
+			if overrideRule.Level != "" {
+				merged.Level = overrideRule.Level
+			}
+			if overrideRule.Payload != "" {
+				merged.Payload = overrideRule.Payload
+			}
+			delete(overrideMap, rule.Type)
+		}
+		res.RuleList = append(res.RuleList, merged)
+	}
+	// Keep the order of the rules in the override policy.
+	for _, rule := range override.RuleList {
+		if _, ok := overrideMap[rule.Type]; ok {
+			filled, err := fillDefaultSQLReviewRule(rule, engine)
+			if err != nil {
+				return nil, err
+			}
+			res.RuleList = append(res.RuleList, filled)
+		}
+	}
+	return res, nil
+}
+
+var (
+	defaultSQLReviewRuleOnce sync.Once
+	// defaultSQLReviewRuleMap is the map from the template ID to the rules in the template keyed by the rule type.
+	defaultSQLReviewRuleMap map[SQLReviewTemplateID]map[SQLReviewRuleType]*SQLReviewRule
+	defaultSQLReviewRuleErr error
+)
+
+// getDefaultSQLReviewTemplateID returns the prod template ID of the engine.
+func getDefaultSQLReviewTemplateID(engine db.Type) SQLReviewTemplateID {
+	if engine == db.Postgres {
+		return TemplateForPostgreSQLProd
+	}
+	return TemplateForMySQLProd
+}
+
+func loadDefaultSQLReviewRuleMap() (map[SQLReviewTemplateID]map[SQLReviewRuleType]*SQLReviewRule, error) {
+	templateList, err := parseSQLReviewTemplateList()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse SQL review templates")
+	}
+	res := make(map[SQLReviewTemplateID]map[SQLReviewRuleType]*SQLReviewRule)
+	for _, template := range templateList {
+		ruleMap := make(map[SQLReviewRuleType]*SQLReviewRule)
+		for _, ruleData := range template.RuleList {
+			defaultRule := &SQLReviewRule{
+				Type:  ruleData.Type,
+				Level: ruleData.Level,
+			}
+			if len(ruleData.Payload) > 0 {
+				payload, err := json.Marshal(ruleData.Payload)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to marshal payload of rule %s in template %s", ruleData.Type, template.ID)
+				}
+				defaultRule.Payload = string(payload)
+			}
+			ruleMap[ruleData.Type] = defaultRule
+		}
+		res[template.ID] = ruleMap
+	}
+	return res, nil
+}
+
+// fillDefaultSQLReviewRule returns a copy of the rule whose empty level and payload are filled by the rule in the prod template of the engine.
+// The level defaults to WARNING if the rule is not in the template, and the filled rule is validated.
+func fillDefaultSQLReviewRule(rule *SQLReviewRule, engine db.Type) (*SQLReviewRule, error) {
+	defaultSQLReviewRuleOnce.Do(func() {
+		defaultSQLReviewRuleMap, defaultSQLReviewRuleErr = loadDefaultSQLReviewRuleMap()
+	})
+	if defaultSQLReviewRuleErr != nil {
+		return nil, defaultSQLReviewRuleErr
+	}
+
+	res := &SQLReviewRule{
+		Type:    rule.Type,
+		Level:   rule.Level,
+		Payload: rule.Payload,
+	}
+	defaultRule, ok := defaultSQLReviewRuleMap[getDefaultSQLReviewTemplateID(engine)][rule.Type]
+	if res.Level == "" {
+		res.Level = SchemaRuleLevelWarning
+		if ok && defaultRule.Level != "" {
+			res.Level = defaultRule.Level
+		}
+	}
+	if res.Payload == "" && ok {
+		res.Payload = defaultRule.Payload
+	}
+	if err := res.Validate(); err != nil {
+		return nil, errors.Wrapf(err, "invalid rule %s", res.Type)
+	}
+	return res, nil
+}
+
 // SQLReviewRule is the rule for SQL review policy.
 type SQLReviewRule struct {
 	Type  SQLReviewRuleType  `json:"type"`
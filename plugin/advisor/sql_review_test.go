@@ -0,0 +1,116 @@
+package advisor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+)
+
+func TestMergeSQLReviewPolicy(t *testing.T) {
+	base := &SQLReviewPolicy{
+		Name: "Prod",
+		RuleList: []*SQLReviewRule{
+			{Type: SchemaRuleStatementRequireWhere, Level: SchemaRuleLevelError, Payload: ""},
+			{Type: SchemaRuleTableNaming, Level: SchemaRuleLevelWarning, Payload: `{"format":"^[a-z]+(_[a-z]+)*$","maxLength":64}`},
+			{Type: SchemaRuleTableRequirePK, Level: SchemaRuleLevelError, Payload: ""},
+		},
+	}
+	override := &SQLReviewPolicy{
+		Name: "Project Prod",
+		RuleList: []*SQLReviewRule{
+			{Type: SchemaRuleTableRequirePK, Level: SchemaRuleLevelDisabled},
+			{Type: SchemaRuleTableNaming, Payload: `{"format":"^tbl_[a-z]+$","maxLength":32}`},
+			{Type: SchemaRuleStatementNoSelectAll, Level: SchemaRuleLevelWarning, Payload: ""},
+		},
+	}
+
+	want := &SQLReviewPolicy{
+		Name: "Project Prod",
+		RuleList: []*SQLReviewRule{
+			{Type: SchemaRuleStatementRequireWhere, Level: SchemaRuleLevelError, Payload: ""},
+			{Type: SchemaRuleTableNaming, Level: SchemaRuleLevelWarning, Payload: `{"format":"^tbl_[a-z]+$","maxLength":32}`},
+			{Type: SchemaRuleTableRequirePK, Level: SchemaRuleLevelDisabled, Payload: ""},
+			{Type: SchemaRuleStatementNoSelectAll, Level: SchemaRuleLevelWarning, Payload: ""},
+		},
+	}
+	got, err := MergeSQLReviewPolicy(base, override, db.MySQL)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+
+	got, err = MergeSQLReviewPolicy(base, nil, db.MySQL)
+	require.NoError(t, err)
+	require.Equal(t, base, got)
+}
+
+func TestMergeSQLReviewPolicyFillDefault(t *testing.T) {
+	// The project-only or non-inheriting policy has no base rule to keep the empty level or payload.
+	override := &SQLReviewPolicy{
+		Name: "Project Prod",
+		RuleList: []*SQLReviewRule{
+			{Type: SchemaRuleTableRequirePK},
+			{Type: SchemaRuleTableNaming, Level: SchemaRuleLevelError},
+			{Type: SchemaRuleStatementInsertRowLimit, Payload: `{"number":10}`},
+			{Type: SchemaRuleStatementNoSelectAll, Level: SchemaRuleLevelDisabled},
+		},
+	}
+	want := &SQLReviewPolicy{
+		Name: "Project Prod",
+		RuleList: []*SQLReviewRule{
+			{Type: SchemaRuleTableRequirePK, Level: SchemaRuleLevelError, Payload: ""},
+			{Type: SchemaRuleTableNaming, Level: SchemaRuleLevelError, Payload: `{"format":"^[a-z]+(_[a-z]+)*$","maxLength":63}`},
+			{Type: SchemaRuleStatementInsertRowLimit, Level: SchemaRuleLevelWarning, Payload: `{"number":10}`},
+			{Type: SchemaRuleStatementNoSelectAll, Level: SchemaRuleLevelDisabled, Payload: ""},
+		},
+	}
+	for _, base := range []*SQLReviewPolicy{nil, {Name: "Prod"}} {
+		got, err := MergeSQLReviewPolicy(base, override, db.MySQL)
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+		for _, rule := range got.RuleList {
+			require.NoError(t, rule.Validate())
+			if rule.Level == SchemaRuleLevelDisabled {
+				continue
+			}
+			_, err := NewStatusBySQLReviewRuleLevel(rule.Level)
+			require.NoError(t, err)
+		}
+	}
+	// The override rules are not modified.
+	require.Equal(t, SQLReviewRuleLevel(""), override.RuleList[0].Level)
+}
+
+func TestMergeSQLReviewPolicyFillDefaultByEngine(t *testing.T) {
+	override := &SQLReviewPolicy{
+		Name: "Project Prod",
+		RuleList: []*SQLReviewRule{
+			{Type: SchemaRuleCharsetAllowlist},
+			{Type: SchemaRuleTableNaming},
+		},
+	}
+	got, err := MergeSQLReviewPolicy(nil, override, db.MySQL)
+	require.NoError(t, err)
+	require.Equal(t, []*SQLReviewRule{
+		{Type: SchemaRuleCharsetAllowlist, Level: SchemaRuleLevelError, Payload: `{"list":["utf8mb4"]}`},
+		{Type: SchemaRuleTableNaming, Level: SchemaRuleLevelWarning, Payload: `{"format":"^[a-z]+(_[a-z]+)*$","maxLength":63}`},
+	}, got.RuleList)
+
+	got, err = MergeSQLReviewPolicy(nil, override, db.Postgres)
+	require.NoError(t, err)
+	require.Equal(t, []*SQLReviewRule{
+		{Type: SchemaRuleCharsetAllowlist, Level: SchemaRuleLevelError, Payload: `{"list":["UTF8"]}`},
+		{Type: SchemaRuleTableNaming, Level: SchemaRuleLevelWarning, Payload: `{"format":"^[a-z]+(_[a-z]+)*$","maxLength":63}`},
+	}, got.RuleList)
+}
+
+func TestMergeSQLReviewPolicyInvalidPayload(t *testing.T) {
+	// The payload-required rule is not in the PostgreSQL template, so its empty payload cannot be filled.
+	_, err := MergeSQLReviewPolicy(nil, &SQLReviewPolicy{
+		Name: "Project Prod",
+		RuleList: []*SQLReviewRule{
+			{Type: SchemaRuleColumnTypeDisallowList, Level: SchemaRuleLevelError},
+		},
+	}, db.Postgres)
+	require.Error(t, err)
+}
@@ -0,0 +1,56 @@
+package advisor
+
+import (
+	"regexp"
+	"strings"
+)
+
+// SuggestName converts the name to the lower snake case, e.g. "UserProfile" to "user_profile",
+// and truncates it to the max length if needed.
+// It returns false if the converted name still mismatches the naming format.
+func SuggestName(name string, format *regexp.Regexp, maxLength int) (string, bool) {
+	var sb strings.Builder
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case isUpperASCII(c):
+			// Split the words for camel case, e.g. "userID" to "user_id" and "HTTPServer" to "http_server".
+			if i > 0 && (isLowerASCII(name[i-1]) || isDigitASCII(name[i-1]) || (isUpperASCII(name[i-1]) && i+1 < len(name) && isLowerASCII(name[i+1]))) {
+				sb.WriteByte('_')
+			}
+			sb.WriteByte(c + 'a' - 'A')
+		case isLowerASCII(c) || isDigitASCII(c):
+			sb.WriteByte(c)
+		default:
+			sb.WriteByte('_')
+		}
+	}
+
+	var wordList []string
+	for _, word := range strings.Split(sb.String(), "_") {
+		if word != "" {
+			wordList = append(wordList, word)
+		}
+	}
+	suggestion := strings.Join(wordList, "_")
+	if maxLength > 0 && len(suggestion) > maxLength {
+		suggestion = strings.TrimRight(suggestion[:maxLength], "_")
+	}
+
+	if suggestion == "" || suggestion == name || !format.MatchString(suggestion) {
+		return "", false
+	}
+	return suggestion, true
+}
+
+func isUpperASCII(c byte) bool {
+	return c >= 'A' && c <= 'Z'
+}
+
+func isLowerASCII(c byte) bool {
+	return c >= 'a' && c <= 'z'
+}
+
+func isDigitASCII(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,63 @@
+package advisor
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestName(t *testing.T) {
+	snakeCase := regexp.MustCompile("^[a-z]+(_[a-z]+)*$")
+	tests := []struct {
+		name      string
+		format    *regexp.Regexp
+		maxLength int
+		want      string
+		wantOK    bool
+	}{
+		{
+			name:   "techBook",
+			format: snakeCase,
+			want:   "tech_book",
+			wantOK: true,
+		},
+		{
+			name:   "HTTPRequestLog",
+			format: snakeCase,
+			want:   "http_request_log",
+			wantOK: true,
+		},
+		{
+			name:   "_tech-Book_",
+			format: snakeCase,
+			want:   "tech_book",
+			wantOK: true,
+		},
+		{
+			name:      "literary_book_author",
+			format:    snakeCase,
+			maxLength: 14,
+			want:      "literary_book",
+			wantOK:    true,
+		},
+		{
+			// The converted name still mismatches the format.
+			name:   "book2",
+			format: snakeCase,
+			wantOK: false,
+		},
+		{
+			// The name is unchanged.
+			name:   "tech_book",
+			format: snakeCase,
+			wantOK: false,
+		},
+	}
+
+	for _, test := range tests {
+		got, ok := SuggestName(test.name, test.format, test.maxLength)
+		require.Equal(t, test.wantOK, ok, test.name)
+		require.Equal(t, test.want, got, test.name)
+	}
+}
@@ -48,12 +48,14 @@ var (
 						Name: MockTableName,
 						ColumnList: []*catalog.Column{
 							{
-								Name: "id",
-								Type: "int",
+								Name:     "id",
+								Position: 1,
+								Type:     "int",
 							},
 							{
-								Name: "name",
-								Type: "varchar(255)",
+								Name:     "name",
+								Position: 2,
+								Type:     "varchar(255)",
 							},
 						},
 						IndexList: []*catalog.Index{
@@ -146,6 +148,22 @@ func RunSQLReviewRuleTests(
 		ctx.Catalog = finder
 		adviceList, err := adv.Check(ctx, tc.Statement)
 		require.NoError(t, err)
+		// The advisors locate the advice by the statement nodes, the range must start in the statement ending at or covering the advice line.
+		// We check the range here and compare the other fields with the test cases.
+		for i := range adviceList {
+			advice := &adviceList[i]
+			if advice.Line > 0 {
+				require.NotNil(t, advice.StartPosition, tc.Statement)
+				require.NotNil(t, advice.EndPosition, tc.Statement)
+				require.LessOrEqual(t, advice.StartPosition.Line, advice.Line, tc.Statement)
+				require.True(t, advice.EndPosition.Line > advice.StartPosition.Line ||
+					(advice.EndPosition.Line == advice.StartPosition.Line && advice.EndPosition.Column >= advice.StartPosition.Column), tc.Statement)
+				require.NotEmpty(t, advice.Excerpt, tc.Statement)
+			}
+			advice.StartPosition = nil
+			advice.EndPosition = nil
+			advice.Excerpt = ""
+		}
 		assert.Equal(t, tc.Want, adviceList, tc.Statement)
 	}
 }
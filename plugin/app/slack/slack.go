@@ -0,0 +1,209 @@
+// Package slack implements the Slack interactive message callers.
+package slack
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	timeout = 30 * time.Second
+	// APIPath is the path of the Slack API server.
+	APIPath = "https://slack.com/api"
+
+	// signatureVersion is the version of the Slack request signature.
+	signatureVersion = "v0"
+	// maxRequestAge is the max age of the Slack request, older requests are rejected to prevent replay attacks.
+	// https://api.slack.com/authentication/verifying-requests-from-slack
+	maxRequestAge = 5 * time.Minute
+)
+
+const (
+	// SignatureHeader is the header of the Slack request signature.
+	SignatureHeader = "X-Slack-Signature"
+	// TimestampHeader is the header of the Slack request timestamp.
+	TimestampHeader = "X-Slack-Request-Timestamp"
+)
+
+// ActionID is the action ID of the interactive message button.
+type ActionID string
+
+const (
+	// ActionApprove is the action ID of the approve button.
+	ActionApprove ActionID = "bb.approval.approve"
+	// ActionReject is the action ID of the reject button.
+	ActionReject ActionID = "bb.approval.reject"
+)
+
+// InteractionTypeBlockActions is the type of the interaction payload when the user clicks a button in the message.
+const InteractionTypeBlockActions = "block_actions"
+
+// InteractionPayload is the payload sent by Slack when the user interacts with the message.
+// https://api.slack.com/reference/interaction-payloads/block-actions
+type InteractionPayload struct {
+	Type string `json:"type"`
+	User struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+	} `json:"user"`
+	Actions []struct {
+		ActionID ActionID `json:"action_id"`
+		Value    string   `json:"value"`
+	} `json:"actions"`
+	ResponseURL string `json:"response_url"`
+}
+
+// ApprovalValue is the value of the approve and reject buttons.
+type ApprovalValue struct {
+	IssueID int `json:"issueId"`
+	StageID int `json:"stageId"`
+}
+
+// userInfoResponse is the response of users.info.
+type userInfoResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+	User  struct {
+		Profile struct {
+			Email string `json:"email"`
+		} `json:"profile"`
+	} `json:"user"`
+}
+
+// responseMessage is the message posted to the response URL of the interaction.
+type responseMessage struct {
+	Text            string `json:"text"`
+	ResponseType    string `json:"response_type"`
+	ReplaceOriginal bool   `json:"replace_original"`
+}
+
+// Provider is the provider for Slack.
+type Provider struct {
+	APIPath string
+	client  *http.Client
+}
+
+// NewProvider returns a Provider.
+func NewProvider(apiPath string) *Provider {
+	return &Provider{
+		APIPath: apiPath,
+		client: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// VerifyRequest verifies the Slack request with the signing secret.
+// https://api.slack.com/authentication/verifying-requests-from-slack
+func VerifyRequest(signingSecret, timestamp, signature string, body []byte, now time.Time) error {
+	if signingSecret == "" {
+		return errors.New("signing secret is empty")
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.Wrapf(err, "invalid request timestamp %q", timestamp)
+	}
+	age := now.Sub(time.Unix(ts, 0))
+	if age > maxRequestAge || age < -maxRequestAge {
+		return errors.Errorf("request timestamp %q is too old", timestamp)
+	}
+	if !hmac.Equal([]byte(signature), []byte(computeSignature(signingSecret, timestamp, body))) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+func computeSignature(signingSecret, timestamp string, body []byte) string {
+	h := hmac.New(sha256.New, []byte(signingSecret))
+	h.Write([]byte(fmt.Sprintf("%s:%s:", signatureVersion, timestamp)))
+	h.Write(body)
+	return fmt.Sprintf("%s=%s", signatureVersion, hex.EncodeToString(h.Sum(nil)))
+}
+
+// ParseInteractionPayload parses the interaction payload from the form-encoded request body.
+func ParseInteractionPayload(body []byte) (*InteractionPayload, error) {
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse request body")
+	}
+	payload := &InteractionPayload{}
+	if err := json.Unmarshal([]byte(values.Get("payload")), payload); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal interaction payload")
+	}
+	return payload, nil
+}
+
+// GetUserEmail gets the email of the Slack user, which requires the users:read.email scope of the bot token.
+// https://api.slack.com/methods/users.info
+func (p *Provider) GetUserEmail(ctx context.Context, botToken, userID string) (string, error) {
+	u := fmt.Sprintf("%s/users.info?user=%s", p.APIPath, url.QueryEscape(userID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to construct GET %s", u)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", botToken))
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to GET %s", u)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read response body from GET %s", u)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("non-200 GET status code %d with body %q", resp.StatusCode, b)
+	}
+
+	var response userInfoResponse
+	if err := json.Unmarshal(b, &response); err != nil {
+		return "", err
+	}
+	if !response.OK {
+		return "", errors.Errorf("failed to get user info, error %s", response.Error)
+	}
+	if response.User.Profile.Email == "" {
+		return "", errors.Errorf("Slack user %s has no email", userID)
+	}
+	return response.User.Profile.Email, nil
+}
+
+// PostResponse posts an ephemeral message to the response URL of the interaction, which is only visible to the user.
+// https://api.slack.com/interactivity/handling#message_responses
+func (p *Provider) PostResponse(ctx context.Context, responseURL, text string) error {
+	body, err := json.Marshal(&responseMessage{
+		Text:            text,
+		ResponseType:    "ephemeral",
+		ReplaceOriginal: false,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, responseURL, bytes.NewBuffer(body))
+	if err != nil {
+		return errors.Wrapf(err, "failed to construct POST %s", responseURL)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to POST %s", responseURL)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(resp.Body)
+		return errors.Errorf("non-200 POST status code %d with body %q", resp.StatusCode, b)
+	}
+	return nil
+}
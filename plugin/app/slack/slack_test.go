@@ -0,0 +1,79 @@
+package slack
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/common"
+)
+
+func TestVerifyRequest(t *testing.T) {
+	a := require.New(t)
+	now := time.Unix(1531420618, 0)
+	body := []byte("token=xyzz0WbapA4vBCDEFasx0q6G&team_id=T1DC2JH3J")
+	timestamp := "1531420618"
+	signature := computeSignature("secret", timestamp, body)
+
+	a.NoError(VerifyRequest("secret", timestamp, signature, body, now))
+	a.NoError(VerifyRequest("secret", timestamp, signature, body, now.Add(4*time.Minute)))
+	a.Error(VerifyRequest("secret", timestamp, signature, body, now.Add(6*time.Minute)))
+	a.Error(VerifyRequest("another", timestamp, signature, body, now))
+	a.Error(VerifyRequest("secret", timestamp, signature, []byte("tampered"), now))
+	a.Error(VerifyRequest("secret", "not-a-number", signature, body, now))
+	a.Error(VerifyRequest("", timestamp, signature, body, now))
+}
+
+func TestParseInteractionPayload(t *testing.T) {
+	a := require.New(t)
+	payload := `{"type":"block_actions","user":{"id":"U123","username":"alice"},"actions":[{"action_id":"bb.approval.approve","value":"{\"issueId\":101,\"stageId\":102}"}],"response_url":"https://hooks.slack.com/actions/T1/1/x"}`
+	body := []byte("payload=" + url.QueryEscape(payload))
+
+	got, err := ParseInteractionPayload(body)
+	a.NoError(err)
+	a.Equal(InteractionTypeBlockActions, got.Type)
+	a.Equal("U123", got.User.ID)
+	a.Len(got.Actions, 1)
+	a.Equal(ActionApprove, got.Actions[0].ActionID)
+	a.Equal(`{"issueId":101,"stageId":102}`, got.Actions[0].Value)
+	a.Equal("https://hooks.slack.com/actions/T1/1/x", got.ResponseURL)
+
+	_, err = ParseInteractionPayload([]byte("payload=invalid"))
+	a.Error(err)
+}
+
+func TestProvider_GetUserEmail(t *testing.T) {
+	a := require.New(t)
+	p := NewProvider(APIPath)
+	p.client = &http.Client{
+		Transport: &common.MockRoundTripper{
+			MockRoundTrip: func(r *http.Request) (*http.Response, error) {
+				a.Equal("Bearer xoxb-token", r.Header.Get("Authorization"))
+				a.Equal("U123", r.URL.Query().Get("user"))
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body: io.NopCloser(strings.NewReader(`
+{
+    "ok": true,
+    "user": {
+        "id": "U123",
+        "profile": {
+            "email": "alice@example.com"
+        }
+    }
+}
+`)),
+				}, nil
+			},
+		},
+	}
+	email, err := p.GetUserEmail(context.Background(), "xoxb-token", "U123")
+	a.NoError(err)
+	a.Equal("alice@example.com", email)
+}
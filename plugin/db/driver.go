@@ -258,6 +258,9 @@ type MigrationInfo struct {
 	// This applies to BASELINE and MIGRATE types of migrations because most of these migrations are retry-able.
 	// We don't use force option for DATA type of migrations yet till there's customer needs.
 	Force bool
+	// Undo is whether the file is the undo script of the schema migration with the same version.
+	// The undo script is stored instead of being executed, and it's used to create the rollback issue of the migration.
+	Undo bool
 }
 
 // placeholderRegexp is the regexp for placeholder.
@@ -321,8 +324,14 @@ func ParseMigrationInfo(filePath, filePathTemplate string, allowOmitDatabaseName
 					mi.Type = Migrate
 				case "ddl":
 					mi.Type = Migrate
+				case "down":
+					mi.Type = Migrate
+					mi.Undo = true
+				case "undo":
+					mi.Type = Migrate
+					mi.Undo = true
 				default:
-					return nil, errors.Errorf("file path %q contains invalid migration type %q, must be 'migrate'('ddl') or 'data'('dml')", filePath, matchList[index])
+					return nil, errors.Errorf("file path %q contains invalid migration type %q, must be 'migrate'('ddl'), 'data'('dml') or 'undo'('down')", filePath, matchList[index])
 				}
 			case "DESCRIPTION":
 				mi.Description = matchList[index]
@@ -338,10 +347,12 @@ func ParseMigrationInfo(filePath, filePathTemplate string, allowOmitDatabaseName
 	}
 
 	if mi.Description == "" {
-		switch mi.Type {
-		case Baseline:
+		switch {
+		case mi.Undo:
+			mi.Description = fmt.Sprintf("Create %s undo migration", mi.Database)
+		case mi.Type == Baseline:
 			mi.Description = fmt.Sprintf("Create %s baseline", mi.Database)
-		case Data:
+		case mi.Type == Data:
 			mi.Description = fmt.Sprintf("Create %s data change", mi.Database)
 		default:
 			mi.Description = fmt.Sprintf("Create %s schema migration", mi.Database)
@@ -138,6 +138,38 @@ func TestParseMigrationInfo(t *testing.T) {
 			},
 			wantErr: "",
 		},
+		{
+			filePath:         "db1##001foo##undo",
+			filePathTemplate: "{{DB_NAME}}##{{VERSION}}##{{TYPE}}",
+			want: &MigrationInfo{
+				Version:     "001foo",
+				Namespace:   "db1",
+				Database:    "db1",
+				Environment: "",
+				Source:      VCS,
+				Type:        Migrate,
+				Description: "Create db1 undo migration",
+				Creator:     "",
+				Undo:        true,
+			},
+			wantErr: "",
+		},
+		{
+			filePath:         "db1##001foo##down##drop_t1",
+			filePathTemplate: "{{DB_NAME}}##{{VERSION}}##{{TYPE}}##{{DESCRIPTION}}",
+			want: &MigrationInfo{
+				Version:     "001foo",
+				Namespace:   "db1",
+				Database:    "db1",
+				Environment: "",
+				Source:      VCS,
+				Type:        Migrate,
+				Description: "Drop t1",
+				Creator:     "",
+				Undo:        true,
+			},
+			wantErr: "",
+		},
 		{
 			filePath:         "db_shop1##001foo##data",
 			filePathTemplate: "{{DB_NAME}}##{{VERSION}}##{{TYPE}}",
@@ -0,0 +1,259 @@
+// Package ldap implements the authentication and the user listing against an LDAP directory, e.g. Active Directory or OpenLDAP.
+package ldap
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/pkg/errors"
+)
+
+const (
+	timeout = 30 * time.Second
+	// pagingSize is the page size of listing the users, Active Directory limits the size of a search result to 1000 by default.
+	pagingSize = 500
+
+	// DefaultUserFilter is the default filter of the user, the %s is replaced by the escaped username.
+	DefaultUserFilter = "(uid=%s)"
+	// DefaultEmailAttribute is the default attribute of the user email.
+	DefaultEmailAttribute = "mail"
+	// DefaultNameAttribute is the default attribute of the user display name.
+	DefaultNameAttribute = "cn"
+	// DefaultGroupAttribute is the default attribute listing the DNs of the groups the user belongs to.
+	DefaultGroupAttribute = "memberOf"
+)
+
+// SecurityProtocol is the protocol securing the connection to the LDAP server.
+type SecurityProtocol string
+
+const (
+	// SecurityProtocolNone connects without TLS.
+	SecurityProtocolNone SecurityProtocol = "NONE"
+	// SecurityProtocolStartTLS upgrades the plain connection to TLS with the StartTLS operation.
+	SecurityProtocolStartTLS SecurityProtocol = "STARTTLS"
+	// SecurityProtocolLDAPS connects with TLS, usually on the port 636.
+	SecurityProtocolLDAPS SecurityProtocol = "LDAPS"
+)
+
+// Config is the configuration of the LDAP directory.
+type Config struct {
+	Host             string
+	Port             int
+	SecurityProtocol SecurityProtocol
+	// InsecureSkipVerify skips the verification of the server certificate, it should only be used for testing.
+	InsecureSkipVerify bool
+	// BindDN and BindPassword are the credentials of the service account searching the users.
+	// The search is anonymous if the BindDN is empty.
+	BindDN       string
+	BindPassword string
+	// BaseDN is the base of the user search, e.g. ou=users,dc=example,dc=com.
+	BaseDN string
+	// UserFilter is the filter of the user, e.g. (uid=%s) for OpenLDAP and (sAMAccountName=%s) for Active Directory.
+	UserFilter string
+	// EmailAttribute, NameAttribute and GroupAttribute are the attributes mapped to the user, the defaults are used if they're empty.
+	EmailAttribute string
+	NameAttribute  string
+	GroupAttribute string
+}
+
+// UserInfo is the user mapped from the directory entry.
+type UserInfo struct {
+	DN    string
+	Email string
+	Name  string
+	// GroupList is the common names of the groups the user belongs to.
+	GroupList []string
+}
+
+// Provider is the LDAP directory provider.
+type Provider struct {
+	config Config
+}
+
+// NewProvider creates a new LDAP directory provider, the defaults are used for the empty fields of the config.
+func NewProvider(config Config) *Provider {
+	if config.SecurityProtocol == "" {
+		config.SecurityProtocol = SecurityProtocolNone
+	}
+	if config.Port == 0 {
+		config.Port = 389
+		if config.SecurityProtocol == SecurityProtocolLDAPS {
+			config.Port = 636
+		}
+	}
+	if config.UserFilter == "" {
+		config.UserFilter = DefaultUserFilter
+	}
+	if config.EmailAttribute == "" {
+		config.EmailAttribute = DefaultEmailAttribute
+	}
+	if config.NameAttribute == "" {
+		config.NameAttribute = DefaultNameAttribute
+	}
+	if config.GroupAttribute == "" {
+		config.GroupAttribute = DefaultGroupAttribute
+	}
+	return &Provider{config: config}
+}
+
+// Ping connects to the LDAP server and binds with the service account to validate the config.
+func (p *Provider) Ping() error {
+	conn, err := p.connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	return nil
+}
+
+// Login authenticates the user by binding as the user entry found by the username.
+func (p *Provider) Login(username, password string) (*UserInfo, error) {
+	// An empty password results in an unauthenticated bind, which most servers accept without checking the credentials.
+	if username == "" || password == "" {
+		return nil, errors.New("username and password cannot be empty")
+	}
+	conn, err := p.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	result, err := conn.Search(p.newUserSearchRequest(getUserFilter(p.config.UserFilter, username, false /* listAll */), 2))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to search user %q", username)
+	}
+	if len(result.Entries) != 1 {
+		return nil, errors.Errorf("expect exactly one user found by %q, but got %d", username, len(result.Entries))
+	}
+	entry := result.Entries[0]
+	if err := conn.Bind(entry.DN, password); err != nil {
+		return nil, errors.Wrapf(err, "failed to bind as %q", entry.DN)
+	}
+
+	userInfo := p.getUserInfo(entry)
+	if userInfo.Email == "" {
+		return nil, errors.Errorf("user %q has no %s attribute", entry.DN, p.config.EmailAttribute)
+	}
+	return userInfo, nil
+}
+
+// ListUsers lists all users matching the user filter under the base DN, the users without email are skipped.
+func (p *Provider) ListUsers() ([]*UserInfo, error) {
+	conn, err := p.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	result, err := conn.SearchWithPaging(p.newUserSearchRequest(getUserFilter(p.config.UserFilter, "", true /* listAll */), 0), pagingSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list users")
+	}
+	var userInfoList []*UserInfo
+	for _, entry := range result.Entries {
+		userInfo := p.getUserInfo(entry)
+		if userInfo.Email == "" {
+			continue
+		}
+		userInfoList = append(userInfoList, userInfo)
+	}
+	return userInfoList, nil
+}
+
+// connect dials the LDAP server and binds with the service account.
+func (p *Provider) connect() (*ldap.Conn, error) {
+	tlsConfig := &tls.Config{
+		ServerName: p.config.Host,
+		// #nosec G402
+		InsecureSkipVerify: p.config.InsecureSkipVerify,
+	}
+	address := net.JoinHostPort(p.config.Host, strconv.Itoa(p.config.Port))
+	dialer := &net.Dialer{Timeout: timeout}
+
+	var conn *ldap.Conn
+	var err error
+	switch p.config.SecurityProtocol {
+	case SecurityProtocolLDAPS:
+		conn, err = ldap.DialURL(fmt.Sprintf("ldaps://%s", address), ldap.DialWithDialer(dialer), ldap.DialWithTLSConfig(tlsConfig))
+	case SecurityProtocolNone, SecurityProtocolStartTLS:
+		conn, err = ldap.DialURL(fmt.Sprintf("ldap://%s", address), ldap.DialWithDialer(dialer))
+	default:
+		return nil, errors.Errorf("unsupported security protocol %q", p.config.SecurityProtocol)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to %s", address)
+	}
+	conn.SetTimeout(timeout)
+
+	if p.config.SecurityProtocol == SecurityProtocolStartTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, errors.Wrap(err, "failed to start TLS")
+		}
+	}
+	if p.config.BindDN != "" {
+		if err := conn.Bind(p.config.BindDN, p.config.BindPassword); err != nil {
+			conn.Close()
+			return nil, errors.Wrapf(err, "failed to bind as %q", p.config.BindDN)
+		}
+	}
+	return conn, nil
+}
+
+func (p *Provider) newUserSearchRequest(filter string, sizeLimit int) *ldap.SearchRequest {
+	return ldap.NewSearchRequest(
+		p.config.BaseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		sizeLimit,
+		int(timeout.Seconds()),
+		false,
+		filter,
+		[]string{p.config.EmailAttribute, p.config.NameAttribute, p.config.GroupAttribute},
+		nil,
+	)
+}
+
+func (p *Provider) getUserInfo(entry *ldap.Entry) *UserInfo {
+	userInfo := &UserInfo{
+		DN:    entry.DN,
+		Email: strings.ToLower(entry.GetEqualFoldAttributeValue(p.config.EmailAttribute)),
+		Name:  entry.GetEqualFoldAttributeValue(p.config.NameAttribute),
+	}
+	if userInfo.Name == "" {
+		userInfo.Name = strings.Split(userInfo.Email, "@")[0]
+	}
+	for _, groupDN := range entry.GetEqualFoldAttributeValues(p.config.GroupAttribute) {
+		userInfo.GroupList = append(userInfo.GroupList, getGroupName(groupDN))
+	}
+	return userInfo
+}
+
+// getUserFilter replaces the %s in the filter with the escaped username.
+// The %s is replaced with the "*" wildcard instead if listAll is true, so the username is never taken as a wildcard.
+func getUserFilter(filter, username string, listAll bool) string {
+	if listAll {
+		return strings.ReplaceAll(filter, "%s", "*")
+	}
+	return strings.ReplaceAll(filter, "%s", ldap.EscapeFilter(username))
+}
+
+// getGroupName returns the common name of the group DN, e.g. dba for cn=dba,ou=groups,dc=example,dc=com.
+// The value is returned as is if it's not a DN or has no common name.
+func getGroupName(groupDN string) string {
+	dn, err := ldap.ParseDN(groupDN)
+	if err != nil || len(dn.RDNs) == 0 {
+		return groupDN
+	}
+	for _, attribute := range dn.RDNs[0].Attributes {
+		if strings.EqualFold(attribute.Type, "cn") {
+			return attribute.Value
+		}
+	}
+	return groupDN
+}
@@ -0,0 +1,58 @@
+package ldap
+
+import (
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetUserFilter(t *testing.T) {
+	a := require.New(t)
+	a.Equal("(uid=alice)", getUserFilter("(uid=%s)", "alice", false))
+	a.Equal("(&(objectClass=user)(sAMAccountName=alice))", getUserFilter("(&(objectClass=user)(sAMAccountName=%s))", "alice", false))
+	// The special characters in the username are escaped to prevent the filter injection.
+	a.Equal(`(uid=\2a\29\28uid=\2a)`, getUserFilter("(uid=%s)", "*)(uid=*", false))
+	// The wildcard username can't be used to log in as any user.
+	a.Equal(`(uid=\2a)`, getUserFilter("(uid=%s)", "*", false))
+	// The wildcard lists all users.
+	a.Equal("(uid=*)", getUserFilter("(uid=%s)", "", true))
+}
+
+func TestGetGroupName(t *testing.T) {
+	a := require.New(t)
+	a.Equal("dba", getGroupName("cn=dba,ou=groups,dc=example,dc=com"))
+	a.Equal("Domain Admins", getGroupName("CN=Domain Admins,CN=Users,DC=example,DC=com"))
+	a.Equal("ou=groups,dc=example,dc=com", getGroupName("ou=groups,dc=example,dc=com"))
+	a.Equal("developers", getGroupName("developers"))
+}
+
+func TestGetUserInfo(t *testing.T) {
+	a := require.New(t)
+	p := NewProvider(Config{Host: "localhost"})
+	a.Equal(389, p.config.Port)
+	a.Equal(389, NewProvider(Config{SecurityProtocol: SecurityProtocolStartTLS}).config.Port)
+	a.Equal(636, NewProvider(Config{SecurityProtocol: SecurityProtocolLDAPS}).config.Port)
+
+	entry := ldap.NewEntry("uid=alice,ou=users,dc=example,dc=com", map[string][]string{
+		"mail":     {"Alice@Example.com"},
+		"cn":       {"Alice"},
+		"memberOf": {"cn=dba,ou=groups,dc=example,dc=com", "cn=dev,ou=groups,dc=example,dc=com"},
+	})
+	a.Equal(&UserInfo{
+		DN:        "uid=alice,ou=users,dc=example,dc=com",
+		Email:     "alice@example.com",
+		Name:      "Alice",
+		GroupList: []string{"dba", "dev"},
+	}, p.getUserInfo(entry))
+
+	// The name falls back to the local part of the email.
+	entry = ldap.NewEntry("uid=bob,ou=users,dc=example,dc=com", map[string][]string{
+		"mail": {"bob@example.com"},
+	})
+	a.Equal(&UserInfo{
+		DN:    "uid=bob,ou=users,dc=example,dc=com",
+		Email: "bob@example.com",
+		Name:  "bob",
+	}, p.getUserInfo(entry))
+}
@@ -0,0 +1,430 @@
+// Package oidc implements the OpenID Connect authorization code flow with PKCE for the single sign-on.
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pkg/errors"
+)
+
+const (
+	timeout = 30 * time.Second
+	// discoveryPath is the path of the OpenID provider configuration relative to the issuer.
+	// https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderConfig
+	discoveryPath = "/.well-known/openid-configuration"
+
+	// DefaultEmailClaim is the default claim of the user email.
+	DefaultEmailClaim = "email"
+	// DefaultNameClaim is the default claim of the user display name.
+	DefaultNameClaim = "name"
+	// DefaultGroupsClaim is the default claim of the user groups.
+	DefaultGroupsClaim = "groups"
+)
+
+// DefaultScopes are the default scopes requesting the ID token with the email and profile claims.
+var DefaultScopes = []string{"openid", "profile", "email"}
+
+// Config is the configuration of the OpenID Connect provider.
+type Config struct {
+	// IssuerURL is the issuer of the provider, e.g. https://example.okta.com, https://keycloak.example.com/realms/bytebase
+	// or https://login.microsoftonline.com/{tenant}/v2.0.
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	// EmailClaim, NameClaim and GroupsClaim are the claims mapped to the user, the defaults are used if they're empty.
+	EmailClaim  string
+	NameClaim   string
+	GroupsClaim string
+}
+
+// Discovery is the OpenID provider configuration.
+type Discovery struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserinfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+}
+
+// UserInfo is the user mapped from the claims of the provider.
+type UserInfo struct {
+	Email     string
+	Name      string
+	GroupList []string
+}
+
+// tokenResponse is the response of the token endpoint.
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// jsonWebKey is a key in the JSON Web Key Set, only the RSA and EC public keys are supported.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// Provider is the OpenID Connect provider.
+type Provider struct {
+	config Config
+	client *http.Client
+}
+
+// NewProvider returns a Provider.
+func NewProvider(config Config) *Provider {
+	if len(config.Scopes) == 0 {
+		config.Scopes = DefaultScopes
+	}
+	if config.EmailClaim == "" {
+		config.EmailClaim = DefaultEmailClaim
+	}
+	if config.NameClaim == "" {
+		config.NameClaim = DefaultNameClaim
+	}
+	if config.GroupsClaim == "" {
+		config.GroupsClaim = DefaultGroupsClaim
+	}
+	return &Provider{
+		config: config,
+		client: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// GeneratePKCE generates the PKCE code verifier and the S256 code challenge.
+// https://www.rfc-editor.org/rfc/rfc7636#section-4.1
+func GeneratePKCE() (verifier string, challenge string, err error) {
+	verifier, err = randomString()
+	if err != nil {
+		return "", "", err
+	}
+	return verifier, getCodeChallenge(verifier), nil
+}
+
+func getCodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// GenerateNonce generates the nonce binding the ID token to the login session.
+func GenerateNonce() (string, error) {
+	return randomString()
+}
+
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "failed to generate random bytes")
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Discover fetches the OpenID provider configuration.
+func (p *Provider) Discover(ctx context.Context) (*Discovery, error) {
+	u := strings.TrimSuffix(p.config.IssuerURL, "/") + discoveryPath
+	b, err := p.get(ctx, u, "")
+	if err != nil {
+		return nil, err
+	}
+	discovery := &Discovery{}
+	if err := json.Unmarshal(b, discovery); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal OpenID provider configuration")
+	}
+	// The issuer must be identical to the URL used to retrieve the configuration.
+	if strings.TrimSuffix(discovery.Issuer, "/") != strings.TrimSuffix(p.config.IssuerURL, "/") {
+		return nil, errors.Errorf("issuer %q in the OpenID provider configuration does not match %q", discovery.Issuer, p.config.IssuerURL)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, errors.New("OpenID provider configuration misses the authorization, token or JWKS endpoint")
+	}
+	return discovery, nil
+}
+
+// GetAuthorizeURL returns the URL of the authorization endpoint, which the user is redirected to.
+func (p *Provider) GetAuthorizeURL(ctx context.Context, redirectURL, state, nonce, codeChallenge string) (string, error) {
+	discovery, err := p.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(discovery.AuthorizationEndpoint)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid authorization endpoint %q", discovery.AuthorizationEndpoint)
+	}
+	query := u.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", redirectURL)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// Login exchanges the authorization code for the tokens, verifies the ID token and returns the user mapped from the claims.
+// The claims of the userinfo endpoint are merged if the ID token doesn't contain the email.
+func (p *Provider) Login(ctx context.Context, redirectURL, code, codeVerifier, nonce string) (*UserInfo, error) {
+	discovery, err := p.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	token, err := p.exchangeToken(ctx, discovery, redirectURL, code, codeVerifier)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := p.verifyIDToken(ctx, discovery, token.IDToken, nonce, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := claims[p.config.EmailClaim]; !ok && discovery.UserinfoEndpoint != "" && token.AccessToken != "" {
+		b, err := p.get(ctx, discovery.UserinfoEndpoint, token.AccessToken)
+		if err != nil {
+			return nil, err
+		}
+		userinfo := jwt.MapClaims{}
+		if err := json.Unmarshal(b, &userinfo); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal userinfo")
+		}
+		// The sub claim of the userinfo must match the ID token.
+		if userinfo["sub"] != claims["sub"] {
+			return nil, errors.New("sub claim of the userinfo does not match the ID token")
+		}
+		for k, v := range userinfo {
+			if _, ok := claims[k]; !ok {
+				claims[k] = v
+			}
+		}
+	}
+	return p.getUserInfo(claims)
+}
+
+func (p *Provider) exchangeToken(ctx context.Context, discovery *Discovery, redirectURL, code, codeVerifier string) (*tokenResponse, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", redirectURL)
+	form.Set("code_verifier", codeVerifier)
+	// The client_secret_basic is the default authentication method of the token endpoint,
+	// and the client_secret_post is used only if the basic one is not supported.
+	usePost := len(discovery.TokenEndpointAuthMethodsSupported) > 0
+	for _, method := range discovery.TokenEndpointAuthMethodsSupported {
+		if method == "client_secret_basic" {
+			usePost = false
+			break
+		}
+	}
+	if usePost {
+		form.Set("client_id", p.config.ClientID)
+		form.Set("client_secret", p.config.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to construct POST %s", discovery.TokenEndpoint)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if !usePost {
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to POST %s", discovery.TokenEndpoint)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read response body from POST %s", discovery.TokenEndpoint)
+	}
+
+	token := &tokenResponse{}
+	if err := json.Unmarshal(b, token); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal token response with status code %d", resp.StatusCode)
+	}
+	if token.Error != "" {
+		return nil, errors.Errorf("failed to exchange token, error %s, description %s", token.Error, token.ErrorDescription)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("non-200 POST status code %d with body %q", resp.StatusCode, b)
+	}
+	if token.IDToken == "" {
+		return nil, errors.New("token response misses the ID token, check if the openid scope is granted")
+	}
+	return token, nil
+}
+
+// verifyIDToken verifies the signature, issuer, audience, expiration and nonce of the ID token.
+// https://openid.net/specs/openid-connect-core-1_0.html#IDTokenValidation
+func (p *Provider) verifyIDToken(ctx context.Context, discovery *Discovery, idToken, nonce string, now time.Time) (jwt.MapClaims, error) {
+	b, err := p.get(ctx, discovery.JWKSURI, "")
+	if err != nil {
+		return nil, err
+	}
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(b, &jwks); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal JWKS")
+	}
+
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		// The time based claims are validated below with the given time.
+		jwt.WithoutClaimsValidation(),
+	)
+	claims := jwt.MapClaims{}
+	if _, err := parser.ParseWithClaims(idToken, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		for _, key := range jwks.Keys {
+			if kid != "" && key.Kid != kid {
+				continue
+			}
+			return key.publicKey()
+		}
+		return nil, errors.Errorf("no key found in JWKS for kid %q", kid)
+	}); err != nil {
+		return nil, errors.Wrap(err, "failed to verify ID token")
+	}
+
+	if !claims.VerifyIssuer(discovery.Issuer, true) {
+		return nil, errors.Errorf("ID token issuer %v does not match %q", claims["iss"], discovery.Issuer)
+	}
+	if !claims.VerifyAudience(p.config.ClientID, true) {
+		return nil, errors.Errorf("ID token audience %v does not contain the client ID", claims["aud"])
+	}
+	if !claims.VerifyExpiresAt(now.Unix(), true) {
+		return nil, errors.New("ID token is expired")
+	}
+	if !claims.VerifyNotBefore(now.Unix(), false) {
+		return nil, errors.New("ID token is not valid yet")
+	}
+	if claimNonce, _ := claims["nonce"].(string); claimNonce != nonce {
+		return nil, errors.New("ID token nonce mismatch")
+	}
+	return claims, nil
+}
+
+// getUserInfo maps the claims to the user, the groups claim can be either a string array or a single string.
+func (p *Provider) getUserInfo(claims jwt.MapClaims) (*UserInfo, error) {
+	email, _ := claims[p.config.EmailClaim].(string)
+	if email == "" {
+		return nil, errors.Errorf("claim %q of the user email is missing", p.config.EmailClaim)
+	}
+	if verified, ok := claims["email_verified"].(bool); ok && !verified && p.config.EmailClaim == DefaultEmailClaim {
+		return nil, errors.Errorf("email %s is not verified", email)
+	}
+	userInfo := &UserInfo{
+		Email: strings.ToLower(email),
+	}
+	userInfo.Name, _ = claims[p.config.NameClaim].(string)
+	if userInfo.Name == "" {
+		userInfo.Name = strings.Split(email, "@")[0]
+	}
+	switch groups := claims[p.config.GroupsClaim].(type) {
+	case string:
+		userInfo.GroupList = []string{groups}
+	case []interface{}:
+		for _, group := range groups {
+			if s, ok := group.(string); ok {
+				userInfo.GroupList = append(userInfo.GroupList, s)
+			}
+		}
+	}
+	return userInfo, nil
+}
+
+func (p *Provider) get(ctx context.Context, u, accessToken string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to construct GET %s", u)
+	}
+	req.Header.Set("Accept", "application/json")
+	if accessToken != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to GET %s", u)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read response body from GET %s", u)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("non-200 GET %s status code %d with body %q", u, resp.StatusCode, b)
+	}
+	return b, nil
+}
+
+func (k *jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid modulus of key %q", k.Kid)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid exponent of key %q", k.Kid)
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.Errorf("unsupported curve %q of key %q", k.Crv, k.Kid)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid x coordinate of key %q", k.Kid)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid y coordinate of key %q", k.Kid)
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	}
+	return nil, errors.Errorf("unsupported key type %q of key %q", k.Kty, k.Kid)
+}
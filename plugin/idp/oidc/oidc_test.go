@@ -0,0 +1,215 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// mockIdP is a local OpenID provider issuing the ID token for the authorization code.
+type mockIdP struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	// claims are the claims of the ID token, the iss, aud and exp are set by the mock.
+	claims jwt.MapClaims
+	// userinfo is the response of the userinfo endpoint.
+	userinfo map[string]interface{}
+	// authMethods are the supported authentication methods of the token endpoint.
+	authMethods []string
+	// tokenForm is the form of the last token request.
+	tokenForm url.Values
+	// tokenBasicAuth is the client ID of the basic authentication in the last token request.
+	tokenBasicAuth string
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	m := &mockIdP{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(discoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(&Discovery{
+			Issuer:                            m.server.URL,
+			AuthorizationEndpoint:             m.server.URL + "/authorize",
+			TokenEndpoint:                     m.server.URL + "/token",
+			UserinfoEndpoint:                  m.server.URL + "/userinfo",
+			JWKSURI:                           m.server.URL + "/jwks",
+			TokenEndpointAuthMethodsSupported: m.authMethods,
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []jsonWebKey{
+				{
+					Kty: "RSA",
+					Kid: "key-1",
+					N:   base64.RawURLEncoding.EncodeToString(m.key.N.Bytes()),
+					E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(m.key.E)).Bytes()),
+				},
+			},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		m.tokenForm = r.PostForm
+		m.tokenBasicAuth, _, _ = r.BasicAuth()
+		if r.PostForm.Get("code") != "valid-code" {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		claims := jwt.MapClaims{
+			"iss": m.server.URL,
+			"aud": "client-id",
+			"exp": time.Now().Add(time.Hour).Unix(),
+		}
+		for k, v := range m.claims {
+			claims[k] = v
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "key-1"
+		idToken, err := token.SignedString(m.key)
+		require.NoError(t, err)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"access_token": "access-token",
+			"id_token":     idToken,
+		})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(m.userinfo)
+	})
+	m.server = httptest.NewServer(mux)
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+func (m *mockIdP) newProvider() *Provider {
+	return NewProvider(Config{
+		IssuerURL:    m.server.URL,
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+	})
+}
+
+func TestGetAuthorizeURL(t *testing.T) {
+	a := require.New(t)
+	m := newMockIdP(t)
+	verifier, challenge, err := GeneratePKCE()
+	a.NoError(err)
+	a.Equal(getCodeChallenge(verifier), challenge)
+
+	authorizeURL, err := m.newProvider().GetAuthorizeURL(context.Background(), "http://localhost/oauth/callback", "state", "nonce", challenge)
+	a.NoError(err)
+	u, err := url.Parse(authorizeURL)
+	a.NoError(err)
+	a.Equal(m.server.URL+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+	query := u.Query()
+	a.Equal("code", query.Get("response_type"))
+	a.Equal("client-id", query.Get("client_id"))
+	a.Equal("openid profile email", query.Get("scope"))
+	a.Equal("state", query.Get("state"))
+	a.Equal("nonce", query.Get("nonce"))
+	a.Equal(challenge, query.Get("code_challenge"))
+	a.Equal("S256", query.Get("code_challenge_method"))
+}
+
+func TestLogin(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	m := newMockIdP(t)
+	m.claims = jwt.MapClaims{
+		"sub":    "user-1",
+		"nonce":  "nonce",
+		"email":  "Alice@Example.com",
+		"name":   "Alice",
+		"groups": []string{"dba", "dev"},
+	}
+
+	userInfo, err := m.newProvider().Login(ctx, "http://localhost/oauth/callback", "valid-code", "verifier", "nonce")
+	a.NoError(err)
+	a.Equal(&UserInfo{Email: "alice@example.com", Name: "Alice", GroupList: []string{"dba", "dev"}}, userInfo)
+	a.Equal("verifier", m.tokenForm.Get("code_verifier"))
+	a.Equal("client-id", m.tokenBasicAuth)
+	a.Equal("", m.tokenForm.Get("client_secret"))
+
+	// The nonce must match.
+	_, err = m.newProvider().Login(ctx, "http://localhost/oauth/callback", "valid-code", "verifier", "another-nonce")
+	a.Error(err)
+
+	// The code must be valid.
+	_, err = m.newProvider().Login(ctx, "http://localhost/oauth/callback", "invalid-code", "verifier", "nonce")
+	a.Error(err)
+
+	// The client_secret_post is used if the basic one is not supported.
+	m.authMethods = []string{"client_secret_post"}
+	_, err = m.newProvider().Login(ctx, "http://localhost/oauth/callback", "valid-code", "verifier", "nonce")
+	a.NoError(err)
+	a.Equal("client-secret", m.tokenForm.Get("client_secret"))
+	a.Equal("", m.tokenBasicAuth)
+}
+
+func TestLoginWithUserinfoAndCustomClaims(t *testing.T) {
+	a := require.New(t)
+	m := newMockIdP(t)
+	m.claims = jwt.MapClaims{
+		"sub":   "user-1",
+		"nonce": "nonce",
+	}
+	m.userinfo = map[string]interface{}{
+		"sub":                "user-1",
+		"preferred_username": "bob@example.com",
+		"roles":              "owner",
+	}
+	p := NewProvider(Config{
+		IssuerURL:   m.server.URL,
+		ClientID:    "client-id",
+		EmailClaim:  "preferred_username",
+		GroupsClaim: "roles",
+	})
+	userInfo, err := p.Login(context.Background(), "http://localhost/oauth/callback", "valid-code", "verifier", "nonce")
+	a.NoError(err)
+	a.Equal(&UserInfo{Email: "bob@example.com", Name: "bob", GroupList: []string{"owner"}}, userInfo)
+
+	// The sub of the userinfo must match the ID token.
+	m.userinfo["sub"] = "user-2"
+	_, err = p.Login(context.Background(), "http://localhost/oauth/callback", "valid-code", "verifier", "nonce")
+	a.Error(err)
+}
+
+func TestVerifyIDTokenWithForgedKey(t *testing.T) {
+	a := require.New(t)
+	m := newMockIdP(t)
+	p := m.newProvider()
+	discovery, err := p.Discover(context.Background())
+	a.NoError(err)
+
+	forgedKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	a.NoError(err)
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   m.server.URL,
+		"aud":   "client-id",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"nonce": "nonce",
+	})
+	token.Header["kid"] = "key-1"
+	idToken, err := token.SignedString(forgedKey)
+	a.NoError(err)
+	_, err = p.verifyIDToken(context.Background(), discovery, idToken, "nonce", time.Now())
+	a.Error(err)
+}
@@ -0,0 +1,128 @@
+// Package mail provides the SMTP client for sending the email notifications.
+package mail
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Encryption is the encryption of the SMTP connection.
+type Encryption string
+
+const (
+	// EncryptionNone sends the emails in plain text.
+	EncryptionNone Encryption = "NONE"
+	// EncryptionStartTLS upgrades the plain text connection to TLS with the STARTTLS command.
+	EncryptionStartTLS Encryption = "STARTTLS"
+	// EncryptionSSLTLS connects to the SMTP server with TLS directly, which is usually on port 465.
+	EncryptionSSLTLS Encryption = "SSL/TLS"
+)
+
+// timeout is the timeout of connecting to the SMTP server.
+var timeout = 10 * time.Second
+
+// Config is the config of the SMTP server.
+type Config struct {
+	Host       string
+	Port       int
+	Username   string
+	Password   string
+	From       string
+	Encryption Encryption
+}
+
+// Message is the email message.
+type Message struct {
+	To       []string
+	Subject  string
+	HTMLBody string
+}
+
+// Send sends the message with the SMTP server in config.
+func Send(config Config, message Message) error {
+	if len(message.To) == 0 {
+		return errors.Errorf("no recipient for email %q", message.Subject)
+	}
+	addr := net.JoinHostPort(config.Host, fmt.Sprintf("%d", config.Port))
+	tlsConfig := &tls.Config{ServerName: config.Host}
+
+	var conn net.Conn
+	var err error
+	if config.Encryption == EncryptionSSLTLS {
+		conn, err = tls.DialWithDialer(&net.Dialer{Timeout: timeout}, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = net.DialTimeout("tcp", addr, timeout)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to connect to SMTP server %s", addr)
+	}
+	client, err := smtp.NewClient(conn, config.Host)
+	if err != nil {
+		conn.Close()
+		return errors.Wrapf(err, "failed to create SMTP client for %s", addr)
+	}
+	defer client.Close()
+
+	if config.Encryption == EncryptionStartTLS {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return errors.Wrap(err, "failed to start TLS")
+		}
+	}
+	if config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", config.Username, config.Password, config.Host)); err != nil {
+			return errors.Wrap(err, "failed to authenticate with SMTP server")
+		}
+	}
+	if err := client.Mail(config.From); err != nil {
+		return errors.Wrapf(err, "failed to set sender %q", config.From)
+	}
+	for _, to := range message.To {
+		if err := client.Rcpt(to); err != nil {
+			return errors.Wrapf(err, "failed to set recipient %q", to)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return errors.Wrap(err, "failed to start email data")
+	}
+	if _, err := w.Write(buildMessage(config.From, message)); err != nil {
+		return errors.Wrap(err, "failed to write email data")
+	}
+	if err := w.Close(); err != nil {
+		return errors.Wrap(err, "failed to send email data")
+	}
+	return client.Quit()
+}
+
+// buildMessage builds the MIME message with the HTML body encoded in base64.
+func buildMessage(from string, message Message) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(message.To, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/html; charset=\"utf-8\"\r\n")
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n")
+	buf.WriteString("\r\n")
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(message.HTMLBody))
+	// The line length of the base64 encoded body must not exceed 76 characters.
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76])
+		buf.WriteString("\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded)
+	buf.WriteString("\r\n")
+	return buf.Bytes()
+}
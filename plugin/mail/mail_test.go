@@ -0,0 +1,126 @@
+package mail
+
+import (
+	"bufio"
+	"encoding/base64"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// smtpStub is a minimal SMTP server accepting a single email.
+type smtpStub struct {
+	listener net.Listener
+	from     string
+	rcptList []string
+	data     string
+	done     chan struct{}
+}
+
+func newSMTPStub(t *testing.T) *smtpStub {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	stub := &smtpStub{listener: listener, done: make(chan struct{})}
+	go stub.serve()
+	return stub
+}
+
+func (s *smtpStub) serve() {
+	defer close(s.done)
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	_ = tp.PrintfLine("220 localhost ESMTP stub")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			_ = tp.PrintfLine("250 localhost")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			s.from = strings.Trim(line[len("MAIL FROM:"):], "<> ")
+			_ = tp.PrintfLine("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			s.rcptList = append(s.rcptList, strings.Trim(line[len("RCPT TO:"):], "<> "))
+			_ = tp.PrintfLine("250 OK")
+		case cmd == "DATA":
+			_ = tp.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			lines, err := tp.ReadDotLines()
+			if err != nil {
+				return
+			}
+			s.data = strings.Join(lines, "\n")
+			_ = tp.PrintfLine("250 OK")
+		case cmd == "QUIT":
+			_ = tp.PrintfLine("221 Bye")
+			return
+		default:
+			_ = tp.PrintfLine("502 Command not implemented")
+		}
+	}
+}
+
+func TestSend(t *testing.T) {
+	a := require.New(t)
+	stub := newSMTPStub(t)
+	defer stub.listener.Close()
+
+	addr := stub.listener.Addr().(*net.TCPAddr)
+	err := Send(Config{
+		Host:       "127.0.0.1",
+		Port:       addr.Port,
+		From:       "bytebase@example.com",
+		Encryption: EncryptionNone,
+	}, Message{
+		To:       []string{"alice@example.com", "bob@example.com"},
+		Subject:  "Issue created - 新工单",
+		HTMLBody: "<p>" + strings.Repeat("hello ", 20) + "</p>",
+	})
+	a.NoError(err)
+	<-stub.done
+
+	a.Equal("bytebase@example.com", stub.from)
+	a.Equal([]string{"alice@example.com", "bob@example.com"}, stub.rcptList)
+	a.Contains(stub.data, "To: alice@example.com, bob@example.com")
+	a.Contains(stub.data, "Subject: =?utf-8?q?")
+	a.Contains(stub.data, "Content-Type: text/html")
+
+	header, body, found := strings.Cut(stub.data, "\n\n")
+	a.True(found, header)
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(body, "\n", ""))
+	a.NoError(err)
+	a.Equal("<p>"+strings.Repeat("hello ", 20)+"</p>", string(decoded))
+}
+
+func TestSendWithoutRecipient(t *testing.T) {
+	a := require.New(t)
+	err := Send(Config{Host: "127.0.0.1", Port: 25}, Message{Subject: "test"})
+	a.Error(err)
+}
+
+func TestBuildMessage(t *testing.T) {
+	a := require.New(t)
+	message := string(buildMessage("bytebase@example.com", Message{
+		To:       []string{"alice@example.com"},
+		Subject:  "test",
+		HTMLBody: strings.Repeat("x", 100),
+	}))
+	reader := bufio.NewReader(strings.NewReader(message))
+	tp := textproto.NewReader(reader)
+	header, err := tp.ReadMIMEHeader()
+	a.NoError(err)
+	a.Equal("bytebase@example.com", header.Get("From"))
+	a.Equal("base64", header.Get("Content-Transfer-Encoding"))
+	for _, line := range strings.Split(strings.TrimRight(message[strings.Index(message, "\r\n\r\n")+4:], "\r\n"), "\r\n") {
+		a.LessOrEqual(len(line), 76)
+	}
+}
@@ -0,0 +1,122 @@
+// Package totp implements the time-based one-time password defined in RFC 6238, which is compatible with
+// the authenticator apps, e.g. Google Authenticator and 1Password.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// period is the time step of the code.
+	period = 30
+	// digits is the number of the digits of the code.
+	digits = 6
+	// skew is the number of the time steps before and after the current one accepted, to tolerate the clock drift.
+	skew = 1
+	// secretSize is the byte size of the secret, RFC 4226 recommends 160 bits.
+	secretSize = 20
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret generates a random base32 encoded secret.
+func GenerateSecret() (string, error) {
+	secret := make([]byte, secretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", errors.Wrap(err, "failed to generate secret")
+	}
+	return encoding.EncodeToString(secret), nil
+}
+
+// GenerateCode generates the code of the secret at the time.
+func GenerateCode(secret string, t time.Time) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return generateCode(key, uint64(t.Unix())/period), nil
+}
+
+// Validate returns true if the code matches the secret at the time, with the adjacent time steps accepted.
+func Validate(code string, secret string, t time.Time) bool {
+	_, ok := ValidateCounter(code, secret, t, -1)
+	return ok
+}
+
+// ValidateCounter validates the code like Validate, and returns the time step counter of the matched code.
+// Only the counters greater than lastCounter are accepted, so that the used code can't be replayed.
+func ValidateCounter(code string, secret string, t time.Time, lastCounter int64) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != digits {
+		return 0, false
+	}
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return 0, false
+	}
+	current := t.Unix() / period
+	for counter := current - skew; counter <= current+skew; counter++ {
+		if counter <= lastCounter {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(generateCode(key, uint64(counter))), []byte(code)) == 1 {
+			return counter, true
+		}
+	}
+	return 0, false
+}
+
+// GetKeyURI returns the otpauth URI of the secret, which is usually rendered as a QR code and scanned by the authenticator app.
+// See https://github.com/google/google-authenticator/wiki/Key-Uri-Format.
+func GetKeyURI(issuer string, accountName string, secret string) string {
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(digits))
+	params.Set("period", fmt.Sprint(period))
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     fmt.Sprintf("/%s:%s", issuer, accountName),
+		RawQuery: params.Encode(),
+	}
+	return u.String()
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil {
+		return nil, errors.Wrap(err, "malformed secret")
+	}
+	return key, nil
+}
+
+// generateCode generates the HOTP code defined in RFC 4226 with the counter.
+func generateCode(key []byte, counter uint64) string {
+	var message [8]byte
+	binary.BigEndian.PutUint64(message[:], counter)
+	h := hmac.New(sha1.New, key)
+	_, _ = h.Write(message[:])
+	sum := h.Sum(nil)
+
+	// Dynamic truncation.
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, value%mod)
+}
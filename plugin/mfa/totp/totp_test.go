@@ -0,0 +1,94 @@
+package totp
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateCode(t *testing.T) {
+	a := require.New(t)
+	// The test vectors of RFC 6238 with SHA1, truncated to 6 digits.
+	// The secret is the ASCII string "12345678901234567890".
+	const secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, test := range tests {
+		code, err := GenerateCode(secret, time.Unix(test.unix, 0))
+		a.NoError(err)
+		a.Equal(test.want, code, test.unix)
+	}
+
+	_, err := GenerateCode("not base32!", time.Now())
+	a.Error(err)
+}
+
+func TestValidate(t *testing.T) {
+	a := require.New(t)
+	secret, err := GenerateSecret()
+	a.NoError(err)
+	a.Len(secret, 32)
+
+	now := time.Unix(1671840000, 0)
+	code, err := GenerateCode(secret, now)
+	a.NoError(err)
+	a.True(Validate(code, secret, now))
+	a.True(Validate(" "+code+" ", secret, now))
+	// The adjacent time steps are accepted for the clock drift.
+	a.True(Validate(code, secret, now.Add(-period*time.Second)))
+	a.True(Validate(code, secret, now.Add(period*time.Second)))
+	a.False(Validate(code, secret, now.Add(2*period*time.Second)))
+	a.False(Validate("", secret, now))
+	a.False(Validate(code[:5], secret, now))
+	a.False(Validate(code, "not base32!", now))
+}
+
+func TestValidateCounter(t *testing.T) {
+	a := require.New(t)
+	const secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	now := time.Unix(1671840000, 0)
+	current := now.Unix() / period
+	code, err := GenerateCode(secret, now)
+	a.NoError(err)
+
+	counter, ok := ValidateCounter(code, secret, now, 0)
+	a.True(ok)
+	a.Equal(current, counter)
+	// The code can't be replayed once its counter is used.
+	_, ok = ValidateCounter(code, secret, now, counter)
+	a.False(ok)
+	_, ok = ValidateCounter(code, secret, now.Add(period*time.Second), counter)
+	a.False(ok)
+
+	// The code of the previous time step is rejected once a later one is used.
+	previousCode, err := GenerateCode(secret, now.Add(-period*time.Second))
+	a.NoError(err)
+	counter, ok = ValidateCounter(previousCode, secret, now, current-2)
+	a.True(ok)
+	a.Equal(current-1, counter)
+	_, ok = ValidateCounter(previousCode, secret, now, current)
+	a.False(ok)
+}
+
+func TestGetKeyURI(t *testing.T) {
+	a := require.New(t)
+	u, err := url.Parse(GetKeyURI("Bytebase", "alice@example.com", "JBSWY3DPEHPK3PXP"))
+	a.NoError(err)
+	a.Equal("otpauth", u.Scheme)
+	a.Equal("totp", u.Host)
+	a.Equal("/Bytebase:alice@example.com", u.Path)
+	a.Equal("JBSWY3DPEHPK3PXP", u.Query().Get("secret"))
+	a.Equal("Bytebase", u.Query().Get("issuer"))
+	a.Equal("6", u.Query().Get("digits"))
+}
@@ -0,0 +1,1625 @@
+// Package azure is the plugin for Azure DevOps.
+package azure
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/vcs"
+	"github.com/bytebase/bytebase/plugin/vcs/internal/oauth"
+)
+
+const (
+	// azureDevOpsURL is URL for the Azure DevOps Services.
+	azureDevOpsURL = "https://dev.azure.com"
+	// azureDevOpsVSSPSURL is URL for the Azure DevOps Services profile and
+	// OAuth endpoints, which are not served under the organization.
+	azureDevOpsVSSPSURL = "https://app.vssps.visualstudio.com"
+
+	// apiVersion is the version of the Azure DevOps REST API.
+	apiVersion = "7.0"
+
+	// apiPageSize is the default page size when making API requests.
+	apiPageSize = 100
+
+	// emptyCommitID is the object ID used by Azure DevOps when the ref is created or deleted.
+	emptyCommitID = "0000000000000000000000000000000000000000"
+
+	// WebhookSecretHeader is the HTTP header carrying the webhook secret in the
+	// service hook requests, which is configured along with the subscription
+	// because Azure DevOps doesn't sign the payload.
+	WebhookSecretHeader = "X-Bytebase-Webhook-Secret"
+
+	// VariableGroupName is the name of the variable group in the Azure DevOps
+	// project which holds the pipeline variables set by Bytebase.
+	VariableGroupName = "Bytebase"
+)
+
+func init() {
+	vcs.Register(vcs.AzureDevOps, newProvider)
+}
+
+var _ vcs.Provider = (*Provider)(nil)
+
+// Provider is an Azure DevOps VCS provider.
+type Provider struct {
+	client *http.Client
+}
+
+func newProvider(config vcs.ProviderConfig) vcs.Provider {
+	if config.Client == nil {
+		config.Client = &http.Client{}
+	}
+	// Copy the client to avoid changing the transport of the shared client.
+	client := *config.Client
+	client.Transport = &personalAccessTokenTransport{base: config.Client.Transport}
+	return &Provider{
+		client: &client,
+	}
+}
+
+// personalAccessTokenTransport sends the personal access token in the basic
+// auth, which is required by Azure DevOps, and leaves the OAuth token in the
+// bearer auth.
+type personalAccessTokenTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *personalAccessTokenTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	authorization := r.Header.Get("Authorization")
+	token := strings.TrimPrefix(authorization, "Bearer ")
+	// The OAuth access token of Azure DevOps is a JWT, while the personal access
+	// token is an opaque string without any dot.
+	if token == authorization || token == "" || strings.Contains(token, ".") {
+		return base.RoundTrip(r)
+	}
+	r = r.Clone(r.Context())
+	r.Header.Set("Authorization", fmt.Sprintf("Basic %s", base64.StdEncoding.EncodeToString([]byte(":"+token))))
+	return base.RoundTrip(r)
+}
+
+// APIURL returns the API URL path of Azure DevOps, and the organization,
+// project and repository are part of the API URL.
+func (*Provider) APIURL(instanceURL string) string {
+	return instanceURL
+}
+
+// vsspsURL returns the URL of the profile and OAuth endpoints of Azure DevOps.
+func vsspsURL(instanceURL string) string {
+	if instanceURL == azureDevOpsURL {
+		return azureDevOpsVSSPSURL
+	}
+
+	// The profile and OAuth endpoints are served under the instance URL for
+	// testing purpose.
+	return instanceURL
+}
+
+// Profile represents an Azure DevOps API response for a user profile.
+type Profile struct {
+	ID           string `json:"id"`
+	DisplayName  string `json:"displayName"`
+	PublicAlias  string `json:"publicAlias"`
+	EmailAddress string `json:"emailAddress"`
+}
+
+// Account represents an Azure DevOps API response for an organization.
+type Account struct {
+	AccountID   string `json:"accountId"`
+	AccountName string `json:"accountName"`
+}
+
+// Project represents an Azure DevOps API response for a project.
+type Project struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Repository represents an Azure DevOps API response for a Git repository.
+type Repository struct {
+	ID            string  `json:"id"`
+	Name          string  `json:"name"`
+	URL           string  `json:"url"`
+	RemoteURL     string  `json:"remoteUrl"`
+	WebURL        string  `json:"webUrl"`
+	DefaultBranch string  `json:"defaultBranch"`
+	Project       Project `json:"project"`
+}
+
+// GitUserDate is the author or committer of an Azure DevOps commit.
+type GitUserDate struct {
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+	Date  time.Time `json:"date"`
+}
+
+// Commit represents an Azure DevOps API response for a commit.
+type Commit struct {
+	CommitID  string      `json:"commitId"`
+	Author    GitUserDate `json:"author"`
+	Comment   string      `json:"comment"`
+	RemoteURL string      `json:"remoteUrl"`
+}
+
+// Item represents an Azure DevOps API response for a file or a folder.
+type Item struct {
+	ObjectID string `json:"objectId"`
+	// Available values: "blob", "tree"
+	GitObjectType string `json:"gitObjectType"`
+	CommitID      string `json:"commitId"`
+	Path          string `json:"path"`
+	IsFolder      bool   `json:"isFolder"`
+	Content       string `json:"content"`
+}
+
+// Change represents an Azure DevOps API response for a changed file.
+type Change struct {
+	Item Item `json:"item"`
+	// ChangeType is a comma-separated list of the change types, e.g. "add", "edit", "delete", "rename" or "edit, rename".
+	ChangeType string `json:"changeType"`
+	// SourceServerItem is the old path of the renamed file.
+	SourceServerItem string `json:"sourceServerItem"`
+}
+
+// CommitDiffs represents an Azure DevOps API response for the diff between two commits.
+type CommitDiffs struct {
+	AllChangesIncluded bool     `json:"allChangesIncluded"`
+	Changes            []Change `json:"changes"`
+}
+
+// Ref represents an Azure DevOps API message for a ref.
+type Ref struct {
+	Name     string `json:"name"`
+	ObjectID string `json:"objectId"`
+}
+
+// RefUpdate represents an Azure DevOps API message for updating a ref.
+type RefUpdate struct {
+	Name        string `json:"name"`
+	OldObjectID string `json:"oldObjectId"`
+	NewObjectID string `json:"newObjectId,omitempty"`
+}
+
+// RefUpdateResult represents an Azure DevOps API response for a ref update.
+type RefUpdateResult struct {
+	Name          string `json:"name"`
+	Success       bool   `json:"success"`
+	CustomMessage string `json:"customMessage"`
+	UpdateStatus  string `json:"updateStatus"`
+}
+
+// ItemContent is the new content of a file in an Azure DevOps push.
+type ItemContent struct {
+	Content string `json:"content"`
+	// Available values: "rawtext", "base64encoded"
+	ContentType string `json:"contentType"`
+}
+
+// PushChange is a file change in an Azure DevOps push.
+type PushChange struct {
+	// Available values: "add", "edit", "delete"
+	ChangeType string `json:"changeType"`
+	Item       struct {
+		Path string `json:"path"`
+	} `json:"item"`
+	NewContent *ItemContent `json:"newContent,omitempty"`
+}
+
+// PushCommit is a commit in an Azure DevOps push.
+type PushCommit struct {
+	Comment string       `json:"comment"`
+	Changes []PushChange `json:"changes"`
+}
+
+// Push represents an Azure DevOps API request for a push.
+type Push struct {
+	RefUpdates []RefUpdate  `json:"refUpdates"`
+	Commits    []PushCommit `json:"commits"`
+}
+
+// PullRequest represents an Azure DevOps API message for a pull request.
+type PullRequest struct {
+	PullRequestID         int         `json:"pullRequestId,omitempty"`
+	Title                 string      `json:"title"`
+	Description           string      `json:"description"`
+	SourceRefName         string      `json:"sourceRefName"`
+	TargetRefName         string      `json:"targetRefName"`
+	Repository            *Repository `json:"repository,omitempty"`
+	LastMergeSourceCommit *Commit     `json:"lastMergeSourceCommit,omitempty"`
+	LastMergeTargetCommit *Commit     `json:"lastMergeTargetCommit,omitempty"`
+	CompletionOptions     *struct {
+		DeleteSourceBranch bool `json:"deleteSourceBranch"`
+	} `json:"completionOptions,omitempty"`
+}
+
+// VariableValue is the value of a variable in an Azure DevOps variable group.
+type VariableValue struct {
+	Value    string `json:"value"`
+	IsSecret bool   `json:"isSecret"`
+}
+
+// VariableGroupProjectReference is the project reference of an Azure DevOps variable group.
+type VariableGroupProjectReference struct {
+	Name             string  `json:"name"`
+	ProjectReference Project `json:"projectReference"`
+}
+
+// VariableGroup represents an Azure DevOps API message for a variable group.
+type VariableGroup struct {
+	ID        int                      `json:"id,omitempty"`
+	Name      string                   `json:"name"`
+	Type      string                   `json:"type"`
+	Variables map[string]VariableValue `json:"variables"`
+	// VariableGroupProjectReferences is required when creating or updating the variable group.
+	VariableGroupProjectReferences []VariableGroupProjectReference `json:"variableGroupProjectReferences,omitempty"`
+}
+
+// WebhookPublisherInputs is the filter of the events in the Azure DevOps service hook.
+type WebhookPublisherInputs struct {
+	// ProjectID is the GUID of the project, which is filled by CreateWebhook and PatchWebhook.
+	ProjectID string `json:"projectId"`
+	// Repository is the GUID of the repository, which is filled by CreateWebhook and PatchWebhook.
+	Repository string `json:"repository"`
+	// Branch is the branch to filter the events, or empty for all branches.
+	Branch string `json:"branch,omitempty"`
+}
+
+// WebhookConsumerInputs is the HTTP request sent by the Azure DevOps service hook.
+type WebhookConsumerInputs struct {
+	// URL is the URL to which the payloads will be delivered.
+	URL string `json:"url"`
+	// HTTPHeaders is the newline-separated list of "Key:Value" headers sent with
+	// each payload, e.g. the WebhookSecretHeader.
+	HTTPHeaders string `json:"httpHeaders,omitempty"`
+}
+
+// WebhookCreateOrUpdate represents an Azure DevOps API request for creating or
+// updating a service hook subscription.
+type WebhookCreateOrUpdate struct {
+	// PublisherID is always "tfs".
+	PublisherID string `json:"publisherId"`
+	// EventType determines what events the hook is triggered for, e.g. "git.push".
+	EventType       string                 `json:"eventType"`
+	ResourceVersion string                 `json:"resourceVersion"`
+	PublisherInputs WebhookPublisherInputs `json:"publisherInputs"`
+	// ConsumerID is always "webHooks".
+	ConsumerID string `json:"consumerId"`
+	// ConsumerActionID is always "httpRequest".
+	ConsumerActionID string                `json:"consumerActionId"`
+	ConsumerInputs   WebhookConsumerInputs `json:"consumerInputs"`
+}
+
+// WebhookInfo represents an Azure DevOps API response for the service hook subscription.
+type WebhookInfo struct {
+	ID string `json:"id"`
+}
+
+// WebhookPushEvent is the API message for Azure DevOps service hook push event.
+//
+// Docs: https://learn.microsoft.com/en-us/azure/devops/service-hooks/events#git.push
+type WebhookPushEvent struct {
+	EventType string `json:"eventType"`
+	Resource  struct {
+		RefUpdates []RefUpdate `json:"refUpdates"`
+		Repository Repository  `json:"repository"`
+		PushedBy   struct {
+			DisplayName string `json:"displayName"`
+		} `json:"pushedBy"`
+	} `json:"resource"`
+}
+
+// listResponse is the Azure DevOps API response for a list of resources.
+type listResponse struct {
+	Count int             `json:"count"`
+	Value json.RawMessage `json:"value"`
+}
+
+// oauthResponse is an Azure DevOps OAuth response.
+type oauthResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	// ExpiresIn is returned as a string by Azure DevOps, e.g. "3599".
+	ExpiresIn        json.Number `json:"expires_in"`
+	Error            string      `json:"error,omitempty"`
+	ErrorDescription string      `json:"error_description,omitempty"`
+}
+
+// toVCSOAuthToken converts the response to *vcs.OAuthToken.
+func (o oauthResponse) toVCSOAuthToken() *vcs.OAuthToken {
+	expiresIn, _ := o.ExpiresIn.Int64()
+	oauthToken := &vcs.OAuthToken{
+		AccessToken:  o.AccessToken,
+		RefreshToken: o.RefreshToken,
+		ExpiresIn:    expiresIn,
+		CreatedAt:    time.Now().Unix(),
+	}
+	if oauthToken.ExpiresIn != 0 {
+		oauthToken.ExpiresTs = oauthToken.CreatedAt + oauthToken.ExpiresIn
+	}
+	return oauthToken
+}
+
+// requestOAuthToken sends the form to the OAuth token endpoint, which is used
+// for both exchanging and refreshing the token.
+func requestOAuthToken(ctx context.Context, client *http.Client, instanceURL string, params url.Values) (*oauthResponse, error) {
+	params.Set("client_assertion_type", "urn:ietf:params:oauth:client-assertion-type:jwt-bearer")
+	url := fmt.Sprintf("%s/oauth2/token", vsspsURL(instanceURL))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, errors.Wrapf(err, "construct POST %s", url)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "POST %s", url)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read OAuth response body, code %v", resp.StatusCode)
+	}
+
+	oauthResp := new(oauthResponse)
+	if err := json.Unmarshal(body, oauthResp); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal OAuth response body, code %v", resp.StatusCode)
+	}
+	if oauthResp.Error != "" {
+		return nil, errors.Errorf("failed to request OAuth token, error: %v, error_description: %v", oauthResp.Error, oauthResp.ErrorDescription)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("non-200 POST %s status code %d with body %q", url, resp.StatusCode, body)
+	}
+	return oauthResp, nil
+}
+
+// splitRepositoryID splits the repository ID into the organization, the
+// project and the repository name.
+func splitRepositoryID(repositoryID string) (string, string, string, error) {
+	parts := strings.Split(repositoryID, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", errors.Errorf("invalid Azure DevOps repository ID %q, expecting the format %q", repositoryID, "{organization}/{project}/{repository}")
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+// repositoryAPIURL returns the API URL of the given repository.
+func (p *Provider) repositoryAPIURL(instanceURL, repositoryID string) (string, error) {
+	organization, project, repository, err := splitRepositoryID(repositoryID)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/%s/%s/_apis/git/repositories/%s", p.APIURL(instanceURL), url.PathEscape(organization), url.PathEscape(project), url.PathEscape(repository)), nil
+}
+
+// apiQuery returns the query string of the API request with the API version.
+func apiQuery(params url.Values) string {
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("api-version", apiVersion)
+	return params.Encode()
+}
+
+// versionDescriptor returns the query parameters to specify the version of
+// the items, which is either a commit ID or a branch name.
+func versionDescriptor(params url.Values, ref string) {
+	versionType := "branch"
+	if isCommitID(ref) {
+		versionType = "commit"
+	}
+	params.Set("versionDescriptor.version", ref)
+	params.Set("versionDescriptor.versionType", versionType)
+}
+
+// isCommitID returns true if the ref is a full commit SHA.
+func isCommitID(ref string) bool {
+	if len(ref) != len(emptyCommitID) {
+		return false
+	}
+	for _, c := range ref {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// splitCommitMessage returns the title of the commit message.
+func splitCommitMessage(message string) string {
+	// Per Git convention, the message title and body are separated by two new line characters.
+	return strings.SplitN(message, "\n\n", 2)[0]
+}
+
+// get sends the GET request to the given URL and returns the response body.
+func (p *Provider) get(ctx context.Context, oauthCtx common.OauthContext, instanceURL, url, resource string) (string, error) {
+	code, _, body, err := oauth.Get(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		tokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return "", errors.Wrapf(err, "GET %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return "", common.Errorf(common.NotFound, "failed to fetch %s from URL %s", resource, url)
+	} else if code >= 300 {
+		return "", errors.Errorf("failed to fetch %s from URL %s, status code: %d, body: %s",
+			resource,
+			url,
+			code,
+			body,
+		)
+	}
+	return body, nil
+}
+
+// getList sends the GET request to the given URL and unmarshals the values of
+// the list response into the given pointer to a slice.
+func (p *Provider) getList(ctx context.Context, oauthCtx common.OauthContext, instanceURL, url, resource string, values any) error {
+	body, err := p.get(ctx, oauthCtx, instanceURL, url, resource)
+	if err != nil {
+		return err
+	}
+
+	var resp listResponse
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		return errors.Wrap(err, "unmarshal body")
+	}
+	if len(resp.Value) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Value, values); err != nil {
+		return errors.Wrapf(err, "unmarshal %s", resource)
+	}
+	return nil
+}
+
+// ExchangeOAuthToken exchanges OAuth content with the provided authorization code.
+//
+// Docs: https://learn.microsoft.com/en-us/azure/devops/integrate/get-started/authentication/oauth#get-an-access-and-refresh-token-for-the-user
+func (p *Provider) ExchangeOAuthToken(ctx context.Context, instanceURL string, oauthExchange *common.OAuthExchange) (*vcs.OAuthToken, error) {
+	params := url.Values{}
+	params.Set("client_assertion", oauthExchange.ClientSecret)
+	params.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	params.Set("assertion", oauthExchange.Code)
+	params.Set("redirect_uri", oauthExchange.RedirectURL)
+	oauthResp, err := requestOAuthToken(ctx, p.client, instanceURL, params)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to exchange OAuth token")
+	}
+	return oauthResp.toVCSOAuthToken(), nil
+}
+
+// TryLogin tries to fetch the user info from the current OAuth context.
+//
+// Docs: https://learn.microsoft.com/en-us/rest/api/azure/devops/profile/profiles/get
+func (p *Provider) TryLogin(ctx context.Context, oauthCtx common.OauthContext, instanceURL string) (*vcs.UserInfo, error) {
+	profile, err := p.fetchProfile(ctx, oauthCtx, instanceURL, "me")
+	if err != nil {
+		return nil, err
+	}
+	return &vcs.UserInfo{
+		PublicEmail: profile.EmailAddress,
+		Name:        profile.DisplayName,
+		State:       vcs.StateActive,
+	}, nil
+}
+
+// fetchProfile fetches the profile of the given user ID, or "me" for the
+// current user.
+func (p *Provider) fetchProfile(ctx context.Context, oauthCtx common.OauthContext, instanceURL, userID string) (*Profile, error) {
+	url := fmt.Sprintf("%s/_apis/profile/profiles/%s?%s", vsspsURL(instanceURL), url.PathEscape(userID), apiQuery(nil))
+	body, err := p.get(ctx, oauthCtx, instanceURL, url, "user profile")
+	if err != nil {
+		return nil, err
+	}
+
+	var profile Profile
+	if err := json.Unmarshal([]byte(body), &profile); err != nil {
+		return nil, errors.Wrap(err, "unmarshal")
+	}
+	return &profile, nil
+}
+
+// FetchCommitByID fetches the commit data by its ID from the repository.
+//
+// Docs: https://learn.microsoft.com/en-us/rest/api/azure/devops/git/commits/get
+func (p *Provider) FetchCommitByID(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, commitID string) (*vcs.Commit, error) {
+	repositoryURL, err := p.repositoryAPIURL(instanceURL, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s/commits/%s?%s", repositoryURL, url.PathEscape(commitID), apiQuery(nil))
+	body, err := p.get(ctx, oauthCtx, instanceURL, url, "commit data")
+	if err != nil {
+		return nil, err
+	}
+
+	var commit Commit
+	if err := json.Unmarshal([]byte(body), &commit); err != nil {
+		return nil, errors.Wrap(err, "unmarshal body")
+	}
+	return &vcs.Commit{
+		ID:          commit.CommitID,
+		Title:       splitCommitMessage(commit.Comment),
+		Message:     commit.Comment,
+		CreatedTs:   commit.Author.Date.Unix(),
+		URL:         commit.RemoteURL,
+		AuthorName:  commit.Author.Name,
+		AuthorEmail: commit.Author.Email,
+	}, nil
+}
+
+// GetDiffFileList gets the diff files list between two commits.
+//
+// Docs: https://learn.microsoft.com/en-us/rest/api/azure/devops/git/diffs/get
+func (p *Provider) GetDiffFileList(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, beforeCommit, afterCommit string) ([]vcs.FileDiff, error) {
+	repositoryURL, err := p.repositoryAPIURL(instanceURL, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []vcs.FileDiff
+	for skip := 0; ; skip += apiPageSize {
+		params := url.Values{}
+		params.Set("baseVersion", beforeCommit)
+		params.Set("baseVersionType", "commit")
+		params.Set("targetVersion", afterCommit)
+		params.Set("targetVersionType", "commit")
+		params.Set("$top", strconv.Itoa(apiPageSize))
+		params.Set("$skip", strconv.Itoa(skip))
+		url := fmt.Sprintf("%s/diffs/commits?%s", repositoryURL, apiQuery(params))
+		body, err := p.get(ctx, oauthCtx, instanceURL, url, "file diff list")
+		if err != nil {
+			return nil, err
+		}
+
+		var diffs CommitDiffs
+		if err := json.Unmarshal([]byte(body), &diffs); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal file diff data from Azure DevOps instance %s", instanceURL)
+		}
+		ret = append(ret, convertChangeList(diffs.Changes)...)
+		if diffs.AllChangesIncluded || len(diffs.Changes) < apiPageSize {
+			break
+		}
+	}
+	return ret, nil
+}
+
+// convertChangeList converts the Azure DevOps changes to the file diffs. A
+// renamed file is treated as the old file is removed and the new file is added.
+func convertChangeList(changes []Change) []vcs.FileDiff {
+	var ret []vcs.FileDiff
+	for _, change := range changes {
+		if change.Item.IsFolder || change.Item.GitObjectType == "tree" {
+			continue
+		}
+		filePath := strings.TrimPrefix(change.Item.Path, "/")
+		changeType := change.ChangeType
+		switch {
+		case strings.Contains(changeType, "delete"):
+			ret = append(ret, vcs.FileDiff{Path: filePath, Type: vcs.FileDiffTypeRemoved})
+		case strings.Contains(changeType, "rename"):
+			ret = append(ret,
+				vcs.FileDiff{Path: strings.TrimPrefix(change.SourceServerItem, "/"), Type: vcs.FileDiffTypeRemoved},
+				vcs.FileDiff{Path: filePath, Type: vcs.FileDiffTypeAdded},
+			)
+		case strings.Contains(changeType, "add"):
+			ret = append(ret, vcs.FileDiff{Path: filePath, Type: vcs.FileDiffTypeAdded})
+		case strings.Contains(changeType, "edit"):
+			ret = append(ret, vcs.FileDiff{Path: filePath, Type: vcs.FileDiffTypeModified})
+		}
+	}
+	return ret
+}
+
+// FetchUserInfo fetches user info of given user ID.
+//
+// Docs: https://learn.microsoft.com/en-us/rest/api/azure/devops/profile/profiles/get
+func (p *Provider) FetchUserInfo(ctx context.Context, oauthCtx common.OauthContext, instanceURL, userID string) (*vcs.UserInfo, error) {
+	profile, err := p.fetchProfile(ctx, oauthCtx, instanceURL, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &vcs.UserInfo{
+		PublicEmail: profile.EmailAddress,
+		Name:        profile.DisplayName,
+		State:       vcs.StateActive,
+	}, nil
+}
+
+// FetchRepositoryActiveMemberList fetch all active members of a repository.
+//
+// NOTE: Azure DevOps grants the repository permissions through the security
+// groups of the project instead of the repository members, thus we cannot sync
+// the members from Azure DevOps.
+func (*Provider) FetchRepositoryActiveMemberList(_ context.Context, _ common.OauthContext, _, _ string) ([]*vcs.RepositoryMember, error) {
+	return nil, common.Errorf(common.NotImplemented, "syncing repository members is not supported by Azure DevOps because the permissions are granted through the security groups")
+}
+
+// FetchAllRepositoryList fetches all repositories in the organizations which
+// the authenticated user is a member of.
+//
+// Docs: https://learn.microsoft.com/en-us/rest/api/azure/devops/git/repositories/list
+func (p *Provider) FetchAllRepositoryList(ctx context.Context, oauthCtx common.OauthContext, instanceURL string) ([]*vcs.Repository, error) {
+	profile, err := p.fetchProfile(ctx, oauthCtx, instanceURL, "me")
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch profile")
+	}
+
+	// Docs: https://learn.microsoft.com/en-us/rest/api/azure/devops/account/accounts/list
+	params := url.Values{}
+	params.Set("memberId", profile.ID)
+	accountsURL := fmt.Sprintf("%s/_apis/accounts?%s", vsspsURL(instanceURL), apiQuery(params))
+	var accounts []Account
+	if err := p.getList(ctx, oauthCtx, instanceURL, accountsURL, "organization list", &accounts); err != nil {
+		return nil, err
+	}
+
+	var allRepos []*vcs.Repository
+	for _, account := range accounts {
+		url := fmt.Sprintf("%s/%s/_apis/git/repositories?%s", p.APIURL(instanceURL), url.PathEscape(account.AccountName), apiQuery(nil))
+		var repos []Repository
+		if err := p.getList(ctx, oauthCtx, instanceURL, url, "repository list", &repos); err != nil {
+			return nil, err
+		}
+		for _, r := range repos {
+			// The GUID of Azure DevOps repository is not numeric, so we use the
+			// full path as the external ID of the repository.
+			allRepos = append(allRepos,
+				&vcs.Repository{
+					Name:     r.Name,
+					FullPath: fmt.Sprintf("%s/%s/%s", account.AccountName, r.Project.Name, r.Name),
+					WebURL:   r.WebURL,
+				},
+			)
+		}
+	}
+	return allRepos, nil
+}
+
+// FetchRepositoryFileList fetches the all files from the given repository tree
+// recursively.
+//
+// Docs: https://learn.microsoft.com/en-us/rest/api/azure/devops/git/items/list
+func (p *Provider) FetchRepositoryFileList(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, ref, filePath string) ([]*vcs.RepositoryTreeNode, error) {
+	repositoryURL, err := p.repositoryAPIURL(instanceURL, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("scopePath", "/"+strings.Trim(filePath, "/"))
+	params.Set("recursionLevel", "Full")
+	versionDescriptor(params, ref)
+	url := fmt.Sprintf("%s/items?%s", repositoryURL, apiQuery(params))
+	var items []Item
+	if err := p.getList(ctx, oauthCtx, instanceURL, url, "repository file list", &items); err != nil {
+		return nil, err
+	}
+
+	var allTreeNodes []*vcs.RepositoryTreeNode
+	for _, item := range items {
+		if item.IsFolder || item.GitObjectType != "blob" {
+			continue
+		}
+		allTreeNodes = append(allTreeNodes,
+			&vcs.RepositoryTreeNode{
+				Path: strings.TrimPrefix(item.Path, "/"),
+				Type: "blob",
+			},
+		)
+	}
+	return allTreeNodes, nil
+}
+
+// CreateFile creates a file at given path in the repository.
+//
+// Docs: https://learn.microsoft.com/en-us/rest/api/azure/devops/git/pushes/create
+func (p *Provider) CreateFile(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, filePath string, fileCommitCreate vcs.FileCommitCreate) error {
+	return p.pushFile(ctx, oauthCtx, instanceURL, repositoryID, filePath, "add", fileCommitCreate)
+}
+
+// OverwriteFile overwrites an existing file at given path in the repository.
+//
+// Docs: https://learn.microsoft.com/en-us/rest/api/azure/devops/git/pushes/create
+func (p *Provider) OverwriteFile(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, filePath string, fileCommitCreate vcs.FileCommitCreate) error {
+	return p.pushFile(ctx, oauthCtx, instanceURL, repositoryID, filePath, "edit", fileCommitCreate)
+}
+
+// pushFile pushes a commit with the given file change to the branch.
+//
+// NOTE: Azure DevOps detects the conflicting writes by the latest commit of
+// the branch instead of the file, so the LastCommitID of the file commit is
+// ignored.
+func (p *Provider) pushFile(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, filePath, changeType string, fileCommitCreate vcs.FileCommitCreate) error {
+	branch, err := p.GetBranch(ctx, oauthCtx, instanceURL, repositoryID, fileCommitCreate.Branch)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get branch %s", fileCommitCreate.Branch)
+	}
+
+	change := PushChange{
+		ChangeType: changeType,
+		NewContent: &ItemContent{
+			Content:     fileCommitCreate.Content,
+			ContentType: "rawtext",
+		},
+	}
+	change.Item.Path = "/" + strings.TrimPrefix(filePath, "/")
+	push := Push{
+		RefUpdates: []RefUpdate{
+			{
+				Name:        fmt.Sprintf("refs/heads/%s", fileCommitCreate.Branch),
+				OldObjectID: branch.LastCommitID,
+			},
+		},
+		Commits: []PushCommit{
+			{
+				Comment: fileCommitCreate.CommitMessage,
+				Changes: []PushChange{change},
+			},
+		},
+	}
+	body, err := json.Marshal(push)
+	if err != nil {
+		return errors.Wrap(err, "marshal push")
+	}
+
+	repositoryURL, err := p.repositoryAPIURL(instanceURL, repositoryID)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/pushes?%s", repositoryURL, apiQuery(nil))
+	code, _, resp, err := oauth.Post(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		bytes.NewReader(body),
+		tokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to create/update file through URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to create/update file through URL %s, status code: %d, body: %s",
+			url,
+			code,
+			resp,
+		)
+	}
+	return nil
+}
+
+// ReadFileMeta reads the metadata of the given file in the repository.
+//
+// Docs: https://learn.microsoft.com/en-us/rest/api/azure/devops/git/items/get
+func (p *Provider) ReadFileMeta(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, filePath, ref string) (*vcs.FileMeta, error) {
+	item, err := p.readFile(ctx, oauthCtx, instanceURL, repositoryID, filePath, ref)
+	if err != nil {
+		return nil, errors.Wrap(err, "read file")
+	}
+
+	filePath = strings.TrimPrefix(item.Path, "/")
+	return &vcs.FileMeta{
+		Name:         path.Base(filePath),
+		Path:         filePath,
+		Size:         int64(len(item.Content)),
+		LastCommitID: item.CommitID,
+	}, nil
+}
+
+// ReadFileContent reads the content of the given file in the repository.
+//
+// Docs: https://learn.microsoft.com/en-us/rest/api/azure/devops/git/items/get
+func (p *Provider) ReadFileContent(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, filePath, ref string) (string, error) {
+	item, err := p.readFile(ctx, oauthCtx, instanceURL, repositoryID, filePath, ref)
+	if err != nil {
+		return "", errors.Wrap(err, "read file")
+	}
+	return item.Content, nil
+}
+
+// readFile reads the file with its content and the latest commit ID in the repository.
+func (p *Provider) readFile(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, filePath, ref string) (*Item, error) {
+	repositoryURL, err := p.repositoryAPIURL(instanceURL, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("path", "/"+strings.TrimPrefix(filePath, "/"))
+	params.Set("includeContent", "true")
+	params.Set("$format", "json")
+	versionDescriptor(params, ref)
+	url := fmt.Sprintf("%s/items?%s", repositoryURL, apiQuery(params))
+	body, err := p.get(ctx, oauthCtx, instanceURL, url, "file")
+	if err != nil {
+		return nil, err
+	}
+
+	var item Item
+	if err := json.Unmarshal([]byte(body), &item); err != nil {
+		return nil, errors.Wrap(err, "unmarshal body")
+	}
+	if item.IsFolder || item.GitObjectType == "tree" {
+		return nil, errors.Errorf("%q is a directory not a file", filePath)
+	}
+	return &item, nil
+}
+
+// ListPullRequestFile lists the changed files in the pull request.
+//
+// Docs: https://learn.microsoft.com/en-us/rest/api/azure/devops/git/pull-requests/get-pull-request
+func (p *Provider) ListPullRequestFile(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, pullRequestID string) ([]*vcs.PullRequestFile, error) {
+	repositoryURL, err := p.repositoryAPIURL(instanceURL, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s/pullrequests/%s?%s", repositoryURL, url.PathEscape(pullRequestID), apiQuery(nil))
+	body, err := p.get(ctx, oauthCtx, instanceURL, url, "pull request")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get pull request")
+	}
+
+	var pullRequest PullRequest
+	if err := json.Unmarshal([]byte(body), &pullRequest); err != nil {
+		return nil, errors.Wrap(err, "unmarshal body")
+	}
+	if pullRequest.LastMergeSourceCommit == nil || pullRequest.LastMergeTargetCommit == nil {
+		return nil, errors.Errorf("cannot find the source and target commits of pull request %s", pullRequestID)
+	}
+
+	fileDiffList, err := p.GetDiffFileList(ctx, oauthCtx, instanceURL, repositoryID, pullRequest.LastMergeTargetCommit.CommitID, pullRequest.LastMergeSourceCommit.CommitID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list pull request file")
+	}
+	var res []*vcs.PullRequestFile
+	for _, fileDiff := range fileDiffList {
+		res = append(res, &vcs.PullRequestFile{
+			Path:         fileDiff.Path,
+			LastCommitID: pullRequest.LastMergeSourceCommit.CommitID,
+			IsDeleted:    fileDiff.Type == vcs.FileDiffTypeRemoved,
+		})
+	}
+	return res, nil
+}
+
+// GetBranch gets the given branch in the repository.
+//
+// Docs: https://learn.microsoft.com/en-us/rest/api/azure/devops/git/refs/list
+func (p *Provider) GetBranch(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, branchName string) (*vcs.BranchInfo, error) {
+	repositoryURL, err := p.repositoryAPIURL(instanceURL, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	// The filter matches the refs starting with the given prefix.
+	params.Set("filter", fmt.Sprintf("heads/%s", branchName))
+	url := fmt.Sprintf("%s/refs?%s", repositoryURL, apiQuery(params))
+	var refs []Ref
+	if err := p.getList(ctx, oauthCtx, instanceURL, url, "branch", &refs); err != nil {
+		return nil, err
+	}
+
+	refName := fmt.Sprintf("refs/heads/%s", branchName)
+	for _, ref := range refs {
+		if ref.Name == refName {
+			return &vcs.BranchInfo{
+				Name:         branchName,
+				LastCommitID: ref.ObjectID,
+			}, nil
+		}
+	}
+	return nil, common.Errorf(common.NotFound, "failed to find branch %q from URL %s", branchName, url)
+}
+
+// CreateBranch creates the branch in the repository.
+//
+// Docs: https://learn.microsoft.com/en-us/rest/api/azure/devops/git/refs/update-refs
+func (p *Provider) CreateBranch(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID string, branch *vcs.BranchInfo) error {
+	body, err := json.Marshal([]RefUpdate{
+		{
+			Name:        fmt.Sprintf("refs/heads/%s", branch.Name),
+			OldObjectID: emptyCommitID,
+			NewObjectID: branch.LastCommitID,
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "marshal branch create")
+	}
+
+	repositoryURL, err := p.repositoryAPIURL(instanceURL, repositoryID)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/refs?%s", repositoryURL, apiQuery(nil))
+	code, _, resp, err := oauth.Post(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		bytes.NewReader(body),
+		tokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to create branch from URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to create branch from URL %s, status code: %d, body: %s",
+			url,
+			code,
+			resp,
+		)
+	}
+
+	// Azure DevOps returns 200 even if the ref update is rejected, e.g. the branch already exists.
+	var results []RefUpdateResult
+	var list listResponse
+	if err := json.Unmarshal([]byte(resp), &list); err != nil {
+		return errors.Wrap(err, "unmarshal body")
+	}
+	if err := json.Unmarshal(list.Value, &results); err != nil {
+		return errors.Wrap(err, "unmarshal ref update results")
+	}
+	for _, result := range results {
+		if !result.Success {
+			return errors.Errorf("failed to create branch %s, status: %s, message: %s", branch.Name, result.UpdateStatus, result.CustomMessage)
+		}
+	}
+	return nil
+}
+
+// CreatePullRequest creates the pull request in the repository.
+//
+// Docs: https://learn.microsoft.com/en-us/rest/api/azure/devops/git/pull-requests/create
+func (p *Provider) CreatePullRequest(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID string, pullRequestCreate *vcs.PullRequestCreate) (*vcs.PullRequest, error) {
+	pullRequest := PullRequest{
+		Title:         pullRequestCreate.Title,
+		Description:   pullRequestCreate.Body,
+		SourceRefName: fmt.Sprintf("refs/heads/%s", pullRequestCreate.Head),
+		TargetRefName: fmt.Sprintf("refs/heads/%s", pullRequestCreate.Base),
+	}
+	if pullRequestCreate.RemoveHeadAfterMerged {
+		pullRequest.CompletionOptions = &struct {
+			DeleteSourceBranch bool `json:"deleteSourceBranch"`
+		}{DeleteSourceBranch: true}
+	}
+	body, err := json.Marshal(pullRequest)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal pull request create")
+	}
+
+	repositoryURL, err := p.repositoryAPIURL(instanceURL, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s/pullrequests?%s", repositoryURL, apiQuery(nil))
+	code, _, resp, err := oauth.Post(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		bytes.NewReader(body),
+		tokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return nil, common.Errorf(common.NotFound, "failed to create pull request from URL %s", url)
+	} else if code >= 300 {
+		return nil, errors.Errorf("failed to create pull request from URL %s, status code: %d, body: %s",
+			url,
+			code,
+			resp,
+		)
+	}
+
+	var res PullRequest
+	if err := json.Unmarshal([]byte(resp), &res); err != nil {
+		return nil, err
+	}
+	if res.Repository == nil {
+		return nil, errors.Errorf("missing the repository of the created pull request")
+	}
+
+	return &vcs.PullRequest{
+		URL: fmt.Sprintf("%s/pullrequest/%d", res.Repository.WebURL, res.PullRequestID),
+	}, nil
+}
+
+// PullRequestThreadComment is the API message for a comment in the pull
+// request thread.
+type PullRequestThreadComment struct {
+	ParentCommentID int    `json:"parentCommentId"`
+	Content         string `json:"content"`
+	// CommentType 1 is the text comment.
+	CommentType int `json:"commentType"`
+}
+
+// PullRequestThreadCreate is the API message for creating a pull request
+// thread.
+type PullRequestThreadCreate struct {
+	Comments []PullRequestThreadComment `json:"comments"`
+	// Status 1 is the active thread.
+	Status int `json:"status"`
+}
+
+// CreatePullRequestComment creates a comment in the pull request, which is a
+// new thread with a single comment in Azure DevOps.
+//
+// Docs: https://learn.microsoft.com/en-us/rest/api/azure/devops/git/pull-request-threads/create
+func (p *Provider) CreatePullRequestComment(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, pullRequestID, comment string) error {
+	body, err := json.Marshal(
+		PullRequestThreadCreate{
+			Comments: []PullRequestThreadComment{
+				{
+					ParentCommentID: 0,
+					Content:         comment,
+					CommentType:     1,
+				},
+			},
+			Status: 1,
+		},
+	)
+	if err != nil {
+		return errors.Wrap(err, "marshal pull request thread create")
+	}
+
+	repositoryURL, err := p.repositoryAPIURL(instanceURL, repositoryID)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/pullRequests/%s/threads?%s", repositoryURL, pullRequestID, apiQuery(nil))
+	code, _, resp, err := oauth.Post(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		bytes.NewReader(body),
+		tokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to create pull request comment from URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to create pull request comment from URL %s, status code: %d, body: %s",
+			url,
+			code,
+			resp,
+		)
+	}
+	return nil
+}
+
+// CommitStatusContext is the API message for the context of the commit status.
+type CommitStatusContext struct {
+	Genre string `json:"genre,omitempty"`
+	Name  string `json:"name"`
+}
+
+// CommitStatusCreate is the API message for creating a commit status.
+type CommitStatusCreate struct {
+	State       string              `json:"state"`
+	Description string              `json:"description,omitempty"`
+	TargetURL   string              `json:"targetUrl,omitempty"`
+	Context     CommitStatusContext `json:"context"`
+}
+
+// CreateCommitStatus creates the status of the commit. The context of the
+// status like "bytebase/sql-review" is split into the genre "bytebase" and the
+// name "sql-review".
+//
+// Docs: https://learn.microsoft.com/en-us/rest/api/azure/devops/git/statuses/create
+func (p *Provider) CreateCommitStatus(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, commitID string, status *vcs.CommitStatus) error {
+	var state string
+	switch status.State {
+	case vcs.CommitStatePending:
+		state = "pending"
+	case vcs.CommitStateSuccess:
+		state = "succeeded"
+	case vcs.CommitStateFailure:
+		state = "failed"
+	default:
+		return errors.Errorf("unexpected commit state %q", status.State)
+	}
+	statusContext := CommitStatusContext{Name: status.Context}
+	if genre, name, ok := strings.Cut(status.Context, "/"); ok {
+		statusContext = CommitStatusContext{Genre: genre, Name: name}
+	}
+	body, err := json.Marshal(
+		CommitStatusCreate{
+			State:       state,
+			Description: status.Description,
+			TargetURL:   status.TargetURL,
+			Context:     statusContext,
+		},
+	)
+	if err != nil {
+		return errors.Wrap(err, "marshal commit status create")
+	}
+
+	repositoryURL, err := p.repositoryAPIURL(instanceURL, repositoryID)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/commits/%s/statuses?%s", repositoryURL, commitID, apiQuery(nil))
+	code, _, resp, err := oauth.Post(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		bytes.NewReader(body),
+		tokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to create commit status from URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to create commit status from URL %s, status code: %d, body: %s",
+			url,
+			code,
+			resp,
+		)
+	}
+	return nil
+}
+
+// ListPullRequest lists the open pull requests to the base branch in the repository.
+//
+// NOTE: It's only used to write back the latest schema by pull requests, which
+// is not supported for Azure DevOps yet.
+func (*Provider) ListPullRequest(_ context.Context, _ common.OauthContext, _, _, _ string) ([]*vcs.PullRequest, error) {
+	return nil, common.Errorf(common.NotImplemented, "listing pull requests is not supported for Azure DevOps")
+}
+
+// ClosePullRequest closes the pull request.
+//
+// NOTE: It's only used to write back the latest schema by pull requests, which
+// is not supported for Azure DevOps yet.
+func (*Provider) ClosePullRequest(_ context.Context, _ common.OauthContext, _, _, _ string) error {
+	return common.Errorf(common.NotImplemented, "closing pull requests is not supported for Azure DevOps")
+}
+
+// UpsertEnvironmentVariable creates or updates the pipeline variable in the
+// VariableGroupName variable group of the repository's project. The variable
+// is stored as a secret so that it is masked in the pipeline logs.
+//
+// Docs: https://learn.microsoft.com/en-us/rest/api/azure/devops/distributedtask/variablegroups
+func (p *Provider) UpsertEnvironmentVariable(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, key, value string) error {
+	organization, _, _, err := splitRepositoryID(repositoryID)
+	if err != nil {
+		return err
+	}
+	repository, err := p.getRepository(ctx, oauthCtx, instanceURL, repositoryID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get repository")
+	}
+
+	variableGroup, err := p.getVariableGroup(ctx, oauthCtx, instanceURL, organization, repository.Project.ID)
+	if err != nil {
+		if common.ErrorCode(err) != common.NotFound {
+			return err
+		}
+		variableGroup = &VariableGroup{
+			Name:      VariableGroupName,
+			Variables: map[string]VariableValue{},
+		}
+	}
+	variableGroup.Type = "Vsts"
+	variableGroup.Variables[key] = VariableValue{
+		Value:    value,
+		IsSecret: true,
+	}
+	variableGroup.VariableGroupProjectReferences = []VariableGroupProjectReference{
+		{
+			Name:             VariableGroupName,
+			ProjectReference: repository.Project,
+		},
+	}
+	body, err := json.Marshal(variableGroup)
+	if err != nil {
+		return errors.Wrap(err, "marshal variable group")
+	}
+
+	send := oauth.Post
+	variableGroupURL := fmt.Sprintf("%s/%s/_apis/distributedtask/variablegroups", p.APIURL(instanceURL), url.PathEscape(organization))
+	if variableGroup.ID != 0 {
+		send = oauth.Put
+		variableGroupURL = fmt.Sprintf("%s/%d", variableGroupURL, variableGroup.ID)
+	}
+	url := fmt.Sprintf("%s?%s", variableGroupURL, apiQuery(nil))
+	code, _, resp, err := send(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		bytes.NewReader(body),
+		tokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "upsert variable group %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to upsert variable group through URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to upsert variable group through URL %s, status code: %d, body: %s",
+			url,
+			code,
+			resp,
+		)
+	}
+	return nil
+}
+
+// getVariableGroup gets the VariableGroupName variable group in the project.
+//
+// Docs: https://learn.microsoft.com/en-us/rest/api/azure/devops/distributedtask/variablegroups/get-variable-groups
+func (p *Provider) getVariableGroup(ctx context.Context, oauthCtx common.OauthContext, instanceURL, organization, projectID string) (*VariableGroup, error) {
+	params := url.Values{}
+	params.Set("groupName", VariableGroupName)
+	url := fmt.Sprintf("%s/%s/%s/_apis/distributedtask/variablegroups?%s", p.APIURL(instanceURL), url.PathEscape(organization), url.PathEscape(projectID), apiQuery(params))
+	var variableGroups []VariableGroup
+	if err := p.getList(ctx, oauthCtx, instanceURL, url, "variable group", &variableGroups); err != nil {
+		return nil, err
+	}
+	for _, variableGroup := range variableGroups {
+		if variableGroup.Name == VariableGroupName {
+			if variableGroup.Variables == nil {
+				variableGroup.Variables = map[string]VariableValue{}
+			}
+			return &variableGroup, nil
+		}
+	}
+	return nil, common.Errorf(common.NotFound, "failed to find variable group %q from URL %s", VariableGroupName, url)
+}
+
+// getRepository gets the repository with its GUID and the project.
+//
+// Docs: https://learn.microsoft.com/en-us/rest/api/azure/devops/git/repositories/get-repository
+func (p *Provider) getRepository(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID string) (*Repository, error) {
+	repositoryURL, err := p.repositoryAPIURL(instanceURL, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s?%s", repositoryURL, apiQuery(nil))
+	body, err := p.get(ctx, oauthCtx, instanceURL, url, "repository")
+	if err != nil {
+		return nil, err
+	}
+
+	var repository Repository
+	if err := json.Unmarshal([]byte(body), &repository); err != nil {
+		return nil, errors.Wrap(err, "unmarshal body")
+	}
+	return &repository, nil
+}
+
+// fillWebhookPublisherInputs fills the GUIDs of the project and the repository
+// into the webhook payload, which are required by the service hook
+// subscription but not known by the caller.
+func (p *Provider) fillWebhookPublisherInputs(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID string, payload []byte) ([]byte, error) {
+	var webhook WebhookCreateOrUpdate
+	if err := json.Unmarshal(payload, &webhook); err != nil {
+		return nil, errors.Wrap(err, "unmarshal webhook payload")
+	}
+	repository, err := p.getRepository(ctx, oauthCtx, instanceURL, repositoryID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get repository")
+	}
+	webhook.PublisherInputs.ProjectID = repository.Project.ID
+	webhook.PublisherInputs.Repository = repository.ID
+	return json.Marshal(webhook)
+}
+
+// CreateWebhook creates a service hook subscription for the repository with
+// given payload, which should be a marshalled WebhookCreateOrUpdate.
+//
+// Docs: https://learn.microsoft.com/en-us/rest/api/azure/devops/hooks/subscriptions/create
+func (p *Provider) CreateWebhook(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID string, payload []byte) (string, error) {
+	organization, _, _, err := splitRepositoryID(repositoryID)
+	if err != nil {
+		return "", err
+	}
+	payload, err = p.fillWebhookPublisherInputs(ctx, oauthCtx, instanceURL, repositoryID, payload)
+	if err != nil {
+		return "", err
+	}
+
+	url := fmt.Sprintf("%s/%s/_apis/hooks/subscriptions?%s", p.APIURL(instanceURL), url.PathEscape(organization), apiQuery(nil))
+	code, _, body, err := oauth.Post(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		bytes.NewReader(payload),
+		tokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return "", errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return "", common.Errorf(common.NotFound, "failed to create webhook through URL %s", url)
+	} else if code >= 300 {
+		return "", errors.Errorf("failed to create webhook through URL %s, status code: %d, body: %s",
+			url,
+			code,
+			body,
+		)
+	}
+
+	var webhookInfo WebhookInfo
+	if err = json.Unmarshal([]byte(body), &webhookInfo); err != nil {
+		return "", errors.Wrap(err, "unmarshal body")
+	}
+	return webhookInfo.ID, nil
+}
+
+// PatchWebhook replaces the service hook subscription with given payload,
+// which should be a marshalled WebhookCreateOrUpdate.
+//
+// Docs: https://learn.microsoft.com/en-us/rest/api/azure/devops/hooks/subscriptions/replace-subscription
+func (p *Provider) PatchWebhook(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, webhookID string, payload []byte) error {
+	organization, _, _, err := splitRepositoryID(repositoryID)
+	if err != nil {
+		return err
+	}
+	payload, err = p.fillWebhookPublisherInputs(ctx, oauthCtx, instanceURL, repositoryID, payload)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/%s/_apis/hooks/subscriptions/%s?%s", p.APIURL(instanceURL), url.PathEscape(organization), url.PathEscape(webhookID), apiQuery(nil))
+	code, _, body, err := oauth.Put(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		bytes.NewReader(payload),
+		tokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "PUT %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to patch webhook through URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to patch webhook through URL %s, status code: %d, body: %s",
+			url,
+			code,
+			body,
+		)
+	}
+	return nil
+}
+
+// DeleteWebhook deletes the service hook subscription of the repository.
+//
+// Docs: https://learn.microsoft.com/en-us/rest/api/azure/devops/hooks/subscriptions/delete
+func (p *Provider) DeleteWebhook(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, webhookID string) error {
+	organization, _, _, err := splitRepositoryID(repositoryID)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/%s/_apis/hooks/subscriptions/%s?%s", p.APIURL(instanceURL), url.PathEscape(organization), url.PathEscape(webhookID), apiQuery(nil))
+	code, _, body, err := oauth.Delete(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		tokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "DELETE %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return nil // It is OK if the webhook has already gone
+	} else if code >= 300 {
+		return errors.Errorf("failed to delete webhook through URL %s, status code: %d, body: %s",
+			url,
+			code,
+			body,
+		)
+	}
+	return nil
+}
+
+// oauthContext is the request context for refreshing oauth token.
+type oauthContext struct {
+	// ClientSecret is the client secret of the Azure DevOps OAuth app, which is
+	// sent as the client assertion.
+	ClientSecret string
+	RefreshToken string
+}
+
+func tokenRefresher(instanceURL string, oauthCtx oauthContext, refresher common.TokenRefresher) oauth.TokenRefresher {
+	return func(ctx context.Context, client *http.Client, oldToken *string) error {
+		// The personal access token cannot be refreshed.
+		if oauthCtx.RefreshToken == "" {
+			return errors.New("the access token has expired or been revoked, and there is no refresh token to renew it")
+		}
+
+		params := url.Values{}
+		params.Set("client_assertion", oauthCtx.ClientSecret)
+		params.Set("grant_type", "refresh_token")
+		params.Set("assertion", oauthCtx.RefreshToken)
+		r, err := requestOAuthToken(ctx, client, instanceURL, params)
+		if err != nil {
+			return err
+		}
+
+		// Update the old token to new value for retries.
+		*oldToken = r.AccessToken
+
+		token := r.toVCSOAuthToken()
+		return refresher(token.AccessToken, token.RefreshToken, token.ExpiresTs)
+	}
+}
+
+// RepositoryID returns the repository ID in the format of
+// "{organization}/{project}/{repository}" of the push event.
+func (p WebhookPushEvent) RepositoryID() (string, error) {
+	repository := p.Resource.Repository
+	// The repository API URL is in the format of
+	// "https://dev.azure.com/{organization}/_apis/git/repositories/{id}".
+	u, err := url.Parse(repository.URL)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse repository URL %q", repository.URL)
+	}
+	organization, _, ok := strings.Cut(strings.TrimPrefix(u.Path, "/"), "/")
+	if !ok || organization == "" {
+		return "", errors.Errorf("failed to find the organization in repository URL %q", repository.URL)
+	}
+	return fmt.Sprintf("%s/%s/%s", organization, repository.Project.Name, repository.Name), nil
+}
+
+// ToVCS returns the push event in VCS format for each branch update. The
+// commit list is not included and should be filled by vcs.FillPushEventCommit.
+func (p WebhookPushEvent) ToVCS() ([]vcs.PushEvent, error) {
+	repositoryID, err := p.RepositoryID()
+	if err != nil {
+		return nil, err
+	}
+	// The remote URL may contain the user name, e.g. "https://org@dev.azure.com/org/project/_git/repo".
+	repositoryURL := p.Resource.Repository.RemoteURL
+	if u, err := url.Parse(repositoryURL); err == nil {
+		u.User = nil
+		repositoryURL = u.String()
+	}
+
+	var pushEventList []vcs.PushEvent
+	for _, refUpdate := range p.Resource.RefUpdates {
+		if !strings.HasPrefix(refUpdate.Name, "refs/heads/") {
+			continue
+		}
+		// Skip the created and deleted branches which have no file changes to apply.
+		if refUpdate.OldObjectID == "" || refUpdate.OldObjectID == emptyCommitID || refUpdate.NewObjectID == "" || refUpdate.NewObjectID == emptyCommitID {
+			continue
+		}
+		pushEventList = append(pushEventList, vcs.PushEvent{
+			VCSType:            vcs.AzureDevOps,
+			Ref:                refUpdate.Name,
+			Before:             refUpdate.OldObjectID,
+			After:              refUpdate.NewObjectID,
+			RepositoryID:       repositoryID,
+			RepositoryURL:      repositoryURL,
+			RepositoryFullPath: repositoryID,
+			AuthorName:         p.Resource.PushedBy.DisplayName,
+		})
+	}
+	return pushEventList, nil
+}
@@ -0,0 +1,377 @@
+package azure
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/vcs"
+)
+
+const testInstanceURL = "https://azure.example.com"
+
+func TestSplitRepositoryID(t *testing.T) {
+	organization, project, repository, err := splitRepositoryID("org/My Project/repo")
+	require.NoError(t, err)
+	assert.Equal(t, "org", organization)
+	assert.Equal(t, "My Project", project)
+	assert.Equal(t, "repo", repository)
+
+	for _, repositoryID := range []string{"", "org/repo", "org//repo", "org/project/repo/extra"} {
+		_, _, _, err := splitRepositoryID(repositoryID)
+		assert.Error(t, err, repositoryID)
+	}
+}
+
+func TestProvider_PersonalAccessToken(t *testing.T) {
+	tests := []struct {
+		token string
+		want  string
+	}{
+		{
+			token: "header.payload.signature",
+			want:  "Bearer header.payload.signature",
+		},
+		{
+			token: "pat",
+			want:  "Basic " + base64.StdEncoding.EncodeToString([]byte(":pat")),
+		},
+	}
+	for _, test := range tests {
+		p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+			assert.Equal(t, test.want, r.Header.Get("Authorization"))
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"id": "user-id", "displayName": "Jane Doe", "emailAddress": "jane@example.com"}`)),
+			}, nil
+		})
+		_, err := p.TryLogin(context.Background(), common.OauthContext{AccessToken: test.token}, testInstanceURL)
+		require.NoError(t, err)
+	}
+}
+
+func TestProvider_TryLogin(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/_apis/profile/profiles/me", r.URL.Path)
+		assert.Equal(t, apiVersion, r.URL.Query().Get("api-version"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"id": "user-id", "displayName": "Jane Doe", "publicAlias": "user-id", "emailAddress": "jane@example.com"}`)),
+		}, nil
+	})
+
+	got, err := p.TryLogin(context.Background(), common.OauthContext{}, testInstanceURL)
+	require.NoError(t, err)
+	want := &vcs.UserInfo{
+		PublicEmail: "jane@example.com",
+		Name:        "Jane Doe",
+		State:       vcs.StateActive,
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_GetDiffFileList(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/org/project/_apis/git/repositories/repo/diffs/commits", r.URL.Path)
+		assert.Equal(t, "before_sha", r.URL.Query().Get("baseVersion"))
+		assert.Equal(t, "after_sha", r.URL.Query().Get("targetVersion"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "allChangesIncluded": true,
+  "changes": [
+    {"item": {"path": "/prod", "isFolder": true, "gitObjectType": "tree"}, "changeType": "edit"},
+    {"item": {"path": "/prod/a.sql", "gitObjectType": "blob"}, "changeType": "add"},
+    {"item": {"path": "/prod/b.sql", "gitObjectType": "blob"}, "changeType": "edit"},
+    {"item": {"path": "/prod/c.sql", "gitObjectType": "blob"}, "changeType": "delete"},
+    {"item": {"path": "/prod/e.sql", "gitObjectType": "blob"}, "changeType": "edit, rename", "sourceServerItem": "/prod/d.sql"}
+  ]
+}
+`)),
+		}, nil
+	})
+
+	got, err := p.GetDiffFileList(context.Background(), common.OauthContext{}, testInstanceURL, "org/project/repo", "before_sha", "after_sha")
+	require.NoError(t, err)
+	want := []vcs.FileDiff{
+		{Path: "prod/a.sql", Type: vcs.FileDiffTypeAdded},
+		{Path: "prod/b.sql", Type: vcs.FileDiffTypeModified},
+		{Path: "prod/c.sql", Type: vcs.FileDiffTypeRemoved},
+		{Path: "prod/d.sql", Type: vcs.FileDiffTypeRemoved},
+		{Path: "prod/e.sql", Type: vcs.FileDiffTypeAdded},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_ReadFileMeta(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/org/project/_apis/git/repositories/repo/items", r.URL.Path)
+		assert.Equal(t, "/prod/a.sql", r.URL.Query().Get("path"))
+		assert.Equal(t, "main", r.URL.Query().Get("versionDescriptor.version"))
+		assert.Equal(t, "branch", r.URL.Query().Get("versionDescriptor.versionType"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"objectId": "object_sha", "gitObjectType": "blob", "commitId": "commit_sha", "path": "/prod/a.sql", "content": "SELECT 1;"}`)),
+		}, nil
+	})
+
+	got, err := p.ReadFileMeta(context.Background(), common.OauthContext{}, testInstanceURL, "org/project/repo", "prod/a.sql", "main")
+	require.NoError(t, err)
+	want := &vcs.FileMeta{
+		Name:         "a.sql",
+		Path:         "prod/a.sql",
+		Size:         9,
+		LastCommitID: "commit_sha",
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_CreateFile(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		switch r.URL.Path {
+		case "/org/project/_apis/git/repositories/repo/refs":
+			assert.Equal(t, "heads/main", r.URL.Query().Get("filter"))
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body: io.NopCloser(strings.NewReader(`
+{"count": 2, "value": [{"name": "refs/heads/main", "objectId": "main_sha"}, {"name": "refs/heads/main-dev", "objectId": "dev_sha"}]}
+`)),
+			}, nil
+		case "/org/project/_apis/git/repositories/repo/pushes":
+			assert.Equal(t, http.MethodPost, r.Method)
+			var push Push
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&push))
+			require.Len(t, push.RefUpdates, 1)
+			assert.Equal(t, "refs/heads/main", push.RefUpdates[0].Name)
+			assert.Equal(t, "main_sha", push.RefUpdates[0].OldObjectID)
+			require.Len(t, push.Commits, 1)
+			assert.Equal(t, "Add schema", push.Commits[0].Comment)
+			require.Len(t, push.Commits[0].Changes, 1)
+			change := push.Commits[0].Changes[0]
+			assert.Equal(t, "add", change.ChangeType)
+			assert.Equal(t, "/prod/.schema.sql", change.Item.Path)
+			assert.Equal(t, &ItemContent{Content: "CREATE TABLE t (id INT);", ContentType: "rawtext"}, change.NewContent)
+			return &http.Response{
+				StatusCode: http.StatusCreated,
+				Body:       io.NopCloser(strings.NewReader(`{"pushId": 1}`)),
+			}, nil
+		}
+		t.Fatalf("unexpected request %s", r.URL.Path)
+		return nil, nil
+	})
+
+	err := p.CreateFile(context.Background(), common.OauthContext{}, testInstanceURL, "org/project/repo", "prod/.schema.sql",
+		vcs.FileCommitCreate{
+			Branch:        "main",
+			Content:       "CREATE TABLE t (id INT);",
+			CommitMessage: "Add schema",
+		},
+	)
+	require.NoError(t, err)
+}
+
+func TestProvider_GetBranch_NotFound(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"count": 1, "value": [{"name": "refs/heads/main-dev", "objectId": "dev_sha"}]}`)),
+		}, nil
+	})
+
+	_, err := p.GetBranch(context.Background(), common.OauthContext{}, testInstanceURL, "org/project/repo", "main")
+	require.Error(t, err)
+	assert.Equal(t, common.NotFound, common.ErrorCode(err))
+}
+
+func TestProvider_UpsertEnvironmentVariable(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		switch r.URL.Path {
+		case "/org/project/_apis/git/repositories/repo":
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"id": "repo-guid", "name": "repo", "project": {"id": "project-guid", "name": "project"}}`)),
+			}, nil
+		case "/org/project-guid/_apis/distributedtask/variablegroups":
+			assert.Equal(t, VariableGroupName, r.URL.Query().Get("groupName"))
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body: io.NopCloser(strings.NewReader(`
+{"count": 1, "value": [{"id": 7, "name": "Bytebase", "type": "Vsts", "variables": {"EXISTING": {"value": "1"}}}]}
+`)),
+			}, nil
+		case "/org/_apis/distributedtask/variablegroups/7":
+			assert.Equal(t, http.MethodPut, r.Method)
+			var variableGroup VariableGroup
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&variableGroup))
+			want := map[string]VariableValue{
+				"EXISTING": {Value: "1"},
+				"KEY":      {Value: "value", IsSecret: true},
+			}
+			assert.Equal(t, want, variableGroup.Variables)
+			assert.Equal(t, []VariableGroupProjectReference{
+				{
+					Name:             VariableGroupName,
+					ProjectReference: Project{ID: "project-guid", Name: "project"},
+				},
+			}, variableGroup.VariableGroupProjectReferences)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"id": 7}`)),
+			}, nil
+		}
+		t.Fatalf("unexpected request %s", r.URL.Path)
+		return nil, nil
+	})
+
+	err := p.UpsertEnvironmentVariable(context.Background(), common.OauthContext{}, testInstanceURL, "org/project/repo", "KEY", "value")
+	require.NoError(t, err)
+}
+
+func TestProvider_CreateWebhook(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		switch r.URL.Path {
+		case "/org/project/_apis/git/repositories/repo":
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"id": "repo-guid", "name": "repo", "project": {"id": "project-guid", "name": "project"}}`)),
+			}, nil
+		case "/org/_apis/hooks/subscriptions":
+			var webhook WebhookCreateOrUpdate
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&webhook))
+			assert.Equal(t, "git.push", webhook.EventType)
+			assert.Equal(t, WebhookPublisherInputs{ProjectID: "project-guid", Repository: "repo-guid"}, webhook.PublisherInputs)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"id": "subscription-guid"}`)),
+			}, nil
+		}
+		t.Fatalf("unexpected request %s", r.URL.Path)
+		return nil, nil
+	})
+
+	payload, err := json.Marshal(WebhookCreateOrUpdate{
+		PublisherID:      "tfs",
+		EventType:        "git.push",
+		ResourceVersion:  "1.0",
+		ConsumerID:       "webHooks",
+		ConsumerActionID: "httpRequest",
+		ConsumerInputs: WebhookConsumerInputs{
+			URL: "https://bytebase.example.com/hook/azure/1",
+		},
+	})
+	require.NoError(t, err)
+	got, err := p.CreateWebhook(context.Background(), common.OauthContext{}, testInstanceURL, "org/project/repo", payload)
+	require.NoError(t, err)
+	assert.Equal(t, "subscription-guid", got)
+}
+
+func TestProvider_CreateCommitStatus(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/org/project/_apis/git/repositories/repo/commits/after_sha/statuses", r.URL.Path)
+		var status CommitStatusCreate
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&status))
+		assert.Equal(t,
+			CommitStatusCreate{
+				State:       "succeeded",
+				Description: "Migration done",
+				TargetURL:   "https://bytebase.example.com/issue/1",
+				Context:     CommitStatusContext{Genre: "bytebase", Name: "Prod"},
+			},
+			status,
+		)
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       io.NopCloser(strings.NewReader(`{"id": 1, "state": "succeeded"}`)),
+		}, nil
+	})
+
+	err := p.CreateCommitStatus(context.Background(), common.OauthContext{}, testInstanceURL, "org/project/repo", "after_sha",
+		&vcs.CommitStatus{
+			State:       vcs.CommitStateSuccess,
+			Context:     "bytebase/Prod",
+			Description: "Migration done",
+			TargetURL:   "https://bytebase.example.com/issue/1",
+		},
+	)
+	require.NoError(t, err)
+}
+
+func TestProvider_CreatePullRequestComment(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/org/project/_apis/git/repositories/repo/pullRequests/1/threads", r.URL.Path)
+		var thread PullRequestThreadCreate
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&thread))
+		require.Len(t, thread.Comments, 1)
+		assert.Equal(t, "Migration done", thread.Comments[0].Content)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"id": 1}`)),
+		}, nil
+	})
+
+	err := p.CreatePullRequestComment(context.Background(), common.OauthContext{}, testInstanceURL, "org/project/repo", "1", "Migration done")
+	require.NoError(t, err)
+}
+
+func TestWebhookPushEvent_ToVCS(t *testing.T) {
+	body := `
+{
+  "eventType": "git.push",
+  "resource": {
+    "refUpdates": [
+      {"name": "refs/heads/main", "oldObjectId": "before_sha", "newObjectId": "after_sha"},
+      {"name": "refs/heads/feature", "oldObjectId": "0000000000000000000000000000000000000000", "newObjectId": "feature_sha"},
+      {"name": "refs/tags/v1", "oldObjectId": "before_sha", "newObjectId": "after_sha"}
+    ],
+    "repository": {
+      "id": "repo-guid",
+      "name": "repo",
+      "url": "https://dev.azure.com/org/_apis/git/repositories/repo-guid",
+      "project": {"id": "project-guid", "name": "My Project"},
+      "remoteUrl": "https://org@dev.azure.com/org/My%20Project/_git/repo"
+    },
+    "pushedBy": {"displayName": "Jane Doe"}
+  }
+}
+`
+	var event WebhookPushEvent
+	require.NoError(t, json.Unmarshal([]byte(body), &event))
+	got, err := event.ToVCS()
+	require.NoError(t, err)
+	want := []vcs.PushEvent{
+		{
+			VCSType:            vcs.AzureDevOps,
+			Ref:                "refs/heads/main",
+			Before:             "before_sha",
+			After:              "after_sha",
+			RepositoryID:       "org/My Project/repo",
+			RepositoryURL:      "https://dev.azure.com/org/My%20Project/_git/repo",
+			RepositoryFullPath: "org/My Project/repo",
+			AuthorName:         "Jane Doe",
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func newMockProvider(mockRoundTrip func(r *http.Request) (*http.Response, error)) vcs.Provider {
+	return newProvider(
+		vcs.ProviderConfig{
+			Client: &http.Client{
+				Transport: &common.MockRoundTripper{
+					MockRoundTrip: mockRoundTrip,
+				},
+			},
+		},
+	)
+}
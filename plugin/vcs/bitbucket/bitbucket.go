@@ -0,0 +1,187 @@
+// Package bitbucket is the plugin for Bitbucket Cloud and Bitbucket Data Center.
+package bitbucket
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/plugin/vcs"
+)
+
+const (
+	// bitbucketCloudURL is URL for the Bitbucket Cloud.
+	bitbucketCloudURL = "https://bitbucket.org"
+
+	// apiPageSize is the default page size when making API requests.
+	apiPageSize = 100
+
+	// emptyCommitID is the commit ID used by Bitbucket Data Center when the ref is created or deleted.
+	emptyCommitID = "0000000000000000000000000000000000000000"
+)
+
+func init() {
+	vcs.Register(vcs.BitbucketCloud, newCloudProvider)
+	vcs.Register(vcs.BitbucketDataCenter, newDataCenterProvider)
+}
+
+// WebhookType is the Bitbucket webhook event key, which is sent in the X-Event-Key header.
+type WebhookType string
+
+const (
+	// WebhookRepoPush is the webhook event key for the push in Bitbucket Cloud.
+	WebhookRepoPush WebhookType = "repo:push"
+	// WebhookRepoRefsChanged is the webhook event key for the push in Bitbucket Data Center.
+	WebhookRepoRefsChanged WebhookType = "repo:refs_changed"
+	// WebhookDiagnosticsPing is the webhook event key for testing the connection in Bitbucket Data Center.
+	WebhookDiagnosticsPing WebhookType = "diagnostics:ping"
+)
+
+// oauthResponse is a Bitbucket OAuth response, which is the same for Bitbucket Cloud and Data Center.
+type oauthResponse struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error,omitempty"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// toVCSOAuthToken converts the response to *vcs.OAuthToken.
+func (o oauthResponse) toVCSOAuthToken() *vcs.OAuthToken {
+	oauthToken := &vcs.OAuthToken{
+		AccessToken:  o.AccessToken,
+		RefreshToken: o.RefreshToken,
+		ExpiresIn:    o.ExpiresIn,
+		CreatedAt:    time.Now().Unix(),
+	}
+	if oauthToken.ExpiresIn != 0 {
+		oauthToken.ExpiresTs = oauthToken.CreatedAt + oauthToken.ExpiresIn
+	}
+	return oauthToken
+}
+
+// requestOAuthToken sends the request to the OAuth token endpoint, which is
+// used for both exchanging and refreshing the token.
+func requestOAuthToken(client *http.Client, req *http.Request) (*oauthResponse, error) {
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "POST %s", req.URL)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read OAuth response body, code %v", resp.StatusCode)
+	}
+
+	oauthResp := new(oauthResponse)
+	if err := json.Unmarshal(body, oauthResp); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal OAuth response body, code %v", resp.StatusCode)
+	}
+	if oauthResp.Error != "" {
+		return nil, errors.Errorf("failed to request OAuth token, error: %v, error_description: %v", oauthResp.Error, oauthResp.ErrorDescription)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("non-200 POST %s status code %d with body %q", req.URL, resp.StatusCode, body)
+	}
+	return oauthResp, nil
+}
+
+// newMultipartForm returns the content type and the body of the multipart form
+// with given fields, which is required by the Bitbucket API to commit files.
+func newMultipartForm(fields map[string]string) (string, []byte, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for key, value := range fields {
+		if err := w.WriteField(key, value); err != nil {
+			return "", nil, errors.Wrapf(err, "write field %q", key)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return "", nil, errors.Wrap(err, "close multipart writer")
+	}
+	return w.FormDataContentType(), buf.Bytes(), nil
+}
+
+// splitRepositoryID splits the repository ID into the owner and the
+// repository slug. The owner is the workspace in Bitbucket Cloud and the
+// project key in Bitbucket Data Center.
+func splitRepositoryID(repositoryID string) (string, string, error) {
+	owner, slug, ok := strings.Cut(repositoryID, "/")
+	if !ok || owner == "" || slug == "" || strings.Contains(slug, "/") {
+		return "", "", errors.Errorf("invalid Bitbucket repository ID %q, expecting the format %q", repositoryID, "{owner}/{slug}")
+	}
+	return owner, slug, nil
+}
+
+// convertToBuildState converts the commit state to the build state, which is
+// shared by Bitbucket Cloud and Bitbucket Data Center.
+func convertToBuildState(state vcs.CommitState) (string, error) {
+	switch state {
+	case vcs.CommitStatePending:
+		return "INPROGRESS", nil
+	case vcs.CommitStateSuccess:
+		return "SUCCESSFUL", nil
+	case vcs.CommitStateFailure:
+		return "FAILED", nil
+	}
+	return "", errors.Errorf("unexpected commit state %q", state)
+}
+
+// splitCommitMessage returns the title of the commit message.
+func splitCommitMessage(message string) string {
+	// Per Git convention, the message title and body are separated by two new line characters.
+	return strings.SplitN(message, "\n\n", 2)[0]
+}
+
+// parseAuthorRaw parses the raw author string like "Name <name@example.com>" into the name and the email.
+func parseAuthorRaw(raw string) (string, string) {
+	start := strings.LastIndex(raw, "<")
+	end := strings.LastIndex(raw, ">")
+	if start < 0 || end < start {
+		return strings.TrimSpace(raw), ""
+	}
+	return strings.TrimSpace(raw[:start]), raw[start+1 : end]
+}
+
+// refChange is a change of a Git ref in the push event.
+type refChange struct {
+	ref    string
+	before string
+	after  string
+}
+
+// toVCS returns the push event in VCS format for each ref change.
+//
+// NOTE: Unlike GitHub and GitLab, Bitbucket doesn't include the changed files
+// in the push event, so the returned push event doesn't contain the commit
+// list and the caller should fill it by vcs.FillPushEventCommit. The ref creation and deletion are skipped because there is no
+// commit range to compare.
+func toVCS(vcsType vcs.Type, repositoryID, repositoryURL, authorName string, changeList []refChange) []vcs.PushEvent {
+	var pushEventList []vcs.PushEvent
+	for _, change := range changeList {
+		if change.before == "" || change.before == emptyCommitID || change.after == "" || change.after == emptyCommitID {
+			continue
+		}
+		pushEventList = append(pushEventList, vcs.PushEvent{
+			VCSType:            vcsType,
+			Ref:                change.ref,
+			Before:             change.before,
+			After:              change.after,
+			RepositoryID:       repositoryID,
+			RepositoryURL:      repositoryURL,
+			RepositoryFullPath: repositoryID,
+			AuthorName:         authorName,
+		})
+	}
+	return pushEventList
+}
@@ -0,0 +1,110 @@
+package bitbucket
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/vcs"
+)
+
+func TestSplitRepositoryID(t *testing.T) {
+	tests := []struct {
+		repositoryID string
+		owner        string
+		slug         string
+		wantErr      bool
+	}{
+		{
+			repositoryID: "PROJ/repo",
+			owner:        "PROJ",
+			slug:         "repo",
+		},
+		{
+			repositoryID: "repo",
+			wantErr:      true,
+		},
+		{
+			repositoryID: "/repo",
+			wantErr:      true,
+		},
+		{
+			repositoryID: "PROJ/repo/extra",
+			wantErr:      true,
+		},
+	}
+
+	for _, test := range tests {
+		owner, slug, err := splitRepositoryID(test.repositoryID)
+		if test.wantErr {
+			assert.Error(t, err, test.repositoryID)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, test.owner, owner)
+		assert.Equal(t, test.slug, slug)
+	}
+}
+
+func TestParseAuthorRaw(t *testing.T) {
+	tests := []struct {
+		raw   string
+		name  string
+		email string
+	}{
+		{
+			raw:   "Jane Doe <jane@example.com>",
+			name:  "Jane Doe",
+			email: "jane@example.com",
+		},
+		{
+			raw:  "Jane Doe",
+			name: "Jane Doe",
+		},
+		{
+			raw: "",
+		},
+	}
+
+	for _, test := range tests {
+		name, email := parseAuthorRaw(test.raw)
+		assert.Equal(t, test.name, name)
+		assert.Equal(t, test.email, email)
+	}
+}
+
+func TestToVCS(t *testing.T) {
+	changeList := []refChange{
+		{
+			ref:    "refs/heads/main",
+			before: "before_sha",
+			after:  "after_sha",
+		},
+		// Branch creation.
+		{
+			ref:    "refs/heads/feature",
+			before: emptyCommitID,
+			after:  "after_sha",
+		},
+		// Branch deletion.
+		{
+			ref:    "refs/heads/feature",
+			before: "before_sha",
+		},
+	}
+	got := toVCS(vcs.BitbucketDataCenter, "PROJ/repo", "https://bitbucket.example.com/projects/PROJ/repos/repo", "Jane Doe", changeList)
+	want := []vcs.PushEvent{
+		{
+			VCSType:            vcs.BitbucketDataCenter,
+			Ref:                "refs/heads/main",
+			Before:             "before_sha",
+			After:              "after_sha",
+			RepositoryID:       "PROJ/repo",
+			RepositoryURL:      "https://bitbucket.example.com/projects/PROJ/repos/repo",
+			RepositoryFullPath: "PROJ/repo",
+			AuthorName:         "Jane Doe",
+		},
+	}
+	assert.Equal(t, want, got)
+}
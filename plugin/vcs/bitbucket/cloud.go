@@ -0,0 +1,1187 @@
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/vcs"
+	"github.com/bytebase/bytebase/plugin/vcs/internal/oauth"
+)
+
+var _ vcs.Provider = (*CloudProvider)(nil)
+
+// CloudProvider is a Bitbucket Cloud VCS provider.
+type CloudProvider struct {
+	client *http.Client
+}
+
+func newCloudProvider(config vcs.ProviderConfig) vcs.Provider {
+	if config.Client == nil {
+		config.Client = &http.Client{}
+	}
+	return &CloudProvider{
+		client: config.Client,
+	}
+}
+
+// APIURL returns the API URL path of Bitbucket Cloud.
+func (*CloudProvider) APIURL(instanceURL string) string {
+	if instanceURL == bitbucketCloudURL {
+		return "https://api.bitbucket.org/2.0"
+	}
+
+	// The API is served under the instance URL for testing purpose.
+	return fmt.Sprintf("%s/api/2.0", instanceURL)
+}
+
+// CloudUser represents a Bitbucket Cloud API response for a user.
+type CloudUser struct {
+	UUID        string `json:"uuid"`
+	AccountID   string `json:"account_id"`
+	Nickname    string `json:"nickname"`
+	DisplayName string `json:"display_name"`
+}
+
+// CloudEmail represents a Bitbucket Cloud API response for a user email.
+type CloudEmail struct {
+	Email       string `json:"email"`
+	IsPrimary   bool   `json:"is_primary"`
+	IsConfirmed bool   `json:"is_confirmed"`
+}
+
+// CloudLink represents a Bitbucket Cloud API link.
+type CloudLink struct {
+	Href string `json:"href"`
+}
+
+// CloudLinks represents the Bitbucket Cloud API links of a resource.
+type CloudLinks struct {
+	HTML CloudLink `json:"html"`
+}
+
+// CloudRepository represents a Bitbucket Cloud API response for a repository.
+type CloudRepository struct {
+	UUID     string     `json:"uuid"`
+	Name     string     `json:"name"`
+	FullName string     `json:"full_name"`
+	Links    CloudLinks `json:"links"`
+}
+
+// CloudCommitAuthor represents a Bitbucket Cloud API response for a commit author.
+type CloudCommitAuthor struct {
+	// Raw is the raw author string, e.g. "Name <name@example.com>".
+	Raw  string    `json:"raw"`
+	User CloudUser `json:"user"`
+}
+
+// CloudCommit represents a Bitbucket Cloud API response for a commit.
+type CloudCommit struct {
+	Hash    string            `json:"hash"`
+	Date    time.Time         `json:"date"`
+	Message string            `json:"message"`
+	Author  CloudCommitAuthor `json:"author"`
+	Links   CloudLinks        `json:"links"`
+}
+
+// CloudDiffStatFile is the file path in the Bitbucket Cloud diff stat.
+type CloudDiffStatFile struct {
+	Path string `json:"path"`
+}
+
+// CloudDiffStat represents a Bitbucket Cloud API response for a changed file.
+type CloudDiffStat struct {
+	// Available values: "added", "removed", "modified", "renamed"
+	Status string `json:"status"`
+	// Old is nil if the file is added.
+	Old *CloudDiffStatFile `json:"old"`
+	// New is nil if the file is removed.
+	New *CloudDiffStatFile `json:"new"`
+}
+
+// CloudTreeEntry represents a Bitbucket Cloud API response for a file or a directory.
+type CloudTreeEntry struct {
+	// Available values: "commit_file", "commit_directory"
+	Type   string `json:"type"`
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	Commit struct {
+		Hash string `json:"hash"`
+	} `json:"commit"`
+}
+
+// CloudBranch represents a Bitbucket Cloud API message for a branch.
+type CloudBranch struct {
+	Name   string `json:"name"`
+	Target struct {
+		Hash string `json:"hash"`
+	} `json:"target"`
+}
+
+// CloudPullRequestBranch is the branch of the Bitbucket Cloud pull request.
+type CloudPullRequestBranch struct {
+	Branch struct {
+		Name string `json:"name"`
+	} `json:"branch"`
+	Commit *struct {
+		Hash string `json:"hash"`
+	} `json:"commit,omitempty"`
+}
+
+// CloudPullRequest represents a Bitbucket Cloud API message for a pull request.
+type CloudPullRequest struct {
+	ID                int                    `json:"id,omitempty"`
+	Title             string                 `json:"title"`
+	Description       string                 `json:"description"`
+	Source            CloudPullRequestBranch `json:"source"`
+	Destination       CloudPullRequestBranch `json:"destination"`
+	CloseSourceBranch bool                   `json:"close_source_branch"`
+	Links             *CloudLinks            `json:"links,omitempty"`
+}
+
+// CloudWebhookCreateOrUpdate represents a Bitbucket Cloud API request for
+// creating or updating a webhook.
+type CloudWebhookCreateOrUpdate struct {
+	Description string `json:"description"`
+	// URL is the URL to which the payloads will be delivered.
+	URL    string `json:"url"`
+	Active bool   `json:"active"`
+	// Secret is used as the key to generate the HMAC hex digest value in the
+	// X-Hub-Signature header.
+	Secret string `json:"secret"`
+	// Events determines what events the hook is triggered for, e.g. "repo:push".
+	Events []string `json:"events"`
+}
+
+// CloudWebhookInfo represents a Bitbucket Cloud API response for the webhook information.
+type CloudWebhookInfo struct {
+	UUID string `json:"uuid"`
+}
+
+// CloudWebhookPushChangeRef is the ref state before or after the push in Bitbucket Cloud.
+type CloudWebhookPushChangeRef struct {
+	// Available values: "branch", "tag"
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	Target struct {
+		Hash string `json:"hash"`
+	} `json:"target"`
+}
+
+// CloudWebhookPushChange is a ref change in the Bitbucket Cloud push event.
+type CloudWebhookPushChange struct {
+	// Old is nil if the ref is created.
+	Old *CloudWebhookPushChangeRef `json:"old"`
+	// New is nil if the ref is deleted.
+	New *CloudWebhookPushChangeRef `json:"new"`
+}
+
+// WebhookPushEvent is the API message for Bitbucket Cloud webhook push event.
+//
+// Docs: https://support.atlassian.com/bitbucket-cloud/docs/event-payloads/#Push
+type WebhookPushEvent struct {
+	Actor      CloudUser       `json:"actor"`
+	Repository CloudRepository `json:"repository"`
+	Push       struct {
+		Changes []CloudWebhookPushChange `json:"changes"`
+	} `json:"push"`
+}
+
+// cloudPage is the paginated Bitbucket Cloud API response.
+type cloudPage struct {
+	Values json.RawMessage `json:"values"`
+	// Next is the URL of the next page, which is empty for the last page.
+	Next string `json:"next"`
+}
+
+// fetchPaginatedList fetches all the values by following the next page URL
+// from the given URL, and calls the callback with the values of each page.
+func (p *CloudProvider) fetchPaginatedList(ctx context.Context, oauthCtx common.OauthContext, instanceURL, url, resource string, callback func(values json.RawMessage) error) error {
+	for url != "" {
+		code, _, body, err := oauth.Get(
+			ctx,
+			p.client,
+			url,
+			&oauthCtx.AccessToken,
+			cloudTokenRefresher(
+				instanceURL,
+				oauthContext{
+					ClientID:     oauthCtx.ClientID,
+					ClientSecret: oauthCtx.ClientSecret,
+					RefreshToken: oauthCtx.RefreshToken,
+				},
+				oauthCtx.Refresher,
+			),
+		)
+		if err != nil {
+			return errors.Wrapf(err, "GET %s", url)
+		}
+
+		if code == http.StatusNotFound {
+			return common.Errorf(common.NotFound, "failed to fetch %s from URL %s", resource, url)
+		} else if code >= 300 {
+			return errors.Errorf("failed to fetch %s from URL %s, status code: %d, body: %s",
+				resource,
+				url,
+				code,
+				body,
+			)
+		}
+
+		var page cloudPage
+		if err := json.Unmarshal([]byte(body), &page); err != nil {
+			return errors.Wrap(err, "unmarshal body")
+		}
+		if err := callback(page.Values); err != nil {
+			return err
+		}
+		url = page.Next
+	}
+	return nil
+}
+
+// ExchangeOAuthToken exchanges OAuth content with the provided authorization code.
+//
+// Docs: https://developer.atlassian.com/cloud/bitbucket/oauth-2/
+func (p *CloudProvider) ExchangeOAuthToken(ctx context.Context, instanceURL string, oauthExchange *common.OAuthExchange) (*vcs.OAuthToken, error) {
+	params := &url.Values{}
+	params.Set("grant_type", "authorization_code")
+	params.Set("code", oauthExchange.Code)
+	url := fmt.Sprintf("%s/site/oauth2/access_token", instanceURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, errors.Wrapf(err, "construct POST %s", url)
+	}
+	// Bitbucket Cloud requires the client credentials in the basic auth.
+	req.SetBasicAuth(oauthExchange.ClientID, oauthExchange.ClientSecret)
+
+	oauthResp, err := requestOAuthToken(p.client, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to exchange OAuth token")
+	}
+	return oauthResp.toVCSOAuthToken(), nil
+}
+
+// TryLogin tries to fetch the user info from the current OAuth context.
+//
+// Docs: https://developer.atlassian.com/cloud/bitbucket/rest/api-group-users/#api-user-get
+func (p *CloudProvider) TryLogin(ctx context.Context, oauthCtx common.OauthContext, instanceURL string) (*vcs.UserInfo, error) {
+	userInfo, err := p.fetchUserInfoImpl(ctx, oauthCtx, instanceURL, "user")
+	if err != nil {
+		return nil, err
+	}
+
+	// The email is not included in the user info, so we use the primary email of the current user.
+	url := fmt.Sprintf("%s/user/emails?pagelen=%d", p.APIURL(instanceURL), apiPageSize)
+	if err := p.fetchPaginatedList(ctx, oauthCtx, instanceURL, url, "user emails", func(values json.RawMessage) error {
+		var emails []CloudEmail
+		if err := json.Unmarshal(values, &emails); err != nil {
+			return errors.Wrap(err, "unmarshal emails")
+		}
+		for _, email := range emails {
+			if email.IsPrimary && email.IsConfirmed {
+				userInfo.PublicEmail = email.Email
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return userInfo, nil
+}
+
+// fetchUserInfoImpl fetches user information from the given resourceURI, which
+// should be either "user" or "users/{username}".
+func (p *CloudProvider) fetchUserInfoImpl(ctx context.Context, oauthCtx common.OauthContext, instanceURL, resourceURI string) (*vcs.UserInfo, error) {
+	url := fmt.Sprintf("%s/%s", p.APIURL(instanceURL), resourceURI)
+	code, _, body, err := oauth.Get(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		cloudTokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "GET")
+	}
+
+	if code == http.StatusNotFound {
+		return nil, common.Errorf(common.NotFound, "failed to read user info from URL %s", url)
+	} else if code >= 300 {
+		return nil, errors.Errorf("failed to read user info from URL %s, status code: %d, body: %s", url, code, body)
+	}
+
+	var user CloudUser
+	if err = json.Unmarshal([]byte(body), &user); err != nil {
+		return nil, errors.Wrap(err, "unmarshal")
+	}
+	return &vcs.UserInfo{
+		Name:  user.DisplayName,
+		State: vcs.StateActive,
+	}, nil
+}
+
+// FetchCommitByID fetches the commit data by its ID from the repository.
+//
+// Docs: https://developer.atlassian.com/cloud/bitbucket/rest/api-group-commits/#api-repositories-workspace-repo-slug-commit-commit-get
+func (p *CloudProvider) FetchCommitByID(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, commitID string) (*vcs.Commit, error) {
+	url := fmt.Sprintf("%s/repositories/%s/commit/%s", p.APIURL(instanceURL), repositoryID, commitID)
+	code, _, body, err := oauth.Get(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		cloudTokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "GET")
+	}
+
+	if code == http.StatusNotFound {
+		return nil, common.Errorf(common.NotFound, "failed to fetch commit data from URL %s", url)
+	} else if code >= 300 {
+		return nil, errors.Errorf("failed to fetch commit data from URL %s, status code: %d, body: %s", url, code, body)
+	}
+
+	commit := &CloudCommit{}
+	if err := json.Unmarshal([]byte(body), commit); err != nil {
+		return nil, errors.Wrap(err, "unmarshal body")
+	}
+
+	authorName, authorEmail := parseAuthorRaw(commit.Author.Raw)
+	if commit.Author.User.DisplayName != "" {
+		authorName = commit.Author.User.DisplayName
+	}
+	return &vcs.Commit{
+		ID:          commit.Hash,
+		Title:       splitCommitMessage(commit.Message),
+		Message:     commit.Message,
+		CreatedTs:   commit.Date.Unix(),
+		URL:         commit.Links.HTML.Href,
+		AuthorName:  authorName,
+		AuthorEmail: authorEmail,
+	}, nil
+}
+
+// GetDiffFileList gets the diff files list between two commits.
+//
+// Docs: https://developer.atlassian.com/cloud/bitbucket/rest/api-group-commits/#api-repositories-workspace-repo-slug-diffstat-spec-get
+func (p *CloudProvider) GetDiffFileList(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, beforeCommit, afterCommit string) ([]vcs.FileDiff, error) {
+	// NOTE: Unlike Git, the spec "a..b" in Bitbucket Cloud compares the commit a
+	// against the merge base of a and b, so the after commit comes first.
+	url := fmt.Sprintf("%s/repositories/%s/diffstat/%s..%s?pagelen=%d", p.APIURL(instanceURL), repositoryID, afterCommit, beforeCommit, apiPageSize)
+	var ret []vcs.FileDiff
+	if err := p.fetchPaginatedList(ctx, oauthCtx, instanceURL, url, "file diff list", func(values json.RawMessage) error {
+		var diffStats []CloudDiffStat
+		if err := json.Unmarshal(values, &diffStats); err != nil {
+			return errors.Wrapf(err, "failed to unmarshal file diff data from Bitbucket Cloud instance %s", instanceURL)
+		}
+		ret = append(ret, convertCloudDiffStatList(diffStats)...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// convertCloudDiffStatList converts the Bitbucket Cloud diff stats to the file diffs.
+// A renamed file is treated as the old file is removed and the new file is added.
+func convertCloudDiffStatList(diffStats []CloudDiffStat) []vcs.FileDiff {
+	var ret []vcs.FileDiff
+	for _, diffStat := range diffStats {
+		switch diffStat.Status {
+		case "added":
+			ret = append(ret, vcs.FileDiff{Path: diffStat.New.Path, Type: vcs.FileDiffTypeAdded})
+		case "modified":
+			ret = append(ret, vcs.FileDiff{Path: diffStat.New.Path, Type: vcs.FileDiffTypeModified})
+		case "removed":
+			ret = append(ret, vcs.FileDiff{Path: diffStat.Old.Path, Type: vcs.FileDiffTypeRemoved})
+		case "renamed":
+			ret = append(ret,
+				vcs.FileDiff{Path: diffStat.Old.Path, Type: vcs.FileDiffTypeRemoved},
+				vcs.FileDiff{Path: diffStat.New.Path, Type: vcs.FileDiffTypeAdded},
+			)
+		}
+	}
+	return ret
+}
+
+// FetchUserInfo fetches user info of given user ID.
+//
+// Docs: https://developer.atlassian.com/cloud/bitbucket/rest/api-group-users/#api-users-selected-user-get
+func (p *CloudProvider) FetchUserInfo(ctx context.Context, oauthCtx common.OauthContext, instanceURL, username string) (*vcs.UserInfo, error) {
+	return p.fetchUserInfoImpl(ctx, oauthCtx, instanceURL, fmt.Sprintf("users/%s", url.PathEscape(username)))
+}
+
+// FetchRepositoryActiveMemberList fetch all active members of a repository.
+//
+// NOTE: Bitbucket Cloud never exposes the email of other users, which is
+// required to map the repository members to the Bytebase principals, thus we
+// cannot sync the members from Bitbucket Cloud.
+func (*CloudProvider) FetchRepositoryActiveMemberList(_ context.Context, _ common.OauthContext, _, _ string) ([]*vcs.RepositoryMember, error) {
+	return nil, common.Errorf(common.NotImplemented, "syncing repository members is not supported by Bitbucket Cloud because it does not expose the email of the members")
+}
+
+// FetchAllRepositoryList fetches all repositories where the authenticated user
+// has admin permissions, which is required to create webhook in the repository.
+//
+// Docs: https://developer.atlassian.com/cloud/bitbucket/rest/api-group-repositories/#api-repositories-get
+func (p *CloudProvider) FetchAllRepositoryList(ctx context.Context, oauthCtx common.OauthContext, instanceURL string) ([]*vcs.Repository, error) {
+	url := fmt.Sprintf("%s/repositories?role=admin&pagelen=%d", p.APIURL(instanceURL), apiPageSize)
+	var allRepos []*vcs.Repository
+	if err := p.fetchPaginatedList(ctx, oauthCtx, instanceURL, url, "repository list", func(values json.RawMessage) error {
+		var repos []CloudRepository
+		if err := json.Unmarshal(values, &repos); err != nil {
+			return errors.Wrap(err, "unmarshal")
+		}
+		for _, r := range repos {
+			// Bitbucket Cloud doesn't have the numeric repository ID, so we use the
+			// full name as the external ID of the repository.
+			allRepos = append(allRepos,
+				&vcs.Repository{
+					Name:     r.Name,
+					FullPath: r.FullName,
+					WebURL:   r.Links.HTML.Href,
+				},
+			)
+		}
+		return nil
+	}); err != nil {
+		return nil, errors.Wrap(err, "fetch paginated list")
+	}
+	return allRepos, nil
+}
+
+// FetchRepositoryFileList fetches the all files from the given repository tree
+// recursively.
+//
+// Docs: https://developer.atlassian.com/cloud/bitbucket/rest/api-group-source/#api-repositories-workspace-repo-slug-src-commit-path-get
+func (p *CloudProvider) FetchRepositoryFileList(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, ref, filePath string) ([]*vcs.RepositoryTreeNode, error) {
+	var allTreeNodes []*vcs.RepositoryTreeNode
+	directoryList := []string{strings.Trim(filePath, "/")}
+	for len(directoryList) > 0 {
+		directory := directoryList[0]
+		directoryList = directoryList[1:]
+
+		url := fmt.Sprintf("%s/repositories/%s/src/%s/%s?pagelen=%d", p.APIURL(instanceURL), repositoryID, url.PathEscape(ref), escapeFilePath(directory), apiPageSize)
+		if err := p.fetchPaginatedList(ctx, oauthCtx, instanceURL, url, "repository file list", func(values json.RawMessage) error {
+			var entries []CloudTreeEntry
+			if err := json.Unmarshal(values, &entries); err != nil {
+				return errors.Wrap(err, "unmarshal body")
+			}
+			for _, entry := range entries {
+				switch entry.Type {
+				case "commit_directory":
+					directoryList = append(directoryList, entry.Path)
+				case "commit_file":
+					allTreeNodes = append(allTreeNodes,
+						&vcs.RepositoryTreeNode{
+							Path: entry.Path,
+							Type: "blob",
+						},
+					)
+				}
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return allTreeNodes, nil
+}
+
+// CreateFile creates a file at given path in the repository.
+//
+// NOTE: Bitbucket Cloud doesn't support detecting the conflicting writes of a
+// single file, so the LastCommitID of the file commit is ignored.
+//
+// Docs: https://developer.atlassian.com/cloud/bitbucket/rest/api-group-source/#api-repositories-workspace-repo-slug-src-post
+func (p *CloudProvider) CreateFile(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, filePath string, fileCommitCreate vcs.FileCommitCreate) error {
+	contentType, body, err := newMultipartForm(map[string]string{
+		// The form field name is the file path, and the value is the file content.
+		filePath:  fileCommitCreate.Content,
+		"message": fileCommitCreate.CommitMessage,
+		"branch":  fileCommitCreate.Branch,
+	})
+	if err != nil {
+		return errors.Wrap(err, "create multipart form")
+	}
+
+	url := fmt.Sprintf("%s/repositories/%s/src", p.APIURL(instanceURL), repositoryID)
+	code, _, resp, err := oauth.PostWithContentType(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		contentType,
+		bytes.NewReader(body),
+		cloudTokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to create/update file through URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to create/update file through URL %s, status code: %d, body: %s",
+			url,
+			code,
+			resp,
+		)
+	}
+	return nil
+}
+
+// OverwriteFile overwrites an existing file at given path in the repository.
+//
+// Docs: https://developer.atlassian.com/cloud/bitbucket/rest/api-group-source/#api-repositories-workspace-repo-slug-src-post
+func (p *CloudProvider) OverwriteFile(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, filePath string, fileCommitCreate vcs.FileCommitCreate) error {
+	return p.CreateFile(ctx, oauthCtx, instanceURL, repositoryID, filePath, fileCommitCreate)
+}
+
+// ReadFileMeta reads the metadata of the given file in the repository.
+//
+// Docs: https://developer.atlassian.com/cloud/bitbucket/rest/api-group-source/#api-repositories-workspace-repo-slug-src-commit-path-get
+func (p *CloudProvider) ReadFileMeta(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, filePath, ref string) (*vcs.FileMeta, error) {
+	body, err := p.readFile(ctx, oauthCtx, instanceURL, repositoryID, filePath, ref, "format=meta")
+	if err != nil {
+		return nil, errors.Wrap(err, "read file")
+	}
+
+	var entry CloudTreeEntry
+	if err := json.Unmarshal([]byte(body), &entry); err != nil {
+		return nil, errors.Wrap(err, "unmarshal body")
+	}
+	if entry.Type != "commit_file" {
+		return nil, errors.Errorf("%q is a directory not a file", filePath)
+	}
+
+	return &vcs.FileMeta{
+		Name:         path.Base(entry.Path),
+		Path:         entry.Path,
+		Size:         entry.Size,
+		LastCommitID: entry.Commit.Hash,
+	}, nil
+}
+
+// ReadFileContent reads the content of the given file in the repository.
+//
+// Docs: https://developer.atlassian.com/cloud/bitbucket/rest/api-group-source/#api-repositories-workspace-repo-slug-src-commit-path-get
+func (p *CloudProvider) ReadFileContent(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, filePath, ref string) (string, error) {
+	content, err := p.readFile(ctx, oauthCtx, instanceURL, repositoryID, filePath, ref, "")
+	if err != nil {
+		return "", errors.Wrap(err, "read file")
+	}
+	return content, nil
+}
+
+// readFile reads the raw file content or the metadata of the given file in the repository.
+func (p *CloudProvider) readFile(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, filePath, ref, query string) (string, error) {
+	url := fmt.Sprintf("%s/repositories/%s/src/%s/%s", p.APIURL(instanceURL), repositoryID, url.PathEscape(ref), escapeFilePath(filePath))
+	if query != "" {
+		url = fmt.Sprintf("%s?%s", url, query)
+	}
+	code, _, body, err := oauth.Get(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		cloudTokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return "", errors.Wrapf(err, "GET %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return "", common.Errorf(common.NotFound, "failed to read file from URL %s", url)
+	} else if code >= 300 {
+		return "", errors.Errorf("failed to read file from URL %s, status code: %d, body: %s",
+			url,
+			code,
+			body,
+		)
+	}
+	return body, nil
+}
+
+// ListPullRequestFile lists the changed files in the pull request.
+//
+// Docs: https://developer.atlassian.com/cloud/bitbucket/rest/api-group-pullrequests/#api-repositories-workspace-repo-slug-pullrequests-pull-request-id-diffstat-get
+func (p *CloudProvider) ListPullRequestFile(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, pullRequestID string) ([]*vcs.PullRequestFile, error) {
+	pullRequest, err := p.getPullRequest(ctx, oauthCtx, instanceURL, repositoryID, pullRequestID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get pull request")
+	}
+	if pullRequest.Source.Commit == nil {
+		return nil, errors.Errorf("cannot find the source commit of pull request %s", pullRequestID)
+	}
+
+	url := fmt.Sprintf("%s/repositories/%s/pullrequests/%s/diffstat?pagelen=%d", p.APIURL(instanceURL), repositoryID, pullRequestID, apiPageSize)
+	var res []*vcs.PullRequestFile
+	if err := p.fetchPaginatedList(ctx, oauthCtx, instanceURL, url, "pull request file", func(values json.RawMessage) error {
+		var diffStats []CloudDiffStat
+		if err := json.Unmarshal(values, &diffStats); err != nil {
+			return errors.Wrap(err, "unmarshal body")
+		}
+		for _, fileDiff := range convertCloudDiffStatList(diffStats) {
+			res = append(res, &vcs.PullRequestFile{
+				Path:         fileDiff.Path,
+				LastCommitID: pullRequest.Source.Commit.Hash,
+				IsDeleted:    fileDiff.Type == vcs.FileDiffTypeRemoved,
+			})
+		}
+		return nil
+	}); err != nil {
+		return nil, errors.Wrap(err, "Failed to list pull request file")
+	}
+	return res, nil
+}
+
+// getPullRequest gets the pull request in the repository.
+func (p *CloudProvider) getPullRequest(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, pullRequestID string) (*CloudPullRequest, error) {
+	url := fmt.Sprintf("%s/repositories/%s/pullrequests/%s", p.APIURL(instanceURL), repositoryID, pullRequestID)
+	code, _, body, err := oauth.Get(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		cloudTokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "GET %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return nil, common.Errorf(common.NotFound, "failed to get pull request from URL %s", url)
+	} else if code >= 300 {
+		return nil, errors.Errorf("failed to get pull request from URL %s, status code: %d, body: %s",
+			url,
+			code,
+			body,
+		)
+	}
+
+	var pullRequest CloudPullRequest
+	if err := json.Unmarshal([]byte(body), &pullRequest); err != nil {
+		return nil, errors.Wrap(err, "unmarshal body")
+	}
+	return &pullRequest, nil
+}
+
+// GetBranch gets the given branch in the repository.
+//
+// Docs: https://developer.atlassian.com/cloud/bitbucket/rest/api-group-refs/#api-repositories-workspace-repo-slug-refs-branches-name-get
+func (p *CloudProvider) GetBranch(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, branchName string) (*vcs.BranchInfo, error) {
+	url := fmt.Sprintf("%s/repositories/%s/refs/branches/%s", p.APIURL(instanceURL), repositoryID, url.PathEscape(branchName))
+	code, _, body, err := oauth.Get(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		cloudTokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "GET %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return nil, common.Errorf(common.NotFound, "failed to get branch from URL %s", url)
+	} else if code >= 300 {
+		return nil, errors.Errorf("failed to get branch from URL %s, status code: %d, body: %s",
+			url,
+			code,
+			body,
+		)
+	}
+
+	var branch CloudBranch
+	if err := json.Unmarshal([]byte(body), &branch); err != nil {
+		return nil, err
+	}
+
+	return &vcs.BranchInfo{
+		Name:         branch.Name,
+		LastCommitID: branch.Target.Hash,
+	}, nil
+}
+
+// CreateBranch creates the branch in the repository.
+//
+// Docs: https://developer.atlassian.com/cloud/bitbucket/rest/api-group-refs/#api-repositories-workspace-repo-slug-refs-branches-post
+func (p *CloudProvider) CreateBranch(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID string, branch *vcs.BranchInfo) error {
+	branchCreate := CloudBranch{
+		Name: branch.Name,
+	}
+	branchCreate.Target.Hash = branch.LastCommitID
+	body, err := json.Marshal(branchCreate)
+	if err != nil {
+		return errors.Wrap(err, "marshal branch create")
+	}
+
+	url := fmt.Sprintf("%s/repositories/%s/refs/branches", p.APIURL(instanceURL), repositoryID)
+	code, _, resp, err := oauth.Post(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		bytes.NewReader(body),
+		cloudTokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to create branch from URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to create branch from URL %s, status code: %d, body: %s",
+			url,
+			code,
+			resp,
+		)
+	}
+	return nil
+}
+
+// CreatePullRequest creates the pull request in the repository.
+//
+// Docs: https://developer.atlassian.com/cloud/bitbucket/rest/api-group-pullrequests/#api-repositories-workspace-repo-slug-pullrequests-post
+func (p *CloudProvider) CreatePullRequest(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID string, pullRequestCreate *vcs.PullRequestCreate) (*vcs.PullRequest, error) {
+	pullRequest := CloudPullRequest{
+		Title:             pullRequestCreate.Title,
+		Description:       pullRequestCreate.Body,
+		CloseSourceBranch: pullRequestCreate.RemoveHeadAfterMerged,
+	}
+	pullRequest.Source.Branch.Name = pullRequestCreate.Head
+	pullRequest.Destination.Branch.Name = pullRequestCreate.Base
+	body, err := json.Marshal(pullRequest)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal pull request create")
+	}
+
+	url := fmt.Sprintf("%s/repositories/%s/pullrequests", p.APIURL(instanceURL), repositoryID)
+	code, _, resp, err := oauth.Post(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		bytes.NewReader(body),
+		cloudTokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return nil, common.Errorf(common.NotFound, "failed to create pull request from URL %s", url)
+	} else if code >= 300 {
+		return nil, errors.Errorf("failed to create pull request from URL %s, status code: %d, body: %s",
+			url,
+			code,
+			resp,
+		)
+	}
+
+	var res CloudPullRequest
+	if err := json.Unmarshal([]byte(resp), &res); err != nil {
+		return nil, err
+	}
+	if res.Links == nil {
+		return nil, errors.Errorf("missing the links of the created pull request")
+	}
+
+	return &vcs.PullRequest{
+		URL: res.Links.HTML.Href,
+	}, nil
+}
+
+// CloudPullRequestCommentCreate is the API message for creating a pull request
+// comment in Bitbucket Cloud.
+type CloudPullRequestCommentCreate struct {
+	Content struct {
+		Raw string `json:"raw"`
+	} `json:"content"`
+}
+
+// CreatePullRequestComment creates a comment in the pull request.
+//
+// Docs: https://developer.atlassian.com/cloud/bitbucket/rest/api-group-pullrequests/#api-repositories-workspace-repo-slug-pullrequests-pull-request-id-comments-post
+func (p *CloudProvider) CreatePullRequestComment(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, pullRequestID, comment string) error {
+	var commentCreate CloudPullRequestCommentCreate
+	commentCreate.Content.Raw = comment
+	body, err := json.Marshal(commentCreate)
+	if err != nil {
+		return errors.Wrap(err, "marshal pull request comment create")
+	}
+
+	url := fmt.Sprintf("%s/repositories/%s/pullrequests/%s/comments", p.APIURL(instanceURL), repositoryID, pullRequestID)
+	code, _, resp, err := oauth.Post(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		bytes.NewReader(body),
+		cloudTokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to create pull request comment from URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to create pull request comment from URL %s, status code: %d, body: %s",
+			url,
+			code,
+			resp,
+		)
+	}
+	return nil
+}
+
+// CloudCommitStatusCreate is the API message for creating a commit build
+// status in Bitbucket Cloud.
+type CloudCommitStatusCreate struct {
+	Key         string `json:"key"`
+	State       string `json:"state"`
+	Name        string `json:"name"`
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// CreateCommitStatus creates the status of the commit, which is shown as a
+// build status of the commit.
+//
+// Docs: https://developer.atlassian.com/cloud/bitbucket/rest/api-group-commit-statuses/#api-repositories-workspace-repo-slug-commit-commit-statuses-build-post
+func (p *CloudProvider) CreateCommitStatus(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, commitID string, status *vcs.CommitStatus) error {
+	state, err := convertToBuildState(status.State)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(
+		CloudCommitStatusCreate{
+			Key:         status.Context,
+			State:       state,
+			Name:        status.Context,
+			URL:         status.TargetURL,
+			Description: status.Description,
+		},
+	)
+	if err != nil {
+		return errors.Wrap(err, "marshal commit status create")
+	}
+
+	url := fmt.Sprintf("%s/repositories/%s/commit/%s/statuses/build", p.APIURL(instanceURL), repositoryID, commitID)
+	code, _, resp, err := oauth.Post(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		bytes.NewReader(body),
+		cloudTokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to create commit status from URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to create commit status from URL %s, status code: %d, body: %s",
+			url,
+			code,
+			resp,
+		)
+	}
+	return nil
+}
+
+// ListPullRequest lists the open pull requests to the base branch in the repository.
+//
+// NOTE: It's only used to write back the latest schema by pull requests, which
+// is not supported for Bitbucket yet.
+func (*CloudProvider) ListPullRequest(_ context.Context, _ common.OauthContext, _, _, _ string) ([]*vcs.PullRequest, error) {
+	return nil, common.Errorf(common.NotImplemented, "listing pull requests is not supported for Bitbucket Cloud")
+}
+
+// ClosePullRequest closes the pull request.
+//
+// NOTE: It's only used to write back the latest schema by pull requests, which
+// is not supported for Bitbucket yet.
+func (*CloudProvider) ClosePullRequest(_ context.Context, _ common.OauthContext, _, _, _ string) error {
+	return common.Errorf(common.NotImplemented, "closing pull requests is not supported for Bitbucket Cloud")
+}
+
+// UpsertEnvironmentVariable creates or updates the environment variable in the repository.
+//
+// NOTE: We don't support the SQL review CI for Bitbucket yet, so there is no
+// need to set up the Bitbucket Pipelines variables.
+func (*CloudProvider) UpsertEnvironmentVariable(_ context.Context, _ common.OauthContext, _, _, _, _ string) error {
+	return common.Errorf(common.NotImplemented, "environment variable is not supported for Bitbucket Cloud")
+}
+
+// CreateWebhook creates a webhook in the repository with given payload.
+//
+// Docs: https://developer.atlassian.com/cloud/bitbucket/rest/api-group-repositories/#api-repositories-workspace-repo-slug-hooks-post
+func (p *CloudProvider) CreateWebhook(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID string, payload []byte) (string, error) {
+	url := fmt.Sprintf("%s/repositories/%s/hooks", p.APIURL(instanceURL), repositoryID)
+	code, _, body, err := oauth.Post(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		bytes.NewReader(payload),
+		cloudTokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return "", errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return "", common.Errorf(common.NotFound, "failed to create webhook through URL %s", url)
+	}
+
+	// Bitbucket Cloud returns 201 HTTP status codes upon successful webhook creation.
+	if code != http.StatusCreated {
+		return "", errors.Errorf("failed to create webhook through URL %s, status code: %d, body: %s",
+			url,
+			code,
+			body,
+		)
+	}
+
+	var webhookInfo CloudWebhookInfo
+	if err = json.Unmarshal([]byte(body), &webhookInfo); err != nil {
+		return "", errors.Wrap(err, "unmarshal body")
+	}
+	return webhookInfo.UUID, nil
+}
+
+// PatchWebhook patches the webhook in the repository with given payload.
+//
+// Docs: https://developer.atlassian.com/cloud/bitbucket/rest/api-group-repositories/#api-repositories-workspace-repo-slug-hooks-uid-put
+func (p *CloudProvider) PatchWebhook(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, webhookID string, payload []byte) error {
+	// The webhook UUID is surrounded by the curly braces, which should be escaped.
+	url := fmt.Sprintf("%s/repositories/%s/hooks/%s", p.APIURL(instanceURL), repositoryID, url.PathEscape(webhookID))
+	code, _, body, err := oauth.Put(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		bytes.NewReader(payload),
+		cloudTokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "PUT %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to patch webhook through URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to patch webhook through URL %s, status code: %d, body: %s",
+			url,
+			code,
+			body,
+		)
+	}
+	return nil
+}
+
+// DeleteWebhook deletes the webhook from the repository.
+//
+// Docs: https://developer.atlassian.com/cloud/bitbucket/rest/api-group-repositories/#api-repositories-workspace-repo-slug-hooks-uid-delete
+func (p *CloudProvider) DeleteWebhook(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, webhookID string) error {
+	url := fmt.Sprintf("%s/repositories/%s/hooks/%s", p.APIURL(instanceURL), repositoryID, url.PathEscape(webhookID))
+	code, _, body, err := oauth.Delete(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		cloudTokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "DELETE %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return nil // It is OK if the webhook has already gone
+	} else if code >= 300 {
+		return errors.Errorf("failed to delete webhook through URL %s, status code: %d, body: %s",
+			url,
+			code,
+			body,
+		)
+	}
+	return nil
+}
+
+// oauthContext is the request context for refreshing oauth token.
+type oauthContext struct {
+	ClientID     string
+	ClientSecret string
+	RefreshToken string
+}
+
+func cloudTokenRefresher(instanceURL string, oauthCtx oauthContext, refresher common.TokenRefresher) oauth.TokenRefresher {
+	return func(ctx context.Context, client *http.Client, oldToken *string) error {
+		params := &url.Values{}
+		params.Set("grant_type", "refresh_token")
+		params.Set("refresh_token", oauthCtx.RefreshToken)
+		url := fmt.Sprintf("%s/site/oauth2/access_token", instanceURL)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(params.Encode()))
+		if err != nil {
+			return errors.Wrapf(err, "construct POST %s", url)
+		}
+		req.SetBasicAuth(oauthCtx.ClientID, oauthCtx.ClientSecret)
+
+		r, err := requestOAuthToken(client, req)
+		if err != nil {
+			return err
+		}
+
+		// Update the old token to new value for retries.
+		*oldToken = r.AccessToken
+
+		token := r.toVCSOAuthToken()
+		return refresher(token.AccessToken, token.RefreshToken, token.ExpiresTs)
+	}
+}
+
+// ToVCS returns the push event in VCS format for each branch change.
+func (p WebhookPushEvent) ToVCS() []vcs.PushEvent {
+	var changeList []refChange
+	for _, change := range p.Push.Changes {
+		if change.Old == nil || change.New == nil || change.New.Type != "branch" {
+			continue
+		}
+		changeList = append(changeList, refChange{
+			ref:    fmt.Sprintf("refs/heads/%s", change.New.Name),
+			before: change.Old.Target.Hash,
+			after:  change.New.Target.Hash,
+		})
+	}
+	return toVCS(vcs.BitbucketCloud, p.Repository.FullName, p.Repository.Links.HTML.Href, p.Actor.DisplayName, changeList)
+}
+
+// escapeFilePath escapes each segment of the file path.
+func escapeFilePath(filePath string) string {
+	var segments []string
+	for _, segment := range strings.Split(filePath, "/") {
+		segments = append(segments, url.PathEscape(segment))
+	}
+	return strings.Join(segments, "/")
+}
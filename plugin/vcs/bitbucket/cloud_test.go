@@ -0,0 +1,340 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/vcs"
+)
+
+func TestCloudProvider_FetchCommitByID(t *testing.T) {
+	p := newMockCloudProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/2.0/repositories/octocat/repo/commit/abc123", r.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "type": "commit",
+  "hash": "abc123",
+  "date": "2022-12-01T08:00:00+00:00",
+  "message": "Add migration\n\nCreate the user table.",
+  "author": {
+    "type": "author",
+    "raw": "Jane Doe <jane@example.com>",
+    "user": {
+      "display_name": "Jane Doe",
+      "nickname": "jane"
+    }
+  },
+  "links": {
+    "html": {
+      "href": "https://bitbucket.org/octocat/repo/commits/abc123"
+    }
+  }
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.FetchCommitByID(ctx, common.OauthContext{}, bitbucketCloudURL, "octocat/repo", "abc123")
+	require.NoError(t, err)
+
+	want := &vcs.Commit{
+		ID:          "abc123",
+		Title:       "Add migration",
+		Message:     "Add migration\n\nCreate the user table.",
+		CreatedTs:   1669881600,
+		URL:         "https://bitbucket.org/octocat/repo/commits/abc123",
+		AuthorName:  "Jane Doe",
+		AuthorEmail: "jane@example.com",
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestCloudProvider_GetDiffFileList(t *testing.T) {
+	p := newMockCloudProvider(func(r *http.Request) (*http.Response, error) {
+		// Bitbucket Cloud compares the first commit against the second one.
+		assert.Equal(t, "/2.0/repositories/octocat/repo/diffstat/after_sha..before_sha", r.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "pagelen": 100,
+  "values": [
+    {"status": "added", "old": null, "new": {"path": "prod/a.sql"}},
+    {"status": "modified", "old": {"path": "prod/b.sql"}, "new": {"path": "prod/b.sql"}},
+    {"status": "removed", "old": {"path": "prod/c.sql"}, "new": null},
+    {"status": "renamed", "old": {"path": "prod/d.sql"}, "new": {"path": "prod/e.sql"}}
+  ],
+  "page": 1
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.GetDiffFileList(ctx, common.OauthContext{}, bitbucketCloudURL, "octocat/repo", "before_sha", "after_sha")
+	require.NoError(t, err)
+
+	want := []vcs.FileDiff{
+		{Path: "prod/a.sql", Type: vcs.FileDiffTypeAdded},
+		{Path: "prod/b.sql", Type: vcs.FileDiffTypeModified},
+		{Path: "prod/c.sql", Type: vcs.FileDiffTypeRemoved},
+		{Path: "prod/d.sql", Type: vcs.FileDiffTypeRemoved},
+		{Path: "prod/e.sql", Type: vcs.FileDiffTypeAdded},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestCloudProvider_FetchAllRepositoryList(t *testing.T) {
+	p := newMockCloudProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/2.0/repositories", r.URL.Path)
+		assert.Equal(t, "admin", r.URL.Query().Get("role"))
+		if r.URL.Query().Get("page") == "" {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body: io.NopCloser(strings.NewReader(`
+{
+  "values": [
+    {"name": "repo1", "full_name": "octocat/repo1", "links": {"html": {"href": "https://bitbucket.org/octocat/repo1"}}}
+  ],
+  "next": "https://api.bitbucket.org/2.0/repositories?role=admin&page=2"
+}
+`)),
+			}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "values": [
+    {"name": "repo2", "full_name": "octocat/repo2", "links": {"html": {"href": "https://bitbucket.org/octocat/repo2"}}}
+  ]
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.FetchAllRepositoryList(ctx, common.OauthContext{}, bitbucketCloudURL)
+	require.NoError(t, err)
+
+	want := []*vcs.Repository{
+		{
+			Name:     "repo1",
+			FullPath: "octocat/repo1",
+			WebURL:   "https://bitbucket.org/octocat/repo1",
+		},
+		{
+			Name:     "repo2",
+			FullPath: "octocat/repo2",
+			WebURL:   "https://bitbucket.org/octocat/repo2",
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestCloudProvider_CreateFile(t *testing.T) {
+	p := newMockCloudProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/2.0/repositories/octocat/repo/src", r.URL.Path)
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, "CREATE TABLE t (id INT);", r.FormValue("prod/a.sql"))
+		assert.Equal(t, "Add migration", r.FormValue("message"))
+		assert.Equal(t, "main", r.FormValue("branch"))
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	err := p.CreateFile(ctx, common.OauthContext{}, bitbucketCloudURL, "octocat/repo", "prod/a.sql",
+		vcs.FileCommitCreate{
+			Branch:        "main",
+			Content:       "CREATE TABLE t (id INT);",
+			CommitMessage: "Add migration",
+		},
+	)
+	require.NoError(t, err)
+}
+
+func TestCloudProvider_CreateWebhook(t *testing.T) {
+	p := newMockCloudProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/2.0/repositories/octocat/repo/hooks", r.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       io.NopCloser(strings.NewReader(`{"uuid": "{8c9d3c0e-6b5a-4e2b-8a3c-1a2b3c4d5e6f}"}`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.CreateWebhook(ctx, common.OauthContext{}, bitbucketCloudURL, "octocat/repo", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "{8c9d3c0e-6b5a-4e2b-8a3c-1a2b3c4d5e6f}", got)
+}
+
+func TestCloudProvider_CreateCommitStatus(t *testing.T) {
+	p := newMockCloudProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/2.0/repositories/octocat/repo/commit/after_sha/statuses/build", r.URL.Path)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"key":"bytebase/Prod","state":"FAILED","name":"bytebase/Prod","url":"https://bytebase.example.com/issue/1","description":"Migration failed"}`, string(body))
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       io.NopCloser(strings.NewReader(`{"key": "bytebase/Prod", "state": "FAILED"}`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	err := p.CreateCommitStatus(ctx, common.OauthContext{}, bitbucketCloudURL, "octocat/repo", "after_sha",
+		&vcs.CommitStatus{
+			State:       vcs.CommitStateFailure,
+			Context:     "bytebase/Prod",
+			Description: "Migration failed",
+			TargetURL:   "https://bytebase.example.com/issue/1",
+		},
+	)
+	require.NoError(t, err)
+}
+
+func TestCloudProvider_CreatePullRequestComment(t *testing.T) {
+	p := newMockCloudProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/2.0/repositories/octocat/repo/pullrequests/1/comments", r.URL.Path)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"content":{"raw":"Migration done"}}`, string(body))
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       io.NopCloser(strings.NewReader(`{"id": 1}`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	err := p.CreatePullRequestComment(ctx, common.OauthContext{}, bitbucketCloudURL, "octocat/repo", "1", "Migration done")
+	require.NoError(t, err)
+}
+
+func TestCloudProvider_RefreshToken(t *testing.T) {
+	calledRefresher := false
+	p := newMockCloudProvider(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path == "/site/oauth2/access_token" {
+			clientID, clientSecret, ok := r.BasicAuth()
+			assert.True(t, ok)
+			assert.Equal(t, "client_id", clientID)
+			assert.Equal(t, "client_secret", clientSecret)
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "refresh_token", r.PostForm.Get("grant_type"))
+			assert.Equal(t, "refresh_token", r.PostForm.Get("refresh_token"))
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"access_token": "new_token", "refresh_token": "new_refresh_token", "expires_in": 7200}`)),
+			}, nil
+		}
+
+		if r.Header.Get("Authorization") == "Bearer expired_token" {
+			return &http.Response{
+				StatusCode: http.StatusUnauthorized,
+				Body:       io.NopCloser(strings.NewReader(`{"type": "error", "error": {"message": "Access token expired."}}`)),
+			}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"display_name": "Jane Doe"}`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.FetchUserInfo(ctx,
+		common.OauthContext{
+			ClientID:     "client_id",
+			ClientSecret: "client_secret",
+			AccessToken:  "expired_token",
+			RefreshToken: "refresh_token",
+			Refresher: func(accessToken, refreshToken string, expiresTs int64) error {
+				calledRefresher = true
+				assert.Equal(t, "new_token", accessToken)
+				assert.Equal(t, "new_refresh_token", refreshToken)
+				assert.NotZero(t, expiresTs)
+				return nil
+			},
+		},
+		bitbucketCloudURL,
+		"jane",
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "Jane Doe", got.Name)
+	assert.True(t, calledRefresher)
+}
+
+func TestWebhookPushEvent_ToVCS(t *testing.T) {
+	var pushEvent WebhookPushEvent
+	err := json.Unmarshal([]byte(`
+{
+  "actor": {"display_name": "Jane Doe", "nickname": "jane"},
+  "repository": {"full_name": "octocat/repo", "links": {"html": {"href": "https://bitbucket.org/octocat/repo"}}},
+  "push": {
+    "changes": [
+      {
+        "old": {"type": "branch", "name": "main", "target": {"hash": "before_sha"}},
+        "new": {"type": "branch", "name": "main", "target": {"hash": "after_sha"}}
+      },
+      {
+        "old": null,
+        "new": {"type": "branch", "name": "feature", "target": {"hash": "after_sha"}}
+      },
+      {
+        "old": {"type": "tag", "name": "v1", "target": {"hash": "before_sha"}},
+        "new": {"type": "tag", "name": "v1", "target": {"hash": "after_sha"}}
+      }
+    ]
+  }
+}
+`), &pushEvent)
+	require.NoError(t, err)
+
+	want := []vcs.PushEvent{
+		{
+			VCSType:            vcs.BitbucketCloud,
+			Ref:                "refs/heads/main",
+			Before:             "before_sha",
+			After:              "after_sha",
+			RepositoryID:       "octocat/repo",
+			RepositoryURL:      "https://bitbucket.org/octocat/repo",
+			RepositoryFullPath: "octocat/repo",
+			AuthorName:         "Jane Doe",
+		},
+	}
+	assert.Equal(t, want, pushEvent.ToVCS())
+}
+
+func newMockCloudProvider(mockRoundTrip func(r *http.Request) (*http.Response, error)) vcs.Provider {
+	return newCloudProvider(
+		vcs.ProviderConfig{
+			Client: &http.Client{
+				Transport: &common.MockRoundTripper{
+					MockRoundTrip: mockRoundTrip,
+				},
+			},
+		},
+	)
+}
@@ -0,0 +1,1166 @@
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/vcs"
+	"github.com/bytebase/bytebase/plugin/vcs/internal/oauth"
+)
+
+var _ vcs.Provider = (*DataCenterProvider)(nil)
+
+// DataCenterProvider is a Bitbucket Data Center VCS provider.
+type DataCenterProvider struct {
+	client *http.Client
+}
+
+func newDataCenterProvider(config vcs.ProviderConfig) vcs.Provider {
+	if config.Client == nil {
+		config.Client = &http.Client{}
+	}
+	return &DataCenterProvider{
+		client: config.Client,
+	}
+}
+
+// APIURL returns the API URL path of a Bitbucket Data Center instance.
+func (*DataCenterProvider) APIURL(instanceURL string) string {
+	return fmt.Sprintf("%s/rest/api/1.0", instanceURL)
+}
+
+// DataCenterUser represents a Bitbucket Data Center API response for a user.
+type DataCenterUser struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	Slug         string `json:"slug"`
+	EmailAddress string `json:"emailAddress"`
+	DisplayName  string `json:"displayName"`
+	Active       bool   `json:"active"`
+}
+
+// DataCenterProject represents a Bitbucket Data Center API response for a project.
+type DataCenterProject struct {
+	Key string `json:"key"`
+}
+
+// DataCenterLink represents a Bitbucket Data Center API link.
+type DataCenterLink struct {
+	Href string `json:"href"`
+}
+
+// DataCenterRepository represents a Bitbucket Data Center API response for a repository.
+type DataCenterRepository struct {
+	ID      int64             `json:"id"`
+	Slug    string            `json:"slug"`
+	Name    string            `json:"name"`
+	Project DataCenterProject `json:"project"`
+	Links   struct {
+		Self []DataCenterLink `json:"self"`
+	} `json:"links"`
+}
+
+// DataCenterCommit represents a Bitbucket Data Center API response for a commit.
+type DataCenterCommit struct {
+	ID      string         `json:"id"`
+	Message string         `json:"message"`
+	Author  DataCenterUser `json:"author"`
+	// AuthorTimestamp is the Unix timestamp in milliseconds.
+	AuthorTimestamp int64 `json:"authorTimestamp"`
+}
+
+// DataCenterChange represents a Bitbucket Data Center API response for a changed file.
+type DataCenterChange struct {
+	Path struct {
+		ToString string `json:"toString"`
+	} `json:"path"`
+	// SrcPath is the previous path of a moved or copied file.
+	SrcPath *struct {
+		ToString string `json:"toString"`
+	} `json:"srcPath,omitempty"`
+	// Available values: "ADD", "MODIFY", "DELETE", "MOVE", "COPY"
+	Type string `json:"type"`
+}
+
+// DataCenterPermittedUser represents a Bitbucket Data Center API response for
+// a user with the repository permission.
+type DataCenterPermittedUser struct {
+	User DataCenterUser `json:"user"`
+	// Available values: "REPO_READ", "REPO_WRITE", "REPO_ADMIN"
+	Permission string `json:"permission"`
+}
+
+// DataCenterBranch represents a Bitbucket Data Center API response for a branch.
+type DataCenterBranch struct {
+	ID           string `json:"id"`
+	DisplayID    string `json:"displayId"`
+	LatestCommit string `json:"latestCommit"`
+}
+
+// DataCenterBranchCreate represents a Bitbucket Data Center API request for creating a branch.
+type DataCenterBranchCreate struct {
+	Name       string `json:"name"`
+	StartPoint string `json:"startPoint"`
+}
+
+// DataCenterPullRequestRef is the source or target ref of the Bitbucket Data Center pull request.
+type DataCenterPullRequestRef struct {
+	ID           string `json:"id"`
+	LatestCommit string `json:"latestCommit,omitempty"`
+}
+
+// DataCenterPullRequest represents a Bitbucket Data Center API message for a pull request.
+type DataCenterPullRequest struct {
+	ID          int                      `json:"id,omitempty"`
+	Title       string                   `json:"title"`
+	Description string                   `json:"description"`
+	FromRef     DataCenterPullRequestRef `json:"fromRef"`
+	ToRef       DataCenterPullRequestRef `json:"toRef"`
+	Links       *struct {
+		Self []DataCenterLink `json:"self"`
+	} `json:"links,omitempty"`
+}
+
+// DataCenterWebhookConfiguration is the configuration of the Bitbucket Data Center webhook.
+type DataCenterWebhookConfiguration struct {
+	// Secret is used as the key to generate the HMAC hex digest value in the
+	// X-Hub-Signature header.
+	Secret string `json:"secret"`
+}
+
+// DataCenterWebhookCreateOrUpdate represents a Bitbucket Data Center API request
+// for creating or updating a webhook.
+type DataCenterWebhookCreateOrUpdate struct {
+	Name string `json:"name"`
+	// URL is the URL to which the payloads will be delivered.
+	URL string `json:"url"`
+	// Events determines what events the hook is triggered for, e.g. "repo:refs_changed".
+	Events        []string                       `json:"events"`
+	Active        bool                           `json:"active"`
+	Configuration DataCenterWebhookConfiguration `json:"configuration"`
+}
+
+// DataCenterWebhookInfo represents a Bitbucket Data Center API response for the webhook information.
+type DataCenterWebhookInfo struct {
+	ID int `json:"id"`
+}
+
+// DataCenterRefChange is a ref change in the Bitbucket Data Center refs changed event.
+type DataCenterRefChange struct {
+	Ref struct {
+		ID        string `json:"id"`
+		DisplayID string `json:"displayId"`
+		// Available values: "BRANCH", "TAG"
+		Type string `json:"type"`
+	} `json:"ref"`
+	RefID    string `json:"refId"`
+	FromHash string `json:"fromHash"`
+	ToHash   string `json:"toHash"`
+	// Available values: "ADD", "DELETE", "UPDATE"
+	Type string `json:"type"`
+}
+
+// WebhookRefsChangedEvent is the API message for Bitbucket Data Center webhook
+// refs changed event.
+//
+// Docs: https://confluence.atlassian.com/bitbucketserver/event-payload-938025882.html#Eventpayload-Push
+type WebhookRefsChangedEvent struct {
+	EventKey   WebhookType           `json:"eventKey"`
+	Actor      DataCenterUser        `json:"actor"`
+	Repository DataCenterRepository  `json:"repository"`
+	Changes    []DataCenterRefChange `json:"changes"`
+}
+
+// dataCenterPage is the paginated Bitbucket Data Center API response.
+type dataCenterPage struct {
+	Values        json.RawMessage `json:"values"`
+	IsLastPage    bool            `json:"isLastPage"`
+	NextPageStart int             `json:"nextPageStart"`
+}
+
+// repositoryAPIURL returns the API URL of the repository with given repository
+// ID in the format of "{projectKey}/{repositorySlug}".
+func (p *DataCenterProvider) repositoryAPIURL(instanceURL, repositoryID string) (string, error) {
+	projectKey, repositorySlug, err := splitRepositoryID(repositoryID)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/projects/%s/repos/%s", p.APIURL(instanceURL), url.PathEscape(projectKey), url.PathEscape(repositorySlug)), nil
+}
+
+// get sends a GET request to the given URL and returns the response body. The
+// resource is used in the error message.
+func (p *DataCenterProvider) get(ctx context.Context, oauthCtx common.OauthContext, instanceURL, url, resource string) (string, error) {
+	code, _, body, err := oauth.Get(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		dataCenterTokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return "", errors.Wrapf(err, "GET %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return "", common.Errorf(common.NotFound, "failed to fetch %s from URL %s", resource, url)
+	} else if code >= 300 {
+		return "", errors.Errorf("failed to fetch %s from URL %s, status code: %d, body: %s",
+			resource,
+			url,
+			code,
+			body,
+		)
+	}
+	return body, nil
+}
+
+// fetchPaginatedList fetches all the values from the given URL page by page,
+// and calls the callback with the values of each page.
+func (p *DataCenterProvider) fetchPaginatedList(ctx context.Context, oauthCtx common.OauthContext, instanceURL, url, resource string, callback func(values json.RawMessage) error) error {
+	separator := "?"
+	if strings.Contains(url, "?") {
+		separator = "&"
+	}
+	start := 0
+	for {
+		body, err := p.get(ctx, oauthCtx, instanceURL, fmt.Sprintf("%s%sstart=%d&limit=%d", url, separator, start, apiPageSize), resource)
+		if err != nil {
+			return err
+		}
+
+		var page dataCenterPage
+		if err := json.Unmarshal([]byte(body), &page); err != nil {
+			return errors.Wrap(err, "unmarshal body")
+		}
+		if err := callback(page.Values); err != nil {
+			return err
+		}
+		if page.IsLastPage || page.NextPageStart <= start {
+			return nil
+		}
+		start = page.NextPageStart
+	}
+}
+
+// ExchangeOAuthToken exchanges OAuth content with the provided authorization code.
+//
+// Docs: https://confluence.atlassian.com/bitbucketserver/bitbucket-oauth-2-0-provider-api-1108483661.html
+func (p *DataCenterProvider) ExchangeOAuthToken(ctx context.Context, instanceURL string, oauthExchange *common.OAuthExchange) (*vcs.OAuthToken, error) {
+	params := &url.Values{}
+	params.Set("client_id", oauthExchange.ClientID)
+	params.Set("client_secret", oauthExchange.ClientSecret)
+	params.Set("code", oauthExchange.Code)
+	params.Set("grant_type", "authorization_code")
+	params.Set("redirect_uri", oauthExchange.RedirectURL)
+	url := fmt.Sprintf("%s/rest/oauth2/latest/token", instanceURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, errors.Wrapf(err, "construct POST %s", url)
+	}
+
+	oauthResp, err := requestOAuthToken(p.client, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to exchange OAuth token")
+	}
+	return oauthResp.toVCSOAuthToken(), nil
+}
+
+// TryLogin tries to fetch the user info from the current OAuth context.
+//
+// Bitbucket Data Center doesn't have an endpoint for the current user, but the
+// username is returned in the X-AUSERNAME header of every authenticated request.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-system-maintenance/#api-api-latest-application-properties-get
+func (p *DataCenterProvider) TryLogin(ctx context.Context, oauthCtx common.OauthContext, instanceURL string) (*vcs.UserInfo, error) {
+	url := fmt.Sprintf("%s/application-properties", p.APIURL(instanceURL))
+	code, header, body, err := oauth.Get(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		dataCenterTokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "GET %s", url)
+	}
+	if code >= 300 {
+		return nil, errors.Errorf("failed to read application properties from URL %s, status code: %d, body: %s", url, code, body)
+	}
+
+	username := header.Get("X-AUSERNAME")
+	if username == "" {
+		return nil, errors.Errorf("failed to get the username of the current user from URL %s", url)
+	}
+	return p.FetchUserInfo(ctx, oauthCtx, instanceURL, username)
+}
+
+// FetchCommitByID fetches the commit data by its ID from the repository.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-commits-commitid-get
+func (p *DataCenterProvider) FetchCommitByID(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, commitID string) (*vcs.Commit, error) {
+	repositoryURL, err := p.repositoryAPIURL(instanceURL, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s/commits/%s", repositoryURL, commitID)
+	body, err := p.get(ctx, oauthCtx, instanceURL, url, "commit data")
+	if err != nil {
+		return nil, err
+	}
+
+	commit := &DataCenterCommit{}
+	if err := json.Unmarshal([]byte(body), commit); err != nil {
+		return nil, errors.Wrap(err, "unmarshal body")
+	}
+
+	authorName := commit.Author.DisplayName
+	if authorName == "" {
+		authorName = commit.Author.Name
+	}
+	return &vcs.Commit{
+		ID:          commit.ID,
+		Title:       splitCommitMessage(commit.Message),
+		Message:     commit.Message,
+		CreatedTs:   commit.AuthorTimestamp / 1000,
+		URL:         fmt.Sprintf("%s/projects/%s/commits/%s", instanceURL, strings.Replace(repositoryID, "/", "/repos/", 1), commit.ID),
+		AuthorName:  authorName,
+		AuthorEmail: commit.Author.EmailAddress,
+	}, nil
+}
+
+// GetDiffFileList gets the diff files list between two commits.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-changes-get
+func (p *DataCenterProvider) GetDiffFileList(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, beforeCommit, afterCommit string) ([]vcs.FileDiff, error) {
+	repositoryURL, err := p.repositoryAPIURL(instanceURL, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s/changes?since=%s&until=%s", repositoryURL, beforeCommit, afterCommit)
+	var ret []vcs.FileDiff
+	if err := p.fetchPaginatedList(ctx, oauthCtx, instanceURL, url, "file diff list", func(values json.RawMessage) error {
+		var changes []DataCenterChange
+		if err := json.Unmarshal(values, &changes); err != nil {
+			return errors.Wrapf(err, "failed to unmarshal file diff data from Bitbucket Data Center instance %s", instanceURL)
+		}
+		ret = append(ret, convertDataCenterChangeList(changes)...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// convertDataCenterChangeList converts the Bitbucket Data Center changes to the
+// file diffs. A moved file is treated as the old file is removed and the new
+// file is added, and a copied file is treated as the new file is added.
+func convertDataCenterChangeList(changes []DataCenterChange) []vcs.FileDiff {
+	var ret []vcs.FileDiff
+	for _, change := range changes {
+		switch change.Type {
+		case "ADD", "COPY":
+			ret = append(ret, vcs.FileDiff{Path: change.Path.ToString, Type: vcs.FileDiffTypeAdded})
+		case "MODIFY":
+			ret = append(ret, vcs.FileDiff{Path: change.Path.ToString, Type: vcs.FileDiffTypeModified})
+		case "DELETE":
+			ret = append(ret, vcs.FileDiff{Path: change.Path.ToString, Type: vcs.FileDiffTypeRemoved})
+		case "MOVE":
+			if change.SrcPath != nil {
+				ret = append(ret, vcs.FileDiff{Path: change.SrcPath.ToString, Type: vcs.FileDiffTypeRemoved})
+			}
+			ret = append(ret, vcs.FileDiff{Path: change.Path.ToString, Type: vcs.FileDiffTypeAdded})
+		}
+	}
+	return ret
+}
+
+// FetchUserInfo fetches user info of given user slug.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-system-maintenance/#api-api-latest-users-userslug-get
+func (p *DataCenterProvider) FetchUserInfo(ctx context.Context, oauthCtx common.OauthContext, instanceURL, userSlug string) (*vcs.UserInfo, error) {
+	url := fmt.Sprintf("%s/users/%s", p.APIURL(instanceURL), url.PathEscape(userSlug))
+	body, err := p.get(ctx, oauthCtx, instanceURL, url, "user info")
+	if err != nil {
+		return nil, err
+	}
+
+	var user DataCenterUser
+	if err := json.Unmarshal([]byte(body), &user); err != nil {
+		return nil, errors.Wrap(err, "unmarshal")
+	}
+	return &vcs.UserInfo{
+		PublicEmail: user.EmailAddress,
+		Name:        user.DisplayName,
+		State:       convertDataCenterUserState(user.Active),
+	}, nil
+}
+
+func convertDataCenterUserState(active bool) vcs.State {
+	if active {
+		return vcs.StateActive
+	}
+	return vcs.StateArchived
+}
+
+// FetchRepositoryActiveMemberList fetches all active members of a repository.
+// Only the users granted with the repository permission directly are returned.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-permission-management/#api-api-latest-projects-projectkey-repos-repositoryslug-permissions-users-get
+func (p *DataCenterProvider) FetchRepositoryActiveMemberList(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID string) ([]*vcs.RepositoryMember, error) {
+	repositoryURL, err := p.repositoryAPIURL(instanceURL, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s/permissions/users", repositoryURL)
+	var allMembers []*vcs.RepositoryMember
+	if err := p.fetchPaginatedList(ctx, oauthCtx, instanceURL, url, "repository member list", func(values json.RawMessage) error {
+		var users []DataCenterPermittedUser
+		if err := json.Unmarshal(values, &users); err != nil {
+			return errors.Wrap(err, "unmarshal")
+		}
+		for _, u := range users {
+			if !u.User.Active {
+				continue
+			}
+			if u.User.EmailAddress == "" {
+				return errors.Errorf("[ %v ] did not configure the email address in Bitbucket, please make sure all members have email address", u.User.Name)
+			}
+
+			// Bitbucket Data Center's "REPO_WRITE" and "REPO_ADMIN" permissions can
+			// both write to the repository.
+			role := common.ProjectDeveloper
+			if u.Permission == "REPO_ADMIN" || u.Permission == "REPO_WRITE" {
+				role = common.ProjectOwner
+			}
+			allMembers = append(allMembers,
+				&vcs.RepositoryMember{
+					Email:        u.User.EmailAddress,
+					Name:         u.User.DisplayName,
+					State:        vcs.StateActive,
+					Role:         role,
+					VCSRole:      u.Permission,
+					RoleProvider: vcs.BitbucketDataCenter,
+				},
+			)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return allMembers, nil
+}
+
+// FetchAllRepositoryList fetches all repositories where the authenticated user
+// has admin permissions, which is required to create webhook in the repository.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-repos-get
+func (p *DataCenterProvider) FetchAllRepositoryList(ctx context.Context, oauthCtx common.OauthContext, instanceURL string) ([]*vcs.Repository, error) {
+	url := fmt.Sprintf("%s/repos?permission=REPO_ADMIN", p.APIURL(instanceURL))
+	var allRepos []*vcs.Repository
+	if err := p.fetchPaginatedList(ctx, oauthCtx, instanceURL, url, "repository list", func(values json.RawMessage) error {
+		var repos []DataCenterRepository
+		if err := json.Unmarshal(values, &repos); err != nil {
+			return errors.Wrap(err, "unmarshal")
+		}
+		for _, r := range repos {
+			var webURL string
+			if len(r.Links.Self) > 0 {
+				webURL = strings.TrimSuffix(r.Links.Self[0].Href, "/browse")
+			}
+			allRepos = append(allRepos,
+				&vcs.Repository{
+					ID:       r.ID,
+					Name:     r.Name,
+					FullPath: fmt.Sprintf("%s/%s", r.Project.Key, r.Slug),
+					WebURL:   webURL,
+				},
+			)
+		}
+		return nil
+	}); err != nil {
+		return nil, errors.Wrap(err, "fetch paginated list")
+	}
+	return allRepos, nil
+}
+
+// FetchRepositoryFileList fetches the all files from the given repository tree
+// recursively.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-files-path-get
+func (p *DataCenterProvider) FetchRepositoryFileList(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, ref, filePath string) ([]*vcs.RepositoryTreeNode, error) {
+	repositoryURL, err := p.repositoryAPIURL(instanceURL, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	filePath = strings.Trim(filePath, "/")
+	url := fmt.Sprintf("%s/files/%s?at=%s", repositoryURL, escapeFilePath(filePath), url.QueryEscape(ref))
+	var allTreeNodes []*vcs.RepositoryTreeNode
+	if err := p.fetchPaginatedList(ctx, oauthCtx, instanceURL, url, "repository file list", func(values json.RawMessage) error {
+		// The values are the file paths relative to the requested directory.
+		var paths []string
+		if err := json.Unmarshal(values, &paths); err != nil {
+			return errors.Wrap(err, "unmarshal body")
+		}
+		for _, relativePath := range paths {
+			allTreeNodes = append(allTreeNodes,
+				&vcs.RepositoryTreeNode{
+					Path: path.Join(filePath, relativePath),
+					Type: "blob",
+				},
+			)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return allTreeNodes, nil
+}
+
+// CreateFile creates a file at given path in the repository.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-browse-path-put
+func (p *DataCenterProvider) CreateFile(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, filePath string, fileCommitCreate vcs.FileCommitCreate) error {
+	repositoryURL, err := p.repositoryAPIURL(instanceURL, repositoryID)
+	if err != nil {
+		return err
+	}
+
+	fields := map[string]string{
+		"content": fileCommitCreate.Content,
+		"message": fileCommitCreate.CommitMessage,
+		"branch":  fileCommitCreate.Branch,
+	}
+	// The source commit ID is required to update an existing file, and the
+	// request would be rejected if the file has been changed since the commit.
+	if fileCommitCreate.LastCommitID != "" {
+		fields["sourceCommitId"] = fileCommitCreate.LastCommitID
+	}
+	contentType, body, err := newMultipartForm(fields)
+	if err != nil {
+		return errors.Wrap(err, "create multipart form")
+	}
+
+	url := fmt.Sprintf("%s/browse/%s", repositoryURL, escapeFilePath(filePath))
+	code, _, resp, err := oauth.PutWithContentType(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		contentType,
+		bytes.NewReader(body),
+		dataCenterTokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "PUT %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to create/update file through URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to create/update file through URL %s, status code: %d, body: %s",
+			url,
+			code,
+			resp,
+		)
+	}
+	return nil
+}
+
+// OverwriteFile overwrites an existing file at given path in the repository.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-browse-path-put
+func (p *DataCenterProvider) OverwriteFile(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, filePath string, fileCommitCreate vcs.FileCommitCreate) error {
+	return p.CreateFile(ctx, oauthCtx, instanceURL, repositoryID, filePath, fileCommitCreate)
+}
+
+// ReadFileMeta reads the metadata of the given file in the repository.
+//
+// Bitbucket Data Center doesn't return the file size, and the last commit ID is
+// the latest commit touching the file on the given ref.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-commits-get
+func (p *DataCenterProvider) ReadFileMeta(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, filePath, ref string) (*vcs.FileMeta, error) {
+	repositoryURL, err := p.repositoryAPIURL(instanceURL, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s/commits?path=%s&until=%s&limit=1", repositoryURL, url.QueryEscape(filePath), url.QueryEscape(ref))
+	body, err := p.get(ctx, oauthCtx, instanceURL, url, "file metadata")
+	if err != nil {
+		return nil, err
+	}
+
+	var page dataCenterPage
+	if err := json.Unmarshal([]byte(body), &page); err != nil {
+		return nil, errors.Wrap(err, "unmarshal body")
+	}
+	var commits []DataCenterCommit
+	if err := json.Unmarshal(page.Values, &commits); err != nil {
+		return nil, errors.Wrap(err, "unmarshal commits")
+	}
+	if len(commits) == 0 {
+		return nil, common.Errorf(common.NotFound, "failed to read file metadata from URL %s", url)
+	}
+
+	return &vcs.FileMeta{
+		Name:         path.Base(filePath),
+		Path:         filePath,
+		LastCommitID: commits[0].ID,
+	}, nil
+}
+
+// ReadFileContent reads the content of the given file in the repository.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-raw-path-get
+func (p *DataCenterProvider) ReadFileContent(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, filePath, ref string) (string, error) {
+	repositoryURL, err := p.repositoryAPIURL(instanceURL, repositoryID)
+	if err != nil {
+		return "", err
+	}
+	url := fmt.Sprintf("%s/raw/%s?at=%s", repositoryURL, escapeFilePath(filePath), url.QueryEscape(ref))
+	return p.get(ctx, oauthCtx, instanceURL, url, "file content")
+}
+
+// ListPullRequestFile lists the changed files in the pull request.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-pull-requests/#api-api-latest-projects-projectkey-repos-repositoryslug-pull-requests-pullrequestid-changes-get
+func (p *DataCenterProvider) ListPullRequestFile(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, pullRequestID string) ([]*vcs.PullRequestFile, error) {
+	repositoryURL, err := p.repositoryAPIURL(instanceURL, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	body, err := p.get(ctx, oauthCtx, instanceURL, fmt.Sprintf("%s/pull-requests/%s", repositoryURL, pullRequestID), "pull request")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get pull request")
+	}
+	var pullRequest DataCenterPullRequest
+	if err := json.Unmarshal([]byte(body), &pullRequest); err != nil {
+		return nil, errors.Wrap(err, "unmarshal body")
+	}
+
+	url := fmt.Sprintf("%s/pull-requests/%s/changes", repositoryURL, pullRequestID)
+	var res []*vcs.PullRequestFile
+	if err := p.fetchPaginatedList(ctx, oauthCtx, instanceURL, url, "pull request file", func(values json.RawMessage) error {
+		var changes []DataCenterChange
+		if err := json.Unmarshal(values, &changes); err != nil {
+			return errors.Wrap(err, "unmarshal body")
+		}
+		for _, fileDiff := range convertDataCenterChangeList(changes) {
+			res = append(res, &vcs.PullRequestFile{
+				Path:         fileDiff.Path,
+				LastCommitID: pullRequest.FromRef.LatestCommit,
+				IsDeleted:    fileDiff.Type == vcs.FileDiffTypeRemoved,
+			})
+		}
+		return nil
+	}); err != nil {
+		return nil, errors.Wrap(err, "Failed to list pull request file")
+	}
+	return res, nil
+}
+
+// GetBranch gets the given branch in the repository.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-branches-get
+func (p *DataCenterProvider) GetBranch(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, branchName string) (*vcs.BranchInfo, error) {
+	repositoryURL, err := p.repositoryAPIURL(instanceURL, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	// The filter text matches the branch name partially, so we need to find the
+	// exact one from the result.
+	url := fmt.Sprintf("%s/branches?filterText=%s", repositoryURL, url.QueryEscape(branchName))
+	var branchInfo *vcs.BranchInfo
+	if err := p.fetchPaginatedList(ctx, oauthCtx, instanceURL, url, "branch", func(values json.RawMessage) error {
+		var branches []DataCenterBranch
+		if err := json.Unmarshal(values, &branches); err != nil {
+			return errors.Wrap(err, "unmarshal body")
+		}
+		for _, branch := range branches {
+			if branch.DisplayID == branchName {
+				branchInfo = &vcs.BranchInfo{
+					Name:         branch.DisplayID,
+					LastCommitID: branch.LatestCommit,
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if branchInfo == nil {
+		return nil, common.Errorf(common.NotFound, "failed to get branch %q from URL %s", branchName, url)
+	}
+	return branchInfo, nil
+}
+
+// CreateBranch creates the branch in the repository.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-branches-post
+func (p *DataCenterProvider) CreateBranch(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID string, branch *vcs.BranchInfo) error {
+	repositoryURL, err := p.repositoryAPIURL(instanceURL, repositoryID)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(DataCenterBranchCreate{
+		Name:       branch.Name,
+		StartPoint: branch.LastCommitID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "marshal branch create")
+	}
+
+	url := fmt.Sprintf("%s/branches", repositoryURL)
+	code, _, resp, err := oauth.Post(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		bytes.NewReader(body),
+		dataCenterTokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to create branch from URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to create branch from URL %s, status code: %d, body: %s",
+			url,
+			code,
+			resp,
+		)
+	}
+	return nil
+}
+
+// CreatePullRequest creates the pull request in the repository.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-pull-requests/#api-api-latest-projects-projectkey-repos-repositoryslug-pull-requests-post
+func (p *DataCenterProvider) CreatePullRequest(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID string, pullRequestCreate *vcs.PullRequestCreate) (*vcs.PullRequest, error) {
+	repositoryURL, err := p.repositoryAPIURL(instanceURL, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(DataCenterPullRequest{
+		Title:       pullRequestCreate.Title,
+		Description: pullRequestCreate.Body,
+		FromRef:     DataCenterPullRequestRef{ID: fmt.Sprintf("refs/heads/%s", pullRequestCreate.Head)},
+		ToRef:       DataCenterPullRequestRef{ID: fmt.Sprintf("refs/heads/%s", pullRequestCreate.Base)},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal pull request create")
+	}
+
+	url := fmt.Sprintf("%s/pull-requests", repositoryURL)
+	code, _, resp, err := oauth.Post(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		bytes.NewReader(body),
+		dataCenterTokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return nil, common.Errorf(common.NotFound, "failed to create pull request from URL %s", url)
+	} else if code >= 300 {
+		return nil, errors.Errorf("failed to create pull request from URL %s, status code: %d, body: %s",
+			url,
+			code,
+			resp,
+		)
+	}
+
+	var res DataCenterPullRequest
+	if err := json.Unmarshal([]byte(resp), &res); err != nil {
+		return nil, err
+	}
+	if res.Links == nil || len(res.Links.Self) == 0 {
+		return nil, errors.Errorf("missing the links of the created pull request")
+	}
+
+	return &vcs.PullRequest{
+		URL: res.Links.Self[0].Href,
+	}, nil
+}
+
+// DataCenterPullRequestCommentCreate is the API message for creating a pull
+// request comment in Bitbucket Data Center.
+type DataCenterPullRequestCommentCreate struct {
+	Text string `json:"text"`
+}
+
+// CreatePullRequestComment creates a comment in the pull request.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v805/api-group-pull-requests/#api-api-latest-projects-projectkey-repos-repositoryslug-pull-requests-pullrequestid-comments-post
+func (p *DataCenterProvider) CreatePullRequestComment(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, pullRequestID, comment string) error {
+	repositoryURL, err := p.repositoryAPIURL(instanceURL, repositoryID)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(DataCenterPullRequestCommentCreate{Text: comment})
+	if err != nil {
+		return errors.Wrap(err, "marshal pull request comment create")
+	}
+
+	url := fmt.Sprintf("%s/pull-requests/%s/comments", repositoryURL, pullRequestID)
+	code, _, resp, err := oauth.Post(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		bytes.NewReader(body),
+		dataCenterTokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to create pull request comment from URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to create pull request comment from URL %s, status code: %d, body: %s",
+			url,
+			code,
+			resp,
+		)
+	}
+	return nil
+}
+
+// DataCenterCommitStatusCreate is the API message for creating a commit build
+// status in Bitbucket Data Center.
+type DataCenterCommitStatusCreate struct {
+	Key         string `json:"key"`
+	State       string `json:"state"`
+	Name        string `json:"name"`
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// CreateCommitStatus creates the status of the commit, which is shown as a
+// build status of the commit.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v805/api-group-builds-and-deployments/#api-api-latest-projects-projectkey-repos-repositoryslug-commits-commitid-builds-post
+func (p *DataCenterProvider) CreateCommitStatus(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, commitID string, status *vcs.CommitStatus) error {
+	repositoryURL, err := p.repositoryAPIURL(instanceURL, repositoryID)
+	if err != nil {
+		return err
+	}
+	state, err := convertToBuildState(status.State)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(
+		DataCenterCommitStatusCreate{
+			Key:         status.Context,
+			State:       state,
+			Name:        status.Context,
+			URL:         status.TargetURL,
+			Description: status.Description,
+		},
+	)
+	if err != nil {
+		return errors.Wrap(err, "marshal commit status create")
+	}
+
+	url := fmt.Sprintf("%s/commits/%s/builds", repositoryURL, commitID)
+	code, _, resp, err := oauth.Post(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		bytes.NewReader(body),
+		dataCenterTokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to create commit status from URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to create commit status from URL %s, status code: %d, body: %s",
+			url,
+			code,
+			resp,
+		)
+	}
+	return nil
+}
+
+// ListPullRequest lists the open pull requests to the base branch in the repository.
+//
+// NOTE: It's only used to write back the latest schema by pull requests, which
+// is not supported for Bitbucket yet.
+func (*DataCenterProvider) ListPullRequest(_ context.Context, _ common.OauthContext, _, _, _ string) ([]*vcs.PullRequest, error) {
+	return nil, common.Errorf(common.NotImplemented, "listing pull requests is not supported for Bitbucket Data Center")
+}
+
+// ClosePullRequest closes the pull request.
+//
+// NOTE: It's only used to write back the latest schema by pull requests, which
+// is not supported for Bitbucket yet.
+func (*DataCenterProvider) ClosePullRequest(_ context.Context, _ common.OauthContext, _, _, _ string) error {
+	return common.Errorf(common.NotImplemented, "closing pull requests is not supported for Bitbucket Data Center")
+}
+
+// UpsertEnvironmentVariable creates or updates the environment variable in the repository.
+//
+// NOTE: Bitbucket Data Center doesn't have the built-in CI, so there is no
+// environment variable to set up.
+func (*DataCenterProvider) UpsertEnvironmentVariable(_ context.Context, _ common.OauthContext, _, _, _, _ string) error {
+	return common.Errorf(common.NotImplemented, "environment variable is not supported for Bitbucket Data Center")
+}
+
+// CreateWebhook creates a webhook in the repository with given payload.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-webhooks-post
+func (p *DataCenterProvider) CreateWebhook(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID string, payload []byte) (string, error) {
+	repositoryURL, err := p.repositoryAPIURL(instanceURL, repositoryID)
+	if err != nil {
+		return "", err
+	}
+	url := fmt.Sprintf("%s/webhooks", repositoryURL)
+	code, _, body, err := oauth.Post(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		bytes.NewReader(payload),
+		dataCenterTokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return "", errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return "", common.Errorf(common.NotFound, "failed to create webhook through URL %s", url)
+	}
+
+	// Bitbucket Data Center returns 201 HTTP status codes upon successful webhook creation.
+	if code != http.StatusCreated {
+		return "", errors.Errorf("failed to create webhook through URL %s, status code: %d, body: %s",
+			url,
+			code,
+			body,
+		)
+	}
+
+	var webhookInfo DataCenterWebhookInfo
+	if err = json.Unmarshal([]byte(body), &webhookInfo); err != nil {
+		return "", errors.Wrap(err, "unmarshal body")
+	}
+	return fmt.Sprintf("%d", webhookInfo.ID), nil
+}
+
+// PatchWebhook patches the webhook in the repository with given payload.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-webhooks-webhookid-put
+func (p *DataCenterProvider) PatchWebhook(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, webhookID string, payload []byte) error {
+	repositoryURL, err := p.repositoryAPIURL(instanceURL, repositoryID)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/webhooks/%s", repositoryURL, webhookID)
+	code, _, body, err := oauth.Put(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		bytes.NewReader(payload),
+		dataCenterTokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "PUT %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to patch webhook through URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to patch webhook through URL %s, status code: %d, body: %s",
+			url,
+			code,
+			body,
+		)
+	}
+	return nil
+}
+
+// DeleteWebhook deletes the webhook from the repository.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-webhooks-webhookid-delete
+func (p *DataCenterProvider) DeleteWebhook(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, webhookID string) error {
+	repositoryURL, err := p.repositoryAPIURL(instanceURL, repositoryID)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/webhooks/%s", repositoryURL, webhookID)
+	code, _, body, err := oauth.Delete(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		dataCenterTokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "DELETE %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return nil // It is OK if the webhook has already gone
+	} else if code >= 300 {
+		return errors.Errorf("failed to delete webhook through URL %s, status code: %d, body: %s",
+			url,
+			code,
+			body,
+		)
+	}
+	return nil
+}
+
+func dataCenterTokenRefresher(instanceURL string, oauthCtx oauthContext, refresher common.TokenRefresher) oauth.TokenRefresher {
+	return func(ctx context.Context, client *http.Client, oldToken *string) error {
+		params := &url.Values{}
+		params.Set("client_id", oauthCtx.ClientID)
+		params.Set("client_secret", oauthCtx.ClientSecret)
+		params.Set("refresh_token", oauthCtx.RefreshToken)
+		params.Set("grant_type", "refresh_token")
+		url := fmt.Sprintf("%s/rest/oauth2/latest/token", instanceURL)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(params.Encode()))
+		if err != nil {
+			return errors.Wrapf(err, "construct POST %s", url)
+		}
+
+		r, err := requestOAuthToken(client, req)
+		if err != nil {
+			return err
+		}
+
+		// Update the old token to new value for retries.
+		*oldToken = r.AccessToken
+
+		token := r.toVCSOAuthToken()
+		return refresher(token.AccessToken, token.RefreshToken, token.ExpiresTs)
+	}
+}
+
+// ToVCS returns the push event in VCS format for each branch change.
+func (p WebhookRefsChangedEvent) ToVCS() []vcs.PushEvent {
+	var changeList []refChange
+	for _, change := range p.Changes {
+		if change.Ref.Type != "BRANCH" {
+			continue
+		}
+		changeList = append(changeList, refChange{
+			ref:    change.RefID,
+			before: change.FromHash,
+			after:  change.ToHash,
+		})
+	}
+	repositoryID := fmt.Sprintf("%s/%s", p.Repository.Project.Key, p.Repository.Slug)
+	var repositoryURL string
+	if len(p.Repository.Links.Self) > 0 {
+		repositoryURL = strings.TrimSuffix(p.Repository.Links.Self[0].Href, "/browse")
+	}
+	authorName := p.Actor.DisplayName
+	if authorName == "" {
+		authorName = p.Actor.Name
+	}
+	return toVCS(vcs.BitbucketDataCenter, repositoryID, repositoryURL, authorName, changeList)
+}
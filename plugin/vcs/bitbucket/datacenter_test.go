@@ -0,0 +1,316 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/vcs"
+)
+
+const testDataCenterURL = "https://bitbucket.example.com"
+
+func TestDataCenterProvider_TryLogin(t *testing.T) {
+	p := newMockDataCenterProvider(func(r *http.Request) (*http.Response, error) {
+		switch r.URL.Path {
+		case "/rest/api/1.0/application-properties":
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"X-Ausername": []string{"jane"}},
+				Body:       io.NopCloser(strings.NewReader(`{"version": "8.5.0", "displayName": "Bitbucket"}`)),
+			}, nil
+		case "/rest/api/1.0/users/jane":
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body: io.NopCloser(strings.NewReader(`
+{"name": "jane", "emailAddress": "jane@example.com", "id": 101, "displayName": "Jane Doe", "active": true, "slug": "jane", "type": "NORMAL"}
+`)),
+			}, nil
+		}
+		t.Fatalf("unexpected request %s", r.URL.Path)
+		return nil, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.TryLogin(ctx, common.OauthContext{}, testDataCenterURL)
+	require.NoError(t, err)
+
+	want := &vcs.UserInfo{
+		PublicEmail: "jane@example.com",
+		Name:        "Jane Doe",
+		State:       vcs.StateActive,
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestDataCenterProvider_GetDiffFileList(t *testing.T) {
+	p := newMockDataCenterProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/rest/api/1.0/projects/PROJ/repos/repo/changes", r.URL.Path)
+		assert.Equal(t, "before_sha", r.URL.Query().Get("since"))
+		assert.Equal(t, "after_sha", r.URL.Query().Get("until"))
+		if r.URL.Query().Get("start") == "0" {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body: io.NopCloser(strings.NewReader(`
+{
+  "values": [
+    {"path": {"toString": "prod/a.sql"}, "type": "ADD"},
+    {"path": {"toString": "prod/b.sql"}, "type": "MODIFY"}
+  ],
+  "isLastPage": false,
+  "nextPageStart": 2
+}
+`)),
+			}, nil
+		}
+		assert.Equal(t, "2", r.URL.Query().Get("start"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "values": [
+    {"path": {"toString": "prod/c.sql"}, "type": "DELETE"},
+    {"path": {"toString": "prod/e.sql"}, "srcPath": {"toString": "prod/d.sql"}, "type": "MOVE"}
+  ],
+  "isLastPage": true
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.GetDiffFileList(ctx, common.OauthContext{}, testDataCenterURL, "PROJ/repo", "before_sha", "after_sha")
+	require.NoError(t, err)
+
+	want := []vcs.FileDiff{
+		{Path: "prod/a.sql", Type: vcs.FileDiffTypeAdded},
+		{Path: "prod/b.sql", Type: vcs.FileDiffTypeModified},
+		{Path: "prod/c.sql", Type: vcs.FileDiffTypeRemoved},
+		{Path: "prod/d.sql", Type: vcs.FileDiffTypeRemoved},
+		{Path: "prod/e.sql", Type: vcs.FileDiffTypeAdded},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestDataCenterProvider_FetchRepositoryActiveMemberList(t *testing.T) {
+	p := newMockDataCenterProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/rest/api/1.0/projects/PROJ/repos/repo/permissions/users", r.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "values": [
+    {"user": {"name": "jane", "emailAddress": "jane@example.com", "displayName": "Jane Doe", "active": true}, "permission": "REPO_ADMIN"},
+    {"user": {"name": "john", "emailAddress": "john@example.com", "displayName": "John Doe", "active": true}, "permission": "REPO_READ"},
+    {"user": {"name": "gone", "emailAddress": "gone@example.com", "displayName": "Gone", "active": false}, "permission": "REPO_WRITE"}
+  ],
+  "isLastPage": true
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.FetchRepositoryActiveMemberList(ctx, common.OauthContext{}, testDataCenterURL, "PROJ/repo")
+	require.NoError(t, err)
+
+	want := []*vcs.RepositoryMember{
+		{
+			Email:        "jane@example.com",
+			Name:         "Jane Doe",
+			State:        vcs.StateActive,
+			Role:         common.ProjectOwner,
+			VCSRole:      "REPO_ADMIN",
+			RoleProvider: vcs.BitbucketDataCenter,
+		},
+		{
+			Email:        "john@example.com",
+			Name:         "John Doe",
+			State:        vcs.StateActive,
+			Role:         common.ProjectDeveloper,
+			VCSRole:      "REPO_READ",
+			RoleProvider: vcs.BitbucketDataCenter,
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestDataCenterProvider_OverwriteFile(t *testing.T) {
+	p := newMockDataCenterProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/rest/api/1.0/projects/PROJ/repos/repo/browse/prod/a.sql", r.URL.Path)
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, "CREATE TABLE t (id INT);", r.FormValue("content"))
+		assert.Equal(t, "Update schema", r.FormValue("message"))
+		assert.Equal(t, "main", r.FormValue("branch"))
+		assert.Equal(t, "last_sha", r.FormValue("sourceCommitId"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"id": "new_sha"}`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	err := p.OverwriteFile(ctx, common.OauthContext{}, testDataCenterURL, "PROJ/repo", "prod/a.sql",
+		vcs.FileCommitCreate{
+			Branch:        "main",
+			Content:       "CREATE TABLE t (id INT);",
+			CommitMessage: "Update schema",
+			LastCommitID:  "last_sha",
+		},
+	)
+	require.NoError(t, err)
+}
+
+func TestDataCenterProvider_GetBranch(t *testing.T) {
+	p := newMockDataCenterProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/rest/api/1.0/projects/PROJ/repos/repo/branches", r.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "values": [
+    {"id": "refs/heads/main-backup", "displayId": "main-backup", "latestCommit": "backup_sha"},
+    {"id": "refs/heads/main", "displayId": "main", "latestCommit": "main_sha"}
+  ],
+  "isLastPage": true
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.GetBranch(ctx, common.OauthContext{}, testDataCenterURL, "PROJ/repo", "main")
+	require.NoError(t, err)
+	assert.Equal(t, &vcs.BranchInfo{Name: "main", LastCommitID: "main_sha"}, got)
+
+	_, err = p.GetBranch(ctx, common.OauthContext{}, testDataCenterURL, "PROJ/repo", "missing")
+	assert.True(t, common.ErrorCode(err) == common.NotFound)
+}
+
+func TestDataCenterProvider_CreateWebhook(t *testing.T) {
+	p := newMockDataCenterProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/rest/api/1.0/projects/PROJ/repos/repo/webhooks", r.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       io.NopCloser(strings.NewReader(`{"id": 10, "name": "Bytebase"}`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.CreateWebhook(ctx, common.OauthContext{}, testDataCenterURL, "PROJ/repo", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "10", got)
+}
+
+func TestDataCenterProvider_CreateCommitStatus(t *testing.T) {
+	p := newMockDataCenterProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/rest/api/1.0/projects/PROJ/repos/repo/commits/after_sha/builds", r.URL.Path)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"key":"bytebase/Prod","state":"INPROGRESS","name":"bytebase/Prod","url":"https://bytebase.example.com/issue/1","description":"Pending approval"}`, string(body))
+		return &http.Response{
+			StatusCode: http.StatusNoContent,
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	err := p.CreateCommitStatus(ctx, common.OauthContext{}, testDataCenterURL, "PROJ/repo", "after_sha",
+		&vcs.CommitStatus{
+			State:       vcs.CommitStatePending,
+			Context:     "bytebase/Prod",
+			Description: "Pending approval",
+			TargetURL:   "https://bytebase.example.com/issue/1",
+		},
+	)
+	require.NoError(t, err)
+}
+
+func TestDataCenterProvider_CreatePullRequestComment(t *testing.T) {
+	p := newMockDataCenterProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/rest/api/1.0/projects/PROJ/repos/repo/pull-requests/1/comments", r.URL.Path)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"text":"Migration done"}`, string(body))
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       io.NopCloser(strings.NewReader(`{"id": 1}`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	err := p.CreatePullRequestComment(ctx, common.OauthContext{}, testDataCenterURL, "PROJ/repo", "1", "Migration done")
+	require.NoError(t, err)
+}
+
+func TestWebhookRefsChangedEvent_ToVCS(t *testing.T) {
+	var event WebhookRefsChangedEvent
+	err := json.Unmarshal([]byte(`
+{
+  "eventKey": "repo:refs_changed",
+  "actor": {"name": "jane", "emailAddress": "jane@example.com", "displayName": "Jane Doe"},
+  "repository": {"slug": "repo", "project": {"key": "PROJ"}, "links": {"self": [{"href": "https://bitbucket.example.com/projects/PROJ/repos/repo/browse"}]}},
+  "changes": [
+    {
+      "ref": {"id": "refs/heads/main", "displayId": "main", "type": "BRANCH"},
+      "refId": "refs/heads/main",
+      "fromHash": "before_sha",
+      "toHash": "after_sha",
+      "type": "UPDATE"
+    },
+    {
+      "ref": {"id": "refs/tags/v1", "displayId": "v1", "type": "TAG"},
+      "refId": "refs/tags/v1",
+      "fromHash": "0000000000000000000000000000000000000000",
+      "toHash": "after_sha",
+      "type": "ADD"
+    }
+  ]
+}
+`), &event)
+	require.NoError(t, err)
+
+	want := []vcs.PushEvent{
+		{
+			VCSType:            vcs.BitbucketDataCenter,
+			Ref:                "refs/heads/main",
+			Before:             "before_sha",
+			After:              "after_sha",
+			RepositoryID:       "PROJ/repo",
+			RepositoryURL:      "https://bitbucket.example.com/projects/PROJ/repos/repo",
+			RepositoryFullPath: "PROJ/repo",
+			AuthorName:         "Jane Doe",
+		},
+	}
+	assert.Equal(t, want, event.ToVCS())
+}
+
+func newMockDataCenterProvider(mockRoundTrip func(r *http.Request) (*http.Response, error)) vcs.Provider {
+	return newDataCenterProvider(
+		vcs.ProviderConfig{
+			Client: &http.Client{
+				Transport: &common.MockRoundTripper{
+					MockRoundTrip: mockRoundTrip,
+				},
+			},
+		},
+	)
+}
@@ -37,6 +37,7 @@ const (
 
 func init() {
 	vcs.Register(vcs.GitHubCom, newProvider)
+	vcs.Register(vcs.GitHubEnterprise, newEnterpriseProvider)
 }
 
 var _ vcs.Provider = (*Provider)(nil)
@@ -44,6 +45,9 @@ var _ vcs.Provider = (*Provider)(nil)
 // Provider is a GitHub VCS provider.
 type Provider struct {
 	client *http.Client
+	// vcsType is either vcs.GitHubCom or vcs.GitHubEnterprise, which is used
+	// as the role provider of the repository members.
+	vcsType vcs.Type
 }
 
 func newProvider(config vcs.ProviderConfig) vcs.Provider {
@@ -51,10 +55,19 @@ func newProvider(config vcs.ProviderConfig) vcs.Provider {
 		config.Client = &http.Client{}
 	}
 	return &Provider{
-		client: config.Client,
+		client:  config.Client,
+		vcsType: vcs.GitHubCom,
 	}
 }
 
+// newEnterpriseProvider returns the GitHub VCS provider for the GitHub
+// Enterprise Server, which shares the same API as GitHub.com.
+func newEnterpriseProvider(config vcs.ProviderConfig) vcs.Provider {
+	p := newProvider(config).(*Provider)
+	p.vcsType = vcs.GitHubEnterprise
+	return p
+}
+
 // APIURL returns the API URL path of GitHub.
 func (*Provider) APIURL(instanceURL string) string {
 	if instanceURL == githubComURL {
@@ -131,6 +144,8 @@ const (
 	WebhookPush WebhookType = "push"
 	// WebhookPing is the webhook type for ping.
 	WebhookPing WebhookType = "ping"
+	// WebhookPullRequest is the webhook type for pull request.
+	WebhookPullRequest WebhookType = "pull_request"
 )
 
 // WebhookInfo represents a GitHub API response for the webhook information.
@@ -210,6 +225,32 @@ type WebhookPushEvent struct {
 	Commits    []WebhookCommit   `json:"commits"`
 }
 
+// WebhookPullRequestBranch is the API message for the source or target branch of the webhook pull request.
+type WebhookPullRequestBranch struct {
+	Ref string `json:"ref"`
+	SHA string `json:"sha"`
+}
+
+// WebhookPullRequestInfo is the API message for webhook pull request.
+type WebhookPullRequestInfo struct {
+	Number  int                      `json:"number"`
+	HTMLURL string                   `json:"html_url"`
+	Title   string                   `json:"title"`
+	Merged  bool                     `json:"merged"`
+	Head    WebhookPullRequestBranch `json:"head"`
+	Base    WebhookPullRequestBranch `json:"base"`
+}
+
+// WebhookPullRequestEvent is the API message for webhook pull request event.
+//
+// Docs: https://docs.github.com/en/developers/webhooks-and-events/webhooks/webhook-events-and-payloads#pull_request
+type WebhookPullRequestEvent struct {
+	Action      string                 `json:"action"`
+	PullRequest WebhookPullRequestInfo `json:"pull_request"`
+	Repository  WebhookRepository      `json:"repository"`
+	Sender      WebhookSender          `json:"sender"`
+}
+
 // fetchUserInfoImpl fetches user information from the given resourceURI, which
 // should be either "user" or "users/{username}".
 func (p *Provider) fetchUserInfoImpl(ctx context.Context, oauthCtx common.OauthContext, instanceURL, resourceURI string) (*vcs.UserInfo, error) {
@@ -423,7 +464,7 @@ func (p *Provider) FetchRepositoryActiveMemberList(ctx context.Context, oauthCtx
 	var emptyEmailUserList []string
 	var allMembers []*vcs.RepositoryMember
 	for _, c := range allCollaborators {
-		userInfo, err := p.FetchUserInfo(ctx, oauthCtx, githubComURL, c.Login)
+		userInfo, err := p.FetchUserInfo(ctx, oauthCtx, instanceURL, c.Login)
 		if err != nil {
 			return nil, errors.Wrapf(err, "fetch user info, login: %s", c.Login)
 		}
@@ -441,7 +482,7 @@ func (p *Provider) FetchRepositoryActiveMemberList(ctx context.Context, oauthCtx
 				Role:         bytebaseRole,
 				VCSRole:      string(githubRole),
 				State:        vcs.StateActive,
-				RoleProvider: vcs.GitHubCom,
+				RoleProvider: p.vcsType,
 			},
 		)
 	}
@@ -1070,7 +1111,18 @@ func (p *Provider) CreateBranch(ctx context.Context, oauthCtx common.OauthContex
 
 // PullRequest is the API message for GitHub pull request.
 type PullRequest struct {
-	HTMLURL string `json:"html_url"`
+	Number  int                      `json:"number"`
+	HTMLURL string                   `json:"html_url"`
+	Head    WebhookPullRequestBranch `json:"head"`
+}
+
+// toVCSPullRequest converts the GitHub pull request to the vcs.PullRequest.
+func (p PullRequest) toVCSPullRequest() *vcs.PullRequest {
+	return &vcs.PullRequest{
+		ID:   strconv.Itoa(p.Number),
+		URL:  p.HTMLURL,
+		Head: p.Head.Ref,
+	}
 }
 
 // CreatePullRequest creates the pull request in the repository.
@@ -1118,9 +1170,221 @@ func (p *Provider) CreatePullRequest(ctx context.Context, oauthCtx common.OauthC
 		return nil, err
 	}
 
-	return &vcs.PullRequest{
-		URL: res.HTMLURL,
-	}, nil
+	return res.toVCSPullRequest(), nil
+}
+
+// ListPullRequest lists the open pull requests to the base branch in the repository.
+//
+// Docs: https://docs.github.com/en/rest/pulls/pulls#list-pull-requests
+func (p *Provider) ListPullRequest(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, base string) ([]*vcs.PullRequest, error) {
+	var res []*vcs.PullRequest
+	page := 1
+	for {
+		requestURL := fmt.Sprintf("%s/repos/%s/pulls?state=open&base=%s&per_page=%d&page=%d", p.APIURL(instanceURL), repositoryID, url.QueryEscape(base), apiPageSize, page)
+		code, _, body, err := oauth.Get(
+			ctx,
+			p.client,
+			requestURL,
+			&oauthCtx.AccessToken,
+			tokenRefresher(
+				instanceURL,
+				oauthContext{
+					ClientID:     oauthCtx.ClientID,
+					ClientSecret: oauthCtx.ClientSecret,
+					RefreshToken: oauthCtx.RefreshToken,
+				},
+				oauthCtx.Refresher,
+			),
+		)
+		if err != nil {
+			return nil, errors.Wrapf(err, "GET %s", requestURL)
+		}
+
+		if code == http.StatusNotFound {
+			return nil, common.Errorf(common.NotFound, "failed to list pull request from URL %s", requestURL)
+		} else if code >= 300 {
+			return nil, errors.Errorf("failed to list pull request from URL %s, status code: %d, body: %s",
+				requestURL,
+				code,
+				body,
+			)
+		}
+
+		var pullRequestList []PullRequest
+		if err := json.Unmarshal([]byte(body), &pullRequestList); err != nil {
+			return nil, errors.Wrap(err, "unmarshal pull request list")
+		}
+		for _, pullRequest := range pullRequestList {
+			res = append(res, pullRequest.toVCSPullRequest())
+		}
+		if len(pullRequestList) < apiPageSize {
+			break
+		}
+		page++
+	}
+	return res, nil
+}
+
+// PullRequestPatch is the API message to patch a pull request.
+type PullRequestPatch struct {
+	State string `json:"state"`
+}
+
+// ClosePullRequest closes the pull request.
+//
+// Docs: https://docs.github.com/en/rest/pulls/pulls#update-a-pull-request
+func (p *Provider) ClosePullRequest(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, pullRequestID string) error {
+	body, err := json.Marshal(PullRequestPatch{State: "closed"})
+	if err != nil {
+		return errors.Wrap(err, "marshal pull request patch")
+	}
+
+	requestURL := fmt.Sprintf("%s/repos/%s/pulls/%s", p.APIURL(instanceURL), repositoryID, pullRequestID)
+	code, _, resp, err := oauth.Patch(
+		ctx,
+		p.client,
+		requestURL,
+		&oauthCtx.AccessToken,
+		bytes.NewReader(body),
+		tokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "PATCH %s", requestURL)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to close pull request from URL %s", requestURL)
+	} else if code >= 300 {
+		return errors.Errorf("failed to close pull request from URL %s, status code: %d, body: %s",
+			requestURL,
+			code,
+			resp,
+		)
+	}
+	return nil
+}
+
+// IssueCommentCreate is the API message to create an issue comment.
+type IssueCommentCreate struct {
+	Body string `json:"body"`
+}
+
+// CreatePullRequestComment creates a comment in the pull request. GitHub
+// treats every pull request as an issue, so the comment is created through the
+// issue comment API.
+//
+// Docs: https://docs.github.com/en/rest/issues/comments#create-an-issue-comment
+func (p *Provider) CreatePullRequestComment(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, pullRequestID, comment string) error {
+	body, err := json.Marshal(IssueCommentCreate{Body: comment})
+	if err != nil {
+		return errors.Wrap(err, "marshal issue comment create")
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/issues/%s/comments", p.APIURL(instanceURL), repositoryID, pullRequestID)
+	code, _, resp, err := oauth.Post(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		bytes.NewReader(body),
+		tokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to create pull request comment from URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to create pull request comment from URL %s, status code: %d, body: %s",
+			url,
+			code,
+			resp,
+		)
+	}
+	return nil
+}
+
+// CommitStatusCreate is the API message to create a commit status.
+type CommitStatusCreate struct {
+	State       string `json:"state"`
+	TargetURL   string `json:"target_url,omitempty"`
+	Description string `json:"description,omitempty"`
+	Context     string `json:"context"`
+}
+
+// CreateCommitStatus creates the status of the commit.
+//
+// Docs: https://docs.github.com/en/rest/commits/statuses#create-a-commit-status
+func (p *Provider) CreateCommitStatus(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, commitID string, status *vcs.CommitStatus) error {
+	var state string
+	switch status.State {
+	case vcs.CommitStatePending:
+		state = "pending"
+	case vcs.CommitStateSuccess:
+		state = "success"
+	case vcs.CommitStateFailure:
+		state = "failure"
+	default:
+		return errors.Errorf("unexpected commit state %q", status.State)
+	}
+	body, err := json.Marshal(CommitStatusCreate{
+		State:       state,
+		TargetURL:   status.TargetURL,
+		Description: status.Description,
+		Context:     status.Context,
+	})
+	if err != nil {
+		return errors.Wrap(err, "marshal commit status create")
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/statuses/%s", p.APIURL(instanceURL), repositoryID, commitID)
+	code, _, resp, err := oauth.Post(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		bytes.NewReader(body),
+		tokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to create commit status from URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to create commit status from URL %s, status code: %d, body: %s",
+			url,
+			code,
+			resp,
+		)
+	}
+	return nil
 }
 
 // RepositorySecretUpdate is the API message to update the repository secret.
@@ -1503,3 +1767,36 @@ func (p WebhookPushEvent) ToVCS() vcs.PushEvent {
 		CommitList:         commitList,
 	}
 }
+
+// ToVCS returns the pull request event in VCS format. It returns false if the
+// action of the event is not concerned, e.g. labeled.
+func (p WebhookPullRequestEvent) ToVCS() (vcs.PullRequestEvent, bool) {
+	var action vcs.PullRequestAction
+	switch p.Action {
+	case "opened", "reopened":
+		action = vcs.PullRequestActionOpened
+	case "synchronize":
+		action = vcs.PullRequestActionUpdated
+	case "closed":
+		action = vcs.PullRequestActionClosed
+		if p.PullRequest.Merged {
+			action = vcs.PullRequestActionMerged
+		}
+	default:
+		return vcs.PullRequestEvent{}, false
+	}
+	return vcs.PullRequestEvent{
+		Action:             action,
+		PullRequestID:      strconv.Itoa(p.PullRequest.Number),
+		URL:                p.PullRequest.HTMLURL,
+		Title:              p.PullRequest.Title,
+		HeadRef:            "refs/heads/" + p.PullRequest.Head.Ref,
+		HeadSHA:            p.PullRequest.Head.SHA,
+		BaseRef:            "refs/heads/" + p.PullRequest.Base.Ref,
+		BaseSHA:            p.PullRequest.Base.SHA,
+		RepositoryID:       p.Repository.FullName,
+		RepositoryURL:      p.Repository.HTMLURL,
+		RepositoryFullPath: p.Repository.FullName,
+		AuthorName:         p.Sender.Login,
+	}, true
+}
@@ -312,6 +312,65 @@ func TestProvider_FetchRepositoryActiveMemberList(t *testing.T) {
 	assert.Equal(t, want, got)
 }
 
+func TestProvider_FetchRepositoryActiveMemberList_Enterprise(t *testing.T) {
+	p := newEnterpriseProvider(
+		vcs.ProviderConfig{
+			Client: &http.Client{
+				Transport: &common.MockRoundTripper{
+					MockRoundTrip: func(r *http.Request) (*http.Response, error) {
+						assert.Equal(t, "github.example.com", r.URL.Host)
+						switch r.URL.Path {
+						case "/api/v3/repos/octocat/Hello-World/collaborators":
+							return &http.Response{
+								StatusCode: http.StatusOK,
+								Body: io.NopCloser(strings.NewReader(`
+[
+  {
+    "login": "octocat",
+    "id": 1,
+    "type": "User",
+    "role_name": "admin"
+  }
+]
+`)),
+							}, nil
+						case "/api/v3/users/octocat":
+							return &http.Response{
+								StatusCode: http.StatusOK,
+								Body: io.NopCloser(strings.NewReader(`
+{
+  "login": "octocat",
+  "id": 1,
+  "name": "monalisa octocat",
+  "email": "octocat@example.com"
+}
+`)),
+							}, nil
+						}
+						return nil, errors.Errorf("unexpected request path: %s", r.URL.Path)
+					},
+				},
+			},
+		},
+	)
+
+	ctx := context.Background()
+	got, err := p.FetchRepositoryActiveMemberList(ctx, common.OauthContext{}, "https://github.example.com", "octocat/Hello-World")
+	require.NoError(t, err)
+
+	want := []*vcs.RepositoryMember{
+		{
+			Email:        "octocat@example.com",
+			Name:         "monalisa octocat",
+			State:        vcs.StateActive,
+			Role:         common.ProjectOwner,
+			VCSRole:      string(RepositoryRoleAdmin),
+			RoleProvider: vcs.GitHubEnterprise,
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
 func TestProvider_FetchCommitByID(t *testing.T) {
 	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
 		assert.Equal(t, "/repos/octocat/Hello-World/git/commits/7638417db6d59f3c431d3e1f261cc637155684cd", r.URL.Path)
@@ -1348,6 +1407,188 @@ func TestProvider_GetDiffFileList(t *testing.T) {
 	assert.Equal(t, want, got)
 }
 
+func TestProvider_CreatePullRequestComment(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/repos/octocat/Hello-World/issues/1347/comments", r.URL.Path)
+		assert.Equal(t, "POST", r.Method)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"body":"Me too"}`, string(body))
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			// Example response taken from https://docs.github.com/en/rest/issues/comments#create-an-issue-comment
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "id": 1,
+  "html_url": "https://github.com/octocat/Hello-World/issues/1347#issuecomment-1",
+  "body": "Me too"
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	err := p.CreatePullRequestComment(ctx, common.OauthContext{}, githubComURL, "octocat/Hello-World", "1347", "Me too")
+	require.NoError(t, err)
+}
+
+func TestProvider_ListPullRequest(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/repos/octocat/Hello-World/pulls", r.URL.Path)
+		assert.Equal(t, "open", r.URL.Query().Get("state"))
+		assert.Equal(t, "master", r.URL.Query().Get("base"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			// Example response taken from https://docs.github.com/en/rest/pulls/pulls#list-pull-requests
+			Body: io.NopCloser(strings.NewReader(`
+[
+  {
+    "id": 1,
+    "number": 1347,
+    "state": "open",
+    "title": "[Bytebase] Update latest schema on master",
+    "html_url": "https://github.com/octocat/Hello-World/pull/1347",
+    "head": {
+      "ref": "bytebase/schema-write-back-6dcb09b5",
+      "sha": "6dcb09b5b57875f334f61aebed695e2e4193db5e"
+    },
+    "base": {
+      "ref": "master",
+      "sha": "6dcb09b5b57875f334f61aebed695e2e4193db5e"
+    }
+  }
+]
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.ListPullRequest(ctx, common.OauthContext{}, githubComURL, "octocat/Hello-World", "master")
+	require.NoError(t, err)
+
+	want := []*vcs.PullRequest{
+		{
+			ID:   "1347",
+			URL:  "https://github.com/octocat/Hello-World/pull/1347",
+			Head: "bytebase/schema-write-back-6dcb09b5",
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_ClosePullRequest(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/repos/octocat/Hello-World/pulls/1347", r.URL.Path)
+		assert.Equal(t, "PATCH", r.Method)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"state":"closed"}`, string(body))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"id": 1, "number": 1347, "state": "closed"}`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	err := p.ClosePullRequest(ctx, common.OauthContext{}, githubComURL, "octocat/Hello-World", "1347")
+	require.NoError(t, err)
+}
+
+func TestProvider_CreateCommitStatus(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/repos/octocat/Hello-World/statuses/6dcb09b5b57875f334f61aebed695e2e4193db5e", r.URL.Path)
+		assert.Equal(t, "POST", r.Method)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"state":"failure","target_url":"https://bytebase.example.com/issue/1","description":"SQL review found 1 error(s)","context":"bytebase/sql-review"}`, string(body))
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			// Example response taken from https://docs.github.com/en/rest/commits/statuses#create-a-commit-status
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "id": 1,
+  "state": "failure",
+  "context": "bytebase/sql-review"
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	err := p.CreateCommitStatus(ctx, common.OauthContext{}, githubComURL, "octocat/Hello-World", "6dcb09b5b57875f334f61aebed695e2e4193db5e",
+		&vcs.CommitStatus{
+			State:       vcs.CommitStateFailure,
+			Context:     "bytebase/sql-review",
+			Description: "SQL review found 1 error(s)",
+			TargetURL:   "https://bytebase.example.com/issue/1",
+		},
+	)
+	require.NoError(t, err)
+}
+
+func TestWebhookPullRequestEvent_ToVCS(t *testing.T) {
+	newEvent := func(action string, merged bool) WebhookPullRequestEvent {
+		return WebhookPullRequestEvent{
+			Action: action,
+			PullRequest: WebhookPullRequestInfo{
+				Number:  1347,
+				HTMLURL: "https://github.com/octocat/Hello-World/pull/1347",
+				Title:   "Add migration",
+				Merged:  merged,
+				Head:    WebhookPullRequestBranch{Ref: "feature", SHA: "head_sha"},
+				Base:    WebhookPullRequestBranch{Ref: "main", SHA: "base_sha"},
+			},
+			Repository: WebhookRepository{
+				FullName: "octocat/Hello-World",
+				HTMLURL:  "https://github.com/octocat/Hello-World",
+			},
+			Sender: WebhookSender{Login: "octocat"},
+		}
+	}
+
+	tests := []struct {
+		action string
+		merged bool
+		want   vcs.PullRequestAction
+		ok     bool
+	}{
+		{action: "opened", want: vcs.PullRequestActionOpened, ok: true},
+		{action: "reopened", want: vcs.PullRequestActionOpened, ok: true},
+		{action: "synchronize", want: vcs.PullRequestActionUpdated, ok: true},
+		{action: "closed", merged: true, want: vcs.PullRequestActionMerged, ok: true},
+		{action: "closed", want: vcs.PullRequestActionClosed, ok: true},
+		{action: "labeled", ok: false},
+	}
+	for _, test := range tests {
+		got, ok := newEvent(test.action, test.merged).ToVCS()
+		require.Equal(t, test.ok, ok, test.action)
+		if !ok {
+			continue
+		}
+		assert.Equal(t,
+			vcs.PullRequestEvent{
+				Action:             test.want,
+				PullRequestID:      "1347",
+				URL:                "https://github.com/octocat/Hello-World/pull/1347",
+				Title:              "Add migration",
+				HeadRef:            "refs/heads/feature",
+				HeadSHA:            "head_sha",
+				BaseRef:            "refs/heads/main",
+				BaseSHA:            "base_sha",
+				RepositoryID:       "octocat/Hello-World",
+				RepositoryURL:      "https://github.com/octocat/Hello-World",
+				RepositoryFullPath: "octocat/Hello-World",
+				AuthorName:         "octocat",
+			},
+			got,
+		)
+	}
+}
+
 func newMockProvider(mockRoundTrip func(r *http.Request) (*http.Response, error)) vcs.Provider {
 	return newProvider(
 		vcs.ProviderConfig{
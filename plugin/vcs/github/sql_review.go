@@ -18,6 +18,12 @@ const (
 )
 
 // SetupSQLReviewCI will setup the SQL review CI content with SQL review endpoint.
-func SetupSQLReviewCI(endpoint string) string {
-	return fmt.Sprintf(sqlReviewAction, endpoint, vcs.SQLReviewAPISecretName)
+// GitHub-hosted runners are not available for GitHub Enterprise Server, so the
+// action runs on self-hosted runners there.
+func SetupSQLReviewCI(vcsType vcs.Type, endpoint string) string {
+	runner := "ubuntu-latest"
+	if vcsType == vcs.GitHubEnterprise {
+		runner = "self-hosted"
+	}
+	return fmt.Sprintf(sqlReviewAction, runner, endpoint, vcs.SQLReviewAPISecretName)
 }
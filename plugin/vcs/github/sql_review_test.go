@@ -0,0 +1,44 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/bytebase/bytebase/plugin/vcs"
+)
+
+func TestSetupSQLReviewCI(t *testing.T) {
+	tests := []struct {
+		vcsType vcs.Type
+		want    string
+	}{
+		{
+			vcsType: vcs.GitHubCom,
+			want:    "ubuntu-latest",
+		},
+		{
+			vcsType: vcs.GitHubEnterprise,
+			want:    "self-hosted",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.vcsType), func(t *testing.T) {
+			content := SetupSQLReviewCI(test.vcsType, "https://bytebase.example.com/hook/sql-review/abc")
+			assert.Contains(t, content, `API="https://bytebase.example.com/hook/sql-review/abc"`)
+			assert.Contains(t, content, "secrets."+vcs.SQLReviewAPISecretName)
+
+			var workflow struct {
+				Jobs map[string]struct {
+					RunsOn string `yaml:"runs-on"`
+				} `yaml:"jobs"`
+			}
+			err := yaml.Unmarshal([]byte(content), &workflow)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, workflow.Jobs["bytebase-sql-review"].RunsOn)
+		})
+	}
+}
@@ -38,6 +38,8 @@ type WebhookType string
 const (
 	// WebhookPush is the webhook type for push.
 	WebhookPush WebhookType = "push"
+	// WebhookMergeRequest is the webhook type for merge request.
+	WebhookMergeRequest WebhookType = "merge_request"
 )
 
 // WebhookInfo represents a GitLab API response for the webhook information.
@@ -51,12 +53,9 @@ type WebhookCreate struct {
 	SecretToken string `json:"token"`
 	// This is set to true
 	PushEvents bool `json:"push_events"`
-	// For now, there is no native dry run DDL support in mysql/postgres. One may wonder if we could wrap the DDL
-	// in a transaction and just not commit at the end, unfortunately there are side effects which are hard to control.
-	// See https://www.postgresql.org/message-id/CAMsr%2BYGiYQ7PYvYR2Voio37YdCpp79j5S%2BcmgVJMOLM2LnRQcA%40mail.gmail.com
-	// So we can't possibly display useful info when reviewing a MR, thus we don't enable this event.
-	// Saying that, delivering a souding dry run solution would be great and hopefully we can achieve that one day.
-	// MergeRequestsEvents  bool   `json:"merge_requests_events"`
+	// MergeRequestsEvents is set to true to create the draft issue when a merge
+	// request is opened, and to promote it when the merge request is merged.
+	MergeRequestsEvents   bool `json:"merge_requests_events"`
 	EnableSSLVerification bool `json:"enable_ssl_verification"`
 }
 
@@ -96,6 +95,42 @@ type WebhookPushEvent struct {
 	CommitList []WebhookCommit `json:"commits"`
 }
 
+// WebhookMergeRequestUser is the API message for webhook merge request user.
+type WebhookMergeRequestUser struct {
+	Name     string `json:"name"`
+	Username string `json:"username"`
+}
+
+// WebhookMergeRequestDiffRefs is the API message for the diff refs of webhook merge request.
+type WebhookMergeRequestDiffRefs struct {
+	BaseSHA string `json:"base_sha"`
+	HeadSHA string `json:"head_sha"`
+}
+
+// WebhookMergeRequestAttributes is the API message for webhook merge request attributes.
+type WebhookMergeRequestAttributes struct {
+	IID          int                         `json:"iid"`
+	Title        string                      `json:"title"`
+	URL          string                      `json:"url"`
+	SourceBranch string                      `json:"source_branch"`
+	TargetBranch string                      `json:"target_branch"`
+	Action       string                      `json:"action"`
+	DiffRefs     WebhookMergeRequestDiffRefs `json:"diff_refs"`
+	LastCommit   WebhookCommit               `json:"last_commit"`
+	// OldRev is only present in the "update" action if there are new commits pushed to the source branch.
+	OldRev string `json:"oldrev"`
+}
+
+// WebhookMergeRequestEvent is the API message for webhook merge request event.
+//
+// Docs: https://docs.gitlab.com/ee/user/project/integrations/webhook_events.html#merge-request-events
+type WebhookMergeRequestEvent struct {
+	ObjectKind       WebhookType                   `json:"object_kind"`
+	User             WebhookMergeRequestUser       `json:"user"`
+	Project          WebhookProject                `json:"project"`
+	ObjectAttributes WebhookMergeRequestAttributes `json:"object_attributes"`
+}
+
 // Commit is the API message for commit.
 type Commit struct {
 	ID         string `json:"id"`
@@ -1001,7 +1036,18 @@ func (p *Provider) CreateBranch(ctx context.Context, oauthCtx common.OauthContex
 
 // MergeRequest is the API message for GitLab merge request.
 type MergeRequest struct {
-	WebURL string `json:"web_url"`
+	IID          int    `json:"iid"`
+	WebURL       string `json:"web_url"`
+	SourceBranch string `json:"source_branch"`
+}
+
+// toVCSPullRequest converts the GitLab merge request to the vcs.PullRequest.
+func (m MergeRequest) toVCSPullRequest() *vcs.PullRequest {
+	return &vcs.PullRequest{
+		ID:   strconv.Itoa(m.IID),
+		URL:  m.WebURL,
+		Head: m.SourceBranch,
+	}
 }
 
 // CreatePullRequest creates the pull request in the repository.
@@ -1057,9 +1103,220 @@ func (p *Provider) CreatePullRequest(ctx context.Context, oauthCtx common.OauthC
 		return nil, err
 	}
 
-	return &vcs.PullRequest{
-		URL: res.WebURL,
-	}, nil
+	return res.toVCSPullRequest(), nil
+}
+
+// ListPullRequest lists the opened merge requests to the target branch in the repository.
+//
+// Docs: https://docs.gitlab.com/ee/api/merge_requests.html#list-project-merge-requests
+func (p *Provider) ListPullRequest(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, base string) ([]*vcs.PullRequest, error) {
+	var res []*vcs.PullRequest
+	page := 1
+	for {
+		requestURL := fmt.Sprintf("%s/projects/%s/merge_requests?state=opened&target_branch=%s&per_page=%d&page=%d", p.APIURL(instanceURL), repositoryID, url.QueryEscape(base), apiPageSize, page)
+		code, _, body, err := oauth.Get(
+			ctx,
+			p.client,
+			requestURL,
+			&oauthCtx.AccessToken,
+			tokenRefresher(
+				instanceURL,
+				oauthContext{
+					ClientID:     oauthCtx.ClientID,
+					ClientSecret: oauthCtx.ClientSecret,
+					RefreshToken: oauthCtx.RefreshToken,
+				},
+				oauthCtx.Refresher,
+			),
+		)
+		if err != nil {
+			return nil, errors.Wrapf(err, "GET %s", requestURL)
+		}
+
+		if code == http.StatusNotFound {
+			return nil, common.Errorf(common.NotFound, "failed to list merge request from URL %s", requestURL)
+		} else if code >= 300 {
+			return nil, errors.Errorf("failed to list merge request from URL %s, status code: %d, body: %s",
+				requestURL,
+				code,
+				body,
+			)
+		}
+
+		var mergeRequestList []MergeRequest
+		if err := json.Unmarshal([]byte(body), &mergeRequestList); err != nil {
+			return nil, errors.Wrap(err, "unmarshal merge request list")
+		}
+		for _, mergeRequest := range mergeRequestList {
+			res = append(res, mergeRequest.toVCSPullRequest())
+		}
+		if len(mergeRequestList) < apiPageSize {
+			break
+		}
+		page++
+	}
+	return res, nil
+}
+
+// MergeRequestPatch is the API message to update a merge request.
+type MergeRequestPatch struct {
+	StateEvent string `json:"state_event"`
+}
+
+// ClosePullRequest closes the merge request.
+//
+// Docs: https://docs.gitlab.com/ee/api/merge_requests.html#update-mr
+func (p *Provider) ClosePullRequest(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, pullRequestID string) error {
+	body, err := json.Marshal(MergeRequestPatch{StateEvent: "close"})
+	if err != nil {
+		return errors.Wrap(err, "marshal merge request patch")
+	}
+
+	requestURL := fmt.Sprintf("%s/projects/%s/merge_requests/%s", p.APIURL(instanceURL), repositoryID, pullRequestID)
+	code, _, resp, err := oauth.Put(
+		ctx,
+		p.client,
+		requestURL,
+		&oauthCtx.AccessToken,
+		bytes.NewReader(body),
+		tokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "PUT %s", requestURL)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to close merge request from URL %s", requestURL)
+	} else if code >= 300 {
+		return errors.Errorf("failed to close merge request from URL %s, status code: %d, body: %s",
+			requestURL,
+			code,
+			resp,
+		)
+	}
+	return nil
+}
+
+// MergeRequestNoteCreate is the API message to create a merge request note.
+type MergeRequestNoteCreate struct {
+	Body string `json:"body"`
+}
+
+// CreatePullRequestComment creates a note in the merge request.
+//
+// Docs: https://docs.gitlab.com/ee/api/notes.html#create-new-merge-request-note
+func (p *Provider) CreatePullRequestComment(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, pullRequestID, comment string) error {
+	body, err := json.Marshal(MergeRequestNoteCreate{Body: comment})
+	if err != nil {
+		return errors.Wrap(err, "marshal merge request note create")
+	}
+
+	url := fmt.Sprintf("%s/projects/%s/merge_requests/%s/notes", p.APIURL(instanceURL), repositoryID, pullRequestID)
+	code, _, resp, err := oauth.Post(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		bytes.NewReader(body),
+		tokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to create merge request note from URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to create merge request note from URL %s, status code: %d, body: %s",
+			url,
+			code,
+			resp,
+		)
+	}
+	return nil
+}
+
+// CommitStatusCreate is the API message to create a commit status.
+type CommitStatusCreate struct {
+	State       string `json:"state"`
+	Name        string `json:"name"`
+	TargetURL   string `json:"target_url,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// CreateCommitStatus creates the status of the commit, which is shown as an
+// external stage in the merge request pipeline.
+//
+// Docs: https://docs.gitlab.com/ee/api/commits.html#post-the-build-status-to-a-commit
+func (p *Provider) CreateCommitStatus(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, commitID string, status *vcs.CommitStatus) error {
+	var state string
+	switch status.State {
+	case vcs.CommitStatePending:
+		state = "pending"
+	case vcs.CommitStateSuccess:
+		state = "success"
+	case vcs.CommitStateFailure:
+		state = "failed"
+	default:
+		return errors.Errorf("unexpected commit state %q", status.State)
+	}
+	body, err := json.Marshal(CommitStatusCreate{
+		State:       state,
+		Name:        status.Context,
+		TargetURL:   status.TargetURL,
+		Description: status.Description,
+	})
+	if err != nil {
+		return errors.Wrap(err, "marshal commit status create")
+	}
+
+	url := fmt.Sprintf("%s/projects/%s/statuses/%s", p.APIURL(instanceURL), repositoryID, commitID)
+	code, _, resp, err := oauth.Post(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		bytes.NewReader(body),
+		tokenRefresher(
+			instanceURL,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to create commit status from URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to create commit status from URL %s, status code: %d, body: %s",
+			url,
+			code,
+			resp,
+		)
+	}
+	return nil
 }
 
 // EnvironmentVariable is the API message for environment variable in GitLab project.
@@ -1479,3 +1736,43 @@ func (p WebhookPushEvent) ToVCS() (vcs.PushEvent, error) {
 		CommitList:         commitList,
 	}, nil
 }
+
+// ToVCS returns the merge request event in VCS format. It returns false if the
+// action of the event is not concerned, e.g. approved, or an update without new
+// commits.
+func (p WebhookMergeRequestEvent) ToVCS() (vcs.PullRequestEvent, bool) {
+	var action vcs.PullRequestAction
+	switch p.ObjectAttributes.Action {
+	case "open", "reopen":
+		action = vcs.PullRequestActionOpened
+	case "update":
+		if p.ObjectAttributes.OldRev == "" {
+			return vcs.PullRequestEvent{}, false
+		}
+		action = vcs.PullRequestActionUpdated
+	case "merge":
+		action = vcs.PullRequestActionMerged
+	case "close":
+		action = vcs.PullRequestActionClosed
+	default:
+		return vcs.PullRequestEvent{}, false
+	}
+	headSHA := p.ObjectAttributes.DiffRefs.HeadSHA
+	if headSHA == "" {
+		headSHA = p.ObjectAttributes.LastCommit.ID
+	}
+	return vcs.PullRequestEvent{
+		Action:             action,
+		PullRequestID:      strconv.Itoa(p.ObjectAttributes.IID),
+		URL:                p.ObjectAttributes.URL,
+		Title:              p.ObjectAttributes.Title,
+		HeadRef:            "refs/heads/" + p.ObjectAttributes.SourceBranch,
+		HeadSHA:            headSHA,
+		BaseRef:            "refs/heads/" + p.ObjectAttributes.TargetBranch,
+		BaseSHA:            p.ObjectAttributes.DiffRefs.BaseSHA,
+		RepositoryID:       fmt.Sprintf("%v", p.Project.ID),
+		RepositoryURL:      p.Project.WebURL,
+		RepositoryFullPath: p.Project.FullPath,
+		AuthorName:         p.User.Username,
+	}, true
+}
@@ -1087,6 +1087,191 @@ func TestProvider_GetDiffFileList(t *testing.T) {
 	assert.Equal(t, want, got)
 }
 
+func TestProvider_CreatePullRequestComment(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/api/v4/projects/1/merge_requests/2/notes", r.URL.Path)
+		assert.Equal(t, "POST", r.Method)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"body":"Comment"}`, string(body))
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			// Example response taken from https://docs.gitlab.com/ee/api/notes.html#create-new-merge-request-note
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "id": 302,
+  "body": "Comment",
+  "noteable_id": 2,
+  "noteable_type": "MergeRequest",
+  "noteable_iid": 2
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	err := p.CreatePullRequestComment(ctx, common.OauthContext{}, "", "1", "2", "Comment")
+	require.NoError(t, err)
+}
+
+func TestProvider_ListPullRequest(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/api/v4/projects/1/merge_requests", r.URL.Path)
+		assert.Equal(t, "opened", r.URL.Query().Get("state"))
+		assert.Equal(t, "main", r.URL.Query().Get("target_branch"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			// Example response taken from https://docs.gitlab.com/ee/api/merge_requests.html#list-project-merge-requests
+			Body: io.NopCloser(strings.NewReader(`
+[
+  {
+    "id": 1,
+    "iid": 1,
+    "project_id": 1,
+    "title": "[Bytebase] Update latest schema on main",
+    "state": "opened",
+    "target_branch": "main",
+    "source_branch": "bytebase/schema-write-back-8888888",
+    "web_url": "http://gitlab.example.com/my-group/my-project/merge_requests/1"
+  }
+]
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.ListPullRequest(ctx, common.OauthContext{}, "", "1", "main")
+	require.NoError(t, err)
+
+	want := []*vcs.PullRequest{
+		{
+			ID:   "1",
+			URL:  "http://gitlab.example.com/my-group/my-project/merge_requests/1",
+			Head: "bytebase/schema-write-back-8888888",
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_ClosePullRequest(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/api/v4/projects/1/merge_requests/2", r.URL.Path)
+		assert.Equal(t, "PUT", r.Method)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"state_event":"close"}`, string(body))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"id": 2, "iid": 2, "state": "closed"}`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	err := p.ClosePullRequest(ctx, common.OauthContext{}, "", "1", "2")
+	require.NoError(t, err)
+}
+
+func TestProvider_CreateCommitStatus(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/api/v4/projects/1/statuses/18f3e63d05582537db6d183d9d557be09e1f90c8", r.URL.Path)
+		assert.Equal(t, "POST", r.Method)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"state":"failed","name":"bytebase/sql-review","target_url":"https://bytebase.example.com/issue/1","description":"SQL review found 1 error(s)"}`, string(body))
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			// Example response taken from https://docs.gitlab.com/ee/api/commits.html#post-the-build-status-to-a-commit
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "id": 93,
+  "sha": "18f3e63d05582537db6d183d9d557be09e1f90c8",
+  "status": "failed",
+  "name": "bytebase/sql-review"
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	err := p.CreateCommitStatus(ctx, common.OauthContext{}, "", "1", "18f3e63d05582537db6d183d9d557be09e1f90c8",
+		&vcs.CommitStatus{
+			State:       vcs.CommitStateFailure,
+			Context:     "bytebase/sql-review",
+			Description: "SQL review found 1 error(s)",
+			TargetURL:   "https://bytebase.example.com/issue/1",
+		},
+	)
+	require.NoError(t, err)
+}
+
+func TestWebhookMergeRequestEvent_ToVCS(t *testing.T) {
+	newEvent := func(action, oldRev string) WebhookMergeRequestEvent {
+		return WebhookMergeRequestEvent{
+			ObjectKind: WebhookMergeRequest,
+			User:       WebhookMergeRequestUser{Name: "Administrator", Username: "root"},
+			Project: WebhookProject{
+				ID:       1,
+				WebURL:   "http://gitlab.example.com/gitlabhq/gitlab-test",
+				FullPath: "gitlabhq/gitlab-test",
+			},
+			ObjectAttributes: WebhookMergeRequestAttributes{
+				IID:          2,
+				Title:        "Add migration",
+				URL:          "http://gitlab.example.com/gitlabhq/gitlab-test/-/merge_requests/2",
+				SourceBranch: "feature",
+				TargetBranch: "main",
+				Action:       action,
+				DiffRefs:     WebhookMergeRequestDiffRefs{BaseSHA: "base_sha", HeadSHA: "head_sha"},
+				OldRev:       oldRev,
+			},
+		}
+	}
+
+	tests := []struct {
+		action string
+		oldRev string
+		want   vcs.PullRequestAction
+		ok     bool
+	}{
+		{action: "open", want: vcs.PullRequestActionOpened, ok: true},
+		{action: "reopen", want: vcs.PullRequestActionOpened, ok: true},
+		{action: "update", oldRev: "old_sha", want: vcs.PullRequestActionUpdated, ok: true},
+		// The update without new commits, e.g. changing the title, is not concerned.
+		{action: "update", ok: false},
+		{action: "merge", want: vcs.PullRequestActionMerged, ok: true},
+		{action: "close", want: vcs.PullRequestActionClosed, ok: true},
+		{action: "approved", ok: false},
+	}
+	for _, test := range tests {
+		got, ok := newEvent(test.action, test.oldRev).ToVCS()
+		require.Equal(t, test.ok, ok, test.action)
+		if !ok {
+			continue
+		}
+		assert.Equal(t,
+			vcs.PullRequestEvent{
+				Action:             test.want,
+				PullRequestID:      "2",
+				URL:                "http://gitlab.example.com/gitlabhq/gitlab-test/-/merge_requests/2",
+				Title:              "Add migration",
+				HeadRef:            "refs/heads/feature",
+				HeadSHA:            "head_sha",
+				BaseRef:            "refs/heads/main",
+				BaseSHA:            "base_sha",
+				RepositoryID:       "1",
+				RepositoryURL:      "http://gitlab.example.com/gitlabhq/gitlab-test",
+				RepositoryFullPath: "gitlabhq/gitlab-test",
+				AuthorName:         "root",
+			},
+			got,
+		)
+	}
+}
+
 func newMockProvider(mockRoundTrip func(r *http.Request) (*http.Response, error)) vcs.Provider {
 	return newProvider(
 		vcs.ProviderConfig{
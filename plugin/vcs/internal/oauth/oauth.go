@@ -13,11 +13,13 @@ import (
 	"github.com/pkg/errors"
 )
 
+const jsonContentType = "application/json"
+
 // TokenRefresher is a function to refresh the OAuth token and assign back to
 // the old token upon a successful refresh.
 type TokenRefresher func(ctx context.Context, client *http.Client, oldToken *string) error
 
-func requester(ctx context.Context, client *http.Client, method, url string, token *string, body io.Reader) func() (*http.Response, error) {
+func requester(ctx context.Context, client *http.Client, method, url string, token *string, contentType string, body io.Reader) func() (*http.Response, error) {
 	// The body may be read multiple times but io.Reader is meant to be read once,
 	// so we read the body first and build the reader every time.
 	var bodyBytes []byte
@@ -36,7 +38,7 @@ func requester(ctx context.Context, client *http.Client, method, url string, tok
 			return nil, errors.Wrapf(err, "construct %s %s", method, url)
 		}
 
-		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Content-Type", contentType)
 		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", *token))
 		resp, err := client.Do(req)
 		if err != nil {
@@ -49,31 +51,45 @@ func requester(ctx context.Context, client *http.Client, method, url string, tok
 // Post makes a HTTP POST request to the given URL using the token. It refreshes
 // token and retries the request in the case of the token has expired.
 func Post(ctx context.Context, client *http.Client, url string, token *string, body io.Reader, tokenRefresher TokenRefresher) (code int, header http.Header, respBody string, err error) {
-	return retry(ctx, client, token, tokenRefresher, requester(ctx, client, http.MethodPost, url, token, body))
+	return retry(ctx, client, token, tokenRefresher, requester(ctx, client, http.MethodPost, url, token, jsonContentType, body))
 }
 
 // Get makes a HTTP GET request to the given URL using the token. It refreshes
 // token and retries the request in the case of the token has expired.
 func Get(ctx context.Context, client *http.Client, url string, token *string, tokenRefresher TokenRefresher) (code int, header http.Header, respBody string, err error) {
-	return retry(ctx, client, token, tokenRefresher, requester(ctx, client, http.MethodGet, url, token, nil))
+	return retry(ctx, client, token, tokenRefresher, requester(ctx, client, http.MethodGet, url, token, jsonContentType, nil))
 }
 
 // Put makes a HTTP PUT request to the given URL using the token. It refreshes
 // token and retries the request in the case of the token has expired.
 func Put(ctx context.Context, client *http.Client, url string, token *string, body io.Reader, tokenRefresher TokenRefresher) (code int, header http.Header, respBody string, err error) {
-	return retry(ctx, client, token, tokenRefresher, requester(ctx, client, http.MethodPut, url, token, body))
+	return retry(ctx, client, token, tokenRefresher, requester(ctx, client, http.MethodPut, url, token, jsonContentType, body))
 }
 
 // Patch makes a HTTP PATCH request to the given URL using the token. It
 // refreshes token and retries the request in the case of the token has expired.
 func Patch(ctx context.Context, client *http.Client, url string, token *string, body io.Reader, tokenRefresher TokenRefresher) (code int, header http.Header, respBody string, err error) {
-	return retry(ctx, client, token, tokenRefresher, requester(ctx, client, http.MethodPatch, url, token, body))
+	return retry(ctx, client, token, tokenRefresher, requester(ctx, client, http.MethodPatch, url, token, jsonContentType, body))
 }
 
 // Delete makes a HTTP DELETE request to the given URL using the token. It refreshes
 // token and retries the request in the case of the token has expired.
 func Delete(ctx context.Context, client *http.Client, url string, token *string, tokenRefresher TokenRefresher) (code int, header http.Header, respBody string, err error) {
-	return retry(ctx, client, token, tokenRefresher, requester(ctx, client, http.MethodDelete, url, token, nil))
+	return retry(ctx, client, token, tokenRefresher, requester(ctx, client, http.MethodDelete, url, token, jsonContentType, nil))
+}
+
+// PostWithContentType makes a HTTP POST request to the given URL using the
+// token and the content type, e.g. the multipart form. It refreshes token and
+// retries the request in the case of the token has expired.
+func PostWithContentType(ctx context.Context, client *http.Client, url string, token *string, contentType string, body io.Reader, tokenRefresher TokenRefresher) (code int, header http.Header, respBody string, err error) {
+	return retry(ctx, client, token, tokenRefresher, requester(ctx, client, http.MethodPost, url, token, contentType, body))
+}
+
+// PutWithContentType makes a HTTP PUT request to the given URL using the token
+// and the content type, e.g. the multipart form. It refreshes token and retries
+// the request in the case of the token has expired.
+func PutWithContentType(ctx context.Context, client *http.Client, url string, token *string, contentType string, body io.Reader, tokenRefresher TokenRefresher) (code int, header http.Header, respBody string, err error) {
+	return retry(ctx, client, token, tokenRefresher, requester(ctx, client, http.MethodPut, url, token, contentType, body))
 }
 
 const maxRetries = 3
@@ -118,6 +134,18 @@ type oauthError struct {
 	ErrorDescription string `json:"error_description"`
 }
 
+type bitbucketError struct {
+	Type  string `json:"type"`
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type azureDevOpsError struct {
+	TypeKey string `json:"typeKey"`
+	Message string `json:"message"`
+}
+
 func (e oauthError) Error() string {
 	return fmt.Sprintf("OAuth response error %q description %q", e.Err, e.ErrorDescription)
 }
@@ -133,6 +161,20 @@ func getOAuthErrorDetails(code int, body []byte) error {
 		return nil
 	}
 
+	// Bitbucket Cloud returns its own error object instead of the standard OAuth error, e.g.
+	// {"type":"error","error":{"message":"Access token expired. Use your refresh token to obtain a new access token."}}
+	var be bitbucketError
+	if err := json.Unmarshal(body, &be); err == nil && be.Type == "error" && strings.Contains(be.Error.Message, "expired") {
+		return &oauthError{Err: "invalid_token", ErrorDescription: be.Error.Message}
+	}
+
+	// Azure DevOps returns 401 with its own exception object when the access token is expired or revoked, e.g.
+	// {"typeKey":"UnauthorizedRequestException","message":"TF400813: The user '' is not authorized to access this resource."}
+	var ae azureDevOpsError
+	if code == http.StatusUnauthorized && json.Unmarshal(body, &ae) == nil && ae.TypeKey == "UnauthorizedRequestException" {
+		return &oauthError{Err: "invalid_token", ErrorDescription: ae.Message}
+	}
+
 	var oe oauthError
 	if err := json.Unmarshal(body, &oe); err != nil {
 		// If we failed to unmarshal body with oauth error, it's not oauthError and we should return nil.
@@ -35,6 +35,27 @@ func TestPost(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestPostWithContentType(t *testing.T) {
+	ctx := context.Background()
+	client := &http.Client{
+		Transport: &common.MockRoundTripper{
+			MockRoundTrip: func(r *http.Request) (*http.Response, error) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "multipart/form-data; boundary=foo", r.Header.Get("Content-Type"))
+				assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				assert.Equal(t, "POST body", string(body))
+				return &http.Response{}, nil
+			},
+		},
+	}
+	token := "token"
+	_, _, _, err := PostWithContentType(ctx, client, "", &token, "multipart/form-data; boundary=foo", strings.NewReader("POST body"), nil)
+	require.NoError(t, err)
+}
+
 func TestGet(t *testing.T) {
 	ctx := context.Background()
 	client := &http.Client{
@@ -73,6 +94,27 @@ func TestPut(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestPutWithContentType(t *testing.T) {
+	ctx := context.Background()
+	client := &http.Client{
+		Transport: &common.MockRoundTripper{
+			MockRoundTrip: func(r *http.Request) (*http.Response, error) {
+				assert.Equal(t, http.MethodPut, r.Method)
+				assert.Equal(t, "multipart/form-data; boundary=foo", r.Header.Get("Content-Type"))
+				assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				assert.Equal(t, "PUT body", string(body))
+				return &http.Response{}, nil
+			},
+		},
+	}
+	token := "token"
+	_, _, _, err := PutWithContentType(ctx, client, "", &token, "multipart/form-data; boundary=foo", strings.NewReader("PUT body"), nil)
+	require.NoError(t, err)
+}
+
 func TestPatch(t *testing.T) {
 	ctx := context.Background()
 	client := &http.Client{
@@ -132,3 +174,67 @@ func TestRetry_Exceeded(t *testing.T) {
 	gotErr := fmt.Sprintf("%v", err)
 	assert.Equal(t, wantErr, gotErr)
 }
+
+func TestRetry_BitbucketExpiredToken(t *testing.T) {
+	ctx := context.Background()
+	token := "expired"
+
+	calls := 0
+	code, _, body, err := retry(ctx, nil, &token,
+		func(_ context.Context, _ *http.Client, token *string) error {
+			*token = "refreshed"
+			return nil
+		},
+		func() (*http.Response, error) {
+			calls++
+			if token == "expired" {
+				return &http.Response{
+					StatusCode: http.StatusUnauthorized,
+					Body: io.NopCloser(strings.NewReader(`
+{"type":"error","error":{"message":"Access token expired. Use your refresh token to obtain a new access token."}}
+`)),
+				}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader("ok")),
+			}, nil
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ok", body)
+}
+
+func TestRetry_AzureDevOpsExpiredToken(t *testing.T) {
+	ctx := context.Background()
+	token := "expired"
+
+	calls := 0
+	code, _, body, err := retry(ctx, nil, &token,
+		func(_ context.Context, _ *http.Client, token *string) error {
+			*token = "refreshed"
+			return nil
+		},
+		func() (*http.Response, error) {
+			calls++
+			if token == "expired" {
+				return &http.Response{
+					StatusCode: http.StatusUnauthorized,
+					Body: io.NopCloser(strings.NewReader(`
+{"$id":"1","innerException":null,"message":"TF400813: The user '' is not authorized to access this resource.","typeName":"Microsoft.TeamFoundation.Framework.Server.UnauthorizedRequestException, Microsoft.TeamFoundation.Framework.Server","typeKey":"UnauthorizedRequestException","errorCode":0,"eventId":3000}
+`)),
+				}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader("ok")),
+			}, nil
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ok", body)
+}
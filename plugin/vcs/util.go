@@ -2,11 +2,13 @@
 package vcs
 
 import (
+	"context"
 	"strings"
 
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
+	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/common/log"
 )
 
@@ -104,3 +106,28 @@ func (p PushEvent) GetDistinctFileList() []DistinctFileItem {
 	}
 	return distinctFileList
 }
+
+// FillPushEventCommit fills the commit list of the push event with the after
+// commit and the files changed between the before and after commits. It is
+// used by the VCS providers whose push event doesn't contain the changed files,
+// e.g. Bitbucket.
+func FillPushEventCommit(ctx context.Context, provider Provider, oauthCtx common.OauthContext, instanceURL, repositoryID string, pushEvent *PushEvent) error {
+	commit, err := provider.FetchCommitByID(ctx, oauthCtx, instanceURL, repositoryID, pushEvent.After)
+	if err != nil {
+		return errors.Wrapf(err, "failed to fetch commit %s", pushEvent.After)
+	}
+	fileDiffList, err := provider.GetDiffFileList(ctx, oauthCtx, instanceURL, repositoryID, pushEvent.Before, pushEvent.After)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get file diff list between %s and %s", pushEvent.Before, pushEvent.After)
+	}
+	for _, fileDiff := range fileDiffList {
+		switch fileDiff.Type {
+		case FileDiffTypeAdded:
+			commit.AddedList = append(commit.AddedList, fileDiff.Path)
+		case FileDiffTypeModified:
+			commit.ModifiedList = append(commit.ModifiedList, fileDiff.Path)
+		}
+	}
+	pushEvent.CommitList = []Commit{*commit}
+	return nil
+}
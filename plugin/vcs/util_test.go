@@ -1,11 +1,14 @@
 package vcs
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/common"
 )
 
 func TestBranch(t *testing.T) {
@@ -478,3 +481,38 @@ func TestGetDistinctFileList(t *testing.T) {
 		})
 	}
 }
+
+type mockCommitProvider struct {
+	Provider
+}
+
+func (mockCommitProvider) FetchCommitByID(_ context.Context, _ common.OauthContext, _, _, commitID string) (*Commit, error) {
+	return &Commit{ID: commitID, Title: "Update schema"}, nil
+}
+
+func (mockCommitProvider) GetDiffFileList(_ context.Context, _ common.OauthContext, _, _, _, _ string) ([]FileDiff, error) {
+	return []FileDiff{
+		{Path: "prod/db__v1__migrate__create.sql", Type: FileDiffTypeAdded},
+		{Path: "prod/db__v0__migrate__init.sql", Type: FileDiffTypeModified},
+		{Path: "prod/old.sql", Type: FileDiffTypeRemoved},
+	}, nil
+}
+
+func TestFillPushEventCommit(t *testing.T) {
+	pushEvent := &PushEvent{
+		Before: "before_sha",
+		After:  "after_sha",
+	}
+	err := FillPushEventCommit(context.Background(), mockCommitProvider{}, common.OauthContext{}, "", "PROJ/repo", pushEvent)
+	require.NoError(t, err)
+
+	want := []Commit{
+		{
+			ID:           "after_sha",
+			Title:        "Update schema",
+			AddedList:    []string{"prod/db__v1__migrate__create.sql"},
+			ModifiedList: []string{"prod/db__v0__migrate__init.sql"},
+		},
+	}
+	assert.Equal(t, want, pushEvent.CommitList)
+}
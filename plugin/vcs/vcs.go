@@ -22,6 +22,14 @@ const (
 	GitLabSelfHost Type = "GITLAB_SELF_HOST"
 	// GitHubCom is the VCS type for GitHub.com.
 	GitHubCom Type = "GITHUB_COM"
+	// GitHubEnterprise is the VCS type for GitHub Enterprise Server.
+	GitHubEnterprise Type = "GITHUB_ENTERPRISE"
+	// BitbucketCloud is the VCS type for Bitbucket Cloud (bitbucket.org).
+	BitbucketCloud Type = "BITBUCKET_CLOUD"
+	// BitbucketDataCenter is the VCS type for Bitbucket Data Center (formerly Bitbucket Server).
+	BitbucketDataCenter Type = "BITBUCKET_DATA_CENTER"
+	// AzureDevOps is the VCS type for Azure DevOps Services.
+	AzureDevOps Type = "AZURE_DEVOPS"
 
 	// SQLReviewAPISecretName is the api secret name used in GitHub action or GitLab CI workflow.
 	SQLReviewAPISecretName = "SQL_REVIEW_API_SECRET"
@@ -122,10 +130,46 @@ type PushEvent struct {
 	RepositoryFullPath string   `json:"repositoryFullPath"`
 	AuthorName         string   `json:"authorName"`
 	CommitList         []Commit `json:"commits"`
+	// PullRequestID and PullRequestURL are set if the event is derived from a
+	// pull request rather than a push, in which case the issue created from it
+	// is a draft until the pull request is merged.
+	PullRequestID  string `json:"pullRequestId,omitempty"`
+	PullRequestURL string `json:"pullRequestUrl,omitempty"`
 	// Legacy field, kept for parsing existing data
 	FileCommit FileCommit `json:"fileCommit"`
 }
 
+// PullRequestAction is the action of a pull request event.
+type PullRequestAction string
+
+const (
+	// PullRequestActionOpened means the pull request is opened or reopened.
+	PullRequestActionOpened PullRequestAction = "OPENED"
+	// PullRequestActionUpdated means new commits are pushed to the source branch of the pull request.
+	PullRequestActionUpdated PullRequestAction = "UPDATED"
+	// PullRequestActionMerged means the pull request is merged.
+	PullRequestActionMerged PullRequestAction = "MERGED"
+	// PullRequestActionClosed means the pull request is closed without being merged.
+	PullRequestActionClosed PullRequestAction = "CLOSED"
+)
+
+// PullRequestEvent is the API message for a VCS pull request event.
+type PullRequestEvent struct {
+	Action        PullRequestAction
+	PullRequestID string
+	URL           string
+	Title         string
+	// HeadRef and BaseRef are the full refs of the source and target branches, e.g. "refs/heads/main".
+	HeadRef            string
+	HeadSHA            string
+	BaseRef            string
+	BaseSHA            string
+	RepositoryID       string
+	RepositoryURL      string
+	RepositoryFullPath string
+	AuthorName         string
+}
+
 // State is the state of a VCS user account.
 type State string
 
@@ -188,7 +232,33 @@ type PullRequestCreate struct {
 
 // PullRequest is the API message for pull request in repository.
 type PullRequest struct {
+	// ID is the pull request number in GitHub, and the merge request IID in GitLab.
+	ID  string `json:"id"`
 	URL string `json:"url"`
+	// Head is the source branch of the pull request.
+	Head string `json:"head"`
+}
+
+// CommitState is the state of a commit status.
+type CommitState string
+
+const (
+	// CommitStatePending means the check on the commit is in progress.
+	CommitStatePending CommitState = "PENDING"
+	// CommitStateSuccess means the check on the commit has passed.
+	CommitStateSuccess CommitState = "SUCCESS"
+	// CommitStateFailure means the check on the commit has failed.
+	CommitStateFailure CommitState = "FAILURE"
+)
+
+// CommitStatus is the API message for the status of a commit, which is shown
+// as a check in the pull request.
+type CommitStatus struct {
+	State CommitState
+	// Context differentiates this status from the statuses of other systems, e.g. "bytebase/sql-review".
+	Context     string
+	Description string
+	TargetURL   string
 }
 
 // Provider is the interface for VCS provider.
@@ -301,6 +371,36 @@ type Provider interface {
 	ListPullRequestFile(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, pullRequestID string) ([]*PullRequestFile, error)
 	// pullRequestCreate: the new pull request info
 	CreatePullRequest(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID string, pullRequestCreate *PullRequestCreate) (*PullRequest, error)
+	// ListPullRequest lists the open pull requests to the base branch in the repository.
+	//
+	// oauthCtx: OAuth context to list the pull requests
+	// instanceURL: VCS instance URL
+	// repositoryID: the repository ID from the external VCS system (note this is NOT the ID of Bytebase's own repository resource)
+	// base: the base branch of the pull requests
+	ListPullRequest(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, base string) ([]*PullRequest, error)
+	// ClosePullRequest closes the pull request without merging it.
+	//
+	// oauthCtx: OAuth context to close the pull request
+	// instanceURL: VCS instance URL
+	// repositoryID: the repository ID from the external VCS system (note this is NOT the ID of Bytebase's own repository resource)
+	// pullRequestID: the pull request id
+	ClosePullRequest(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, pullRequestID string) error
+	// CreatePullRequestComment creates a comment in the pull request.
+	//
+	// oauthCtx: OAuth context to create the comment
+	// instanceURL: VCS instance URL
+	// repositoryID: the repository ID from the external VCS system (note this is NOT the ID of Bytebase's own repository resource)
+	// pullRequestID: the pull request id
+	// comment: the comment content in markdown
+	CreatePullRequestComment(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, pullRequestID, comment string) error
+	// CreateCommitStatus creates or updates the status of the commit, which is shown as a check in the pull request.
+	//
+	// oauthCtx: OAuth context to create the commit status
+	// instanceURL: VCS instance URL
+	// repositoryID: the repository ID from the external VCS system (note this is NOT the ID of Bytebase's own repository resource)
+	// commitID: the commit ID
+	// status: the commit status, the status with the same context is overwritten
+	CreateCommitStatus(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, commitID string, status *CommitStatus) error
 	// UpsertEnvironmentVariable creates or updates the environment variable in the repository.
 	//
 	// oauthCtx: OAuth context to create the webhook
@@ -2,13 +2,28 @@ package webhook
 
 import (
 	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/pkg/errors"
 )
 
+const (
+	// CustomWebhookSignatureHeader is the header of the HMAC-SHA256 signature of the request body, in the format of "sha256=<hex digest>".
+	// It's only present if the secret of the webhook is set.
+	CustomWebhookSignatureHeader = "X-Bytebase-Signature-256"
+	// CustomWebhookDeliveryHeader is the header of the delivery ID, which is the same for the retries of a delivery.
+	CustomWebhookDeliveryHeader = "X-Bytebase-Delivery"
+	// CustomWebhookVersion is the version of the custom webhook request schema.
+	// Fields are only added within the same version, and it's bumped on breaking changes.
+	CustomWebhookVersion = "1"
+)
+
 // CustomWebhookResponse is the API message for Custom webhook response.
 type CustomWebhookResponse struct {
 	Code    int    `json:"code"`
@@ -16,17 +31,27 @@ type CustomWebhookResponse struct {
 }
 
 // CustomWebhookRequest is the API message for Custom webhook request.
+// The stage, task, task_result and approver are only present for the activities on the tasks.
+// The activity_payload is only present for the workspace webhooks.
 type CustomWebhookRequest struct {
-	Level        Level    `json:"level"`
-	ActivityType string   `json:"activity_type"`
-	Title        string   `json:"title"`
-	Description  string   `json:"description"`
-	Link         string   `json:"link"`
-	CreatorID    int      `json:"creator_id"`
-	CreatorName  string   `json:"creator_name"`
-	CreatedTS    int64    `json:"created_ts"`
-	Issue        *Issue   `json:"issue"`
-	Project      *Project `json:"project"`
+	Version      string      `json:"version"`
+	Level        Level       `json:"level"`
+	ActivityType string      `json:"activity_type"`
+	Title        string      `json:"title"`
+	Description  string      `json:"description"`
+	Link         string      `json:"link"`
+	CreatorID    int         `json:"creator_id"`
+	CreatorName  string      `json:"creator_name"`
+	CreatorEmail string      `json:"creator_email"`
+	CreatedTS    int64       `json:"created_ts"`
+	Issue        *Issue      `json:"issue"`
+	Project      *Project    `json:"project"`
+	Stage        *Stage      `json:"stage,omitempty"`
+	Task         *Task       `json:"task,omitempty"`
+	TaskResult   *TaskResult `json:"task_result,omitempty"`
+	Approver     *Principal  `json:"approver,omitempty"`
+	// ActivityPayload is the raw payload of the activity, it's only present for the workspace webhooks.
+	ActivityPayload json.RawMessage `json:"activity_payload,omitempty"`
 }
 
 func init() {
@@ -37,8 +62,8 @@ func init() {
 type CustomReceiver struct{}
 
 func (*CustomReceiver) post(context Context) error {
-	// TODO(p0ny): handle context.Task
 	payload := CustomWebhookRequest{
+		Version:      CustomWebhookVersion,
 		Level:        context.Level,
 		ActivityType: context.ActivityType,
 		Title:        context.Title,
@@ -46,9 +71,17 @@ func (*CustomReceiver) post(context Context) error {
 		Link:         context.Link,
 		CreatorID:    context.CreatorID,
 		CreatorName:  context.CreatorName,
+		CreatorEmail: context.CreatorEmail,
 		CreatedTS:    context.CreatedTs,
 		Issue:        context.Issue,
 		Project:      context.Project,
+		Stage:        context.Stage,
+		Task:         context.Task,
+		TaskResult:   context.TaskResult,
+		Approver:     context.Approver,
+	}
+	if context.ActivityPayload != "" && json.Valid([]byte(context.ActivityPayload)) {
+		payload.ActivityPayload = json.RawMessage(context.ActivityPayload)
 	}
 
 	body, err := json.Marshal(&payload)
@@ -62,6 +95,12 @@ func (*CustomReceiver) post(context Context) error {
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	if context.Secret != "" {
+		req.Header.Set(CustomWebhookSignatureHeader, signCustomWebhookBody(context.Secret, body))
+	}
+	if context.DeliveryID != 0 {
+		req.Header.Set(CustomWebhookDeliveryHeader, strconv.Itoa(context.DeliveryID))
+	}
 	client := &http.Client{
 		Timeout: timeout,
 	}
@@ -77,7 +116,7 @@ func (*CustomReceiver) post(context Context) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.Errorf("failed to POST webhook %s, status code: %d, response body: %s", context.URL, resp.StatusCode, b)
+		return &ResponseError{URL: context.URL, StatusCode: resp.StatusCode, Body: string(b)}
 	}
 
 	webhookResponse := &CustomWebhookResponse{}
@@ -91,3 +130,11 @@ func (*CustomReceiver) post(context Context) error {
 
 	return nil
 }
+
+// signCustomWebhookBody returns the HMAC-SHA256 signature of the body with the secret.
+func signCustomWebhookBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	// hash.Hash never returns an error on Write.
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,73 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignCustomWebhookBody(t *testing.T) {
+	a := require.New(t)
+	// The signature can be verified by `echo -n '{"title":"test"}' | openssl dgst -sha256 -hmac secret`.
+	a.Equal("sha256=427628034681b0bfa22da50925372ad52a6fd5e990366a1a4ce9755986ddc959", signCustomWebhookBody("secret", []byte(`{"title":"test"}`)))
+}
+
+func TestCustomReceiver_post(t *testing.T) {
+	a := require.New(t)
+	var gotSignature, gotDelivery string
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSignature = r.Header.Get(CustomWebhookSignatureHeader)
+		gotDelivery = r.Header.Get(CustomWebhookDeliveryHeader)
+		body, err := io.ReadAll(r.Body)
+		a.NoError(err)
+		gotBody = body
+		_ = json.NewEncoder(w).Encode(&CustomWebhookResponse{})
+	}))
+	defer server.Close()
+
+	err := Post("bb.plugin.webhook.custom", Context{
+		URL:        server.URL,
+		Secret:     "secret",
+		DeliveryID: 101,
+		Title:      "test",
+		Stage:      &Stage{ID: 1, Name: "Prod Stage", EnvironmentID: 2, EnvironmentName: "Prod"},
+		Task:       &Task{ID: 3, Name: "Update schema", DatabaseName: "db", InstanceName: "instance", MigrationVersion: "20221220000000"},
+		TaskResult: &TaskResult{Name: "Update schema", Status: "FAILED", Detail: "syntax error"},
+	})
+	a.NoError(err)
+	a.Equal("101", gotDelivery)
+	a.Equal(signCustomWebhookBody("secret", gotBody), gotSignature)
+	var request CustomWebhookRequest
+	a.NoError(json.Unmarshal(gotBody, &request))
+	a.Equal(CustomWebhookVersion, request.Version)
+	a.Equal("Prod", request.Stage.EnvironmentName)
+	a.Equal("db", request.Task.DatabaseName)
+	a.Equal("20221220000000", request.Task.MigrationVersion)
+	a.Equal("syntax error", request.TaskResult.Detail)
+	a.Nil(request.Approver)
+	a.Nil(request.ActivityPayload)
+
+	err = Post("bb.plugin.webhook.custom", Context{
+		URL:             server.URL,
+		Title:           "Instance created",
+		ActivityPayload: `{"instanceName":"prod","engine":"MYSQL"}`,
+	})
+	a.NoError(err)
+	request = CustomWebhookRequest{}
+	a.NoError(json.Unmarshal(gotBody, &request))
+	a.JSONEq(`{"instanceName":"prod","engine":"MYSQL"}`, string(request.ActivityPayload))
+
+	failedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failedServer.Close()
+	err = Post("bb.plugin.webhook.custom", Context{URL: failedServer.URL})
+	responseErr, ok := err.(*ResponseError)
+	a.True(ok)
+	a.Equal(http.StatusBadGateway, responseErr.StatusCode)
+}
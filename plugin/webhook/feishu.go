@@ -163,7 +163,7 @@ func (*FeishuReceiver) post(context Context) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.Errorf("failed to POST webhook %s, status code: %d, response body: %s", context.URL, resp.StatusCode, b)
+		return &ResponseError{URL: context.URL, StatusCode: resp.StatusCode, Body: string(b)}
 	}
 
 	webhookResponse := &FeishuWebhookResponse{}
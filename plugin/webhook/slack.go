@@ -8,6 +8,8 @@ import (
 	"net/http"
 
 	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/plugin/app/slack"
 )
 
 // SlackWebhookBlockMarkdown is the API message for Slack webhook block markdown.
@@ -23,10 +25,14 @@ type SlackWebhookElementButton struct {
 }
 
 // SlackWebhookElement is the API message for Slack webhook element.
+// The ActionID and Value are sent back to Bytebase when the user clicks the button, if the interactivity is enabled in the Slack app.
 type SlackWebhookElement struct {
-	Type   string                    `json:"type"`
-	Button SlackWebhookElementButton `json:"text,omitempty"`
-	URL    string                    `json:"url,omitempty"`
+	Type     string                    `json:"type"`
+	Button   SlackWebhookElementButton `json:"text,omitempty"`
+	URL      string                    `json:"url,omitempty"`
+	ActionID string                    `json:"action_id,omitempty"`
+	Value    string                    `json:"value,omitempty"`
+	Style    string                    `json:"style,omitempty"`
 }
 
 // SlackWebhookBlock is the API message for Slack webhook block.
@@ -98,18 +104,13 @@ func (*SlackReceiver) post(context Context) error {
 		},
 	})
 
+	elementList, err := getSlackElementList(context)
+	if err != nil {
+		return err
+	}
 	blockList = append(blockList, SlackWebhookBlock{
-		Type: "actions",
-		ElementList: []SlackWebhookElement{
-			{
-				Type: "button",
-				Button: SlackWebhookElementButton{
-					Type: "plain_text",
-					Text: "View in Bytebase",
-				},
-				URL: context.Link,
-			},
-		},
+		Type:        "actions",
+		ElementList: elementList,
 	})
 
 	post := SlackWebhook{
@@ -142,7 +143,7 @@ func (*SlackReceiver) post(context Context) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.Errorf("failed to POST webhook to %s, status code: %d, response body: %s", context.URL, resp.StatusCode, b)
+		return &ResponseError{URL: context.URL, StatusCode: resp.StatusCode, Body: string(b)}
 	}
 
 	if string(b) != "ok" {
@@ -151,3 +152,52 @@ func (*SlackReceiver) post(context Context) error {
 
 	return nil
 }
+
+// getSlackElementList returns the buttons of the message.
+// The approve and reject buttons are only present if the issue has tasks pending approval.
+func getSlackElementList(context Context) ([]SlackWebhookElement, error) {
+	elementList := []SlackWebhookElement{
+		{
+			Type: "button",
+			Button: SlackWebhookElementButton{
+				Type: "plain_text",
+				Text: "View in Bytebase",
+			},
+			URL: context.Link,
+		},
+	}
+	if context.PendingApproval == nil || context.Issue == nil {
+		return elementList, nil
+	}
+
+	value, err := json.Marshal(&slack.ApprovalValue{
+		IssueID: context.Issue.ID,
+		StageID: context.PendingApproval.StageID,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal approval value")
+	}
+	elementList = append(elementList,
+		SlackWebhookElement{
+			Type: "button",
+			Button: SlackWebhookElementButton{
+				Type: "plain_text",
+				Text: fmt.Sprintf("Approve %s", context.PendingApproval.StageName),
+			},
+			ActionID: string(slack.ActionApprove),
+			Value:    string(value),
+			Style:    "primary",
+		},
+		SlackWebhookElement{
+			Type: "button",
+			Button: SlackWebhookElementButton{
+				Type: "plain_text",
+				Text: "Reject",
+			},
+			ActionID: string(slack.ActionReject),
+			Value:    string(value),
+			Style:    "danger",
+		},
+	)
+	return elementList, nil
+}
@@ -0,0 +1,36 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/app/slack"
+)
+
+func TestGetSlackElementList(t *testing.T) {
+	a := require.New(t)
+	context := Context{
+		Link: "http://localhost:8080/issue/101",
+		Issue: &Issue{
+			ID: 101,
+		},
+	}
+	elementList, err := getSlackElementList(context)
+	a.NoError(err)
+	a.Len(elementList, 1)
+	a.Equal(context.Link, elementList[0].URL)
+
+	context.PendingApproval = &PendingApproval{
+		StageID:   102,
+		StageName: "Prod",
+	}
+	elementList, err = getSlackElementList(context)
+	a.NoError(err)
+	a.Len(elementList, 3)
+	a.Equal(string(slack.ActionApprove), elementList[1].ActionID)
+	a.Equal("Approve Prod", elementList[1].Button.Text)
+	a.Equal(`{"issueId":101,"stageId":102}`, elementList[1].Value)
+	a.Equal(string(slack.ActionReject), elementList[2].ActionID)
+	a.Equal(`{"issueId":101,"stageId":102}`, elementList[2].Value)
+}
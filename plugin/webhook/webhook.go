@@ -2,6 +2,7 @@
 package webhook
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
@@ -60,21 +61,79 @@ type Project struct {
 	Name string `json:"name"`
 }
 
+// Stage is the stage of the task.
+type Stage struct {
+	ID              int    `json:"id"`
+	Name            string `json:"name"`
+	EnvironmentID   int    `json:"environment_id"`
+	EnvironmentName string `json:"environment_name"`
+}
+
+// Task is the task of the activity.
+// The Statement is truncated to limit the message size in the webhook body.
+type Task struct {
+	ID               int    `json:"id"`
+	Name             string `json:"name"`
+	Type             string `json:"type"`
+	Status           string `json:"status"`
+	InstanceName     string `json:"instance_name"`
+	DatabaseName     string `json:"database_name"`
+	Statement        string `json:"statement"`
+	MigrationVersion string `json:"migration_version"`
+}
+
+// Principal is a user taking part in the activity, e.g. the approver of the tasks.
+type Principal struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// PendingApproval is the stage with tasks pending approval.
+// Receivers supporting interactive messages, e.g. Slack, post the approve and reject actions for it.
+type PendingApproval struct {
+	StageID   int    `json:"stageId"`
+	StageName string `json:"stageName"`
+}
+
 // Context is the context of webhook.
+// The context is persisted as the payload of the webhook delivery, so the URL and Secret which are
+// retrieved from the webhook on delivery are excluded.
 type Context struct {
-	URL          string
-	Level        Level
-	ActivityType string
-	Title        string
-	Description  string
-	Link         string
-	CreatorID    int
-	CreatorName  string
-	CreatorEmail string
-	CreatedTs    int64
-	Issue        *Issue
-	Project      *Project
-	TaskResult   *TaskResult
+	URL          string      `json:"-"`
+	Secret       string      `json:"-"`
+	DeliveryID   int         `json:"-"`
+	Level        Level       `json:"level"`
+	ActivityType string      `json:"activityType"`
+	Title        string      `json:"title"`
+	Description  string      `json:"description"`
+	Link         string      `json:"link"`
+	CreatorID    int         `json:"creatorId"`
+	CreatorName  string      `json:"creatorName"`
+	CreatorEmail string      `json:"creatorEmail"`
+	CreatedTs    int64       `json:"createdTs"`
+	Issue        *Issue      `json:"issue,omitempty"`
+	Project      *Project    `json:"project,omitempty"`
+	TaskResult   *TaskResult `json:"taskResult,omitempty"`
+	Stage        *Stage      `json:"stage,omitempty"`
+	Task         *Task       `json:"task,omitempty"`
+	// Approver is only present if the activity approves the tasks.
+	Approver *Principal `json:"approver,omitempty"`
+	// PendingApproval is only present if the active stage of the issue has tasks pending approval.
+	PendingApproval *PendingApproval `json:"pendingApproval,omitempty"`
+	// ActivityPayload is the JSON payload of the activity, it's only sent to the workspace webhooks.
+	ActivityPayload string `json:"activityPayload,omitempty"`
+}
+
+// ResponseError is the error returned if the webhook endpoint responds with an unexpected status code.
+type ResponseError struct {
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("failed to POST webhook to %s, status code: %d, response body: %s", e.URL, e.StatusCode, e.Body)
 }
 
 // Receiver is the webhook receiver.
@@ -0,0 +1,350 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/store"
+)
+
+const (
+	// accessTokenLength is the length of the random part of the long-lived access token.
+	accessTokenLength = 40
+	// accessTokenLastUsedInterval throttles the update of the last used time, so that a busy token doesn't write on every request.
+	accessTokenLastUsedInterval = 1 * time.Minute
+)
+
+var taskCheckRouteRegex = regexp.MustCompile(`^/pipeline/\d+/task/\d+/check$`)
+
+// projectResourceRouteRegex matches the routes of the project and the project-owned resources, e.g. /database/101/backup.
+var projectResourceRouteRegex = regexp.MustCompile(`^/(?P<resourceType>project|database|issue|pipeline|sheet)/(?P<resourceID>\d+)(/|$)`)
+
+func (s *Server) registerAccessTokenRoutes(g *echo.Group) {
+	g.GET("/principal/:principalID/access-token", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		principalID, err := strconv.Atoi(c.Param("principalID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("principalID"))).SetInternal(err)
+		}
+
+		accessTokenList, err := s.store.FindAccessToken(ctx, &api.AccessTokenFind{PrincipalID: &principalID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch access token list of principal ID: %v", principalID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, accessTokenList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal access token list response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.POST("/principal/:principalID/access-token", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		principalID, err := strconv.Atoi(c.Param("principalID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("principalID"))).SetInternal(err)
+		}
+		accessTokenCreate := &api.AccessTokenCreate{
+			CreatorID:   c.Get(getPrincipalIDContextKey()).(int),
+			PrincipalID: principalID,
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, accessTokenCreate); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create access token request").SetInternal(err)
+		}
+
+		principal, err := s.store.GetPrincipalByID(ctx, principalID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch principal ID: %v", principalID)).SetInternal(err)
+		}
+		if principal == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("User ID not found: %d", principalID))
+		}
+		if principal.Type == api.SystemBot {
+			return echo.NewHTTPError(http.StatusBadRequest, "Cannot create access token for the system bot")
+		}
+		// The workspace owner manages the keys of the service accounts, but never creates the personal access tokens of other users.
+		if principalID != accessTokenCreate.CreatorID && principal.Type != api.ServiceAccount {
+			return echo.NewHTTPError(http.StatusForbidden, "Cannot create personal access token for other users")
+		}
+		if httpErr := s.validateAccessTokenCreate(c, accessTokenCreate); httpErr != nil {
+			return httpErr
+		}
+
+		prefix := personalAccessTokenPrefix
+		if principal.Type == api.ServiceAccount {
+			prefix = serviceAccountKeyPrefix
+		}
+		random, err := common.RandomString(accessTokenLength)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate access token").SetInternal(err)
+		}
+		token := prefix + random
+		accessTokenCreate.TokenHash = hashAccessToken(token)
+
+		accessToken, err := s.store.CreateAccessToken(ctx, accessTokenCreate)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create access token").SetInternal(err)
+		}
+		// Only return the token for the first time after the creation.
+		accessToken.Token = token
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, accessToken); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal create access token response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.DELETE("/principal/:principalID/access-token/:accessTokenID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		principalID, err := strconv.Atoi(c.Param("principalID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("principalID"))).SetInternal(err)
+		}
+		id, err := strconv.Atoi(c.Param("accessTokenID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Access token ID is not a number: %s", c.Param("accessTokenID"))).SetInternal(err)
+		}
+
+		// The principal is part of the filter, so that one can't revoke the token of others by the self route.
+		accessToken, err := s.store.GetAccessToken(ctx, &api.AccessTokenFind{ID: &id, PrincipalID: &principalID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch access token ID: %v", id)).SetInternal(err)
+		}
+		if accessToken == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Access token ID not found: %d", id))
+		}
+
+		if err := s.store.DeleteAccessToken(ctx, &api.AccessTokenDelete{
+			ID:        id,
+			DeleterID: c.Get(getPrincipalIDContextKey()).(int),
+		}); err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Access token ID not found: %d", id))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to delete access token ID: %v", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		c.Response().WriteHeader(http.StatusOK)
+		return nil
+	})
+}
+
+func (s *Server) validateAccessTokenCreate(c echo.Context, create *api.AccessTokenCreate) *echo.HTTPError {
+	ctx := c.Request().Context()
+	create.Name = strings.TrimSpace(create.Name)
+	if create.Name == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Access token name cannot be empty")
+	}
+
+	scopeList := []string{}
+	scopeSet := make(map[string]bool)
+	for _, scope := range create.ScopeList {
+		switch api.AccessTokenScope(scope) {
+		case api.AccessTokenScopeReadOnly, api.AccessTokenScopeSQLReview, api.AccessTokenScopeIssueCreate:
+		default:
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid access token scope %q", scope))
+		}
+		if !scopeSet[scope] {
+			scopeSet[scope] = true
+			scopeList = append(scopeList, scope)
+		}
+	}
+	create.ScopeList = scopeList
+
+	if create.ProjectID != 0 {
+		project, err := s.store.GetProjectByID(ctx, create.ProjectID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch project ID: %v", create.ProjectID)).SetInternal(err)
+		}
+		if project == nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID not found: %d", create.ProjectID))
+		}
+	}
+	if create.ExpiresTs < 0 || (create.ExpiresTs > 0 && create.ExpiresTs <= time.Now().Unix()) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Access token expiration time must be in the future")
+	}
+	return nil
+}
+
+// authenticateAccessToken authenticates the request by the long-lived access token, and stores the principal ID
+// and the access token into the context.
+func authenticateAccessToken(c echo.Context, principalStore *store.Store, token string, path string) error {
+	ctx := c.Request().Context()
+	tokenHash := hashAccessToken(token)
+	accessToken, err := principalStore.GetAccessToken(ctx, &api.AccessTokenFind{TokenHash: &tokenHash})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Server error to find access token").SetInternal(err)
+	}
+	if accessToken == nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Invalid access token")
+	}
+	now := time.Now().Unix()
+	if accessToken.ExpiresTs != 0 && accessToken.ExpiresTs <= now {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Expired access token")
+	}
+
+	// Even if the token is valid, we still need to make sure the user still exists.
+	user, err := principalStore.GetPrincipalByID(ctx, accessToken.PrincipalID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Server error to find user ID: %d", accessToken.PrincipalID)).SetInternal(err)
+	}
+	if user == nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, fmt.Sprintf("Failed to find user ID: %d", accessToken.PrincipalID))
+	}
+
+	method := c.Request().Method
+	if !isAccessTokenScopeAllowed(accessToken.ScopeList, method, path) {
+		return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("Access token scope %v does not allow %s %s", accessToken.ScopeList, method, path))
+	}
+	allowed, err := isAccessTokenProjectAllowed(accessToken.ProjectID, method, path, c.QueryParam("project"), func(resourceType string, resourceID int) (int, error) {
+		return getResourceProjectID(ctx, principalStore, resourceType, resourceID)
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find the project of the requested resource").SetInternal(err)
+	}
+	if !allowed {
+		return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("Access token is restricted to project ID %d", accessToken.ProjectID))
+	}
+
+	if now-accessToken.LastUsedTs >= int64(accessTokenLastUsedInterval.Seconds()) {
+		if _, err := principalStore.PatchAccessToken(ctx, &api.AccessTokenPatch{
+			ID:         accessToken.ID,
+			UpdaterID:  accessToken.PrincipalID,
+			LastUsedTs: &now,
+		}); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update access token").SetInternal(err)
+		}
+	}
+
+	// Stores principalID and access token into context.
+	c.Set(getPrincipalIDContextKey(), accessToken.PrincipalID)
+	c.Set(getAccessTokenContextKey(), accessToken)
+	return nil
+}
+
+// checkAccessTokenProject returns an error if the request is authenticated by an access token restricted to another project.
+func checkAccessTokenProject(c echo.Context, projectID int) *echo.HTTPError {
+	accessToken, ok := c.Get(getAccessTokenContextKey()).(*api.AccessToken)
+	if !ok || accessToken.ProjectID == 0 || accessToken.ProjectID == projectID {
+		return nil
+	}
+	return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("Access token is restricted to project ID %d", accessToken.ProjectID))
+}
+
+// isLongLivedAccessToken returns true if the token is a personal access token or a service account key,
+// rather than a JWT.
+func isLongLivedAccessToken(token string) bool {
+	return common.HasPrefixes(token, personalAccessTokenPrefix, serviceAccountKeyPrefix)
+}
+
+// hashAccessToken returns the hex encoded SHA-256 hash of the token.
+// The token has enough entropy, so it doesn't need a slow hash like the password.
+func hashAccessToken(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
+
+// isAccessTokenScopeAllowed returns true if the scopes of the access token allow the request.
+// Every scope allows reading the resources, and the token has the full access of the principal if the scope list is empty.
+// The access tokens can never manage the access tokens and the two-factor authentication, so that a leaked token
+// can't issue new ones or disable the second factor.
+func isAccessTokenScopeAllowed(scopeList []string, method string, path string) bool {
+	if strings.Contains(path, "/access-token") || strings.HasSuffix(path, "/mfa") || strings.Contains(path, "/mfa/") {
+		return false
+	}
+	if len(scopeList) == 0 || method == http.MethodGet {
+		return true
+	}
+	for _, scope := range scopeList {
+		switch api.AccessTokenScope(scope) {
+		case api.AccessTokenScopeSQLReview:
+			if method == http.MethodPost && taskCheckRouteRegex.MatchString(path) {
+				return true
+			}
+		case api.AccessTokenScopeIssueCreate:
+			if method == http.MethodPost && path == "/issue" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isAccessTokenProjectAllowed returns true if the request only operates on the project the access token is restricted to.
+// The project of the requested resource is found by getProjectID, and the routes not operating on a project-owned resource
+// are denied, e.g. listing the databases without the project query. The project of the created issue is enforced by the handler.
+func isAccessTokenProjectAllowed(tokenProjectID int, method, path, projectQuery string, getProjectID func(resourceType string, resourceID int) (int, error)) (bool, error) {
+	if tokenProjectID == 0 {
+		return true, nil
+	}
+	if method == http.MethodPost && path == "/issue" {
+		return true, nil
+	}
+	if path == "/database" || path == "/issue" {
+		return projectQuery == strconv.Itoa(tokenProjectID), nil
+	}
+	matches := projectResourceRouteRegex.FindStringSubmatch(path)
+	if matches == nil {
+		return false, nil
+	}
+	resourceType := matches[1]
+	resourceID, err := strconv.Atoi(matches[2])
+	if err != nil {
+		return false, nil
+	}
+	if resourceType == "project" {
+		return resourceID == tokenProjectID, nil
+	}
+	projectID, err := getProjectID(resourceType, resourceID)
+	if err != nil {
+		return false, err
+	}
+	return projectID == tokenProjectID, nil
+}
+
+// getResourceProjectID returns the ID of the project owning the resource, or 0 if the resource is not found.
+func getResourceProjectID(ctx context.Context, principalStore *store.Store, resourceType string, resourceID int) (int, error) {
+	switch resourceType {
+	case "database":
+		database, err := principalStore.GetDatabase(ctx, &api.DatabaseFind{ID: &resourceID})
+		if err != nil || database == nil {
+			return 0, err
+		}
+		return database.ProjectID, nil
+	case "issue":
+		issue, err := principalStore.GetIssueByID(ctx, resourceID)
+		if err != nil || issue == nil {
+			return 0, err
+		}
+		return issue.ProjectID, nil
+	case "pipeline":
+		issue, err := principalStore.GetIssueByPipelineID(ctx, resourceID)
+		if err != nil || issue == nil {
+			return 0, err
+		}
+		return issue.ProjectID, nil
+	case "sheet":
+		sheet, err := principalStore.GetSheet(ctx, &api.SheetFind{ID: &resourceID}, api.SystemBotID)
+		if err != nil || sheet == nil {
+			return 0, err
+		}
+		return sheet.ProjectID, nil
+	}
+	return 0, errors.Errorf("unknown project resource type %q", resourceType)
+}
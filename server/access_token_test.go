@@ -0,0 +1,122 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsAccessTokenScopeAllowed(t *testing.T) {
+	tests := []struct {
+		scopeList []string
+		method    string
+		path      string
+		want      bool
+	}{
+		// The token without scope has the full access of the principal.
+		{nil, http.MethodPatch, "/project/101", true},
+		{nil, http.MethodPost, "/issue", true},
+		// The access tokens can never manage the access tokens.
+		{nil, http.MethodPost, "/principal/101/access-token", false},
+		{[]string{"read-only"}, http.MethodGet, "/principal/101/access-token", false},
+		{nil, http.MethodDelete, "/principal/101/mfa", false},
+		{nil, http.MethodPost, "/principal/101/mfa/recovery-code", false},
+		{[]string{"read-only"}, http.MethodGet, "/issue/101", true},
+		{[]string{"read-only"}, http.MethodPost, "/issue", false},
+		{[]string{"read-only"}, http.MethodPost, "/pipeline/101/task/102/check", false},
+		{[]string{"sql-review"}, http.MethodGet, "/database", true},
+		{[]string{"sql-review"}, http.MethodPost, "/pipeline/101/task/102/check", true},
+		{[]string{"sql-review"}, http.MethodPatch, "/pipeline/101/task/102/status", false},
+		{[]string{"sql-review"}, http.MethodPost, "/issue", false},
+		{[]string{"issue-create"}, http.MethodPost, "/issue", true},
+		{[]string{"issue-create"}, http.MethodPatch, "/issue/101", false},
+		{[]string{"read-only", "issue-create"}, http.MethodPost, "/issue", true},
+	}
+
+	for _, test := range tests {
+		got := isAccessTokenScopeAllowed(test.scopeList, test.method, test.path)
+		require.Equal(t, test.want, got, "%v %s %s", test.scopeList, test.method, test.path)
+	}
+}
+
+func TestIsAccessTokenProjectAllowed(t *testing.T) {
+	// The databases, issues, pipelines and sheets with ID 1xx are in project 101, and the ones with ID 2xx are in project 102.
+	getProjectID := func(resourceType string, resourceID int) (int, error) {
+		switch {
+		case resourceType == "issue" && resourceID == 999:
+			return 0, errors.New("server error")
+		case resourceID < 100 || resourceID >= 300:
+			return 0, nil
+		case resourceID < 200:
+			return 101, nil
+		default:
+			return 102, nil
+		}
+	}
+	tests := []struct {
+		tokenProjectID int
+		method         string
+		path           string
+		projectQuery   string
+		want           bool
+	}{
+		// The token without project restriction can access all projects.
+		{0, http.MethodPatch, "/project/102", "", true},
+		{0, http.MethodGet, "/database/201", "", true},
+		{0, http.MethodGet, "/environment", "", true},
+		{101, http.MethodGet, "/project/101", "", true},
+		{101, http.MethodPatch, "/project/101/member", "", true},
+		{101, http.MethodGet, "/project/102", "", false},
+		{101, http.MethodGet, "/project/1011", "", false},
+		// The list routes are only allowed with the project query of the token.
+		{101, http.MethodGet, "/issue", "101", true},
+		{101, http.MethodGet, "/issue", "102", false},
+		{101, http.MethodGet, "/issue", "", false},
+		{101, http.MethodGet, "/database", "101", true},
+		{101, http.MethodGet, "/database", "", false},
+		// The project of the created issue is enforced by the handler.
+		{101, http.MethodPost, "/issue", "", true},
+		// Read the resources in the project and in another project.
+		{101, http.MethodGet, "/database/101", "", true},
+		{101, http.MethodGet, "/database/201/table", "", false},
+		{101, http.MethodGet, "/issue/102", "", true},
+		{101, http.MethodGet, "/issue/202", "", false},
+		{101, http.MethodGet, "/sheet/103", "", true},
+		{101, http.MethodGet, "/sheet/203", "", false},
+		// Write the resources in the project and in another project.
+		{101, http.MethodPatch, "/issue/102/status", "", true},
+		{101, http.MethodPatch, "/issue/202/status", "", false},
+		{101, http.MethodPost, "/database/101/backup", "", true},
+		{101, http.MethodPost, "/database/201/backup", "", false},
+		{101, http.MethodPatch, "/pipeline/104/task/1/status", "", true},
+		{101, http.MethodPost, "/pipeline/204/task/1/check", "", false},
+		{101, http.MethodDelete, "/sheet/203", "", false},
+		// The resources not found are denied.
+		{101, http.MethodGet, "/database/301", "", false},
+		// The routes not operating on a project-owned resource are denied.
+		{101, http.MethodGet, "/environment", "", false},
+		{101, http.MethodGet, "/instance/101/database", "", false},
+		{101, http.MethodGet, "/sheet/my", "", false},
+		{101, http.MethodGet, "/database101", "", false},
+	}
+
+	for _, test := range tests {
+		got, err := isAccessTokenProjectAllowed(test.tokenProjectID, test.method, test.path, test.projectQuery, getProjectID)
+		require.NoError(t, err)
+		require.Equal(t, test.want, got, "%d %s %s?project=%s", test.tokenProjectID, test.method, test.path, test.projectQuery)
+	}
+
+	_, err := isAccessTokenProjectAllowed(101, http.MethodGet, "/issue/999", "", getProjectID)
+	require.Error(t, err)
+}
+
+func TestHashAccessToken(t *testing.T) {
+	a := require.New(t)
+	a.Equal("9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", hashAccessToken("test"))
+	a.True(isLongLivedAccessToken("bbp_abc"))
+	a.True(isLongLivedAccessToken("bbk_abc"))
+	a.False(isLongLivedAccessToken("bbs_abc"))
+	a.False(isLongLivedAccessToken("eyJhbGciOiJIUzI1NiJ9"))
+}
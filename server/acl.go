@@ -9,28 +9,39 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/casbin/casbin/v2"
 	"github.com/pkg/errors"
 
 	"github.com/labstack/echo/v4"
 
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/server/component/rbac"
 )
 
 const (
-	roleContextKey = "role"
+	roleContextKey           = "role"
+	permissionListContextKey = "permission-list"
 )
 
 func getRoleContextKey() string {
 	return roleContextKey
 }
 
+func getPermissionListContextKey() string {
+	return permissionListContextKey
+}
+
+// hasWorkspacePermission returns whether the role of the current member grants the workspace permission.
+func hasWorkspacePermission(c echo.Context, permission api.Permission) bool {
+	permissionList, _ := c.Get(getPermissionListContextKey()).([]api.Permission)
+	return rbac.HasPermission(permissionList, permission)
+}
+
 var projectGeneralRouteRegex = regexp.MustCompile(`^/project/(?P<projectID>\d+)`)
 var projectMemberRouteRegex = regexp.MustCompile(`^/project/(?P<projectID>\d+)/member`)
 var projectSyncSheetRouteRegex = regexp.MustCompile(`^/project/(?P<projectID>\d+)/sync-sheet`)
 
-func enforceWorkspaceDeveloperProjectRouteACL(plan api.PlanType, path string, method string, quaryParams url.Values, principalID int, roleFinder func(projectID int, principalID int) (common.ProjectRole, error)) *echo.HTTPError {
+func enforceWorkspaceDeveloperProjectRouteACL(plan api.PlanType, path string, method string, quaryParams url.Values, principalID int, roleFinder func(projectID int, principalID int) (common.ProjectRole, error), customRoleFinder func(role string) (*api.CustomRole, error)) *echo.HTTPError {
 	var projectID int
 	var permission api.ProjectPermissionType
 	var permissionErrMsg string
@@ -71,7 +82,11 @@ func enforceWorkspaceDeveloperProjectRouteACL(plan api.PlanType, path string, me
 			return echo.NewHTTPError(http.StatusUnauthorized, "is not a member of the project")
 		}
 
-		if !api.ProjectPermission(permission, plan, role) {
+		ok, err := rbac.HasProjectRolePermission(permission, plan, role, customRoleFinder)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process authorize request.").SetInternal(err)
+		}
+		if !ok {
 			return echo.NewHTTPError(http.StatusUnauthorized, permissionErrMsg)
 		}
 	}
@@ -82,7 +97,7 @@ func enforceWorkspaceDeveloperProjectRouteACL(plan api.PlanType, path string, me
 var sheetRouteRegex = regexp.MustCompile(`^/sheet/(?P<sheetID>\d+)`)
 var sheetOrganizeRouteRegex = regexp.MustCompile(`^/sheet/(?P<projectID>\d+)/organize`)
 
-func enforceWorkspaceDeveloperSheetRouteACL(plan api.PlanType, path string, method string, principalID int, roleFinder func(projectID int, principalID int) (common.ProjectRole, error), sheetFinder func(sheetID int) (*api.Sheet, error)) *echo.HTTPError {
+func enforceWorkspaceDeveloperSheetRouteACL(plan api.PlanType, path string, method string, principalID int, roleFinder func(projectID int, principalID int) (common.ProjectRole, error), customRoleFinder func(role string) (*api.CustomRole, error), sheetFinder func(sheetID int) (*api.Sheet, error)) *echo.HTTPError {
 	if matches := sheetOrganizeRouteRegex.FindStringSubmatch(path); matches != nil {
 		sheetID, _ := strconv.Atoi(matches[1])
 		sheet, err := sheetFinder(sheetID)
@@ -111,7 +126,11 @@ func enforceWorkspaceDeveloperSheetRouteACL(plan api.PlanType, path string, meth
 				return echo.NewHTTPError(http.StatusUnauthorized, "is not a member of the project containing the sheet")
 			}
 
-			if !api.ProjectPermission(api.ProjectPermissionOrganizeSheet, plan, role) {
+			ok, err := rbac.HasProjectRolePermission(api.ProjectPermissionOrganizeSheet, plan, role, customRoleFinder)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process authorize request.").SetInternal(err)
+			}
+			if !ok {
 				return echo.NewHTTPError(http.StatusUnauthorized, "not have permission to organize the project sheet")
 			}
 		}
@@ -150,7 +169,11 @@ func enforceWorkspaceDeveloperSheetRouteACL(plan api.PlanType, path string, meth
 				return nil
 			}
 
-			if !api.ProjectPermission(api.ProjectPermissionAdminSheet, plan, role) {
+			ok, err := rbac.HasProjectRolePermission(api.ProjectPermissionAdminSheet, plan, role, customRoleFinder)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process authorize request.").SetInternal(err)
+			}
+			if !ok {
 				return echo.NewHTTPError(http.StatusUnauthorized, "not have permission to change the project sheet")
 			}
 		}
@@ -159,7 +182,7 @@ func enforceWorkspaceDeveloperSheetRouteACL(plan api.PlanType, path string, meth
 	return nil
 }
 
-func aclMiddleware(s *Server, pathPrefix string, ce *casbin.Enforcer, next echo.HandlerFunc, readonly bool) echo.HandlerFunc {
+func aclMiddleware(s *Server, pathPrefix string, next echo.HandlerFunc, readonly bool) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		ctx := c.Request().Context()
 
@@ -204,29 +227,20 @@ func aclMiddleware(s *Server, pathPrefix string, ce *casbin.Enforcer, next echo.
 		if !s.licenseService.IsFeatureEnabled(api.FeatureRBAC) {
 			role = api.Owner
 		}
-
-		// Performs the ACL check.
-		pass, err := ce.Enforce(string(role), path, method)
+		permissionList, err := s.getRolePermissionList(ctx, role)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process authorize request.").SetInternal(err)
 		}
 
-		if !pass {
-			// If the request is trying to GET/PATCH/DELETE itself, we will change the method signature to
-			// XXX_SELF and try again. Because XXX is a superset of XXX_SELF, thus we only try XXX_SELF after
-			// XXX fails.
-			if method == "GET" || method == "PATCH" || method == "DELETE" {
-				if isSelf, err := isOperatingSelf(ctx, c, s, principalID, method, path); err != nil {
-					return err
-				} else if isSelf {
-					method += "_SELF"
-
-					// Performs the ACL check with _SELF.
-					pass, err = ce.Enforce(string(role), path, method)
-					if err != nil {
-						return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process authorize request.").SetInternal(err)
-					}
-				}
+		// Performs the ACL check.
+		pass := false
+		if acl := findRouteACL(method, path); acl != nil {
+			// The members without the permission can still GET/POST/PATCH/DELETE themselves if the route allows.
+			pass, err = hasRoutePermission(acl, permissionList, func() (bool, error) {
+				return isOperatingSelf(ctx, c, s, principalID, method, path)
+			})
+			if err != nil {
+				return err
 			}
 		}
 
@@ -235,9 +249,9 @@ func aclMiddleware(s *Server, pathPrefix string, ce *casbin.Enforcer, next echo.
 				errors.Errorf("rejected by the ACL policy; %s %s u%d/%s", method, path, principalID, role))
 		}
 
-		// Workspace Owner or DBA assumes project Owner role for all projects, so will
-		// pass any project ACL.
-		if role != api.Owner && role != api.DBA {
+		// The project admin, e.g. the workspace Owner or DBA, assumes project Owner role for all projects,
+		// so will pass any project ACL.
+		if !rbac.HasPermission(permissionList, api.PermissionProjectAdmin) {
 			var aclErr *echo.HTTPError
 			roleFinder := func(projectID int, principalID int) (common.ProjectRole, error) {
 				memberList, err := s.store.FindProjectMember(ctx, &api.ProjectMemberFind{ProjectID: &projectID, PrincipalID: &principalID})
@@ -250,6 +264,10 @@ func aclMiddleware(s *Server, pathPrefix string, ce *casbin.Enforcer, next echo.
 				return "", nil
 			}
 
+			customRoleFinder := func(role string) (*api.CustomRole, error) {
+				return s.findCustomRole(ctx, api.Role(role))
+			}
+
 			sheetFinder := func(sheetID int) (*api.Sheet, error) {
 				sheetFind := &api.SheetFind{
 					ID: &sheetID,
@@ -258,9 +276,9 @@ func aclMiddleware(s *Server, pathPrefix string, ce *casbin.Enforcer, next echo.
 			}
 
 			if strings.HasPrefix(path, "/project") {
-				aclErr = enforceWorkspaceDeveloperProjectRouteACL(s.licenseService.GetEffectivePlan(), path, method, c.QueryParams(), principalID, roleFinder)
+				aclErr = enforceWorkspaceDeveloperProjectRouteACL(s.licenseService.GetEffectivePlan(), path, method, c.QueryParams(), principalID, roleFinder, customRoleFinder)
 			} else if strings.HasPrefix(path, "/sheet") {
-				aclErr = enforceWorkspaceDeveloperSheetRouteACL(s.licenseService.GetEffectivePlan(), path, method, principalID, roleFinder, sheetFinder)
+				aclErr = enforceWorkspaceDeveloperSheetRouteACL(s.licenseService.GetEffectivePlan(), path, method, principalID, roleFinder, customRoleFinder, sheetFinder)
 			}
 
 			if aclErr != nil {
@@ -268,8 +286,9 @@ func aclMiddleware(s *Server, pathPrefix string, ce *casbin.Enforcer, next echo.
 			}
 		}
 
-		// Stores role into context.
+		// Stores role and its permissions into context.
 		c.Set(getRoleContextKey(), role)
+		c.Set(getPermissionListContextKey(), permissionList)
 
 		return next(c)
 	}
@@ -279,7 +298,7 @@ func isOperatingSelf(ctx context.Context, c echo.Context, s *Server, curPrincipa
 	switch method {
 	case http.MethodGet:
 		return isGettingSelf(ctx, c, s, curPrincipalID, path)
-	case http.MethodPatch, http.MethodDelete:
+	case http.MethodPost, http.MethodPatch, http.MethodDelete:
 		return isUpdatingSelf(ctx, c, s, curPrincipalID, path)
 	default:
 		return false, nil
@@ -287,7 +306,12 @@ func isOperatingSelf(ctx context.Context, c echo.Context, s *Server, curPrincipa
 }
 
 func isGettingSelf(_ context.Context, c echo.Context, _ *Server, curPrincipalID int, path string) (bool, error) {
-	if strings.HasPrefix(path, "/inbox/user") {
+	if strings.HasPrefix(path, "/principal") {
+		pathPrincipalID := c.Param("principalID")
+		if pathPrincipalID != "" {
+			return pathPrincipalID == strconv.Itoa(curPrincipalID), nil
+		}
+	} else if strings.HasPrefix(path, "/inbox/user") {
 		userID, err := strconv.Atoi(c.Param("userID"))
 		if err != nil {
 			return false, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("User ID is not a number: %s", c.Param("userID"))).SetInternal(err)
@@ -331,6 +355,10 @@ func isUpdatingSelf(ctx context.Context, c echo.Context, s *Server, curPrincipal
 			return activity.CreatorID == curPrincipalID, nil
 		}
 	} else if strings.HasPrefix(path, "/bookmark") {
+		// The bookmark is always created for the current principal.
+		if path == "/bookmark" {
+			return true, nil
+		}
 		if bookmarkIDStr := c.Param("bookmarkID"); bookmarkIDStr != "" {
 			bookmarkID, err := strconv.Atoi(bookmarkIDStr)
 			if err != nil {
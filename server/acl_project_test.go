@@ -271,11 +271,35 @@ func TestEnforceWorkspaceDeveloperProjectRouteACL(t *testing.T) {
 			principalID: testFindPrincipalIDFromProject(100, ""),
 			errMsg:      "is not a member of the project",
 		},
+		{
+			desc:        "POST member to a single project as a custom role with the permission",
+			plan:        api.ENTERPRISE,
+			path:        "/project/102/member",
+			method:      "POST",
+			principalID: testFindPrincipalIDFromProject(102, testCustomRole),
+			errMsg:      "",
+		},
+		{
+			desc:        "PATCH a single project as a custom role without the permission",
+			plan:        api.ENTERPRISE,
+			path:        "/project/102",
+			method:      "PATCH",
+			principalID: testFindPrincipalIDFromProject(102, testCustomRole),
+			errMsg:      "not have permission to manage the project general setting",
+		},
+		{
+			desc:        "Sync sheet for a single project as a custom role without the permission",
+			plan:        api.ENTERPRISE,
+			path:        "/project/102/sync-sheet",
+			method:      "POST",
+			principalID: testFindPrincipalIDFromProject(102, testCustomRole),
+			errMsg:      "not have permission to sync sheet for project",
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.desc, func(t *testing.T) {
-			err := enforceWorkspaceDeveloperProjectRouteACL(tc.plan, tc.path, tc.method, tc.queryParams, tc.principalID, roleFinder)
+			err := enforceWorkspaceDeveloperProjectRouteACL(tc.plan, tc.path, tc.method, tc.queryParams, tc.principalID, roleFinder, customRoleFinder)
 			if err != nil {
 				if tc.errMsg == "" {
 					t.Errorf("expect no error, got %s", err.Message)
@@ -0,0 +1,251 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+// routeACL is the access control of an API route.
+type routeACL struct {
+	method string
+	// pattern is the route path, where the "{xxx}" segment matches any non-empty segment.
+	pattern string
+	// permission is the permission required to access the route.
+	// The route can only be accessed by the members operating on themselves if empty.
+	permission api.Permission
+	// self allows the members without the permission to access the route when they are operating on themselves,
+	// e.g. updating their own profile.
+	self bool
+}
+
+// routeACLList is the list of the access control of all API routes behind the ACL middleware.
+// The requests not matching any route are rejected.
+var routeACLList = []routeACL{
+	{"GET", "/activity", api.PermissionActivityGet, false},
+	{"POST", "/activity", api.PermissionActivityCreate, false},
+	{"PATCH", "/activity/{activityID}", "", true},
+
+	{"GET", "/anomaly", api.PermissionAnomalyGet, false},
+
+	{"POST", "/bookmark", "", true},
+	{"GET", "/bookmark/user/{userID}", "", true},
+	{"DELETE", "/bookmark/{bookmarkID}", "", true},
+
+	{"GET", "/database", api.PermissionDatabaseGet, false},
+	{"GET", "/database/{databaseID}", api.PermissionDatabaseGet, false},
+	{"PATCH", "/database/{databaseID}", api.PermissionDatabaseUpdate, false},
+	{"GET", "/database/{databaseID}/backup", api.PermissionDatabaseGet, false},
+	{"POST", "/database/{databaseID}/backup", api.PermissionDatabaseBackup, false},
+	{"GET", "/database/{databaseID}/backup-setting", api.PermissionDatabaseGet, false},
+	{"PATCH", "/database/{databaseID}/backup-setting", api.PermissionDatabaseUpdate, false},
+	{"POST", "/database/{databaseID}/data-source", api.PermissionDatabaseUpdate, false},
+	{"GET", "/database/{databaseID}/data-source/{dataSourceID}", api.PermissionDatabaseGet, false},
+	{"PATCH", "/database/{databaseID}/data-source/{dataSourceID}", api.PermissionDatabaseUpdate, false},
+	{"DELETE", "/database/{databaseID}/data-source/{dataSourceID}", api.PermissionDatabaseUpdate, false},
+	// Generating the DDL statements from the schema editor doesn't change the database.
+	{"POST", "/database/{databaseID}/edit", api.PermissionDatabaseGet, false},
+	{"GET", "/database/{databaseID}/extension", api.PermissionDatabaseGet, false},
+	{"GET", "/database/{databaseID}/schema", api.PermissionDatabaseGet, false},
+	{"GET", "/database/{databaseID}/table", api.PermissionDatabaseGet, false},
+	{"GET", "/database/{databaseID}/table/{tableName}", api.PermissionDatabaseGet, false},
+	{"GET", "/database/{databaseID}/view", api.PermissionDatabaseGet, false},
+
+	{"GET", "/debug", api.PermissionDebugGet, false},
+	{"PATCH", "/debug", api.PermissionDebugManage, false},
+	{"GET", "/debug/log", api.PermissionDebugGet, false},
+
+	{"GET", "/environment", api.PermissionEnvironmentGet, false},
+	{"POST", "/environment", api.PermissionEnvironmentCreate, false},
+	{"GET", "/environment/{environmentID}", api.PermissionEnvironmentGet, false},
+	// It also matches PATCH /environment/reorder.
+	{"PATCH", "/environment/{environmentID}", api.PermissionEnvironmentUpdate, false},
+	{"DELETE", "/environment/{environmentID}", api.PermissionEnvironmentDelete, false},
+	{"PATCH", "/environment/{environmentID}/backup-setting", api.PermissionEnvironmentUpdate, false},
+
+	{"GET", "/inbox/user/{userID}", "", true},
+	{"GET", "/inbox/user/{userID}/summary", "", true},
+	{"PATCH", "/inbox/{inboxID}", "", true},
+
+	{"GET", "/instance", api.PermissionInstanceGet, false},
+	{"POST", "/instance", api.PermissionInstanceCreate, false},
+	{"POST", "/instance/new-embedded-pg", api.PermissionInstanceCreateEmbedded, false},
+	{"GET", "/instance/{instanceID}", api.PermissionInstanceGet, false},
+	{"PATCH", "/instance/{instanceID}", api.PermissionInstanceUpdate, false},
+	{"DELETE", "/instance/{instanceID}", api.PermissionInstanceDelete, false},
+	{"POST", "/instance/{instanceID}/migration", api.PermissionInstanceUpdate, false},
+	{"GET", "/instance/{instanceID}/migration/history", api.PermissionInstanceGet, false},
+	{"GET", "/instance/{instanceID}/migration/history/{historyID}", api.PermissionInstanceGet, false},
+	{"GET", "/instance/{instanceID}/migration/status", api.PermissionInstanceGet, false},
+	{"GET", "/instance/{instanceID}/user", api.PermissionInstanceGet, false},
+	{"GET", "/instance/{instanceID}/user/{userID}", api.PermissionInstanceGet, false},
+
+	{"GET", "/issue", api.PermissionIssueGet, false},
+	{"POST", "/issue", api.PermissionIssueCreate, false},
+	{"GET", "/issue/{issueID}", api.PermissionIssueGet, false},
+	{"PATCH", "/issue/{issueID}", api.PermissionIssueUpdate, false},
+	{"PATCH", "/issue/{issueID}/status", api.PermissionIssueUpdate, false},
+	{"GET", "/issue/{issueID}/subscriber", api.PermissionIssueGet, false},
+	{"POST", "/issue/{issueID}/subscriber", api.PermissionIssueUpdate, false},
+	{"DELETE", "/issue/{issueID}/subscriber/{subscriberID}", api.PermissionIssueUpdate, false},
+
+	{"GET", "/label", api.PermissionLabelGet, false},
+	{"PATCH", "/label/{labelID}", api.PermissionLabelUpdate, false},
+
+	{"POST", "/mail-delivery/test", api.PermissionSettingUpdate, false},
+
+	{"GET", "/member", api.PermissionMemberGet, false},
+	{"POST", "/member", api.PermissionMemberManage, false},
+	{"PATCH", "/member/{memberID}", api.PermissionMemberManage, false},
+
+	// The task status changes are further checked against the approval policy by the handlers.
+	{"PATCH", "/pipeline/{pipelineID}/stage/{stageID}/status", api.PermissionIssueUpdate, false},
+	{"PATCH", "/pipeline/{pipelineID}/task/all", api.PermissionIssueUpdate, false},
+	{"PATCH", "/pipeline/{pipelineID}/task/{taskID}", api.PermissionIssueUpdate, false},
+	{"POST", "/pipeline/{pipelineID}/task/{taskID}/check", api.PermissionIssueUpdate, false},
+	{"PATCH", "/pipeline/{pipelineID}/task/{taskID}/status", api.PermissionIssueUpdate, false},
+
+	{"GET", "/policy", api.PermissionPolicyGet, false},
+	{"GET", "/policy/{resourceType}/{resourceID}", api.PermissionPolicyGet, false},
+	{"PATCH", "/policy/{resourceType}/{resourceID}", api.PermissionPolicyUpdate, false},
+	{"DELETE", "/policy/{resourceType}/{resourceID}", api.PermissionPolicyUpdate, false},
+
+	{"GET", "/principal", api.PermissionPrincipalGet, false},
+	{"POST", "/principal", api.PermissionPrincipalCreate, false},
+	{"GET", "/principal/{principalID}", api.PermissionPrincipalGet, false},
+	{"PATCH", "/principal/{principalID}", api.PermissionPrincipalUpdate, true},
+	{"GET", "/principal/{principalID}/access-token", api.PermissionPrincipalUpdate, true},
+	{"POST", "/principal/{principalID}/access-token", api.PermissionPrincipalUpdate, true},
+	{"DELETE", "/principal/{principalID}/access-token/{accessTokenID}", api.PermissionPrincipalUpdate, true},
+	{"GET", "/principal/{principalID}/mfa", api.PermissionPrincipalUpdate, true},
+	{"DELETE", "/principal/{principalID}/mfa", api.PermissionPrincipalUpdate, true},
+	{"POST", "/principal/{principalID}/mfa/recovery-code", "", true},
+	{"GET", "/principal/{principalID}/notification-preference", "", true},
+	{"PATCH", "/principal/{principalID}/notification-preference", "", true},
+
+	// The project routes are further checked against the project role if the member is not a project admin.
+	{"GET", "/project", api.PermissionProjectGet, false},
+	{"POST", "/project", api.PermissionProjectCreate, false},
+	{"GET", "/project/{projectID}", api.PermissionProjectGet, false},
+	{"PATCH", "/project/{projectID}", api.PermissionProjectUpdate, false},
+	{"GET", "/project/{projectID}/deployment", api.PermissionProjectGet, false},
+	{"PATCH", "/project/{projectID}/deployment", api.PermissionProjectUpdate, false},
+	{"POST", "/project/{projectID}/member", api.PermissionProjectUpdate, false},
+	{"PATCH", "/project/{projectID}/member/{memberID}", api.PermissionProjectUpdate, false},
+	{"DELETE", "/project/{projectID}/member/{memberID}", api.PermissionProjectUpdate, false},
+	{"GET", "/project/{projectID}/repository", api.PermissionProjectGet, false},
+	{"POST", "/project/{projectID}/repository", api.PermissionProjectUpdate, false},
+	{"PATCH", "/project/{projectID}/repository", api.PermissionProjectUpdate, false},
+	{"DELETE", "/project/{projectID}/repository", api.PermissionProjectUpdate, false},
+	{"POST", "/project/{projectID}/repository/{repositoryID}/import", api.PermissionProjectUpdate, false},
+	{"POST", "/project/{projectID}/repository/{repositoryID}/sql-review-ci", api.PermissionProjectUpdate, false},
+	{"GET", "/project/{projectID}/repository/{repositoryID}/webhook-delivery", api.PermissionProjectGet, false},
+	{"POST", "/project/{projectID}/sync-member", api.PermissionProjectUpdate, false},
+	{"POST", "/project/{projectID}/sync-sheet", api.PermissionProjectUpdate, false},
+	{"GET", "/project/{projectID}/webhook", api.PermissionProjectGet, false},
+	{"POST", "/project/{projectID}/webhook", api.PermissionProjectUpdate, false},
+	{"GET", "/project/{projectID}/webhook/{webhookID}", api.PermissionProjectGet, false},
+	{"PATCH", "/project/{projectID}/webhook/{webhookID}", api.PermissionProjectUpdate, false},
+	{"DELETE", "/project/{projectID}/webhook/{webhookID}", api.PermissionProjectUpdate, false},
+	{"GET", "/project/{projectID}/webhook/{webhookID}/delivery", api.PermissionProjectGet, false},
+	{"POST", "/project/{projectID}/webhook/{webhookID}/delivery/{deliveryID}/redeliver", api.PermissionProjectUpdate, false},
+	{"GET", "/project/{projectID}/webhook/{webhookID}/test", api.PermissionProjectGet, false},
+
+	{"GET", "/role", api.PermissionRoleGet, false},
+	{"POST", "/role", api.PermissionRoleManage, false},
+	{"PATCH", "/role/{roleID}", api.PermissionRoleManage, false},
+	{"DELETE", "/role/{roleID}", api.PermissionRoleManage, false},
+
+	{"GET", "/setting", api.PermissionSettingGet, false},
+	{"PATCH", "/setting/{name}", api.PermissionSettingUpdate, false},
+
+	// The sheet routes are further checked against the sheet visibility if the member is not a project admin.
+	{"POST", "/sheet", api.PermissionSheetCreate, false},
+	{"GET", "/sheet/my", api.PermissionSheetGet, false},
+	{"GET", "/sheet/shared", api.PermissionSheetGet, false},
+	{"GET", "/sheet/starred", api.PermissionSheetGet, false},
+	{"GET", "/sheet/{sheetID}", api.PermissionSheetGet, false},
+	{"PATCH", "/sheet/{sheetID}", api.PermissionSheetUpdate, false},
+	{"DELETE", "/sheet/{sheetID}", api.PermissionSheetDelete, false},
+	{"PATCH", "/sheet/{sheetID}/organizer", api.PermissionSheetUpdate, false},
+
+	{"POST", "/sql/execute", api.PermissionDatabaseQuery, false},
+	{"POST", "/sql/execute/admin", api.PermissionDatabaseAdminQuery, false},
+	{"POST", "/sql/ping", api.PermissionDatabaseQuery, false},
+	{"POST", "/sql/sync-schema", api.PermissionInstanceUpdate, false},
+
+	{"PATCH", "/subscription", api.PermissionSubscriptionUpdate, false},
+	{"POST", "/subscription/trial", api.PermissionSubscriptionTrial, false},
+
+	{"GET", "/vcs", api.PermissionVCSGet, false},
+	{"POST", "/vcs", api.PermissionVCSManage, false},
+	{"GET", "/vcs/{vcsID}", api.PermissionVCSGet, false},
+	{"PATCH", "/vcs/{vcsID}", api.PermissionVCSManage, false},
+	{"DELETE", "/vcs/{vcsID}", api.PermissionVCSManage, false},
+	{"GET", "/vcs/{vcsID}/external-repository", api.PermissionVCSGet, false},
+	{"GET", "/vcs/{vcsID}/repository", api.PermissionVCSGetRepository, false},
+
+	{"GET", "/workspace/webhook", api.PermissionWorkspaceWebhookManage, false},
+	{"POST", "/workspace/webhook", api.PermissionWorkspaceWebhookManage, false},
+	{"GET", "/workspace/webhook/{webhookID}", api.PermissionWorkspaceWebhookManage, false},
+	{"PATCH", "/workspace/webhook/{webhookID}", api.PermissionWorkspaceWebhookManage, false},
+	{"DELETE", "/workspace/webhook/{webhookID}", api.PermissionWorkspaceWebhookManage, false},
+	{"GET", "/workspace/webhook/{webhookID}/delivery", api.PermissionWorkspaceWebhookManage, false},
+	{"POST", "/workspace/webhook/{webhookID}/delivery/{deliveryID}/redeliver", api.PermissionWorkspaceWebhookManage, false},
+	{"GET", "/workspace/webhook/{webhookID}/test", api.PermissionWorkspaceWebhookManage, false},
+}
+
+// findRouteACL returns the access control of the route matching the request, and nil if no route matches.
+// The route with the most literal segments wins, e.g. "/instance/new-embedded-pg" over "/instance/{instanceID}".
+func findRouteACL(method string, path string) *routeACL {
+	var matched *routeACL
+	matchedLiteralCount := -1
+	for i, acl := range routeACLList {
+		if acl.method != method {
+			continue
+		}
+		if literalCount, ok := matchRoutePattern(acl.pattern, path); ok && literalCount > matchedLiteralCount {
+			matched, matchedLiteralCount = &routeACLList[i], literalCount
+		}
+	}
+	return matched
+}
+
+// matchRoutePattern returns whether the path matches the route pattern, along with the number of literal segments matched.
+func matchRoutePattern(pattern string, path string) (int, bool) {
+	patternSegmentList := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegmentList := strings.Split(strings.Trim(path, "/"), "/")
+	if len(patternSegmentList) != len(pathSegmentList) {
+		return 0, false
+	}
+	literalCount := 0
+	for i, segment := range patternSegmentList {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			if pathSegmentList[i] == "" {
+				return 0, false
+			}
+			continue
+		}
+		if segment != pathSegmentList[i] {
+			return 0, false
+		}
+		literalCount++
+	}
+	return literalCount, true
+}
+
+// hasRoutePermission returns whether the permission list grants the access to the route.
+// The isSelf function is only called for the routes allowing the members to operate on themselves.
+func hasRoutePermission(acl *routeACL, permissionList []api.Permission, isSelf func() (bool, error)) (bool, error) {
+	if acl.permission != "" {
+		for _, permission := range permissionList {
+			if permission == acl.permission {
+				return true, nil
+			}
+		}
+	}
+	if acl.self {
+		return isSelf()
+	}
+	return false, nil
+}
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/server/component/rbac"
+)
+
+func TestFindRouteACL(t *testing.T) {
+	tests := []struct {
+		method  string
+		path    string
+		pattern string
+	}{
+		{"GET", "/database/101", "/database/{databaseID}"},
+		{"GET", "/database/101/table/employee", "/database/{databaseID}/table/{tableName}"},
+		{"POST", "/instance/new-embedded-pg", "/instance/new-embedded-pg"},
+		{"PATCH", "/instance/101", "/instance/{instanceID}"},
+		{"PATCH", "/environment/reorder", "/environment/{environmentID}"},
+		{"GET", "/sheet/my", "/sheet/my"},
+		{"GET", "/sheet/101", "/sheet/{sheetID}"},
+		{"POST", "/sql/execute/admin", "/sql/execute/admin"},
+		{"DELETE", "/principal/101/access-token/102", "/principal/{principalID}/access-token/{accessTokenID}"},
+		// No route matches.
+		{"POST", "/database/101", ""},
+		{"GET", "/database//table", ""},
+		{"GET", "/unknown", ""},
+	}
+
+	for _, test := range tests {
+		acl := findRouteACL(test.method, test.path)
+		if test.pattern == "" {
+			require.Nil(t, acl, "%s %s", test.method, test.path)
+			continue
+		}
+		require.NotNil(t, acl, "%s %s", test.method, test.path)
+		require.Equal(t, test.pattern, acl.pattern, "%s %s", test.method, test.path)
+	}
+}
+
+func TestHasRoutePermission(t *testing.T) {
+	tests := []struct {
+		role   api.Role
+		method string
+		path   string
+		isSelf bool
+		want   bool
+	}{
+		{api.Owner, "PATCH", "/setting/bb.branding.logo", false, true},
+		{api.DBA, "PATCH", "/setting/bb.branding.logo", false, false},
+		{api.Developer, "PATCH", "/setting/bb.branding.logo", false, false},
+		{api.Owner, "POST", "/instance", false, true},
+		{api.DBA, "POST", "/instance", false, true},
+		{api.Developer, "POST", "/instance", false, false},
+		{api.DBA, "POST", "/instance/new-embedded-pg", false, false},
+		{api.Developer, "POST", "/sql/execute", false, true},
+		{api.Developer, "POST", "/sql/execute/admin", false, false},
+		{api.Developer, "GET", "/vcs/101/repository", false, false},
+		{api.Owner, "POST", "/role", false, true},
+		{api.DBA, "POST", "/role", false, false},
+		{api.Developer, "GET", "/role", false, true},
+		// The members without the permission can still update themselves.
+		{api.Owner, "PATCH", "/principal/101", false, true},
+		{api.DBA, "PATCH", "/principal/101", false, false},
+		{api.DBA, "PATCH", "/principal/101", true, true},
+		{api.Developer, "DELETE", "/principal/101/mfa", true, true},
+		// The routes only allowed for the members operating on themselves.
+		{api.Owner, "GET", "/inbox/user/101", false, false},
+		{api.Owner, "GET", "/inbox/user/101", true, true},
+		{api.Owner, "POST", "/principal/101/mfa/recovery-code", false, false},
+		{api.Developer, "PATCH", "/activity/101", true, true},
+	}
+
+	for _, test := range tests {
+		acl := findRouteACL(test.method, test.path)
+		require.NotNil(t, acl, "%s %s", test.method, test.path)
+		got, err := hasRoutePermission(acl, api.BuiltInRolePermissionList(test.role), func() (bool, error) {
+			return test.isSelf, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, test.want, got, "%s %s %s self=%v", test.role, test.method, test.path, test.isSelf)
+	}
+}
+
+func TestRouteACLPermission(t *testing.T) {
+	for _, acl := range routeACLList {
+		// Every route must be accessible by a permission, or by the members operating on themselves.
+		require.True(t, acl.permission != "" || acl.self, "%s %s", acl.method, acl.pattern)
+		if acl.permission != "" {
+			require.True(t, api.IsValidPermission(string(acl.permission)), "%s %s", acl.method, acl.pattern)
+			// The owner has all permissions.
+			require.True(t, rbac.HasPermission(api.BuiltInRolePermissionList(api.Owner), acl.permission), "%s %s", acl.method, acl.pattern)
+		}
+	}
+}
+
+// TestRouteACLCasbinPolicyParity checks that the routes allowed by the casbin policies replaced by the route ACL
+// are still allowed for the built-in roles.
+func TestRouteACLCasbinPolicyParity(t *testing.T) {
+	for _, name := range []string{"owner", "dba", "developer"} {
+		file, err := os.Open(filepath.Join("testdata", fmt.Sprintf("acl_casbin_policy_%s.csv", name)))
+		require.NoError(t, err)
+		recordList, err := csv.NewReader(file).ReadAll()
+		require.NoError(t, file.Close())
+		require.NoError(t, err)
+
+		for _, record := range recordList {
+			require.Len(t, record, 4)
+			role, path, method := api.Role(strings.TrimSpace(record[1])), strings.TrimSpace(record[2]), strings.TrimSpace(record[3])
+			// The XXX_SELF action is only allowed when the member is operating on itself.
+			isSelf := strings.HasSuffix(method, "_SELF")
+			method = strings.TrimSuffix(method, "_SELF")
+			// The ACL middleware skips GET /subscription.
+			if method == "GET" && path == "/subscription" {
+				continue
+			}
+
+			acl := findRouteACL(method, path)
+			if acl == nil {
+				t.Errorf("%s %s %s matches no route", role, method, path)
+				continue
+			}
+			// The routes without a permission, e.g. the bookmarks and the inbox, are narrowed down to the members
+			// operating on themselves, which are the only ones using them.
+			if acl.permission == "" {
+				isSelf = true
+			}
+			got, err := hasRoutePermission(acl, api.BuiltInRolePermissionList(role), func() (bool, error) {
+				return isSelf, nil
+			})
+			require.NoError(t, err)
+			if !got {
+				t.Errorf("%s %s %s self=%v is denied", role, method, path, isSelf)
+			}
+		}
+	}
+}
@@ -14,8 +14,14 @@ var testProjectMemberMap = map[int]map[common.ProjectRole]int{
 	101: {
 		common.ProjectOwner: 202,
 	},
+	102: {
+		testCustomRole: 203,
+	},
 }
 
+// testCustomRole is a custom role allowed to manage the project members but not the general project settings.
+const testCustomRole common.ProjectRole = "RELEASE_MANAGER"
+
 // map from sheet ID to the project ID.
 var testSheetProjectMap = map[int]int{
 	1000: 100,
@@ -83,6 +89,17 @@ var roleFinder = func(projectID int, principalID int) (common.ProjectRole, error
 	return "", nil
 }
 
+var customRoleFinder = func(role string) (*api.CustomRole, error) {
+	if role == string(testCustomRole) {
+		return &api.CustomRole{
+			Role:           api.Role(testCustomRole),
+			Name:           "Release Manager",
+			PermissionList: []string{string(api.PermissionIssueGet), string(api.ProjectPermissionManageMember)},
+		}, nil
+	}
+	return nil, nil
+}
+
 var sheetFinder = func(sheetID int) (*api.Sheet, error) {
 	switch sheetID {
 	case 1000:
@@ -61,22 +61,17 @@ func (s *Server) registerAuthRoutes(g *echo.Group) {
 					return echo.NewHTTPError(http.StatusBadRequest, "Malformed login request").SetInternal(err)
 				}
 
-				var err error
-				user, err = s.store.GetPrincipalByEmail(ctx, login.Email)
-				if err != nil {
-					return echo.NewHTTPError(http.StatusInternalServerError, "Failed to authenticate user").SetInternal(err)
+				var httpError *echo.HTTPError
+				user, httpError = s.authenticatePassword(ctx, login.Email, login.Password)
+				if httpError != nil {
+					return httpError
 				}
-				if user == nil {
-					return echo.NewHTTPError(http.StatusUnauthorized, fmt.Sprintf("User not found: %s", login.Email))
-				}
-
-				// Compare the stored hashed password, with the hashed version of the password that was received.
-				if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(login.Password)); err != nil {
-					// If the two passwords don't match, return a 401 status.
-					return echo.NewHTTPError(http.StatusUnauthorized, "Incorrect password").SetInternal(err)
+				// The tokens are only issued after the second factor is verified.
+				if httpError := s.verifyMFA(ctx, user, login.OTPCode, login.RecoveryCode); httpError != nil {
+					return httpError
 				}
 			}
-		case api.PrincipalAuthProviderGitlabSelfHost, api.PrincipalAuthProviderGitHubCom:
+		case api.PrincipalAuthProviderGitlabSelfHost, api.PrincipalAuthProviderGitHubCom, api.PrincipalAuthProviderGitHubEnterprise, api.PrincipalAuthProviderBitbucketCloud, api.PrincipalAuthProviderBitbucketDataCenter, api.PrincipalAuthProviderAzureDevOps:
 			{
 				login := &api.VCSLogin{}
 				if err := jsonapi.UnmarshalPayload(c.Request().Body, login); err != nil {
@@ -158,6 +153,18 @@ func (s *Server) registerAuthRoutes(g *echo.Group) {
 					}
 				}
 			}
+		case api.PrincipalAuthProviderOIDC:
+			var httpError *echo.HTTPError
+			user, httpError = s.loginWithOIDC(c)
+			if httpError != nil {
+				return httpError
+			}
+		case api.PrincipalAuthProviderLDAP:
+			var httpError *echo.HTTPError
+			user, httpError = s.loginWithLDAP(c)
+			if httpError != nil {
+				return httpError
+			}
 		default:
 			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unsupported auth provider: %s", authProvider))
 		}
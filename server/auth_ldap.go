@@ -0,0 +1,89 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/server/component/sso"
+	"github.com/bytebase/bytebase/server/runner/ldapsync"
+)
+
+func (s *Server) registerAuthLDAPRoutes(g *echo.Group) {
+	g.GET("/auth/ldap", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		value, err := ldapsync.GetAuthLDAPSetting(ctx, s.store)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get LDAP setting").SetInternal(err)
+		}
+		if value == nil || !value.Enabled {
+			return echo.NewHTTPError(http.StatusNotFound, "LDAP authentication is not enabled")
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, &api.LDAPProvider{Name: value.Name}); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal LDAP provider response").SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// loginWithLDAP logs in the user via LDAP, the user is provisioned on the first login if allowed.
+// The principal is linked to the directory entry, whose workspace role and project memberships are synced from the groups.
+func (s *Server) loginWithLDAP(c echo.Context) (*api.Principal, *echo.HTTPError) {
+	ctx := c.Request().Context()
+	login := &api.LDAPLogin{}
+	if err := jsonapi.UnmarshalPayload(c.Request().Body, login); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Malformed login request").SetInternal(err)
+	}
+	value, err := ldapsync.GetAuthLDAPSetting(ctx, s.store)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to get LDAP setting").SetInternal(err)
+	}
+	if value == nil || !value.Enabled {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "LDAP authentication is not enabled")
+	}
+
+	userInfo, err := ldapsync.GetProvider(value).Login(login.Username, login.Password)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, "Incorrect LDAP username or password").SetInternal(err)
+	}
+
+	user, err := s.store.GetPrincipalByEmail(ctx, userInfo.Email)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to authenticate user").SetInternal(err)
+	}
+	if user == nil {
+		if !value.AutoProvision {
+			return nil, echo.NewHTTPError(http.StatusUnauthorized, fmt.Sprintf("User not found: %s, please contact the workspace owner", userInfo.Email))
+		}
+		// Like the VCS login, the random password is not guessable. If the user wants to login via password,
+		// they need to set the new password from the profile page.
+		password, err := common.RandomString(20)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate random password").SetInternal(err)
+		}
+		var httpError *echo.HTTPError
+		user, httpError = trySignUp(ctx, s, &api.SignUp{
+			Email:    userInfo.Email,
+			Password: password,
+			Name:     userInfo.Name,
+		}, api.SystemBotID)
+		if httpError != nil {
+			return nil, httpError
+		}
+		if _, hasGroupRole := sso.GetRoleFromGroupList(userInfo.GroupList, value.GroupRoleMapping); !hasGroupRole && value.DefaultRole != "" {
+			if err := sso.SyncMemberRole(ctx, s.store, s.ActivityManager, user, value.DefaultRole); err != nil {
+				return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to sync member role").SetInternal(err)
+			}
+		}
+	}
+	if err := ldapsync.SyncUser(ctx, s.store, s.ActivityManager, value, user, userInfo); err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to sync LDAP user").SetInternal(err)
+	}
+	return user, nil
+}
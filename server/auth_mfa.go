@@ -0,0 +1,403 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/mfa/totp"
+)
+
+const (
+	// mfaIssuer is the issuer displayed in the authenticator app.
+	mfaIssuer = "Bytebase"
+	// recoveryCodeCount is the number of the recovery codes generated each time.
+	recoveryCodeCount = 10
+	// recoveryCodeLength is the length of each recovery code, excluding the separator.
+	recoveryCodeLength = 10
+
+	// maxMFAFailedAttemptCount is the number of the consecutive failed verifications before the verification is locked.
+	maxMFAFailedAttemptCount = 5
+	// mfaLockDuration is the duration the verification is locked for after too many failed attempts.
+	mfaLockDuration = 15 * time.Minute
+
+	// The client prompts for the code or the enrollment by the following messages of the login response.
+	mfaCodeRequiredMessage       = "Two-factor authentication code is required"
+	mfaEnrollmentRequiredMessage = "Two-factor authentication is required for your role, please enroll first"
+)
+
+func (s *Server) registerAuthMFARoutes(g *echo.Group) {
+	// The enrollment is authenticated by the password rather than the access token, so that the users required to
+	// enable the two-factor authentication can enroll before the first login.
+	g.POST("/auth/mfa/enroll", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		enrollmentCreate := &api.MFAEnrollmentCreate{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, enrollmentCreate); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create two-factor authentication enrollment request").SetInternal(err)
+		}
+		user, httpErr := s.authenticatePassword(ctx, enrollmentCreate.Email, enrollmentCreate.Password)
+		if httpErr != nil {
+			return httpErr
+		}
+		if user.Type != api.EndUser {
+			return echo.NewHTTPError(http.StatusBadRequest, "Two-factor authentication is only available to end users")
+		}
+		principalMFA, err := s.store.GetPrincipalMFA(ctx, user.ID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get two-factor authentication").SetInternal(err)
+		}
+		// Otherwise, anyone with the password could replace the authenticator.
+		if principalMFA != nil && principalMFA.Enabled {
+			return echo.NewHTTPError(http.StatusBadRequest, "Two-factor authentication is already enabled")
+		}
+
+		secret, err := totp.GenerateSecret()
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate two-factor authentication secret").SetInternal(err)
+		}
+		if _, err := s.store.UpsertPrincipalMFA(ctx, &api.PrincipalMFAUpsert{
+			PrincipalID:   user.ID,
+			TempOTPSecret: secret,
+		}); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create two-factor authentication enrollment").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, &api.MFAEnrollment{
+			OTPSecret:  secret,
+			OTPAuthURL: totp.GetKeyURI(mfaIssuer, user.Email, secret),
+		}); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal two-factor authentication enrollment response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.POST("/auth/mfa/verify", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		verify := &api.MFAVerify{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, verify); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed verify two-factor authentication request").SetInternal(err)
+		}
+		user, httpErr := s.authenticatePassword(ctx, verify.Email, verify.Password)
+		if httpErr != nil {
+			return httpErr
+		}
+		principalMFA, err := s.store.GetPrincipalMFA(ctx, user.ID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get two-factor authentication").SetInternal(err)
+		}
+		if principalMFA == nil || principalMFA.TempOTPSecret == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Two-factor authentication enrollment not found, please enroll first")
+		}
+		if principalMFA.Enabled {
+			return echo.NewHTTPError(http.StatusBadRequest, "Two-factor authentication is already enabled")
+		}
+		if !totp.Validate(verify.OTPCode, principalMFA.TempOTPSecret, time.Now()) {
+			return echo.NewHTTPError(http.StatusUnauthorized, "Invalid two-factor authentication code")
+		}
+
+		recoveryCodeList, recoveryCodeHashList, err := generateRecoveryCodes()
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate recovery codes").SetInternal(err)
+		}
+		principalMFA, err = s.store.UpsertPrincipalMFA(ctx, &api.PrincipalMFAUpsert{
+			PrincipalID:          user.ID,
+			OTPSecret:            principalMFA.TempOTPSecret,
+			RecoveryCodeHashList: recoveryCodeHashList,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to enable two-factor authentication").SetInternal(err)
+		}
+		// Only return the recovery codes for the first time after they're generated.
+		principalMFA.RecoveryCodeList = recoveryCodeList
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, principalMFA); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal two-factor authentication response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.GET("/principal/:principalID/mfa", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("principalID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("principalID"))).SetInternal(err)
+		}
+
+		principalMFA, err := s.store.GetPrincipalMFA(ctx, id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch two-factor authentication of principal ID: %v", id)).SetInternal(err)
+		}
+		if principalMFA == nil {
+			principalMFA = &api.PrincipalMFA{PrincipalID: id}
+		}
+		principalMFA.RecoveryCodeList = []string{}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, principalMFA); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal two-factor authentication response: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.POST("/principal/:principalID/mfa/recovery-code", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("principalID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("principalID"))).SetInternal(err)
+		}
+		recoveryCodeCreate := &api.MFARecoveryCodeCreate{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, recoveryCodeCreate); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create recovery code request").SetInternal(err)
+		}
+
+		principalMFA, err := s.store.GetPrincipalMFA(ctx, id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch two-factor authentication of principal ID: %v", id)).SetInternal(err)
+		}
+		if principalMFA == nil || !principalMFA.Enabled {
+			return echo.NewHTTPError(http.StatusBadRequest, "Two-factor authentication is not enabled")
+		}
+		if httpErr := s.verifyMFACode(ctx, principalMFA, recoveryCodeCreate.OTPCode, ""); httpErr != nil {
+			return httpErr
+		}
+
+		recoveryCodeList, recoveryCodeHashList, err := generateRecoveryCodes()
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate recovery codes").SetInternal(err)
+		}
+		principalMFA, err = s.store.UpsertPrincipalMFA(ctx, &api.PrincipalMFAUpsert{
+			PrincipalID:          id,
+			OTPSecret:            principalMFA.OTPSecret,
+			RecoveryCodeHashList: recoveryCodeHashList,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to regenerate recovery codes").SetInternal(err)
+		}
+		principalMFA.RecoveryCodeList = recoveryCodeList
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, principalMFA); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal two-factor authentication response: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	// The workspace owner can disable the two-factor authentication of others, e.g. when the user loses the authenticator.
+	// The users disabling their own two-factor authentication need to provide the TOTP code or a recovery code,
+	// so that a hijacked session can't remove the second factor.
+	g.DELETE("/principal/:principalID/mfa", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("principalID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("principalID"))).SetInternal(err)
+		}
+
+		if id == c.Get(getPrincipalIDContextKey()).(int) {
+			principalMFA, err := s.store.GetPrincipalMFA(ctx, id)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch two-factor authentication of principal ID: %v", id)).SetInternal(err)
+			}
+			if principalMFA != nil && principalMFA.Enabled {
+				mfaDelete := &api.PrincipalMFADelete{}
+				if err := jsonapi.UnmarshalPayload(c.Request().Body, mfaDelete); err != nil {
+					return echo.NewHTTPError(http.StatusBadRequest, "Malformed disable two-factor authentication request").SetInternal(err)
+				}
+				if httpErr := s.verifyMFACode(ctx, principalMFA, mfaDelete.OTPCode, mfaDelete.RecoveryCode); httpErr != nil {
+					return httpErr
+				}
+			}
+
+			member, err := s.store.GetMemberByPrincipalID(ctx, id)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch member of principal ID: %v", id)).SetInternal(err)
+			}
+			if member != nil {
+				required, err := s.isMFARequired(ctx, member.Role)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get two-factor authentication setting").SetInternal(err)
+				}
+				if required {
+					return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Two-factor authentication is required for the %s role", member.Role))
+				}
+			}
+		}
+
+		if _, err := s.store.UpsertPrincipalMFA(ctx, &api.PrincipalMFAUpsert{PrincipalID: id}); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to disable two-factor authentication of principal ID: %v", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		c.Response().WriteHeader(http.StatusOK)
+		return nil
+	})
+}
+
+// authenticatePassword returns the user if the password matches.
+func (s *Server) authenticatePassword(ctx context.Context, email string, password string) (*api.Principal, *echo.HTTPError) {
+	user, err := s.store.GetPrincipalByEmail(ctx, email)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to authenticate user").SetInternal(err)
+	}
+	if user == nil {
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, fmt.Sprintf("User not found: %s", email))
+	}
+
+	// Compare the stored hashed password, with the hashed version of the password that was received.
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		// If the two passwords don't match, return a 401 status.
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, "Incorrect password").SetInternal(err)
+	}
+	return user, nil
+}
+
+// verifyMFA verifies the second factor of the password login. The user logs in with either the TOTP code or
+// an unused recovery code if the two-factor authentication is enabled, and can't log in without enrollment
+// if the workspace requires it for the user's role.
+func (s *Server) verifyMFA(ctx context.Context, user *api.Principal, otpCode string, recoveryCode string) *echo.HTTPError {
+	principalMFA, err := s.store.GetPrincipalMFA(ctx, user.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get two-factor authentication").SetInternal(err)
+	}
+	if principalMFA == nil || !principalMFA.Enabled {
+		required, err := s.isMFARequiredForUser(ctx, user)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get two-factor authentication setting").SetInternal(err)
+		}
+		if required {
+			return echo.NewHTTPError(http.StatusUnauthorized, mfaEnrollmentRequiredMessage)
+		}
+		return nil
+	}
+	return s.verifyMFACode(ctx, principalMFA, otpCode, recoveryCode)
+}
+
+// verifyMFACode verifies either the TOTP code or an unused recovery code of the enabled two-factor authentication.
+// The TOTP code of the used time step is rejected to prevent the replay, and the verification is locked for
+// mfaLockDuration after maxMFAFailedAttemptCount consecutive failures to prevent brute-forcing the code.
+func (s *Server) verifyMFACode(ctx context.Context, principalMFA *api.PrincipalMFA, otpCode string, recoveryCode string) *echo.HTTPError {
+	now := time.Now()
+	if principalMFA.LockedUntilTs > now.Unix() {
+		return echo.NewHTTPError(http.StatusTooManyRequests, "Too many failed two-factor authentication attempts, please try again later")
+	}
+
+	verified := false
+	failedMessage := ""
+	switch {
+	case otpCode != "":
+		if counter, ok := totp.ValidateCounter(otpCode, principalMFA.OTPSecret, now, principalMFA.LastOTPCounter); ok {
+			consumed, err := s.store.ConsumePrincipalMFAOTPCounter(ctx, principalMFA.PrincipalID, counter)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to verify two-factor authentication code").SetInternal(err)
+			}
+			verified = consumed
+		}
+		failedMessage = "Invalid two-factor authentication code"
+	case recoveryCode != "":
+		consumed, err := s.store.ConsumePrincipalMFARecoveryCode(ctx, principalMFA.PrincipalID, hashRecoveryCode(recoveryCode))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to verify recovery code").SetInternal(err)
+		}
+		verified = consumed
+		failedMessage = "Invalid recovery code"
+	default:
+		return echo.NewHTTPError(http.StatusUnauthorized, mfaCodeRequiredMessage)
+	}
+
+	if !verified {
+		if err := s.store.RecordPrincipalMFAFailedAttempt(ctx, principalMFA.PrincipalID, maxMFAFailedAttemptCount, now.Add(mfaLockDuration).Unix()); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to record two-factor authentication attempt").SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusUnauthorized, failedMessage)
+	}
+	if principalMFA.FailedAttemptCount > 0 {
+		if err := s.store.ResetPrincipalMFAFailedAttempt(ctx, principalMFA.PrincipalID); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to reset two-factor authentication attempts").SetInternal(err)
+		}
+	}
+	return nil
+}
+
+// isMFARequiredForUser returns true if the workspace requires the two-factor authentication for the role of the end user.
+func (s *Server) isMFARequiredForUser(ctx context.Context, user *api.Principal) (bool, error) {
+	if user.Type != api.EndUser {
+		return false, nil
+	}
+	member, err := s.store.GetMemberByPrincipalID(ctx, user.ID)
+	if err != nil {
+		return false, err
+	}
+	if member == nil {
+		return false, nil
+	}
+	return s.isMFARequired(ctx, member.Role)
+}
+
+// isMFARequired returns true if the workspace requires the two-factor authentication for the role.
+func (s *Server) isMFARequired(ctx context.Context, role api.Role) (bool, error) {
+	value, err := s.getAuthMFASetting(ctx)
+	if err != nil {
+		return false, err
+	}
+	if value == nil {
+		return false, nil
+	}
+	for _, requiredRole := range value.RequiredRoleList {
+		if requiredRole == role {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// getAuthMFASetting returns the two-factor authentication setting value, or nil if it's not configured.
+func (s *Server) getAuthMFASetting(ctx context.Context) (*api.SettingAuthMFAValue, error) {
+	settingName := api.SettingAuthMFA
+	setting, err := s.store.GetSetting(ctx, &api.SettingFind{Name: &settingName})
+	if err != nil {
+		return nil, err
+	}
+	if setting == nil || setting.Value == "" {
+		return nil, nil
+	}
+	var value api.SettingAuthMFAValue
+	if err := json.Unmarshal([]byte(setting.Value), &value); err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// generateRecoveryCodes returns the recovery codes in the format of xxxxx-xxxxx and their hashes.
+func generateRecoveryCodes() ([]string, []string, error) {
+	var recoveryCodeList, recoveryCodeHashList []string
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := common.RandomString(recoveryCodeLength)
+		if err != nil {
+			return nil, nil, err
+		}
+		code = fmt.Sprintf("%s-%s", code[:recoveryCodeLength/2], code[recoveryCodeLength/2:])
+		recoveryCodeList = append(recoveryCodeList, code)
+		recoveryCodeHashList = append(recoveryCodeHashList, hashRecoveryCode(code))
+	}
+	return recoveryCodeList, recoveryCodeHashList, nil
+}
+
+// hashRecoveryCode returns the hex encoded SHA-256 hash of the recovery code, the separator and spaces are ignored.
+func hashRecoveryCode(code string) string {
+	code = strings.NewReplacer("-", "", " ", "").Replace(code)
+	h := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/mfa/totp"
+)
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	a := require.New(t)
+	recoveryCodeList, recoveryCodeHashList, err := generateRecoveryCodes()
+	a.NoError(err)
+	a.Len(recoveryCodeList, recoveryCodeCount)
+	a.Len(recoveryCodeHashList, recoveryCodeCount)
+	for i, code := range recoveryCodeList {
+		a.Len(code, recoveryCodeLength+1)
+		a.Equal(5, strings.Index(code, "-"))
+		a.Equal(recoveryCodeHashList[i], hashRecoveryCode(code))
+		// The separator and spaces are ignored.
+		a.Equal(recoveryCodeHashList[i], hashRecoveryCode(" "+strings.ReplaceAll(code, "-", "")+" "))
+	}
+	a.NotEqual(hashRecoveryCode("abcde-fghij"), hashRecoveryCode("ABCDE-FGHIJ"))
+}
+
+func TestVerifyMFACodeLocked(t *testing.T) {
+	a := require.New(t)
+	s := &Server{}
+	principalMFA := &api.PrincipalMFA{
+		PrincipalID:   101,
+		OTPSecret:     "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ",
+		LockedUntilTs: time.Now().Add(mfaLockDuration).Unix(),
+	}
+	code, err := totp.GenerateCode(principalMFA.OTPSecret, time.Now())
+	a.NoError(err)
+	// Even the valid code is rejected while the verification is locked.
+	httpErr := s.verifyMFACode(context.Background(), principalMFA, code, "")
+	a.NotNil(httpErr)
+	a.Equal(http.StatusTooManyRequests, httpErr.Code)
+
+	principalMFA.LockedUntilTs = time.Now().Add(-time.Second).Unix()
+	httpErr = s.verifyMFACode(context.Background(), principalMFA, "", "")
+	a.NotNil(httpErr)
+	a.Equal(http.StatusUnauthorized, httpErr.Code)
+	a.Equal(mfaCodeRequiredMessage, httpErr.Message)
+}
@@ -0,0 +1,239 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/idp/oidc"
+	"github.com/bytebase/bytebase/server/component/sso"
+)
+
+const (
+	// oidcSessionCookieName is the cookie keeping the nonce and the PKCE code verifier between the authorization and the login.
+	oidcSessionCookieName = "oidc-session"
+	oidcSessionAudience   = "bb.oidc.session"
+	oidcSessionDuration   = 10 * time.Minute
+)
+
+// oidcSessionClaims is signed with the server secret so that the client cannot forge the nonce and code verifier.
+type oidcSessionClaims struct {
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"codeVerifier"`
+	jwt.RegisteredClaims
+}
+
+func (s *Server) registerAuthOIDCRoutes(g *echo.Group) {
+	g.GET("/auth/oidc", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		value, err := s.getAuthOIDCSetting(ctx)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get OIDC setting").SetInternal(err)
+		}
+		if value == nil || !value.Enabled {
+			return echo.NewHTTPError(http.StatusNotFound, "OIDC single sign-on is not enabled")
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, &api.OIDCProvider{Name: value.Name}); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal OIDC provider response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.POST("/auth/oidc/authorize", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		create := &api.OIDCAuthorizationCreate{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, create); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed OIDC authorization request").SetInternal(err)
+		}
+		if create.State == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "State cannot be empty")
+		}
+		value, err := s.getAuthOIDCSetting(ctx)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get OIDC setting").SetInternal(err)
+		}
+		if value == nil || !value.Enabled {
+			return echo.NewHTTPError(http.StatusNotFound, "OIDC single sign-on is not enabled")
+		}
+
+		nonce, err := oidc.GenerateNonce()
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate nonce").SetInternal(err)
+		}
+		codeVerifier, codeChallenge, err := oidc.GeneratePKCE()
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate PKCE code challenge").SetInternal(err)
+		}
+		authorizeURL, err := getOIDCProvider(value).GetAuthorizeURL(ctx, s.getOIDCRedirectURL(), create.State, nonce, codeChallenge)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Failed to discover the OpenID provider").SetInternal(err)
+		}
+
+		expiration := time.Now().Add(oidcSessionDuration)
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, &oidcSessionClaims{
+			Nonce:        nonce,
+			CodeVerifier: codeVerifier,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Audience:  jwt.ClaimStrings{oidcSessionAudience},
+				ExpiresAt: jwt.NewNumericDate(expiration),
+				IssuedAt:  jwt.NewNumericDate(time.Now()),
+				Issuer:    issuer,
+			},
+		})
+		token.Header["kid"] = keyID
+		session, err := token.SignedString([]byte(s.secret))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to sign OIDC session").SetInternal(err)
+		}
+		setOIDCSessionCookie(c, session, expiration)
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, &api.OIDCAuthorization{AuthorizeURL: authorizeURL}); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal OIDC authorization response").SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// loginWithOIDC logs in the user via OpenID Connect, the user is provisioned on the first login if allowed,
+// and the workspace role is synced from the groups claim.
+func (s *Server) loginWithOIDC(c echo.Context) (*api.Principal, *echo.HTTPError) {
+	ctx := c.Request().Context()
+	login := &api.OIDCLogin{}
+	if err := jsonapi.UnmarshalPayload(c.Request().Body, login); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Malformed login request").SetInternal(err)
+	}
+	value, err := s.getAuthOIDCSetting(ctx)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to get OIDC setting").SetInternal(err)
+	}
+	if value == nil || !value.Enabled {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "OIDC single sign-on is not enabled")
+	}
+
+	cookie, err := c.Cookie(oidcSessionCookieName)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, "Missing OIDC session, please sign in again")
+	}
+	removeOIDCSessionCookie(c)
+	claims := &oidcSessionClaims{}
+	if _, err := jwt.ParseWithClaims(cookie.Value, claims, func(t *jwt.Token) (interface{}, error) {
+		if t.Method.Alg() != jwt.SigningMethodHS256.Name {
+			return nil, errors.Errorf("unexpected OIDC session signing method=%v, expect %v", t.Header["alg"], jwt.SigningMethodHS256)
+		}
+		return []byte(s.secret), nil
+	}); err != nil {
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, "Invalid OIDC session, please sign in again").SetInternal(err)
+	}
+	if !audienceContains(claims.Audience, oidcSessionAudience) {
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, "Invalid OIDC session audience, please sign in again")
+	}
+
+	userInfo, err := getOIDCProvider(value).Login(ctx, s.getOIDCRedirectURL(), login.Code, claims.CodeVerifier, claims.Nonce)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, "Failed to login via OIDC").SetInternal(err)
+	}
+
+	user, err := s.store.GetPrincipalByEmail(ctx, userInfo.Email)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to authenticate user").SetInternal(err)
+	}
+	role, hasGroupRole := sso.GetRoleFromGroupList(userInfo.GroupList, value.GroupRoleMapping)
+	if user == nil {
+		if !value.AutoProvision {
+			return nil, echo.NewHTTPError(http.StatusUnauthorized, fmt.Sprintf("User not found: %s, please contact the workspace owner", userInfo.Email))
+		}
+		// Like the VCS login, the random password is not guessable. If the user wants to login via password,
+		// they need to set the new password from the profile page.
+		password, err := common.RandomString(20)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate random password").SetInternal(err)
+		}
+		var httpError *echo.HTTPError
+		user, httpError = trySignUp(ctx, s, &api.SignUp{
+			Email:    userInfo.Email,
+			Password: password,
+			Name:     userInfo.Name,
+		}, api.SystemBotID)
+		if httpError != nil {
+			return nil, httpError
+		}
+		if !hasGroupRole && value.DefaultRole != "" {
+			role, hasGroupRole = value.DefaultRole, true
+		}
+	}
+	if hasGroupRole {
+		if err := sso.SyncMemberRole(ctx, s.store, s.ActivityManager, user, role); err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to sync member role").SetInternal(err)
+		}
+	}
+	return user, nil
+}
+
+// getAuthOIDCSetting returns the OIDC setting value, or nil if it's not configured.
+func (s *Server) getAuthOIDCSetting(ctx context.Context) (*api.SettingAuthOIDCValue, error) {
+	settingName := api.SettingAuthOIDC
+	setting, err := s.store.GetSetting(ctx, &api.SettingFind{Name: &settingName})
+	if err != nil {
+		return nil, err
+	}
+	if setting == nil || setting.Value == "" {
+		return nil, nil
+	}
+	var value api.SettingAuthOIDCValue
+	if err := json.Unmarshal([]byte(setting.Value), &value); err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// getOIDCRedirectURL returns the redirect URI, which reuses the OAuth callback page of the VCS login.
+func (s *Server) getOIDCRedirectURL() string {
+	return fmt.Sprintf("%s/oauth/callback", s.profile.ExternalURL)
+}
+
+func getOIDCProvider(value *api.SettingAuthOIDCValue) *oidc.Provider {
+	return oidc.NewProvider(oidc.Config{
+		IssuerURL:    value.IssuerURL,
+		ClientID:     value.ClientID,
+		ClientSecret: value.ClientSecret,
+		Scopes:       value.Scopes,
+		EmailClaim:   value.EmailClaim,
+		NameClaim:    value.NameClaim,
+		GroupsClaim:  value.GroupsClaim,
+	})
+}
+
+// The session cookie uses the Lax mode, because the login follows the cross-site redirection from the OpenID provider.
+func setOIDCSessionCookie(c echo.Context, session string, expiration time.Time) {
+	cookie := new(http.Cookie)
+	cookie.Name = oidcSessionCookieName
+	cookie.Value = session
+	cookie.Expires = expiration
+	cookie.Path = "/"
+	cookie.HttpOnly = true
+	cookie.SameSite = http.SameSiteLaxMode
+	c.SetCookie(cookie)
+}
+
+func removeOIDCSessionCookie(c echo.Context) {
+	cookie := new(http.Cookie)
+	cookie.Name = oidcSessionCookieName
+	cookie.Value = ""
+	cookie.Expires = time.Unix(0, 0)
+	cookie.Path = "/"
+	cookie.HttpOnly = true
+	cookie.SameSite = http.SameSiteLaxMode
+	c.SetCookie(cookie)
+}
@@ -5,11 +5,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/common/log"
 	"github.com/bytebase/bytebase/plugin/webhook"
 	"github.com/bytebase/bytebase/server/component/config"
@@ -71,6 +74,12 @@ func (m *Manager) BatchCreateTaskStatusUpdateApprovalActivity(ctx context.Contex
 		return errors.Errorf("failed to create any activity")
 	}
 	anyActivity := activityList[0]
+	for _, activity := range activityList {
+		m.postWorkspaceWebhook(ctx, activity, &Metadata{Issue: issue})
+	}
+
+	// Post the status back to VCS in Go routine to avoid blocking web serving thread.
+	go m.postVCSStatusList(ctx, []vcsStatusEvent{{issue: issue, stageID: stage.ID, newStatus: api.TaskPending}})
 
 	activityType := api.ActivityPipelineTaskStatusUpdate
 	webhookList, err := m.store.FindProjectWebhook(ctx, &api.ProjectWebhookFind{
@@ -105,10 +114,17 @@ func (m *Manager) BatchCreateTaskStatusUpdateApprovalActivity(ctx context.Contex
 		CreatorName:  anyActivity.Creator.Name,
 		CreatorEmail: anyActivity.Creator.Email,
 	}
-	// Call external webhook endpoint in Go routine to avoid blocking web serving thread.
-	go postWebhookList(webhookCtx, webhookList)
-
-	return nil
+	webhookCtx.Stage = getWebhookStage(stage)
+	webhookCtx.Approver = &webhook.Principal{
+		ID:    anyActivity.CreatorID,
+		Name:  anyActivity.Creator.Name,
+		Email: anyActivity.Creator.Email,
+	}
+	if len(taskList) == 1 {
+		webhookCtx.Task = getWebhookTask(taskList[0])
+	}
+	// The webhook deliveries are posted by the webhook runner to avoid blocking web serving thread.
+	return m.createWebhookDeliveryList(ctx, webhookCtx, webhookList)
 }
 
 // CreateActivity creates an activity.
@@ -118,9 +134,16 @@ func (m *Manager) CreateActivity(ctx context.Context, create *api.ActivityCreate
 		return nil, err
 	}
 
+	// The workspace webhooks receive the activities of the whole workspace, not only the ones on the issues.
+	m.postWorkspaceWebhook(ctx, activity, meta)
+
 	if meta.Issue == nil {
 		return activity, nil
 	}
+	if eventList := getVCSStatusEventList(activity, meta.Issue); len(eventList) > 0 {
+		// Post the status back to VCS in Go routine to avoid blocking web serving thread.
+		go m.postVCSStatusList(ctx, eventList)
+	}
 	postInbox, err := shouldPostInbox(activity, create.Type)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to post webhook event after changing the issue task status: %s", meta.Issue.Name)
@@ -130,6 +153,9 @@ func (m *Manager) CreateActivity(ctx context.Context, create *api.ActivityCreate
 			return nil, err
 		}
 	}
+	if err := m.postEmailIssueActivity(ctx, meta.Issue, activity, postInbox); err != nil {
+		return nil, err
+	}
 
 	hookFind := &api.ProjectWebhookFind{
 		ProjectID:    &meta.Issue.ProjectID,
@@ -158,42 +184,175 @@ func (m *Manager) CreateActivity(ctx context.Context, create *api.ActivityCreate
 			zap.Error(err))
 		return activity, nil
 	}
-	// Call external webhook endpoint in Go routine to avoid blocking web serving thread.
-	go postWebhookList(webhookCtx, webhookList)
+	// The webhook deliveries are posted by the webhook runner to avoid blocking web serving thread.
+	if err := m.createWebhookDeliveryList(ctx, webhookCtx, webhookList); err != nil {
+		return nil, errors.Wrapf(err, "failed to create webhook deliveries after changing the issue status: %v", meta.Issue.Name)
+	}
 
 	return activity, nil
 }
 
-func postWebhookList(webhookCtx webhook.Context, webhookList []*api.ProjectWebhook) {
+// createWebhookDeliveryList persists the webhook deliveries, so that they are retried on failure and survive the server restarts.
+func (m *Manager) createWebhookDeliveryList(ctx context.Context, webhookCtx webhook.Context, webhookList []*api.ProjectWebhook) error {
+	webhookCtx.CreatedTs = time.Now().Unix()
+	payload, err := json.Marshal(webhookCtx)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal webhook context")
+	}
 	for _, hook := range webhookList {
-		webhookCtx.URL = hook.URL
-		webhookCtx.CreatedTs = time.Now().Unix()
-		const maxRetries = 3
-		for retries := 0; retries < maxRetries; retries++ {
-			if err := webhook.Post(hook.Type, webhookCtx); err != nil {
-				// The external webhook endpoint might be invalid which is out of our code control, so we just emit a warning
-				log.Warn("Failed to post webhook event on activity",
-					zap.String("webhook type", hook.Type),
-					zap.String("webhook name", hook.Name),
-					zap.String("activity type", webhookCtx.ActivityType),
-					zap.String("title", webhookCtx.Title),
-					zap.Error(err))
-			} else {
-				break
+		if _, err := m.store.CreateProjectWebhookDelivery(ctx, &api.ProjectWebhookDeliveryCreate{
+			ProjectWebhookID: hook.ID,
+			ActivityType:     api.ActivityType(webhookCtx.ActivityType),
+			Payload:          string(payload),
+		}); err != nil {
+			return errors.Wrapf(err, "failed to create delivery for webhook %q", hook.Name)
+		}
+	}
+	return nil
+}
+
+// postWorkspaceWebhook creates the deliveries of the workspace webhooks receiving the activity.
+// The failure is only logged, because the activity has been created.
+func (m *Manager) postWorkspaceWebhook(ctx context.Context, activity *api.Activity, meta *Metadata) {
+	webhookList, err := m.store.FindWorkspaceWebhook(ctx, &api.WorkspaceWebhookFind{
+		ActivityType: &activity.Type,
+		Level:        &activity.Level,
+	})
+	if err != nil {
+		log.Warn("Failed to find workspace webhook for activity",
+			zap.Int("activity_id", activity.ID),
+			zap.Error(err))
+		return
+	}
+	if len(webhookList) == 0 {
+		return
+	}
+
+	webhookCtx := m.getWorkspaceWebhookContext(activity, meta)
+	webhookCtx.CreatedTs = time.Now().Unix()
+	payload, err := json.Marshal(webhookCtx)
+	if err != nil {
+		log.Warn("Failed to marshal workspace webhook context",
+			zap.Int("activity_id", activity.ID),
+			zap.Error(err))
+		return
+	}
+	for _, hook := range webhookList {
+		if _, err := m.store.CreateWorkspaceWebhookDelivery(ctx, &api.WorkspaceWebhookDeliveryCreate{
+			WorkspaceWebhookID: hook.ID,
+			ActivityType:       activity.Type,
+			Payload:            string(payload),
+		}); err != nil {
+			log.Warn("Failed to create workspace webhook delivery",
+				zap.Int("activity_id", activity.ID),
+				zap.String("webhook", hook.Name),
+				zap.Error(err))
+		}
+	}
+}
+
+// getWorkspaceWebhookContext returns the workspace webhook context of the activity.
+// Unlike the project webhooks, the activity payload is sent as is, since the activity may not belong to an issue.
+func (m *Manager) getWorkspaceWebhookContext(activity *api.Activity, meta *Metadata) webhook.Context {
+	level := webhook.WebhookInfo
+	switch activity.Level {
+	case api.ActivityWarn:
+		level = webhook.WebhookWarn
+	case api.ActivityError:
+		level = webhook.WebhookError
+	}
+	webhookCtx := webhook.Context{
+		Level:           level,
+		ActivityType:    string(activity.Type),
+		Title:           getWorkspaceWebhookTitle(activity.Type),
+		Description:     activity.Comment,
+		Link:            m.profile.ExternalURL,
+		CreatorID:       activity.CreatorID,
+		ActivityPayload: activity.Payload,
+	}
+	if activity.Creator != nil {
+		webhookCtx.CreatorName = activity.Creator.Name
+		webhookCtx.CreatorEmail = activity.Creator.Email
+	}
+	if meta.Issue != nil {
+		webhookCtx.Title = fmt.Sprintf("%s - %s", webhookCtx.Title, meta.Issue.Name)
+		webhookCtx.Link = fmt.Sprintf("%s/issue/%s", m.profile.ExternalURL, api.IssueSlug(meta.Issue))
+		webhookCtx.Issue = &webhook.Issue{
+			ID:          meta.Issue.ID,
+			Name:        meta.Issue.Name,
+			Status:      string(meta.Issue.Status),
+			Type:        string(meta.Issue.Type),
+			Description: meta.Issue.Description,
+		}
+		if meta.Issue.Project != nil {
+			webhookCtx.Project = &webhook.Project{
+				ID:   meta.Issue.ProjectID,
+				Name: meta.Issue.Project.Name,
 			}
 		}
 	}
+	return webhookCtx
+}
+
+// getWorkspaceWebhookTitle returns the title of the workspace webhook for the activity type.
+func getWorkspaceWebhookTitle(activityType api.ActivityType) string {
+	switch activityType {
+	case api.ActivityIssueCreate:
+		return "Issue created"
+	case api.ActivityIssueCommentCreate:
+		return "Comment created"
+	case api.ActivityIssueFieldUpdate:
+		return "Issue updated"
+	case api.ActivityIssueStatusUpdate:
+		return "Issue status changed"
+	case api.ActivityPipelineTaskStatusUpdate:
+		return "Task status changed"
+	case api.ActivityMemberCreate:
+		return "Member joined"
+	case api.ActivityMemberRoleUpdate:
+		return "Member role changed"
+	case api.ActivityMemberActivate:
+		return "Member activated"
+	case api.ActivityMemberDeactivate:
+		return "Member deactivated"
+	case api.ActivityProjectMemberCreate:
+		return "Project member added"
+	case api.ActivityProjectMemberDelete:
+		return "Project member removed"
+	case api.ActivityProjectMemberRoleUpdate:
+		return "Project member role changed"
+	case api.ActivitySQLEditorQuery:
+		return "SQL query executed"
+	case api.ActivityInstanceCreate:
+		return "Instance created"
+	case api.ActivityInstanceUpdate:
+		return "Instance updated"
+	case api.ActivityEnvironmentCreate:
+		return "Environment created"
+	case api.ActivityEnvironmentUpdate:
+		return "Environment updated"
+	case api.ActivityPolicyUpdate:
+		return "Policy updated"
+	case api.ActivityAnomalyCreate:
+		return "Anomaly detected"
+	}
+	return string(activityType)
 }
 
 func (m *Manager) getWebhookContext(ctx context.Context, activity *api.Activity, meta *Metadata, updater *api.Principal) (webhook.Context, error) {
 	var webhookCtx webhook.Context
 	var webhookTaskResult *webhook.TaskResult
+	var webhookTask *webhook.Task
+	var webhookStage *webhook.Stage
+	var webhookApprover *webhook.Principal
+	var webhookPendingApproval *webhook.PendingApproval
 	level := webhook.WebhookInfo
 	title := ""
 	link := fmt.Sprintf("%s/issue/%s", m.profile.ExternalURL, api.IssueSlug(meta.Issue))
 	switch activity.Type {
 	case api.ActivityIssueCreate:
 		title = fmt.Sprintf("Issue created - %s", meta.Issue.Name)
+		webhookPendingApproval = getWebhookPendingApproval(getActiveStagePendingApproval(meta.Issue.Pipeline, 0 /* doneTaskID */))
 	case api.ActivityIssueStatusUpdate:
 		switch meta.Issue.Status {
 		case "OPEN":
@@ -318,6 +477,17 @@ func (m *Manager) getWebhookContext(ctx context.Context, activity *api.Activity,
 			Name:   task.Name,
 			Status: string(task.Status),
 		}
+		webhookTask = getWebhookTask(task)
+		var taskStage *api.Stage
+		if meta.Issue.Pipeline != nil {
+			for _, stage := range meta.Issue.Pipeline.StageList {
+				if stage.ID == task.StageID {
+					taskStage = stage
+					webhookStage = getWebhookStage(stage)
+					break
+				}
+			}
+		}
 
 		title = "Task changed - " + task.Name
 		switch update.NewStatus {
@@ -327,12 +497,23 @@ func (m *Manager) getWebhookContext(ctx context.Context, activity *api.Activity,
 				title = "Task canceled - " + task.Name
 			case api.TaskPendingApproval:
 				title = "Task approved - " + task.Name
+				webhookApprover = &webhook.Principal{
+					ID:    updater.ID,
+					Name:  updater.Name,
+					Email: updater.Email,
+				}
+			}
+		case api.TaskPendingApproval:
+			if taskStage != nil {
+				webhookPendingApproval = getWebhookPendingApproval(taskStage)
 			}
 		case api.TaskRunning:
 			title = "Task started - " + task.Name
 		case api.TaskDone:
 			level = webhook.WebhookSuccess
 			title = "Task completed - " + task.Name
+			// The tasks of the next stage are waiting for approval after the last task of the current stage is done.
+			webhookPendingApproval = getWebhookPendingApproval(getActiveStagePendingApproval(meta.Issue.Pipeline, task.ID))
 		case api.TaskFailed:
 			level = webhook.WebhookError
 			title = "Task failed - " + task.Name
@@ -377,16 +558,75 @@ func (m *Manager) getWebhookContext(ctx context.Context, activity *api.Activity,
 			ID:   meta.Issue.ProjectID,
 			Name: meta.Issue.Project.Name,
 		},
-		TaskResult:   webhookTaskResult,
-		Description:  activity.Comment,
-		Link:         link,
-		CreatorID:    updater.ID,
-		CreatorName:  updater.Name,
-		CreatorEmail: updater.Email,
+		TaskResult:      webhookTaskResult,
+		Stage:           webhookStage,
+		Task:            webhookTask,
+		Approver:        webhookApprover,
+		PendingApproval: webhookPendingApproval,
+		Description:     activity.Comment,
+		Link:            link,
+		CreatorID:       updater.ID,
+		CreatorName:     updater.Name,
+		CreatorEmail:    updater.Email,
 	}
 	return webhookCtx, nil
 }
 
+// getWebhookStage returns the webhook stage of the stage.
+func getWebhookStage(stage *api.Stage) *webhook.Stage {
+	webhookStage := &webhook.Stage{
+		ID:            stage.ID,
+		Name:          stage.Name,
+		EnvironmentID: stage.EnvironmentID,
+	}
+	if stage.Environment != nil {
+		webhookStage.EnvironmentName = stage.Environment.Name
+	}
+	return webhookStage
+}
+
+// getWebhookPendingApproval returns the webhook pending approval of the stage, or nil if the stage is nil.
+func getWebhookPendingApproval(stage *api.Stage) *webhook.PendingApproval {
+	if stage == nil {
+		return nil
+	}
+	return &webhook.PendingApproval{
+		StageID:   stage.ID,
+		StageName: stage.Name,
+	}
+}
+
+// getWebhookTask returns the webhook task of the task, the statement and migration version are extracted from the task payload.
+func getWebhookTask(task *api.Task) *webhook.Task {
+	webhookTask := &webhook.Task{
+		ID:     task.ID,
+		Name:   task.Name,
+		Type:   string(task.Type),
+		Status: string(task.Status),
+	}
+	if task.Instance != nil {
+		webhookTask.InstanceName = task.Instance.Name
+	}
+	if task.Database != nil {
+		webhookTask.DatabaseName = task.Database.Name
+	}
+	if task.Payload == "" {
+		return webhookTask
+	}
+	// The statement and schemaVersion share the same JSON keys in the payloads of the task types applying statements.
+	var payload struct {
+		Statement     string `json:"statement"`
+		SchemaVersion string `json:"schemaVersion"`
+	}
+	if err := json.Unmarshal([]byte(task.Payload), &payload); err != nil {
+		log.Warn("Failed to unmarshal task payload for webhook", zap.Int("task_id", task.ID), zap.Error(err))
+		return webhookTask
+	}
+	webhookTask.Statement = common.TruncateStringWithDescription(payload.Statement)
+	webhookTask.MigrationVersion = payload.SchemaVersion
+	return webhookTask
+}
+
 func (m *Manager) postInboxIssueActivity(ctx context.Context, issue *api.Issue, activityID int) error {
 	if issue.CreatorID != api.SystemBotID {
 		inboxCreate := &api.InboxCreate{
@@ -423,6 +663,204 @@ func (m *Manager) postInboxIssueActivity(ctx context.Context, issue *api.Issue,
 	return nil
 }
 
+// postEmailIssueActivity creates the email notifications of the issue activity, which are sent by the mail runner.
+// It's a no-op if the mail delivery is not enabled.
+func (m *Manager) postEmailIssueActivity(ctx context.Context, issue *api.Issue, activity *api.Activity, postInbox bool) error {
+	settingName := api.SettingMailDelivery
+	setting, err := m.store.GetSetting(ctx, &api.SettingFind{Name: &settingName})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get setting %q", settingName)
+	}
+	if setting == nil || setting.Value == "" {
+		return nil
+	}
+	var value api.SettingMailDeliveryValue
+	if err := json.Unmarshal([]byte(setting.Value), &value); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal setting %q", settingName)
+	}
+	if !value.Enabled {
+		return nil
+	}
+
+	var mentionedIDList []int
+	if activity.Type == api.ActivityIssueCommentCreate {
+		for _, email := range getMentionedEmailList(activity.Comment) {
+			principal, err := m.store.GetPrincipalByEmail(ctx, email)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get mentioned principal %q", email)
+			}
+			if principal != nil {
+				mentionedIDList = append(mentionedIDList, principal.ID)
+			}
+		}
+	}
+	recipientMap, err := getEmailRecipientMap(activity, issue, postInbox, mentionedIDList)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get email recipients of activity %d", activity.ID)
+	}
+	if len(recipientMap) == 0 {
+		return nil
+	}
+
+	link := fmt.Sprintf("%s/issue/%s", m.profile.ExternalURL, api.IssueSlug(issue))
+	if activity.Type == api.ActivityIssueCommentCreate {
+		link += fmt.Sprintf("#activity%d", activity.ID)
+	}
+	emailPayload := api.EmailNotificationPayload{
+		Title:       fmt.Sprintf("%s - %s", getWorkspaceWebhookTitle(activity.Type), issue.Name),
+		IssueName:   issue.Name,
+		Description: activity.Comment,
+		Link:        link,
+		CreatedTs:   activity.CreatedTs,
+	}
+	if issue.Project != nil {
+		emailPayload.ProjectName = issue.Project.Name
+	}
+	if activity.Creator != nil {
+		emailPayload.CreatorName = activity.Creator.Name
+	}
+	payload, err := json.Marshal(emailPayload)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal email notification payload")
+	}
+
+	// Sort the receivers to create the notifications in a stable order.
+	var receiverIDList []int
+	for receiverID := range recipientMap {
+		receiverIDList = append(receiverIDList, receiverID)
+	}
+	sort.Ints(receiverIDList)
+	for _, receiverID := range receiverIDList {
+		reason := recipientMap[receiverID]
+		preference, err := m.store.GetNotificationPreference(ctx, receiverID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get notification preference of principal %d", receiverID)
+		}
+		if !preference.IsEmailEnabled(reason) {
+			continue
+		}
+		if _, err := m.store.CreateEmailNotification(ctx, &api.EmailNotificationCreate{
+			ReceiverID: receiverID,
+			ActivityID: activity.ID,
+			Reason:     reason,
+			Payload:    string(payload),
+		}); err != nil {
+			return errors.Wrapf(err, "failed to create email notification to principal %d", receiverID)
+		}
+	}
+	return nil
+}
+
+// mentionRegexp matches the mentioned emails in the comments, e.g. "@alice@example.com".
+var mentionRegexp = regexp.MustCompile(`(?:^|\s)@([A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,})`)
+
+// getMentionedEmailList returns the distinct emails mentioned in the comment.
+func getMentionedEmailList(comment string) []string {
+	var emailList []string
+	seen := make(map[string]bool)
+	for _, match := range mentionRegexp.FindAllStringSubmatch(comment, -1) {
+		email := strings.ToLower(match[1])
+		if !seen[email] {
+			seen[email] = true
+			emailList = append(emailList, email)
+		}
+	}
+	return emailList
+}
+
+// emailReasonPriority is the priority of the notification reasons, a user only receives the notification once with the highest priority reason.
+var emailReasonPriority = map[api.NotificationReason]int{
+	api.NotificationReasonApprovalNeeded: 4,
+	api.NotificationReasonAssigned:       3,
+	api.NotificationReasonMentioned:      2,
+	api.NotificationReasonSubscribed:     1,
+}
+
+// getEmailRecipientMap returns the users receiving the email notification of the issue activity with the reasons.
+// The creator of the activity never receives the notification of their own activity.
+func getEmailRecipientMap(activity *api.Activity, issue *api.Issue, postInbox bool, mentionedIDList []int) (map[int]api.NotificationReason, error) {
+	recipientMap := make(map[int]api.NotificationReason)
+	add := func(principalID int, reason api.NotificationReason) {
+		if principalID == api.SystemBotID || principalID == activity.CreatorID {
+			return
+		}
+		if old, ok := recipientMap[principalID]; ok && emailReasonPriority[old] >= emailReasonPriority[reason] {
+			return
+		}
+		recipientMap[principalID] = reason
+	}
+
+	if postInbox {
+		add(issue.CreatorID, api.NotificationReasonSubscribed)
+		add(issue.AssigneeID, api.NotificationReasonSubscribed)
+		for _, subscriber := range issue.SubscriberList {
+			add(subscriber.ID, api.NotificationReasonSubscribed)
+		}
+	}
+	for _, principalID := range mentionedIDList {
+		add(principalID, api.NotificationReasonMentioned)
+	}
+
+	switch activity.Type {
+	case api.ActivityIssueCreate:
+		add(issue.AssigneeID, api.NotificationReasonAssigned)
+		if getActiveStagePendingApproval(issue.Pipeline, 0 /* doneTaskID */) != nil {
+			add(issue.AssigneeID, api.NotificationReasonApprovalNeeded)
+		}
+	case api.ActivityIssueFieldUpdate:
+		update := &api.ActivityIssueFieldUpdatePayload{}
+		if err := json.Unmarshal([]byte(activity.Payload), update); err != nil {
+			return nil, err
+		}
+		if update.FieldID == api.IssueFieldAssignee && update.NewValue != "" {
+			assigneeID, err := strconv.Atoi(update.NewValue)
+			if err != nil {
+				return nil, errors.Wrapf(err, "assignee id %q is not a number", update.NewValue)
+			}
+			add(assigneeID, api.NotificationReasonAssigned)
+		}
+	case api.ActivityPipelineTaskStatusUpdate:
+		update := &api.ActivityPipelineTaskStatusUpdatePayload{}
+		if err := json.Unmarshal([]byte(activity.Payload), update); err != nil {
+			return nil, err
+		}
+		switch update.NewStatus {
+		case api.TaskPendingApproval:
+			add(issue.AssigneeID, api.NotificationReasonApprovalNeeded)
+		case api.TaskDone:
+			// The tasks of the next stage are waiting for approval after the last task of the current stage is done.
+			if getActiveStagePendingApproval(issue.Pipeline, update.TaskID) != nil {
+				add(issue.AssigneeID, api.NotificationReasonApprovalNeeded)
+			}
+		}
+	}
+	return recipientMap, nil
+}
+
+// getActiveStagePendingApproval returns the active stage, which is the first stage with undone tasks, if it has tasks pending approval.
+// The task doneTaskID is regarded as done, because the pipeline may be fetched before the task is done.
+func getActiveStagePendingApproval(pipeline *api.Pipeline, doneTaskID int) *api.Stage {
+	if pipeline == nil {
+		return nil
+	}
+	for _, stage := range pipeline.StageList {
+		active := false
+		for _, task := range stage.TaskList {
+			if task.ID == doneTaskID || task.Status == api.TaskDone {
+				continue
+			}
+			active = true
+			if task.Status == api.TaskPendingApproval {
+				return stage
+			}
+		}
+		if active {
+			return nil
+		}
+	}
+	return nil
+}
+
 func shouldPostInbox(activity *api.Activity, createType api.ActivityType) (bool, error) {
 	switch createType {
 	case api.ActivityIssueCreate:
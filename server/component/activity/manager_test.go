@@ -0,0 +1,164 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/webhook"
+	"github.com/bytebase/bytebase/server/component/config"
+)
+
+func TestGetWebhookTask(t *testing.T) {
+	a := require.New(t)
+	task := &api.Task{
+		ID:       101,
+		Name:     "Update schema for database \"db\"",
+		Status:   api.TaskFailed,
+		Type:     api.TaskDatabaseSchemaUpdate,
+		Instance: &api.Instance{Name: "instance"},
+		Database: &api.Database{Name: "db"},
+		Payload:  `{"statement":"ALTER TABLE t ADD COLUMN c INT;","schemaVersion":"20221220000000"}`,
+	}
+	a.Equal(&webhook.Task{
+		ID:               101,
+		Name:             "Update schema for database \"db\"",
+		Type:             string(api.TaskDatabaseSchemaUpdate),
+		Status:           string(api.TaskFailed),
+		InstanceName:     "instance",
+		DatabaseName:     "db",
+		Statement:        "ALTER TABLE t ADD COLUMN c INT;",
+		MigrationVersion: "20221220000000",
+	}, getWebhookTask(task))
+
+	task.Payload = `{"statement":"` + strings.Repeat("x", 1000) + `"}`
+	got := getWebhookTask(task)
+	a.True(strings.HasSuffix(got.Statement, "... (view details in Bytebase)"))
+	a.Equal("", got.MigrationVersion)
+}
+
+func TestGetWebhookStage(t *testing.T) {
+	a := require.New(t)
+	stage := &api.Stage{
+		ID:            101,
+		Name:          "Prod Stage",
+		EnvironmentID: 102,
+		Environment:   &api.Environment{Name: "Prod"},
+	}
+	a.Equal(&webhook.Stage{
+		ID:              101,
+		Name:            "Prod Stage",
+		EnvironmentID:   102,
+		EnvironmentName: "Prod",
+	}, getWebhookStage(stage))
+}
+
+func TestGetWorkspaceWebhookContext(t *testing.T) {
+	a := require.New(t)
+	m := &Manager{profile: config.Profile{ExternalURL: "https://bytebase.example.com"}}
+	activity := &api.Activity{
+		CreatorID: 101,
+		Creator:   &api.Principal{ID: 101, Name: "alice", Email: "alice@example.com"},
+		Type:      api.ActivityAnomalyCreate,
+		Level:     api.ActivityWarn,
+		Payload:   `{"anomalyType":"bb.anomaly.database.backup.missing"}`,
+	}
+	a.Equal(webhook.Context{
+		Level:           webhook.WebhookWarn,
+		ActivityType:    string(api.ActivityAnomalyCreate),
+		Title:           "Anomaly detected",
+		Link:            "https://bytebase.example.com",
+		CreatorID:       101,
+		CreatorName:     "alice",
+		CreatorEmail:    "alice@example.com",
+		ActivityPayload: `{"anomalyType":"bb.anomaly.database.backup.missing"}`,
+	}, m.getWorkspaceWebhookContext(activity, &Metadata{}))
+
+	activity.Type = api.ActivityIssueCreate
+	activity.Level = api.ActivityInfo
+	issue := &api.Issue{ID: 102, Name: "Add column", ProjectID: 103, Project: &api.Project{Name: "project"}}
+	got := m.getWorkspaceWebhookContext(activity, &Metadata{Issue: issue})
+	a.Equal(webhook.WebhookInfo, got.Level)
+	a.Equal("Issue created - Add column", got.Title)
+	a.Equal("https://bytebase.example.com/issue/add-column-102", got.Link)
+	a.Equal(&webhook.Project{ID: 103, Name: "project"}, got.Project)
+
+	a.Equal("bb.unknown", getWorkspaceWebhookTitle(api.ActivityType("bb.unknown")))
+}
+
+func TestGetMentionedEmailList(t *testing.T) {
+	a := require.New(t)
+	a.Equal([]string{"alice@example.com", "bob@example.com"}, getMentionedEmailList("@alice@example.com please review, cc @Bob@Example.com and @alice@example.com"))
+	a.Nil(getMentionedEmailList("send to alice@example.com"))
+}
+
+func TestGetEmailRecipientMap(t *testing.T) {
+	a := require.New(t)
+	pipeline := &api.Pipeline{
+		StageList: []*api.Stage{
+			{TaskList: []*api.Task{{ID: 1, Status: api.TaskRunning}}},
+			{TaskList: []*api.Task{{ID: 2, Status: api.TaskPendingApproval}}},
+		},
+	}
+	issue := &api.Issue{
+		CreatorID:      101,
+		AssigneeID:     102,
+		SubscriberList: []*api.Principal{{ID: 103}, {ID: 104}},
+		Pipeline:       pipeline,
+	}
+
+	activity := &api.Activity{CreatorID: 101, Type: api.ActivityIssueCreate}
+	recipientMap, err := getEmailRecipientMap(activity, issue, true /* postInbox */, nil)
+	a.NoError(err)
+	a.Equal(map[int]api.NotificationReason{
+		102: api.NotificationReasonAssigned,
+		103: api.NotificationReasonSubscribed,
+		104: api.NotificationReasonSubscribed,
+	}, recipientMap)
+
+	activity = &api.Activity{CreatorID: 103, Type: api.ActivityIssueCommentCreate}
+	recipientMap, err = getEmailRecipientMap(activity, issue, true /* postInbox */, []int{104, 105, 103})
+	a.NoError(err)
+	a.Equal(map[int]api.NotificationReason{
+		101: api.NotificationReasonSubscribed,
+		102: api.NotificationReasonSubscribed,
+		104: api.NotificationReasonMentioned,
+		105: api.NotificationReasonMentioned,
+	}, recipientMap)
+
+	activity = &api.Activity{
+		CreatorID: api.SystemBotID,
+		Type:      api.ActivityPipelineTaskStatusUpdate,
+		Payload:   `{"taskId":1,"oldStatus":"RUNNING","newStatus":"DONE"}`,
+	}
+	recipientMap, err = getEmailRecipientMap(activity, issue, false /* postInbox */, nil)
+	a.NoError(err)
+	a.Equal(map[int]api.NotificationReason{102: api.NotificationReasonApprovalNeeded}, recipientMap)
+
+	activity = &api.Activity{
+		CreatorID: 102,
+		Type:      api.ActivityIssueFieldUpdate,
+		Payload:   `{"fieldId":"3","oldValue":"102","newValue":"104"}`,
+	}
+	recipientMap, err = getEmailRecipientMap(activity, issue, false /* postInbox */, nil)
+	a.NoError(err)
+	a.Equal(map[int]api.NotificationReason{104: api.NotificationReasonAssigned}, recipientMap)
+}
+
+func TestGetActiveStagePendingApproval(t *testing.T) {
+	a := require.New(t)
+	pipeline := &api.Pipeline{
+		StageList: []*api.Stage{
+			{ID: 101, TaskList: []*api.Task{{ID: 1, Status: api.TaskDone}, {ID: 2, Status: api.TaskRunning}}},
+			{ID: 102, TaskList: []*api.Task{{ID: 3, Status: api.TaskPendingApproval}}},
+		},
+	}
+	a.Nil(getActiveStagePendingApproval(pipeline, 0))
+	a.Equal(pipeline.StageList[1], getActiveStagePendingApproval(pipeline, 2))
+	a.Nil(getActiveStagePendingApproval(nil, 0))
+
+	a.Nil(getWebhookPendingApproval(nil))
+	a.Equal(102, getWebhookPendingApproval(pipeline.StageList[1]).StageID)
+}
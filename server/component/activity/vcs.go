@@ -0,0 +1,264 @@
+package activity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/vcs"
+	"github.com/bytebase/bytebase/server/utils"
+)
+
+const (
+	// vcsStatusContextPrefix is the prefix of the commit status context, followed by the environment name.
+	vcsStatusContextPrefix = "bytebase"
+	// maxVCSStatusDescriptionLength is the max length of the commit status description, which is limited by GitHub.
+	maxVCSStatusDescriptionLength = 140
+	// vcsStatusTimeout is the timeout to post the status of a stage back to the VCS.
+	vcsStatusTimeout = 10 * time.Second
+)
+
+// detachedContext keeps the values of the parent context but drops its cancellation, so that posting the
+// status in the Go routine is not canceled as soon as the web request returns.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+// vcsStatusEvent is the event to post the migration status of a stage back to the VCS.
+type vcsStatusEvent struct {
+	issue   *api.Issue
+	stageID int
+	// taskID is the task whose status is changed, it's 0 if the status is posted for the whole stage,
+	// e.g. the issue is created.
+	taskID    int
+	newStatus api.TaskStatus
+}
+
+// getVCSStatusEventList returns the events to post the migration status back to the VCS for the activity.
+func getVCSStatusEventList(activity *api.Activity, issue *api.Issue) []vcsStatusEvent {
+	var eventList []vcsStatusEvent
+	switch activity.Type {
+	case api.ActivityIssueCreate:
+		if issue.Pipeline == nil {
+			return nil
+		}
+		for _, stage := range issue.Pipeline.StageList {
+			eventList = append(eventList, vcsStatusEvent{issue: issue, stageID: stage.ID})
+		}
+	case api.ActivityPipelineTaskStatusUpdate:
+		update := &api.ActivityPipelineTaskStatusUpdatePayload{}
+		if err := json.Unmarshal([]byte(activity.Payload), update); err != nil {
+			log.Warn("Failed to unmarshal task status update payload", zap.Int("activity_id", activity.ID), zap.Error(err))
+			return nil
+		}
+		eventList = append(eventList, vcsStatusEvent{issue: issue, taskID: update.TaskID, newStatus: update.NewStatus})
+	}
+	return eventList
+}
+
+// postVCSStatusList posts the migration status of the stages back to the originating commit and pull request
+// of the VCS push event. It's best-effort and the errors are only logged.
+func (m *Manager) postVCSStatusList(ctx context.Context, eventList []vcsStatusEvent) {
+	for _, event := range eventList {
+		if err := m.postVCSStatusWithTimeout(ctx, event); err != nil {
+			log.Warn("Failed to post migration status back to VCS",
+				zap.Int("issue_id", event.issue.ID),
+				zap.Int("stage_id", event.stageID),
+				zap.Error(err))
+		}
+	}
+}
+
+// postVCSStatusWithTimeout posts the status with a timeout derived from the context of the caller, so that a
+// slow VCS cannot hold the Go routine forever.
+func (m *Manager) postVCSStatusWithTimeout(ctx context.Context, event vcsStatusEvent) error {
+	ctx, cancel := context.WithTimeout(detachedContext{ctx}, vcsStatusTimeout)
+	defer cancel()
+	return m.postVCSStatus(ctx, event)
+}
+
+func (m *Manager) postVCSStatus(ctx context.Context, event vcsStatusEvent) error {
+	var task *api.Task
+	if event.taskID != 0 {
+		t, err := m.store.GetTaskByID(ctx, event.taskID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to find task %d", event.taskID)
+		}
+		if t == nil {
+			return errors.Errorf("task %d not found", event.taskID)
+		}
+		task = t
+		event.stageID = task.StageID
+	}
+
+	stageList, err := m.store.FindStage(ctx, &api.StageFind{ID: &event.stageID})
+	if err != nil {
+		return errors.Wrapf(err, "failed to find stage %d", event.stageID)
+	}
+	if len(stageList) == 0 {
+		return errors.Errorf("stage %d not found", event.stageID)
+	}
+	stage := stageList[0]
+
+	pushEvent := getVCSPushEvent(stage.TaskList)
+	if pushEvent == nil {
+		// The issue is not created from the VCS.
+		return nil
+	}
+	commitID := pushEvent.After
+	if commitID == "" && len(pushEvent.CommitList) > 0 {
+		commitID = pushEvent.CommitList[len(pushEvent.CommitList)-1].ID
+	}
+	if commitID == "" {
+		return nil
+	}
+
+	repositoryList, err := m.store.FindRepository(ctx, &api.RepositoryFind{ProjectID: &event.issue.ProjectID})
+	if err != nil {
+		return errors.Wrapf(err, "failed to find repository for project %d", event.issue.ProjectID)
+	}
+	var repo *api.Repository
+	for _, r := range repositoryList {
+		if r.VCS != nil && r.ExternalID == pushEvent.RepositoryID {
+			repo = r
+			break
+		}
+	}
+	if repo == nil {
+		// The repository may have been unlinked from the project.
+		return nil
+	}
+
+	environmentName := stage.Name
+	if stage.Environment != nil {
+		environmentName = stage.Environment.Name
+	}
+	link := fmt.Sprintf("%s/issue/%s", m.profile.ExternalURL, api.IssueSlug(event.issue))
+	state, description, err := m.getStageCommitState(ctx, stage.TaskList)
+	if err != nil {
+		return err
+	}
+	if runes := []rune(description); len(runes) > maxVCSStatusDescriptionLength {
+		description = string(runes[:maxVCSStatusDescriptionLength-3]) + "..."
+	}
+
+	provider := vcs.Get(repo.VCS.Type, vcs.ProviderConfig{})
+	oauthContext := common.OauthContext{
+		ClientID:     repo.VCS.ApplicationID,
+		ClientSecret: repo.VCS.Secret,
+		AccessToken:  repo.AccessToken,
+		RefreshToken: repo.RefreshToken,
+		Refresher:    utils.RefreshToken(ctx, m.store, repo.WebURL),
+	}
+	if err := provider.CreateCommitStatus(ctx, oauthContext, repo.VCS.InstanceURL, repo.ExternalID, commitID, &vcs.CommitStatus{
+		State:       state,
+		Context:     fmt.Sprintf("%s/%s", vcsStatusContextPrefix, environmentName),
+		Description: description,
+		TargetURL:   link,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to create commit status for commit %s", commitID)
+	}
+
+	// To reduce noise, we only comment on the pull request when a task fails, or all tasks in the environment are done.
+	if pushEvent.PullRequestID == "" || task == nil {
+		return nil
+	}
+	var comment string
+	switch {
+	case event.newStatus == api.TaskFailed:
+		detail, err := m.getTaskFailedDetail(ctx, task.ID)
+		if err != nil {
+			return err
+		}
+		comment = fmt.Sprintf("Migration task %q failed in environment **%s**: %s\n\nSee [%s](%s) for details.", task.Name, environmentName, detail, event.issue.Name, link)
+	case event.newStatus == api.TaskDone && state == vcs.CommitStateSuccess:
+		comment = fmt.Sprintf("Migration is done in environment **%s**.\n\nSee [%s](%s) for details.", environmentName, event.issue.Name, link)
+	default:
+		return nil
+	}
+	if err := provider.CreatePullRequestComment(ctx, oauthContext, repo.VCS.InstanceURL, repo.ExternalID, pushEvent.PullRequestID, comment); err != nil {
+		return errors.Wrapf(err, "failed to create comment for pull request %s", pushEvent.PullRequestID)
+	}
+	return nil
+}
+
+// getStageCommitState aggregates the task status in the stage into the commit state and description.
+func (m *Manager) getStageCommitState(ctx context.Context, taskList []*api.Task) (vcs.CommitState, string, error) {
+	statusCount := make(map[api.TaskStatus]int)
+	var failedTask *api.Task
+	for _, task := range taskList {
+		statusCount[task.Status]++
+		if task.Status == api.TaskFailed && failedTask == nil {
+			failedTask = task
+		}
+	}
+
+	switch {
+	case failedTask != nil:
+		detail, err := m.getTaskFailedDetail(ctx, failedTask.ID)
+		if err != nil {
+			return "", "", err
+		}
+		return vcs.CommitStateFailure, fmt.Sprintf("Failed: %s", detail), nil
+	case statusCount[api.TaskCanceled] > 0:
+		return vcs.CommitStateFailure, "Canceled", nil
+	case statusCount[api.TaskDone] == len(taskList):
+		return vcs.CommitStateSuccess, "Done", nil
+	case statusCount[api.TaskRunning] > 0:
+		return vcs.CommitStatePending, "Running", nil
+	case statusCount[api.TaskPendingApproval] > 0:
+		return vcs.CommitStatePending, "Pending approval", nil
+	default:
+		return vcs.CommitStatePending, "Pending", nil
+	}
+}
+
+// getTaskFailedDetail returns the error detail of the most recent task run.
+func (m *Manager) getTaskFailedDetail(ctx context.Context, taskID int) (string, error) {
+	task, err := m.store.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to find task %d", taskID)
+	}
+	if task == nil || len(task.TaskRunList) == 0 {
+		return "unknown error", nil
+	}
+	sort.Slice(task.TaskRunList, func(i int, j int) bool {
+		return task.TaskRunList[i].UpdatedTs > task.TaskRunList[j].UpdatedTs || (task.TaskRunList[i].UpdatedTs == task.TaskRunList[j].UpdatedTs && task.TaskRunList[i].ID > task.TaskRunList[j].ID)
+	})
+	var result api.TaskRunResultPayload
+	if err := json.Unmarshal([]byte(task.TaskRunList[0].Result), &result); err != nil {
+		return "", errors.Wrapf(err, "failed to unmarshal the result of task run %d", task.TaskRunList[0].ID)
+	}
+	if result.Detail == "" {
+		return "unknown error", nil
+	}
+	return result.Detail, nil
+}
+
+// getVCSPushEvent returns the VCS push event of the tasks. All the migration task payloads
+// share the same "pushEvent" field with the MigrationInfoPayload.
+func getVCSPushEvent(taskList []*api.Task) *vcs.PushEvent {
+	for _, task := range taskList {
+		var payload db.MigrationInfoPayload
+		if err := json.Unmarshal([]byte(task.Payload), &payload); err != nil {
+			continue
+		}
+		if payload.VCSPushEvent != nil {
+			return payload.VCSPushEvent
+		}
+	}
+	return nil
+}
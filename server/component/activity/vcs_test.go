@@ -0,0 +1,94 @@
+package activity
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/vcs"
+)
+
+func TestGetVCSStatusEventList(t *testing.T) {
+	a := require.New(t)
+	issue := &api.Issue{
+		ID: 101,
+		Pipeline: &api.Pipeline{
+			StageList: []*api.Stage{{ID: 201}, {ID: 202}},
+		},
+	}
+
+	a.Equal([]vcsStatusEvent{
+		{issue: issue, stageID: 201},
+		{issue: issue, stageID: 202},
+	}, getVCSStatusEventList(&api.Activity{Type: api.ActivityIssueCreate}, issue))
+
+	a.Equal([]vcsStatusEvent{
+		{issue: issue, taskID: 301, newStatus: api.TaskFailed},
+	}, getVCSStatusEventList(&api.Activity{
+		Type:    api.ActivityPipelineTaskStatusUpdate,
+		Payload: `{"taskId":301,"oldStatus":"RUNNING","newStatus":"FAILED"}`,
+	}, issue))
+
+	a.Nil(getVCSStatusEventList(&api.Activity{Type: api.ActivityPipelineTaskStatusUpdate, Payload: "{"}, issue))
+	a.Nil(getVCSStatusEventList(&api.Activity{Type: api.ActivityIssueCommentCreate}, issue))
+	a.Nil(getVCSStatusEventList(&api.Activity{Type: api.ActivityIssueCreate}, &api.Issue{ID: 102}))
+}
+
+func TestGetVCSPushEvent(t *testing.T) {
+	a := require.New(t)
+	a.Nil(getVCSPushEvent([]*api.Task{
+		{Payload: `{"statement":"CREATE TABLE t(id INT);"}`},
+	}))
+	a.Equal(&vcs.PushEvent{
+		Ref:           "refs/heads/main",
+		After:         "abc",
+		PullRequestID: "12",
+	}, getVCSPushEvent([]*api.Task{
+		{Payload: "{"},
+		{Payload: `{"statement":"CREATE TABLE t(id INT);","pushEvent":{"ref":"refs/heads/main","after":"abc","pullRequestId":"12"}}`},
+	}))
+}
+
+func TestGetStageCommitState(t *testing.T) {
+	a := require.New(t)
+	m := &Manager{}
+	tests := []struct {
+		statusList  []api.TaskStatus
+		state       vcs.CommitState
+		description string
+	}{
+		{[]api.TaskStatus{api.TaskDone, api.TaskDone}, vcs.CommitStateSuccess, "Done"},
+		{[]api.TaskStatus{api.TaskDone, api.TaskCanceled}, vcs.CommitStateFailure, "Canceled"},
+		{[]api.TaskStatus{api.TaskDone, api.TaskRunning, api.TaskPendingApproval}, vcs.CommitStatePending, "Running"},
+		{[]api.TaskStatus{api.TaskPending, api.TaskPendingApproval}, vcs.CommitStatePending, "Pending approval"},
+		{[]api.TaskStatus{api.TaskDone, api.TaskPending}, vcs.CommitStatePending, "Pending"},
+	}
+	for _, test := range tests {
+		var taskList []*api.Task
+		for _, status := range test.statusList {
+			taskList = append(taskList, &api.Task{Status: status})
+		}
+		state, description, err := m.getStageCommitState(context.Background(), taskList)
+		a.NoError(err)
+		a.Equal(test.state, state, test.statusList)
+		a.Equal(test.description, description, test.statusList)
+	}
+}
+
+func TestDetachedContext(t *testing.T) {
+	a := require.New(t)
+	type key struct{}
+	parent, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "value"))
+	cancel()
+
+	ctx, cancel := context.WithTimeout(detachedContext{parent}, vcsStatusTimeout)
+	defer cancel()
+	a.NoError(ctx.Err())
+	a.Equal("value", ctx.Value(key{}))
+	deadline, ok := ctx.Deadline()
+	a.True(ok)
+	a.True(deadline.After(time.Now()))
+}
@@ -48,6 +48,8 @@ type Profile struct {
 	// IM integration related fields
 	// FeishuAPIURL is the URL of Feishu API server.
 	FeishuAPIURL string
+	// SlackAPIURL is the URL of Slack API server.
+	SlackAPIURL string
 
 	// Version is the bytebase's version
 	Version string
@@ -0,0 +1,73 @@
+// Package rbac resolves the permissions of the built-in and custom roles, which is shared by the API server and the runners.
+package rbac
+
+import (
+	"context"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	enterpriseAPI "github.com/bytebase/bytebase/enterprise/api"
+	"github.com/bytebase/bytebase/store"
+)
+
+// FindCustomRole finds the custom role by the role key.
+// Returns nil if the custom role is not found or the custom role feature is not enabled.
+func FindCustomRole(ctx context.Context, store *store.Store, licenseService enterpriseAPI.LicenseService, role api.Role) (*api.CustomRole, error) {
+	if !licenseService.IsFeatureEnabled(api.FeatureCustomRole) {
+		return nil, nil
+	}
+	return store.GetCustomRole(ctx, &api.CustomRoleFind{Role: &role})
+}
+
+// GetRolePermissionList returns the workspace permissions of the built-in or custom role.
+// The custom role not found falls back to the DEVELOPER role, which is the least privileged built-in role.
+func GetRolePermissionList(ctx context.Context, store *store.Store, licenseService enterpriseAPI.LicenseService, role api.Role) ([]api.Permission, error) {
+	if permissionList := api.BuiltInRolePermissionList(role); permissionList != nil {
+		return permissionList, nil
+	}
+	customRole, err := FindCustomRole(ctx, store, licenseService, role)
+	if err != nil {
+		return nil, err
+	}
+	if customRole == nil {
+		return api.BuiltInRolePermissionList(api.Developer), nil
+	}
+	var permissionList []api.Permission
+	for _, permission := range customRole.PermissionList {
+		permissionList = append(permissionList, api.Permission(permission))
+	}
+	return permissionList, nil
+}
+
+// HasPermission returns whether the permission is in the permission list.
+func HasPermission(permissionList []api.Permission, permission api.Permission) bool {
+	for _, p := range permissionList {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// HasProjectPermission returns whether the project role, either built-in or custom, grants the project permission.
+func HasProjectPermission(ctx context.Context, store *store.Store, licenseService enterpriseAPI.LicenseService, permission api.ProjectPermissionType, role common.ProjectRole) (bool, error) {
+	return HasProjectRolePermission(permission, licenseService.GetEffectivePlan(), role, func(role string) (*api.CustomRole, error) {
+		return FindCustomRole(ctx, store, licenseService, api.Role(role))
+	})
+}
+
+// HasProjectRolePermission returns whether the project role grants the project permission.
+// The custom role not found by the customRoleFinder falls back to the project developer role.
+func HasProjectRolePermission(permission api.ProjectPermissionType, plan api.PlanType, role common.ProjectRole, customRoleFinder func(role string) (*api.CustomRole, error)) (bool, error) {
+	if role == common.ProjectOwner || role == common.ProjectDeveloper {
+		return api.ProjectPermission(permission, plan, role), nil
+	}
+	customRole, err := customRoleFinder(string(role))
+	if err != nil {
+		return false, err
+	}
+	if customRole == nil {
+		return api.ProjectPermission(permission, plan, common.ProjectDeveloper), nil
+	}
+	return customRole.HasPermission(string(permission)), nil
+}
@@ -0,0 +1,51 @@
+package rbac
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+func TestHasProjectRolePermission(t *testing.T) {
+	a := require.New(t)
+	const releaseManager common.ProjectRole = "RELEASE_MANAGER"
+	customRoleFinder := func(role string) (*api.CustomRole, error) {
+		if role == string(releaseManager) {
+			return &api.CustomRole{
+				Role:           api.Role(releaseManager),
+				PermissionList: []string{string(api.ProjectPermissionManageMember), string(api.ProjectPermissionApproveIssue)},
+			}, nil
+		}
+		return nil, nil
+	}
+
+	ok, err := HasProjectRolePermission(api.ProjectPermissionManageMember, api.ENTERPRISE, releaseManager, customRoleFinder)
+	a.NoError(err)
+	a.True(ok)
+	ok, err = HasProjectRolePermission(api.ProjectPermissionApproveIssue, api.ENTERPRISE, releaseManager, customRoleFinder)
+	a.NoError(err)
+	a.True(ok)
+	ok, err = HasProjectRolePermission(api.ProjectPermissionManageGeneral, api.ENTERPRISE, releaseManager, customRoleFinder)
+	a.NoError(err)
+	a.False(ok)
+	ok, err = HasProjectRolePermission(api.ProjectPermissionApproveIssue, api.ENTERPRISE, common.ProjectOwner, customRoleFinder)
+	a.NoError(err)
+	a.True(ok)
+	// The custom role not found falls back to the project developer.
+	ok, err = HasProjectRolePermission(api.ProjectPermissionChangeDatabase, api.ENTERPRISE, "UNKNOWN", customRoleFinder)
+	a.NoError(err)
+	a.True(ok)
+	ok, err = HasProjectRolePermission(api.ProjectPermissionApproveIssue, api.ENTERPRISE, "UNKNOWN", customRoleFinder)
+	a.NoError(err)
+	a.False(ok)
+}
+
+func TestHasPermission(t *testing.T) {
+	a := require.New(t)
+	a.True(HasPermission(api.BuiltInRolePermissionList(api.DBA), api.PermissionIssueApprove))
+	a.False(HasPermission(api.BuiltInRolePermissionList(api.Developer), api.PermissionIssueApprove))
+	a.False(HasPermission(nil, api.PermissionIssueApprove))
+}
@@ -0,0 +1,108 @@
+// Package sso is a component for syncing the workspace members from the single sign-on providers, e.g. OIDC and LDAP.
+package sso
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/server/component/activity"
+	"github.com/bytebase/bytebase/store"
+)
+
+// getRolePriority returns the priority of the role mapped from the groups of the single sign-on provider.
+// A custom role ranks above DEVELOPER and below DBA, and the custom roles rank equally so the first mapped one wins.
+func getRolePriority(role api.Role) int {
+	switch role {
+	case "":
+		return 0
+	case api.Developer:
+		return 1
+	case api.DBA:
+		return 3
+	case api.Owner:
+		return 4
+	default:
+		return 2
+	}
+}
+
+// GetRoleFromGroupList returns the most privileged role mapped from the groups, and false if no group is mapped.
+func GetRoleFromGroupList(groupList []string, mappingList []api.SSOGroupRoleMapping) (api.Role, bool) {
+	var role api.Role
+	for _, mapping := range mappingList {
+		for _, group := range groupList {
+			if group == mapping.Group && getRolePriority(mapping.Role) > getRolePriority(role) {
+				role = mapping.Role
+			}
+		}
+	}
+	return role, role != ""
+}
+
+// SyncMemberRole updates the workspace role of the user to the role mapped from the single sign-on provider.
+// The only remaining owner in the workspace is never demoted.
+func SyncMemberRole(ctx context.Context, store *store.Store, activityManager *activity.Manager, user *api.Principal, role api.Role) error {
+	member, err := store.GetMemberByPrincipalID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if member == nil || member.Role == role {
+		return nil
+	}
+	if member.Role == api.Owner {
+		isLastOwner, err := IsLastOwner(ctx, store)
+		if err != nil {
+			return err
+		}
+		if isLastOwner {
+			return nil
+		}
+	}
+
+	roleString := string(role)
+	updatedMember, err := store.PatchMember(ctx, &api.MemberPatch{
+		ID:        member.ID,
+		UpdaterID: api.SystemBotID,
+		Role:      &roleString,
+	})
+	if err != nil {
+		return err
+	}
+	bytes, err := json.Marshal(api.ActivityMemberRoleUpdatePayload{
+		PrincipalID:    updatedMember.PrincipalID,
+		PrincipalName:  user.Name,
+		PrincipalEmail: user.Email,
+		OldRole:        member.Role,
+		NewRole:        updatedMember.Role,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := activityManager.CreateActivity(ctx, &api.ActivityCreate{
+		CreatorID:   api.SystemBotID,
+		ContainerID: updatedMember.ID,
+		Type:        api.ActivityMemberRoleUpdate,
+		Level:       api.ActivityInfo,
+		Payload:     string(bytes),
+	}, &activity.Metadata{}); err != nil {
+		return err
+	}
+	// The principal is composed with the role of the member.
+	user.Role = updatedMember.Role
+	return nil
+}
+
+// IsLastOwner returns true if there is only one active owner in the workspace.
+func IsLastOwner(ctx context.Context, store *store.Store) (bool, error) {
+	countResult, err := store.CountMemberGroupByRoleAndStatus(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, count := range countResult {
+		if count.Role == api.Owner && count.RowStatus == api.Normal && count.Count == 1 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,39 @@
+package sso
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestGetRoleFromGroupList(t *testing.T) {
+	mappingList := []api.SSOGroupRoleMapping{
+		{Group: "developers", Role: api.Developer},
+		{Group: "dbas", Role: api.DBA},
+		{Group: "admins", Role: api.Owner},
+		{Group: "releasers", Role: api.Role("RELEASER")},
+		{Group: "auditors", Role: api.Role("AUDITOR")},
+	}
+	tests := []struct {
+		groupList []string
+		wantRole  api.Role
+		wantOK    bool
+	}{
+		{groupList: nil, wantRole: "", wantOK: false},
+		{groupList: []string{"others"}, wantRole: "", wantOK: false},
+		{groupList: []string{"developers"}, wantRole: api.Developer, wantOK: true},
+		{groupList: []string{"developers", "dbas"}, wantRole: api.DBA, wantOK: true},
+		{groupList: []string{"admins", "developers", "dbas"}, wantRole: api.Owner, wantOK: true},
+		{groupList: []string{"developers", "releasers"}, wantRole: api.Role("RELEASER"), wantOK: true},
+		{groupList: []string{"releasers", "dbas"}, wantRole: api.DBA, wantOK: true},
+		{groupList: []string{"auditors", "releasers"}, wantRole: api.Role("RELEASER"), wantOK: true},
+	}
+	a := require.New(t)
+	for _, test := range tests {
+		role, ok := GetRoleFromGroupList(test.groupList, mappingList)
+		a.Equal(test.wantRole, role, test.groupList)
+		a.Equal(test.wantOK, ok, test.groupList)
+	}
+}
@@ -0,0 +1,220 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/server/component/rbac"
+)
+
+// customRoleKeyRegex restricts the role key to the same form as the built-in roles, e.g. RELEASE_MANAGER.
+var customRoleKeyRegex = regexp.MustCompile(`^[A-Z][A-Z0-9_]{0,63}$`)
+
+func (s *Server) registerCustomRoleRoutes(g *echo.Group) {
+	g.GET("/role", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		customRoleList, err := s.store.FindCustomRole(ctx, &api.CustomRoleFind{})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch custom role list").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, customRoleList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal custom role list response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.POST("/role", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		if !s.licenseService.IsFeatureEnabled(api.FeatureCustomRole) {
+			return echo.NewHTTPError(http.StatusForbidden, api.FeatureCustomRole.AccessErrorMessage())
+		}
+
+		customRoleCreate := &api.CustomRoleCreate{
+			CreatorID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, customRoleCreate); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create custom role request").SetInternal(err)
+		}
+		if err := validateCustomRoleKey(customRoleCreate.Role); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if customRoleCreate.Name == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Custom role name is required")
+		}
+		if err := validatePermissionList(customRoleCreate.PermissionList); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		customRole, err := s.store.CreateCustomRole(ctx, customRoleCreate)
+		if err != nil {
+			if common.ErrorCode(err) == common.Conflict {
+				return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Custom role already exists: %s", customRoleCreate.Role))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create custom role").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, customRole); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal create custom role response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.PATCH("/role/:roleID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		if !s.licenseService.IsFeatureEnabled(api.FeatureCustomRole) {
+			return echo.NewHTTPError(http.StatusForbidden, api.FeatureCustomRole.AccessErrorMessage())
+		}
+
+		id, err := strconv.Atoi(c.Param("roleID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Custom role ID is not a number: %s", c.Param("roleID"))).SetInternal(err)
+		}
+		customRolePatch := &api.CustomRolePatch{
+			ID:        id,
+			UpdaterID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, customRolePatch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch custom role request").SetInternal(err)
+		}
+		if v := customRolePatch.Name; v != nil && *v == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Custom role name is required")
+		}
+		if v := customRolePatch.PermissionList; v != nil {
+			if err := validatePermissionList(v); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+		}
+
+		customRole, err := s.store.PatchCustomRole(ctx, customRolePatch)
+		if err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Custom role ID not found: %d", id))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to patch custom role ID: %v", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, customRole); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal patch custom role response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.DELETE("/role/:roleID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("roleID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Custom role ID is not a number: %s", c.Param("roleID"))).SetInternal(err)
+		}
+
+		customRole, err := s.store.GetCustomRole(ctx, &api.CustomRoleFind{ID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch custom role ID: %v", id)).SetInternal(err)
+		}
+		if customRole == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Custom role ID not found: %d", id))
+		}
+		// The role in use must be unassigned first, otherwise the members would silently lose their permissions.
+		memberList, err := s.store.FindMember(ctx, &api.MemberFind{Role: &customRole.Role})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch member list").SetInternal(err)
+		}
+		if len(memberList) > 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Custom role %s is still assigned to %d workspace members", customRole.Role, len(memberList)))
+		}
+		projectMemberList, err := s.store.FindProjectMember(ctx, &api.ProjectMemberFind{Role: &customRole.Role})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch project member list").SetInternal(err)
+		}
+		if len(projectMemberList) > 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Custom role %s is still assigned to %d project members", customRole.Role, len(projectMemberList)))
+		}
+
+		if err := s.store.DeleteCustomRole(ctx, &api.CustomRoleDelete{
+			ID:        id,
+			DeleterID: c.Get(getPrincipalIDContextKey()).(int),
+		}); err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Custom role ID not found: %d", id))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to delete custom role ID: %v", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		c.Response().WriteHeader(http.StatusOK)
+		return nil
+	})
+}
+
+// findCustomRole finds the custom role by the role key.
+// Returns nil if the custom role is not found or the custom role feature is not enabled.
+func (s *Server) findCustomRole(ctx context.Context, role api.Role) (*api.CustomRole, error) {
+	return rbac.FindCustomRole(ctx, s.store, s.licenseService, role)
+}
+
+// getRolePermissionList returns the workspace permissions of the built-in or custom role.
+func (s *Server) getRolePermissionList(ctx context.Context, role api.Role) ([]api.Permission, error) {
+	return rbac.GetRolePermissionList(ctx, s.store, s.licenseService, role)
+}
+
+// hasProjectPermission returns whether the project role, either built-in or custom, grants the project permission.
+func (s *Server) hasProjectPermission(ctx context.Context, permission api.ProjectPermissionType, role common.ProjectRole) (bool, error) {
+	return rbac.HasProjectPermission(ctx, s.store, s.licenseService, permission, role)
+}
+
+// validateRoleAssignment validates the role assigned to a workspace or project member,
+// which is either one of the built-in roles of the membership or an existing custom role.
+func (s *Server) validateRoleAssignment(ctx context.Context, role api.Role, builtInRoleList ...api.Role) *echo.HTTPError {
+	for _, builtInRole := range builtInRoleList {
+		if role == builtInRole {
+			return nil
+		}
+	}
+	if !s.licenseService.IsFeatureEnabled(api.FeatureCustomRole) {
+		return echo.NewHTTPError(http.StatusForbidden, api.FeatureCustomRole.AccessErrorMessage())
+	}
+	customRole, err := s.findCustomRole(ctx, role)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch custom role %s", role)).SetInternal(err)
+	}
+	if customRole == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid role %q", role))
+	}
+	return nil
+}
+
+func validateCustomRoleKey(role api.Role) error {
+	if api.IsBuiltInRole(role) {
+		return errors.Errorf("role %s is a built-in role", role)
+	}
+	if !customRoleKeyRegex.MatchString(string(role)) {
+		return errors.Errorf("invalid role %q, the role must start with an uppercase letter and contain only uppercase letters, digits and underscores", role)
+	}
+	return nil
+}
+
+func validatePermissionList(permissionList []string) error {
+	permissionMap := make(map[string]bool)
+	for _, permission := range permissionList {
+		if !api.IsValidPermission(permission) {
+			return errors.Errorf("invalid permission %q", permission)
+		}
+		if permissionMap[permission] {
+			return errors.Errorf("duplicate permission %q", permission)
+		}
+		permissionMap[permission] = true
+	}
+	return nil
+}
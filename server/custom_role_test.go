@@ -0,0 +1,30 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestValidateCustomRoleKey(t *testing.T) {
+	a := require.New(t)
+	a.NoError(validateCustomRoleKey("RELEASE_MANAGER"))
+	a.NoError(validateCustomRoleKey("DBA2"))
+	a.Error(validateCustomRoleKey(api.Owner))
+	a.Error(validateCustomRoleKey(api.DBA))
+	a.Error(validateCustomRoleKey(""))
+	a.Error(validateCustomRoleKey("release_manager"))
+	a.Error(validateCustomRoleKey("2ND_DBA"))
+	a.Error(validateCustomRoleKey("RELEASE MANAGER"))
+}
+
+func TestValidatePermissionList(t *testing.T) {
+	a := require.New(t)
+	a.NoError(validatePermissionList(nil))
+	a.NoError(validatePermissionList([]string{"instance.create", "database.query", "issue.approve"}))
+	a.NoError(validatePermissionList([]string{"issue.get", "bb.permission.project.approve-issue"}))
+	a.Error(validatePermissionList([]string{"instance.drop"}))
+	a.Error(validatePermissionList([]string{"database.query", "database.query"}))
+}
@@ -53,10 +53,9 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 		}
 
 		var filteredList []*api.Database
-		role := c.Get(getRoleContextKey()).(api.Role)
-		// If the caller is a developer, we will only return databases belonging to the
+		// If the caller is not a project admin, e.g. a developer, we will only return databases belonging to the
 		// project where the caller is a member of.
-		if role == api.Developer {
+		if !hasWorkspacePermission(c, api.PermissionProjectAdmin) {
 			principalID := c.Get(getPrincipalIDContextKey()).(int)
 			for _, database := range dbList {
 				for _, projectMember := range database.Project.ProjectMemberList {
@@ -36,9 +36,8 @@ func (s *Server) registerDebugRoutes(g *echo.Group) {
 		// incrementID is used as primary key in jsonapi.
 		var incrementID int
 
-		// Only Owner and DBA can see debug logs.
-		role := c.Get(getRoleContextKey()).(api.Role)
-		if role != api.Owner && role != api.DBA {
+		// Only the members with the debug.manage permission, e.g. Owner and DBA, can see debug logs.
+		if !hasWorkspacePermission(c, api.PermissionDebugManage) {
 			return echo.NewHTTPError(http.StatusForbidden, "Not allowed to fetch debug logs")
 		}
 
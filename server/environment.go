@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/server/component/activity"
 	"github.com/bytebase/bytebase/store"
 )
 
@@ -181,6 +183,22 @@ func (s *Server) createEnvironment(ctx context.Context, create *store.Environmen
 		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to create environment").SetInternal(err)
 	}
 
+	bytes, err := json.Marshal(api.ActivityEnvironmentCreatePayload{
+		EnvironmentName: env.Name,
+	})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to construct activity payload").SetInternal(err)
+	}
+	if _, err := s.ActivityManager.CreateActivity(ctx, &api.ActivityCreate{
+		CreatorID:   create.CreatorID,
+		ContainerID: env.ID,
+		Type:        api.ActivityEnvironmentCreate,
+		Level:       api.ActivityInfo,
+		Payload:     string(bytes),
+	}, &activity.Metadata{}); err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create activity after creating environment: %d", env.ID)).SetInternal(err)
+	}
+
 	return env, nil
 }
 
@@ -214,5 +232,22 @@ func (s *Server) updateEnvironment(ctx context.Context, patch *store.Environment
 		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to patch environment ID: %v", patch.ID)).SetInternal(err)
 	}
 
+	bytes, err := json.Marshal(api.ActivityEnvironmentUpdatePayload{
+		EnvironmentName: env.Name,
+		RowStatus:       env.RowStatus,
+	})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to construct activity payload").SetInternal(err)
+	}
+	if _, err := s.ActivityManager.CreateActivity(ctx, &api.ActivityCreate{
+		CreatorID:   patch.UpdaterID,
+		ContainerID: env.ID,
+		Type:        api.ActivityEnvironmentUpdate,
+		Level:       api.ActivityInfo,
+		Payload:     string(bytes),
+	}, &activity.Metadata{}); err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create activity after updating environment: %d", env.ID)).SetInternal(err)
+	}
+
 	return env, nil
 }
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
@@ -18,6 +19,7 @@ import (
 	"github.com/bytebase/bytebase/common/log"
 	"github.com/bytebase/bytebase/plugin/db"
 	"github.com/bytebase/bytebase/resources/postgres"
+	"github.com/bytebase/bytebase/server/component/activity"
 	"github.com/bytebase/bytebase/store"
 )
 
@@ -485,6 +487,23 @@ func (s *Server) createInstance(ctx context.Context, create *store.InstanceCreat
 		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to create instance").SetInternal(err)
 	}
 
+	bytes, err := json.Marshal(api.ActivityInstanceCreatePayload{
+		InstanceName: instance.Name,
+		Engine:       instance.Engine,
+	})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to construct activity payload").SetInternal(err)
+	}
+	if _, err := s.ActivityManager.CreateActivity(ctx, &api.ActivityCreate{
+		CreatorID:   create.CreatorID,
+		ContainerID: instance.ID,
+		Type:        api.ActivityInstanceCreate,
+		Level:       api.ActivityInfo,
+		Payload:     string(bytes),
+	}, &activity.Metadata{}); err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create activity after creating instance: %d", instance.ID)).SetInternal(err)
+	}
+
 	// Try creating the "bytebase" db in the added instance if needed.
 	// Since we allow user to add new instance upfront even providing the incorrect username/password,
 	// thus it's OK if it fails. Frontend will surface relevant info suggesting the "bytebase" db hasn't created yet.
@@ -580,6 +599,23 @@ func (s *Server) updateInstance(ctx context.Context, patch *store.InstancePatch)
 			}
 			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to patch instance ID: %v", patch.ID)).SetInternal(err)
 		}
+
+		bytes, err := json.Marshal(api.ActivityInstanceUpdatePayload{
+			InstanceName: instancePatched.Name,
+			RowStatus:    instancePatched.RowStatus,
+		})
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to construct activity payload").SetInternal(err)
+		}
+		if _, err := s.ActivityManager.CreateActivity(ctx, &api.ActivityCreate{
+			CreatorID:   patch.UpdaterID,
+			ContainerID: instancePatched.ID,
+			Type:        api.ActivityInstanceUpdate,
+			Level:       api.ActivityInfo,
+			Payload:     string(bytes),
+		}, &activity.Metadata{}); err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create activity after updating instance: %d", instancePatched.ID)).SetInternal(err)
+		}
 	}
 
 	// Try immediately setup the migration schema, sync the engine version and schema after updating any connection related info.
@@ -35,6 +35,9 @@ func (s *Server) registerIssueRoutes(g *echo.Group) {
 		if err := jsonapi.UnmarshalPayload(c.Request().Body, issueCreate); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create issue request").SetInternal(err)
 		}
+		if httpErr := checkAccessTokenProject(c, issueCreate.ProjectID); httpErr != nil {
+			return httpErr
+		}
 
 		issue, err := s.createIssue(ctx, issueCreate)
 		if err != nil {
@@ -532,15 +535,18 @@ func (s *Server) getPipelineCreateForDatabaseRollback(ctx context.Context, issue
 	if task == nil {
 		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Task not found with ID %d", taskID))
 	}
-	if task.Type != api.TaskDatabaseDataUpdate {
-		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task type must be %s, but got %s", api.TaskDatabaseDataUpdate, task.Type))
-	}
-	if task.Database.Instance.Engine != db.MySQL {
-		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Only support rollback for MySQL now, but got %s", task.Database.Instance.Engine))
+	if task.Type != api.TaskDatabaseDataUpdate && task.Type != api.TaskDatabaseSchemaUpdate {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task type must be %s or %s, but got %s", api.TaskDatabaseDataUpdate, api.TaskDatabaseSchemaUpdate, task.Type))
 	}
 	if task.PipelineID != issue.PipelineID {
 		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task %d is not in issue %d", taskID, issue.ID))
 	}
+	if task.Type == api.TaskDatabaseSchemaUpdate {
+		return s.getPipelineCreateForDatabaseSchemaUndo(ctx, issueCreate, issueID, task)
+	}
+	if task.Database.Instance.Engine != db.MySQL {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Only support rollback for MySQL now, but got %s", task.Database.Instance.Engine))
+	}
 	if task.Status != api.TaskDone && task.Status != api.TaskFailed {
 		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task %d has status %s, must be %s or %s", taskID, task.Status, api.TaskDone, api.TaskFailed))
 	}
@@ -599,6 +605,65 @@ func (s *Server) getPipelineCreateForDatabaseRollback(ctx context.Context, issue
 	return pipelineCreate, nil
 }
 
+// getPipelineCreateForDatabaseSchemaUndo creates the pipeline to roll back the schema update task by applying
+// the undo script of its schema version, which is committed in the VCS along with the forward migration.
+func (s *Server) getPipelineCreateForDatabaseSchemaUndo(ctx context.Context, issueCreate *api.IssueCreate, issueID int, task *api.Task) (*api.PipelineCreate, error) {
+	if task.Status != api.TaskDone {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task %d has status %s, must be %s", task.ID, task.Status, api.TaskDone))
+	}
+	taskPayload := &api.TaskDatabaseSchemaUpdatePayload{}
+	if err := json.Unmarshal([]byte(task.Payload), taskPayload); err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to unmarshal the task payload with ID %d", task.ID)).SetInternal(err)
+	}
+	undo, err := s.store.GetMigrationUndo(ctx, *task.DatabaseID, taskPayload.SchemaVersion)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find undo script for version %s of database %q", taskPayload.SchemaVersion, task.Database.Name)).SetInternal(err)
+	}
+	if undo == nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Undo script for version %s of database %q is not found, it should be committed to the repository with the undo migration type", taskPayload.SchemaVersion, task.Database.Name))
+	}
+
+	issueCreateContext := &api.MigrationContext{
+		DetailList: []*api.MigrationDetail{
+			{
+				MigrationType: db.Migrate,
+				DatabaseID:    *task.DatabaseID,
+				Statement:     undo.Statement,
+			},
+		},
+	}
+	bytes, err := json.Marshal(issueCreateContext)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal issue create context for rollback issue")
+	}
+	issueCreate.CreateContext = string(bytes)
+	issueCreate.Type = api.IssueDatabaseSchemaUpdate
+	pipelineCreate, err := s.getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx, issueCreate)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pipelineCreate.StageList) != 1 {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Must have one stage for a rollback task")
+	}
+	if len(pipelineCreate.StageList[0].TaskList) != 1 {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Must have one task for a rollback task")
+	}
+	rollbackTaskPayload := &api.TaskDatabaseSchemaUpdatePayload{}
+	if err := json.Unmarshal([]byte(pipelineCreate.StageList[0].TaskList[0].Payload), rollbackTaskPayload); err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to unmarshal the rollback task create payload").SetInternal(err)
+	}
+	rollbackTaskPayload.RollbackFromIssueID = issueID
+	rollbackTaskPayload.RollbackFromTaskID = task.ID
+	buf, err := json.Marshal(rollbackTaskPayload)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal rollback task payload").SetInternal(err)
+	}
+	pipelineCreate.StageList[0].TaskList[0].Payload = string(buf)
+
+	return pipelineCreate, nil
+}
+
 func (s *Server) getPipelineCreateForDatabaseCreate(ctx context.Context, issueCreate *api.IssueCreate) (*api.PipelineCreate, error) {
 	c := api.CreateDatabaseContext{}
 	if err := json.Unmarshal([]byte(issueCreate.CreateContext), &c); err != nil {
@@ -743,6 +808,8 @@ func (s *Server) getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx context.Con
 	}
 	create := &api.PipelineCreate{
 		Name: "Change database pipeline",
+		// The issue created from an open pull request is a draft, it's held until the pull request is merged.
+		Held: c.VCSPushEvent != nil && c.VCSPushEvent.PullRequestID != "",
 	}
 
 	project, err := s.store.GetProjectByID(ctx, issueCreate.ProjectID)
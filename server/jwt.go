@@ -46,11 +46,17 @@ const (
 	// The key name used to store principal id in the context
 	// principal id is extracted from the jwt token subject field.
 	principalIDContextKey = "principal-id"
+	// The key name used to store the access token in the context if the request is authenticated by a long-lived access token.
+	accessTokenContextKey = "access-token"
 
 	// Various access key / token prefix.
 
 	// serviceAccountAccessKeyPrefix is the prefix for service account access key.
 	serviceAccountAccessKeyPrefix = "bbs_"
+	// personalAccessTokenPrefix is the prefix for the long-lived personal access token of the end user.
+	personalAccessTokenPrefix = "bbp_"
+	// serviceAccountKeyPrefix is the prefix for the long-lived scoped key of the service account.
+	serviceAccountKeyPrefix = "bbk_"
 )
 
 // Claims creates a struct that will be encoded to a JWT.
@@ -64,6 +70,10 @@ func getPrincipalIDContextKey() string {
 	return principalIDContextKey
 }
 
+func getAccessTokenContextKey() string {
+	return accessTokenContextKey
+}
+
 // GenerateTokensAndSetCookies generates jwt token and saves it to the http-only cookie.
 func GenerateTokensAndSetCookies(c echo.Context, user *api.Principal, mode common.ReleaseMode, secret string) error {
 	accessToken, err := generateAccessToken(user, mode, secret)
@@ -175,18 +185,23 @@ func removeUserCookie(c echo.Context) {
 }
 
 func findAccessToken(c echo.Context) (string, error) {
+	authHeader := c.Request().Header.Get("Authorization")
 	if common.HasPrefixes(c.Path(), openAPIPrefix) {
-		authHeader := c.Request().Header.Get("Authorization")
 		if authHeader == "" {
 			return "", nil
 		}
+		return getBearerToken(authHeader)
+	}
 
-		authHeaderParts := strings.Fields(authHeader)
-		if len(authHeaderParts) != 2 || strings.ToLower(authHeaderParts[0]) != "bearer" {
-			return "", common.Errorf(common.Invalid, "Authorization header format must be Bearer {token}")
+	// The long-lived access tokens are also accepted by the internal API, so that the clients don't need to login first.
+	if authHeader != "" {
+		token, err := getBearerToken(authHeader)
+		if err != nil {
+			return "", err
+		}
+		if isLongLivedAccessToken(token) {
+			return token, nil
 		}
-
-		return authHeaderParts[1], nil
 	}
 
 	cookie, err := c.Cookie(accessTokenCookieName)
@@ -197,6 +212,14 @@ func findAccessToken(c echo.Context) (string, error) {
 	return cookie.Value, nil
 }
 
+func getBearerToken(authHeader string) (string, error) {
+	authHeaderParts := strings.Fields(authHeader)
+	if len(authHeaderParts) != 2 || strings.ToLower(authHeaderParts[0]) != "bearer" {
+		return "", common.Errorf(common.Invalid, "Authorization header format must be Bearer {token}")
+	}
+	return authHeaderParts[1], nil
+}
+
 // JWTMiddleware validates the access token.
 // If the access token is about to expire or has expired and the request has a valid refresh token, it
 // will try to generate new access token and refresh token.
@@ -225,6 +248,14 @@ func JWTMiddleware(pathPrefix string, principalStore *store.Store, next echo.Han
 			return echo.NewHTTPError(http.StatusUnauthorized, "Missing access token")
 		}
 
+		// The long-lived access token is opaque, it's validated against the hash stored in the access_token table.
+		if isLongLivedAccessToken(token) {
+			if err := authenticateAccessToken(c, principalStore, token, path); err != nil {
+				return err
+			}
+			return next(c)
+		}
+
 		claims := &Claims{}
 		accessToken, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
 			if t.Method.Alg() != jwt.SigningMethodHS256.Name {
@@ -23,6 +23,9 @@ func (s *Server) registerMemberRoutes(g *echo.Group) {
 		}
 
 		memberCreate.CreatorID = c.Get(getPrincipalIDContextKey()).(int)
+		if httpErr := s.validateRoleAssignment(ctx, memberCreate.Role, api.Owner, api.DBA, api.Developer); httpErr != nil {
+			return httpErr
+		}
 
 		member, err := s.store.CreateMember(ctx, memberCreate)
 		if err != nil {
@@ -109,6 +112,11 @@ func (s *Server) registerMemberRoutes(g *echo.Group) {
 		if err := jsonapi.UnmarshalPayload(c.Request().Body, memberPatch); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch member request").SetInternal(err)
 		}
+		if v := memberPatch.Role; v != nil {
+			if httpErr := s.validateRoleAssignment(ctx, api.Role(*v), api.Owner, api.DBA, api.Developer); httpErr != nil {
+				return httpErr
+			}
+		}
 		// When archiving an owner, make sure there are other active owners.
 		if member.Role == api.Owner && memberPatch.RowStatus != nil && *memberPatch.RowStatus == string(api.Archived) {
 			countResult, err := s.store.CountMemberGroupByRoleAndStatus(ctx)
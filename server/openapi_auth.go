@@ -42,6 +42,19 @@ func (s *Server) openAPIUserLogin(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusUnauthorized, "Incorrect password").SetInternal(err)
 	}
 
+	// The OpenAPI login has no second factor, the users with the two-factor authentication should use the access tokens instead.
+	principalMFA, err := s.store.GetPrincipalMFA(ctx, user.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to authenticate user").SetInternal(err)
+	}
+	mfaRequired, err := s.isMFARequiredForUser(ctx, user)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to authenticate user").SetInternal(err)
+	}
+	if (principalMFA != nil && principalMFA.Enabled) || mfaRequired {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Two-factor authentication is enabled, please use a personal access token instead")
+	}
+
 	// test the status of this user
 	member, err := s.store.GetMemberByPrincipalID(ctx, user.ID)
 	if err != nil {
@@ -12,6 +12,7 @@ import (
 
 	"github.com/bytebase/bytebase/api"
 	metricAPI "github.com/bytebase/bytebase/metric"
+	"github.com/bytebase/bytebase/plugin/advisor"
 	"github.com/bytebase/bytebase/plugin/advisor/catalog"
 	advisorDB "github.com/bytebase/bytebase/plugin/advisor/db"
 	"github.com/bytebase/bytebase/plugin/db"
@@ -52,8 +53,13 @@ type sqlCheckRequestBody struct {
 	EnvironmentName string `json:"environmentName"`
 	Host            string `json:"host"`
 	Port            string `json:"port"`
+	// FilePath is the file path of the statement used in the SARIF or JUnit report.
+	FilePath string `json:"filePath"`
 }
 
+// defaultSQLCheckReportFilePath is the file path in the SQL check report if the request doesn't specify one.
+const defaultSQLCheckReportFilePath = "statement.sql"
+
 // sqlCheckController godoc
 // @Summary  Check the SQL statement.
 // @Description  Parse and check the SQL statement according to the SQL review policy.
@@ -66,11 +72,19 @@ type sqlCheckRequestBody struct {
 // @Param  host             body  string  false  "The instance host."
 // @Param  port             body  string  false  "The instance port."
 // @Param  databaseName     body  string  false  "The database name in the instance."
+// @Param  filePath         body  string  false  "The file path of the statement in the SARIF or JUnit report."
+// @Param  format           query string  false  "The output format. Return the advice list in JSON if not specified."  Enums(sarif, junit)
 // @Success  200  {array}   advisor.Advice
 // @Failure  400  {object}  echo.HTTPError
 // @Failure  500  {object}  echo.HTTPError
 // @Router  /sql/advise  [post].
 func (s *Server) sqlCheckController(c echo.Context) error {
+	format := advisor.ReportFormat(c.QueryParam("format"))
+	if format != "" {
+		if err := advisor.ValidateReportFormat(format); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+	}
 	request := &sqlCheckRequestBody{}
 	body, err := io.ReadAll(c.Request().Body)
 	if err != nil {
@@ -93,6 +107,7 @@ func (s *Server) sqlCheckController(c echo.Context) error {
 	var catalog catalog.Catalog
 	var driver db.Driver
 	var connection *sql.DB
+	projectID := api.UnknownID
 
 	if request.DatabaseName != "" && request.Host != "" && request.Port != "" {
 		database, err := s.findDatabase(ctx, request.Host, request.Port, request.DatabaseName)
@@ -101,6 +116,7 @@ func (s *Server) sqlCheckController(c echo.Context) error {
 		}
 		dbType := database.Instance.Engine
 		databaseType = string(dbType)
+		projectID = database.ProjectID
 		catalog, err = s.store.NewCatalog(ctx, database.ID, dbType)
 		if err != nil {
 			return err
@@ -145,6 +161,7 @@ func (s *Server) sqlCheckController(c echo.Context) error {
 		"utf8mb4",
 		"utf8mb4_general_ci",
 		envList[0].ID,
+		projectID,
 		request.Statement,
 		catalog,
 		connection,
@@ -164,6 +181,22 @@ func (s *Server) sqlCheckController(c echo.Context) error {
 		})
 	}
 
+	switch format {
+	case advisor.ReportFormatSARIF, advisor.ReportFormatJUnit:
+		filePath := request.FilePath
+		if filePath == "" {
+			filePath = defaultSQLCheckReportFilePath
+		}
+		report, err := advisor.GenerateReport(format, map[string][]advisor.Advice{filePath: adviceList})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to generate %s report", format)).SetInternal(err)
+		}
+		if format == advisor.ReportFormatSARIF {
+			return c.Blob(http.StatusOK, "application/sarif+json", []byte(report))
+		}
+		return c.Blob(http.StatusOK, echo.MIMEApplicationXMLCharsetUTF8, []byte(report))
+	}
+
 	return c.JSON(http.StatusOK, adviceList)
 }
 
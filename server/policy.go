@@ -1,6 +1,7 @@
 package server
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/server/component/activity"
 )
 
 // hasAccessToUpdatePolicy checks if user can access to policy control feature.
@@ -94,6 +96,25 @@ func (s *Server) registerPolicyRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to set policy for type %q", pType)).SetInternal(err)
 		}
 
+		bytes, err := json.Marshal(api.ActivityPolicyUpdatePayload{
+			ResourceType: policy.ResourceType,
+			ResourceID:   policy.ResourceID,
+			PolicyType:   policy.Type,
+			Payload:      policy.Payload,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to construct activity payload").SetInternal(err)
+		}
+		if _, err := s.ActivityManager.CreateActivity(ctx, &api.ActivityCreate{
+			CreatorID:   policyUpsert.UpdaterID,
+			ContainerID: policy.ID,
+			Type:        api.ActivityPolicyUpdate,
+			Level:       api.ActivityInfo,
+			Payload:     string(bytes),
+		}, &activity.Metadata{}); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create activity after setting policy: %d", policy.ID)).SetInternal(err)
+		}
+
 		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
 		if err := jsonapi.MarshalPayload(c.Response().Writer, policy); err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal create set policy response").SetInternal(err)
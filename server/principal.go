@@ -147,4 +147,49 @@ func (s *Server) registerPrincipalRoutes(g *echo.Group) {
 		}
 		return nil
 	})
+
+	g.GET("/principal/:principalID/notification-preference", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("principalID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("principalID"))).SetInternal(err)
+		}
+
+		preference, err := s.store.GetNotificationPreference(ctx, id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch notification preference of principal ID: %v", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, preference); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal notification preference response: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.PATCH("/principal/:principalID/notification-preference", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("principalID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("principalID"))).SetInternal(err)
+		}
+
+		preferenceUpsert := &api.NotificationPreferenceUpsert{
+			PrincipalID: id,
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, preferenceUpsert); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch notification preference request").SetInternal(err)
+		}
+
+		preference, err := s.store.UpsertNotificationPreference(ctx, preferenceUpsert)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to patch notification preference of principal ID: %v", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, preference); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal notification preference response: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
 }
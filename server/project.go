@@ -21,6 +21,8 @@ import (
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/common/log"
 	vcsPlugin "github.com/bytebase/bytebase/plugin/vcs"
+	"github.com/bytebase/bytebase/plugin/vcs/azure"
+	"github.com/bytebase/bytebase/plugin/vcs/bitbucket"
 	"github.com/bytebase/bytebase/plugin/vcs/github"
 	"github.com/bytebase/bytebase/plugin/vcs/gitlab"
 	"github.com/bytebase/bytebase/server/utils"
@@ -264,6 +266,10 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed create linked repository request: %s", err.Error()))
 		}
 
+		if err := s.validateBranchEnvironmentSetting(ctx, &repositoryCreate.BranchEnvironmentSetting); err != nil {
+			return err
+		}
+
 		vcs, err := s.store.GetVCSByID(ctx, repositoryCreate.VCSID)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find VCS for creating repository: %d", repositoryCreate.VCSID)).SetInternal(err)
@@ -271,6 +277,11 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 		if vcs == nil {
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("VCS not found with ID: %d", repositoryCreate.VCSID))
 		}
+		if repositoryCreate.SchemaWriteBackType != "" {
+			if err := validateSchemaWriteBackType(repositoryCreate.SchemaWriteBackType, vcs.Type); err != nil {
+				return err
+			}
+		}
 
 		// When the branch names doesn't contain wildcards, we should make sure the branch exists in the repo.
 		if !hasWildcard {
@@ -361,6 +372,12 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusBadRequest, "SQL review CI is already enabled")
 		}
 
+		switch repository.VCS.Type {
+		case vcsPlugin.GitHubCom, vcsPlugin.GitHubEnterprise, vcsPlugin.GitLabSelfHost:
+		default:
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("SQL review CI is not supported for VCS type %s", repository.VCS.Type))
+		}
+
 		pullRequest, err := s.setupVCSSQLReviewCI(ctx, repository)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create SQL review CI").SetInternal(err)
@@ -387,6 +404,95 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 		return nil
 	})
 
+	// The webhook delivery log of the repository, which lists the received VCS events and their outcomes.
+	g.GET("/project/:projectID/repository/:repositoryID/webhook-delivery", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+		repositoryID, err := strconv.Atoi(c.Param("repositoryID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Repository ID is not a number: %s", c.Param("repositoryID"))).SetInternal(err)
+		}
+
+		repository, err := s.store.GetRepository(ctx, &api.RepositoryFind{
+			ID:        &repositoryID,
+			ProjectID: &projectID,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find repository %d in project %d", repositoryID, projectID)).SetInternal(err)
+		}
+		if repository == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Cannot found repository %d in project %d", repositoryID, projectID))
+		}
+
+		deliveryFind := &api.RepositoryWebhookDeliveryFind{
+			RepositoryID: &repositoryID,
+		}
+		if limitStr := c.QueryParam("limit"); limitStr != "" {
+			limit, err := strconv.Atoi(limitStr)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter limit is not a number: %s", limitStr)).SetInternal(err)
+			}
+			deliveryFind.Limit = &limit
+		}
+		deliveryList, err := s.store.FindRepositoryWebhookDelivery(ctx, deliveryFind)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch webhook delivery list for repository %d", repositoryID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, deliveryList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal webhook delivery list response for repository %d", repositoryID)).SetInternal(err)
+		}
+		return nil
+	})
+
+	// Import the existing migration files in the repository, which are not processed by the webhook since they are pushed
+	// before the repository is linked.
+	g.POST("/project/:projectID/repository/:repositoryID/import", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+		repositoryID, err := strconv.Atoi(c.Param("repositoryID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Repository ID is not a number: %s", c.Param("repositoryID"))).SetInternal(err)
+		}
+
+		repository, err := s.store.GetRepository(ctx, &api.RepositoryFind{
+			ID:        &repositoryID,
+			ProjectID: &projectID,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find repository %d in project %d", repositoryID, projectID)).SetInternal(err)
+		}
+		if repository == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Cannot found repository %d in project %d", repositoryID, projectID))
+		}
+
+		repositoryImport := &api.RepositoryImport{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, repositoryImport); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed import repository request").SetInternal(err)
+		}
+		if repositoryImport.Action == "" {
+			repositoryImport.Action = api.RepositoryImportActionReport
+		}
+
+		response, err := s.importRepositoryMigration(ctx, repository, repositoryImport, c.Get(getPrincipalIDContextKey()).(int))
+		if err != nil {
+			return err
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, response); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal import response for repository %d", repositoryID)).SetInternal(err)
+		}
+		return nil
+	})
+
 	// Requires a separate API to return the repository, we do this because
 	// 1. repository also contains project, which would cause circular dependency when composing it.
 	// 2. repository info is only needed when fetching a particular project by id, thus it's unnecessary to include it in the project list response.
@@ -451,6 +557,12 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 			}
 		}
 
+		if repoPatch.BranchEnvironmentSetting != nil {
+			if err := s.validateBranchEnvironmentSetting(ctx, repoPatch.BranchEnvironmentSetting); err != nil {
+				return err
+			}
+		}
+
 		// Remove enclosing /
 		if repoPatch.BaseDirectory != nil {
 			baseDir := strings.Trim(*repoPatch.BaseDirectory, "/")
@@ -497,6 +609,11 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 		if vcs == nil {
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("VCS not found with ID: %d", repo.VCSID))
 		}
+		if repoPatch.SchemaWriteBackType != nil {
+			if err := validateSchemaWriteBackType(*repoPatch.SchemaWriteBackType, vcs.Type); err != nil {
+				return err
+			}
+		}
 
 		// When the branch names doesn't contain wildcards, we should make sure the branch exists in the repo.
 		if !hasWildcard {
@@ -831,6 +948,14 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 				sheetSource = api.SheetFromGitLabSelfHost
 			case vcsPlugin.GitHubCom:
 				sheetSource = api.SheetFromGitHubCom
+			case vcsPlugin.GitHubEnterprise:
+				sheetSource = api.SheetFromGitHubEnterprise
+			case vcsPlugin.BitbucketCloud:
+				sheetSource = api.SheetFromBitbucketCloud
+			case vcsPlugin.BitbucketDataCenter:
+				sheetSource = api.SheetFromBitbucketDataCenter
+			case vcsPlugin.AzureDevOps:
+				sheetSource = api.SheetFromAzureDevOps
 			}
 			vscSheetType := api.SheetForSQL
 			sheetFind := &api.SheetFind{
@@ -911,7 +1036,7 @@ func (s *Server) setupVCSSQLReviewCI(ctx context.Context, repository *api.Reposi
 	sqlReviewEndpoint := fmt.Sprintf("%s/hook/sql-review/%s", s.profile.ExternalURL, repository.WebhookEndpointID)
 
 	switch repository.VCS.Type {
-	case vcsPlugin.GitHubCom:
+	case vcsPlugin.GitHubCom, vcsPlugin.GitHubEnterprise:
 		if err := s.setupVCSSQLReviewCIForGitHub(ctx, repository, branch, sqlReviewEndpoint); err != nil {
 			return nil, err
 		}
@@ -987,7 +1112,7 @@ func (s *Server) setupVCSSQLReviewBranch(ctx context.Context, repository *api.Re
 
 // setupVCSSQLReviewCIForGitHub will create the pull request in GitHub to setup SQL review action.
 func (s *Server) setupVCSSQLReviewCIForGitHub(ctx context.Context, repository *api.Repository, branch *vcsPlugin.BranchInfo, sqlReviewEndpoint string) error {
-	sqlReviewConfig := github.SetupSQLReviewCI(sqlReviewEndpoint)
+	sqlReviewConfig := github.SetupSQLReviewCI(repository.VCS.Type, sqlReviewEndpoint)
 	fileLastCommitID := ""
 
 	fileMeta, err := vcsPlugin.Get(repository.VCS.Type, vcsPlugin.ProviderConfig{}).ReadFileMeta(
@@ -1166,52 +1291,142 @@ func (s *Server) createOrUpdateVCSSQLReviewFileForGitLab(
 	)
 }
 
+// validateSchemaWriteBackType validates the way to write back the latest schema. Writing back by pull requests
+// is only supported for GitHub and GitLab.
+func validateSchemaWriteBackType(writeBackType api.SchemaWriteBackType, vcsType vcsPlugin.Type) error {
+	switch writeBackType {
+	case api.SchemaWriteBackCommit:
+		return nil
+	case api.SchemaWriteBackPullRequest:
+		switch vcsType {
+		case vcsPlugin.GitHubCom, vcsPlugin.GitHubEnterprise, vcsPlugin.GitLabSelfHost:
+			return nil
+		default:
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Writing back the latest schema by pull requests is not supported for VCS type %s", vcsType))
+		}
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid schema write-back type %q", writeBackType))
+	}
+}
+
+// validateBranchEnvironmentSetting validates the branch-to-environment rules of the repository,
+// and makes sure the environments exist.
+func (s *Server) validateBranchEnvironmentSetting(ctx context.Context, setting *api.BranchEnvironmentSetting) error {
+	if err := setting.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid branch environment setting: %s", err.Error()))
+	}
+	for _, rule := range setting.RuleList {
+		environment, err := s.store.GetEnvironmentByID(ctx, rule.EnvironmentID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find environment %d", rule.EnvironmentID)).SetInternal(err)
+		}
+		if environment == nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Environment %d of branch filter %q not found", rule.EnvironmentID, rule.BranchFilter))
+		}
+	}
+	return nil
+}
+
 func (s *Server) createVCSWebhook(ctx context.Context, vcsType vcsPlugin.Type, webhookEndpointID, secretToken, accessToken, instanceURL, externalRepoID string) (string, error) {
 	// Create a new webhook and retrieve the created webhook ID
+	webhookCreatePayload, err := getVCSWebhookPayload(vcsType, s.profile.ExternalURL, webhookEndpointID, secretToken)
+	if err != nil {
+		return "", err
+	}
+	webhookID, err := vcsPlugin.Get(vcsType, vcsPlugin.ProviderConfig{}).CreateWebhook(
+		ctx,
+		common.OauthContext{
+			AccessToken: accessToken,
+			// We use refreshTokenNoop() because the repository isn't created yet.
+			Refresher: refreshTokenNoop(),
+		},
+		instanceURL,
+		externalRepoID,
+		webhookCreatePayload,
+	)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create webhook")
+	}
+	return webhookID, nil
+}
+
+// getVCSWebhookPayload returns the payload to create or update the repository webhook, which subscribes to the push
+// events, and the pull request events if the VCS supports the pull request driven migrations.
+func getVCSWebhookPayload(vcsType vcsPlugin.Type, webhookURLHost, webhookEndpointID, secretToken string) ([]byte, error) {
 	var webhookCreatePayload []byte
 	var err error
 	switch vcsType {
 	case vcsPlugin.GitLabSelfHost:
 		webhookCreate := gitlab.WebhookCreate{
-			URL:                   fmt.Sprintf("%s/hook/gitlab/%s", s.profile.ExternalURL, webhookEndpointID),
+			URL:                   fmt.Sprintf("%s/hook/gitlab/%s", webhookURLHost, webhookEndpointID),
 			SecretToken:           secretToken,
 			PushEvents:            true,
+			MergeRequestsEvents:   true,
 			EnableSSLVerification: false, // TODO(tianzhou): This is set to false, be lax to not enable_ssl_verification
 		}
 		webhookCreatePayload, err = json.Marshal(webhookCreate)
 		if err != nil {
-			return "", errors.Wrap(err, "failed to marshal request body for creating webhook")
+			return nil, errors.Wrap(err, "failed to marshal request body for creating webhook")
 		}
-	case vcsPlugin.GitHubCom:
+	case vcsPlugin.GitHubCom, vcsPlugin.GitHubEnterprise:
 		webhookPost := github.WebhookCreateOrUpdate{
 			Config: github.WebhookConfig{
-				URL:         fmt.Sprintf("%s/hook/github/%s", s.profile.ExternalURL, webhookEndpointID),
+				URL:         fmt.Sprintf("%s/hook/github/%s", webhookURLHost, webhookEndpointID),
 				ContentType: "json",
 				Secret:      secretToken,
 				InsecureSSL: 1, // TODO: Allow user to specify this value through api.RepositoryCreate
 			},
-			Events: []string{"push"},
+			Events: []string{string(github.WebhookPush), string(github.WebhookPullRequest)},
 		}
 		webhookCreatePayload, err = json.Marshal(webhookPost)
 		if err != nil {
-			return "", errors.Wrap(err, "failed to marshal request body for creating webhook")
+			return nil, errors.Wrap(err, "failed to marshal request body for creating webhook")
+		}
+	case vcsPlugin.BitbucketCloud:
+		webhookCreate := bitbucket.CloudWebhookCreateOrUpdate{
+			Description: "Bytebase GitOps",
+			URL:         fmt.Sprintf("%s/hook/bitbucket/%s", webhookURLHost, webhookEndpointID),
+			Active:      true,
+			Secret:      secretToken,
+			Events:      []string{string(bitbucket.WebhookRepoPush)},
+		}
+		webhookCreatePayload, err = json.Marshal(webhookCreate)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal request body for creating webhook")
+		}
+	case vcsPlugin.BitbucketDataCenter:
+		webhookCreate := bitbucket.DataCenterWebhookCreateOrUpdate{
+			Name:   "Bytebase GitOps",
+			URL:    fmt.Sprintf("%s/hook/bitbucket/%s", webhookURLHost, webhookEndpointID),
+			Events: []string{string(bitbucket.WebhookRepoRefsChanged)},
+			Active: true,
+			Configuration: bitbucket.DataCenterWebhookConfiguration{
+				Secret: secretToken,
+			},
+		}
+		webhookCreatePayload, err = json.Marshal(webhookCreate)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal request body for creating webhook")
+		}
+	case vcsPlugin.AzureDevOps:
+		// The project and repository GUIDs of the publisher inputs are filled by the provider.
+		webhookCreate := azure.WebhookCreateOrUpdate{
+			PublisherID:      "tfs",
+			EventType:        "git.push",
+			ResourceVersion:  "1.0",
+			ConsumerID:       "webHooks",
+			ConsumerActionID: "httpRequest",
+			ConsumerInputs: azure.WebhookConsumerInputs{
+				URL:         fmt.Sprintf("%s/hook/azure/%s", webhookURLHost, webhookEndpointID),
+				HTTPHeaders: fmt.Sprintf("%s:%s", azure.WebhookSecretHeader, secretToken),
+			},
+		}
+		webhookCreatePayload, err = json.Marshal(webhookCreate)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal request body for creating webhook")
 		}
 	}
-	webhookID, err := vcsPlugin.Get(vcsType, vcsPlugin.ProviderConfig{}).CreateWebhook(
-		ctx,
-		common.OauthContext{
-			AccessToken: accessToken,
-			// We use refreshTokenNoop() because the repository isn't created yet.
-			Refresher: refreshTokenNoop(),
-		},
-		instanceURL,
-		externalRepoID,
-		webhookCreatePayload,
-	)
-	if err != nil {
-		return "", errors.Wrap(err, "failed to create webhook")
-	}
-	return webhookID, nil
+	return webhookCreatePayload, nil
 }
 
 // refreshToken is a no-op token refresher. It should be used when the repository isn't created yet.
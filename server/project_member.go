@@ -126,6 +126,14 @@ func (s *Server) registerProjectMemberRoutes(g *echo.Group) {
 			roleProvider = api.ProjectRoleProviderGitLabSelfHost
 		case vcsPlugin.GitHubCom:
 			roleProvider = api.ProjectRoleProviderGitHubCom
+		case vcsPlugin.GitHubEnterprise:
+			roleProvider = api.ProjectRoleProviderGitHubEnterprise
+		case vcsPlugin.BitbucketCloud:
+			roleProvider = api.ProjectRoleProviderBitbucketCloud
+		case vcsPlugin.BitbucketDataCenter:
+			roleProvider = api.ProjectRoleProviderBitbucketDataCenter
+		case vcsPlugin.AzureDevOps:
+			roleProvider = api.ProjectRoleProviderAzureDevOps
 		default:
 			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Unrecognized VCS type %q", vcs.Type))
 		}
@@ -250,6 +258,9 @@ func (s *Server) registerProjectMemberRoutes(g *echo.Group) {
 		if err := jsonapi.UnmarshalPayload(c.Request().Body, projectMemberCreate); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create project membership request").SetInternal(err)
 		}
+		if httpErr := s.validateRoleAssignment(ctx, api.Role(projectMemberCreate.Role), api.Owner, api.Developer); httpErr != nil {
+			return httpErr
+		}
 
 		projectMember, err := s.store.CreateProjectMember(ctx, projectMemberCreate)
 		if err != nil {
@@ -313,6 +324,11 @@ func (s *Server) registerProjectMemberRoutes(g *echo.Group) {
 		if err := jsonapi.UnmarshalPayload(c.Request().Body, projectMemberPatch); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed change project membership").SetInternal(err)
 		}
+		if v := projectMemberPatch.Role; v != nil {
+			if httpErr := s.validateRoleAssignment(ctx, api.Role(*v), api.Owner, api.Developer); httpErr != nil {
+				return httpErr
+			}
+		}
 
 		projectMember, err := s.store.PatchProjectMember(ctx, projectMemberPatch)
 		if err != nil {
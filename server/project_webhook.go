@@ -190,6 +190,7 @@ func (s *Server) registerProjectWebhookRoutes(g *echo.Group) {
 			webhook.Type,
 			webhookPlugin.Context{
 				URL:          webhook.URL,
+				Secret:       webhook.Secret,
 				Level:        webhookPlugin.WebhookInfo,
 				ActivityType: string(api.ActivityIssueCreate),
 				Title:        fmt.Sprintf("Test webhook %q", webhook.Name),
@@ -213,4 +214,93 @@ func (s *Server) registerProjectWebhookRoutes(g *echo.Group) {
 		}
 		return nil
 	})
+
+	g.GET("/project/:projectID/webhook/:webhookID/delivery", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		webhook, err := s.getProjectWebhookFromParam(c)
+		if err != nil {
+			return err
+		}
+
+		limit := 50
+		if limitStr := c.QueryParam("limit"); limitStr != "" {
+			if limit, err = strconv.Atoi(limitStr); err != nil || limit <= 0 {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter limit is not a positive number: %s", limitStr)).SetInternal(err)
+			}
+		}
+		deliveryList, err := s.store.FindProjectWebhookDelivery(ctx, &api.ProjectWebhookDeliveryFind{
+			ProjectWebhookID: &webhook.ID,
+			Limit:            &limit,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch delivery list for project webhook ID: %d", webhook.ID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, deliveryList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal project webhook delivery list response: %d", webhook.ID)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.POST("/project/:projectID/webhook/:webhookID/delivery/:deliveryID/redeliver", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		webhook, err := s.getProjectWebhookFromParam(c)
+		if err != nil {
+			return err
+		}
+
+		deliveryID, err := strconv.Atoi(c.Param("deliveryID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Delivery ID is not a number: %s", c.Param("deliveryID"))).SetInternal(err)
+		}
+		delivery, err := s.store.GetProjectWebhookDeliveryByID(ctx, deliveryID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch project webhook delivery ID: %d", deliveryID)).SetInternal(err)
+		}
+		if delivery == nil || delivery.ProjectWebhookID != webhook.ID {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Project webhook delivery ID not found: %d", deliveryID))
+		}
+		if delivery.Status == api.ProjectWebhookDeliveryPending || delivery.Status == api.ProjectWebhookDeliverySending {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project webhook delivery %d is still pending", deliveryID))
+		}
+
+		// Redelivery creates a new delivery with the same payload, so that the history of the original one is kept.
+		redelivery, err := s.store.CreateProjectWebhookDelivery(ctx, &api.ProjectWebhookDeliveryCreate{
+			ProjectWebhookID: webhook.ID,
+			ActivityType:     delivery.ActivityType,
+			Payload:          delivery.Payload,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to redeliver project webhook delivery ID: %d", deliveryID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, redelivery); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal project webhook redelivery response: %d", deliveryID)).SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// getProjectWebhookFromParam returns the project webhook of the projectID and webhookID parameters, or an echo error.
+func (s *Server) getProjectWebhookFromParam(c echo.Context) (*api.ProjectWebhook, error) {
+	ctx := c.Request().Context()
+	projectID, err := strconv.Atoi(c.Param("projectID"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+	}
+	id, err := strconv.Atoi(c.Param("webhookID"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project webhook ID is not a number: %s", c.Param("webhookID"))).SetInternal(err)
+	}
+
+	webhook, err := s.store.GetProjectWebhookByID(ctx, id)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch project webhook ID: %v", id)).SetInternal(err)
+	}
+	if webhook == nil || webhook.ProjectID != projectID {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Project webhook ID not found: %d", id))
+	}
+	return webhook, nil
 }
@@ -0,0 +1,354 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/vcs"
+	"github.com/bytebase/bytebase/server/utils"
+)
+
+// importFile is a migration file in the repository to be imported for a database.
+type importFile struct {
+	fileInfo fileInfo
+	database *api.Database
+	result   *api.RepositoryImportFile
+}
+
+// importRepositoryMigration scans the migration files in the repository at the branch, and compares them against
+// the migration history of the databases. It's used to onboard an existing repository with historical migration
+// files, since only the files in the new pushes are processed.
+//  1. For the REPORT action, it only reports the status of the files.
+//  2. For the BASELINE action, it creates issues to establish the baseline at the latest unapplied version of each database.
+//  3. For the ISSUE action, it creates issues to apply the pending files.
+func (s *Server) importRepositoryMigration(ctx context.Context, repo *api.Repository, repositoryImport *api.RepositoryImport, creatorID int) (*api.RepositoryImportResult, error) {
+	ref := repositoryImport.Ref
+	if ref == "" {
+		ref = repo.BranchFilter
+	}
+	if strings.Contains(ref, "*") {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Ref must be specified because branch filter %q has wildcard", repo.BranchFilter))
+	}
+	switch repositoryImport.Action {
+	case api.RepositoryImportActionReport, api.RepositoryImportActionBaseline, api.RepositoryImportActionIssue:
+	default:
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid import action %q", repositoryImport.Action))
+	}
+
+	provider := vcs.Get(repo.VCS.Type, vcs.ProviderConfig{})
+	oauthContext := common.OauthContext{
+		ClientID:     repo.VCS.ApplicationID,
+		ClientSecret: repo.VCS.Secret,
+		AccessToken:  repo.AccessToken,
+		RefreshToken: repo.RefreshToken,
+		Refresher:    utils.RefreshToken(ctx, s.store, repo.WebURL),
+	}
+	branch, err := provider.GetBranch(ctx, oauthContext, repo.VCS.InstanceURL, repo.ExternalID, ref)
+	if err != nil {
+		if common.ErrorCode(err) == common.NotFound {
+			return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Branch %q not found in repository %s", ref, repo.Name)).SetInternal(err)
+		}
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get branch %q", ref)).SetInternal(err)
+	}
+	commit, err := provider.FetchCommitByID(ctx, oauthContext, repo.VCS.InstanceURL, repo.ExternalID, branch.LastCommitID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch commit %s", branch.LastCommitID)).SetInternal(err)
+	}
+	nodeList, err := provider.FetchRepositoryFileList(ctx, oauthContext, repo.VCS.InstanceURL, repo.ExternalID, ref, repo.BaseDirectory)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch repository file list of branch %q", ref)).SetInternal(err)
+	}
+
+	// The files are imported as if they are pushed to the branch in a single commit.
+	pushEvent := vcs.PushEvent{
+		VCSType:            repo.VCS.Type,
+		BaseDirectory:      repo.BaseDirectory,
+		Ref:                "refs/heads/" + ref,
+		After:              commit.ID,
+		RepositoryID:       repo.ExternalID,
+		RepositoryURL:      repo.WebURL,
+		RepositoryFullPath: repo.FullPath,
+		AuthorName:         commit.AuthorName,
+		CommitList:         []vcs.Commit{*commit},
+	}
+	var branchEnvironment *api.Environment
+	if repo.Project.TenantMode != api.TenantModeTenant {
+		if branchEnvironment, err = s.getBranchEnvironment(ctx, repo, pushEvent.Ref); err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to get the environment of the branch").SetInternal(err)
+		}
+	}
+
+	result := &api.RepositoryImportResult{
+		Ref:      ref,
+		CommitID: commit.ID,
+	}
+	importFileList, err := s.getImportFileList(ctx, repo, nodeList, branchEnvironment)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range importFileList {
+		result.FileList = append(result.FileList, *file.result)
+	}
+
+	var issueList []*api.Issue
+	switch repositoryImport.Action {
+	case api.RepositoryImportActionBaseline:
+		issueList, err = s.createImportBaselineIssue(ctx, repo, pushEvent, creatorID, importFileList)
+	case api.RepositoryImportActionIssue:
+		issueList, err = s.createImportMigrationIssue(ctx, repo, pushEvent, creatorID, importFileList)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, issue := range issueList {
+		result.IssueIDList = append(result.IssueIDList, issue.ID)
+	}
+	return result, nil
+}
+
+// getImportFileList parses the migration files in the repository, and gets their status for each database.
+// The list is sorted by the database name, environment and version.
+func (s *Server) getImportFileList(ctx context.Context, repo *api.Repository, nodeList []*vcs.RepositoryTreeNode, branchEnvironment *api.Environment) ([]*importFile, error) {
+	var importFileList []*importFile
+	historyListMap := make(map[int][]*db.MigrationHistory)
+	for _, node := range nodeList {
+		item := vcs.DistinctFileItem{
+			FileName: node.Path,
+			ItemType: vcs.FileItemTypeAdded,
+			IsYAML:   strings.HasSuffix(node.Path, ".yml"),
+		}
+		migrationInfo, fType, _, err := getFileInfo(item, []*api.Repository{repo})
+		if err != nil || fType != migrationFileType {
+			// It's not a migration file.
+			continue
+		}
+		info := fileInfo{
+			item:          item,
+			migrationInfo: migrationInfo,
+			fType:         fType,
+			repository:    repo,
+		}
+		ignored := func(message string) {
+			importFileList = append(importFileList, &importFile{
+				fileInfo: info,
+				result: &api.RepositoryImportFile{
+					FilePath:     node.Path,
+					Version:      migrationInfo.Version,
+					Status:       api.RepositoryImportFileIgnored,
+					DatabaseName: migrationInfo.Database,
+					Message:      message,
+				},
+			})
+		}
+		if item.IsYAML {
+			ignored("The YAML migration file is not supported to import")
+			continue
+		}
+		if migrationInfo.Undo {
+			ignored("The undo script is not a migration to apply")
+			continue
+		}
+		if branchEnvironment != nil {
+			if migrationInfo.Environment != "" && !strings.EqualFold(migrationInfo.Environment, branchEnvironment.Name) {
+				ignored(fmt.Sprintf("The environment %q in the file path mismatches environment %q of the branch", migrationInfo.Environment, branchEnvironment.Name))
+				continue
+			}
+			migrationInfo.Environment = branchEnvironment.Name
+		}
+		databaseList, err := s.findProjectDatabases(ctx, repo.ProjectID, migrationInfo.Database, migrationInfo.Environment)
+		if err != nil {
+			ignored(err.Error())
+			continue
+		}
+
+		for _, database := range databaseList {
+			historyList, ok := historyListMap[database.ID]
+			if !ok {
+				historyList, err = s.findDatabaseMigrationHistoryList(ctx, database)
+				if err != nil {
+					return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find migration history of database %q", database.Name)).SetInternal(err)
+				}
+				historyListMap[database.ID] = historyList
+			}
+			status, message := getImportFileStatus(historyList, migrationInfo.Version)
+			importFileList = append(importFileList, &importFile{
+				fileInfo: info,
+				database: database,
+				result: &api.RepositoryImportFile{
+					FilePath:        node.Path,
+					Version:         migrationInfo.Version,
+					Status:          status,
+					DatabaseID:      database.ID,
+					DatabaseName:    database.Name,
+					EnvironmentName: database.Instance.Environment.Name,
+					Message:         message,
+				},
+			})
+		}
+	}
+
+	sort.SliceStable(importFileList, func(i, j int) bool {
+		a, b := importFileList[i].result, importFileList[j].result
+		if a.DatabaseName != b.DatabaseName {
+			return a.DatabaseName < b.DatabaseName
+		}
+		if a.EnvironmentName != b.EnvironmentName {
+			return a.EnvironmentName < b.EnvironmentName
+		}
+		return a.Version < b.Version
+	})
+	return importFileList, nil
+}
+
+// findDatabaseMigrationHistoryList finds the migration history of the database.
+func (s *Server) findDatabaseMigrationHistoryList(ctx context.Context, database *api.Database) ([]*db.MigrationHistory, error) {
+	driver, err := s.dbFactory.GetAdminDatabaseDriver(ctx, database.Instance, "" /* databaseName */)
+	if err != nil {
+		return nil, err
+	}
+	defer driver.Close(ctx)
+	return driver.FindMigrationHistoryList(ctx, &db.MigrationHistoryFind{Database: &database.Name})
+}
+
+// getImportFileStatus returns the status of the version against the migration history of the database.
+// Same as the migration executor, a version lower than the largest version applied since the last baseline
+// cannot be applied any more.
+func getImportFileStatus(historyList []*db.MigrationHistory, version string) (api.RepositoryImportFileStatus, string) {
+	baselineSequence := -1
+	for _, history := range historyList {
+		if history.Type == db.Baseline && history.Status == db.Done && history.Sequence > baselineSequence {
+			baselineSequence = history.Sequence
+		}
+	}
+	largestVersion := ""
+	for _, history := range historyList {
+		if history.Version == version {
+			switch history.Status {
+			case db.Done:
+				return api.RepositoryImportFileApplied, ""
+			default:
+				return api.RepositoryImportFileFailed, fmt.Sprintf("The migration of version %s is %s", version, strings.ToLower(string(history.Status)))
+			}
+		}
+		if history.Status == db.Done && history.Sequence >= baselineSequence && history.Version > largestVersion {
+			largestVersion = history.Version
+		}
+	}
+	if largestVersion != "" && largestVersion >= version {
+		return api.RepositoryImportFileOutOfOrder, fmt.Sprintf("The database has already applied version %s which >= %s", largestVersion, version)
+	}
+	return api.RepositoryImportFilePending, ""
+}
+
+// createImportBaselineIssue creates an issue for each database name to establish the baseline of the databases
+// at the latest unapplied version.
+func (s *Server) createImportBaselineIssue(ctx context.Context, repo *api.Repository, pushEvent vcs.PushEvent, creatorID int, importFileList []*importFile) ([]*api.Issue, error) {
+	var databaseNameList []string
+	databaseMap := make(map[string][]*api.Database)
+	versionMap := make(map[int]string)
+	for _, file := range importFileList {
+		if file.result.Status != api.RepositoryImportFilePending && file.result.Status != api.RepositoryImportFileOutOfOrder {
+			continue
+		}
+		database := file.database
+		version, ok := versionMap[database.ID]
+		if !ok {
+			if _, ok := databaseMap[database.Name]; !ok {
+				databaseNameList = append(databaseNameList, database.Name)
+			}
+			databaseMap[database.Name] = append(databaseMap[database.Name], database)
+		}
+		if file.result.Version > version {
+			versionMap[database.ID] = file.result.Version
+		}
+	}
+
+	var issueList []*api.Issue
+	for _, databaseName := range databaseNameList {
+		var migrationDetailList []*api.MigrationDetail
+		var versionList []string
+		for _, database := range databaseMap[databaseName] {
+			migrationDetailList = append(migrationDetailList, &api.MigrationDetail{
+				MigrationType: db.Baseline,
+				DatabaseID:    database.ID,
+				SchemaVersion: versionMap[database.ID],
+			})
+			versionList = append(versionList, fmt.Sprintf("%s: %s", database.Instance.Environment.Name, versionMap[database.ID]))
+		}
+		issueName := fmt.Sprintf(issueNameTemplate, databaseName, "Establish baseline")
+		issueDescription := fmt.Sprintf("Establish baseline by importing VCS branch %q at commit %s:\n\n%s\n", strings.TrimPrefix(pushEvent.Ref, "refs/heads/"), pushEvent.After, strings.Join(versionList, "\n"))
+		issue, err := s.createIssueFromMigrationDetailList(ctx, issueName, issueDescription, pushEvent, creatorID, repo, migrationDetailList)
+		if err != nil {
+			return nil, err
+		}
+		issueList = append(issueList, issue)
+	}
+	return issueList, nil
+}
+
+// createImportMigrationIssue creates an issue for each database name to apply the pending files.
+func (s *Server) createImportMigrationIssue(ctx context.Context, repo *api.Repository, pushEvent vcs.PushEvent, creatorID int, importFileList []*importFile) ([]*api.Issue, error) {
+	var databaseNameList []string
+	fileMap := make(map[string][]*importFile)
+	for _, file := range importFileList {
+		if file.result.Status != api.RepositoryImportFilePending {
+			continue
+		}
+		databaseName := file.database.Name
+		if _, ok := fileMap[databaseName]; !ok {
+			databaseNameList = append(databaseNameList, databaseName)
+		}
+		fileMap[databaseName] = append(fileMap[databaseName], file)
+	}
+
+	var issueList []*api.Issue
+	contentMap := make(map[string]string)
+	for _, databaseName := range databaseNameList {
+		fileList := fileMap[databaseName]
+		// The files are applied in the order of the version.
+		sort.SliceStable(fileList, func(i, j int) bool {
+			return fileList[i].result.Version < fileList[j].result.Version
+		})
+
+		var migrationDetailList []*api.MigrationDetail
+		var filePathList []string
+		migrateType := "Change data"
+		for _, file := range fileList {
+			content, ok := contentMap[file.result.FilePath]
+			if !ok {
+				var err error
+				content, err = s.readFileContent(ctx, pushEvent, repo, file.result.FilePath)
+				if err != nil {
+					return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to read file %q", file.result.FilePath)).SetInternal(err)
+				}
+				contentMap[file.result.FilePath] = content
+				filePathList = append(filePathList, strings.TrimPrefix(file.result.FilePath, repo.BaseDirectory+"/"))
+			}
+			if file.fileInfo.migrationInfo.Type == db.Migrate {
+				migrateType = "Alter schema"
+			}
+			migrationDetailList = append(migrationDetailList, &api.MigrationDetail{
+				MigrationType: file.fileInfo.migrationInfo.Type,
+				DatabaseID:    file.database.ID,
+				Statement:     content,
+				SchemaVersion: file.result.Version,
+			})
+		}
+		issueName := fmt.Sprintf(issueNameTemplate, databaseName, migrateType)
+		issueDescription := fmt.Sprintf("By importing VCS files:\n\n%s\n", strings.Join(filePathList, "\n"))
+		issue, err := s.createIssueFromMigrationDetailList(ctx, issueName, issueDescription, pushEvent, creatorID, repo, migrationDetailList)
+		if err != nil {
+			return nil, err
+		}
+		issueList = append(issueList, issue)
+	}
+	return issueList, nil
+}
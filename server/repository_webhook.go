@@ -0,0 +1,95 @@
+package server
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+	vcsPlugin "github.com/bytebase/bytebase/plugin/vcs"
+	"github.com/bytebase/bytebase/server/utils"
+)
+
+// backfillRepositoryWebhook patches the webhooks of the repositories created before the pull request driven migrations,
+// so that they subscribe to the pull request events as the newly created ones.
+// It runs once after the upgrade, and retries on the next start if any repository fails to be patched.
+func (s *Server) backfillRepositoryWebhook(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	settingName := api.SettingRepositoryWebhookPullRequest
+	setting, err := s.store.GetSetting(ctx, &api.SettingFind{Name: &settingName})
+	if err != nil {
+		log.Error("Failed to get the repository webhook backfill setting", zap.Error(err))
+		return
+	}
+	if setting == nil || setting.Value == "true" {
+		return
+	}
+
+	repoList, err := s.store.FindRepository(ctx, &api.RepositoryFind{})
+	if err != nil {
+		log.Error("Failed to find repositories to backfill the webhooks", zap.Error(err))
+		return
+	}
+	failed := false
+	for _, repo := range repoList {
+		if err := s.patchRepositoryWebhook(ctx, repo); err != nil {
+			log.Warn("Failed to patch the repository webhook to subscribe to the pull request events",
+				zap.Int("repository_id", repo.ID),
+				zap.String("repository", repo.WebURL),
+				zap.Error(err))
+			failed = true
+		}
+	}
+	if failed {
+		return
+	}
+
+	if _, err := s.store.PatchSetting(ctx, &api.SettingPatch{
+		UpdaterID: api.SystemBotID,
+		Name:      settingName,
+		Value:     "true",
+	}); err != nil {
+		log.Error("Failed to patch the repository webhook backfill setting", zap.Error(err))
+	}
+}
+
+// patchRepositoryWebhook patches the repository webhook with the same payload as the newly created one.
+// It only patches the webhooks of the VCS supporting the pull request driven migrations.
+func (s *Server) patchRepositoryWebhook(ctx context.Context, repo *api.Repository) error {
+	switch repo.VCS.Type {
+	case vcsPlugin.GitLabSelfHost, vcsPlugin.GitHubCom, vcsPlugin.GitHubEnterprise:
+	default:
+		return nil
+	}
+
+	payload, err := getVCSWebhookPayload(repo.VCS.Type, repo.WebhookURLHost, repo.WebhookEndpointID, repo.WebhookSecretToken)
+	if err != nil {
+		return err
+	}
+	if err := vcsPlugin.Get(repo.VCS.Type, vcsPlugin.ProviderConfig{}).PatchWebhook(
+		ctx,
+		common.OauthContext{
+			ClientID:     repo.VCS.ApplicationID,
+			ClientSecret: repo.VCS.Secret,
+			AccessToken:  repo.AccessToken,
+			RefreshToken: repo.RefreshToken,
+			Refresher:    utils.RefreshToken(ctx, s.store, repo.WebURL),
+		},
+		repo.VCS.InstanceURL,
+		repo.ExternalID,
+		repo.ExternalWebhookID,
+		payload,
+	); err != nil {
+		// The webhook has been deleted in the VCS, there is nothing to patch.
+		if common.ErrorCode(err) == common.NotFound {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to patch webhook %s", repo.ExternalWebhookID)
+	}
+	return nil
+}
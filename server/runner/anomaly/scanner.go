@@ -17,6 +17,7 @@ import (
 	"github.com/bytebase/bytebase/common/log"
 	enterpriseAPI "github.com/bytebase/bytebase/enterprise/api"
 	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/server/component/activity"
 	"github.com/bytebase/bytebase/server/component/dbfactory"
 	"github.com/bytebase/bytebase/store"
 )
@@ -27,19 +28,21 @@ const (
 )
 
 // NewScanner creates a anomaly scanner.
-func NewScanner(store *store.Store, dbFactory *dbfactory.DBFactory, licenseService enterpriseAPI.LicenseService) *Scanner {
+func NewScanner(store *store.Store, dbFactory *dbfactory.DBFactory, licenseService enterpriseAPI.LicenseService, activityManager *activity.Manager) *Scanner {
 	return &Scanner{
-		store:          store,
-		dbFactory:      dbFactory,
-		licenseService: licenseService,
+		store:           store,
+		dbFactory:       dbFactory,
+		licenseService:  licenseService,
+		activityManager: activityManager,
 	}
 }
 
 // Scanner is the anomaly scanner.
 type Scanner struct {
-	store          *store.Store
-	dbFactory      *dbfactory.DBFactory
-	licenseService enterpriseAPI.LicenseService
+	store           *store.Store
+	dbFactory       *dbfactory.DBFactory
+	licenseService  enterpriseAPI.LicenseService
+	activityManager *activity.Manager
 }
 
 // Run will run the anomaly scanner once.
@@ -156,6 +159,52 @@ func (s *Scanner) Run(ctx context.Context, wg *sync.WaitGroup) {
 	}
 }
 
+// upsertActiveAnomaly upserts the active anomaly, and creates an activity if the anomaly is newly detected,
+// so that the workspace webhooks are not flooded by the anomalies detected in every round.
+func (s *Scanner) upsertActiveAnomaly(ctx context.Context, instance *api.Instance, database *api.Database, upsert *api.AnomalyUpsert) (*api.Anomaly, error) {
+	status := api.Normal
+	activeList, err := s.store.FindAnomaly(ctx, &api.AnomalyFind{
+		RowStatus:  &status,
+		InstanceID: &upsert.InstanceID,
+		DatabaseID: upsert.DatabaseID,
+		Type:       &upsert.Type,
+	})
+	if err != nil {
+		return nil, err
+	}
+	anomaly, err := s.store.UpsertActiveAnomaly(ctx, upsert)
+	if err != nil {
+		return nil, err
+	}
+	if len(activeList) > 0 {
+		return anomaly, nil
+	}
+
+	activityPayload := api.ActivityAnomalyCreatePayload{
+		AnomalyType:  anomaly.Type,
+		InstanceID:   instance.ID,
+		InstanceName: instance.Name,
+	}
+	if database != nil {
+		activityPayload.DatabaseID = database.ID
+		activityPayload.DatabaseName = database.Name
+	}
+	payload, err := json.Marshal(activityPayload)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal activity payload for anomaly %d", anomaly.ID)
+	}
+	if _, err := s.activityManager.CreateActivity(ctx, &api.ActivityCreate{
+		CreatorID:   api.SystemBotID,
+		ContainerID: anomaly.ID,
+		Type:        api.ActivityAnomalyCreate,
+		Level:       api.ActivityWarn,
+		Payload:     string(payload),
+	}, &activity.Metadata{}); err != nil {
+		return nil, errors.Wrapf(err, "failed to create activity for anomaly %d", anomaly.ID)
+	}
+	return anomaly, nil
+}
+
 func (s *Scanner) checkInstanceAnomaly(ctx context.Context, instance *api.Instance) {
 	driver, err := s.dbFactory.GetAdminDatabaseDriver(ctx, instance, "" /* databaseName */)
 
@@ -171,7 +220,7 @@ func (s *Scanner) checkInstanceAnomaly(ctx context.Context, instance *api.Instan
 				zap.String("type", string(api.AnomalyInstanceConnection)),
 				zap.Error(err))
 		} else {
-			if _, err = s.store.UpsertActiveAnomaly(ctx, &api.AnomalyUpsert{
+			if _, err = s.upsertActiveAnomaly(ctx, instance, nil /* database */, &api.AnomalyUpsert{
 				CreatorID:  api.SystemBotID,
 				InstanceID: instance.ID,
 				Type:       api.AnomalyInstanceConnection,
@@ -208,7 +257,7 @@ func (s *Scanner) checkInstanceAnomaly(ctx context.Context, instance *api.Instan
 				zap.Error(err))
 		} else {
 			if setup {
-				if _, err = s.store.UpsertActiveAnomaly(ctx, &api.AnomalyUpsert{
+				if _, err = s.upsertActiveAnomaly(ctx, instance, nil /* database */, &api.AnomalyUpsert{
 					CreatorID:  api.SystemBotID,
 					InstanceID: instance.ID,
 					Type:       api.AnomalyInstanceMigrationSchema,
@@ -250,7 +299,7 @@ func (s *Scanner) checkDatabaseAnomaly(ctx context.Context, instance *api.Instan
 				zap.String("type", string(api.AnomalyDatabaseConnection)),
 				zap.Error(err))
 		} else {
-			if _, err = s.store.UpsertActiveAnomaly(ctx, &api.AnomalyUpsert{
+			if _, err = s.upsertActiveAnomaly(ctx, instance, database, &api.AnomalyUpsert{
 				CreatorID:  api.SystemBotID,
 				InstanceID: instance.ID,
 				DatabaseID: &database.ID,
@@ -339,7 +388,7 @@ func (s *Scanner) checkDatabaseAnomaly(ctx context.Context, instance *api.Instan
 						zap.String("type", string(api.AnomalyDatabaseSchemaDrift)),
 						zap.Error(err))
 				} else {
-					if _, err = s.store.UpsertActiveAnomaly(ctx, &api.AnomalyUpsert{
+					if _, err = s.upsertActiveAnomaly(ctx, instance, database, &api.AnomalyUpsert{
 						CreatorID:  api.SystemBotID,
 						InstanceID: instance.ID,
 						DatabaseID: &database.ID,
@@ -419,7 +468,7 @@ func (s *Scanner) checkBackupAnomaly(ctx context.Context, instance *api.Instance
 					zap.String("type", string(api.AnomalyDatabaseBackupPolicyViolation)),
 					zap.Error(err))
 			} else {
-				if _, err = s.store.UpsertActiveAnomaly(ctx, &api.AnomalyUpsert{
+				if _, err = s.upsertActiveAnomaly(ctx, instance, database, &api.AnomalyUpsert{
 					CreatorID:  api.SystemBotID,
 					InstanceID: instance.ID,
 					DatabaseID: &database.ID,
@@ -502,7 +551,7 @@ func (s *Scanner) checkBackupAnomaly(ctx context.Context, instance *api.Instance
 					zap.String("type", string(api.AnomalyDatabaseBackupMissing)),
 					zap.Error(err))
 			} else {
-				if _, err = s.store.UpsertActiveAnomaly(ctx, &api.AnomalyUpsert{
+				if _, err = s.upsertActiveAnomaly(ctx, instance, database, &api.AnomalyUpsert{
 					CreatorID:  api.SystemBotID,
 					InstanceID: instance.ID,
 					DatabaseID: &database.ID,
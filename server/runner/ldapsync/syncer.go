@@ -0,0 +1,353 @@
+// Package ldapsync is the runner syncing the LDAP users from the directory.
+package ldapsync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+	enterpriseAPI "github.com/bytebase/bytebase/enterprise/api"
+	"github.com/bytebase/bytebase/plugin/idp/ldap"
+	"github.com/bytebase/bytebase/server/component/activity"
+	"github.com/bytebase/bytebase/server/component/sso"
+	"github.com/bytebase/bytebase/store"
+)
+
+const (
+	runnerInterval = time.Duration(1) * time.Minute
+	// defaultSyncInterval is the sync interval if it's not set in the LDAP setting.
+	defaultSyncInterval = time.Duration(1) * time.Hour
+)
+
+// NewSyncer creates an LDAP directory syncer.
+func NewSyncer(store *store.Store, activityManager *activity.Manager, licenseService enterpriseAPI.LicenseService) *Syncer {
+	return &Syncer{
+		store:           store,
+		activityManager: activityManager,
+		licenseService:  licenseService,
+	}
+}
+
+// Syncer is the runner syncing the workspace roles and project memberships of the LDAP users from the directory groups,
+// and deactivating the LDAP users removed from the directory.
+type Syncer struct {
+	store           *store.Store
+	activityManager *activity.Manager
+	licenseService  enterpriseAPI.LicenseService
+	lastSyncTime    time.Time
+}
+
+// Run starts the LDAP directory syncer.
+func (s *Syncer) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(runnerInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("LDAP directory syncer started and will run every %v", runnerInterval))
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.syncIfDue(ctx, time.Now()); err != nil && !errors.Is(err, context.Canceled) {
+				log.Error("Failed to sync LDAP directory", zap.Error(err))
+			}
+		case <-ctx.Done(): // if cancel() execute
+			return
+		}
+	}
+}
+
+func (s *Syncer) syncIfDue(ctx context.Context, now time.Time) error {
+	if !s.licenseService.IsFeatureEnabled(api.Feature3rdPartyAuth) {
+		return nil
+	}
+	value, err := GetAuthLDAPSetting(ctx, s.store)
+	if err != nil {
+		return err
+	}
+	if value == nil || !value.Enabled {
+		return nil
+	}
+	syncInterval := defaultSyncInterval
+	if value.SyncIntervalSeconds > 0 {
+		syncInterval = time.Duration(value.SyncIntervalSeconds) * time.Second
+	}
+	if now.Sub(s.lastSyncTime) < syncInterval {
+		return nil
+	}
+	s.lastSyncTime = now
+	return s.syncDirectory(ctx, value)
+}
+
+func (s *Syncer) syncDirectory(ctx context.Context, value *api.SettingAuthLDAPValue) error {
+	userInfoList, err := GetProvider(value).ListUsers()
+	if err != nil {
+		return err
+	}
+	// An empty result is more likely caused by a wrong base DN or user filter than an empty directory,
+	// so we don't deactivate all the LDAP users in this case.
+	if len(userInfoList) == 0 {
+		return errors.New("no user found in the LDAP directory, please check the base DN and the user filter")
+	}
+	userInfoMap := make(map[string]*ldap.UserInfo)
+	for _, userInfo := range userInfoList {
+		userInfoMap[strings.ToLower(userInfo.DN)] = userInfo
+	}
+
+	ldapUserList, err := s.store.FindLDAPUser(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to find LDAP users")
+	}
+	for _, ldapUser := range ldapUserList {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		principal, err := s.store.GetPrincipalByID(ctx, ldapUser.PrincipalID)
+		if err != nil {
+			log.Error("Failed to get principal", zap.Int("principal_id", ldapUser.PrincipalID), zap.Error(err))
+			continue
+		}
+		if principal == nil {
+			continue
+		}
+		userInfo, ok := userInfoMap[strings.ToLower(ldapUser.DN)]
+		if !ok {
+			if err := s.deactivateMember(ctx, principal); err != nil {
+				log.Error("Failed to deactivate the user removed from the LDAP directory", zap.String("email", principal.Email), zap.Error(err))
+			}
+			continue
+		}
+		if err := SyncUser(ctx, s.store, s.activityManager, value, principal, userInfo); err != nil {
+			log.Error("Failed to sync LDAP user", zap.String("email", principal.Email), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// deactivateMember deactivates the member of the principal, the only remaining owner in the workspace is never deactivated.
+func (s *Syncer) deactivateMember(ctx context.Context, principal *api.Principal) error {
+	member, err := s.store.GetMemberByPrincipalID(ctx, principal.ID)
+	if err != nil {
+		return err
+	}
+	if member == nil || member.RowStatus == api.Archived {
+		return nil
+	}
+	if member.Role == api.Owner {
+		isLastOwner, err := sso.IsLastOwner(ctx, s.store)
+		if err != nil {
+			return err
+		}
+		if isLastOwner {
+			log.Warn("Skip deactivating the only remaining owner removed from the LDAP directory", zap.String("email", principal.Email))
+			return nil
+		}
+	}
+
+	rowStatus := string(api.Archived)
+	updatedMember, err := s.store.PatchMember(ctx, &api.MemberPatch{
+		ID:        member.ID,
+		UpdaterID: api.SystemBotID,
+		RowStatus: &rowStatus,
+	})
+	if err != nil {
+		return err
+	}
+	bytes, err := json.Marshal(api.ActivityMemberActivateDeactivatePayload{
+		PrincipalID:    updatedMember.PrincipalID,
+		PrincipalName:  principal.Name,
+		PrincipalEmail: principal.Email,
+		Role:           member.Role,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := s.activityManager.CreateActivity(ctx, &api.ActivityCreate{
+		CreatorID:   api.SystemBotID,
+		ContainerID: updatedMember.ID,
+		Type:        api.ActivityMemberDeactivate,
+		Level:       api.ActivityInfo,
+		Payload:     string(bytes),
+	}, &activity.Metadata{}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// SyncUser links the principal to the directory entry, and syncs the workspace role and the project memberships from the groups.
+func SyncUser(ctx context.Context, store *store.Store, activityManager *activity.Manager, value *api.SettingAuthLDAPValue, principal *api.Principal, userInfo *ldap.UserInfo) error {
+	if _, err := store.UpsertLDAPUser(ctx, &api.LDAPUserUpsert{
+		PrincipalID: principal.ID,
+		DN:          userInfo.DN,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to link principal %d to %q", principal.ID, userInfo.DN)
+	}
+	if role, ok := getWorkspaceRole(value, userInfo.GroupList); ok {
+		if err := sso.SyncMemberRole(ctx, store, activityManager, principal, role); err != nil {
+			return errors.Wrapf(err, "failed to sync role of principal %d", principal.ID)
+		}
+	}
+	return syncProjectMember(ctx, store, activityManager, value.GroupProjectMapping, principal, userInfo.GroupList)
+}
+
+// getWorkspaceRole returns the workspace role synced from the groups, and false if the workspace role isn't managed by
+// the directory, i.e. the group role mapping is empty. The user falls back to the default role if none of its groups
+// is mapped, so that the user removed from the mapped groups is downgraded.
+func getWorkspaceRole(value *api.SettingAuthLDAPValue, groupList []string) (api.Role, bool) {
+	if len(value.GroupRoleMapping) == 0 {
+		return "", false
+	}
+	if role, ok := sso.GetRoleFromGroupList(groupList, value.GroupRoleMapping); ok {
+		return role, true
+	}
+	if value.DefaultRole != "" {
+		return value.DefaultRole, true
+	}
+	return api.Developer, true
+}
+
+// syncProjectMember syncs the memberships of the projects in the mapping. For these projects, the membership of the LDAP user
+// is fully managed by the directory groups, i.e. the user is removed from the project if none of its groups is mapped.
+// The projects whose members are synced from the VCS are skipped.
+func syncProjectMember(ctx context.Context, store *store.Store, activityManager *activity.Manager, mappingList []api.LDAPGroupProjectMapping, principal *api.Principal, groupList []string) error {
+	projectIDList, projectRoleMap := getProjectRoleMap(mappingList, groupList)
+	for _, projectID := range projectIDList {
+		project, err := store.GetProjectByID(ctx, projectID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get project %d", projectID)
+		}
+		if project == nil || project.RowStatus == api.Archived || project.RoleProvider != api.ProjectRoleProviderBytebase {
+			continue
+		}
+		projectID := projectID
+		roleProvider := api.ProjectRoleProviderBytebase
+		member, err := store.GetProjectMember(ctx, &api.ProjectMemberFind{
+			ProjectID:    &projectID,
+			PrincipalID:  &principal.ID,
+			RoleProvider: &roleProvider,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to get member of project %d", projectID)
+		}
+
+		role := projectRoleMap[projectID]
+		var activityCreate *api.ActivityCreate
+		switch {
+		case role != "" && member == nil:
+			if _, err := store.CreateProjectMember(ctx, &api.ProjectMemberCreate{
+				CreatorID:    api.SystemBotID,
+				ProjectID:    projectID,
+				Role:         role,
+				PrincipalID:  principal.ID,
+				RoleProvider: roleProvider,
+			}); err != nil {
+				return errors.Wrapf(err, "failed to create member of project %d", projectID)
+			}
+			activityCreate = &api.ActivityCreate{
+				Type: api.ActivityProjectMemberCreate,
+				Comment: fmt.Sprintf("Granted %s to %s (%s) (synced from LDAP).",
+					principal.Name, principal.Email, role),
+			}
+		case role != "" && member.Role != string(role):
+			roleString := string(role)
+			if _, err := store.PatchProjectMember(ctx, &api.ProjectMemberPatch{
+				ID:        member.ID,
+				UpdaterID: api.SystemBotID,
+				Role:      &roleString,
+			}); err != nil {
+				return errors.Wrapf(err, "failed to patch member of project %d", projectID)
+			}
+			activityCreate = &api.ActivityCreate{
+				Type: api.ActivityProjectMemberRoleUpdate,
+				Comment: fmt.Sprintf("Changed %s (%s) from %s to %s (synced from LDAP).",
+					principal.Name, principal.Email, member.Role, role),
+			}
+		case role == "" && member != nil:
+			if err := store.DeleteProjectMember(ctx, &api.ProjectMemberDelete{
+				ID:        member.ID,
+				ProjectID: projectID,
+				DeleterID: api.SystemBotID,
+			}); err != nil {
+				return errors.Wrapf(err, "failed to delete member of project %d", projectID)
+			}
+			activityCreate = &api.ActivityCreate{
+				Type: api.ActivityProjectMemberDelete,
+				Comment: fmt.Sprintf("Revoked %s from %s (%s). Because this member does not belong to the mapped LDAP groups.",
+					member.Role, principal.Name, principal.Email),
+			}
+		default:
+			continue
+		}
+
+		activityCreate.CreatorID = api.SystemBotID
+		activityCreate.ContainerID = projectID
+		activityCreate.Level = api.ActivityInfo
+		if _, err := activityManager.CreateActivity(ctx, activityCreate, &activity.Metadata{}); err != nil {
+			log.Warn("Failed to create project activity after syncing LDAP member",
+				zap.Int("project_id", projectID),
+				zap.Int("principal_id", principal.ID),
+				zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// getProjectRoleMap returns the projects in the mapping, and the project roles mapped from the groups.
+// The role is empty if none of the groups is mapped to the project, and the owner wins if the groups are mapped to both roles.
+func getProjectRoleMap(mappingList []api.LDAPGroupProjectMapping, groupList []string) ([]int, map[int]common.ProjectRole) {
+	var projectIDList []int
+	projectRoleMap := make(map[int]common.ProjectRole)
+	for _, mapping := range mappingList {
+		if _, ok := projectRoleMap[mapping.ProjectID]; !ok {
+			projectIDList = append(projectIDList, mapping.ProjectID)
+			projectRoleMap[mapping.ProjectID] = ""
+		}
+		for _, group := range groupList {
+			if group == mapping.Group && projectRoleMap[mapping.ProjectID] != common.ProjectOwner {
+				projectRoleMap[mapping.ProjectID] = mapping.Role
+			}
+		}
+	}
+	return projectIDList, projectRoleMap
+}
+
+// GetAuthLDAPSetting returns the LDAP setting value, or nil if it's not configured.
+func GetAuthLDAPSetting(ctx context.Context, store *store.Store) (*api.SettingAuthLDAPValue, error) {
+	settingName := api.SettingAuthLDAP
+	setting, err := store.GetSetting(ctx, &api.SettingFind{Name: &settingName})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get setting %q", settingName)
+	}
+	if setting == nil || setting.Value == "" {
+		return nil, nil
+	}
+	var value api.SettingAuthLDAPValue
+	if err := json.Unmarshal([]byte(setting.Value), &value); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal setting %q", settingName)
+	}
+	return &value, nil
+}
+
+// GetProvider returns the LDAP directory provider of the setting.
+func GetProvider(value *api.SettingAuthLDAPValue) *ldap.Provider {
+	return ldap.NewProvider(ldap.Config{
+		Host:               value.Host,
+		Port:               value.Port,
+		SecurityProtocol:   ldap.SecurityProtocol(value.SecurityProtocol),
+		InsecureSkipVerify: value.InsecureSkipVerify,
+		BindDN:             value.BindDN,
+		BindPassword:       value.BindPassword,
+		BaseDN:             value.BaseDN,
+		UserFilter:         value.UserFilter,
+		EmailAttribute:     value.EmailAttribute,
+		NameAttribute:      value.NameAttribute,
+		GroupAttribute:     value.GroupAttribute,
+	})
+}
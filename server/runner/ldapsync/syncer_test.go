@@ -0,0 +1,59 @@
+package ldapsync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+func TestGetProjectRoleMap(t *testing.T) {
+	a := require.New(t)
+	mappingList := []api.LDAPGroupProjectMapping{
+		{Group: "dev", ProjectID: 102, Role: common.ProjectDeveloper},
+		{Group: "dba", ProjectID: 101, Role: common.ProjectOwner},
+		{Group: "dev", ProjectID: 101, Role: common.ProjectDeveloper},
+		{Group: "qa", ProjectID: 103, Role: common.ProjectDeveloper},
+	}
+
+	projectIDList, projectRoleMap := getProjectRoleMap(mappingList, []string{"dba", "dev"})
+	a.Equal([]int{102, 101, 103}, projectIDList)
+	a.Equal(map[int]common.ProjectRole{
+		101: common.ProjectOwner,
+		102: common.ProjectDeveloper,
+		103: "",
+	}, projectRoleMap)
+
+	_, projectRoleMap = getProjectRoleMap(mappingList, nil)
+	a.Equal(map[int]common.ProjectRole{101: "", 102: "", 103: ""}, projectRoleMap)
+}
+
+func TestGetWorkspaceRole(t *testing.T) {
+	a := require.New(t)
+	value := &api.SettingAuthLDAPValue{
+		GroupRoleMapping: []api.SSOGroupRoleMapping{
+			{Group: "admins", Role: api.Owner},
+			{Group: "dbas", Role: api.DBA},
+		},
+	}
+
+	role, ok := getWorkspaceRole(value, []string{"dbas", "admins"})
+	a.True(ok)
+	a.Equal(api.Owner, role)
+
+	// The former owner removed from the mapped groups is downgraded to the default role.
+	role, ok = getWorkspaceRole(value, []string{"engineers"})
+	a.True(ok)
+	a.Equal(api.Developer, role)
+
+	value.DefaultRole = api.DBA
+	role, ok = getWorkspaceRole(value, nil)
+	a.True(ok)
+	a.Equal(api.DBA, role)
+
+	// The workspace role isn't managed by the directory without the group role mapping.
+	_, ok = getWorkspaceRole(&api.SettingAuthLDAPValue{DefaultRole: api.DBA}, []string{"dbas"})
+	a.False(ok)
+}
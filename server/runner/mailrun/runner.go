@@ -0,0 +1,245 @@
+// Package mailrun is the runner for sending the email notifications.
+package mailrun
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/mail"
+	"github.com/bytebase/bytebase/store"
+)
+
+const (
+	runnerInterval = time.Duration(10) * time.Second
+	// defaultDigestInterval is the digest interval if it's not set in the mail delivery setting.
+	defaultDigestInterval = time.Duration(1) * time.Hour
+	// maxAttemptCount is the maximum number of attempts to send the notifications before they're marked as FAILED.
+	maxAttemptCount = 5
+	// initialRetryDelay is the delay before the first retry, and it's doubled for each subsequent retry.
+	initialRetryDelay = time.Duration(1) * time.Minute
+	maxRetryDelay     = time.Duration(1) * time.Hour
+)
+
+// NewRunner creates an email notification runner.
+func NewRunner(store *store.Store) *Runner {
+	return &Runner{
+		store: store,
+	}
+}
+
+// Runner is the runner sending the pending email notifications with the SMTP server in the mail delivery setting.
+// The notifications of the users preferring digest are batched and sent once per digest interval.
+type Runner struct {
+	store *store.Store
+}
+
+// Run starts the email notification runner.
+func (r *Runner) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(runnerInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("Email notification runner started and will run every %v", runnerInterval))
+	for {
+		select {
+		case <-ticker.C:
+			if err := r.sendPending(ctx, time.Now()); err != nil && !errors.Is(err, context.Canceled) {
+				log.Error("Failed to send email notifications", zap.Error(err))
+			}
+		case <-ctx.Done(): // if cancel() execute
+			return
+		}
+	}
+}
+
+func (r *Runner) sendPending(ctx context.Context, now time.Time) error {
+	value, err := GetMailDeliverySetting(ctx, r.store)
+	if err != nil {
+		return err
+	}
+	if value == nil || !value.Enabled {
+		return nil
+	}
+	digestInterval := defaultDigestInterval
+	if value.DigestIntervalSeconds > 0 {
+		digestInterval = time.Duration(value.DigestIntervalSeconds) * time.Second
+	}
+
+	status := api.EmailNotificationPending
+	notificationList, err := r.store.FindEmailNotification(ctx, &api.EmailNotificationFind{Status: &status})
+	if err != nil {
+		return errors.Wrap(err, "failed to find pending email notifications")
+	}
+	for _, group := range groupByReceiver(notificationList) {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		receiverID := group[0].ReceiverID
+		preference, err := r.store.GetNotificationPreference(ctx, receiverID)
+		if err != nil {
+			log.Error("Failed to get notification preference", zap.Int("receiver_id", receiverID), zap.Error(err))
+			continue
+		}
+		if preference.EmailDigest && !isDigestDue(group, digestInterval, now) {
+			continue
+		}
+		if !isRetryDue(group, now) {
+			continue
+		}
+		if err := r.send(ctx, value, group, now); err != nil {
+			log.Error("Failed to send email notifications", zap.Int("receiver_id", receiverID), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// send sends the notifications of a receiver in one email, and marks them as SENT, or PENDING to retry with
+// backoff, or FAILED after all the attempts.
+func (r *Runner) send(ctx context.Context, value *api.SettingMailDeliveryValue, notificationList []*api.EmailNotification, now time.Time) error {
+	sendErr := r.sendEmail(ctx, value, notificationList)
+	if sendErr != nil {
+		log.Warn("Failed to send email notification",
+			zap.Int("receiver_id", notificationList[0].ReceiverID),
+			zap.Int("count", len(notificationList)),
+			zap.Error(sendErr))
+	}
+	patch := getNotificationPatch(notificationList, sendErr, now)
+	if err := r.store.PatchEmailNotification(ctx, patch); err != nil {
+		return errors.Wrapf(err, "failed to patch email notifications %v", patch.IDList)
+	}
+	return nil
+}
+
+func (r *Runner) sendEmail(ctx context.Context, value *api.SettingMailDeliveryValue, notificationList []*api.EmailNotification) error {
+	receiver, err := r.store.GetPrincipalByID(ctx, notificationList[0].ReceiverID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get receiver %d", notificationList[0].ReceiverID)
+	}
+	if receiver == nil || receiver.Email == "" {
+		return errors.Errorf("receiver %d has no email", notificationList[0].ReceiverID)
+	}
+	subject, body, err := renderEmail(notificationList)
+	if err != nil {
+		return err
+	}
+	return mail.Send(GetMailConfig(value), mail.Message{
+		To:       []string{receiver.Email},
+		Subject:  subject,
+		HTMLBody: body,
+	})
+}
+
+// GetMailDeliverySetting returns the mail delivery setting, or nil if it's not set.
+func GetMailDeliverySetting(ctx context.Context, store *store.Store) (*api.SettingMailDeliveryValue, error) {
+	settingName := api.SettingMailDelivery
+	setting, err := store.GetSetting(ctx, &api.SettingFind{Name: &settingName})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get setting %q", settingName)
+	}
+	if setting == nil || setting.Value == "" {
+		return nil, nil
+	}
+	var value api.SettingMailDeliveryValue
+	if err := json.Unmarshal([]byte(setting.Value), &value); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal setting %q", settingName)
+	}
+	return &value, nil
+}
+
+// GetMailConfig returns the SMTP config of the mail delivery setting.
+func GetMailConfig(value *api.SettingMailDeliveryValue) mail.Config {
+	encryption := mail.Encryption(value.SMTPEncryption)
+	if encryption == "" {
+		encryption = mail.EncryptionNone
+	}
+	return mail.Config{
+		Host:       value.SMTPHost,
+		Port:       value.SMTPPort,
+		Username:   value.SMTPUsername,
+		Password:   value.SMTPPassword,
+		From:       value.SMTPFrom,
+		Encryption: encryption,
+	}
+}
+
+// groupByReceiver groups the notifications by the receivers, in the order of the first notification of each receiver.
+func groupByReceiver(notificationList []*api.EmailNotification) [][]*api.EmailNotification {
+	var groupList [][]*api.EmailNotification
+	index := make(map[int]int)
+	for _, notification := range notificationList {
+		i, ok := index[notification.ReceiverID]
+		if !ok {
+			i = len(groupList)
+			index[notification.ReceiverID] = i
+			groupList = append(groupList, nil)
+		}
+		groupList[i] = append(groupList[i], notification)
+	}
+	return groupList
+}
+
+// isDigestDue returns true if the earliest notification has waited for the digest interval.
+func isDigestDue(notificationList []*api.EmailNotification, digestInterval time.Duration, now time.Time) bool {
+	earliest := notificationList[0].CreatedTs
+	for _, notification := range notificationList {
+		if notification.CreatedTs < earliest {
+			earliest = notification.CreatedTs
+		}
+	}
+	return now.Unix()-earliest >= int64(digestInterval/time.Second)
+}
+
+// isRetryDue returns true if none of the notifications is waiting for the next retry.
+func isRetryDue(notificationList []*api.EmailNotification, now time.Time) bool {
+	for _, notification := range notificationList {
+		if notification.NextAttemptTs > now.Unix() {
+			return false
+		}
+	}
+	return true
+}
+
+// getNotificationPatch returns the patch of the notifications sent in one email after an attempt with the error sendErr.
+// The notifications share the attempt count, which is the largest one of them.
+func getNotificationPatch(notificationList []*api.EmailNotification, sendErr error, now time.Time) *api.EmailNotificationPatch {
+	patch := &api.EmailNotificationPatch{}
+	for _, notification := range notificationList {
+		patch.IDList = append(patch.IDList, notification.ID)
+		if notification.AttemptCount > patch.AttemptCount {
+			patch.AttemptCount = notification.AttemptCount
+		}
+	}
+	if sendErr == nil {
+		patch.Status = api.EmailNotificationSent
+		return patch
+	}
+
+	patch.AttemptCount++
+	patch.Error = sendErr.Error()
+	if patch.AttemptCount >= maxAttemptCount {
+		patch.Status = api.EmailNotificationFailed
+		return patch
+	}
+	patch.Status = api.EmailNotificationPending
+	patch.NextAttemptTs = now.Add(getRetryDelay(patch.AttemptCount)).Unix()
+	return patch
+}
+
+// getRetryDelay returns the delay before the next attempt after attemptCount attempts.
+func getRetryDelay(attemptCount int) time.Duration {
+	delay := initialRetryDelay
+	for i := 1; i < attemptCount; i++ {
+		delay *= 2
+		if delay >= maxRetryDelay {
+			return maxRetryDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,95 @@
+package mailrun
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestGroupByReceiver(t *testing.T) {
+	a := require.New(t)
+	notificationList := []*api.EmailNotification{
+		{ID: 101, ReceiverID: 2},
+		{ID: 102, ReceiverID: 1},
+		{ID: 103, ReceiverID: 2},
+	}
+	groupList := groupByReceiver(notificationList)
+	a.Len(groupList, 2)
+	a.Equal([]*api.EmailNotification{notificationList[0], notificationList[2]}, groupList[0])
+	a.Equal([]*api.EmailNotification{notificationList[1]}, groupList[1])
+}
+
+func TestIsDigestDue(t *testing.T) {
+	a := require.New(t)
+	now := time.Unix(10000, 0)
+	notificationList := []*api.EmailNotification{{CreatedTs: 9000}, {CreatedTs: 6400}}
+	a.True(isDigestDue(notificationList, time.Hour, now))
+	a.False(isDigestDue(notificationList[:1], time.Hour, now))
+}
+
+func TestIsRetryDue(t *testing.T) {
+	a := require.New(t)
+	now := time.Unix(1000, 0)
+	a.True(isRetryDue([]*api.EmailNotification{{NextAttemptTs: 0}, {NextAttemptTs: 1000}}, now))
+	a.False(isRetryDue([]*api.EmailNotification{{NextAttemptTs: 0}, {NextAttemptTs: 1060}}, now))
+}
+
+func TestGetNotificationPatch(t *testing.T) {
+	a := require.New(t)
+	now := time.Unix(1000, 0)
+	notificationList := []*api.EmailNotification{{ID: 101, AttemptCount: 1}, {ID: 102}}
+
+	a.Equal(&api.EmailNotificationPatch{
+		IDList:       []int{101, 102},
+		Status:       api.EmailNotificationSent,
+		AttemptCount: 1,
+	}, getNotificationPatch(notificationList, nil, now))
+
+	a.Equal(&api.EmailNotificationPatch{
+		IDList:        []int{101, 102},
+		Status:        api.EmailNotificationPending,
+		Error:         "connection refused",
+		AttemptCount:  2,
+		NextAttemptTs: 1120,
+	}, getNotificationPatch(notificationList, errors.New("connection refused"), now))
+
+	a.Equal(&api.EmailNotificationPatch{
+		IDList:       []int{101},
+		Status:       api.EmailNotificationFailed,
+		Error:        "connection refused",
+		AttemptCount: maxAttemptCount,
+	}, getNotificationPatch([]*api.EmailNotification{{ID: 101, AttemptCount: maxAttemptCount - 1}}, errors.New("connection refused"), now))
+}
+
+func TestRenderEmail(t *testing.T) {
+	a := require.New(t)
+	subject, body, err := renderEmail([]*api.EmailNotification{
+		{
+			ID:      101,
+			Reason:  api.NotificationReasonAssigned,
+			Payload: `{"title":"Issue created - Add <column>","projectName":"Blog","link":"https://bytebase.example.com/issue/add-column-101","creatorName":"alice","createdTs":1671500000}`,
+		},
+	})
+	a.NoError(err)
+	a.Equal("[Bytebase] Issue created - Add <column>", subject)
+	a.Contains(body, "Issue created - Add &lt;column&gt;")
+	a.Contains(body, "https://bytebase.example.com/issue/add-column-101")
+	a.Contains(body, "you are assigned to the issue")
+	a.Contains(body, "2022-12-20 01:33:20 UTC")
+
+	subject, body, err = renderEmail([]*api.EmailNotification{
+		{ID: 101, Reason: api.NotificationReasonMentioned, Payload: `{"title":"Comment created - Add column"}`},
+		{ID: 102, Reason: api.NotificationReasonApprovalNeeded, Payload: `{"title":"Issue created - Drop table"}`},
+	})
+	a.NoError(err)
+	a.Equal("[Bytebase] 2 new notifications", subject)
+	a.Contains(body, "Comment created - Add column")
+	a.Contains(body, "your approval is needed")
+
+	_, _, err = renderEmail([]*api.EmailNotification{{ID: 101, Payload: "{"}})
+	a.Error(err)
+}
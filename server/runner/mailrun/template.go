@@ -0,0 +1,93 @@
+package mailrun
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+// emailItem is an email notification rendered in the templates.
+type emailItem struct {
+	api.EmailNotificationPayload
+	Reason string
+	Time   string
+}
+
+var issueEmailTemplate = template.Must(template.New("issue").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Helvetica, Arial, sans-serif; color: #1f2937;">
+  <h2 style="font-size: 18px;">{{.Title}}</h2>
+  {{if .ProjectName}}<p style="color: #6b7280;">Project: {{.ProjectName}}</p>{{end}}
+  <p>{{.CreatorName}} · {{.Time}}</p>
+  {{if .Description}}<blockquote style="border-left: 4px solid #e5e7eb; margin: 0; padding-left: 12px; white-space: pre-wrap;">{{.Description}}</blockquote>{{end}}
+  <p><a href="{{.Link}}" style="display: inline-block; padding: 8px 16px; background: #4f46e5; color: #ffffff; text-decoration: none; border-radius: 4px;">View issue</a></p>
+  <hr style="border: none; border-top: 1px solid #e5e7eb;">
+  <p style="font-size: 12px; color: #9ca3af;">You receive this email because {{.Reason}}. You can change the email notification preference in your profile.</p>
+</body>
+</html>`))
+
+var digestEmailTemplate = template.Must(template.New("digest").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Helvetica, Arial, sans-serif; color: #1f2937;">
+  <h2 style="font-size: 18px;">{{len .}} new notifications</h2>
+  <ul style="padding-left: 20px;">
+  {{range .}}
+    <li style="margin-bottom: 12px;">
+      <a href="{{.Link}}">{{.Title}}</a>
+      <div style="font-size: 12px; color: #6b7280;">{{.CreatorName}} · {{.Time}} · {{.Reason}}</div>
+    </li>
+  {{end}}
+  </ul>
+  <hr style="border: none; border-top: 1px solid #e5e7eb;">
+  <p style="font-size: 12px; color: #9ca3af;">You receive this digest because you prefer batching the email notifications. You can change the email notification preference in your profile.</p>
+</body>
+</html>`))
+
+// renderEmail renders the subject and HTML body of the email notifications to a user.
+// A single notification is rendered as an issue email, and multiple ones are rendered as a digest.
+func renderEmail(notificationList []*api.EmailNotification) (string, string, error) {
+	var itemList []*emailItem
+	for _, notification := range notificationList {
+		item := &emailItem{Reason: getReasonText(notification.Reason)}
+		if err := json.Unmarshal([]byte(notification.Payload), &item.EmailNotificationPayload); err != nil {
+			return "", "", errors.Wrapf(err, "failed to unmarshal email notification %d payload", notification.ID)
+		}
+		item.Time = time.Unix(item.CreatedTs, 0).UTC().Format("2006-01-02 15:04:05 UTC")
+		itemList = append(itemList, item)
+	}
+	if len(itemList) == 0 {
+		return "", "", errors.Errorf("no email notification to render")
+	}
+
+	var buf bytes.Buffer
+	if len(itemList) == 1 {
+		if err := issueEmailTemplate.Execute(&buf, itemList[0]); err != nil {
+			return "", "", errors.Wrap(err, "failed to render issue email")
+		}
+		return fmt.Sprintf("[Bytebase] %s", itemList[0].Title), buf.String(), nil
+	}
+	if err := digestEmailTemplate.Execute(&buf, itemList); err != nil {
+		return "", "", errors.Wrap(err, "failed to render digest email")
+	}
+	return fmt.Sprintf("[Bytebase] %d new notifications", len(itemList)), buf.String(), nil
+}
+
+func getReasonText(reason api.NotificationReason) string {
+	switch reason {
+	case api.NotificationReasonApprovalNeeded:
+		return "your approval is needed"
+	case api.NotificationReasonAssigned:
+		return "you are assigned to the issue"
+	case api.NotificationReasonMentioned:
+		return "you are mentioned"
+	case api.NotificationReasonSubscribed:
+		return "you subscribe to the issue"
+	}
+	return string(reason)
+}
@@ -8,19 +8,23 @@ import (
 
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
+	enterpriseAPI "github.com/bytebase/bytebase/enterprise/api"
+	"github.com/bytebase/bytebase/server/component/rbac"
 	"github.com/bytebase/bytebase/store"
 )
 
 // NewLGTMExecutor creates a task check LGTM executor.
-func NewLGTMExecutor(store *store.Store) Executor {
+func NewLGTMExecutor(store *store.Store, licenseService enterpriseAPI.LicenseService) Executor {
 	return &LGTMExecutor{
-		store: store,
+		store:          store,
+		licenseService: licenseService,
 	}
 }
 
 // LGTMExecutor is the task check LGTM executor. It checks if "LGTM" comments are present.
 type LGTMExecutor struct {
-	store *store.Store
+	store          *store.Store
+	licenseService enterpriseAPI.LicenseService
 }
 
 // Run will run the task check LGTM executor once.
@@ -61,7 +65,7 @@ func (e *LGTMExecutor) Run(ctx context.Context, _ *api.TaskCheckRun, task *api.T
 		return nil, common.Wrap(err, common.Internal)
 	}
 
-	ok, err := checkLGTMcomments(ctx, e.store, activityList, issue)
+	ok, err := e.checkLGTMcomments(ctx, activityList, issue)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to check LGTM comments")
 	}
@@ -88,9 +92,9 @@ func (e *LGTMExecutor) Run(ctx context.Context, _ *api.TaskCheckRun, task *api.T
 	}, nil
 }
 
-func checkLGTMcomments(ctx context.Context, store *store.Store, activityList []*api.Activity, issue *api.Issue) (bool, error) {
+func (e *LGTMExecutor) checkLGTMcomments(ctx context.Context, activityList []*api.Activity, issue *api.Issue) (bool, error) {
 	for _, activity := range activityList {
-		ok, err := isCommentLGTM(ctx, store, activity, issue)
+		ok, err := e.isCommentLGTM(ctx, activity, issue)
 		if err != nil {
 			return false, err
 		}
@@ -101,11 +105,13 @@ func checkLGTMcomments(ctx context.Context, store *store.Store, activityList []*
 	return false, nil
 }
 
-func isCommentLGTM(ctx context.Context, store *store.Store, activity *api.Activity, issue *api.Issue) (bool, error) {
+// isCommentLGTM returns true if the comment is an LGTM from the project member allowed by the LGTM setting.
+// The project owner setting accepts the project roles with the approve-issue permission, including the custom roles.
+func (e *LGTMExecutor) isCommentLGTM(ctx context.Context, activity *api.Activity, issue *api.Issue) (bool, error) {
 	if activity.Comment != "LGTM" {
 		return false, nil
 	}
-	member, err := store.GetProjectMember(ctx, &api.ProjectMemberFind{
+	member, err := e.store.GetProjectMember(ctx, &api.ProjectMemberFind{
 		PrincipalID: &activity.CreatorID,
 		ProjectID:   &issue.ProjectID,
 	})
@@ -119,7 +125,7 @@ func isCommentLGTM(ctx context.Context, store *store.Store, activity *api.Activi
 	case api.LGTMValueProjectMember:
 		return true, nil
 	case api.LGTMValueProjectOwner:
-		return member.Role == string(api.Owner), nil
+		return rbac.HasProjectPermission(ctx, e.store, e.licenseService, api.ProjectPermissionApproveIssue, common.ProjectRole(member.Role))
 	}
 	return false, errors.Errorf("unexpected LGTM setting value: %s", issue.Project.LGTMCheckSetting.Value)
 }
@@ -43,7 +43,12 @@ func (e *StatementAdvisorCompositeExecutor) Run(ctx context.Context, taskCheckRu
 		return nil, common.Wrapf(err, common.Invalid, "invalid check statement advise payload")
 	}
 
-	policy, err := e.store.GetNormalSQLReviewPolicy(ctx, &api.PolicyFind{ID: &payload.PolicyID})
+	dbType, err := advisorDB.ConvertToAdvisorDBType(string(payload.DbType))
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := e.store.GetProjectSQLReviewPolicy(ctx, &api.PolicyFind{ID: &payload.PolicyID}, task.Database.ProjectID, dbType)
 	if err != nil {
 		if e, ok := err.(*common.Error); ok && e.Code == common.NotFound {
 			return []api.TaskCheckResult{
@@ -64,11 +69,6 @@ func (e *StatementAdvisorCompositeExecutor) Run(ctx context.Context, taskCheckRu
 		return nil, common.Wrapf(err, common.Internal, "failed to create a catalog")
 	}
 
-	dbType, err := advisorDB.ConvertToAdvisorDBType(string(payload.DbType))
-	if err != nil {
-		return nil, err
-	}
-
 	driver, err := e.dbFactory.GetReadOnlyDatabaseDriver(ctx, task.Instance, task.Database.Name)
 	if err != nil {
 		return nil, err
@@ -104,11 +104,15 @@ func (e *StatementAdvisorCompositeExecutor) Run(ctx context.Context, taskCheckRu
 		}
 
 		result = append(result, api.TaskCheckResult{
-			Status:    status,
-			Namespace: api.AdvisorNamespace,
-			Code:      advice.Code.Int(),
-			Title:     advice.Title,
-			Content:   advice.Content,
+			Status:        status,
+			Namespace:     api.AdvisorNamespace,
+			Code:          advice.Code.Int(),
+			Title:         advice.Title,
+			Content:       advice.Content,
+			Line:          advice.Line,
+			StartPosition: advice.StartPosition,
+			EndPosition:   advice.EndPosition,
+			Excerpt:       advice.Excerpt,
 		})
 	}
 
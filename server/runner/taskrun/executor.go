@@ -285,8 +285,9 @@ func postMigration(ctx context.Context, store *store.Store, activityManager *act
 	// On the presence of schema path template and non-wildcard branch filter, We write back the latest schema after migration for VCS-based projects for
 	// 1) baseline migration for SDL,
 	// 2) all DDL/Ghost migrations.
+	// The pull request write-back targets the pushed branch, so it's allowed for the wildcard branch filter as well.
 	writeBack := false
-	if repo != nil && repo.SchemaPathTemplate != "" && !strings.Contains(repo.BranchFilter, "*") {
+	if repo != nil && repo.SchemaPathTemplate != "" && (!strings.Contains(repo.BranchFilter, "*") || repo.SchemaWriteBackType == api.SchemaWriteBackPullRequest) {
 		if repo.Project.SchemaChangeType == api.ProjectSchemaChangeTypeSDL {
 			if task.Type == api.TaskDatabaseSchemaBaseline {
 				writeBack = true
@@ -350,9 +351,21 @@ func postMigration(ctx context.Context, store *store.Store, activityManager *act
 			bytebaseURL = fmt.Sprintf("%s/issue/%s?stage=%d", profile.ExternalURL, api.IssueSlug(issue), task.StageID)
 		}
 
-		commitID, err := writeBackLatestSchema(ctx, store, repo, vcsPushEvent, mi, repo.BranchFilter, latestSchemaFile, schema, bytebaseURL)
-		if err != nil {
-			return true, nil, err
+		branch := repo.BranchFilter
+		comment := fmt.Sprintf("Committed the latest schema after applying migration version %s to %q.", mi.Version, dbName)
+		var commitID string
+		if repo.SchemaWriteBackType == api.SchemaWriteBackPullRequest {
+			var pullRequest *vcsPlugin.PullRequest
+			commitID, branch, pullRequest, err = writeBackLatestSchemaPullRequest(ctx, store, repo, vcsPushEvent, mi, latestSchemaFile, schema, bytebaseURL)
+			if err != nil {
+				return true, nil, err
+			}
+			comment = fmt.Sprintf("Committed the latest schema after applying migration version %s to %q in pull request %s.", mi.Version, dbName, pullRequest.URL)
+		} else {
+			commitID, err = writeBackLatestSchema(ctx, store, repo, vcsPushEvent, mi, branch, latestSchemaFile, schema, bytebaseURL)
+			if err != nil {
+				return true, nil, err
+			}
 		}
 
 		// Create file commit activity
@@ -361,7 +374,7 @@ func postMigration(ctx context.Context, store *store.Store, activityManager *act
 				TaskID:             task.ID,
 				VCSInstanceURL:     repo.VCS.InstanceURL,
 				RepositoryFullPath: repo.FullPath,
-				Branch:             repo.BranchFilter,
+				Branch:             branch,
 				FilePath:           latestSchemaFile,
 				CommitID:           commitID,
 			})
@@ -379,11 +392,8 @@ func postMigration(ctx context.Context, store *store.Store, activityManager *act
 				ContainerID: task.PipelineID,
 				Type:        api.ActivityPipelineTaskFileCommit,
 				Level:       api.ActivityInfo,
-				Comment: fmt.Sprintf("Committed the latest schema after applying migration version %s to %q.",
-					mi.Version,
-					dbName,
-				),
-				Payload: string(payload),
+				Comment:     comment,
+				Payload:     string(payload),
 			}
 
 			if _, err := activityManager.CreateActivity(ctx, activityCreate, &activity.Metadata{}); err != nil {
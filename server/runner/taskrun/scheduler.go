@@ -21,6 +21,7 @@ import (
 	"github.com/bytebase/bytebase/plugin/db"
 	"github.com/bytebase/bytebase/server/component/activity"
 	"github.com/bytebase/bytebase/server/component/config"
+	"github.com/bytebase/bytebase/server/component/rbac"
 	"github.com/bytebase/bytebase/server/component/state"
 	"github.com/bytebase/bytebase/server/runner/apprun"
 	"github.com/bytebase/bytebase/server/runner/schemasync"
@@ -510,6 +511,14 @@ func (s *Scheduler) CanSchedule(ctx context.Context, task *api.Task) (bool, erro
 		return false, nil
 	}
 
+	held, err := s.isTaskHeld(ctx, task)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to check if task is held")
+	}
+	if held {
+		return false, nil
+	}
+
 	return s.passAllCheck(ctx, task, api.TaskCheckStatusWarn)
 }
 
@@ -517,6 +526,7 @@ func (s *Scheduler) CanSchedule(ctx context.Context, task *api.Task) (bool, erro
 //  1. its required check does not contain error in the latest run.
 //  2. it has no blocking tasks.
 //  3. it has passed the earliest allowed time.
+//  4. its pipeline is not held, e.g. by an open pull request.
 func (s *Scheduler) ScheduleIfNeeded(ctx context.Context, task *api.Task) (*api.Task, error) {
 	schedule, err := s.CanSchedule(ctx, task)
 	if err != nil {
@@ -555,8 +565,25 @@ func (s *Scheduler) isTaskBlocked(ctx context.Context, task *api.Task) (bool, er
 	return false, nil
 }
 
+// isTaskHeld returns true if the pipeline of the task is held, e.g. the task belongs to a draft issue created
+// from a pull request which is still open. The task is released after the pull request is merged.
+func (s *Scheduler) isTaskHeld(ctx context.Context, task *api.Task) (bool, error) {
+	pipeline, err := s.store.GetPipelineByID(ctx, task.PipelineID)
+	if err != nil {
+		return true, errors.Wrapf(err, "failed to get pipeline by ID %d", task.PipelineID)
+	}
+	if pipeline == nil {
+		return true, errors.Errorf("pipeline %d not found", task.PipelineID)
+	}
+	return pipeline.Held, nil
+}
+
 // ScheduleActiveStage tries to schedule the tasks in the active stage.
 func (s *Scheduler) ScheduleActiveStage(ctx context.Context, pipeline *api.Pipeline) error {
+	// The held pipeline is skipped as a whole, so we don't need to check its tasks one by one.
+	if pipeline.Held {
+		return nil
+	}
 	stage := utils.GetActiveStage(pipeline.StageList)
 	if stage == nil {
 		return nil
@@ -911,6 +938,8 @@ func (s *Scheduler) getAnyProjectOwner(ctx context.Context, projectID int) (*api
 }
 
 // CanPrincipalBeAssignee checks if a principal could be the assignee of an issue, judging by the principal role and the environment policy.
+// The roles with the issue.approve permission, e.g. the workspace owner and DBA, or the project roles with the approve-issue
+// permission, e.g. the project owner, can be the assignee of the respective assignee group.
 func (s *Scheduler) CanPrincipalBeAssignee(ctx context.Context, principalID int, environmentID int, projectID int, issueType api.IssueType) (bool, error) {
 	policy, err := s.store.GetPipelineApprovalPolicy(ctx, environmentID)
 	if err != nil {
@@ -934,11 +963,13 @@ func (s *Scheduler) CanPrincipalBeAssignee(ctx context.Context, principalID int,
 			return false, common.Errorf(common.NotFound, "principal not found by ID %d", principalID)
 		}
 		if !s.licenseService.IsFeatureEnabled(api.FeatureRBAC) {
-			principal.Role = api.Owner
-		}
-		if principal.Role == api.Owner || principal.Role == api.DBA {
 			return true, nil
 		}
+		permissionList, err := rbac.GetRolePermissionList(ctx, s.store, s.licenseService, principal.Role)
+		if err != nil {
+			return false, common.Wrapf(err, common.Internal, "failed to get permissions of role %s", principal.Role)
+		}
+		return rbac.HasPermission(permissionList, api.PermissionIssueApprove), nil
 	} else if *groupValue == api.AssigneeGroupValueProjectOwner {
 		// the assignee group is the project owner.
 		member, err := s.store.GetProjectMember(ctx, &api.ProjectMemberFind{
@@ -952,11 +983,9 @@ func (s *Scheduler) CanPrincipalBeAssignee(ctx context.Context, principalID int,
 			return false, common.Errorf(common.NotFound, "project member not found by projectID %d, principalID %d", projectID, principalID)
 		}
 		if !s.licenseService.IsFeatureEnabled(api.FeatureRBAC) {
-			member.Role = string(api.Owner)
-		}
-		if member.Role == string(api.Owner) {
 			return true, nil
 		}
+		return rbac.HasProjectPermission(ctx, s.store, s.licenseService, api.ProjectPermissionApproveIssue, common.ProjectRole(member.Role))
 	}
 	return false, nil
 }
@@ -0,0 +1,242 @@
+package taskrun
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+	vcsPlugin "github.com/bytebase/bytebase/plugin/vcs"
+	"github.com/bytebase/bytebase/server/utils"
+	"github.com/bytebase/bytebase/store"
+)
+
+const (
+	// schemaWriteBackBranchPrefix is the prefix of the bot branches to write back the latest schema,
+	// followed by the short commit ID of the base branch where the bot branch is created from.
+	schemaWriteBackBranchPrefix = "bytebase/schema-write-back-"
+	// shortCommitIDLength is the length of the short commit ID in the bot branch name.
+	shortCommitIDLength = 8
+)
+
+// getSchemaWriteBackBranch returns the bot branch to write back the latest schema for the base commit.
+// The latest schema of the databases are batched in the same bot branch until the base branch moves on.
+func getSchemaWriteBackBranch(baseCommitID string) string {
+	if len(baseCommitID) > shortCommitIDLength {
+		baseCommitID = baseCommitID[:shortCommitIDLength]
+	}
+	return schemaWriteBackBranchPrefix + baseCommitID
+}
+
+// splitSchemaWriteBackPullRequest splits the open pull requests into the one from the bot branch, and the stale ones
+// from the other bot branches which are created from the previous commits of the base branch. The stale ones are
+// sorted by the pull request ID in descending order, so that the newer ones come first.
+func splitSchemaWriteBackPullRequest(pullRequestList []*vcsPlugin.PullRequest, branch string) (*vcsPlugin.PullRequest, []*vcsPlugin.PullRequest) {
+	var current *vcsPlugin.PullRequest
+	var staleList []*vcsPlugin.PullRequest
+	for _, pullRequest := range pullRequestList {
+		switch {
+		case pullRequest.Head == branch:
+			current = pullRequest
+		case strings.HasPrefix(pullRequest.Head, schemaWriteBackBranchPrefix):
+			staleList = append(staleList, pullRequest)
+		}
+	}
+	sort.SliceStable(staleList, func(i, j int) bool {
+		idI, _ := strconv.Atoi(staleList[i].ID)
+		idJ, _ := strconv.Atoi(staleList[j].ID)
+		return idI > idJ
+	})
+	return current, staleList
+}
+
+// writeBackLatestSchemaPullRequest writes back the latest schema to the bot branch, and opens a pull request to
+// the branch of the repository if there isn't one. It's used when the branch is protected from direct pushes.
+// The pull requests from the bot branches of the previous base commits are stale, and the latest schema files
+// in them are carried over to the new bot branch before they are closed.
+// Returns the commit id, the bot branch and the pull request on success.
+func writeBackLatestSchemaPullRequest(ctx context.Context, store *store.Store, repository *api.Repository, pushEvent *vcsPlugin.PushEvent, mi *db.MigrationInfo, latestSchemaFile string, schema string, bytebaseURL string) (string, string, *vcsPlugin.PullRequest, error) {
+	if pushEvent == nil {
+		return "", "", nil, errors.Errorf("missing VCS push event to write back the latest schema for repository %q", repository.FullPath)
+	}
+	// The pull request targets the branch the migration is pushed to, the branch filter of the repository may be
+	// a wildcard, e.g. "release/*".
+	base, err := vcsPlugin.Branch(pushEvent.Ref)
+	if err != nil {
+		return "", "", nil, errors.Wrapf(err, "failed to get the branch of the VCS push event")
+	}
+	provider := vcsPlugin.Get(repository.VCS.Type, vcsPlugin.ProviderConfig{})
+
+	oauthContext, err := getRepositoryOauthContext(ctx, store, repository)
+	if err != nil {
+		return "", "", nil, err
+	}
+	baseBranch, err := provider.GetBranch(ctx, oauthContext, repository.VCS.InstanceURL, repository.ExternalID, base)
+	if err != nil {
+		return "", "", nil, errors.Wrapf(err, "failed to get branch %q", base)
+	}
+	branch := getSchemaWriteBackBranch(baseBranch.LastCommitID)
+
+	oauthContext, err = getRepositoryOauthContext(ctx, store, repository)
+	if err != nil {
+		return "", "", nil, err
+	}
+	pullRequestList, err := provider.ListPullRequest(ctx, oauthContext, repository.VCS.InstanceURL, repository.ExternalID, base)
+	if err != nil {
+		return "", "", nil, errors.Wrapf(err, "failed to list pull requests to branch %q", base)
+	}
+	pullRequest, stalePullRequestList := splitSchemaWriteBackPullRequest(pullRequestList, branch)
+
+	if pullRequest == nil {
+		oauthContext, err = getRepositoryOauthContext(ctx, store, repository)
+		if err != nil {
+			return "", "", nil, err
+		}
+		if _, err := provider.GetBranch(ctx, oauthContext, repository.VCS.InstanceURL, repository.ExternalID, branch); err != nil {
+			if common.ErrorCode(err) != common.NotFound {
+				return "", "", nil, errors.Wrapf(err, "failed to get branch %q", branch)
+			}
+			if err := provider.CreateBranch(ctx, oauthContext, repository.VCS.InstanceURL, repository.ExternalID, &vcsPlugin.BranchInfo{
+				Name:         branch,
+				LastCommitID: baseBranch.LastCommitID,
+			}); err != nil {
+				return "", "", nil, errors.Wrapf(err, "failed to create branch %q", branch)
+			}
+		}
+		if err := carryOverStaleSchemaFile(ctx, store, repository, stalePullRequestList, branch, latestSchemaFile); err != nil {
+			return "", "", nil, err
+		}
+	}
+
+	commitID, err := writeBackLatestSchema(ctx, store, repository, pushEvent, mi, branch, latestSchemaFile, schema, bytebaseURL)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	if pullRequest == nil {
+		body := "THIS PULL REQUEST IS AUTO-GENERATED BY BYTEBASE\n\nThe latest schema of the databases after migration are committed to this pull request until the branch moves on."
+		if bytebaseURL != "" {
+			body += "\n\n" + bytebaseURL
+		}
+		oauthContext, err = getRepositoryOauthContext(ctx, store, repository)
+		if err != nil {
+			return "", "", nil, err
+		}
+		pullRequest, err = provider.CreatePullRequest(ctx, oauthContext, repository.VCS.InstanceURL, repository.ExternalID, &vcsPlugin.PullRequestCreate{
+			Title:                 fmt.Sprintf("[Bytebase] Update latest schema on %s", base),
+			Body:                  body,
+			Head:                  branch,
+			Base:                  base,
+			RemoveHeadAfterMerged: true,
+		})
+		if err != nil {
+			return "", "", nil, errors.Wrapf(err, "failed to create pull request from branch %q to %q", branch, base)
+		}
+	}
+
+	// Closing the stale pull requests is best-effort, they will be closed again in the next write-back.
+	for _, stale := range stalePullRequestList {
+		oauthContext, err = getRepositoryOauthContext(ctx, store, repository)
+		if err != nil {
+			return "", "", nil, err
+		}
+		comment := fmt.Sprintf("The latest schema in this pull request is carried over to %s, closing this stale pull request.", pullRequest.URL)
+		if err := provider.CreatePullRequestComment(ctx, oauthContext, repository.VCS.InstanceURL, repository.ExternalID, stale.ID, comment); err != nil {
+			log.Warn("Failed to comment on stale schema write-back pull request", zap.String("pull_request", stale.URL), zap.Error(err))
+		}
+		if err := provider.ClosePullRequest(ctx, oauthContext, repository.VCS.InstanceURL, repository.ExternalID, stale.ID); err != nil {
+			log.Warn("Failed to close stale schema write-back pull request", zap.String("pull_request", stale.URL), zap.Error(err))
+		}
+	}
+
+	return commitID, branch, pullRequest, nil
+}
+
+// carryOverStaleSchemaFile commits the latest schema files in the stale pull requests to the bot branch,
+// except the latestSchemaFile which will be written back next. The newer pull requests come first, so
+// the file is carried over from the newest pull request if it's changed in multiple ones.
+func carryOverStaleSchemaFile(ctx context.Context, store *store.Store, repository *api.Repository, stalePullRequestList []*vcsPlugin.PullRequest, branch, latestSchemaFile string) error {
+	provider := vcsPlugin.Get(repository.VCS.Type, vcsPlugin.ProviderConfig{})
+	carried := map[string]bool{latestSchemaFile: true}
+	for _, stale := range stalePullRequestList {
+		oauthContext, err := getRepositoryOauthContext(ctx, store, repository)
+		if err != nil {
+			return err
+		}
+		fileList, err := provider.ListPullRequestFile(ctx, oauthContext, repository.VCS.InstanceURL, repository.ExternalID, stale.ID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to list files of pull request %s", stale.URL)
+		}
+		for _, file := range fileList {
+			if file.IsDeleted || carried[file.Path] {
+				continue
+			}
+			carried[file.Path] = true
+			content, err := provider.ReadFileContent(ctx, oauthContext, repository.VCS.InstanceURL, repository.ExternalID, file.Path, stale.Head)
+			if err != nil {
+				return errors.Wrapf(err, "failed to read file %s of pull request %s", file.Path, stale.URL)
+			}
+			commitMessage := fmt.Sprintf("[Bytebase] Carry over latest schema %s\n\nTHIS COMMIT IS AUTO-GENERATED BY BYTEBASE\n\n%s", file.Path, stale.URL)
+			if err := commitFile(ctx, store, repository, branch, file.Path, content, commitMessage); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// commitFile creates or overwrites the file in the branch.
+func commitFile(ctx context.Context, store *store.Store, repository *api.Repository, branch, filePath, content, commitMessage string) error {
+	provider := vcsPlugin.Get(repository.VCS.Type, vcsPlugin.ProviderConfig{})
+	oauthContext, err := getRepositoryOauthContext(ctx, store, repository)
+	if err != nil {
+		return err
+	}
+	fileCommit := vcsPlugin.FileCommitCreate{
+		Branch:        branch,
+		CommitMessage: commitMessage,
+		Content:       content,
+	}
+	fileMeta, err := provider.ReadFileMeta(ctx, oauthContext, repository.VCS.InstanceURL, repository.ExternalID, filePath, branch)
+	if err != nil {
+		if common.ErrorCode(err) != common.NotFound {
+			return errors.Wrapf(err, "failed to read file %s in branch %q", filePath, branch)
+		}
+		if err := provider.CreateFile(ctx, oauthContext, repository.VCS.InstanceURL, repository.ExternalID, filePath, fileCommit); err != nil {
+			return errors.Wrapf(err, "failed to create file %s in branch %q", filePath, branch)
+		}
+		return nil
+	}
+	fileCommit.LastCommitID = fileMeta.LastCommitID
+	if err := provider.OverwriteFile(ctx, oauthContext, repository.VCS.InstanceURL, repository.ExternalID, filePath, fileCommit); err != nil {
+		return errors.Wrapf(err, "failed to overwrite file %s in branch %q", filePath, branch)
+	}
+	return nil
+}
+
+// getRepositoryOauthContext returns the OAuth context of the repository. It retrieves the latest AccessToken and
+// RefreshToken as the previous VCS call may have updated the stored token pair.
+func getRepositoryOauthContext(ctx context.Context, store *store.Store, repository *api.Repository) (common.OauthContext, error) {
+	repo, err := store.GetRepository(ctx, &api.RepositoryFind{ID: &repository.ID})
+	if err != nil {
+		return common.OauthContext{}, errors.Wrap(err, "failed to fetch repository for schema write-back")
+	}
+	if repo == nil {
+		return common.OauthContext{}, errors.Errorf("repository not found for schema write-back: %v", repository.ID)
+	}
+	return common.OauthContext{
+		ClientID:     repository.VCS.ApplicationID,
+		ClientSecret: repository.VCS.Secret,
+		AccessToken:  repo.AccessToken,
+		RefreshToken: repo.RefreshToken,
+		Refresher:    utils.RefreshToken(ctx, store, repo.WebURL),
+	}, nil
+}
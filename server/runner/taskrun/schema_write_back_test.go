@@ -0,0 +1,31 @@
+package taskrun
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	vcsPlugin "github.com/bytebase/bytebase/plugin/vcs"
+)
+
+func TestGetSchemaWriteBackBranch(t *testing.T) {
+	assert.Equal(t, "bytebase/schema-write-back-6dcb09b5", getSchemaWriteBackBranch("6dcb09b5b57875f334f61aebed695e2e4193db5e"))
+	assert.Equal(t, "bytebase/schema-write-back-6dcb", getSchemaWriteBackBranch("6dcb"))
+}
+
+func TestSplitSchemaWriteBackPullRequest(t *testing.T) {
+	pullRequestList := []*vcsPlugin.PullRequest{
+		{ID: "2", Head: "bytebase/schema-write-back-aaaaaaaa"},
+		{ID: "3", Head: "feature/foo"},
+		{ID: "10", Head: "bytebase/schema-write-back-bbbbbbbb"},
+		{ID: "11", Head: "bytebase/schema-write-back-cccccccc"},
+	}
+
+	current, staleList := splitSchemaWriteBackPullRequest(pullRequestList, "bytebase/schema-write-back-cccccccc")
+	assert.Equal(t, pullRequestList[3], current)
+	assert.Equal(t, []*vcsPlugin.PullRequest{pullRequestList[2], pullRequestList[0]}, staleList)
+
+	current, staleList = splitSchemaWriteBackPullRequest(pullRequestList, "bytebase/schema-write-back-dddddddd")
+	assert.Nil(t, current)
+	assert.Equal(t, []*vcsPlugin.PullRequest{pullRequestList[3], pullRequestList[2], pullRequestList[0]}, staleList)
+}
@@ -0,0 +1,258 @@
+// Package webhookrun is the runner for delivering the project and workspace webhooks.
+package webhookrun
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/webhook"
+	"github.com/bytebase/bytebase/store"
+)
+
+const (
+	runnerInterval = time.Duration(3) * time.Second
+	// maxAttemptCount is the maximum number of attempts of a delivery before it's marked as FAILED.
+	maxAttemptCount = 8
+	// initialRetryDelay is the delay before the first retry, and it's doubled for each subsequent retry.
+	initialRetryDelay = time.Duration(10) * time.Second
+	maxRetryDelay     = time.Duration(1) * time.Hour
+	// maxResponseBodyLength is the maximum length of the response body kept in the delivery.
+	maxResponseBodyLength = 1024
+	// claimLimit is the maximum number of the deliveries of each kind claimed in one run.
+	claimLimit = 100
+	// maxConcurrentDelivery is the maximum number of the deliveries sent concurrently.
+	maxConcurrentDelivery = 8
+	// sendingTimeout is the time after which a sending delivery is reclaimed, e.g. the runner is stopped in the
+	// middle of the attempt. It's longer than the timeout of posting a webhook.
+	sendingTimeout = time.Duration(5) * time.Minute
+)
+
+// NewRunner creates a webhook delivery runner.
+func NewRunner(store *store.Store) *Runner {
+	return &Runner{
+		store: store,
+	}
+}
+
+// Runner is the runner delivering the pending project and workspace webhook deliveries, with exponential backoff on failure.
+type Runner struct {
+	store *store.Store
+}
+
+// Run starts the webhook delivery runner.
+func (r *Runner) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(runnerInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("Webhook delivery runner started and will run every %v", runnerInterval))
+	for {
+		select {
+		case <-ticker.C:
+			r.deliverPending(ctx)
+		case <-ctx.Done(): // if cancel() execute
+			return
+		}
+	}
+}
+
+// webhookKind is the kind of the webhook a delivery belongs to.
+type webhookKind string
+
+const (
+	projectWebhook   webhookKind = "project"
+	workspaceWebhook webhookKind = "workspace"
+)
+
+// delivery is a project or workspace webhook delivery, both kinds share the same deliver path and retry strategy.
+type delivery struct {
+	kind          webhookKind
+	id            int
+	webhookID     int
+	activityType  api.ActivityType
+	attemptCount  int
+	nextAttemptTs int64
+	payload       string
+}
+
+func (r *Runner) deliverPending(ctx context.Context) {
+	now := time.Now()
+	claim := &api.WebhookDeliveryClaim{
+		MaxNextAttemptTs:    now.Unix(),
+		MaxSendingUpdatedTs: now.Add(-sendingTimeout).Unix(),
+		Limit:               claimLimit,
+	}
+	// The deliveries are claimed before sending, so that each of them is sent only once even if there are
+	// multiple runners.
+	var pendingList []*delivery
+	projectDeliveryList, err := r.store.ClaimProjectWebhookDelivery(ctx, claim)
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			log.Error("Failed to claim pending project webhook deliveries", zap.Error(err))
+		}
+	}
+	for _, d := range projectDeliveryList {
+		pendingList = append(pendingList, &delivery{
+			kind:          projectWebhook,
+			id:            d.ID,
+			webhookID:     d.ProjectWebhookID,
+			activityType:  d.ActivityType,
+			attemptCount:  d.AttemptCount,
+			nextAttemptTs: d.NextAttemptTs,
+			payload:       d.Payload,
+		})
+	}
+	workspaceDeliveryList, err := r.store.ClaimWorkspaceWebhookDelivery(ctx, claim)
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			log.Error("Failed to claim pending workspace webhook deliveries", zap.Error(err))
+		}
+	}
+	for _, d := range workspaceDeliveryList {
+		pendingList = append(pendingList, &delivery{
+			kind:          workspaceWebhook,
+			id:            d.ID,
+			webhookID:     d.WorkspaceWebhookID,
+			activityType:  d.ActivityType,
+			attemptCount:  d.AttemptCount,
+			nextAttemptTs: d.NextAttemptTs,
+			payload:       d.Payload,
+		})
+	}
+
+	// A slow webhook endpoint shouldn't hold up the others, so the deliveries are sent concurrently.
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentDelivery)
+	for _, d := range pendingList {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(d *delivery) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := r.deliver(ctx, d); err != nil {
+				log.Error("Failed to deliver webhook", zap.String("kind", string(d.kind)), zap.Int("delivery_id", d.id), zap.Error(err))
+			}
+		}(d)
+	}
+	wg.Wait()
+}
+
+func (r *Runner) deliver(ctx context.Context, d *delivery) error {
+	var webhookType, url, secret string
+	found := false
+	switch d.kind {
+	case projectWebhook:
+		hook, err := r.store.GetProjectWebhookByID(ctx, d.webhookID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get project webhook %d", d.webhookID)
+		}
+		if hook != nil {
+			webhookType, url, secret, found = hook.Type, hook.URL, hook.Secret, true
+		}
+	case workspaceWebhook:
+		hook, err := r.store.GetWorkspaceWebhookByID(ctx, d.webhookID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get workspace webhook %d", d.webhookID)
+		}
+		if hook != nil {
+			webhookType, url, secret, found = hook.Type, hook.URL, hook.Secret, true
+		}
+	default:
+		return errors.Errorf("unknown webhook kind %q", d.kind)
+	}
+
+	var postErr error
+	if !found {
+		postErr = errors.Errorf("%s webhook %d not found", d.kind, d.webhookID)
+	} else {
+		postErr = post(webhookType, url, secret, d.id, d.payload)
+	}
+	if postErr != nil {
+		// The external webhook endpoint might be invalid which is out of our code control, so we just emit a warning.
+		log.Warn("Failed to post webhook event on activity",
+			zap.String("kind", string(d.kind)),
+			zap.Int("delivery_id", d.id),
+			zap.Int("webhook_id", d.webhookID),
+			zap.String("activity type", string(d.activityType)),
+			zap.Int("attempt", d.attemptCount+1),
+			zap.Error(postErr))
+	}
+
+	patch := getDeliveryPatch(d, postErr, time.Now())
+	var err error
+	switch d.kind {
+	case projectWebhook:
+		_, err = r.store.PatchProjectWebhookDelivery(ctx, patch)
+	case workspaceWebhook:
+		_, err = r.store.PatchWorkspaceWebhookDelivery(ctx, patch)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to patch %s webhook delivery %d", d.kind, d.id)
+	}
+	return nil
+}
+
+// post posts the webhook context stored in the delivery payload to the webhook url.
+func post(webhookType, url, secret string, deliveryID int, payload string) error {
+	var webhookCtx webhook.Context
+	if err := json.Unmarshal([]byte(payload), &webhookCtx); err != nil {
+		return errors.Wrap(err, "malformed webhook delivery payload")
+	}
+	webhookCtx.URL = url
+	webhookCtx.Secret = secret
+	webhookCtx.DeliveryID = deliveryID
+	return webhook.Post(webhookType, webhookCtx)
+}
+
+// getDeliveryPatch returns the patch of the delivery after an attempt with the error postErr.
+func getDeliveryPatch(d *delivery, postErr error, now time.Time) *api.WebhookDeliveryPatch {
+	patch := &api.WebhookDeliveryPatch{
+		ID:            d.id,
+		AttemptCount:  d.attemptCount + 1,
+		NextAttemptTs: d.nextAttemptTs,
+	}
+	if postErr == nil {
+		patch.Status = api.ProjectWebhookDeliverySucceeded
+		// The receivers only accept 200 as the successful response.
+		patch.ResponseCode = 200
+		return patch
+	}
+
+	patch.Error = postErr.Error()
+	var responseErr *webhook.ResponseError
+	if errors.As(postErr, &responseErr) {
+		patch.ResponseCode = responseErr.StatusCode
+		patch.ResponseBody = responseErr.Body
+		if len(patch.ResponseBody) > maxResponseBodyLength {
+			patch.ResponseBody = patch.ResponseBody[:maxResponseBodyLength]
+		}
+	}
+	if patch.AttemptCount >= maxAttemptCount {
+		patch.Status = api.ProjectWebhookDeliveryFailed
+		return patch
+	}
+	patch.Status = api.ProjectWebhookDeliveryPending
+	patch.NextAttemptTs = now.Add(getRetryDelay(patch.AttemptCount)).Unix()
+	return patch
+}
+
+// getRetryDelay returns the delay before the next attempt after attemptCount attempts.
+func getRetryDelay(attemptCount int) time.Duration {
+	delay := initialRetryDelay
+	for i := 1; i < attemptCount; i++ {
+		delay *= 2
+		if delay >= maxRetryDelay {
+			return maxRetryDelay
+		}
+	}
+	return delay
+}
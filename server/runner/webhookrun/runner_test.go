@@ -0,0 +1,55 @@
+package webhookrun
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/webhook"
+)
+
+func TestGetRetryDelay(t *testing.T) {
+	a := require.New(t)
+	a.Equal(10*time.Second, getRetryDelay(1))
+	a.Equal(20*time.Second, getRetryDelay(2))
+	a.Equal(640*time.Second, getRetryDelay(7))
+	a.Equal(time.Hour, getRetryDelay(20))
+}
+
+func TestGetDeliveryPatch(t *testing.T) {
+	a := require.New(t)
+	now := time.Unix(1000, 0)
+
+	patch := getDeliveryPatch(&delivery{kind: projectWebhook, id: 101, nextAttemptTs: 900}, nil, now)
+	a.Equal(&api.WebhookDeliveryPatch{
+		ID:            101,
+		Status:        api.ProjectWebhookDeliverySucceeded,
+		AttemptCount:  1,
+		NextAttemptTs: 900,
+		ResponseCode:  200,
+	}, patch)
+
+	responseErr := &webhook.ResponseError{URL: "https://example.com", StatusCode: 502, Body: "bad gateway"}
+	patch = getDeliveryPatch(&delivery{kind: workspaceWebhook, id: 101, attemptCount: 1, nextAttemptTs: 900}, errors.Wrap(responseErr, "post"), now)
+	a.Equal(&api.WebhookDeliveryPatch{
+		ID:            101,
+		Status:        api.ProjectWebhookDeliveryPending,
+		AttemptCount:  2,
+		NextAttemptTs: 1020,
+		ResponseCode:  502,
+		ResponseBody:  "bad gateway",
+		Error:         "post: " + responseErr.Error(),
+	}, patch)
+
+	patch = getDeliveryPatch(&delivery{kind: projectWebhook, id: 101, attemptCount: maxAttemptCount - 1, nextAttemptTs: 900}, errors.New("timeout"), now)
+	a.Equal(&api.WebhookDeliveryPatch{
+		ID:            101,
+		Status:        api.ProjectWebhookDeliveryFailed,
+		AttemptCount:  maxAttemptCount,
+		NextAttemptTs: 900,
+		Error:         "timeout",
+	}, patch)
+}
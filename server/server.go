@@ -6,22 +6,15 @@ import (
 	"fmt"
 	"net/http"
 	"runtime"
-	"strings"
 	"sync"
 	"time"
 
-	// embed will embeds the acl policy.
-	_ "embed"
-
-	"github.com/casbin/casbin/v2"
-	"github.com/casbin/casbin/v2/model"
 	"github.com/google/uuid"
 	"github.com/labstack/echo-contrib/pprof"
 	"github.com/labstack/echo-contrib/prometheus"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/pkg/errors"
-	scas "github.com/qiangmzsx/string-adapter/v2"
 	echoSwagger "github.com/swaggo/echo-swagger"
 
 	"github.com/bytebase/bytebase/api"
@@ -33,6 +26,7 @@ import (
 	"github.com/bytebase/bytebase/metric"
 	metricCollector "github.com/bytebase/bytebase/metric/collector"
 	"github.com/bytebase/bytebase/plugin/app/feishu"
+	"github.com/bytebase/bytebase/plugin/app/slack"
 	bbs3 "github.com/bytebase/bytebase/plugin/storage/s3"
 	"github.com/bytebase/bytebase/resources/mysqlutil"
 	"github.com/bytebase/bytebase/resources/postgres"
@@ -43,11 +37,14 @@ import (
 	"github.com/bytebase/bytebase/server/runner/anomaly"
 	"github.com/bytebase/bytebase/server/runner/apprun"
 	"github.com/bytebase/bytebase/server/runner/backuprun"
+	"github.com/bytebase/bytebase/server/runner/ldapsync"
+	"github.com/bytebase/bytebase/server/runner/mailrun"
 	"github.com/bytebase/bytebase/server/runner/metricreport"
 	"github.com/bytebase/bytebase/server/runner/rollbackrun"
 	"github.com/bytebase/bytebase/server/runner/schemasync"
 	"github.com/bytebase/bytebase/server/runner/taskcheck"
 	"github.com/bytebase/bytebase/server/runner/taskrun"
+	"github.com/bytebase/bytebase/server/runner/webhookrun"
 	"github.com/bytebase/bytebase/store"
 
 	// Register clickhouse driver.
@@ -104,6 +101,9 @@ type Server struct {
 	AnomalyScanner     *anomaly.Scanner
 	ApplicationRunner  *apprun.Runner
 	RollbackRunner     *rollbackrun.Runner
+	WebhookRunner      *webhookrun.Runner
+	MailRunner         *mailrun.Runner
+	LDAPSyncer         *ldapsync.Syncer
 	runnerWG           sync.WaitGroup
 
 	ActivityManager *activity.Manager
@@ -127,6 +127,7 @@ type Server struct {
 
 	s3Client       *bbs3.Client
 	feishuProvider *feishu.Provider
+	slackProvider  *slack.Provider
 
 	// stateCfg is the shared in-momory state within the server.
 	stateCfg *state.State
@@ -135,18 +136,6 @@ type Server struct {
 	cancel context.CancelFunc
 }
 
-//go:embed acl_casbin_model.conf
-var casbinModel string
-
-//go:embed acl_casbin_policy_owner.csv
-var casbinOwnerPolicy string
-
-//go:embed acl_casbin_policy_dba.csv
-var casbinDBAPolicy string
-
-//go:embed acl_casbin_policy_developer.csv
-var casbinDeveloperPolicy string
-
 // Use following cmd to generate swagger doc
 // swag init -g ./server.go -d ./server --output docs/openapi --parseDependency
 
@@ -303,9 +292,13 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 		s.SchemaSyncer = schemasync.NewSyncer(storeInstance, s.dbFactory, s.stateCfg, profile)
 		// TODO(p0ny): enable Feishu provider only when it is needed.
 		s.feishuProvider = feishu.NewProvider(profile.FeishuAPIURL)
+		s.slackProvider = slack.NewProvider(profile.SlackAPIURL)
 		s.ApplicationRunner = apprun.NewRunner(storeInstance, s.ActivityManager, s.feishuProvider, profile)
 		s.BackupRunner = backuprun.NewRunner(storeInstance, s.dbFactory, s.s3Client, s.stateCfg, &profile)
 		s.RollbackRunner = rollbackrun.NewRunner(storeInstance, s.dbFactory, s.stateCfg)
+		s.WebhookRunner = webhookrun.NewRunner(storeInstance)
+		s.MailRunner = mailrun.NewRunner(storeInstance)
+		s.LDAPSyncer = ldapsync.NewSyncer(storeInstance, s.ActivityManager, s.licenseService)
 
 		s.TaskScheduler = taskrun.NewScheduler(storeInstance, s.ApplicationRunner, s.SchemaSyncer, s.ActivityManager, s.licenseService, s.stateCfg, profile)
 		s.TaskScheduler.Register(api.TaskGeneral, taskrun.NewDefaultExecutor())
@@ -334,13 +327,13 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 		s.TaskCheckScheduler.Register(api.TaskCheckInstanceMigrationSchema, migrationSchemaExecutor)
 		ghostSyncExecutor := taskcheck.NewGhostSyncExecutor(storeInstance)
 		s.TaskCheckScheduler.Register(api.TaskCheckGhostSync, ghostSyncExecutor)
-		checkLGTMExecutor := taskcheck.NewLGTMExecutor(storeInstance)
+		checkLGTMExecutor := taskcheck.NewLGTMExecutor(storeInstance, s.licenseService)
 		s.TaskCheckScheduler.Register(api.TaskCheckIssueLGTM, checkLGTMExecutor)
 		pitrMySQLExecutor := taskcheck.NewPITRMySQLExecutor(storeInstance, s.dbFactory)
 		s.TaskCheckScheduler.Register(api.TaskCheckPITRMySQL, pitrMySQLExecutor)
 
 		// Anomaly scanner
-		s.AnomalyScanner = anomaly.NewScanner(storeInstance, s.dbFactory, s.licenseService)
+		s.AnomalyScanner = anomaly.NewScanner(storeInstance, s.dbFactory, s.licenseService, s.ActivityManager)
 
 		// Metric reporter
 		s.initMetricReporter(config.workspaceID)
@@ -373,28 +366,25 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 		return JWTMiddleware(internalAPIPrefix, s.store, next, profile.Mode, config.secret)
 	})
 
-	m, err := model.NewModelFromString(casbinModel)
-	if err != nil {
-		return nil, err
-	}
-	sa := scas.NewAdapter(strings.Join([]string{casbinOwnerPolicy, casbinDBAPolicy, casbinDeveloperPolicy}, "\n"))
-	ce, err := casbin.NewEnforcer(m, sa)
-	if err != nil {
-		return nil, err
-	}
 	apiGroup.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
-		return aclMiddleware(s, internalAPIPrefix, ce, next, profile.Readonly)
+		return aclMiddleware(s, internalAPIPrefix, next, profile.Readonly)
 	})
 	s.registerDebugRoutes(apiGroup)
 	s.registerSettingRoutes(apiGroup)
 	s.registerActuatorRoutes(apiGroup)
 	s.registerAuthRoutes(apiGroup)
+	s.registerAuthOIDCRoutes(apiGroup)
+	s.registerAuthLDAPRoutes(apiGroup)
+	s.registerAuthMFARoutes(apiGroup)
 	s.registerOAuthRoutes(apiGroup)
 	s.registerPrincipalRoutes(apiGroup)
+	s.registerAccessTokenRoutes(apiGroup)
+	s.registerCustomRoleRoutes(apiGroup)
 	s.registerMemberRoutes(apiGroup)
 	s.registerPolicyRoutes(apiGroup)
 	s.registerProjectRoutes(apiGroup)
 	s.registerProjectWebhookRoutes(apiGroup)
+	s.registerWorkspaceWebhookRoutes(apiGroup)
 	s.registerProjectMemberRoutes(apiGroup)
 	s.registerEnvironmentRoutes(apiGroup)
 	s.registerInstanceRoutes(apiGroup)
@@ -420,7 +410,7 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 	})
 
 	// Register open API routes
-	s.registerOpenAPIRoutes(e, ce, profile)
+	s.registerOpenAPIRoutes(e, profile)
 
 	// Register pprof endpoints.
 	pprof.Register(e)
@@ -432,14 +422,14 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 	return s, nil
 }
 
-func (s *Server) registerOpenAPIRoutes(e *echo.Echo, ce *casbin.Enforcer, prof config.Profile) {
+func (s *Server) registerOpenAPIRoutes(e *echo.Echo, prof config.Profile) {
 	openAPIGroup := e.Group(openAPIPrefix)
 
 	openAPIGroup.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
 		return JWTMiddleware(openAPIPrefix, s.store, next, prof.Mode, s.secret)
 	})
 	openAPIGroup.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
-		return aclMiddleware(s, openAPIPrefix, ce, next, prof.Readonly)
+		return aclMiddleware(s, openAPIPrefix, next, prof.Readonly)
 	})
 	openAPIGroup.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
 		return openAPIMetricMiddleware(s, next)
@@ -546,6 +536,66 @@ func getInitSetting(ctx context.Context, store *store.Store) (*workspaceConfig,
 		return nil, err
 	}
 
+	// initial repository webhook backfill
+	if _, _, err := store.CreateSettingIfNotExist(ctx, &api.SettingCreate{
+		CreatorID:   api.SystemBotID,
+		Name:        api.SettingRepositoryWebhookPullRequest,
+		Value:       "",
+		Description: "Whether the existing repository webhooks are subscribed to the pull request events.",
+	}); err != nil {
+		return nil, err
+	}
+
+	// initial mail delivery
+	if _, _, err := store.CreateSettingIfNotExist(ctx, &api.SettingCreate{
+		CreatorID:   api.SystemBotID,
+		Name:        api.SettingMailDelivery,
+		Value:       "",
+		Description: "The SMTP server delivering the email notifications.",
+	}); err != nil {
+		return nil, err
+	}
+
+	// initial OIDC single sign-on
+	if _, _, err := store.CreateSettingIfNotExist(ctx, &api.SettingCreate{
+		CreatorID:   api.SystemBotID,
+		Name:        api.SettingAuthOIDC,
+		Value:       "",
+		Description: "The OpenID Connect single sign-on provider.",
+	}); err != nil {
+		return nil, err
+	}
+
+	// initial LDAP authentication
+	if _, _, err := store.CreateSettingIfNotExist(ctx, &api.SettingCreate{
+		CreatorID:   api.SystemBotID,
+		Name:        api.SettingAuthLDAP,
+		Value:       "",
+		Description: "The LDAP directory for the authentication and the member sync.",
+	}); err != nil {
+		return nil, err
+	}
+
+	// initial two-factor authentication policy
+	if _, _, err := store.CreateSettingIfNotExist(ctx, &api.SettingCreate{
+		CreatorID:   api.SystemBotID,
+		Name:        api.SettingAuthMFA,
+		Value:       "",
+		Description: "The two-factor authentication policy of the workspace.",
+	}); err != nil {
+		return nil, err
+	}
+
+	// initial slack app
+	if _, _, err := store.CreateSettingIfNotExist(ctx, &api.SettingCreate{
+		CreatorID:   api.SystemBotID,
+		Name:        api.SettingAppSlack,
+		Value:       "",
+		Description: "The Slack app handling the interactive approvals.",
+	}); err != nil {
+		return nil, err
+	}
+
 	return conf, nil
 }
 
@@ -570,6 +620,14 @@ func (s *Server) Run(ctx context.Context, port int) error {
 		go s.AnomalyScanner.Run(ctx, &s.runnerWG)
 		s.runnerWG.Add(1)
 		go s.ApplicationRunner.Run(ctx, &s.runnerWG)
+		s.runnerWG.Add(1)
+		go s.WebhookRunner.Run(ctx, &s.runnerWG)
+		s.runnerWG.Add(1)
+		go s.MailRunner.Run(ctx, &s.runnerWG)
+		s.runnerWG.Add(1)
+		go s.LDAPSyncer.Run(ctx, &s.runnerWG)
+		s.runnerWG.Add(1)
+		go s.backfillRepositoryWebhook(ctx, &s.runnerWG)
 		if s.profile.Mode == common.ReleaseModeDev {
 			s.runnerWG.Add(1)
 			go s.RollbackRunner.Run(ctx, &s.runnerWG)
@@ -1,9 +1,11 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/google/jsonapi"
 	"github.com/labstack/echo/v4"
@@ -11,12 +13,22 @@ import (
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/plugin/app/feishu"
+	"github.com/bytebase/bytebase/plugin/idp/ldap"
+	"github.com/bytebase/bytebase/plugin/mail"
+	"github.com/bytebase/bytebase/server/runner/ldapsync"
+	"github.com/bytebase/bytebase/server/runner/mailrun"
 )
 
 // Some settings contain secret info so we only return settings that are needed by the client.
 var whitelistSettings = []api.SettingName{
 	api.SettingBrandingLogo,
 	api.SettingAppIM,
+	api.SettingAuthMFA,
+	// The secrets of the following settings are cleared by maskSettingValue before returning to the client.
+	api.SettingMailDelivery,
+	api.SettingAppSlack,
+	api.SettingAuthOIDC,
+	api.SettingAuthLDAP,
 }
 
 func (s *Server) registerSettingRoutes(g *echo.Group) {
@@ -32,6 +44,11 @@ func (s *Server) registerSettingRoutes(g *echo.Group) {
 		for _, setting := range settingList {
 			for _, whitelist := range whitelistSettings {
 				if setting.Name == whitelist {
+					value, err := maskSettingValue(setting.Name, setting.Value)
+					if err != nil {
+						return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to mask setting %s", setting.Name)).SetInternal(err)
+					}
+					setting.Value = value
 					filteredList = append(filteredList, setting)
 					break
 				}
@@ -105,6 +122,46 @@ func (s *Server) registerSettingRoutes(g *echo.Group) {
 			}
 		}
 
+		if settingPatch.Name == api.SettingMailDelivery {
+			value, err := s.getMailDeliverySettingPatchValue(ctx, settingPatch.Value)
+			if err != nil {
+				return err
+			}
+			settingPatch.Value = value
+		}
+
+		if settingPatch.Name == api.SettingAuthOIDC {
+			value, err := s.getAuthOIDCSettingPatchValue(ctx, settingPatch.Value)
+			if err != nil {
+				return err
+			}
+			settingPatch.Value = value
+		}
+
+		if settingPatch.Name == api.SettingAuthLDAP {
+			value, err := s.getAuthLDAPSettingPatchValue(ctx, settingPatch.Value)
+			if err != nil {
+				return err
+			}
+			settingPatch.Value = value
+		}
+
+		if settingPatch.Name == api.SettingAuthMFA {
+			value, err := s.getAuthMFASettingPatchValue(ctx, settingPatch.Value)
+			if err != nil {
+				return err
+			}
+			settingPatch.Value = value
+		}
+
+		if settingPatch.Name == api.SettingAppSlack {
+			value, err := s.getAppSlackSettingPatchValue(ctx, settingPatch.Value)
+			if err != nil {
+				return err
+			}
+			settingPatch.Value = value
+		}
+
 		setting, err := s.store.PatchSetting(ctx, settingPatch)
 		if err != nil {
 			if common.ErrorCode(err) == common.NotFound {
@@ -113,10 +170,311 @@ func (s *Server) registerSettingRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to update setting: %v", settingPatch.Name)).SetInternal(err)
 		}
 
+		value, err := maskSettingValue(setting.Name, setting.Value)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to mask setting %s", setting.Name)).SetInternal(err)
+		}
+		setting.Value = value
+
 		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
 		if err := jsonapi.MarshalPayload(c.Response().Writer, setting); err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal setting response").SetInternal(err)
 		}
 		return nil
 	})
+
+	g.POST("/mail-delivery/test", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		principal, err := s.store.GetPrincipalByID(ctx, principalID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch principal ID: %v", principalID)).SetInternal(err)
+		}
+		if principal == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Principal ID not found: %d", principalID))
+		}
+		value, err := mailrun.GetMailDeliverySetting(ctx, s.store)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get mail delivery setting").SetInternal(err)
+		}
+		if value == nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Mail delivery is not configured")
+		}
+
+		// Send the test email to the current user, even if the mail delivery is not enabled yet.
+		result := &api.MailDeliveryTestResult{}
+		if err := mail.Send(mailrun.GetMailConfig(value), mail.Message{
+			To:       []string{principal.Email},
+			Subject:  "[Bytebase] Test email",
+			HTMLBody: "<p>This is a test email from Bytebase.</p>",
+		}); err != nil {
+			result.Error = err.Error()
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, result); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal mail delivery test response").SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// getMailDeliverySettingPatchValue validates the mail delivery setting value in the patch.
+// The SMTP password is kept unchanged if it's empty in the patch, because it's never returned to the client.
+func (s *Server) getMailDeliverySettingPatchValue(ctx context.Context, patchValue string) (string, error) {
+	var value api.SettingMailDeliveryValue
+	if err := json.Unmarshal([]byte(patchValue), &value); err != nil {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "Malformed setting value for mail delivery").SetInternal(err)
+	}
+	switch mail.Encryption(value.SMTPEncryption) {
+	case "", mail.EncryptionNone, mail.EncryptionStartTLS, mail.EncryptionSSLTLS:
+	default:
+		return "", echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unknown SMTP encryption %s", value.SMTPEncryption))
+	}
+	if value.Enabled && (value.SMTPHost == "" || value.SMTPPort <= 0 || value.SMTPFrom == "") {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "SMTP host, port and sender cannot be empty")
+	}
+	if value.DigestIntervalSeconds < 0 {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "Digest interval cannot be negative")
+	}
+	if value.SMTPPassword == "" {
+		oldValue, err := mailrun.GetMailDeliverySetting(ctx, s.store)
+		if err != nil {
+			return "", echo.NewHTTPError(http.StatusInternalServerError, "Failed to get mail delivery setting").SetInternal(err)
+		}
+		if oldValue != nil {
+			value.SMTPPassword = oldValue.SMTPPassword
+		}
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal updated setting value").SetInternal(err)
+	}
+	return string(b), nil
+}
+
+// getAppSlackSettingPatchValue keeps the signing secret and the bot token unchanged if they're empty in the patch,
+// because they're never returned to the client.
+func (s *Server) getAppSlackSettingPatchValue(ctx context.Context, patchValue string) (string, error) {
+	var value api.SettingAppSlackValue
+	if err := json.Unmarshal([]byte(patchValue), &value); err != nil {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "Malformed setting value for Slack").SetInternal(err)
+	}
+	if value.SigningSecret == "" || value.BotToken == "" {
+		oldValue, err := s.getAppSlackSetting(ctx)
+		if err != nil {
+			return "", echo.NewHTTPError(http.StatusInternalServerError, "Failed to get Slack setting").SetInternal(err)
+		}
+		if oldValue != nil {
+			if value.SigningSecret == "" {
+				value.SigningSecret = oldValue.SigningSecret
+			}
+			if value.BotToken == "" {
+				value.BotToken = oldValue.BotToken
+			}
+		}
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal updated setting value").SetInternal(err)
+	}
+	return string(b), nil
+}
+
+// getAuthOIDCSettingPatchValue validates the OIDC setting value in the patch, and checks if the OpenID provider can be discovered if enabled.
+// The client secret is kept unchanged if it's empty in the patch, because it's never returned to the client.
+func (s *Server) getAuthOIDCSettingPatchValue(ctx context.Context, patchValue string) (string, error) {
+	var value api.SettingAuthOIDCValue
+	if err := json.Unmarshal([]byte(patchValue), &value); err != nil {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "Malformed setting value for OIDC").SetInternal(err)
+	}
+	if value.Enabled && !s.licenseService.IsFeatureEnabled(api.Feature3rdPartyAuth) {
+		return "", echo.NewHTTPError(http.StatusForbidden, api.Feature3rdPartyAuth.AccessErrorMessage())
+	}
+	if httpErr := s.validateSSORoleMapping(ctx, value.DefaultRole, value.GroupRoleMapping); httpErr != nil {
+		return "", httpErr
+	}
+	if value.ClientSecret == "" {
+		oldValue, err := s.getAuthOIDCSetting(ctx)
+		if err != nil {
+			return "", echo.NewHTTPError(http.StatusInternalServerError, "Failed to get OIDC setting").SetInternal(err)
+		}
+		if oldValue != nil {
+			value.ClientSecret = oldValue.ClientSecret
+		}
+	}
+	if value.Enabled {
+		if value.Name == "" || value.IssuerURL == "" || value.ClientID == "" {
+			return "", echo.NewHTTPError(http.StatusBadRequest, "Name, issuer URL and client ID cannot be empty")
+		}
+		if _, err := getOIDCProvider(&value).Discover(ctx); err != nil {
+			return "", echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to discover the OpenID provider: %v", err)).SetInternal(err)
+		}
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal updated setting value").SetInternal(err)
+	}
+	return string(b), nil
+}
+
+// getAuthLDAPSettingPatchValue validates the LDAP setting value in the patch, and checks if the service account can bind if enabled.
+// The bind password is kept unchanged if it's empty in the patch, because it's never returned to the client.
+func (s *Server) getAuthLDAPSettingPatchValue(ctx context.Context, patchValue string) (string, error) {
+	var value api.SettingAuthLDAPValue
+	if err := json.Unmarshal([]byte(patchValue), &value); err != nil {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "Malformed setting value for LDAP").SetInternal(err)
+	}
+	if value.Enabled && !s.licenseService.IsFeatureEnabled(api.Feature3rdPartyAuth) {
+		return "", echo.NewHTTPError(http.StatusForbidden, api.Feature3rdPartyAuth.AccessErrorMessage())
+	}
+	switch ldap.SecurityProtocol(value.SecurityProtocol) {
+	case "", ldap.SecurityProtocolNone, ldap.SecurityProtocolStartTLS, ldap.SecurityProtocolLDAPS:
+	default:
+		return "", echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid security protocol %s", value.SecurityProtocol))
+	}
+	if value.UserFilter != "" && !strings.Contains(value.UserFilter, "%s") {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "User filter must contain %s as the placeholder of the username")
+	}
+	if httpErr := s.validateSSORoleMapping(ctx, value.DefaultRole, value.GroupRoleMapping); httpErr != nil {
+		return "", httpErr
+	}
+	for _, mapping := range value.GroupProjectMapping {
+		if mapping.Group == "" || (mapping.Role != common.ProjectOwner && mapping.Role != common.ProjectDeveloper) {
+			return "", echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid group project mapping from %q to %q", mapping.Group, mapping.Role))
+		}
+		project, err := s.store.GetProjectByID(ctx, mapping.ProjectID)
+		if err != nil {
+			return "", echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get project %d", mapping.ProjectID)).SetInternal(err)
+		}
+		if project == nil {
+			return "", echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project %d not found in group project mapping", mapping.ProjectID))
+		}
+	}
+	if value.SyncIntervalSeconds < 0 {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "Sync interval cannot be negative")
+	}
+	if value.BindPassword == "" {
+		oldValue, err := ldapsync.GetAuthLDAPSetting(ctx, s.store)
+		if err != nil {
+			return "", echo.NewHTTPError(http.StatusInternalServerError, "Failed to get LDAP setting").SetInternal(err)
+		}
+		if oldValue != nil {
+			value.BindPassword = oldValue.BindPassword
+		}
+	}
+	if value.Enabled {
+		if value.Name == "" || value.Host == "" || value.BaseDN == "" {
+			return "", echo.NewHTTPError(http.StatusBadRequest, "Name, host and base DN cannot be empty")
+		}
+		if err := ldapsync.GetProvider(&value).Ping(); err != nil {
+			return "", echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to connect to the LDAP server: %v", err)).SetInternal(err)
+		}
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal updated setting value").SetInternal(err)
+	}
+	return string(b), nil
+}
+
+// getAuthMFASettingPatchValue validates the two-factor authentication setting value in the patch.
+// The required roles can be either built-in or custom roles.
+func (s *Server) getAuthMFASettingPatchValue(ctx context.Context, patchValue string) (string, error) {
+	var value api.SettingAuthMFAValue
+	if err := json.Unmarshal([]byte(patchValue), &value); err != nil {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "Malformed setting value for two-factor authentication").SetInternal(err)
+	}
+	for _, role := range value.RequiredRoleList {
+		if httpErr := s.validateRoleAssignment(ctx, role, api.Owner, api.DBA, api.Developer); httpErr != nil {
+			return "", httpErr
+		}
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal setting value for two-factor authentication").SetInternal(err)
+	}
+	return string(b), nil
+}
+
+// validateSSORoleMapping validates the default role and the group role mapping of the single sign-on provider.
+// The roles can be either built-in or custom roles, see sso.GetRoleFromGroupList for how the mapped roles are ranked.
+func (s *Server) validateSSORoleMapping(ctx context.Context, defaultRole api.Role, mappingList []api.SSOGroupRoleMapping) *echo.HTTPError {
+	if defaultRole != "" {
+		if httpErr := s.validateRoleAssignment(ctx, defaultRole, api.Owner, api.DBA, api.Developer); httpErr != nil {
+			return httpErr
+		}
+	}
+	for _, mapping := range mappingList {
+		if mapping.Group == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid group role mapping from %q to %q", mapping.Group, mapping.Role))
+		}
+		if httpErr := s.validateRoleAssignment(ctx, mapping.Role, api.Owner, api.DBA, api.Developer); httpErr != nil {
+			return httpErr
+		}
+	}
+	return nil
+}
+
+// getAppSlackSetting returns the Slack setting value, or nil if it's not configured.
+func (s *Server) getAppSlackSetting(ctx context.Context) (*api.SettingAppSlackValue, error) {
+	settingName := api.SettingAppSlack
+	setting, err := s.store.GetSetting(ctx, &api.SettingFind{Name: &settingName})
+	if err != nil {
+		return nil, err
+	}
+	if setting == nil || setting.Value == "" {
+		return nil, nil
+	}
+	var value api.SettingAppSlackValue
+	if err := json.Unmarshal([]byte(setting.Value), &value); err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// maskSettingValue clears the secrets in the setting value before returning to the client.
+func maskSettingValue(name api.SettingName, settingValue string) (string, error) {
+	if settingValue == "" {
+		return "", nil
+	}
+	var value interface{}
+	switch name {
+	case api.SettingMailDelivery:
+		var mailDelivery api.SettingMailDeliveryValue
+		if err := json.Unmarshal([]byte(settingValue), &mailDelivery); err != nil {
+			return "", err
+		}
+		mailDelivery.SMTPPassword = ""
+		value = mailDelivery
+	case api.SettingAppSlack:
+		var slack api.SettingAppSlackValue
+		if err := json.Unmarshal([]byte(settingValue), &slack); err != nil {
+			return "", err
+		}
+		slack.SigningSecret = ""
+		slack.BotToken = ""
+		value = slack
+	case api.SettingAuthOIDC:
+		var oidc api.SettingAuthOIDCValue
+		if err := json.Unmarshal([]byte(settingValue), &oidc); err != nil {
+			return "", err
+		}
+		oidc.ClientSecret = ""
+		value = oidc
+	case api.SettingAuthLDAP:
+		var authLDAP api.SettingAuthLDAPValue
+		if err := json.Unmarshal([]byte(settingValue), &authLDAP); err != nil {
+			return "", err
+		}
+		authLDAP.BindPassword = ""
+		value = authLDAP
+	default:
+		return settingValue, nil
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
 }
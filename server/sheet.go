@@ -51,9 +51,8 @@ func (s *Server) registerSheetRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Project ID not found: %d", sheetCreate.ProjectID))
 		}
 
-		role := c.Get(getRoleContextKey()).(api.Role)
-		if role != api.Owner && role != api.DBA {
-			// Non-workspace Owner or DBA can only create sheet into the project where she has the membership.
+		if !hasWorkspacePermission(c, api.PermissionProjectAdmin) {
+			// Non-project admin, e.g. the workspace Developer, can only create sheet into the project where she has the membership.
 			if !api.HasActiveProjectMembership(currentPrincipalID, project) {
 				return echo.NewHTTPError(http.StatusUnauthorized, "Must be a project member to create new sheet")
 			}
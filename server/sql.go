@@ -278,6 +278,7 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 				database.CharacterSet,
 				database.Collation,
 				instance.EnvironmentID,
+				database.ProjectID,
 				exec.Statement,
 				catalog,
 				connection,
@@ -608,19 +609,28 @@ func (s *Server) createSQLEditorQueryActivity(ctx context.Context, c echo.Contex
 	return nil
 }
 
+// sqlCheck checks the statement with the SQL review policy applied to the project in the environment.
+// The projectID should be api.UnknownID if the statement doesn't target a database in any project.
 func (s *Server) sqlCheck(
 	ctx context.Context,
 	dbType advisorDB.Type,
 	dbCharacterSet string,
 	dbCollation string,
 	environmentID int,
+	projectID int,
 	statement string,
 	catalog catalog.Catalog,
 	driver *sql.DB,
 ) (advisor.Status, []advisor.Advice, error) {
 	var adviceList []advisor.Advice
 	environmentResourceType := api.PolicyResourceTypeEnvironment
-	policy, err := s.store.GetNormalSQLReviewPolicy(ctx, &api.PolicyFind{ResourceType: &environmentResourceType, ResourceID: &environmentID})
+	var policy *advisor.SQLReviewPolicy
+	var err error
+	if projectID == api.UnknownID {
+		policy, err = s.store.GetNormalSQLReviewPolicy(ctx, &api.PolicyFind{ResourceType: &environmentResourceType, ResourceID: &environmentID})
+	} else {
+		policy, err = s.store.GetProjectSQLReviewPolicy(ctx, &api.PolicyFind{ResourceType: &environmentResourceType, ResourceID: &environmentID}, projectID, dbType)
+	}
 	if err != nil {
 		if e, ok := err.(*common.Error); ok && e.Code == common.NotFound {
 			return advisor.Success, nil, nil
@@ -1,14 +1,17 @@
 package server
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/google/jsonapi"
 	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
 
 	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
 )
 
 func (s *Server) registerStageRoutes(g *echo.Group) {
@@ -45,39 +48,54 @@ func (s *Server) registerStageRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusBadRequest, "Only support status transitioning from PENDING_APPROVAL to PENDING")
 		}
 
-		pendingApprovalStatus := []api.TaskStatus{api.TaskPendingApproval}
-		tasks, err := s.store.FindTask(ctx, &api.TaskFind{PipelineID: &pipelineID, StageID: &stageID, StatusList: &pendingApprovalStatus}, true /* returnOnErr */)
-		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get tasks").SetInternal(err)
-		}
-		if len(tasks) == 0 {
-			return echo.NewHTTPError(http.StatusInternalServerError, "No task to approve in the stage")
-		}
-
-		// pick any task in the stage to validate
-		// because all tasks in the same stage share the issue & environment.
-		ok, err := s.canPrincipalChangeTaskStatus(ctx, currentPrincipalID, tasks[0], stageAllTaskStatusPatch.Status)
-		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to validate if the principal can change task status").SetInternal(err)
-		}
-		if !ok {
-			return echo.NewHTTPError(http.StatusUnauthorized, "Not allowed to change task status")
-		}
-		var taskIDList []int
-		for _, task := range tasks {
-			taskIDList = append(taskIDList, task.ID)
-		}
-		if err := s.store.BatchPatchTaskStatus(ctx, taskIDList, api.TaskPending, currentPrincipalID); err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to update task %q status", taskIDList)).SetInternal(err)
-		}
-		issue, err := s.store.GetIssueByPipelineID(ctx, tasks[0].PipelineID)
-		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch containing issue").SetInternal(err)
-		}
-		if err := s.ActivityManager.BatchCreateTaskStatusUpdateApprovalActivity(ctx, tasks, currentPrincipalID, issue, stage); err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, "failed to create task status update activity").SetInternal(err)
+		if err := s.approveStage(ctx, pipelineID, stage, currentPrincipalID); err != nil {
+			switch common.ErrorCode(err) {
+			case common.NotFound:
+				return echo.NewHTTPError(http.StatusInternalServerError, common.ErrorMessage(err))
+			case common.NotAuthorized:
+				return echo.NewHTTPError(http.StatusUnauthorized, common.ErrorMessage(err))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to approve stage %v", stageID)).SetInternal(err)
 		}
 
 		return c.String(http.StatusOK, "")
 	})
 }
+
+// approveStage approves the tasks pending approval in the stage on behalf of the principal.
+// It's shared by the approval in the UI and the interactive approval in Slack.
+func (s *Server) approveStage(ctx context.Context, pipelineID int, stage *api.Stage, principalID int) error {
+	pendingApprovalStatus := []api.TaskStatus{api.TaskPendingApproval}
+	tasks, err := s.store.FindTask(ctx, &api.TaskFind{PipelineID: &pipelineID, StageID: &stage.ID, StatusList: &pendingApprovalStatus}, true /* returnOnErr */)
+	if err != nil {
+		return errors.Wrap(err, "failed to get tasks")
+	}
+	if len(tasks) == 0 {
+		return common.Errorf(common.NotFound, "No task to approve in the stage")
+	}
+
+	// pick any task in the stage to validate
+	// because all tasks in the same stage share the issue & environment.
+	ok, err := s.canPrincipalChangeTaskStatus(ctx, principalID, tasks[0], api.TaskPending)
+	if err != nil {
+		return errors.Wrap(err, "failed to validate if the principal can change task status")
+	}
+	if !ok {
+		return common.Errorf(common.NotAuthorized, "Not allowed to change task status")
+	}
+	var taskIDList []int
+	for _, task := range tasks {
+		taskIDList = append(taskIDList, task.ID)
+	}
+	if err := s.store.BatchPatchTaskStatus(ctx, taskIDList, api.TaskPending, principalID); err != nil {
+		return errors.Wrapf(err, "failed to update task %q status", taskIDList)
+	}
+	issue, err := s.store.GetIssueByPipelineID(ctx, tasks[0].PipelineID)
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch containing issue")
+	}
+	if err := s.ActivityManager.BatchCreateTaskStatusUpdateApprovalActivity(ctx, tasks, principalID, issue, stage); err != nil {
+		return errors.Wrap(err, "failed to create task status update activity")
+	}
+	return nil
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/common/log"
 	"github.com/bytebase/bytebase/server/component/activity"
+	"github.com/bytebase/bytebase/server/component/rbac"
 )
 
 func (s *Server) canUpdateTaskStatement(ctx context.Context, task *api.Task) *echo.HTTPError {
@@ -213,6 +214,17 @@ func (s *Server) registerTaskRoutes(g *echo.Group) {
 		if task == nil {
 			return echo.NewHTTPError(http.StatusUnauthorized, fmt.Sprintf("Task not found with ID %d", taskID))
 		}
+		if c.Get(getAccessTokenContextKey()) != nil {
+			issue, err := s.store.GetIssueByPipelineID(ctx, task.PipelineID)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch issue with pipeline ID %d", task.PipelineID)).SetInternal(err)
+			}
+			if issue != nil {
+				if httpErr := checkAccessTokenProject(c, issue.ProjectID); httpErr != nil {
+					return httpErr
+				}
+			}
+		}
 
 		taskUpdated, err := s.TaskCheckScheduler.ScheduleCheck(ctx, task, c.Get(getPrincipalIDContextKey()).(int))
 		if err != nil {
@@ -522,7 +534,7 @@ func (s *Server) canPrincipalChangeTaskStatus(ctx context.Context, principalID i
 			return true, nil
 		}
 	}
-	// the workspace owner and DBA roles can always change task status.
+	// the roles with the issue.approve permission, e.g. the workspace owner and DBA, can always change task status.
 	principal, err := s.store.GetPrincipalByID(ctx, principalID)
 	if err != nil {
 		return false, common.Wrapf(err, common.Internal, "failed to get principal by ID %d", principalID)
@@ -530,7 +542,11 @@ func (s *Server) canPrincipalChangeTaskStatus(ctx context.Context, principalID i
 	if principal == nil {
 		return false, common.Errorf(common.NotFound, "principal not found by ID %d", principalID)
 	}
-	if principal.Role == api.Owner || principal.Role == api.DBA {
+	permissionList, err := s.getRolePermissionList(ctx, principal.Role)
+	if err != nil {
+		return false, common.Wrapf(err, common.Internal, "failed to get permissions of role %s", principal.Role)
+	}
+	if rbac.HasPermission(permissionList, api.PermissionIssueApprove) {
 		return true, nil
 	}
 
@@ -548,7 +564,7 @@ func (s *Server) canPrincipalChangeTaskStatus(ctx context.Context, principalID i
 	if groupValue == nil {
 		return false, nil
 	}
-	// as the policy says, the project owner has the privilege to change task status.
+	// as the policy says, the project owner, or the project role with the approve-issue permission, has the privilege to change task status.
 	if *groupValue == api.AssigneeGroupValueProjectOwner {
 		member, err := s.store.GetProjectMember(ctx, &api.ProjectMemberFind{
 			ProjectID:   &issue.ProjectID,
@@ -557,8 +573,8 @@ func (s *Server) canPrincipalChangeTaskStatus(ctx context.Context, principalID i
 		if err != nil {
 			return false, common.Wrapf(err, common.Internal, "failed to get project member by projectID %d, principalID %d", issue.ProjectID, principalID)
 		}
-		if member != nil && member.Role == string(api.Owner) {
-			return true, nil
+		if member != nil {
+			return s.hasProjectPermission(ctx, api.ProjectPermissionApproveIssue, common.ProjectRole(member.Role))
 		}
 	}
 	return false, nil
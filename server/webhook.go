@@ -28,6 +28,8 @@ import (
 	advisorDB "github.com/bytebase/bytebase/plugin/advisor/db"
 	"github.com/bytebase/bytebase/plugin/db"
 	"github.com/bytebase/bytebase/plugin/vcs"
+	"github.com/bytebase/bytebase/plugin/vcs/azure"
+	"github.com/bytebase/bytebase/plugin/vcs/bitbucket"
 	"github.com/bytebase/bytebase/plugin/vcs/github"
 	"github.com/bytebase/bytebase/plugin/vcs/gitlab"
 	"github.com/bytebase/bytebase/server/component/activity"
@@ -36,7 +38,7 @@ import (
 
 const (
 	// sqlReviewDocs is the URL for SQL review doc.
-	sqlReviewDocs = "https://www.bytebase.com/docs/reference/error-code/advisor"
+	sqlReviewDocs = advisor.ErrorCodeDocsURL
 
 	// issueNameTemplate should be consistent with UI issue names generated from the frontend except for the timestamp.
 	// Because we cannot get the correct timezone of the client here.
@@ -56,18 +58,21 @@ func (s *Server) registerWebhookRoutes(g *echo.Group) {
 		if err := json.Unmarshal(body, &pushEvent); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed push event").SetInternal(err)
 		}
-		// This shouldn't happen as we only setup webhook to receive push event, just in case.
+		if pushEvent.ObjectKind == gitlab.WebhookMergeRequest {
+			return s.handleGitLabMergeRequestEvent(c, body)
+		}
+		// This shouldn't happen as we only setup webhook to receive push and merge request events, just in case.
 		if pushEvent.ObjectKind != gitlab.WebhookPush {
-			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid webhook event type, got %s, want push", pushEvent.ObjectKind))
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid webhook event type, got %s, want %s or %s", pushEvent.ObjectKind, gitlab.WebhookPush, gitlab.WebhookMergeRequest))
 		}
 		repositoryID := fmt.Sprintf("%v", pushEvent.Project.ID)
 
 		filter := func(repo *api.Repository) (bool, error) {
-			if c.Request().Header.Get("X-Gitlab-Token") != repo.WebhookSecretToken {
+			if !validateGitLabWebhookToken(c.Request().Header.Get("X-Gitlab-Token"), repo.WebhookSecretToken) {
 				return false, nil
 			}
 
-			return s.isWebhookEventBranch(pushEvent.Ref, repo.BranchFilter)
+			return s.isWebhookEventBranch(pushEvent.Ref, repo)
 		}
 		repositoryList, err := s.filterRepository(ctx, c.Param("id"), repositoryID, filter)
 		if err != nil {
@@ -78,12 +83,24 @@ func (s *Server) registerWebhookRoutes(g *echo.Group) {
 			return c.String(http.StatusOK, "OK")
 		}
 
+		// GitLab may redeliver the same push event, and we should not create duplicate issues for it.
+		delivery := getGitLabWebhookDelivery(c.Request().Header, string(gitlab.WebhookPush), pushEvent.Ref, pushEvent.After)
+		repositoryList, deliveryIDMap, err := s.claimWebhookDelivery(ctx, repositoryList, delivery)
+		if err != nil {
+			return err
+		}
+		if len(repositoryList) == 0 {
+			log.Debug("The push event has been delivered. Ignore this push event.", zap.String("event_uuid", delivery.eventUUID), zap.String("commit", delivery.commitSHA))
+			return c.String(http.StatusOK, "Duplicate")
+		}
+
 		baseVCSPushEvent, err := pushEvent.ToVCS()
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to convert GitLab commits").SetInternal(err)
 		}
 
-		createdMessages, err := s.processPushEvent(ctx, repositoryList, baseVCSPushEvent)
+		createdMessages, activityList, err := s.processPushEvent(ctx, repositoryList, baseVCSPushEvent)
+		s.recordWebhookDelivery(ctx, repositoryList, deliveryIDMap, createdMessages, activityList, err)
 		if err != nil {
 			return err
 		}
@@ -93,7 +110,7 @@ func (s *Server) registerWebhookRoutes(g *echo.Group) {
 	g.POST("/github/:id", func(c echo.Context) error {
 		ctx := c.Request().Context()
 
-		// This shouldn't happen as we only setup webhook to receive push event, just in case.
+		// This shouldn't happen as we only setup webhook to receive push and pull request events, just in case.
 		eventType := github.WebhookType(c.Request().Header.Get("X-GitHub-Event"))
 		// https://docs.github.com/en/developers/webhooks-and-events/webhooks/about-webhooks#ping-event
 		// When we create a new webhook, GitHub will send us a simple ping event to let us know we've set up the webhook correctly.
@@ -101,14 +118,17 @@ func (s *Server) registerWebhookRoutes(g *echo.Group) {
 		if eventType == github.WebhookPing {
 			return c.String(http.StatusOK, "OK")
 		}
-		if eventType != github.WebhookPush {
-			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid webhook event type, got %s, want %s", eventType, github.WebhookPush))
+		if eventType != github.WebhookPush && eventType != github.WebhookPullRequest {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid webhook event type, got %s, want %s or %s", eventType, github.WebhookPush, github.WebhookPullRequest))
 		}
 
 		body, err := io.ReadAll(c.Request().Body)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Failed to read webhook request").SetInternal(err)
 		}
+		if eventType == github.WebhookPullRequest {
+			return s.handleGitHubPullRequestEvent(c, body)
+		}
 		var pushEvent github.WebhookPushEvent
 		if err := json.Unmarshal(body, &pushEvent); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed push event").SetInternal(err)
@@ -124,7 +144,7 @@ func (s *Server) registerWebhookRoutes(g *echo.Group) {
 				return false, nil
 			}
 
-			return s.isWebhookEventBranch(pushEvent.Ref, repo.BranchFilter)
+			return s.isWebhookEventBranch(pushEvent.Ref, repo)
 		}
 		repositoryList, err := s.filterRepository(ctx, c.Param("id"), repositoryID, filter)
 		if err != nil {
@@ -137,7 +157,94 @@ func (s *Server) registerWebhookRoutes(g *echo.Group) {
 
 		baseVCSPushEvent := pushEvent.ToVCS()
 
-		createdMessages, err := s.processPushEvent(ctx, repositoryList, baseVCSPushEvent)
+		createdMessages, _, err := s.processPushEvent(ctx, repositoryList, baseVCSPushEvent)
+		if err != nil {
+			return err
+		}
+		return c.String(http.StatusOK, strings.Join(createdMessages, "\n"))
+	})
+
+	g.POST("/bitbucket/:id", func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		// Bitbucket Cloud and Bitbucket Data Center share the same endpoint, and
+		// the event is distinguished by the X-Event-Key header.
+		eventType := bitbucket.WebhookType(c.Request().Header.Get("X-Event-Key"))
+		// When we test the connection of a webhook, Bitbucket Data Center will send us a ping event.
+		if eventType == bitbucket.WebhookDiagnosticsPing {
+			return c.String(http.StatusOK, "OK")
+		}
+
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Failed to read webhook request").SetInternal(err)
+		}
+		var pushEventList []vcs.PushEvent
+		switch eventType {
+		case bitbucket.WebhookRepoPush:
+			var pushEvent bitbucket.WebhookPushEvent
+			if err := json.Unmarshal(body, &pushEvent); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "Malformed push event").SetInternal(err)
+			}
+			pushEventList = pushEvent.ToVCS()
+		case bitbucket.WebhookRepoRefsChanged:
+			var refsChangedEvent bitbucket.WebhookRefsChangedEvent
+			if err := json.Unmarshal(body, &refsChangedEvent); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "Malformed push event").SetInternal(err)
+			}
+			pushEventList = refsChangedEvent.ToVCS()
+		default:
+			// This shouldn't happen as we only setup webhook to receive push event, just in case.
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid webhook event type, got %s, want %s or %s", eventType, bitbucket.WebhookRepoPush, bitbucket.WebhookRepoRefsChanged))
+		}
+
+		createdMessages, err := s.processPushEventListWithoutCommit(ctx, c.Param("id"), pushEventList, func(repo *api.Repository, pushEvent vcs.PushEvent) (bool, error) {
+			ok, err := validateGitHubWebhookSignature256(c.Request().Header.Get("X-Hub-Signature"), repo.WebhookSecretToken, body)
+			if err != nil {
+				return false, echo.NewHTTPError(http.StatusInternalServerError, "Failed to validate Bitbucket webhook signature").SetInternal(err)
+			}
+			if !ok {
+				return false, nil
+			}
+
+			return s.isWebhookEventBranch(pushEvent.Ref, repo)
+		})
+		if err != nil {
+			return err
+		}
+		return c.String(http.StatusOK, strings.Join(createdMessages, "\n"))
+	})
+
+	g.POST("/azure/:id", func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Failed to read webhook request").SetInternal(err)
+		}
+		var pushEvent azure.WebhookPushEvent
+		if err := json.Unmarshal(body, &pushEvent); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed push event").SetInternal(err)
+		}
+		// This shouldn't happen as we only setup webhook to receive push event, just in case.
+		if pushEvent.EventType != "git.push" {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid webhook event type, got %s, want git.push", pushEvent.EventType))
+		}
+		pushEventList, err := pushEvent.ToVCS()
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed push event").SetInternal(err)
+		}
+
+		createdMessages, err := s.processPushEventListWithoutCommit(ctx, c.Param("id"), pushEventList, func(repo *api.Repository, pushEvent vcs.PushEvent) (bool, error) {
+			// Azure DevOps doesn't sign the payload, so we compare the secret in the
+			// header configured along with the service hook subscription.
+			secretToken := c.Request().Header.Get(azure.WebhookSecretHeader)
+			if subtle.ConstantTimeCompare([]byte(secretToken), []byte(repo.WebhookSecretToken)) != 1 {
+				return false, nil
+			}
+
+			return s.isWebhookEventBranch(pushEvent.Ref, repo)
+		})
 		if err != nil {
 			return err
 		}
@@ -146,7 +253,14 @@ func (s *Server) registerWebhookRoutes(g *echo.Group) {
 
 	// id is the webhookEndpointID in repository
 	// This endpoint is generated and injected into GitHub action & GitLab CI during the VCS setup.
+	// The optional format query parameter ("sarif" or "junit") overrides the default output format of the VCS.
 	g.POST("/sql-review/:id", func(c echo.Context) error {
+		format := advisor.ReportFormat(c.QueryParam("format"))
+		if format != "" {
+			if err := advisor.ValidateReportFormat(format); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+			}
+		}
 		body, err := io.ReadAll(c.Request().Body)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Failed to read SQL review request").SetInternal(err)
@@ -230,37 +344,18 @@ func (s *Server) registerWebhookRoutes(g *echo.Group) {
 			})
 		}
 
-		sqlCheckAdvice := map[string][]advisor.Advice{}
-		var wg sync.WaitGroup
-
-		repoID2FileItemList := groupFileInfoByRepo(distinctFileList, repositoryList)
-		for _, fileInfoListInRepo := range repoID2FileItemList {
-			for _, file := range fileInfoListInRepo {
-				wg.Add(1)
-				go func(file fileInfo) {
-					defer wg.Done()
-					adviceList, err := s.sqlAdviceForFile(ctx, file)
-					if err != nil {
-						log.Debug(
-							"Failed to take SQL review for file",
-							zap.String("file", file.item.FileName),
-							zap.String("external_id", file.repository.ExternalID),
-							zap.Error(err),
-						)
-					} else if adviceList != nil {
-						sqlCheckAdvice[file.item.FileName] = adviceList
-					}
-				}(file)
-			}
-		}
-
-		wg.Wait()
+		sqlCheckAdvice := s.sqlAdviceForFileList(ctx, distinctFileList, repositoryList)
 
 		response := &api.VCSSQLReviewResult{}
-		switch repo.VCS.Type {
-		case vcs.GitHubCom:
+		switch {
+		case format != "":
+			response, err = convertSQLAdviceToReportResult(format, sqlCheckAdvice)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to generate %s report", format)).SetInternal(err)
+			}
+		case repo.VCS.Type == vcs.GitHubCom, repo.VCS.Type == vcs.GitHubEnterprise:
 			response = convertSQLAdiceToGitHubActionResult(sqlCheckAdvice)
-		case vcs.GitLabSelfHost:
+		case repo.VCS.Type == vcs.GitLabSelfHost:
 			response = convertSQLAdviceToGitLabCIResult(sqlCheckAdvice)
 		}
 
@@ -274,6 +369,42 @@ func (s *Server) registerWebhookRoutes(g *echo.Group) {
 
 		return c.JSON(http.StatusOK, response)
 	})
+
+	// The Slack interaction request URL configured in the Slack app, which receives the approve and reject actions.
+	g.POST("/slack/interaction", s.handleSlackInteraction)
+}
+
+// sqlAdviceForFileList takes the SQL review for the files concurrently, and returns the advice list keyed by the file name.
+func (s *Server) sqlAdviceForFileList(ctx context.Context, distinctFileList []vcs.DistinctFileItem, repositoryList []*api.Repository) map[string][]advisor.Advice {
+	sqlCheckAdvice := map[string][]advisor.Advice{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	repoID2FileItemList := groupFileInfoByRepo(distinctFileList, repositoryList)
+	for _, fileInfoListInRepo := range repoID2FileItemList {
+		for _, file := range fileInfoListInRepo {
+			wg.Add(1)
+			go func(file fileInfo) {
+				defer wg.Done()
+				adviceList, err := s.sqlAdviceForFile(ctx, file)
+				if err != nil {
+					log.Debug(
+						"Failed to take SQL review for file",
+						zap.String("file", file.item.FileName),
+						zap.String("external_id", file.repository.ExternalID),
+						zap.Error(err),
+					)
+				} else if adviceList != nil {
+					mu.Lock()
+					sqlCheckAdvice[file.item.FileName] = adviceList
+					mu.Unlock()
+				}
+			}(file)
+		}
+	}
+
+	wg.Wait()
+	return sqlCheckAdvice
 }
 
 func (s *Server) sqlAdviceForFile(
@@ -333,8 +464,13 @@ func (s *Server) sqlAdviceForFile(
 	// There may exist many databases that match the file name.
 	// We just need to use the first one, which has the SQL review policy and can let us take the check.
 	for _, database := range databases {
+		dbType, err := advisorDB.ConvertToAdvisorDBType(string(database.Instance.Engine))
+		if err != nil {
+			return nil, errors.Errorf("Failed to convert database engine type %v to advisor db type with error: %v", database.Instance.Engine, err)
+		}
+
 		environmentResourceType := api.PolicyResourceTypeEnvironment
-		policy, err := s.store.GetNormalSQLReviewPolicy(ctx, &api.PolicyFind{ResourceType: &environmentResourceType, ResourceID: &database.Instance.EnvironmentID})
+		policy, err := s.store.GetProjectSQLReviewPolicy(ctx, &api.PolicyFind{ResourceType: &environmentResourceType, ResourceID: &database.Instance.EnvironmentID}, database.ProjectID, dbType)
 		if err != nil {
 			if e, ok := err.(*common.Error); ok && e.Code == common.NotFound {
 				log.Debug("Cannot found SQL review policy in environment", zap.Int("Environment", database.Instance.EnvironmentID), zap.Error(err))
@@ -344,11 +480,6 @@ func (s *Server) sqlAdviceForFile(
 			return nil, errors.Errorf("Failed to get SQL review policy in environment %v with error: %v", database.Instance.EnvironmentID, err)
 		}
 
-		dbType, err := advisorDB.ConvertToAdvisorDBType(string(database.Instance.Engine))
-		if err != nil {
-			return nil, errors.Errorf("Failed to convert database engine type %v to advisor db type with error: %v", database.Instance.Engine, err)
-		}
-
 		catalog, err := s.store.NewCatalog(ctx, database.ID, database.Instance.Engine)
 		if err != nil {
 			return nil, errors.Errorf("Failed to get catalog for database %v with error: %v", database.ID, err)
@@ -433,22 +564,122 @@ func (s *Server) filterRepository(ctx context.Context, webhookEndpointID string,
 	return filteredRepos, nil
 }
 
-func (*Server) isWebhookEventBranch(pushEventRef, branchFilter string) (bool, error) {
+// isWebhookEventBranch returns true if the branch of the event matches the branch filter, or
+// any branch-to-environment rule of the repository.
+func (*Server) isWebhookEventBranch(pushEventRef string, repo *api.Repository) (bool, error) {
 	branch, err := parseBranchNameFromRefs(pushEventRef)
 	if err != nil {
 		return false, echo.NewHTTPError(http.StatusBadRequest, "Invalid ref: %s", pushEventRef).SetInternal(err)
 	}
-	ok, err := filepath.Match(branchFilter, branch)
+	ok, err := filepath.Match(repo.BranchFilter, branch)
 	if err != nil {
 		return false, errors.Wrapf(err, "failed to match branch filter")
 	}
-	if !ok {
-		log.Debug("Skipping repo due to branch filter mismatch", zap.String("branch", branch), zap.String("filter", branchFilter))
+	if ok {
+		return true, nil
+	}
+	rule, err := repo.BranchEnvironmentSetting.FindRule(branch)
+	if err != nil {
+		return false, err
+	}
+	if rule == nil {
+		log.Debug("Skipping repo due to branch filter mismatch", zap.String("branch", branch), zap.String("filter", repo.BranchFilter))
 		return false, nil
 	}
 	return true, nil
 }
 
+// getBranchEnvironment returns the environment mapped from the branch by the branch-to-environment rules
+// of the repository, or nil if no rule matches.
+func (s *Server) getBranchEnvironment(ctx context.Context, repo *api.Repository, ref string) (*api.Environment, error) {
+	if len(repo.BranchEnvironmentSetting.RuleList) == 0 {
+		return nil, nil
+	}
+	branch, err := parseBranchNameFromRefs(ref)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := repo.BranchEnvironmentSetting.FindRule(branch)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		return nil, nil
+	}
+	environment, err := s.store.GetEnvironmentByID(ctx, rule.EnvironmentID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find environment %d", rule.EnvironmentID)
+	}
+	if environment == nil {
+		return nil, errors.Errorf("environment %d of the branch filter %q not found", rule.EnvironmentID, rule.BranchFilter)
+	}
+	return environment, nil
+}
+
+// applyBranchEnvironment sets the environment of the files to the one mapped from the branch, so that
+// the databases in the environment are chosen as the migration targets. The files whose environment
+// in the file path is different from the mapped one are ignored.
+func applyBranchEnvironment(repo *api.Repository, pushEvent vcs.PushEvent, branchEnvironment *api.Environment, fileInfoList []fileInfo) ([]fileInfo, []*api.ActivityCreate) {
+	var filteredList []fileInfo
+	var activityCreateList []*api.ActivityCreate
+	for _, fileInfo := range fileInfoList {
+		if fileInfo.migrationInfo.Environment != "" && !strings.EqualFold(fileInfo.migrationInfo.Environment, branchEnvironment.Name) {
+			err := errors.Errorf("environment %q in the file path mismatches environment %q of branch %q", fileInfo.migrationInfo.Environment, branchEnvironment.Name, strings.TrimPrefix(pushEvent.Ref, "refs/heads/"))
+			activityCreateList = append(activityCreateList, getIgnoredFileActivityCreate(repo.ProjectID, pushEvent, fileInfo.item.FileName, err))
+			continue
+		}
+		fileInfo.migrationInfo.Environment = branchEnvironment.Name
+		filteredList = append(filteredList, fileInfo)
+	}
+	return filteredList, activityCreateList
+}
+
+// processPushEventListWithoutCommit processes the push events whose commit list
+// is not included in the webhook payload, e.g. Bitbucket and Azure DevOps. The
+// commit list is filled from the VCS after the repositories are filtered.
+func (s *Server) processPushEventListWithoutCommit(ctx context.Context, webhookEndpointID string, pushEventList []vcs.PushEvent, filter func(*api.Repository, vcs.PushEvent) (bool, error)) ([]string, error) {
+	var createdMessages []string
+	// A single push may update multiple branches, and each of them is processed separately.
+	for _, pushEvent := range pushEventList {
+		pushEvent := pushEvent
+		repositoryList, err := s.filterRepository(ctx, webhookEndpointID, pushEvent.RepositoryID, func(repo *api.Repository) (bool, error) {
+			return filter(repo, pushEvent)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(repositoryList) == 0 {
+			log.Debug("Empty handle repo list. Ignore this push event.", zap.String("ref", pushEvent.Ref))
+			continue
+		}
+
+		repo := repositoryList[0]
+		if err := vcs.FillPushEventCommit(
+			ctx,
+			vcs.Get(repo.VCS.Type, vcs.ProviderConfig{}),
+			common.OauthContext{
+				ClientID:     repo.VCS.ApplicationID,
+				ClientSecret: repo.VCS.Secret,
+				AccessToken:  repo.AccessToken,
+				RefreshToken: repo.RefreshToken,
+				Refresher:    utils.RefreshToken(ctx, s.store, repo.WebURL),
+			},
+			repo.VCS.InstanceURL,
+			repo.ExternalID,
+			&pushEvent,
+		); err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch the commits of the push event").SetInternal(err)
+		}
+
+		messages, _, err := s.processPushEvent(ctx, repositoryList, pushEvent)
+		if err != nil {
+			return nil, err
+		}
+		createdMessages = append(createdMessages, messages...)
+	}
+	return createdMessages, nil
+}
+
 // validateGitHubWebhookSignature256 returns true if the signature matches the
 // HMAC hex digested SHA256 hash of the body using the given key.
 func validateGitHubWebhookSignature256(signature, key string, body []byte) (bool, error) {
@@ -481,9 +712,11 @@ func parseBranchNameFromRefs(ref string) (string, error) {
 	return ref[len(expectedPrefix):], nil
 }
 
-func (s *Server) processPushEvent(ctx context.Context, repositoryList []*api.Repository, baseVCSPushEvent vcs.PushEvent) ([]string, error) {
+// processPushEvent creates issues from the files in the push event. It returns the created messages, and the
+// project activities created for the ignored files.
+func (s *Server) processPushEvent(ctx context.Context, repositoryList []*api.Repository, baseVCSPushEvent vcs.PushEvent) ([]string, []*api.Activity, error) {
 	if len(repositoryList) == 0 {
-		return nil, errors.Errorf("empty repository list")
+		return nil, nil, errors.Errorf("empty repository list")
 	}
 
 	distinctFileList := baseVCSPushEvent.GetDistinctFileList()
@@ -496,16 +729,29 @@ func (s *Server) processPushEvent(ctx context.Context, repositoryList []*api.Rep
 			zap.String("repoURL", baseVCSPushEvent.RepositoryURL),
 			zap.String("repoName", baseVCSPushEvent.RepositoryFullPath),
 			zap.String("commits", strings.Join(commitIDs, ",")))
-		return nil, nil
+		return nil, nil, nil
+	}
+
+	if baseVCSPushEvent.PullRequestID == "" {
+		var err error
+		distinctFileList, err = s.filterFilesFromPullRequest(ctx, repositoryList, baseVCSPushEvent.Ref, distinctFileList)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(distinctFileList) == 0 {
+			log.Debug("All files in the push event have been processed in the pull requests", zap.String("repoURL", baseVCSPushEvent.RepositoryURL))
+			return nil, nil, nil
+		}
 	}
 
 	repo := repositoryList[0]
 	filteredDistinctFileList, err := s.filterFilesByCommitsDiff(ctx, repo, distinctFileList, baseVCSPushEvent.Before, baseVCSPushEvent.After)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	var createdMessageList []string
+	var activityList []*api.Activity
 	repoID2FileItemList := groupFileInfoByRepo(filteredDistinctFileList, repositoryList)
 	for _, fileInfoListInRepo := range repoID2FileItemList {
 		// There are possibly multiple files in the push event.
@@ -518,6 +764,21 @@ func (s *Server) processPushEvent(ctx context.Context, repositoryList []*api.Rep
 			pushEvent := baseVCSPushEvent
 			pushEvent.VCSType = repository.VCS.Type
 			pushEvent.BaseDirectory = repository.BaseDirectory
+			// The tenant mode project deploys to the databases by the deployment config instead of the environment.
+			if repository.Project.TenantMode != api.TenantModeTenant {
+				branchEnvironment, err := s.getBranchEnvironment(ctx, repository, pushEvent.Ref)
+				if err != nil {
+					return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to get the environment of the branch").SetInternal(err)
+				}
+				if branchEnvironment != nil {
+					var activityCreateList []*api.ActivityCreate
+					fileInfoListSorted, activityCreateList = applyBranchEnvironment(repository, pushEvent, branchEnvironment, fileInfoListSorted)
+					activityList = append(activityList, s.createIgnoredFileActivityList(ctx, activityCreateList)...)
+					if len(fileInfoListSorted) == 0 {
+						continue
+					}
+				}
+			}
 			createdMessage, created, activityCreateList, err := s.processFilesInProject(
 				ctx,
 				pushEvent,
@@ -525,16 +786,12 @@ func (s *Server) processPushEvent(ctx context.Context, repositoryList []*api.Rep
 				fileInfoListSorted,
 			)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			if created {
 				createdMessageList = append(createdMessageList, createdMessage)
 			} else {
-				for _, activityCreate := range activityCreateList {
-					if _, err := s.ActivityManager.CreateActivity(ctx, activityCreate, &activity.Metadata{}); err != nil {
-						log.Warn("Failed to create project activity for the ignored repository files", zap.Error(err))
-					}
-				}
+				activityList = append(activityList, s.createIgnoredFileActivityList(ctx, activityCreateList)...)
 			}
 		}
 	}
@@ -547,7 +804,21 @@ func (s *Server) processPushEvent(ctx context.Context, repositoryList []*api.Rep
 		log.Warn("Ignored push event because no applicable file found in the commit list", zap.Strings("repos", repoURLs))
 	}
 
-	return createdMessageList, nil
+	return createdMessageList, activityList, nil
+}
+
+// createIgnoredFileActivityList creates the project activities for the ignored repository files.
+func (s *Server) createIgnoredFileActivityList(ctx context.Context, activityCreateList []*api.ActivityCreate) []*api.Activity {
+	var activityList []*api.Activity
+	for _, activityCreate := range activityCreateList {
+		createdActivity, err := s.ActivityManager.CreateActivity(ctx, activityCreate, &activity.Metadata{})
+		if err != nil {
+			log.Warn("Failed to create project activity for the ignored repository files", zap.Error(err))
+			continue
+		}
+		activityList = append(activityList, createdActivity)
+	}
+	return activityList
 }
 
 // Users may merge commits from other branches, and some of the commits merged in may already be merged into the main branch.
@@ -735,6 +1006,7 @@ func (s *Server) processFilesInProject(ctx context.Context, pushEvent vcs.PushEv
 	var activityCreateList []*api.ActivityCreate
 	var createdIssueList []string
 	var fileNameList []string
+	var undoFileNameList []string
 
 	creatorID := s.getIssueCreatorID(ctx, pushEvent.CommitList[0].AuthorEmail)
 	for _, fileInfo := range fileInfoList {
@@ -747,7 +1019,7 @@ func (s *Server) processFilesInProject(ctx context.Context, pushEvent vcs.PushEv
 					databaseName := fileInfo.migrationInfo.Database
 					issueName := fmt.Sprintf(issueNameTemplate, databaseName, "Alter schema")
 					issueDescription := fmt.Sprintf("Apply schema diff by file %s", strings.TrimPrefix(fileInfo.item.FileName, repo.BaseDirectory+"/"))
-					if err := s.createIssueFromMigrationDetailList(ctx, issueName, issueDescription, pushEvent, creatorID, repo.ProjectID, migrationDetailListForFile); err != nil {
+					if _, err := s.createIssueFromMigrationDetailList(ctx, issueName, issueDescription, pushEvent, creatorID, repo, migrationDetailListForFile); err != nil {
 						return "", false, activityCreateList, echo.NewHTTPError(http.StatusInternalServerError, "Failed to create issue").SetInternal(err)
 					}
 					createdIssueList = append(createdIssueList, issueName)
@@ -756,6 +1028,15 @@ func (s *Server) processFilesInProject(ctx context.Context, pushEvent vcs.PushEv
 				log.Debug("Ignored schema file for non-SDL project", zap.String("fileName", fileInfo.item.FileName), zap.String("type", string(fileInfo.item.ItemType)))
 			}
 		} else { // fileInfo.fType == migrationFileType
+			if fileInfo.migrationInfo.Undo {
+				// The undo script is stored to create the rollback issue of the migration later, instead of being applied.
+				stored, activityCreateListForFile := s.storeUndoFromFile(ctx, repo, pushEvent, creatorID, fileInfo)
+				activityCreateList = append(activityCreateList, activityCreateListForFile...)
+				if stored {
+					undoFileNameList = append(undoFileNameList, strings.TrimPrefix(fileInfo.item.FileName, repo.BaseDirectory+"/"))
+				}
+				continue
+			}
 			// This is a migration-based DDL or DML file and we would allow it for both DDL and SDL schema change type project.
 			// For DDL schema change type project, this is expected.
 			// For SDL schema change type project, we allow it because:
@@ -771,8 +1052,12 @@ func (s *Server) processFilesInProject(ctx context.Context, pushEvent vcs.PushEv
 		}
 	}
 
+	var messageList []string
+	if len(undoFileNameList) != 0 {
+		messageList = append(messageList, fmt.Sprintf("Stored undo script %q from push event", strings.Join(undoFileNameList, ",")))
+	}
 	if len(migrationDetailList) == 0 {
-		return "", len(createdIssueList) != 0, activityCreateList, nil
+		return strings.Join(messageList, "\n"), len(createdIssueList) != 0 || len(undoFileNameList) != 0, activityCreateList, nil
 	}
 
 	// Create one issue per push event for DDL project, or non-schema files for SDL project.
@@ -787,12 +1072,13 @@ func (s *Server) processFilesInProject(ctx context.Context, pushEvent vcs.PushEv
 	databaseName := fileInfoList[0].migrationInfo.Database
 	issueName := fmt.Sprintf(issueNameTemplate, databaseName, migrateType)
 	issueDescription := fmt.Sprintf("By VCS files:\n\n%s\n", strings.Join(fileNameList, "\n"))
-	if err := s.createIssueFromMigrationDetailList(ctx, issueName, issueDescription, pushEvent, creatorID, repo.ProjectID, migrationDetailList); err != nil {
+	if _, err := s.createIssueFromMigrationDetailList(ctx, issueName, issueDescription, pushEvent, creatorID, repo, migrationDetailList); err != nil {
 		return "", len(createdIssueList) != 0, activityCreateList, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create issue %s", issueName)).SetInternal(err)
 	}
 	createdIssueList = append(createdIssueList, issueName)
+	messageList = append(messageList, fmt.Sprintf("Created issue %q from push event", strings.Join(createdIssueList, ",")))
 
-	return fmt.Sprintf("Created issue %q from push event", strings.Join(createdIssueList, ",")), true, activityCreateList, nil
+	return strings.Join(messageList, "\n"), true, activityCreateList, nil
 }
 
 func sortFilesBySchemaVersion(fileInfoList []fileInfo) []fileInfo {
@@ -806,7 +1092,13 @@ func sortFilesBySchemaVersion(fileInfoList []fileInfo) []fileInfo {
 	return ret
 }
 
-func (s *Server) createIssueFromMigrationDetailList(ctx context.Context, issueName, issueDescription string, pushEvent vcs.PushEvent, creatorID, projectID int, migrationDetailList []*api.MigrationDetail) error {
+// createIssueFromMigrationDetailList creates an issue from the migration details. If the push event is
+// derived from a pull request, the issue is a draft and its tasks are held until the pull request is merged.
+func (s *Server) createIssueFromMigrationDetailList(ctx context.Context, issueName, issueDescription string, pushEvent vcs.PushEvent, creatorID int, repo *api.Repository, migrationDetailList []*api.MigrationDetail) (*api.Issue, error) {
+	projectID := repo.ProjectID
+	if pushEvent.PullRequestID != "" {
+		issueDescription = fmt.Sprintf("Draft issue created from pull request %s, it will be ready to rollout after the pull request is merged.\n\n%s", pushEvent.PullRequestURL, issueDescription)
+	}
 	createContext, err := json.Marshal(
 		&api.MigrationContext{
 			VCSPushEvent: &pushEvent,
@@ -814,7 +1106,7 @@ func (s *Server) createIssueFromMigrationDetailList(ctx context.Context, issueNa
 		},
 	)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal update schema context").SetInternal(err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal update schema context").SetInternal(err)
 	}
 
 	// TODO(d): unify issue type for database changes.
@@ -840,7 +1132,31 @@ func (s *Server) createIssueFromMigrationDetailList(ctx context.Context, issueNa
 		if issueType == api.IssueDatabaseDataUpdate {
 			errMsg = "Failed to create data update issue"
 		}
-		return echo.NewHTTPError(http.StatusInternalServerError, errMsg).SetInternal(err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, errMsg).SetInternal(err)
+	}
+
+	if pushEvent.PullRequestID != "" {
+		var fileList []string
+		for _, commit := range pushEvent.CommitList {
+			fileList = append(fileList, commit.AddedList...)
+		}
+		payload, err := json.Marshal(api.RepositoryPullRequestPayload{
+			URL:      pushEvent.PullRequestURL,
+			FileList: fileList,
+		})
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal repository pull request payload").SetInternal(err)
+		}
+		if _, err := s.store.CreateRepositoryPullRequest(ctx, &api.RepositoryPullRequestCreate{
+			RepositoryID:  repo.ID,
+			IssueID:       issue.ID,
+			PullRequestID: pushEvent.PullRequestID,
+			BaseRef:       pushEvent.Ref,
+			HeadSHA:       pushEvent.After,
+			Payload:       string(payload),
+		}); err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to link issue %d with pull request %s", issue.ID, pushEvent.PullRequestID)).SetInternal(err)
+		}
 	}
 
 	// Create a project activity after successfully creating the issue from the push event.
@@ -852,7 +1168,7 @@ func (s *Server) createIssueFromMigrationDetailList(ctx context.Context, issueNa
 		},
 	)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to construct activity payload").SetInternal(err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to construct activity payload").SetInternal(err)
 	}
 
 	activityCreate := &api.ActivityCreate{
@@ -864,10 +1180,10 @@ func (s *Server) createIssueFromMigrationDetailList(ctx context.Context, issueNa
 		Payload:     string(activityPayload),
 	}
 	if _, err := s.ActivityManager.CreateActivity(ctx, activityCreate, &activity.Metadata{}); err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create project activity after creating issue from repository push event: %d", issue.ID)).SetInternal(err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create project activity after creating issue from repository push event: %d", issue.ID)).SetInternal(err)
 	}
 
-	return nil
+	return issue, nil
 }
 
 func (s *Server) getIssueCreatorID(ctx context.Context, email string) int {
@@ -1149,6 +1465,43 @@ func (s *Server) prepareIssueFromFile(ctx context.Context, repo *api.Repository,
 	return nil, nil
 }
 
+// storeUndoFromFile stores the undo script in the file for the databases matching the file. It returns true if
+// the undo script is stored, and the activities for the ignored file otherwise.
+func (s *Server) storeUndoFromFile(ctx context.Context, repo *api.Repository, pushEvent vcs.PushEvent, creatorID int, fileInfo fileInfo) (bool, []*api.ActivityCreate) {
+	if fileInfo.item.IsYAML {
+		return false, []*api.ActivityCreate{
+			getIgnoredFileActivityCreate(repo.ProjectID, pushEvent, fileInfo.item.FileName, errors.New("YAML undo script is not supported")),
+		}
+	}
+	content, err := s.readFileContent(ctx, pushEvent, repo, fileInfo.item.FileName)
+	if err != nil {
+		return false, []*api.ActivityCreate{
+			getIgnoredFileActivityCreate(repo.ProjectID, pushEvent, fileInfo.item.FileName, errors.Wrap(err, "Failed to read file content")),
+		}
+	}
+	databases, err := s.findProjectDatabases(ctx, repo.ProjectID, fileInfo.migrationInfo.Database, fileInfo.migrationInfo.Environment)
+	if err != nil {
+		return false, []*api.ActivityCreate{
+			getIgnoredFileActivityCreate(repo.ProjectID, pushEvent, fileInfo.item.FileName, errors.Wrap(err, "Failed to find project databases")),
+		}
+	}
+	for _, database := range databases {
+		if _, err := s.store.UpsertMigrationUndo(ctx, &api.MigrationUndoUpsert{
+			UpdaterID:  creatorID,
+			DatabaseID: database.ID,
+			Version:    fileInfo.migrationInfo.Version,
+			Statement:  content,
+			FilePath:   fileInfo.item.FileName,
+			CommitID:   pushEvent.After,
+		}); err != nil {
+			return false, []*api.ActivityCreate{
+				getIgnoredFileActivityCreate(repo.ProjectID, pushEvent, fileInfo.item.FileName, errors.Wrapf(err, "Failed to store undo script for database %q", database.Name)),
+			}
+		}
+	}
+	return true, nil
+}
+
 func (s *Server) tryUpdateTasksFromModifiedFile(ctx context.Context, databases []*api.Database, fileName, schemaVersion, statement string) error {
 	// For modified files, we try to update the existing issue's statement.
 	for _, database := range databases {
@@ -1300,11 +1653,18 @@ func convertSQLAdiceToGitHubActionResult(adviceMap map[string][]advisor.Advice)
 				}
 			}
 
+			column, endColumn := 1, 2
+			if advice.StartPosition != nil && advice.EndPosition != nil && advice.StartPosition.Line == advice.EndPosition.Line {
+				line, column, endColumn = advice.StartPosition.Line, advice.StartPosition.Column, advice.EndPosition.Column
+			}
+
 			msg := fmt.Sprintf(
-				"::%s file=%s,line=%d,col=1,endColumn=2,title=%s (%d)::%s\nDoc: %s#%d",
+				"::%s file=%s,line=%d,col=%d,endColumn=%d,title=%s (%d)::%s\nDoc: %s#%d",
 				prefix,
 				filePath,
 				line,
+				column,
+				endColumn,
 				advice.Title,
 				advice.Code,
 				advice.Content,
@@ -1320,3 +1680,28 @@ func convertSQLAdiceToGitHubActionResult(adviceMap map[string][]advisor.Advice)
 		Content: messageList,
 	}
 }
+
+// convertSQLAdviceToReportResult will convert SQL advice map to the report in SARIF or JUnit format.
+func convertSQLAdviceToReportResult(format advisor.ReportFormat, adviceMap map[string][]advisor.Advice) (*api.VCSSQLReviewResult, error) {
+	report, err := advisor.GenerateReport(format, adviceMap)
+	if err != nil {
+		return nil, err
+	}
+	status := advisor.Success
+	for _, adviceList := range adviceMap {
+		for _, advice := range adviceList {
+			if advice.Code == 0 {
+				continue
+			}
+			if advice.Status == advisor.Error {
+				status = advice.Status
+			} else if advice.Status == advisor.Warn && status != advisor.Error {
+				status = advice.Status
+			}
+		}
+	}
+	return &api.VCSSQLReviewResult{
+		Status:  status,
+		Content: []string{report},
+	}, nil
+}
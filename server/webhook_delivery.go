@@ -0,0 +1,160 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+)
+
+// webhookDeliveryProcessingTimeout is the time after which a processing delivery is considered stale, e.g. Bytebase
+// restarts in the middle of processing it, and the event can be claimed by a redelivery.
+const webhookDeliveryProcessingTimeout = 10 * time.Minute
+
+// webhookDelivery identifies a webhook delivery received from the VCS. The VCS may redeliver
+// the same event, e.g. GitLab retries the webhook on timeout, and users can resend it manually.
+type webhookDelivery struct {
+	// eventUUID is the unique ID of the event, it may be empty if the VCS doesn't provide one.
+	eventUUID string
+	eventType string
+	// ref is the full ref of the branch of the event, because the same commit may be pushed to several branches.
+	ref       string
+	commitSHA string
+}
+
+// getGitLabWebhookDelivery returns the webhook delivery of the GitLab event.
+// The Idempotency-Key header stays the same across the retries of the same event, and
+// the X-Gitlab-Event-UUID header is used for the older GitLab versions without it.
+//
+// Docs: https://docs.gitlab.com/ee/user/project/integrations/webhooks.html#delivery-headers
+func getGitLabWebhookDelivery(header http.Header, eventType, ref, commitSHA string) webhookDelivery {
+	eventUUID := header.Get("Idempotency-Key")
+	if eventUUID == "" {
+		eventUUID = header.Get("X-Gitlab-Event-UUID")
+	}
+	return webhookDelivery{
+		eventUUID: eventUUID,
+		eventType: eventType,
+		ref:       ref,
+		commitSHA: commitSHA,
+	}
+}
+
+// validateGitLabWebhookToken returns true if the X-Gitlab-Token header matches the secret token of the repository.
+// The comparison takes constant time to avoid leaking the secret token by timing.
+func validateGitLabWebhookToken(token, secretToken string) bool {
+	return subtle.ConstantTimeCompare([]byte(token), []byte(secretToken)) == 1
+}
+
+// claimWebhookDelivery claims the delivery for the repositories which haven't processed it yet, and records
+// a duplicate delivery for the others. It returns the claimed repositories and their delivery IDs, which are
+// patched by recordWebhookDelivery after processing the event.
+// The claim is a processing delivery, and the unique indexes on the repository and the event UUID, or the
+// event type, ref and commit make sure at most one of the concurrent redeliveries claims the event. The failed
+// deliveries are excluded from the unique indexes so that they can be retried, and a delivery left processing
+// for longer than webhookDeliveryProcessingTimeout, e.g. by a restart in the middle, is expired as failed.
+func (s *Server) claimWebhookDelivery(ctx context.Context, repositoryList []*api.Repository, delivery webhookDelivery) ([]*api.Repository, map[int]int, error) {
+	var claimedList []*api.Repository
+	deliveryIDMap := make(map[int]int)
+	for _, repo := range repositoryList {
+		if err := s.store.ExpireRepositoryWebhookDelivery(ctx, &api.RepositoryWebhookDeliveryExpire{
+			RepositoryID:  repo.ID,
+			EventUUID:     delivery.eventUUID,
+			EventType:     delivery.eventType,
+			Ref:           delivery.ref,
+			CommitSHA:     delivery.commitSHA,
+			UpdatedBefore: time.Now().Add(-webhookDeliveryProcessingTimeout).Unix(),
+			Message:       "Expired because the processing didn't finish in time",
+		}); err != nil {
+			return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to expire stale webhook delivery for repository %d", repo.ID)).SetInternal(err)
+		}
+
+		claim, err := s.store.CreateRepositoryWebhookDelivery(ctx, &api.RepositoryWebhookDeliveryCreate{
+			RepositoryID: repo.ID,
+			EventUUID:    delivery.eventUUID,
+			EventType:    delivery.eventType,
+			Ref:          delivery.ref,
+			CommitSHA:    delivery.commitSHA,
+			Status:       api.RepositoryWebhookDeliveryProcessing,
+			Message:      "Processing",
+		})
+		if err == nil {
+			claimedList = append(claimedList, repo)
+			deliveryIDMap[repo.ID] = claim.ID
+			continue
+		}
+		if common.ErrorCode(err) != common.Conflict {
+			return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create webhook delivery for repository %d", repo.ID)).SetInternal(err)
+		}
+
+		if _, err := s.store.CreateRepositoryWebhookDelivery(ctx, &api.RepositoryWebhookDeliveryCreate{
+			RepositoryID: repo.ID,
+			EventUUID:    delivery.eventUUID,
+			EventType:    delivery.eventType,
+			Ref:          delivery.ref,
+			CommitSHA:    delivery.commitSHA,
+			Status:       api.RepositoryWebhookDeliveryDuplicate,
+			Message:      "Skipped because the event has been delivered",
+		}); err != nil {
+			return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create webhook delivery for repository %d", repo.ID)).SetInternal(err)
+		}
+	}
+	return claimedList, deliveryIDMap, nil
+}
+
+// recordWebhookDelivery records the outcome of processing the delivery for the repositories claiming it.
+// It's best-effort and the errors are only logged, because the event has been processed anyway.
+func (s *Server) recordWebhookDelivery(ctx context.Context, repositoryList []*api.Repository, deliveryIDMap map[int]int, messageList []string, activityList []*api.Activity, processErr error) {
+	status, message := getWebhookDeliveryResult(messageList, processErr)
+	for _, repo := range repositoryList {
+		var payload api.RepositoryWebhookDeliveryPayload
+		for _, activity := range activityList {
+			// The activities for the ignored files are created in the project of the repository.
+			if activity.ContainerID != repo.ProjectID {
+				continue
+			}
+			payload.ActivityList = append(payload.ActivityList, api.RepositoryWebhookDeliveryActivity{
+				ID:      activity.ID,
+				Level:   activity.Level,
+				Comment: activity.Comment,
+			})
+		}
+		payloadBytes, err := json.Marshal(payload)
+		if err != nil {
+			log.Warn("Failed to marshal webhook delivery payload", zap.Int("repository_id", repo.ID), zap.Error(err))
+			payloadBytes = []byte("{}")
+		}
+		if _, err := s.store.PatchRepositoryWebhookDelivery(ctx, &api.RepositoryWebhookDeliveryPatch{
+			ID:      deliveryIDMap[repo.ID],
+			Status:  status,
+			Message: message,
+			Payload: string(payloadBytes),
+		}); err != nil {
+			log.Warn("Failed to patch webhook delivery", zap.Int("repository_id", repo.ID), zap.Int("delivery_id", deliveryIDMap[repo.ID]), zap.Error(err))
+		}
+	}
+}
+
+// getWebhookDeliveryResult returns the delivery status and message from the result of processing the event.
+func getWebhookDeliveryResult(messageList []string, processErr error) (api.RepositoryWebhookDeliveryStatus, string) {
+	if processErr != nil {
+		if httpErr, ok := processErr.(*echo.HTTPError); ok && httpErr.Internal != nil {
+			return api.RepositoryWebhookDeliveryFailed, fmt.Sprintf("%v: %v", httpErr.Message, httpErr.Internal)
+		}
+		return api.RepositoryWebhookDeliveryFailed, processErr.Error()
+	}
+	if len(messageList) == 0 {
+		return api.RepositoryWebhookDeliveryIgnored, "No issue is created or changed"
+	}
+	return api.RepositoryWebhookDeliveryProcessed, strings.Join(messageList, "\n")
+}
@@ -0,0 +1,484 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/vcs"
+	"github.com/bytebase/bytebase/plugin/vcs/github"
+	"github.com/bytebase/bytebase/plugin/vcs/gitlab"
+	"github.com/bytebase/bytebase/server/component/activity"
+	"github.com/bytebase/bytebase/server/utils"
+)
+
+const (
+	// pullRequestSQLReviewContext is the context of the commit status for the SQL review of the pull request.
+	pullRequestSQLReviewContext = "bytebase/sql-review"
+)
+
+func (s *Server) handleGitHubPullRequestEvent(c echo.Context, body []byte) error {
+	ctx := c.Request().Context()
+
+	var pullRequestEvent github.WebhookPullRequestEvent
+	if err := json.Unmarshal(body, &pullRequestEvent); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformed pull request event").SetInternal(err)
+	}
+	prEvent, ok := pullRequestEvent.ToVCS()
+	if !ok {
+		log.Debug("Ignore the pull request event", zap.String("action", pullRequestEvent.Action))
+		return c.String(http.StatusOK, "OK")
+	}
+
+	filter := func(repo *api.Repository) (bool, error) {
+		ok, err := validateGitHubWebhookSignature256(c.Request().Header.Get("X-Hub-Signature-256"), repo.WebhookSecretToken, body)
+		if err != nil {
+			return false, echo.NewHTTPError(http.StatusInternalServerError, "Failed to validate GitHub webhook signature").SetInternal(err)
+		}
+		if !ok {
+			return false, nil
+		}
+
+		// The migration files are applied to the target branch after the pull request is merged.
+		return s.isWebhookEventBranch(prEvent.BaseRef, repo)
+	}
+	repositoryList, err := s.filterRepository(ctx, c.Param("id"), prEvent.RepositoryID, filter)
+	if err != nil {
+		return err
+	}
+	if len(repositoryList) == 0 {
+		log.Debug("Empty handle repo list. Ignore this pull request event.")
+		return c.String(http.StatusOK, "OK")
+	}
+
+	messages, _, err := s.processPullRequestEvent(ctx, repositoryList, prEvent)
+	if err != nil {
+		return err
+	}
+	return c.String(http.StatusOK, strings.Join(messages, "\n"))
+}
+
+func (s *Server) handleGitLabMergeRequestEvent(c echo.Context, body []byte) error {
+	ctx := c.Request().Context()
+
+	var mergeRequestEvent gitlab.WebhookMergeRequestEvent
+	if err := json.Unmarshal(body, &mergeRequestEvent); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformed merge request event").SetInternal(err)
+	}
+	prEvent, ok := mergeRequestEvent.ToVCS()
+	if !ok {
+		log.Debug("Ignore the merge request event", zap.String("action", mergeRequestEvent.ObjectAttributes.Action))
+		return c.String(http.StatusOK, "OK")
+	}
+
+	filter := func(repo *api.Repository) (bool, error) {
+		if !validateGitLabWebhookToken(c.Request().Header.Get("X-Gitlab-Token"), repo.WebhookSecretToken) {
+			return false, nil
+		}
+
+		// The migration files are applied to the target branch after the merge request is merged.
+		return s.isWebhookEventBranch(prEvent.BaseRef, repo)
+	}
+	repositoryList, err := s.filterRepository(ctx, c.Param("id"), prEvent.RepositoryID, filter)
+	if err != nil {
+		return err
+	}
+	if len(repositoryList) == 0 {
+		log.Debug("Empty handle repo list. Ignore this merge request event.")
+		return c.String(http.StatusOK, "OK")
+	}
+
+	// The action is part of the event type, because the merge request events of different actions share the same head commit.
+	delivery := getGitLabWebhookDelivery(c.Request().Header, fmt.Sprintf("%s.%s", gitlab.WebhookMergeRequest, prEvent.Action), prEvent.BaseRef, prEvent.HeadSHA)
+	repositoryList, deliveryIDMap, err := s.claimWebhookDelivery(ctx, repositoryList, delivery)
+	if err != nil {
+		return err
+	}
+	if len(repositoryList) == 0 {
+		log.Debug("The merge request event has been delivered. Ignore this merge request event.", zap.String("event_uuid", delivery.eventUUID), zap.String("commit", delivery.commitSHA))
+		return c.String(http.StatusOK, "Duplicate")
+	}
+
+	messages, activityList, err := s.processPullRequestEvent(ctx, repositoryList, prEvent)
+	s.recordWebhookDelivery(ctx, repositoryList, deliveryIDMap, messages, activityList, err)
+	if err != nil {
+		return err
+	}
+	return c.String(http.StatusOK, strings.Join(messages, "\n"))
+}
+
+// processPullRequestEvent processes the pull request event for the repositories.
+//  1. When the pull request is opened, we create draft issues for the added migration files, and post the
+//     SQL review result back to the pull request as a comment and a commit status.
+//  2. When new commits are pushed to the pull request, we cancel the previous draft issues and create new ones.
+//  3. When the pull request is merged, the draft issues are ready to rollout.
+//  4. When the pull request is closed without being merged, we cancel the draft issues.
+func (s *Server) processPullRequestEvent(ctx context.Context, repositoryList []*api.Repository, prEvent vcs.PullRequestEvent) ([]string, []*api.Activity, error) {
+	switch prEvent.Action {
+	case vcs.PullRequestActionOpened:
+		return s.createDraftIssueFromPullRequest(ctx, repositoryList, prEvent)
+	case vcs.PullRequestActionUpdated:
+		comment := fmt.Sprintf("Canceled because the pull request %s is updated with new commits.", prEvent.URL)
+		if err := s.cancelPullRequestIssue(ctx, repositoryList, prEvent, comment); err != nil {
+			return nil, nil, err
+		}
+		return s.createDraftIssueFromPullRequest(ctx, repositoryList, prEvent)
+	case vcs.PullRequestActionMerged:
+		messageList, err := s.releasePullRequestIssue(ctx, repositoryList, prEvent)
+		return messageList, nil, err
+	case vcs.PullRequestActionClosed:
+		comment := fmt.Sprintf("Canceled because the pull request %s is closed without being merged.", prEvent.URL)
+		return nil, nil, s.cancelPullRequestIssue(ctx, repositoryList, prEvent, comment)
+	default:
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unsupported pull request action %q", prEvent.Action))
+	}
+}
+
+// createDraftIssueFromPullRequest creates the draft issues from the migration files added in the pull request.
+// It returns the created messages, and the project activities created for the ignored files.
+func (s *Server) createDraftIssueFromPullRequest(ctx context.Context, repositoryList []*api.Repository, prEvent vcs.PullRequestEvent) ([]string, []*api.Activity, error) {
+	repo := repositoryList[0]
+	provider := vcs.Get(repo.VCS.Type, vcs.ProviderConfig{})
+	oauthContext := common.OauthContext{
+		ClientID:     repo.VCS.ApplicationID,
+		ClientSecret: repo.VCS.Secret,
+		AccessToken:  repo.AccessToken,
+		RefreshToken: repo.RefreshToken,
+		Refresher:    utils.RefreshToken(ctx, s.store, repo.WebURL),
+	}
+
+	fileDiffList, err := provider.GetDiffFileList(ctx, oauthContext, repo.VCS.InstanceURL, repo.ExternalID, prEvent.BaseSHA, prEvent.HeadSHA)
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get file diff list for pull request %s", prEvent.URL)).SetInternal(err)
+	}
+	var addedList []string
+	for _, fileDiff := range fileDiffList {
+		// Only the newly added migration files are applied, the same as the push event.
+		if fileDiff.Type == vcs.FileDiffTypeAdded {
+			addedList = append(addedList, fileDiff.Path)
+		}
+	}
+	if len(addedList) == 0 {
+		log.Debug("No file added in the pull request", zap.String("pull_request", prEvent.URL))
+		return nil, nil, nil
+	}
+
+	commit, err := provider.FetchCommitByID(ctx, oauthContext, repo.VCS.InstanceURL, repo.ExternalID, prEvent.HeadSHA)
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch the head commit of pull request %s", prEvent.URL)).SetInternal(err)
+	}
+	// The pull request is treated as a single commit so that all the added files are read from the head commit.
+	commit.AddedList = addedList
+	commit.ModifiedList = nil
+
+	pushEvent := vcs.PushEvent{
+		// The migration files are applied to the target branch after the pull request is merged,
+		// so the target environment is mapped from it.
+		Ref:                prEvent.BaseRef,
+		Before:             prEvent.BaseSHA,
+		After:              prEvent.HeadSHA,
+		RepositoryID:       prEvent.RepositoryID,
+		RepositoryURL:      prEvent.RepositoryURL,
+		RepositoryFullPath: prEvent.RepositoryFullPath,
+		AuthorName:         prEvent.AuthorName,
+		CommitList:         []vcs.Commit{*commit},
+		PullRequestID:      prEvent.PullRequestID,
+		PullRequestURL:     prEvent.URL,
+	}
+	createdMessageList, activityList, err := s.processPushEvent(ctx, repositoryList, pushEvent)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	issueList, err := s.findPullRequestIssueList(ctx, repositoryList, prEvent.PullRequestID, api.RepositoryPullRequestOpen)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(issueList) == 0 {
+		return createdMessageList, activityList, nil
+	}
+
+	// The VCS write-back is best-effort, the draft issues have been created anyway.
+	adviceMap := s.sqlAdviceForFileList(ctx, pushEvent.GetDistinctFileList(), repositoryList)
+	if err := provider.CreatePullRequestComment(ctx, oauthContext, repo.VCS.InstanceURL, repo.ExternalID, prEvent.PullRequestID, s.getPullRequestOpenedComment(issueList, adviceMap)); err != nil {
+		log.Warn("Failed to create pull request comment", zap.String("pull_request", prEvent.URL), zap.Error(err))
+	}
+	if err := provider.CreateCommitStatus(ctx, oauthContext, repo.VCS.InstanceURL, repo.ExternalID, prEvent.HeadSHA, s.getPullRequestSQLReviewStatus(issueList, adviceMap)); err != nil {
+		log.Warn("Failed to create commit status", zap.String("pull_request", prEvent.URL), zap.String("commit", prEvent.HeadSHA), zap.Error(err))
+	}
+
+	return createdMessageList, activityList, nil
+}
+
+// cancelPullRequestIssue cancels the open draft issues created from the pull request.
+func (s *Server) cancelPullRequestIssue(ctx context.Context, repositoryList []*api.Repository, prEvent vcs.PullRequestEvent, comment string) error {
+	pullRequestList, err := s.findRepositoryPullRequestList(ctx, repositoryList, prEvent.PullRequestID, api.RepositoryPullRequestOpen)
+	if err != nil {
+		return err
+	}
+	for _, pullRequest := range pullRequestList {
+		issue, err := s.store.GetIssueByID(ctx, pullRequest.IssueID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get issue %d", pullRequest.IssueID)).SetInternal(err)
+		}
+		if issue != nil && issue.Status == api.IssueOpen {
+			if _, err := s.TaskScheduler.ChangeIssueStatus(ctx, issue, api.IssueCanceled, api.SystemBotID, comment); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to cancel issue %d", issue.ID)).SetInternal(err)
+			}
+		}
+		status := api.RepositoryPullRequestClosed
+		if _, err := s.store.PatchRepositoryPullRequest(ctx, &api.RepositoryPullRequestPatch{
+			ID:     pullRequest.ID,
+			Status: &status,
+		}); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to close pull request %s for issue %d", prEvent.PullRequestID, pullRequest.IssueID)).SetInternal(err)
+		}
+	}
+	return nil
+}
+
+// releasePullRequestIssue marks the draft issues created from the merged pull request as ready, so that the
+// task scheduler starts to schedule their tasks.
+func (s *Server) releasePullRequestIssue(ctx context.Context, repositoryList []*api.Repository, prEvent vcs.PullRequestEvent) ([]string, error) {
+	pullRequestList, err := s.findRepositoryPullRequestList(ctx, repositoryList, prEvent.PullRequestID, api.RepositoryPullRequestOpen)
+	if err != nil {
+		return nil, err
+	}
+
+	var messageList []string
+	var issueList []*api.Issue
+	for _, pullRequest := range pullRequestList {
+		status := api.RepositoryPullRequestMerged
+		if _, err := s.store.PatchRepositoryPullRequest(ctx, &api.RepositoryPullRequestPatch{
+			ID:     pullRequest.ID,
+			Status: &status,
+		}); err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to merge pull request %s for issue %d", prEvent.PullRequestID, pullRequest.IssueID)).SetInternal(err)
+		}
+
+		issue, err := s.store.GetIssueByID(ctx, pullRequest.IssueID)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get issue %d", pullRequest.IssueID)).SetInternal(err)
+		}
+		if issue == nil {
+			continue
+		}
+		held := false
+		if _, err := s.store.PatchPipeline(ctx, &api.PipelinePatch{
+			ID:        issue.PipelineID,
+			UpdaterID: api.SystemBotID,
+			Held:      &held,
+		}); err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to release the pipeline of issue %d", issue.ID)).SetInternal(err)
+		}
+		activityCreate := &api.ActivityCreate{
+			CreatorID:   api.SystemBotID,
+			ContainerID: issue.ID,
+			Type:        api.ActivityIssueCommentCreate,
+			Level:       api.ActivityInfo,
+			Comment:     fmt.Sprintf("The pull request %s is merged, the issue is ready to rollout.", prEvent.URL),
+		}
+		if _, err := s.ActivityManager.CreateActivity(ctx, activityCreate, &activity.Metadata{Issue: issue}); err != nil {
+			log.Warn("Failed to create issue activity after the pull request is merged", zap.Int("issue_id", issue.ID), zap.Error(err))
+		}
+		issueList = append(issueList, issue)
+		messageList = append(messageList, fmt.Sprintf("Issue %q is ready to rollout", issue.Name))
+	}
+	if len(issueList) == 0 {
+		return nil, nil
+	}
+
+	repo := repositoryList[0]
+	comment := fmt.Sprintf("The pull request is merged, the following Bytebase issue(s) are ready to rollout:\n\n%s", s.getPullRequestIssueListMarkdown(issueList))
+	if err := vcs.Get(repo.VCS.Type, vcs.ProviderConfig{}).CreatePullRequestComment(
+		ctx,
+		common.OauthContext{
+			ClientID:     repo.VCS.ApplicationID,
+			ClientSecret: repo.VCS.Secret,
+			AccessToken:  repo.AccessToken,
+			RefreshToken: repo.RefreshToken,
+			Refresher:    utils.RefreshToken(ctx, s.store, repo.WebURL),
+		},
+		repo.VCS.InstanceURL,
+		repo.ExternalID,
+		prEvent.PullRequestID,
+		comment,
+	); err != nil {
+		log.Warn("Failed to create pull request comment", zap.String("pull_request", prEvent.URL), zap.Error(err))
+	}
+	return messageList, nil
+}
+
+// findRepositoryPullRequestList finds the pull request links with the given status in the repositories.
+func (s *Server) findRepositoryPullRequestList(ctx context.Context, repositoryList []*api.Repository, pullRequestID string, status api.RepositoryPullRequestStatus) ([]*api.RepositoryPullRequest, error) {
+	var list []*api.RepositoryPullRequest
+	for _, repo := range repositoryList {
+		repoID := repo.ID
+		pullRequestList, err := s.store.FindRepositoryPullRequest(ctx, &api.RepositoryPullRequestFind{
+			RepositoryID:  &repoID,
+			PullRequestID: &pullRequestID,
+			Status:        &status,
+		})
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find pull request %s for repository %d", pullRequestID, repo.ID)).SetInternal(err)
+		}
+		list = append(list, pullRequestList...)
+	}
+	return list, nil
+}
+
+// findPullRequestIssueList finds the issues created from the pull request with the given status.
+func (s *Server) findPullRequestIssueList(ctx context.Context, repositoryList []*api.Repository, pullRequestID string, status api.RepositoryPullRequestStatus) ([]*api.Issue, error) {
+	pullRequestList, err := s.findRepositoryPullRequestList(ctx, repositoryList, pullRequestID, status)
+	if err != nil {
+		return nil, err
+	}
+	var issueList []*api.Issue
+	for _, pullRequest := range pullRequestList {
+		issue, err := s.store.GetIssueByID(ctx, pullRequest.IssueID)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get issue %d", pullRequest.IssueID)).SetInternal(err)
+		}
+		if issue != nil {
+			issueList = append(issueList, issue)
+		}
+	}
+	return issueList, nil
+}
+
+// filterFilesFromPullRequest filters out the added files which have been processed in the open or merged pull
+// requests targeting the pushed branch, so that we don't create duplicate issues for them when the pull request
+// is merged into the branch.
+func (s *Server) filterFilesFromPullRequest(ctx context.Context, repositoryList []*api.Repository, ref string, distinctFileList []vcs.DistinctFileItem) ([]vcs.DistinctFileItem, error) {
+	var addedFileList []string
+	for _, item := range distinctFileList {
+		if item.ItemType == vcs.FileItemTypeAdded {
+			addedFileList = append(addedFileList, item.FileName)
+		}
+	}
+	if len(addedFileList) == 0 {
+		return distinctFileList, nil
+	}
+
+	var pullRequestList []*api.RepositoryPullRequest
+	for _, repo := range repositoryList {
+		repoID := repo.ID
+		list, err := s.store.FindRepositoryPullRequest(ctx, &api.RepositoryPullRequestFind{
+			RepositoryID: &repoID,
+			BaseRef:      &ref,
+			StatusList:   []api.RepositoryPullRequestStatus{api.RepositoryPullRequestOpen, api.RepositoryPullRequestMerged},
+			FileList:     addedFileList,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find pull requests for repository %d", repo.ID)
+		}
+		pullRequestList = append(pullRequestList, list...)
+	}
+	return filterProcessedFileList(pullRequestList, distinctFileList)
+}
+
+// filterProcessedFileList filters out the added files in the pull request list.
+func filterProcessedFileList(pullRequestList []*api.RepositoryPullRequest, distinctFileList []vcs.DistinctFileItem) ([]vcs.DistinctFileItem, error) {
+	processedFiles := make(map[string]bool)
+	for _, pullRequest := range pullRequestList {
+		var payload api.RepositoryPullRequestPayload
+		if err := json.Unmarshal([]byte(pullRequest.Payload), &payload); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal payload of repository pull request %d", pullRequest.ID)
+		}
+		for _, file := range payload.FileList {
+			processedFiles[file] = true
+		}
+	}
+
+	var filteredDistinctFileList []vcs.DistinctFileItem
+	for _, item := range distinctFileList {
+		if item.ItemType == vcs.FileItemTypeAdded && processedFiles[item.FileName] {
+			log.Debug("Ignored file processed in the pull request", zap.String("file", item.FileName))
+			continue
+		}
+		filteredDistinctFileList = append(filteredDistinctFileList, item)
+	}
+	return filteredDistinctFileList, nil
+}
+
+func (s *Server) getPullRequestIssueListMarkdown(issueList []*api.Issue) string {
+	var lines []string
+	for _, issue := range issueList {
+		lines = append(lines, fmt.Sprintf("- [%s](%s/issue/%s)", issue.Name, s.profile.ExternalURL, api.IssueSlug(issue)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (s *Server) getPullRequestOpenedComment(issueList []*api.Issue, adviceMap map[string][]advisor.Advice) string {
+	var sb strings.Builder
+	_, _ = sb.WriteString("Bytebase created the following draft issue(s) for the migration files in this pull request. ")
+	_, _ = sb.WriteString("The task checks will run in the issue(s), and the issue(s) will be ready to rollout after the pull request is merged.\n\n")
+	_, _ = sb.WriteString(s.getPullRequestIssueListMarkdown(issueList))
+	_, _ = sb.WriteString("\n\n### SQL review\n\n")
+
+	var adviceLines []string
+	for file, adviceList := range adviceMap {
+		for _, advice := range adviceList {
+			if advice.Status == advisor.Success {
+				continue
+			}
+			adviceLines = append(adviceLines, getPullRequestAdviceMarkdown(file, advice))
+		}
+	}
+	if len(adviceLines) == 0 {
+		_, _ = sb.WriteString("All SQL review checks passed.")
+	} else {
+		sort.Strings(adviceLines)
+		_, _ = sb.WriteString(strings.Join(adviceLines, "\n"))
+	}
+	return sb.String()
+}
+
+// getPullRequestAdviceMarkdown returns the markdown list item of the advice, with the offending statement as the code block if any.
+func getPullRequestAdviceMarkdown(file string, advice advisor.Advice) string {
+	location := fmt.Sprintf("line %d", advice.Line)
+	if advice.StartPosition != nil {
+		location = fmt.Sprintf("line %d, column %d", advice.StartPosition.Line, advice.StartPosition.Column)
+	}
+	item := fmt.Sprintf("- `%s` %s: **%s** %s: %s", file, location, advice.Status, advice.Title, advice.Content)
+	if advice.Excerpt == "" {
+		return item
+	}
+	// Indent the code block to keep it in the list item.
+	return fmt.Sprintf("%s\n  ```sql\n  %s\n  ```", item, strings.ReplaceAll(advice.Excerpt, "\n", "\n  "))
+}
+
+func (s *Server) getPullRequestSQLReviewStatus(issueList []*api.Issue, adviceMap map[string][]advisor.Advice) *vcs.CommitStatus {
+	errorCount := 0
+	for _, adviceList := range adviceMap {
+		for _, advice := range adviceList {
+			if advice.Status == advisor.Error {
+				errorCount++
+			}
+		}
+	}
+	status := &vcs.CommitStatus{
+		State:       vcs.CommitStateSuccess,
+		Context:     pullRequestSQLReviewContext,
+		Description: "SQL review passed",
+	}
+	if errorCount > 0 {
+		status.State = vcs.CommitStateFailure
+		status.Description = fmt.Sprintf("SQL review found %d error(s)", errorCount)
+	}
+	if len(issueList) > 0 {
+		status.TargetURL = fmt.Sprintf("%s/issue/%s", s.profile.ExternalURL, api.IssueSlug(issueList[0]))
+	}
+	return status
+}
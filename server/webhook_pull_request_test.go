@@ -0,0 +1,86 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/vcs"
+	"github.com/bytebase/bytebase/plugin/vcs/bitbucket"
+	"github.com/bytebase/bytebase/plugin/vcs/github"
+	"github.com/bytebase/bytebase/plugin/vcs/gitlab"
+)
+
+func TestFilterProcessedFileList(t *testing.T) {
+	pullRequestList := []*api.RepositoryPullRequest{
+		{ID: 101, Payload: `{"url":"https://github.com/org/repo/pull/1","fileList":["bytebase/prod/db__ver1__migrate__create_t1.sql"]}`},
+		{ID: 102, Payload: `{"url":"https://github.com/org/repo/pull/2","fileList":["bytebase/prod/db__ver2__migrate__create_t2.sql"]}`},
+	}
+	distinctFileList := []vcs.DistinctFileItem{
+		{FileName: "bytebase/prod/db__ver1__migrate__create_t1.sql", ItemType: vcs.FileItemTypeAdded},
+		// The modified file is not filtered, because the pull request only creates issues for the added files.
+		{FileName: "bytebase/prod/db__ver2__migrate__create_t2.sql", ItemType: vcs.FileItemTypeModified},
+		{FileName: "bytebase/prod/db__ver3__migrate__create_t3.sql", ItemType: vcs.FileItemTypeAdded},
+	}
+
+	got, err := filterProcessedFileList(pullRequestList, distinctFileList)
+	require.NoError(t, err)
+	require.Equal(t, distinctFileList[1:], got)
+
+	got, err = filterProcessedFileList(nil, distinctFileList)
+	require.NoError(t, err)
+	require.Equal(t, distinctFileList, got)
+
+	_, err = filterProcessedFileList([]*api.RepositoryPullRequest{{ID: 103, Payload: "{"}}, distinctFileList)
+	require.Error(t, err)
+}
+
+func TestGetPullRequestAdviceMarkdown(t *testing.T) {
+	a := require.New(t)
+	advice := advisor.Advice{
+		Status:  advisor.Warn,
+		Title:   "naming.table",
+		Content: "`Book` mismatches table naming convention",
+		Line:    2,
+	}
+	a.Equal("- `db__ver1__migrate.sql` line 2: **WARN** naming.table: `Book` mismatches table naming convention", getPullRequestAdviceMarkdown("db__ver1__migrate.sql", advice))
+
+	advice.StartPosition = &advisor.Position{Line: 1, Column: 14}
+	advice.EndPosition = &advisor.Position{Line: 1, Column: 18}
+	advice.Excerpt = "CREATE TABLE Book (\n  id int\n);"
+	a.Equal("- `db__ver1__migrate.sql` line 1, column 14: **WARN** naming.table: `Book` mismatches table naming convention\n"+
+		"  ```sql\n"+
+		"  CREATE TABLE Book (\n"+
+		"    id int\n"+
+		"  );\n"+
+		"  ```", getPullRequestAdviceMarkdown("db__ver1__migrate.sql", advice))
+}
+
+func TestGetVCSWebhookPayload(t *testing.T) {
+	a := require.New(t)
+
+	payload, err := getVCSWebhookPayload(vcs.GitLabSelfHost, "https://bytebase.example.com", "endpoint", "secret")
+	a.NoError(err)
+	gitlabWebhook := gitlab.WebhookCreate{}
+	a.NoError(json.Unmarshal(payload, &gitlabWebhook))
+	a.Equal("https://bytebase.example.com/hook/gitlab/endpoint", gitlabWebhook.URL)
+	a.True(gitlabWebhook.PushEvents)
+	a.True(gitlabWebhook.MergeRequestsEvents)
+
+	payload, err = getVCSWebhookPayload(vcs.GitHubCom, "https://bytebase.example.com", "endpoint", "secret")
+	a.NoError(err)
+	githubWebhook := github.WebhookCreateOrUpdate{}
+	a.NoError(json.Unmarshal(payload, &githubWebhook))
+	a.Equal("https://bytebase.example.com/hook/github/endpoint", githubWebhook.Config.URL)
+	a.Equal([]string{string(github.WebhookPush), string(github.WebhookPullRequest)}, githubWebhook.Events)
+
+	// The pull request driven migrations are not supported by Bitbucket yet.
+	payload, err = getVCSWebhookPayload(vcs.BitbucketCloud, "https://bytebase.example.com", "endpoint", "secret")
+	a.NoError(err)
+	bitbucketWebhook := bitbucket.CloudWebhookCreateOrUpdate{}
+	a.NoError(json.Unmarshal(payload, &bitbucketWebhook))
+	a.Equal([]string{string(bitbucket.WebhookRepoPush)}, bitbucketWebhook.Events)
+}
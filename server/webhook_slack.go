@@ -0,0 +1,177 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/app/slack"
+	"github.com/bytebase/bytebase/server/component/activity"
+)
+
+// handleSlackInteraction handles the approve and reject actions of the Slack messages posted by the project webhooks.
+// The Slack user is mapped to the Bytebase principal by email, and the result is posted back to the Slack user as an ephemeral message.
+func (s *Server) handleSlackInteraction(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to read Slack interaction request").SetInternal(err)
+	}
+	value, err := s.getAppSlackSetting(ctx)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get Slack setting").SetInternal(err)
+	}
+	if value == nil || value.SigningSecret == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Slack app is not configured")
+	}
+	if err := slack.VerifyRequest(value.SigningSecret, c.Request().Header.Get(slack.TimestampHeader), c.Request().Header.Get(slack.SignatureHeader), body, time.Now()); err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Failed to verify Slack request").SetInternal(err)
+	}
+
+	payload, err := slack.ParseInteractionPayload(body)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformed Slack interaction payload").SetInternal(err)
+	}
+	// Ignore other interactions, e.g. clicking the "View in Bytebase" link button.
+	if payload.Type != slack.InteractionTypeBlockActions || len(payload.Actions) == 0 {
+		return c.String(http.StatusOK, "")
+	}
+	action := payload.Actions[0]
+	if action.ActionID != slack.ActionApprove && action.ActionID != slack.ActionReject {
+		return c.String(http.StatusOK, "")
+	}
+
+	message, err := s.processSlackApprovalAction(ctx, value, payload.User.ID, action.ActionID, action.Value)
+	if err != nil {
+		log.Error("Failed to process Slack approval action",
+			zap.String("slack_user", payload.User.ID),
+			zap.String("action", string(action.ActionID)),
+			zap.String("value", action.Value),
+			zap.Error(err))
+		message = "Failed to process the action, please try again in Bytebase."
+	}
+	if payload.ResponseURL != "" {
+		if err := s.slackProvider.PostResponse(ctx, payload.ResponseURL, message); err != nil {
+			log.Warn("Failed to post Slack interaction response", zap.Error(err))
+		}
+	}
+	return c.String(http.StatusOK, "")
+}
+
+// processSlackApprovalAction approves or rejects the stage on behalf of the principal mapped from the Slack user.
+// It returns the message to the Slack user, and an error only if it fails unexpectedly.
+func (s *Server) processSlackApprovalAction(ctx context.Context, value *api.SettingAppSlackValue, slackUserID string, actionID slack.ActionID, actionValue string) (string, error) {
+	var approval slack.ApprovalValue
+	if err := json.Unmarshal([]byte(actionValue), &approval); err != nil {
+		return "", errors.Wrapf(err, "failed to unmarshal approval value %q", actionValue)
+	}
+
+	email, err := s.slackProvider.GetUserEmail(ctx, value.BotToken, slackUserID)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get email of Slack user %s", slackUserID)
+	}
+	principal, err := s.store.GetPrincipalByEmail(ctx, email)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get principal by email %s", email)
+	}
+	if principal == nil {
+		return fmt.Sprintf("No Bytebase user found with email %s.", email), nil
+	}
+	member, err := s.store.GetMemberByPrincipalID(ctx, principal.ID)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get member by principal ID %d", principal.ID)
+	}
+	if member == nil || member.RowStatus == api.Archived {
+		return fmt.Sprintf("Bytebase user %s is not an active member.", email), nil
+	}
+
+	issue, err := s.store.GetIssueByID(ctx, approval.IssueID)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get issue %d", approval.IssueID)
+	}
+	if issue == nil {
+		return fmt.Sprintf("Issue %d not found.", approval.IssueID), nil
+	}
+	if issue.Status != api.IssueOpen {
+		return fmt.Sprintf("Issue %q is no longer open.", issue.Name), nil
+	}
+	var stage *api.Stage
+	for _, issueStage := range issue.Pipeline.StageList {
+		if issueStage.ID == approval.StageID {
+			stage = issueStage
+			break
+		}
+	}
+	if stage == nil {
+		return fmt.Sprintf("Stage %d not found in issue %q.", approval.StageID, issue.Name), nil
+	}
+
+	switch actionID {
+	case slack.ActionApprove:
+		if err := s.approveStage(ctx, issue.PipelineID, stage, principal.ID); err != nil {
+			switch common.ErrorCode(err) {
+			case common.NotFound:
+				return fmt.Sprintf("No task is pending approval in stage %q of issue %q.", stage.Name, issue.Name), nil
+			case common.NotAuthorized:
+				return fmt.Sprintf("%s is not allowed to approve stage %q of issue %q.", principal.Name, stage.Name, issue.Name), nil
+			}
+			return "", errors.Wrapf(err, "failed to approve stage %d", stage.ID)
+		}
+		return fmt.Sprintf("Approved stage %q of issue %q.", stage.Name, issue.Name), nil
+	case slack.ActionReject:
+		var pendingApprovalTask *api.Task
+		for _, task := range stage.TaskList {
+			if task.Status == api.TaskPendingApproval {
+				pendingApprovalTask = task
+				break
+			}
+		}
+		if pendingApprovalTask == nil {
+			return fmt.Sprintf("No task is pending approval in stage %q of issue %q.", stage.Name, issue.Name), nil
+		}
+		ok, err := s.canPrincipalChangeTaskStatus(ctx, principal.ID, pendingApprovalTask, api.TaskPending)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to validate if the principal can change task status")
+		}
+		if !ok {
+			return fmt.Sprintf("%s is not allowed to reject stage %q of issue %q.", principal.Name, stage.Name, issue.Name), nil
+		}
+
+		// Like the rejection on Feishu, the tasks are kept pending approval and the rejection is recorded as an issue comment.
+		activityPayload, err := json.Marshal(api.ActivityIssueCommentCreatePayload{
+			ExternalApprovalEvent: &api.ExternalApprovalEvent{
+				Type:      api.ExternalApprovalTypeSlack,
+				Action:    api.ExternalApprovalEventActionReject,
+				StageName: stage.Name,
+			},
+			IssueName: issue.Name,
+		})
+		if err != nil {
+			return "", errors.Wrap(err, "failed to marshal ActivityIssueCommentCreatePayload")
+		}
+		activityCreate := &api.ActivityCreate{
+			CreatorID:   principal.ID,
+			ContainerID: issue.ID,
+			Type:        api.ActivityIssueCommentCreate,
+			Level:       api.ActivityInfo,
+			Comment:     "",
+			Payload:     string(activityPayload),
+		}
+		if _, err := s.ActivityManager.CreateActivity(ctx, activityCreate, &activity.Metadata{Issue: issue}); err != nil {
+			return "", errors.Wrap(err, "failed to create activity after Slack approval rejected")
+		}
+		return fmt.Sprintf("Rejected stage %q of issue %q.", stage.Name, issue.Name), nil
+	}
+	return "", errors.Errorf("unknown Slack action %s", actionID)
+}
@@ -1,8 +1,11 @@
 package server
 
 import (
+	"net/http"
 	"testing"
 
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -184,6 +187,20 @@ func TestVCSSQLReview_ConvertSQLAdviceToGitHubActionResult(t *testing.T) {
 	assert.Equal(t, expect, res.Content)
 }
 
+func TestVCSSQLReview_ConvertSQLAdviceToReportResult(t *testing.T) {
+	res, err := convertSQLAdviceToReportResult(advisor.ReportFormatSARIF, mockSQLAdviceMap)
+	assert.NoError(t, err)
+	assert.Equal(t, advisor.Error, res.Status)
+	assert.Equal(t, 1, len(res.Content))
+	assert.Contains(t, res.Content[0], `"ruleId": "naming.index.uk"`)
+
+	res, err = convertSQLAdviceToReportResult(advisor.ReportFormatJUnit, mockSQLAdviceMap)
+	assert.NoError(t, err)
+	assert.Equal(t, advisor.Error, res.Status)
+	assert.Equal(t, 1, len(res.Content))
+	assert.Contains(t, res.Content[0], `<testsuites name="SQL Review" tests="4" failures="4">`)
+}
+
 func TestGetFileInfo(t *testing.T) {
 	t.Run("a SQL format DDL", func(t *testing.T) {
 		mi, fileType, repo, err := getFileInfo(
@@ -338,3 +355,91 @@ func TestGetFileInfo(t *testing.T) {
 		require.EqualError(t, err, "file change should be associated with exactly one project but found project-1, project-2")
 	})
 }
+
+func TestValidateGitLabWebhookToken(t *testing.T) {
+	assert.True(t, validateGitLabWebhookToken("secret", "secret"))
+	assert.False(t, validateGitLabWebhookToken("secret", "secret2"))
+	assert.False(t, validateGitLabWebhookToken("", "secret"))
+}
+
+func TestGetGitLabWebhookDelivery(t *testing.T) {
+	t.Run("idempotency key", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("Idempotency-Key", "key")
+		header.Set("X-Gitlab-Event-UUID", "uuid")
+		got := getGitLabWebhookDelivery(header, "push", "refs/heads/main", "sha")
+		assert.Equal(t, webhookDelivery{eventUUID: "key", eventType: "push", ref: "refs/heads/main", commitSHA: "sha"}, got)
+	})
+
+	t.Run("event uuid", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("X-Gitlab-Event-UUID", "uuid")
+		got := getGitLabWebhookDelivery(header, "push", "refs/heads/main", "sha")
+		assert.Equal(t, webhookDelivery{eventUUID: "uuid", eventType: "push", ref: "refs/heads/main", commitSHA: "sha"}, got)
+	})
+}
+
+func TestGetWebhookDeliveryResult(t *testing.T) {
+	status, message := getWebhookDeliveryResult([]string{"a", "b"}, nil)
+	assert.Equal(t, api.RepositoryWebhookDeliveryProcessed, status)
+	assert.Equal(t, "a\nb", message)
+
+	status, _ = getWebhookDeliveryResult(nil, nil)
+	assert.Equal(t, api.RepositoryWebhookDeliveryIgnored, status)
+
+	status, message = getWebhookDeliveryResult(nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to create issue").SetInternal(errors.New("boom")))
+	assert.Equal(t, api.RepositoryWebhookDeliveryFailed, status)
+	assert.Equal(t, "Failed to create issue: boom", message)
+}
+
+func TestApplyBranchEnvironment(t *testing.T) {
+	repo := &api.Repository{ProjectID: 1}
+	pushEvent := vcs.PushEvent{Ref: "refs/heads/release/1.0"}
+	fileInfoList := []fileInfo{
+		{
+			item:          vcs.DistinctFileItem{FileName: "db##0001##migrate.sql"},
+			migrationInfo: &db.MigrationInfo{Database: "db"},
+		},
+		{
+			item:          vcs.DistinctFileItem{FileName: "staging/db##0002##migrate.sql"},
+			migrationInfo: &db.MigrationInfo{Database: "db", Environment: "Staging"},
+		},
+		{
+			item:          vcs.DistinctFileItem{FileName: "prod/db##0003##migrate.sql"},
+			migrationInfo: &db.MigrationInfo{Database: "db", Environment: "prod"},
+		},
+	}
+
+	got, activityCreateList := applyBranchEnvironment(repo, pushEvent, &api.Environment{Name: "staging"}, fileInfoList)
+	require.Len(t, got, 2)
+	assert.Equal(t, "staging", got[0].migrationInfo.Environment)
+	assert.Equal(t, "staging", got[1].migrationInfo.Environment)
+	require.Len(t, activityCreateList, 1)
+	assert.Equal(t, `Ignored file "prod/db##0003##migrate.sql", environment "prod" in the file path mismatches environment "staging" of branch "release/1.0".`, activityCreateList[0].Comment)
+}
+
+func TestGetImportFileStatus(t *testing.T) {
+	historyList := []*db.MigrationHistory{
+		{Sequence: 1, Type: db.Migrate, Status: db.Done, Version: "0001"},
+		{Sequence: 2, Type: db.Baseline, Status: db.Done, Version: "0003"},
+		{Sequence: 3, Type: db.Migrate, Status: db.Done, Version: "0005"},
+		{Sequence: 4, Type: db.Data, Status: db.Failed, Version: "0006"},
+	}
+	tests := []struct {
+		version string
+		want    api.RepositoryImportFileStatus
+	}{
+		{version: "0001", want: api.RepositoryImportFileApplied},
+		{version: "0005", want: api.RepositoryImportFileApplied},
+		{version: "0004", want: api.RepositoryImportFileOutOfOrder},
+		{version: "0006", want: api.RepositoryImportFileFailed},
+		{version: "0007", want: api.RepositoryImportFilePending},
+	}
+	for _, test := range tests {
+		got, _ := getImportFileStatus(historyList, test.version)
+		assert.Equal(t, test.want, got, test.version)
+	}
+
+	got, _ := getImportFileStatus(nil, "0001")
+	assert.Equal(t, api.RepositoryImportFilePending, got)
+}
@@ -0,0 +1,261 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	webhookPlugin "github.com/bytebase/bytebase/plugin/webhook"
+)
+
+func (s *Server) registerWorkspaceWebhookRoutes(g *echo.Group) {
+	g.GET("/workspace/webhook", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		webhookList, err := s.store.FindWorkspaceWebhook(ctx, &api.WorkspaceWebhookFind{})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch workspace webhook list").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, webhookList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal workspace webhook list response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.POST("/workspace/webhook", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		hookCreate := &api.WorkspaceWebhookCreate{
+			CreatorID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, hookCreate); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create workspace webhook request").SetInternal(err)
+		}
+		if err := validateWorkspaceWebhookLevelList(hookCreate.LevelList); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+
+		webhook, err := s.store.CreateWorkspaceWebhook(ctx, hookCreate)
+		if err != nil {
+			if common.ErrorCode(err) == common.Conflict {
+				return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Webhook url already exists in the workspace: %s", hookCreate.URL))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create workspace webhook").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, webhook); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal create workspace webhook response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.GET("/workspace/webhook/:webhookID", func(c echo.Context) error {
+		webhook, err := s.getWorkspaceWebhookFromParam(c)
+		if err != nil {
+			return err
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, webhook); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal workspace webhook ID response: %v", webhook.ID)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.PATCH("/workspace/webhook/:webhookID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("webhookID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Workspace webhook ID is not a number: %s", c.Param("webhookID"))).SetInternal(err)
+		}
+
+		hookPatch := &api.WorkspaceWebhookPatch{
+			ID:        id,
+			UpdaterID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, hookPatch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed change workspace webhook request").SetInternal(err)
+		}
+		if v := hookPatch.LevelList; v != nil && *v != "" {
+			if err := validateWorkspaceWebhookLevelList(strings.Split(*v, ",")); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+			}
+		}
+
+		webhook, err := s.store.PatchWorkspaceWebhook(ctx, hookPatch)
+		if err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Workspace webhook ID not found: %d", id))
+			}
+			if common.ErrorCode(err) == common.Conflict {
+				return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Webhook url already exists in the workspace: %s", *hookPatch.URL))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to change workspace webhook ID: %v", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, webhook); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal workspace webhook change response: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.DELETE("/workspace/webhook/:webhookID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("webhookID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Workspace webhook ID is not a number: %s", c.Param("webhookID"))).SetInternal(err)
+		}
+
+		hookDelete := &api.WorkspaceWebhookDelete{
+			ID:        id,
+			DeleterID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := s.store.DeleteWorkspaceWebhook(ctx, hookDelete); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to delete workspace webhook ID: %v", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		c.Response().WriteHeader(http.StatusOK)
+		return nil
+	})
+
+	g.GET("/workspace/webhook/:webhookID/test", func(c echo.Context) error {
+		webhook, err := s.getWorkspaceWebhookFromParam(c)
+		if err != nil {
+			return err
+		}
+
+		result := &api.ProjectWebhookTestResult{}
+		if err := webhookPlugin.Post(
+			webhook.Type,
+			webhookPlugin.Context{
+				URL:          webhook.URL,
+				Secret:       webhook.Secret,
+				Level:        webhookPlugin.WebhookInfo,
+				ActivityType: string(api.ActivityInstanceCreate),
+				Title:        fmt.Sprintf("Test webhook %q", webhook.Name),
+				Description:  "This is a test",
+				Link:         s.profile.ExternalURL,
+				CreatorID:    api.SystemBotID,
+				CreatorName:  "Bytebase",
+				CreatorEmail: "support@bytebase.com",
+				CreatedTs:    time.Now().Unix(),
+			},
+		); err != nil {
+			result.Error = err.Error()
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, result); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal workspace webhook test response: %v", webhook.ID)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.GET("/workspace/webhook/:webhookID/delivery", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		webhook, err := s.getWorkspaceWebhookFromParam(c)
+		if err != nil {
+			return err
+		}
+
+		limit := 50
+		if limitStr := c.QueryParam("limit"); limitStr != "" {
+			if limit, err = strconv.Atoi(limitStr); err != nil || limit <= 0 {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter limit is not a positive number: %s", limitStr)).SetInternal(err)
+			}
+		}
+		deliveryList, err := s.store.FindWorkspaceWebhookDelivery(ctx, &api.WorkspaceWebhookDeliveryFind{
+			WorkspaceWebhookID: &webhook.ID,
+			Limit:              &limit,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch delivery list for workspace webhook ID: %d", webhook.ID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, deliveryList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal workspace webhook delivery list response: %d", webhook.ID)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.POST("/workspace/webhook/:webhookID/delivery/:deliveryID/redeliver", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		webhook, err := s.getWorkspaceWebhookFromParam(c)
+		if err != nil {
+			return err
+		}
+
+		deliveryID, err := strconv.Atoi(c.Param("deliveryID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Delivery ID is not a number: %s", c.Param("deliveryID"))).SetInternal(err)
+		}
+		delivery, err := s.store.GetWorkspaceWebhookDeliveryByID(ctx, deliveryID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch workspace webhook delivery ID: %d", deliveryID)).SetInternal(err)
+		}
+		if delivery == nil || delivery.WorkspaceWebhookID != webhook.ID {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Workspace webhook delivery ID not found: %d", deliveryID))
+		}
+		if delivery.Status == api.ProjectWebhookDeliveryPending || delivery.Status == api.ProjectWebhookDeliverySending {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Workspace webhook delivery %d is still pending", deliveryID))
+		}
+
+		// Redelivery creates a new delivery with the same payload, so that the history of the original one is kept.
+		redelivery, err := s.store.CreateWorkspaceWebhookDelivery(ctx, &api.WorkspaceWebhookDeliveryCreate{
+			WorkspaceWebhookID: webhook.ID,
+			ActivityType:       delivery.ActivityType,
+			Payload:            delivery.Payload,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to redeliver workspace webhook delivery ID: %d", deliveryID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, redelivery); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal workspace webhook redelivery response: %d", deliveryID)).SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// getWorkspaceWebhookFromParam returns the workspace webhook of the webhookID parameter, or an echo error.
+func (s *Server) getWorkspaceWebhookFromParam(c echo.Context) (*api.WorkspaceWebhook, error) {
+	ctx := c.Request().Context()
+	id, err := strconv.Atoi(c.Param("webhookID"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Workspace webhook ID is not a number: %s", c.Param("webhookID"))).SetInternal(err)
+	}
+
+	webhook, err := s.store.GetWorkspaceWebhookByID(ctx, id)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch workspace webhook ID: %v", id)).SetInternal(err)
+	}
+	if webhook == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Workspace webhook ID not found: %d", id))
+	}
+	return webhook, nil
+}
+
+// validateWorkspaceWebhookLevelList validates the activity levels received by the workspace webhook.
+func validateWorkspaceWebhookLevelList(levelList []string) error {
+	for _, level := range levelList {
+		switch api.ActivityLevel(level) {
+		case api.ActivityInfo, api.ActivityWarn, api.ActivityError:
+		default:
+			return errors.Errorf("invalid activity level %q, should be one of INFO, WARN and ERROR", level)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgtype"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// CreateAccessToken creates an instance of AccessToken.
+func (s *Store) CreateAccessToken(ctx context.Context, create *api.AccessTokenCreate) (*api.AccessToken, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	scopeList := create.ScopeList
+	if scopeList == nil {
+		scopeList = []string{}
+	}
+	query := `
+		INSERT INTO access_token (
+			creator_id,
+			updater_id,
+			principal_id,
+			name,
+			token_hash,
+			scope_list,
+			project_id,
+			expires_ts
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING ` + accessTokenColumns
+	accessToken, err := scanAccessToken(tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.CreatorID,
+		create.PrincipalID,
+		create.Name,
+		create.TokenHash,
+		scopeList,
+		create.ProjectID,
+		create.ExpiresTs,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return accessToken, nil
+}
+
+// GetAccessToken gets an instance of AccessToken.
+// Returns nil if the access token is not found.
+func (s *Store) GetAccessToken(ctx context.Context, find *api.AccessTokenFind) (*api.AccessToken, error) {
+	list, err := s.FindAccessToken(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+	if len(list) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: errors.Errorf("found %d access tokens with filter %+v, expect 1", len(list), find)}
+	}
+	return list[0], nil
+}
+
+// FindAccessToken finds a list of AccessToken instances.
+func (s *Store) FindAccessToken(ctx context.Context, find *api.AccessTokenFind) ([]*api.AccessToken, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.PrincipalID; v != nil {
+		where, args = append(where, fmt.Sprintf("principal_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.TokenHash; v != nil {
+		where, args = append(where, fmt.Sprintf("token_hash = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT`+accessTokenColumns+`
+		FROM access_token
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	var accessTokenList []*api.AccessToken
+	for rows.Next() {
+		accessToken, err := scanAccessToken(rows)
+		if err != nil {
+			return nil, FormatError(err)
+		}
+		accessTokenList = append(accessTokenList, accessToken)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return accessTokenList, nil
+}
+
+// PatchAccessToken patches an instance of AccessToken.
+func (s *Store) PatchAccessToken(ctx context.Context, patch *api.AccessTokenPatch) (*api.AccessToken, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	// Build UPDATE clause.
+	set, args := []string{"updater_id = $1"}, []interface{}{patch.UpdaterID}
+	if v := patch.LastUsedTs; v != nil {
+		set, args = append(set, fmt.Sprintf("last_used_ts = $%d", len(args)+1)), append(args, *v)
+	}
+	args = append(args, patch.ID)
+
+	accessToken, err := scanAccessToken(tx.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE access_token
+		SET `+strings.Join(set, ", ")+`
+		WHERE id = $%d
+		RETURNING `+accessTokenColumns, len(args)),
+		args...,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("access token ID not found: %d", patch.ID)}
+		}
+		return nil, FormatError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return accessToken, nil
+}
+
+// DeleteAccessToken deletes an existing access token by ID, the token is revoked immediately.
+func (s *Store) DeleteAccessToken(ctx context.Context, delete *api.AccessTokenDelete) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM access_token WHERE id = $1`, delete.ID)
+	if err != nil {
+		return FormatError(err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return FormatError(err)
+	}
+	if rows == 0 {
+		return &common.Error{Code: common.NotFound, Err: errors.Errorf("access token ID not found: %d", delete.ID)}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
+
+const accessTokenColumns = `
+			id,
+			creator_id,
+			created_ts,
+			updated_ts,
+			principal_id,
+			name,
+			token_hash,
+			scope_list,
+			project_id,
+			expires_ts,
+			last_used_ts`
+
+func scanAccessToken(scanner interface{ Scan(...interface{}) error }) (*api.AccessToken, error) {
+	var accessToken api.AccessToken
+	var scopeArray pgtype.TextArray
+	if err := scanner.Scan(
+		&accessToken.ID,
+		&accessToken.CreatorID,
+		&accessToken.CreatedTs,
+		&accessToken.UpdatedTs,
+		&accessToken.PrincipalID,
+		&accessToken.Name,
+		&accessToken.TokenHash,
+		&scopeArray,
+		&accessToken.ProjectID,
+		&accessToken.ExpiresTs,
+		&accessToken.LastUsedTs,
+	); err != nil {
+		return nil, err
+	}
+	if err := scopeArray.AssignTo(&accessToken.ScopeList); err != nil {
+		return nil, err
+	}
+	// Return an empty list rather than null to the client.
+	if accessToken.ScopeList == nil {
+		accessToken.ScopeList = []string{}
+	}
+	return &accessToken, nil
+}
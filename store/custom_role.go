@@ -0,0 +1,225 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgtype"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// CreateCustomRole creates an instance of CustomRole.
+func (s *Store) CreateCustomRole(ctx context.Context, create *api.CustomRoleCreate) (*api.CustomRole, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	permissionList := create.PermissionList
+	if permissionList == nil {
+		permissionList = []string{}
+	}
+	query := `
+		INSERT INTO custom_role (
+			creator_id,
+			updater_id,
+			role,
+			name,
+			description,
+			permission_list
+		)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + customRoleColumns
+	customRole, err := scanCustomRole(tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.CreatorID,
+		create.Role,
+		create.Name,
+		create.Description,
+		permissionList,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return customRole, nil
+}
+
+// GetCustomRole gets an instance of CustomRole.
+// Returns nil if the custom role is not found.
+func (s *Store) GetCustomRole(ctx context.Context, find *api.CustomRoleFind) (*api.CustomRole, error) {
+	list, err := s.FindCustomRole(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+	if len(list) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: errors.Errorf("found %d custom roles with filter %+v, expect 1", len(list), find)}
+	}
+	return list[0], nil
+}
+
+// FindCustomRole finds a list of CustomRole instances.
+func (s *Store) FindCustomRole(ctx context.Context, find *api.CustomRoleFind) ([]*api.CustomRole, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Role; v != nil {
+		where, args = append(where, fmt.Sprintf("role = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT`+customRoleColumns+`
+		FROM custom_role
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	var customRoleList []*api.CustomRole
+	for rows.Next() {
+		customRole, err := scanCustomRole(rows)
+		if err != nil {
+			return nil, FormatError(err)
+		}
+		customRoleList = append(customRoleList, customRole)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return customRoleList, nil
+}
+
+// PatchCustomRole patches an instance of CustomRole.
+func (s *Store) PatchCustomRole(ctx context.Context, patch *api.CustomRolePatch) (*api.CustomRole, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	// Build UPDATE clause.
+	set, args := []string{"updater_id = $1"}, []interface{}{patch.UpdaterID}
+	if v := patch.Name; v != nil {
+		set, args = append(set, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.Description; v != nil {
+		set, args = append(set, fmt.Sprintf("description = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.PermissionList; v != nil {
+		set, args = append(set, fmt.Sprintf("permission_list = $%d", len(args)+1)), append(args, v)
+	}
+	args = append(args, patch.ID)
+
+	customRole, err := scanCustomRole(tx.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE custom_role
+		SET `+strings.Join(set, ", ")+`
+		WHERE id = $%d
+		RETURNING `+customRoleColumns, len(args)),
+		args...,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("custom role ID not found: %d", patch.ID)}
+		}
+		return nil, FormatError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return customRole, nil
+}
+
+// DeleteCustomRole deletes an existing custom role by ID.
+func (s *Store) DeleteCustomRole(ctx context.Context, delete *api.CustomRoleDelete) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM custom_role WHERE id = $1`, delete.ID)
+	if err != nil {
+		return FormatError(err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return FormatError(err)
+	}
+	if rows == 0 {
+		return &common.Error{Code: common.NotFound, Err: errors.Errorf("custom role ID not found: %d", delete.ID)}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
+
+const customRoleColumns = `
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			role,
+			name,
+			description,
+			permission_list`
+
+func scanCustomRole(scanner interface{ Scan(...interface{}) error }) (*api.CustomRole, error) {
+	var customRole api.CustomRole
+	var permissionArray pgtype.TextArray
+	if err := scanner.Scan(
+		&customRole.ID,
+		&customRole.CreatorID,
+		&customRole.CreatedTs,
+		&customRole.UpdaterID,
+		&customRole.UpdatedTs,
+		&customRole.Role,
+		&customRole.Name,
+		&customRole.Description,
+		&permissionArray,
+	); err != nil {
+		return nil, err
+	}
+	if err := permissionArray.AssignTo(&customRole.PermissionList); err != nil {
+		return nil, err
+	}
+	// Return an empty list rather than null to the client.
+	if customRole.PermissionList == nil {
+		customRole.PermissionList = []string{}
+	}
+	return &customRole, nil
+}
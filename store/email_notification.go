@@ -0,0 +1,170 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// CreateEmailNotification creates a pending EmailNotification.
+func (s *Store) CreateEmailNotification(ctx context.Context, create *api.EmailNotificationCreate) (*api.EmailNotification, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	payload := create.Payload
+	if payload == "" {
+		payload = "{}"
+	}
+	query := `
+		INSERT INTO email_notification (
+			receiver_id,
+			activity_id,
+			reason,
+			status,
+			payload
+		)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + emailNotificationColumns
+	notification, err := scanEmailNotification(tx.QueryRowContext(ctx, query,
+		create.ReceiverID,
+		create.ActivityID,
+		create.Reason,
+		api.EmailNotificationPending,
+		payload,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, errors.Wrapf(FormatError(err), "failed to create EmailNotification with EmailNotificationCreate[%+v]", create)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return notification, nil
+}
+
+// FindEmailNotification finds a list of EmailNotification by find, sorted by ID in ascending order.
+func (s *Store) FindEmailNotification(ctx context.Context, find *api.EmailNotificationFind) ([]*api.EmailNotification, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ReceiverID; v != nil {
+		where, args = append(where, fmt.Sprintf("receiver_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Status; v != nil {
+		where, args = append(where, fmt.Sprintf("status = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT`+emailNotificationColumns+`
+		FROM email_notification
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	var list []*api.EmailNotification
+	for rows.Next() {
+		notification, err := scanEmailNotification(rows)
+		if err != nil {
+			return nil, FormatError(err)
+		}
+		list = append(list, notification)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return list, nil
+}
+
+// PatchEmailNotification patches the status of a batch of EmailNotification after an attempt to send them.
+func (s *Store) PatchEmailNotification(ctx context.Context, patch *api.EmailNotificationPatch) error {
+	if len(patch.IDList) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.Rollback()
+
+	args := []interface{}{patch.Status, patch.Error, patch.AttemptCount, patch.NextAttemptTs}
+	var idList []string
+	for _, id := range patch.IDList {
+		idList = append(idList, fmt.Sprintf("$%d", len(args)+1))
+		args = append(args, id)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE email_notification
+		SET status = $1, error = $2, attempt_count = $3, next_attempt_ts = $4
+		WHERE id IN (`+strings.Join(idList, ", ")+`)`,
+		args...,
+	); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
+
+//
+// private functions
+//
+
+const emailNotificationColumns = `
+			id,
+			created_ts,
+			updated_ts,
+			receiver_id,
+			activity_id,
+			reason,
+			status,
+			error,
+			attempt_count,
+			next_attempt_ts,
+			payload`
+
+func scanEmailNotification(scanner interface{ Scan(...interface{}) error }) (*api.EmailNotification, error) {
+	var notification api.EmailNotification
+	if err := scanner.Scan(
+		&notification.ID,
+		&notification.CreatedTs,
+		&notification.UpdatedTs,
+		&notification.ReceiverID,
+		&notification.ActivityID,
+		&notification.Reason,
+		&notification.Status,
+		&notification.Error,
+		&notification.AttemptCount,
+		&notification.NextAttemptTs,
+		&notification.Payload,
+	); err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
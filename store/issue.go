@@ -241,6 +241,7 @@ func (s *Store) CreatePipelineValidateOnly(ctx context.Context, create *api.Pipe
 		ID:        id,
 		Name:      create.Name,
 		Status:    api.PipelineOpen,
+		Held:      create.Held,
 		CreatorID: create.CreatorID,
 		CreatedTs: ts,
 		UpdaterID: create.CreatorID,
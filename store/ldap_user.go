@@ -0,0 +1,88 @@
+package store
+
+import (
+	"context"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+// UpsertLDAPUser links the principal to the entry of the LDAP directory.
+func (s *Store) UpsertLDAPUser(ctx context.Context, upsert *api.LDAPUserUpsert) (*api.LDAPUser, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	var ldapUser api.LDAPUser
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO ldap_user (
+			principal_id,
+			dn
+		)
+		VALUES ($1, $2)
+		ON CONFLICT (principal_id) DO UPDATE SET
+			dn = EXCLUDED.dn
+		RETURNING principal_id, created_ts, updated_ts, dn
+	`,
+		upsert.PrincipalID,
+		upsert.DN,
+	).Scan(
+		&ldapUser.PrincipalID,
+		&ldapUser.CreatedTs,
+		&ldapUser.UpdatedTs,
+		&ldapUser.DN,
+	); err != nil {
+		return nil, FormatError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return &ldapUser, nil
+}
+
+// FindLDAPUser finds all the principals linked to the LDAP directory.
+func (s *Store) FindLDAPUser(ctx context.Context) ([]*api.LDAPUser, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			principal_id,
+			created_ts,
+			updated_ts,
+			dn
+		FROM ldap_user
+		ORDER BY principal_id`,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	var ldapUserList []*api.LDAPUser
+	for rows.Next() {
+		var ldapUser api.LDAPUser
+		if err := rows.Scan(
+			&ldapUser.PrincipalID,
+			&ldapUser.CreatedTs,
+			&ldapUser.UpdatedTs,
+			&ldapUser.DN,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		ldapUserList = append(ldapUserList, &ldapUser)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return ldapUserList, nil
+}
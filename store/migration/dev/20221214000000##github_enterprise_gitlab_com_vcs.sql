@@ -0,0 +1,11 @@
+ALTER TABLE vcs DROP CONSTRAINT vcs_type_check;
+ALTER TABLE vcs ADD CONSTRAINT vcs_type_check CHECK (type IN ('GITLAB_SELF_HOST', 'GITHUB_COM', 'BITBUCKET_CLOUD', 'BITBUCKET_DATA_CENTER', 'AZURE_DEVOPS', 'GITHUB_ENTERPRISE', 'GITLAB_COM'));
+
+ALTER TABLE project DROP CONSTRAINT project_role_provider_check;
+ALTER TABLE project ADD CONSTRAINT project_role_provider_check CHECK (role_provider IN ('BYTEBASE', 'GITLAB_SELF_HOST', 'GITHUB_COM', 'BITBUCKET_CLOUD', 'BITBUCKET_DATA_CENTER', 'AZURE_DEVOPS', 'GITHUB_ENTERPRISE', 'GITLAB_COM'));
+
+ALTER TABLE project_member DROP CONSTRAINT project_member_role_provider_check;
+ALTER TABLE project_member ADD CONSTRAINT project_member_role_provider_check CHECK (role_provider IN ('BYTEBASE', 'GITLAB_SELF_HOST', 'GITHUB_COM', 'BITBUCKET_CLOUD', 'BITBUCKET_DATA_CENTER', 'AZURE_DEVOPS', 'GITHUB_ENTERPRISE', 'GITLAB_COM'));
+
+ALTER TABLE sheet DROP CONSTRAINT sheet_source_check;
+ALTER TABLE sheet ADD CONSTRAINT sheet_source_check CHECK (source IN ('BYTEBASE', 'GITLAB_SELF_HOST', 'GITHUB_COM', 'BITBUCKET_CLOUD', 'BITBUCKET_DATA_CENTER', 'AZURE_DEVOPS', 'GITHUB_ENTERPRISE', 'GITLAB_COM'));
@@ -0,0 +1,26 @@
+-- repository_pull_request stores the issues created from the pull requests in the VCS repository.
+-- The tasks of the issue are held until the pull request is merged.
+CREATE TABLE repository_pull_request (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    repository_id INTEGER NOT NULL REFERENCES repository (id) ON DELETE CASCADE,
+    issue_id INTEGER NOT NULL REFERENCES issue (id),
+    pull_request_id TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('OPEN', 'MERGED', 'CLOSED')),
+    head_sha TEXT NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX idx_repository_pull_request_repository_id_pull_request_id ON repository_pull_request(repository_id, pull_request_id);
+
+CREATE INDEX idx_repository_pull_request_issue_id ON repository_pull_request(issue_id);
+
+ALTER SEQUENCE repository_pull_request_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_repository_pull_request_updated_ts
+BEFORE
+UPDATE
+    ON repository_pull_request FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
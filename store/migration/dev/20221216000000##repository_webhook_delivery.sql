@@ -0,0 +1,27 @@
+-- repository_webhook_delivery stores the webhook deliveries received from the VCS for the repository.
+-- It's used to skip the redelivered events and to show the delivery log.
+CREATE TABLE repository_webhook_delivery (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    repository_id INTEGER NOT NULL REFERENCES repository (id) ON DELETE CASCADE,
+    event_uuid TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    commit_sha TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('PROCESSED', 'IGNORED', 'DUPLICATE', 'FAILED')),
+    message TEXT NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX idx_repository_webhook_delivery_repository_id_event_uuid ON repository_webhook_delivery(repository_id, event_uuid);
+
+CREATE INDEX idx_repository_webhook_delivery_repository_id_commit_sha ON repository_webhook_delivery(repository_id, commit_sha);
+
+ALTER SEQUENCE repository_webhook_delivery_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_repository_webhook_delivery_updated_ts
+BEFORE
+UPDATE
+    ON repository_webhook_delivery FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
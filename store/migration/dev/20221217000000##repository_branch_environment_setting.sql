@@ -0,0 +1,2 @@
+-- The rules mapping the branches to the environments, e.g. {"ruleList": [{"branchFilter": "release/*", "environmentId": 102}]}.
+ALTER TABLE repository ADD COLUMN branch_environment_setting JSONB NOT NULL DEFAULT '{}';
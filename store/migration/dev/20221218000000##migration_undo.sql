@@ -0,0 +1,25 @@
+-- migration_undo stores the undo scripts of the schema migrations committed in the VCS.
+-- It's keyed by the database and the version of the forward migration in the migration history.
+CREATE TABLE migration_undo (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id) ON DELETE CASCADE,
+    version TEXT NOT NULL,
+    statement TEXT NOT NULL,
+    file_path TEXT NOT NULL,
+    commit_id TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_migration_undo_unique_database_id_version ON migration_undo(database_id, version);
+
+ALTER SEQUENCE migration_undo_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_migration_undo_updated_ts
+BEFORE
+UPDATE
+    ON migration_undo FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
@@ -0,0 +1,2 @@
+-- The way to write back the latest schema to the branch, either committing to the branch directly or opening a pull request.
+ALTER TABLE repository ADD COLUMN schema_write_back_type TEXT NOT NULL CHECK (schema_write_back_type IN ('COMMIT', 'PULL_REQUEST')) DEFAULT 'COMMIT';
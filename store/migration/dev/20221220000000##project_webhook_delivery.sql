@@ -0,0 +1,32 @@
+-- secret is used to sign the custom webhook request body with HMAC-SHA256.
+ALTER TABLE project_webhook ADD COLUMN secret TEXT NOT NULL DEFAULT '';
+
+-- project_webhook_delivery stores the outbound deliveries of the project webhooks.
+-- The pending deliveries are retried with exponential backoff, and they survive the server restarts.
+CREATE TABLE project_webhook_delivery (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    project_webhook_id INTEGER NOT NULL REFERENCES project_webhook (id) ON DELETE CASCADE,
+    activity_type TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'SUCCEEDED', 'FAILED')),
+    attempt_count INTEGER NOT NULL DEFAULT 0,
+    next_attempt_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    response_code INTEGER NOT NULL DEFAULT 0,
+    response_body TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    payload JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX idx_project_webhook_delivery_project_webhook_id ON project_webhook_delivery(project_webhook_id);
+
+CREATE INDEX idx_project_webhook_delivery_status_next_attempt_ts ON project_webhook_delivery(status, next_attempt_ts);
+
+ALTER SEQUENCE project_webhook_delivery_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_project_webhook_delivery_updated_ts
+BEFORE
+UPDATE
+    ON project_webhook_delivery FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
@@ -0,0 +1,55 @@
+-- workspace_webhook receives the activities of the whole workspace, filtered by the activity types and levels.
+CREATE TABLE workspace_webhook (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    type TEXT NOT NULL CHECK (type LIKE 'bb.plugin.webhook.%'),
+    name TEXT NOT NULL,
+    url TEXT NOT NULL,
+    activity_list TEXT ARRAY NOT NULL,
+    -- level_list is the activity levels to receive, empty means all levels.
+    level_list TEXT ARRAY NOT NULL DEFAULT '{}',
+    secret TEXT NOT NULL DEFAULT ''
+);
+
+CREATE UNIQUE INDEX idx_workspace_webhook_unique_url ON workspace_webhook(url);
+
+ALTER SEQUENCE workspace_webhook_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_workspace_webhook_updated_ts
+BEFORE
+UPDATE
+    ON workspace_webhook FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- workspace_webhook_delivery stores the outbound deliveries of the workspace webhooks.
+CREATE TABLE workspace_webhook_delivery (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    workspace_webhook_id INTEGER NOT NULL REFERENCES workspace_webhook (id) ON DELETE CASCADE,
+    activity_type TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'SUCCEEDED', 'FAILED')),
+    attempt_count INTEGER NOT NULL DEFAULT 0,
+    next_attempt_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    response_code INTEGER NOT NULL DEFAULT 0,
+    response_body TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    payload JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX idx_workspace_webhook_delivery_workspace_webhook_id ON workspace_webhook_delivery(workspace_webhook_id);
+
+CREATE INDEX idx_workspace_webhook_delivery_status_next_attempt_ts ON workspace_webhook_delivery(status, next_attempt_ts);
+
+ALTER SEQUENCE workspace_webhook_delivery_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_workspace_webhook_delivery_updated_ts
+BEFORE
+UPDATE
+    ON workspace_webhook_delivery FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
@@ -0,0 +1,40 @@
+-- notification_preference stores the email notification preference of the users.
+CREATE TABLE notification_preference (
+    principal_id INTEGER PRIMARY KEY REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    email_approval_needed BOOLEAN NOT NULL DEFAULT TRUE,
+    email_assigned BOOLEAN NOT NULL DEFAULT TRUE,
+    email_mentioned BOOLEAN NOT NULL DEFAULT TRUE,
+    email_subscribed BOOLEAN NOT NULL DEFAULT TRUE,
+    email_digest BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE TRIGGER update_notification_preference_updated_ts
+BEFORE
+UPDATE
+    ON notification_preference FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- email_notification stores the outbound email notifications of the activities.
+CREATE TABLE email_notification (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    receiver_id INTEGER NOT NULL REFERENCES principal (id),
+    activity_id INTEGER NOT NULL REFERENCES activity (id),
+    reason TEXT NOT NULL CHECK (reason IN ('APPROVAL_NEEDED', 'ASSIGNED', 'MENTIONED', 'SUBSCRIBED')),
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'SENT', 'FAILED')),
+    error TEXT NOT NULL DEFAULT '',
+    payload JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX idx_email_notification_status_receiver_id ON email_notification(status, receiver_id);
+
+ALTER SEQUENCE email_notification_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_email_notification_updated_ts
+BEFORE
+UPDATE
+    ON email_notification FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
@@ -0,0 +1,13 @@
+-- ldap_user links the principals to the entries of the LDAP directory.
+CREATE TABLE ldap_user (
+    principal_id INTEGER PRIMARY KEY REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    dn TEXT NOT NULL
+);
+
+CREATE TRIGGER update_ldap_user_updated_ts
+BEFORE
+UPDATE
+    ON ldap_user FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
@@ -0,0 +1,31 @@
+-- access_token stores the long-lived personal access tokens and the service account keys for the API.
+-- Only the SHA-256 hash of the token is stored, the token itself is returned once on creation.
+CREATE TABLE access_token (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    principal_id INTEGER NOT NULL REFERENCES principal (id),
+    name TEXT NOT NULL,
+    token_hash TEXT NOT NULL,
+    -- scope_list is the scopes of the token, empty means the full access of the principal.
+    scope_list TEXT ARRAY NOT NULL DEFAULT '{}',
+    -- project_id restricts the token to the project, 0 means no restriction.
+    project_id INTEGER NOT NULL DEFAULT 0,
+    -- expires_ts is the expiration time of the token, 0 means the token never expires.
+    expires_ts BIGINT NOT NULL DEFAULT 0,
+    last_used_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX idx_access_token_unique_token_hash ON access_token(token_hash);
+
+CREATE INDEX idx_access_token_principal_id ON access_token(principal_id);
+
+ALTER SEQUENCE access_token_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_access_token_updated_ts
+BEFORE
+UPDATE
+    ON access_token FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
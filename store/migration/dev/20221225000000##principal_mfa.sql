@@ -0,0 +1,18 @@
+-- principal_mfa stores the two-factor authentication of the principals.
+CREATE TABLE principal_mfa (
+    principal_id INTEGER PRIMARY KEY REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    -- otp_secret is the secret of the enabled TOTP, empty means the two-factor authentication is disabled.
+    otp_secret TEXT NOT NULL DEFAULT '',
+    -- temp_otp_secret is the secret of the pending enrollment.
+    temp_otp_secret TEXT NOT NULL DEFAULT '',
+    -- recovery_code_hash_list is the SHA-256 hashes of the unused recovery codes.
+    recovery_code_hash_list TEXT ARRAY NOT NULL DEFAULT '{}'
+);
+
+CREATE TRIGGER update_principal_mfa_updated_ts
+BEFORE
+UPDATE
+    ON principal_mfa FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
@@ -0,0 +1,29 @@
+-- custom_role stores the workspace-defined roles composed of permissions.
+-- The custom roles are assigned to the workspace and project members along with the built-in roles.
+CREATE TABLE custom_role (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    -- role is the unique key stored in the member and project_member tables, e.g. RELEASE_MANAGER.
+    role TEXT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    -- permission_list is the list of the workspace permissions and the project permissions.
+    permission_list TEXT ARRAY NOT NULL DEFAULT '{}'
+);
+
+CREATE UNIQUE INDEX idx_custom_role_unique_role ON custom_role(role);
+
+ALTER SEQUENCE custom_role_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_custom_role_updated_ts
+BEFORE
+UPDATE
+    ON custom_role FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- The members can be assigned the custom roles besides the built-in roles.
+ALTER TABLE member DROP CONSTRAINT member_role_check;
+ALTER TABLE project_member DROP CONSTRAINT project_member_role_check;
@@ -0,0 +1,3 @@
+ALTER TABLE repository_pull_request ADD base_ref TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_repository_pull_request_repository_id_base_ref ON repository_pull_request(repository_id, base_ref);
@@ -0,0 +1,20 @@
+ALTER TABLE repository_webhook_delivery DROP CONSTRAINT repository_webhook_delivery_status_check;
+
+ALTER TABLE repository_webhook_delivery ADD CONSTRAINT repository_webhook_delivery_status_check CHECK (status IN ('PROCESSING', 'PROCESSED', 'IGNORED', 'DUPLICATE', 'FAILED'));
+
+-- Mark the deliveries processed concurrently for the same event as duplicate before creating the unique indexes.
+UPDATE repository_webhook_delivery AS d
+SET status = 'DUPLICATE'
+WHERE d.status IN ('PROCESSED', 'IGNORED')
+AND EXISTS (
+    SELECT 1 FROM repository_webhook_delivery AS o
+    WHERE o.repository_id = d.repository_id
+    AND o.status IN ('PROCESSED', 'IGNORED')
+    AND o.id < d.id
+    AND ((d.event_uuid != '' AND o.event_uuid = d.event_uuid) OR (d.commit_sha != '' AND o.event_type = d.event_type AND o.commit_sha = d.commit_sha))
+);
+
+-- An event is claimed by at most one delivery for a repository, the failed deliveries are excluded so that the event can be redelivered.
+CREATE UNIQUE INDEX idx_repository_webhook_delivery_unique_event_uuid ON repository_webhook_delivery(repository_id, event_uuid) WHERE event_uuid != '' AND status IN ('PROCESSING', 'PROCESSED', 'IGNORED');
+
+CREATE UNIQUE INDEX idx_repository_webhook_delivery_unique_commit_sha ON repository_webhook_delivery(repository_id, event_type, commit_sha) WHERE commit_sha != '' AND status IN ('PROCESSING', 'PROCESSED', 'IGNORED');
@@ -0,0 +1,5 @@
+ALTER TABLE principal_mfa ADD last_otp_counter BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE principal_mfa ADD failed_attempt_count INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE principal_mfa ADD locked_until_ts BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,17 @@
+-- GitLab.com shares the same API as the self-hosted GitLab, so it's configured as GITLAB_SELF_HOST with the instance URL https://gitlab.com.
+UPDATE vcs SET type = 'GITLAB_SELF_HOST' WHERE type = 'GITLAB_COM';
+UPDATE project SET role_provider = 'GITLAB_SELF_HOST' WHERE role_provider = 'GITLAB_COM';
+UPDATE project_member SET role_provider = 'GITLAB_SELF_HOST' WHERE role_provider = 'GITLAB_COM';
+UPDATE sheet SET source = 'GITLAB_SELF_HOST' WHERE source = 'GITLAB_COM';
+
+ALTER TABLE vcs DROP CONSTRAINT vcs_type_check;
+ALTER TABLE vcs ADD CONSTRAINT vcs_type_check CHECK (type IN ('GITLAB_SELF_HOST', 'GITHUB_COM', 'BITBUCKET_CLOUD', 'BITBUCKET_DATA_CENTER', 'AZURE_DEVOPS', 'GITHUB_ENTERPRISE'));
+
+ALTER TABLE project DROP CONSTRAINT project_role_provider_check;
+ALTER TABLE project ADD CONSTRAINT project_role_provider_check CHECK (role_provider IN ('BYTEBASE', 'GITLAB_SELF_HOST', 'GITHUB_COM', 'BITBUCKET_CLOUD', 'BITBUCKET_DATA_CENTER', 'AZURE_DEVOPS', 'GITHUB_ENTERPRISE'));
+
+ALTER TABLE project_member DROP CONSTRAINT project_member_role_provider_check;
+ALTER TABLE project_member ADD CONSTRAINT project_member_role_provider_check CHECK (role_provider IN ('BYTEBASE', 'GITLAB_SELF_HOST', 'GITHUB_COM', 'BITBUCKET_CLOUD', 'BITBUCKET_DATA_CENTER', 'AZURE_DEVOPS', 'GITHUB_ENTERPRISE'));
+
+ALTER TABLE sheet DROP CONSTRAINT sheet_source_check;
+ALTER TABLE sheet ADD CONSTRAINT sheet_source_check CHECK (source IN ('BYTEBASE', 'GITLAB_SELF_HOST', 'GITHUB_COM', 'BITBUCKET_CLOUD', 'BITBUCKET_DATA_CENTER', 'AZURE_DEVOPS', 'GITHUB_ENTERPRISE'));
@@ -0,0 +1,10 @@
+-- The pipeline of the draft issue created from an open pull request is held until the pull request is merged.
+ALTER TABLE pipeline ADD COLUMN held BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE pipeline SET held = TRUE
+WHERE id IN (
+    SELECT issue.pipeline_id
+    FROM repository_pull_request
+    JOIN issue ON issue.id = repository_pull_request.issue_id
+    WHERE repository_pull_request.status = 'OPEN'
+);
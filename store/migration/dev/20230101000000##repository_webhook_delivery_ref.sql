@@ -0,0 +1,6 @@
+-- The same commit may be pushed to several branches, and each of them is a different event.
+ALTER TABLE repository_webhook_delivery ADD COLUMN ref TEXT NOT NULL DEFAULT '';
+
+DROP INDEX idx_repository_webhook_delivery_unique_commit_sha;
+
+CREATE UNIQUE INDEX idx_repository_webhook_delivery_unique_commit_sha ON repository_webhook_delivery(repository_id, event_type, ref, commit_sha) WHERE commit_sha != '' AND status IN ('PROCESSING', 'PROCESSED', 'IGNORED');
@@ -0,0 +1,6 @@
+-- The runner claims the due deliveries by transiting them to SENDING before the attempt.
+ALTER TABLE project_webhook_delivery DROP CONSTRAINT project_webhook_delivery_status_check;
+ALTER TABLE project_webhook_delivery ADD CONSTRAINT project_webhook_delivery_status_check CHECK (status IN ('PENDING', 'SENDING', 'SUCCEEDED', 'FAILED'));
+
+ALTER TABLE workspace_webhook_delivery DROP CONSTRAINT workspace_webhook_delivery_status_check;
+ALTER TABLE workspace_webhook_delivery ADD CONSTRAINT workspace_webhook_delivery_status_check CHECK (status IN ('PENDING', 'SENDING', 'SUCCEEDED', 'FAILED'));
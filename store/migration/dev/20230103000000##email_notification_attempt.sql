@@ -0,0 +1,3 @@
+-- The failed attempts to send the email notifications are retried with exponential backoff.
+ALTER TABLE email_notification ADD COLUMN attempt_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE email_notification ADD COLUMN next_attempt_ts BIGINT NOT NULL DEFAULT 0;
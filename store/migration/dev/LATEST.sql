@@ -89,7 +89,8 @@ CREATE TABLE member (
     updater_id INTEGER NOT NULL REFERENCES principal (id),
     updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
     status TEXT NOT NULL CHECK (status IN ('INVITED', 'ACTIVE')),
-    role TEXT NOT NULL CHECK (role IN ('OWNER', 'DBA', 'DEVELOPER')),
+    -- role is one of the built-in roles OWNER, DBA and DEVELOPER, or the role of a custom_role.
+    role TEXT NOT NULL,
     principal_id INTEGER NOT NULL REFERENCES principal (id)
 );
 
@@ -170,7 +171,7 @@ CREATE TABLE project (
     -- db_name_template is only used when a project is in tenant mode.
     -- Empty value means {{DB_NAME}}.
     db_name_template TEXT NOT NULL,
-    role_provider TEXT NOT NULL CHECK (role_provider IN ('BYTEBASE', 'GITLAB_SELF_HOST', 'GITHUB_COM')) DEFAULT 'BYTEBASE',
+    role_provider TEXT NOT NULL CHECK (role_provider IN ('BYTEBASE', 'GITLAB_SELF_HOST', 'GITHUB_COM', 'BITBUCKET_CLOUD', 'BITBUCKET_DATA_CENTER', 'AZURE_DEVOPS', 'GITHUB_ENTERPRISE')) DEFAULT 'BYTEBASE',
     schema_version_type TEXT NOT NULL CHECK (schema_version_type IN ('TIMESTAMP', 'SEMANTIC')) DEFAULT 'TIMESTAMP',
     schema_change_type TEXT NOT NULL CHECK (schema_change_type IN ('DDL', 'SDL')) DEFAULT 'DDL',
     lgtm_check JSONB NOT NULL DEFAULT '{}'
@@ -220,9 +221,10 @@ CREATE TABLE project_member (
     updater_id INTEGER NOT NULL REFERENCES principal (id),
     updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
     project_id INTEGER NOT NULL REFERENCES project (id),
-    role TEXT NOT NULL CHECK (role IN ('OWNER', 'DEVELOPER')),
+    -- role is one of the built-in roles OWNER and DEVELOPER, or the role of a custom_role.
+    role TEXT NOT NULL,
     principal_id INTEGER NOT NULL REFERENCES principal (id),
-    role_provider TEXT NOT NULL CHECK (role_provider IN ('BYTEBASE', 'GITLAB_SELF_HOST', 'GITHUB_COM')) DEFAULT 'BYTEBASE',
+    role_provider TEXT NOT NULL CHECK (role_provider IN ('BYTEBASE', 'GITLAB_SELF_HOST', 'GITHUB_COM', 'BITBUCKET_CLOUD', 'BITBUCKET_DATA_CENTER', 'AZURE_DEVOPS', 'GITHUB_ENTERPRISE')) DEFAULT 'BYTEBASE',
     -- payload is determined by the type of role_provider
     payload JSONB NOT NULL DEFAULT '{}'
 );
@@ -249,7 +251,9 @@ CREATE TABLE project_webhook (
     type TEXT NOT NULL CHECK (type LIKE 'bb.plugin.webhook.%'),
     name TEXT NOT NULL,
     url TEXT NOT NULL,
-    activity_list TEXT ARRAY NOT NULL
+    activity_list TEXT ARRAY NOT NULL,
+    -- secret is used to sign the custom webhook request body with HMAC-SHA256.
+    secret TEXT NOT NULL DEFAULT ''
 );
 
 CREATE INDEX idx_project_webhook_project_id ON project_webhook(project_id);
@@ -264,6 +268,92 @@ UPDATE
     ON project_webhook FOR EACH ROW
 EXECUTE FUNCTION trigger_update_updated_ts();
 
+-- project_webhook_delivery stores the outbound deliveries of the project webhooks.
+-- The pending deliveries are retried with exponential backoff, and they survive the server restarts.
+CREATE TABLE project_webhook_delivery (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    project_webhook_id INTEGER NOT NULL REFERENCES project_webhook (id) ON DELETE CASCADE,
+    activity_type TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'SENDING', 'SUCCEEDED', 'FAILED')),
+    attempt_count INTEGER NOT NULL DEFAULT 0,
+    next_attempt_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    response_code INTEGER NOT NULL DEFAULT 0,
+    response_body TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    payload JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX idx_project_webhook_delivery_project_webhook_id ON project_webhook_delivery(project_webhook_id);
+
+CREATE INDEX idx_project_webhook_delivery_status_next_attempt_ts ON project_webhook_delivery(status, next_attempt_ts);
+
+ALTER SEQUENCE project_webhook_delivery_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_project_webhook_delivery_updated_ts
+BEFORE
+UPDATE
+    ON project_webhook_delivery FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- workspace_webhook receives the activities of the whole workspace, filtered by the activity types and levels.
+CREATE TABLE workspace_webhook (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    type TEXT NOT NULL CHECK (type LIKE 'bb.plugin.webhook.%'),
+    name TEXT NOT NULL,
+    url TEXT NOT NULL,
+    activity_list TEXT ARRAY NOT NULL,
+    -- level_list is the activity levels to receive, empty means all levels.
+    level_list TEXT ARRAY NOT NULL DEFAULT '{}',
+    secret TEXT NOT NULL DEFAULT ''
+);
+
+CREATE UNIQUE INDEX idx_workspace_webhook_unique_url ON workspace_webhook(url);
+
+ALTER SEQUENCE workspace_webhook_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_workspace_webhook_updated_ts
+BEFORE
+UPDATE
+    ON workspace_webhook FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- workspace_webhook_delivery stores the outbound deliveries of the workspace webhooks.
+CREATE TABLE workspace_webhook_delivery (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    workspace_webhook_id INTEGER NOT NULL REFERENCES workspace_webhook (id) ON DELETE CASCADE,
+    activity_type TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'SENDING', 'SUCCEEDED', 'FAILED')),
+    attempt_count INTEGER NOT NULL DEFAULT 0,
+    next_attempt_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    response_code INTEGER NOT NULL DEFAULT 0,
+    response_body TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    payload JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX idx_workspace_webhook_delivery_workspace_webhook_id ON workspace_webhook_delivery(workspace_webhook_id);
+
+CREATE INDEX idx_workspace_webhook_delivery_status_next_attempt_ts ON workspace_webhook_delivery(status, next_attempt_ts);
+
+ALTER SEQUENCE workspace_webhook_delivery_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_workspace_webhook_delivery_updated_ts
+BEFORE
+UPDATE
+    ON workspace_webhook_delivery FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
 -- Instance
 CREATE TABLE instance (
     id SERIAL PRIMARY KEY,
@@ -632,7 +722,9 @@ CREATE TABLE pipeline (
     updater_id INTEGER NOT NULL REFERENCES principal (id),
     updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
     name TEXT NOT NULL,
-    status TEXT NOT NULL CHECK (status IN ('OPEN', 'DONE', 'CANCELED'))
+    status TEXT NOT NULL CHECK (status IN ('OPEN', 'DONE', 'CANCELED')),
+    -- held means the tasks of the pipeline are not scheduled, e.g. the draft issue created from an open pull request.
+    held BOOLEAN NOT NULL DEFAULT FALSE
 );
 
 CREATE INDEX idx_pipeline_status ON pipeline(status);
@@ -908,7 +1000,7 @@ CREATE TABLE vcs (
     updater_id INTEGER NOT NULL REFERENCES principal (id),
     updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
     name TEXT NOT NULL,
-    type TEXT NOT NULL CHECK (type IN ('GITLAB_SELF_HOST', 'GITHUB_COM')),
+    type TEXT NOT NULL CHECK (type IN ('GITLAB_SELF_HOST', 'GITHUB_COM', 'BITBUCKET_CLOUD', 'BITBUCKET_DATA_CENTER', 'AZURE_DEVOPS', 'GITHUB_ENTERPRISE')),
     instance_url TEXT NOT NULL CHECK ((instance_url LIKE 'http://%' OR instance_url LIKE 'https://%') AND instance_url = rtrim(instance_url, '/')),
     api_url TEXT NOT NULL CHECK ((api_url LIKE 'http://%' OR api_url LIKE 'https://%') AND api_url = rtrim(api_url, '/')),
     application_id TEXT NOT NULL,
@@ -973,7 +1065,11 @@ CREATE TABLE repository (
     -- access_token, expires_ts, refresh_token belongs to the user linking the project to the VCS repository.
     access_token TEXT NOT NULL,
     expires_ts BIGINT NOT NULL,
-    refresh_token TEXT NOT NULL
+    refresh_token TEXT NOT NULL,
+    -- The rules mapping the branches to the environments, e.g. {"ruleList": [{"branchFilter": "release/*", "environmentId": 102}]}.
+    branch_environment_setting JSONB NOT NULL DEFAULT '{}',
+    -- The way to write back the latest schema to the branch, either committing to the branch directly or opening a pull request.
+    schema_write_back_type TEXT NOT NULL CHECK (schema_write_back_type IN ('COMMIT', 'PULL_REQUEST')) DEFAULT 'COMMIT'
 );
 
 CREATE UNIQUE INDEX idx_repository_unique_project_id ON repository(project_id);
@@ -1121,7 +1217,7 @@ CREATE TABLE sheet (
     name TEXT NOT NULL,
     statement TEXT NOT NULL,
     visibility TEXT NOT NULL CHECK (visibility IN ('PRIVATE', 'PROJECT', 'PUBLIC')) DEFAULT 'PRIVATE',
-    source TEXT NOT NULL CHECK (source IN ('BYTEBASE', 'GITLAB_SELF_HOST', 'GITHUB_COM')) DEFAULT 'BYTEBASE',
+    source TEXT NOT NULL CHECK (source IN ('BYTEBASE', 'GITLAB_SELF_HOST', 'GITHUB_COM', 'BITBUCKET_CLOUD', 'BITBUCKET_DATA_CENTER', 'AZURE_DEVOPS', 'GITHUB_ENTERPRISE')) DEFAULT 'BYTEBASE',
     type TEXT NOT NULL CHECK (type IN ('SQL')) DEFAULT 'SQL',
     payload JSONB NOT NULL DEFAULT '{}'
 );
@@ -1179,3 +1275,235 @@ BEFORE
 UPDATE
     ON external_approval FOR EACH ROW
 EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- repository_pull_request stores the issues created from the pull requests in the VCS repository.
+-- The pipeline of the issue is held until the pull request is merged.
+CREATE TABLE repository_pull_request (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    repository_id INTEGER NOT NULL REFERENCES repository (id) ON DELETE CASCADE,
+    issue_id INTEGER NOT NULL REFERENCES issue (id),
+    pull_request_id TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('OPEN', 'MERGED', 'CLOSED')),
+    head_sha TEXT NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    base_ref TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_repository_pull_request_repository_id_pull_request_id ON repository_pull_request(repository_id, pull_request_id);
+
+CREATE INDEX idx_repository_pull_request_repository_id_base_ref ON repository_pull_request(repository_id, base_ref);
+
+CREATE INDEX idx_repository_pull_request_issue_id ON repository_pull_request(issue_id);
+
+ALTER SEQUENCE repository_pull_request_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_repository_pull_request_updated_ts
+BEFORE
+UPDATE
+    ON repository_pull_request FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- repository_webhook_delivery stores the webhook deliveries received from the VCS for the repository.
+-- It's used to skip the redelivered events and to show the delivery log.
+CREATE TABLE repository_webhook_delivery (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    repository_id INTEGER NOT NULL REFERENCES repository (id) ON DELETE CASCADE,
+    event_uuid TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    commit_sha TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('PROCESSING', 'PROCESSED', 'IGNORED', 'DUPLICATE', 'FAILED')),
+    message TEXT NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    ref TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_repository_webhook_delivery_repository_id_event_uuid ON repository_webhook_delivery(repository_id, event_uuid);
+
+CREATE INDEX idx_repository_webhook_delivery_repository_id_commit_sha ON repository_webhook_delivery(repository_id, commit_sha);
+
+-- An event is claimed by at most one delivery for a repository, the failed deliveries are excluded so that the event can be redelivered.
+CREATE UNIQUE INDEX idx_repository_webhook_delivery_unique_event_uuid ON repository_webhook_delivery(repository_id, event_uuid) WHERE event_uuid != '' AND status IN ('PROCESSING', 'PROCESSED', 'IGNORED');
+
+CREATE UNIQUE INDEX idx_repository_webhook_delivery_unique_commit_sha ON repository_webhook_delivery(repository_id, event_type, ref, commit_sha) WHERE commit_sha != '' AND status IN ('PROCESSING', 'PROCESSED', 'IGNORED');
+
+ALTER SEQUENCE repository_webhook_delivery_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_repository_webhook_delivery_updated_ts
+BEFORE
+UPDATE
+    ON repository_webhook_delivery FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- migration_undo stores the undo scripts of the schema migrations committed in the VCS.
+-- It's keyed by the database and the version of the forward migration in the migration history.
+CREATE TABLE migration_undo (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id) ON DELETE CASCADE,
+    version TEXT NOT NULL,
+    statement TEXT NOT NULL,
+    file_path TEXT NOT NULL,
+    commit_id TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_migration_undo_unique_database_id_version ON migration_undo(database_id, version);
+
+ALTER SEQUENCE migration_undo_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_migration_undo_updated_ts
+BEFORE
+UPDATE
+    ON migration_undo FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- notification_preference stores the email notification preference of the users.
+CREATE TABLE notification_preference (
+    principal_id INTEGER PRIMARY KEY REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    email_approval_needed BOOLEAN NOT NULL DEFAULT TRUE,
+    email_assigned BOOLEAN NOT NULL DEFAULT TRUE,
+    email_mentioned BOOLEAN NOT NULL DEFAULT TRUE,
+    email_subscribed BOOLEAN NOT NULL DEFAULT TRUE,
+    email_digest BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE TRIGGER update_notification_preference_updated_ts
+BEFORE
+UPDATE
+    ON notification_preference FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- email_notification stores the outbound email notifications of the activities.
+CREATE TABLE email_notification (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    receiver_id INTEGER NOT NULL REFERENCES principal (id),
+    activity_id INTEGER NOT NULL REFERENCES activity (id),
+    reason TEXT NOT NULL CHECK (reason IN ('APPROVAL_NEEDED', 'ASSIGNED', 'MENTIONED', 'SUBSCRIBED')),
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'SENT', 'FAILED')),
+    error TEXT NOT NULL DEFAULT '',
+    payload JSONB NOT NULL DEFAULT '{}',
+    -- attempt_count and next_attempt_ts are used to retry the failed attempts with exponential backoff.
+    attempt_count INTEGER NOT NULL DEFAULT 0,
+    next_attempt_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_email_notification_status_receiver_id ON email_notification(status, receiver_id);
+
+ALTER SEQUENCE email_notification_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_email_notification_updated_ts
+BEFORE
+UPDATE
+    ON email_notification FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- ldap_user links the principals to the entries of the LDAP directory.
+CREATE TABLE ldap_user (
+    principal_id INTEGER PRIMARY KEY REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    dn TEXT NOT NULL
+);
+
+CREATE TRIGGER update_ldap_user_updated_ts
+BEFORE
+UPDATE
+    ON ldap_user FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- access_token stores the long-lived personal access tokens and the service account keys for the API.
+-- Only the SHA-256 hash of the token is stored, the token itself is returned once on creation.
+CREATE TABLE access_token (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    principal_id INTEGER NOT NULL REFERENCES principal (id),
+    name TEXT NOT NULL,
+    token_hash TEXT NOT NULL,
+    -- scope_list is the scopes of the token, empty means the full access of the principal.
+    scope_list TEXT ARRAY NOT NULL DEFAULT '{}',
+    -- project_id restricts the token to the project, 0 means no restriction.
+    project_id INTEGER NOT NULL DEFAULT 0,
+    -- expires_ts is the expiration time of the token, 0 means the token never expires.
+    expires_ts BIGINT NOT NULL DEFAULT 0,
+    last_used_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX idx_access_token_unique_token_hash ON access_token(token_hash);
+
+CREATE INDEX idx_access_token_principal_id ON access_token(principal_id);
+
+ALTER SEQUENCE access_token_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_access_token_updated_ts
+BEFORE
+UPDATE
+    ON access_token FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- principal_mfa stores the two-factor authentication of the principals.
+CREATE TABLE principal_mfa (
+    principal_id INTEGER PRIMARY KEY REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    -- otp_secret is the secret of the enabled TOTP, empty means the two-factor authentication is disabled.
+    otp_secret TEXT NOT NULL DEFAULT '',
+    -- temp_otp_secret is the secret of the pending enrollment.
+    temp_otp_secret TEXT NOT NULL DEFAULT '',
+    -- recovery_code_hash_list is the SHA-256 hashes of the unused recovery codes.
+    recovery_code_hash_list TEXT ARRAY NOT NULL DEFAULT '{}',
+    -- last_otp_counter is the time step counter of the last accepted TOTP code, the codes of the same or earlier time steps are rejected.
+    last_otp_counter BIGINT NOT NULL DEFAULT 0,
+    -- failed_attempt_count is the number of the consecutive failed verifications.
+    failed_attempt_count INTEGER NOT NULL DEFAULT 0,
+    -- locked_until_ts is the time until which the verification is locked after too many failed attempts.
+    locked_until_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE TRIGGER update_principal_mfa_updated_ts
+BEFORE
+UPDATE
+    ON principal_mfa FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- custom_role stores the workspace-defined roles composed of permissions.
+-- The custom roles are assigned to the workspace and project members along with the built-in roles.
+CREATE TABLE custom_role (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    -- role is the unique key stored in the member and project_member tables, e.g. RELEASE_MANAGER.
+    role TEXT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    -- permission_list is the list of the workspace permissions and the project permissions.
+    permission_list TEXT ARRAY NOT NULL DEFAULT '{}'
+);
+
+CREATE UNIQUE INDEX idx_custom_role_unique_role ON custom_role(role);
+
+ALTER SEQUENCE custom_role_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_custom_role_updated_ts
+BEFORE
+UPDATE
+    ON custom_role FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
@@ -0,0 +1,168 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+// UpsertMigrationUndo upserts the undo script of the migration version of a database.
+func (s *Store) UpsertMigrationUndo(ctx context.Context, upsert *api.MigrationUndoUpsert) (*api.MigrationUndo, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	undo, err := upsertMigrationUndoImpl(ctx, tx, upsert)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to upsert MigrationUndo with MigrationUndoUpsert[%+v]", upsert)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return undo, nil
+}
+
+// FindMigrationUndo finds a list of MigrationUndo by find.
+func (s *Store) FindMigrationUndo(ctx context.Context, find *api.MigrationUndoFind) ([]*api.MigrationUndo, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	list, err := findMigrationUndoImpl(ctx, tx, find)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find MigrationUndo with MigrationUndoFind[%+v]", find)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return list, nil
+}
+
+// GetMigrationUndo gets the undo script of the migration version of a database.
+// Returns nil if the undo script is not found.
+func (s *Store) GetMigrationUndo(ctx context.Context, databaseID int, version string) (*api.MigrationUndo, error) {
+	list, err := s.FindMigrationUndo(ctx, &api.MigrationUndoFind{
+		DatabaseID: &databaseID,
+		Version:    &version,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+	return list[0], nil
+}
+
+//
+// private functions
+//
+
+func upsertMigrationUndoImpl(ctx context.Context, tx *Tx, upsert *api.MigrationUndoUpsert) (*api.MigrationUndo, error) {
+	query := `
+		INSERT INTO migration_undo (
+			creator_id,
+			updater_id,
+			database_id,
+			version,
+			statement,
+			file_path,
+			commit_id
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT(database_id, version) DO UPDATE SET
+			updater_id = EXCLUDED.updater_id,
+			statement = EXCLUDED.statement,
+			file_path = EXCLUDED.file_path,
+			commit_id = EXCLUDED.commit_id
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, version, statement, file_path, commit_id
+	`
+	var undo api.MigrationUndo
+	if err := tx.QueryRowContext(ctx, query,
+		upsert.UpdaterID,
+		upsert.UpdaterID,
+		upsert.DatabaseID,
+		upsert.Version,
+		upsert.Statement,
+		upsert.FilePath,
+		upsert.CommitID,
+	).Scan(
+		&undo.ID,
+		&undo.CreatorID,
+		&undo.CreatedTs,
+		&undo.UpdaterID,
+		&undo.UpdatedTs,
+		&undo.DatabaseID,
+		&undo.Version,
+		&undo.Statement,
+		&undo.FilePath,
+		&undo.CommitID,
+	); err != nil {
+		return nil, FormatError(err)
+	}
+	return &undo, nil
+}
+
+func findMigrationUndoImpl(ctx context.Context, tx *Tx, find *api.MigrationUndoFind) ([]*api.MigrationUndo, error) {
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.DatabaseID; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Version; v != nil {
+		where, args = append(where, fmt.Sprintf("version = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			database_id,
+			version,
+			statement,
+			file_path,
+			commit_id
+		FROM migration_undo
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY database_id, version`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	var list []*api.MigrationUndo
+	for rows.Next() {
+		var undo api.MigrationUndo
+		if err := rows.Scan(
+			&undo.ID,
+			&undo.CreatorID,
+			&undo.CreatedTs,
+			&undo.UpdaterID,
+			&undo.UpdatedTs,
+			&undo.DatabaseID,
+			&undo.Version,
+			&undo.Statement,
+			&undo.FilePath,
+			&undo.CommitID,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		list = append(list, &undo)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+	return list, nil
+}
@@ -0,0 +1,122 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+// GetNotificationPreference gets the notification preference of the user.
+// Returns the default preference if the user hasn't set it.
+func (s *Store) GetNotificationPreference(ctx context.Context, principalID int) (*api.NotificationPreference, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	preference, err := getNotificationPreferenceImpl(ctx, tx, principalID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get NotificationPreference for principal %d", principalID)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return preference, nil
+}
+
+// UpsertNotificationPreference upserts the notification preference of the user.
+// The fields not set in upsert are kept, or take the default values if the preference doesn't exist.
+func (s *Store) UpsertNotificationPreference(ctx context.Context, upsert *api.NotificationPreferenceUpsert) (*api.NotificationPreference, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	preference, err := getNotificationPreferenceImpl(ctx, tx, upsert.PrincipalID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get NotificationPreference for principal %d", upsert.PrincipalID)
+	}
+	if v := upsert.EmailApprovalNeeded; v != nil {
+		preference.EmailApprovalNeeded = *v
+	}
+	if v := upsert.EmailAssigned; v != nil {
+		preference.EmailAssigned = *v
+	}
+	if v := upsert.EmailMentioned; v != nil {
+		preference.EmailMentioned = *v
+	}
+	if v := upsert.EmailSubscribed; v != nil {
+		preference.EmailSubscribed = *v
+	}
+	if v := upsert.EmailDigest; v != nil {
+		preference.EmailDigest = *v
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO notification_preference (
+			principal_id,
+			email_approval_needed,
+			email_assigned,
+			email_mentioned,
+			email_subscribed,
+			email_digest
+		)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (principal_id) DO UPDATE SET
+			email_approval_needed = EXCLUDED.email_approval_needed,
+			email_assigned = EXCLUDED.email_assigned,
+			email_mentioned = EXCLUDED.email_mentioned,
+			email_subscribed = EXCLUDED.email_subscribed,
+			email_digest = EXCLUDED.email_digest
+	`,
+		preference.PrincipalID,
+		preference.EmailApprovalNeeded,
+		preference.EmailAssigned,
+		preference.EmailMentioned,
+		preference.EmailSubscribed,
+		preference.EmailDigest,
+	); err != nil {
+		return nil, FormatError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return preference, nil
+}
+
+//
+// private functions
+//
+
+func getNotificationPreferenceImpl(ctx context.Context, tx *Tx, principalID int) (*api.NotificationPreference, error) {
+	preference := api.NotificationPreference{PrincipalID: principalID}
+	if err := tx.QueryRowContext(ctx, `
+		SELECT
+			email_approval_needed,
+			email_assigned,
+			email_mentioned,
+			email_subscribed,
+			email_digest
+		FROM notification_preference
+		WHERE principal_id = $1`,
+		principalID,
+	).Scan(
+		&preference.EmailApprovalNeeded,
+		&preference.EmailAssigned,
+		&preference.EmailMentioned,
+		&preference.EmailSubscribed,
+		&preference.EmailDigest,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return api.DefaultNotificationPreference(principalID), nil
+		}
+		return nil, FormatError(err)
+	}
+	return &preference, nil
+}
@@ -714,6 +714,9 @@ func FormatError(err error) error {
 			return common.Errorf(common.Conflict, "The project key already exists")
 		case strings.Contains(err.Error(), "idx_project_member_unique_project_id_role_provider_principal_id"):
 			return common.Errorf(common.Conflict, "project member already exists")
+		case strings.Contains(err.Error(), "idx_repository_webhook_delivery_unique_event_uuid"),
+			strings.Contains(err.Error(), "idx_repository_webhook_delivery_unique_commit_sha"):
+			return common.Errorf(common.Conflict, "webhook delivery already exists")
 		case strings.Contains(err.Error(), "idx_project_webhook_unique_project_id_url"):
 			return common.Errorf(common.Conflict, "webhook url already exists")
 		case strings.Contains(err.Error(), "idx_instance_user_unique_instance_id_name"):
@@ -738,6 +741,8 @@ func FormatError(err error) error {
 			return common.Errorf(common.Conflict, "bookmark already exists")
 		case strings.Contains(err.Error(), "idx_repository_unique_project_id"):
 			return common.Errorf(common.Conflict, "project has already linked repository")
+		case strings.Contains(err.Error(), "idx_custom_role_unique_role"):
+			return common.Errorf(common.Conflict, "custom role already exists")
 		case strings.Contains(err.Error(), "idx_label_key_unique_key"):
 			return common.Errorf(common.Conflict, "label key already exists")
 		case strings.Contains(err.Error(), "idx_label_value_unique_key_value"):
@@ -28,6 +28,7 @@ type pipelineRaw struct {
 	// Domain specific fields
 	Name   string
 	Status api.PipelineStatus
+	Held   bool
 }
 
 // toPipeline creates an instance of Pipeline based on the pipelineRaw.
@@ -45,6 +46,7 @@ func (raw *pipelineRaw) toPipeline() *api.Pipeline {
 		// Domain specific fields
 		Name:   raw.Name,
 		Status: raw.Status,
+		Held:   raw.Held,
 	}
 }
 
@@ -282,16 +284,18 @@ func (*Store) createPipelineImpl(ctx context.Context, tx *Tx, create *api.Pipeli
 			creator_id,
 			updater_id,
 			name,
-			status
+			status,
+			held
 		)
-		VALUES ($1, $2, $3, 'OPEN')
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, name, status
+		VALUES ($1, $2, $3, 'OPEN', $4)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, name, status, held
 	`
 	var pipelineRaw pipelineRaw
 	if err := tx.QueryRowContext(ctx, query,
 		create.CreatorID,
 		create.CreatorID,
 		create.Name,
+		create.Held,
 	).Scan(
 		&pipelineRaw.ID,
 		&pipelineRaw.CreatorID,
@@ -300,6 +304,7 @@ func (*Store) createPipelineImpl(ctx context.Context, tx *Tx, create *api.Pipeli
 		&pipelineRaw.UpdatedTs,
 		&pipelineRaw.Name,
 		&pipelineRaw.Status,
+		&pipelineRaw.Held,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
@@ -327,7 +332,8 @@ func (*Store) findPipelineImpl(ctx context.Context, tx *Tx, find *api.PipelineFi
 			updater_id,
 			updated_ts,
 			name,
-			status
+			status,
+			held
 		FROM pipeline
 		WHERE `+strings.Join(where, " AND "),
 		args...,
@@ -349,6 +355,7 @@ func (*Store) findPipelineImpl(ctx context.Context, tx *Tx, find *api.PipelineFi
 			&pipelineRaw.UpdatedTs,
 			&pipelineRaw.Name,
 			&pipelineRaw.Status,
+			&pipelineRaw.Held,
 		); err != nil {
 			return nil, FormatError(err)
 		}
@@ -369,6 +376,9 @@ func (*Store) patchPipelineImpl(ctx context.Context, tx *Tx, patch *api.Pipeline
 	if v := patch.Status; v != nil {
 		set, args = append(set, fmt.Sprintf("status = $%d", len(args)+1)), append(args, api.PipelineStatus(*v))
 	}
+	if v := patch.Held; v != nil {
+		set, args = append(set, fmt.Sprintf("held = $%d", len(args)+1)), append(args, *v)
+	}
 
 	args = append(args, patch.ID)
 
@@ -378,7 +388,7 @@ func (*Store) patchPipelineImpl(ctx context.Context, tx *Tx, patch *api.Pipeline
 		UPDATE pipeline
 		SET `+strings.Join(set, ", ")+`
 		WHERE id = $%d
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, name, status
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, name, status, held
 	`, len(args)),
 		args...,
 	).Scan(
@@ -389,6 +399,7 @@ func (*Store) patchPipelineImpl(ctx context.Context, tx *Tx, patch *api.Pipeline
 		&pipelineRaw.UpdatedTs,
 		&pipelineRaw.Name,
 		&pipelineRaw.Status,
+		&pipelineRaw.Held,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("pipeline ID not found: %d", patch.ID)}
@@ -11,6 +11,7 @@ import (
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/plugin/advisor"
+	advisorDB "github.com/bytebase/bytebase/plugin/advisor/db"
 )
 
 // policyRaw is the store model for an Policy.
@@ -232,6 +233,51 @@ func (s *Store) GetNormalSQLReviewPolicy(ctx context.Context, find *api.PolicyFi
 	return api.UnmarshalSQLReviewPolicy(policy.Payload)
 }
 
+// GetProjectSQLReviewPolicy will get the SQL review policy applied to the project in the environment.
+// The project policy is resolved on its own first. If it inherits from the parent, its rules override the rules of the environment policy
+// found by environmentFind with the same type, otherwise it replaces the environment policy.
+// The rules left without the level or payload are filled by the prod template of the engine.
+// Returns NotFound error if neither the environment nor the project has a normal SQL review policy.
+func (s *Store) GetProjectSQLReviewPolicy(ctx context.Context, environmentFind *api.PolicyFind, projectID int, engine advisorDB.Type) (*advisor.SQLReviewPolicy, error) {
+	projectResourceType := api.PolicyResourceTypeProject
+	policy, err := s.getPolicyRaw(ctx, &api.PolicyFind{
+		ResourceType: &projectResourceType,
+		ResourceID:   &projectID,
+		Type:         api.PolicyTypeSQLReview,
+	})
+	if err != nil {
+		return nil, err
+	}
+	hasProjectPolicy := policy.ID != api.DefaultPolicyID && policy.RowStatus != api.Archived
+	if hasProjectPolicy && !policy.InheritFromParent {
+		projectPolicy, err := api.UnmarshalSQLReviewPolicy(policy.Payload)
+		if err != nil {
+			return nil, err
+		}
+		return advisor.MergeSQLReviewPolicy(nil, projectPolicy, engine)
+	}
+
+	environmentPolicy, err := s.GetNormalSQLReviewPolicy(ctx, environmentFind)
+	if err != nil {
+		if common.ErrorCode(err) != common.NotFound {
+			return nil, err
+		}
+		environmentPolicy = nil
+	}
+	if !hasProjectPolicy {
+		if environmentPolicy == nil {
+			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("SQL review policy for project %d not found", projectID)}
+		}
+		return environmentPolicy, nil
+	}
+
+	projectPolicy, err := api.UnmarshalSQLReviewPolicy(policy.Payload)
+	if err != nil {
+		return nil, err
+	}
+	return advisor.MergeSQLReviewPolicy(environmentPolicy, projectPolicy, engine)
+}
+
 // GetSQLReviewPolicyIDByEnvID will get the SQL review policy ID for an environment.
 func (s *Store) GetSQLReviewPolicyIDByEnvID(ctx context.Context, environmentID int) (int, error) {
 	environmentResourceType := api.PolicyResourceTypeEnvironment
@@ -329,11 +375,13 @@ func (s *Store) composePolicy(ctx context.Context, raw *policyRaw) (*api.Policy,
 	}
 	policy.Updater = updater
 
-	env, err := s.GetEnvironmentByID(ctx, policy.ResourceID)
-	if err != nil {
-		return nil, err
+	if policy.ResourceType == api.PolicyResourceTypeEnvironment {
+		env, err := s.GetEnvironmentByID(ctx, policy.ResourceID)
+		if err != nil {
+			return nil, err
+		}
+		policy.Environment = env
 	}
-	policy.Environment = env
 
 	return policy, nil
 }
@@ -412,6 +460,7 @@ func findPolicyImpl(ctx context.Context, tx *Tx, find *api.PolicyFind) ([]*polic
 			updater_id,
 			updated_ts,
 			row_status,
+			resource_type,
 			resource_id,
 			inherit_from_parent,
 			type,
@@ -436,6 +485,7 @@ func findPolicyImpl(ctx context.Context, tx *Tx, find *api.PolicyFind) ([]*polic
 			&policyRaw.UpdaterID,
 			&policyRaw.UpdatedTs,
 			&policyRaw.RowStatus,
+			&policyRaw.ResourceType,
 			&policyRaw.ResourceID,
 			&policyRaw.InheritFromParent,
 			&policyRaw.Type,
@@ -513,7 +563,7 @@ func upsertPolicyImpl(ctx context.Context, tx *Tx, upsert *api.PolicyUpsert) (*p
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		ON CONFLICT(resource_type, resource_id, type) DO UPDATE SET
 			%s
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, row_status, resource_id, inherit_from_parent, type, payload
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, row_status, resource_type, resource_id, inherit_from_parent, type, payload
 	`, strings.Join(set, ","))
 	var policyRaw policyRaw
 	if err := tx.QueryRowContext(ctx, query,
@@ -532,6 +582,7 @@ func upsertPolicyImpl(ctx context.Context, tx *Tx, upsert *api.PolicyUpsert) (*p
 		&policyRaw.UpdaterID,
 		&policyRaw.UpdatedTs,
 		&policyRaw.RowStatus,
+		&policyRaw.ResourceType,
 		&policyRaw.ResourceID,
 		&policyRaw.InheritFromParent,
 		&policyRaw.Type,
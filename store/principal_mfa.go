@@ -0,0 +1,226 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgtype"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+// GetPrincipalMFA gets the two-factor authentication of the principal.
+// Returns nil if the principal has never enrolled.
+func (s *Store) GetPrincipalMFA(ctx context.Context, principalID int) (*api.PrincipalMFA, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	principalMFA, err := scanPrincipalMFA(tx.QueryRowContext(ctx, `
+		SELECT`+principalMFAColumns+`
+		FROM principal_mfa
+		WHERE principal_id = $1`,
+		principalID,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, FormatError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return principalMFA, nil
+}
+
+// UpsertPrincipalMFA upserts the two-factor authentication of the principal.
+func (s *Store) UpsertPrincipalMFA(ctx context.Context, upsert *api.PrincipalMFAUpsert) (*api.PrincipalMFA, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	recoveryCodeHashList := upsert.RecoveryCodeHashList
+	if recoveryCodeHashList == nil {
+		recoveryCodeHashList = []string{}
+	}
+	principalMFA, err := scanPrincipalMFA(tx.QueryRowContext(ctx, `
+		INSERT INTO principal_mfa (
+			principal_id,
+			otp_secret,
+			temp_otp_secret,
+			recovery_code_hash_list
+		)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (principal_id) DO UPDATE SET
+			otp_secret = EXCLUDED.otp_secret,
+			temp_otp_secret = EXCLUDED.temp_otp_secret,
+			recovery_code_hash_list = EXCLUDED.recovery_code_hash_list,
+			failed_attempt_count = 0,
+			locked_until_ts = 0
+		RETURNING `+principalMFAColumns,
+		upsert.PrincipalID,
+		upsert.OTPSecret,
+		upsert.TempOTPSecret,
+		recoveryCodeHashList,
+	))
+	if err != nil {
+		return nil, FormatError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return principalMFA, nil
+}
+
+// ConsumePrincipalMFARecoveryCode removes the recovery code hash from the principal's unused recovery codes.
+// Returns false if the recovery code is not found, so that each recovery code can only be used once even with concurrent logins.
+func (s *Store) ConsumePrincipalMFARecoveryCode(ctx context.Context, principalID int, recoveryCodeHash string) (bool, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE principal_mfa
+		SET recovery_code_hash_list = array_remove(recovery_code_hash_list, $2)
+		WHERE principal_id = $1 AND $2 = ANY(recovery_code_hash_list)`,
+		principalID,
+		recoveryCodeHash,
+	)
+	if err != nil {
+		return false, FormatError(err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, FormatError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, FormatError(err)
+	}
+	return rows > 0, nil
+}
+
+// ConsumePrincipalMFAOTPCounter updates the last accepted TOTP counter of the principal.
+// Returns false if the counter is not greater than the last one, so that each TOTP code can only be used once even with concurrent logins.
+func (s *Store) ConsumePrincipalMFAOTPCounter(ctx context.Context, principalID int, counter int64) (bool, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE principal_mfa
+		SET last_otp_counter = $2
+		WHERE principal_id = $1 AND last_otp_counter < $2`,
+		principalID,
+		counter,
+	)
+	if err != nil {
+		return false, FormatError(err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, FormatError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, FormatError(err)
+	}
+	return rows > 0, nil
+}
+
+// RecordPrincipalMFAFailedAttempt increases the failed verification count of the principal. The verification is locked
+// until lockedUntilTs once the count reaches maxFailedAttemptCount, and the count starts over after the lock.
+func (s *Store) RecordPrincipalMFAFailedAttempt(ctx context.Context, principalID int, maxFailedAttemptCount int, lockedUntilTs int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE principal_mfa
+		SET
+			failed_attempt_count = CASE WHEN failed_attempt_count + 1 >= $2 THEN 0 ELSE failed_attempt_count + 1 END,
+			locked_until_ts = CASE WHEN failed_attempt_count + 1 >= $2 THEN $3 ELSE locked_until_ts END
+		WHERE principal_id = $1`,
+		principalID,
+		maxFailedAttemptCount,
+		lockedUntilTs,
+	); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
+
+// ResetPrincipalMFAFailedAttempt resets the failed verification count of the principal after a successful verification.
+func (s *Store) ResetPrincipalMFAFailedAttempt(ctx context.Context, principalID int) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE principal_mfa
+		SET failed_attempt_count = 0
+		WHERE principal_id = $1 AND failed_attempt_count > 0`,
+		principalID,
+	); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
+
+const principalMFAColumns = `
+			principal_id,
+			created_ts,
+			updated_ts,
+			otp_secret,
+			temp_otp_secret,
+			recovery_code_hash_list,
+			last_otp_counter,
+			failed_attempt_count,
+			locked_until_ts`
+
+func scanPrincipalMFA(scanner interface{ Scan(...interface{}) error }) (*api.PrincipalMFA, error) {
+	var principalMFA api.PrincipalMFA
+	var recoveryCodeHashArray pgtype.TextArray
+	if err := scanner.Scan(
+		&principalMFA.PrincipalID,
+		&principalMFA.CreatedTs,
+		&principalMFA.UpdatedTs,
+		&principalMFA.OTPSecret,
+		&principalMFA.TempOTPSecret,
+		&recoveryCodeHashArray,
+		&principalMFA.LastOTPCounter,
+		&principalMFA.FailedAttemptCount,
+		&principalMFA.LockedUntilTs,
+	); err != nil {
+		return nil, err
+	}
+	if err := recoveryCodeHashArray.AssignTo(&principalMFA.RecoveryCodeHashList); err != nil {
+		return nil, err
+	}
+	principalMFA.Enabled = principalMFA.OTPSecret != ""
+	principalMFA.RecoveryCodeCount = len(principalMFA.RecoveryCodeHashList)
+	return &principalMFA, nil
+}
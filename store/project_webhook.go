@@ -32,6 +32,7 @@ type projectWebhookRaw struct {
 	Name         string
 	URL          string
 	ActivityList []string
+	Secret       string
 }
 
 // toProjectWebhook creates an instance of ProjectWebhook based on the projectWebhookRaw.
@@ -50,9 +51,10 @@ func (raw *projectWebhookRaw) toProjectWebhook() *api.ProjectWebhook {
 		ProjectID: raw.ProjectID,
 
 		// Domain specific fields
-		Type: raw.Type,
-		Name: raw.Name,
-		URL:  raw.URL,
+		Type:   raw.Type,
+		Name:   raw.Name,
+		URL:    raw.URL,
+		Secret: raw.Secret,
 	}
 	projectWebhook.ActivityList = append(projectWebhook.ActivityList, raw.ActivityList...)
 	return &projectWebhook
@@ -249,10 +251,11 @@ func createProjectWebhookImpl(ctx context.Context, tx *Tx, create *api.ProjectWe
 			type,
 			name,
 			url,
-			activity_list
+			activity_list,
+			secret
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, project_id, type, name, url, activity_list
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, project_id, type, name, url, activity_list, secret
 	`
 	var projectWebhookRaw projectWebhookRaw
 	var txtArray pgtype.TextArray
@@ -264,6 +267,7 @@ func createProjectWebhookImpl(ctx context.Context, tx *Tx, create *api.ProjectWe
 		create.Name,
 		create.URL,
 		create.ActivityList,
+		create.Secret,
 	).Scan(
 		&projectWebhookRaw.ID,
 		&projectWebhookRaw.CreatorID,
@@ -275,6 +279,7 @@ func createProjectWebhookImpl(ctx context.Context, tx *Tx, create *api.ProjectWe
 		&projectWebhookRaw.Name,
 		&projectWebhookRaw.URL,
 		&txtArray,
+		&projectWebhookRaw.Secret,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
@@ -308,7 +313,8 @@ func findProjectWebhookImpl(ctx context.Context, tx *Tx, find *api.ProjectWebhoo
 			type,
 			name,
 			url,
-			activity_list
+			activity_list,
+			secret
 		FROM project_webhook
 		WHERE `+strings.Join(where, " AND "),
 		args...,
@@ -335,6 +341,7 @@ func findProjectWebhookImpl(ctx context.Context, tx *Tx, find *api.ProjectWebhoo
 			&projectWebhookRaw.Name,
 			&projectWebhookRaw.URL,
 			&txtArray,
+			&projectWebhookRaw.Secret,
 		); err != nil {
 			return nil, FormatError(err)
 		}
@@ -375,6 +382,9 @@ func patchProjectWebhookImpl(ctx context.Context, tx *Tx, patch *api.ProjectWebh
 		activities := strings.Split(*v, ",")
 		set, args = append(set, fmt.Sprintf("activity_list = $%d", len(args)+1)), append(args, activities)
 	}
+	if v := patch.Secret; v != nil {
+		set, args = append(set, fmt.Sprintf("secret = $%d", len(args)+1)), append(args, *v)
+	}
 
 	args = append(args, patch.ID)
 
@@ -385,7 +395,7 @@ func patchProjectWebhookImpl(ctx context.Context, tx *Tx, patch *api.ProjectWebh
 		UPDATE project_webhook
 		SET `+strings.Join(set, ", ")+`
 		WHERE id = $%d
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, project_id, type, name, url, activity_list
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, project_id, type, name, url, activity_list, secret
 	`, len(args)),
 		args...,
 	).Scan(
@@ -399,6 +409,7 @@ func patchProjectWebhookImpl(ctx context.Context, tx *Tx, patch *api.ProjectWebh
 		&projectWebhookRaw.Name,
 		&projectWebhookRaw.URL,
 		&txtArray,
+		&projectWebhookRaw.Secret,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("project hook ID not found: %d", patch.ID)}
@@ -0,0 +1,76 @@
+package store
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+// CreateProjectWebhookDelivery creates a pending ProjectWebhookDelivery which is due to attempt immediately.
+func (s *Store) CreateProjectWebhookDelivery(ctx context.Context, create *api.ProjectWebhookDeliveryCreate) (*api.ProjectWebhookDelivery, error) {
+	raw, err := s.createWebhookDeliveryRaw(ctx, projectWebhookDeliveryTable, &webhookDeliveryCreate{
+		WebhookID:    create.ProjectWebhookID,
+		ActivityType: create.ActivityType,
+		Payload:      create.Payload,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create ProjectWebhookDelivery with ProjectWebhookDeliveryCreate[%+v]", create)
+	}
+	return raw.toProjectWebhookDelivery(), nil
+}
+
+// FindProjectWebhookDelivery finds a list of ProjectWebhookDelivery by find.
+func (s *Store) FindProjectWebhookDelivery(ctx context.Context, find *api.ProjectWebhookDeliveryFind) ([]*api.ProjectWebhookDelivery, error) {
+	rawList, err := s.findWebhookDeliveryRaw(ctx, projectWebhookDeliveryTable, &webhookDeliveryFind{
+		ID:               find.ID,
+		WebhookID:        find.ProjectWebhookID,
+		Status:           find.Status,
+		MaxNextAttemptTs: find.MaxNextAttemptTs,
+		Limit:            find.Limit,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find ProjectWebhookDelivery with ProjectWebhookDeliveryFind[%+v]", find)
+	}
+	var list []*api.ProjectWebhookDelivery
+	for _, raw := range rawList {
+		list = append(list, raw.toProjectWebhookDelivery())
+	}
+	return list, nil
+}
+
+// GetProjectWebhookDeliveryByID gets a ProjectWebhookDelivery by ID.
+// Returns nil if the delivery is not found.
+func (s *Store) GetProjectWebhookDeliveryByID(ctx context.Context, id int) (*api.ProjectWebhookDelivery, error) {
+	list, err := s.FindProjectWebhookDelivery(ctx, &api.ProjectWebhookDeliveryFind{ID: &id})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+	return list[0], nil
+}
+
+// ClaimProjectWebhookDelivery claims the due ProjectWebhookDeliveries to attempt by transiting them to SENDING.
+func (s *Store) ClaimProjectWebhookDelivery(ctx context.Context, claim *api.WebhookDeliveryClaim) ([]*api.ProjectWebhookDelivery, error) {
+	rawList, err := s.claimWebhookDeliveryRaw(ctx, projectWebhookDeliveryTable, claim)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to claim ProjectWebhookDelivery with WebhookDeliveryClaim[%+v]", claim)
+	}
+	var list []*api.ProjectWebhookDelivery
+	for _, raw := range rawList {
+		list = append(list, raw.toProjectWebhookDelivery())
+	}
+	return list, nil
+}
+
+// PatchProjectWebhookDelivery patches a ProjectWebhookDelivery with the result of an attempt.
+func (s *Store) PatchProjectWebhookDelivery(ctx context.Context, patch *api.WebhookDeliveryPatch) (*api.ProjectWebhookDelivery, error) {
+	raw, err := s.patchWebhookDeliveryRaw(ctx, projectWebhookDeliveryTable, patch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to patch ProjectWebhookDelivery with WebhookDeliveryPatch[%+v]", patch)
+	}
+	return raw.toProjectWebhookDelivery(), nil
+}
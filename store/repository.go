@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -28,23 +29,25 @@ type repositoryRaw struct {
 	ProjectID int
 
 	// Domain specific fields
-	Name               string
-	FullPath           string
-	WebURL             string
-	BranchFilter       string
-	BaseDirectory      string
-	FilePathTemplate   string
-	SchemaPathTemplate string
-	SheetPathTemplate  string
-	EnableSQLReviewCI  bool
-	ExternalID         string
-	ExternalWebhookID  string
-	WebhookURLHost     string
-	WebhookEndpointID  string
-	WebhookSecretToken string
-	AccessToken        string
-	ExpiresTs          int64
-	RefreshToken       string
+	Name                     string
+	FullPath                 string
+	WebURL                   string
+	BranchFilter             string
+	BranchEnvironmentSetting api.BranchEnvironmentSetting
+	BaseDirectory            string
+	FilePathTemplate         string
+	SchemaPathTemplate       string
+	SchemaWriteBackType      api.SchemaWriteBackType
+	SheetPathTemplate        string
+	EnableSQLReviewCI        bool
+	ExternalID               string
+	ExternalWebhookID        string
+	WebhookURLHost           string
+	WebhookEndpointID        string
+	WebhookSecretToken       string
+	AccessToken              string
+	ExpiresTs                int64
+	RefreshToken             string
 }
 
 // toRepository creates an instance of Repository based on the repositoryRaw.
@@ -61,23 +64,25 @@ func (raw *repositoryRaw) toRepository() *api.Repository {
 		VCSID:     raw.VCSID,
 		ProjectID: raw.ProjectID,
 
-		Name:               raw.Name,
-		FullPath:           raw.FullPath,
-		WebURL:             raw.WebURL,
-		BranchFilter:       raw.BranchFilter,
-		BaseDirectory:      raw.BaseDirectory,
-		FilePathTemplate:   raw.FilePathTemplate,
-		SchemaPathTemplate: raw.SchemaPathTemplate,
-		SheetPathTemplate:  raw.SheetPathTemplate,
-		EnableSQLReviewCI:  raw.EnableSQLReviewCI,
-		ExternalID:         raw.ExternalID,
-		ExternalWebhookID:  raw.ExternalWebhookID,
-		WebhookURLHost:     raw.WebhookURLHost,
-		WebhookEndpointID:  raw.WebhookEndpointID,
-		WebhookSecretToken: raw.WebhookSecretToken,
-		AccessToken:        raw.AccessToken,
-		ExpiresTs:          raw.ExpiresTs,
-		RefreshToken:       raw.RefreshToken,
+		Name:                     raw.Name,
+		FullPath:                 raw.FullPath,
+		WebURL:                   raw.WebURL,
+		BranchFilter:             raw.BranchFilter,
+		BranchEnvironmentSetting: raw.BranchEnvironmentSetting,
+		BaseDirectory:            raw.BaseDirectory,
+		FilePathTemplate:         raw.FilePathTemplate,
+		SchemaPathTemplate:       raw.SchemaPathTemplate,
+		SchemaWriteBackType:      raw.SchemaWriteBackType,
+		SheetPathTemplate:        raw.SheetPathTemplate,
+		EnableSQLReviewCI:        raw.EnableSQLReviewCI,
+		ExternalID:               raw.ExternalID,
+		ExternalWebhookID:        raw.ExternalWebhookID,
+		WebhookURLHost:           raw.WebhookURLHost,
+		WebhookEndpointID:        raw.WebhookEndpointID,
+		WebhookSecretToken:       raw.WebhookSecretToken,
+		AccessToken:              raw.AccessToken,
+		ExpiresTs:                raw.ExpiresTs,
+		RefreshToken:             raw.RefreshToken,
 	}
 }
 
@@ -289,6 +294,16 @@ func (s *Store) createRepositoryImpl(ctx context.Context, tx *Tx, create *api.Re
 		return nil, err
 	}
 
+	branchEnvironmentSetting, err := json.Marshal(create.BranchEnvironmentSetting)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal branch environment setting")
+	}
+
+	schemaWriteBackType := create.SchemaWriteBackType
+	if schemaWriteBackType == "" {
+		schemaWriteBackType = api.SchemaWriteBackCommit
+	}
+
 	var repository repositoryRaw
 	// Insert row into database.
 	query := `
@@ -304,6 +319,7 @@ func (s *Store) createRepositoryImpl(ctx context.Context, tx *Tx, create *api.Re
 			base_directory,
 			file_path_template,
 			schema_path_template,
+			schema_write_back_type,
 			sheet_path_template,
 			enable_sql_review_ci,
 			external_id,
@@ -313,10 +329,11 @@ func (s *Store) createRepositoryImpl(ctx context.Context, tx *Tx, create *api.Re
 			webhook_secret_token,
 			access_token,
 			expires_ts,
-			refresh_token
+			refresh_token,
+			branch_environment_setting
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, vcs_id, project_id, name, full_path, web_url, branch_filter, base_directory, file_path_template, schema_path_template, sheet_path_template, enable_sql_review_ci, external_id, external_webhook_id, webhook_url_host, webhook_endpoint_id, webhook_secret_token, access_token, expires_ts, refresh_token
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, vcs_id, project_id, name, full_path, web_url, branch_filter, branch_environment_setting, base_directory, file_path_template, schema_path_template, schema_write_back_type, sheet_path_template, enable_sql_review_ci, external_id, external_webhook_id, webhook_url_host, webhook_endpoint_id, webhook_secret_token, access_token, expires_ts, refresh_token
 	`
 	if err := tx.QueryRowContext(ctx, query,
 		create.CreatorID,
@@ -330,6 +347,7 @@ func (s *Store) createRepositoryImpl(ctx context.Context, tx *Tx, create *api.Re
 		create.BaseDirectory,
 		create.FilePathTemplate,
 		create.SchemaPathTemplate,
+		schemaWriteBackType,
 		create.SheetPathTemplate,
 		false, /* EnableSQLReviewCI */
 		create.ExternalID,
@@ -340,6 +358,7 @@ func (s *Store) createRepositoryImpl(ctx context.Context, tx *Tx, create *api.Re
 		create.AccessToken,
 		create.ExpiresTs,
 		create.RefreshToken,
+		string(branchEnvironmentSetting),
 	).Scan(
 		&repository.ID,
 		&repository.CreatorID,
@@ -352,9 +371,11 @@ func (s *Store) createRepositoryImpl(ctx context.Context, tx *Tx, create *api.Re
 		&repository.FullPath,
 		&repository.WebURL,
 		&repository.BranchFilter,
+		&repository.BranchEnvironmentSetting,
 		&repository.BaseDirectory,
 		&repository.FilePathTemplate,
 		&repository.SchemaPathTemplate,
+		&repository.SchemaWriteBackType,
 		&repository.SheetPathTemplate,
 		&repository.EnableSQLReviewCI,
 		&repository.ExternalID,
@@ -406,9 +427,11 @@ func (*Store) findRepositoryImpl(ctx context.Context, tx *Tx, find *api.Reposito
 			full_path,
 			web_url,
 			branch_filter,
+			branch_environment_setting,
 			base_directory,
 			file_path_template,
 			schema_path_template,
+			schema_write_back_type,
 			sheet_path_template,
 			enable_sql_review_ci,
 			external_id,
@@ -444,9 +467,11 @@ func (*Store) findRepositoryImpl(ctx context.Context, tx *Tx, find *api.Reposito
 			&repository.FullPath,
 			&repository.WebURL,
 			&repository.BranchFilter,
+			&repository.BranchEnvironmentSetting,
 			&repository.BaseDirectory,
 			&repository.FilePathTemplate,
 			&repository.SchemaPathTemplate,
+			&repository.SchemaWriteBackType,
 			&repository.SheetPathTemplate,
 			&repository.EnableSQLReviewCI,
 			&repository.ExternalID,
@@ -477,6 +502,13 @@ func (*Store) patchRepositoryImpl(ctx context.Context, tx *Tx, patch *api.Reposi
 	if v := patch.BranchFilter; v != nil {
 		set, args = append(set, fmt.Sprintf("branch_filter = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := patch.BranchEnvironmentSetting; v != nil {
+		branchEnvironmentSetting, err := json.Marshal(v)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal branch environment setting")
+		}
+		set, args = append(set, fmt.Sprintf("branch_environment_setting = $%d", len(args)+1)), append(args, string(branchEnvironmentSetting))
+	}
 	if v := patch.BaseDirectory; v != nil {
 		set, args = append(set, fmt.Sprintf("base_directory = $%d", len(args)+1)), append(args, *v)
 	}
@@ -486,6 +518,9 @@ func (*Store) patchRepositoryImpl(ctx context.Context, tx *Tx, patch *api.Reposi
 	if v := patch.SchemaPathTemplate; v != nil {
 		set, args = append(set, fmt.Sprintf("schema_path_template = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := patch.SchemaWriteBackType; v != nil {
+		set, args = append(set, fmt.Sprintf("schema_write_back_type = $%d", len(args)+1)), append(args, *v)
+	}
 	if v := patch.SheetPathTemplate; v != nil {
 		set, args = append(set, fmt.Sprintf("sheet_path_template = $%d", len(args)+1)), append(args, *v)
 	}
@@ -518,7 +553,7 @@ func (*Store) patchRepositoryImpl(ctx context.Context, tx *Tx, patch *api.Reposi
 		UPDATE repository
 		SET `+strings.Join(set, ", ")+`
 		WHERE `+strings.Join(where, " AND ")+`
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, vcs_id, project_id, name, full_path, web_url, branch_filter, base_directory, file_path_template, schema_path_template, sheet_path_template, enable_sql_review_ci, external_id, external_webhook_id, webhook_url_host, webhook_endpoint_id, webhook_secret_token, access_token, expires_ts, refresh_token
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, vcs_id, project_id, name, full_path, web_url, branch_filter, branch_environment_setting, base_directory, file_path_template, schema_path_template, schema_write_back_type, sheet_path_template, enable_sql_review_ci, external_id, external_webhook_id, webhook_url_host, webhook_endpoint_id, webhook_secret_token, access_token, expires_ts, refresh_token
 		`,
 		args...,
 	).Scan(
@@ -533,9 +568,11 @@ func (*Store) patchRepositoryImpl(ctx context.Context, tx *Tx, patch *api.Reposi
 		&repository.FullPath,
 		&repository.WebURL,
 		&repository.BranchFilter,
+		&repository.BranchEnvironmentSetting,
 		&repository.BaseDirectory,
 		&repository.FilePathTemplate,
 		&repository.SchemaPathTemplate,
+		&repository.SchemaWriteBackType,
 		&repository.SheetPathTemplate,
 		&repository.EnableSQLReviewCI,
 		&repository.ExternalID,
@@ -0,0 +1,224 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+// CreateRepositoryPullRequest creates a RepositoryPullRequest.
+func (s *Store) CreateRepositoryPullRequest(ctx context.Context, create *api.RepositoryPullRequestCreate) (*api.RepositoryPullRequest, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	pullRequest, err := createRepositoryPullRequestImpl(ctx, tx, create)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create RepositoryPullRequest with RepositoryPullRequestCreate[%+v]", create)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return pullRequest, nil
+}
+
+// FindRepositoryPullRequest finds a list of RepositoryPullRequest by find.
+func (s *Store) FindRepositoryPullRequest(ctx context.Context, find *api.RepositoryPullRequestFind) ([]*api.RepositoryPullRequest, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	list, err := findRepositoryPullRequestImpl(ctx, tx, find)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find RepositoryPullRequest with RepositoryPullRequestFind[%+v]", find)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return list, nil
+}
+
+// PatchRepositoryPullRequest patches a RepositoryPullRequest.
+func (s *Store) PatchRepositoryPullRequest(ctx context.Context, patch *api.RepositoryPullRequestPatch) (*api.RepositoryPullRequest, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	pullRequest, err := patchRepositoryPullRequestImpl(ctx, tx, patch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to patch RepositoryPullRequest with RepositoryPullRequestPatch[%+v]", patch)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return pullRequest, nil
+}
+
+//
+// private functions
+//
+
+func createRepositoryPullRequestImpl(ctx context.Context, tx *Tx, create *api.RepositoryPullRequestCreate) (*api.RepositoryPullRequest, error) {
+	payload := create.Payload
+	if payload == "" {
+		payload = "{}"
+	}
+	query := `
+		INSERT INTO repository_pull_request (
+			repository_id,
+			issue_id,
+			pull_request_id,
+			base_ref,
+			status,
+			head_sha,
+			payload
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_ts, updated_ts, repository_id, issue_id, pull_request_id, base_ref, status, head_sha, payload
+	`
+	var pullRequest api.RepositoryPullRequest
+	if err := tx.QueryRowContext(ctx, query,
+		create.RepositoryID,
+		create.IssueID,
+		create.PullRequestID,
+		create.BaseRef,
+		api.RepositoryPullRequestOpen,
+		create.HeadSHA,
+		payload,
+	).Scan(
+		&pullRequest.ID,
+		&pullRequest.CreatedTs,
+		&pullRequest.UpdatedTs,
+		&pullRequest.RepositoryID,
+		&pullRequest.IssueID,
+		&pullRequest.PullRequestID,
+		&pullRequest.BaseRef,
+		&pullRequest.Status,
+		&pullRequest.HeadSHA,
+		&pullRequest.Payload,
+	); err != nil {
+		return nil, FormatError(err)
+	}
+	return &pullRequest, nil
+}
+
+func findRepositoryPullRequestImpl(ctx context.Context, tx *Tx, find *api.RepositoryPullRequestFind) ([]*api.RepositoryPullRequest, error) {
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.RepositoryID; v != nil {
+		where, args = append(where, fmt.Sprintf("repository_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.IssueID; v != nil {
+		where, args = append(where, fmt.Sprintf("issue_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.PullRequestID; v != nil {
+		where, args = append(where, fmt.Sprintf("pull_request_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.BaseRef; v != nil {
+		where, args = append(where, fmt.Sprintf("base_ref = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Status; v != nil {
+		where, args = append(where, fmt.Sprintf("status = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.StatusList; len(v) > 0 {
+		var list []string
+		for _, status := range v {
+			list = append(list, fmt.Sprintf("$%d", len(args)+1))
+			args = append(args, status)
+		}
+		where = append(where, fmt.Sprintf("status IN (%s)", strings.Join(list, ",")))
+	}
+	if v := find.FileList; len(v) > 0 {
+		where, args = append(where, fmt.Sprintf("payload->'fileList' ?| $%d", len(args)+1)), append(args, v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			created_ts,
+			updated_ts,
+			repository_id,
+			issue_id,
+			pull_request_id,
+			base_ref,
+			status,
+			head_sha,
+			payload
+		FROM repository_pull_request
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	var list []*api.RepositoryPullRequest
+	for rows.Next() {
+		var pullRequest api.RepositoryPullRequest
+		if err := rows.Scan(
+			&pullRequest.ID,
+			&pullRequest.CreatedTs,
+			&pullRequest.UpdatedTs,
+			&pullRequest.RepositoryID,
+			&pullRequest.IssueID,
+			&pullRequest.PullRequestID,
+			&pullRequest.BaseRef,
+			&pullRequest.Status,
+			&pullRequest.HeadSHA,
+			&pullRequest.Payload,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		list = append(list, &pullRequest)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+	return list, nil
+}
+
+func patchRepositoryPullRequestImpl(ctx context.Context, tx *Tx, patch *api.RepositoryPullRequestPatch) (*api.RepositoryPullRequest, error) {
+	set, args := []string{}, []interface{}{}
+	if v := patch.Status; v != nil {
+		set, args = append(set, fmt.Sprintf("status = $%d", len(args)+1)), append(args, *v)
+	}
+	if len(set) == 0 {
+		return nil, errors.New("no update for the repository pull request")
+	}
+	args = append(args, patch.ID)
+
+	var pullRequest api.RepositoryPullRequest
+	if err := tx.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE repository_pull_request
+		SET `+strings.Join(set, ", ")+`
+		WHERE id = $%d
+		RETURNING id, created_ts, updated_ts, repository_id, issue_id, pull_request_id, base_ref, status, head_sha, payload
+	`, len(args)),
+		args...,
+	).Scan(
+		&pullRequest.ID,
+		&pullRequest.CreatedTs,
+		&pullRequest.UpdatedTs,
+		&pullRequest.RepositoryID,
+		&pullRequest.IssueID,
+		&pullRequest.PullRequestID,
+		&pullRequest.BaseRef,
+		&pullRequest.Status,
+		&pullRequest.HeadSHA,
+		&pullRequest.Payload,
+	); err != nil {
+		return nil, FormatError(err)
+	}
+	return &pullRequest, nil
+}
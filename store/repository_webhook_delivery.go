@@ -0,0 +1,264 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// CreateRepositoryWebhookDelivery creates a RepositoryWebhookDelivery.
+func (s *Store) CreateRepositoryWebhookDelivery(ctx context.Context, create *api.RepositoryWebhookDeliveryCreate) (*api.RepositoryWebhookDelivery, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	delivery, err := createRepositoryWebhookDeliveryImpl(ctx, tx, create)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create RepositoryWebhookDelivery with RepositoryWebhookDeliveryCreate[%+v]", create)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return delivery, nil
+}
+
+// FindRepositoryWebhookDelivery finds a list of RepositoryWebhookDelivery by find.
+func (s *Store) FindRepositoryWebhookDelivery(ctx context.Context, find *api.RepositoryWebhookDeliveryFind) ([]*api.RepositoryWebhookDelivery, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	list, err := findRepositoryWebhookDeliveryImpl(ctx, tx, find)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find RepositoryWebhookDelivery with RepositoryWebhookDeliveryFind[%+v]", find)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return list, nil
+}
+
+// PatchRepositoryWebhookDelivery patches a RepositoryWebhookDelivery.
+func (s *Store) PatchRepositoryWebhookDelivery(ctx context.Context, patch *api.RepositoryWebhookDeliveryPatch) (*api.RepositoryWebhookDelivery, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	delivery, err := patchRepositoryWebhookDeliveryImpl(ctx, tx, patch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to patch RepositoryWebhookDelivery with RepositoryWebhookDeliveryPatch[%+v]", patch)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return delivery, nil
+}
+
+// ExpireRepositoryWebhookDelivery marks the stale processing deliveries of the event as failed, so that the
+// event can be claimed by a redelivery.
+func (s *Store) ExpireRepositoryWebhookDelivery(ctx context.Context, expire *api.RepositoryWebhookDeliveryExpire) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE repository_webhook_delivery
+		SET status = $1, message = $2
+		WHERE repository_id = $3
+		AND status = $4
+		AND updated_ts < $5
+		AND ((event_uuid != '' AND event_uuid = $6) OR (commit_sha != '' AND event_type = $7 AND ref = $8 AND commit_sha = $9))
+	`,
+		api.RepositoryWebhookDeliveryFailed,
+		expire.Message,
+		expire.RepositoryID,
+		api.RepositoryWebhookDeliveryProcessing,
+		expire.UpdatedBefore,
+		expire.EventUUID,
+		expire.EventType,
+		expire.Ref,
+		expire.CommitSHA,
+	); err != nil {
+		return FormatError(err)
+	}
+	if err := tx.Commit(); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
+
+//
+// private functions
+//
+
+func createRepositoryWebhookDeliveryImpl(ctx context.Context, tx *Tx, create *api.RepositoryWebhookDeliveryCreate) (*api.RepositoryWebhookDelivery, error) {
+	payload := create.Payload
+	if payload == "" {
+		payload = "{}"
+	}
+	query := `
+		INSERT INTO repository_webhook_delivery (
+			repository_id,
+			event_uuid,
+			event_type,
+			ref,
+			commit_sha,
+			status,
+			message,
+			payload
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_ts, repository_id, event_uuid, event_type, ref, commit_sha, status, message, payload
+	`
+	var delivery api.RepositoryWebhookDelivery
+	if err := tx.QueryRowContext(ctx, query,
+		create.RepositoryID,
+		create.EventUUID,
+		create.EventType,
+		create.Ref,
+		create.CommitSHA,
+		create.Status,
+		create.Message,
+		payload,
+	).Scan(
+		&delivery.ID,
+		&delivery.CreatedTs,
+		&delivery.RepositoryID,
+		&delivery.EventUUID,
+		&delivery.EventType,
+		&delivery.Ref,
+		&delivery.CommitSHA,
+		&delivery.Status,
+		&delivery.Message,
+		&delivery.Payload,
+	); err != nil {
+		return nil, FormatError(err)
+	}
+	return &delivery, nil
+}
+
+func patchRepositoryWebhookDeliveryImpl(ctx context.Context, tx *Tx, patch *api.RepositoryWebhookDeliveryPatch) (*api.RepositoryWebhookDelivery, error) {
+	payload := patch.Payload
+	if payload == "" {
+		payload = "{}"
+	}
+	query := `
+		UPDATE repository_webhook_delivery
+		SET status = $1, message = $2, payload = $3
+		WHERE id = $4
+		RETURNING id, created_ts, repository_id, event_uuid, event_type, ref, commit_sha, status, message, payload
+	`
+	var delivery api.RepositoryWebhookDelivery
+	if err := tx.QueryRowContext(ctx, query,
+		patch.Status,
+		patch.Message,
+		payload,
+		patch.ID,
+	).Scan(
+		&delivery.ID,
+		&delivery.CreatedTs,
+		&delivery.RepositoryID,
+		&delivery.EventUUID,
+		&delivery.EventType,
+		&delivery.Ref,
+		&delivery.CommitSHA,
+		&delivery.Status,
+		&delivery.Message,
+		&delivery.Payload,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("repository webhook delivery ID not found: %d", patch.ID)}
+		}
+		return nil, FormatError(err)
+	}
+	return &delivery, nil
+}
+
+func findRepositoryWebhookDeliveryImpl(ctx context.Context, tx *Tx, find *api.RepositoryWebhookDeliveryFind) ([]*api.RepositoryWebhookDelivery, error) {
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.RepositoryID; v != nil {
+		where, args = append(where, fmt.Sprintf("repository_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.EventUUID; v != nil {
+		where, args = append(where, fmt.Sprintf("event_uuid = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.EventType; v != nil {
+		where, args = append(where, fmt.Sprintf("event_type = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Ref; v != nil {
+		where, args = append(where, fmt.Sprintf("ref = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.CommitSHA; v != nil {
+		where, args = append(where, fmt.Sprintf("commit_sha = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.StatusList; v != nil {
+		list := []string{}
+		for _, status := range *v {
+			list = append(list, fmt.Sprintf("$%d", len(args)+1))
+			args = append(args, status)
+		}
+		where = append(where, fmt.Sprintf("status in (%s)", strings.Join(list, ",")))
+	}
+
+	query := `
+		SELECT
+			id,
+			created_ts,
+			repository_id,
+			event_uuid,
+			event_type,
+			ref,
+			commit_sha,
+			status,
+			message,
+			payload
+		FROM repository_webhook_delivery
+		WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY id DESC`
+	if v := find.Limit; v != nil {
+		query += fmt.Sprintf(" LIMIT %d", *v)
+	}
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	var list []*api.RepositoryWebhookDelivery
+	for rows.Next() {
+		var delivery api.RepositoryWebhookDelivery
+		if err := rows.Scan(
+			&delivery.ID,
+			&delivery.CreatedTs,
+			&delivery.RepositoryID,
+			&delivery.EventUUID,
+			&delivery.EventType,
+			&delivery.Ref,
+			&delivery.CommitSHA,
+			&delivery.Status,
+			&delivery.Message,
+			&delivery.Payload,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		list = append(list, &delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+	return list, nil
+}
@@ -0,0 +1,349 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// webhookDeliveryTable is the table of the project or workspace webhook deliveries.
+// The tables share the same columns except the one referencing the webhook.
+type webhookDeliveryTable struct {
+	name            string
+	webhookIDColumn string
+}
+
+var (
+	projectWebhookDeliveryTable   = webhookDeliveryTable{name: "project_webhook_delivery", webhookIDColumn: "project_webhook_id"}
+	workspaceWebhookDeliveryTable = webhookDeliveryTable{name: "workspace_webhook_delivery", webhookIDColumn: "workspace_webhook_id"}
+)
+
+// webhookDeliveryRaw is the store model for a project or workspace webhook delivery.
+type webhookDeliveryRaw struct {
+	ID int
+
+	// Standard fields
+	CreatedTs int64
+	UpdatedTs int64
+
+	// Related fields
+	WebhookID int
+
+	// Domain specific fields
+	ActivityType  api.ActivityType
+	Status        api.ProjectWebhookDeliveryStatus
+	AttemptCount  int
+	NextAttemptTs int64
+	ResponseCode  int
+	ResponseBody  string
+	Error         string
+	Payload       string
+}
+
+// toProjectWebhookDelivery creates an instance of ProjectWebhookDelivery based on the webhookDeliveryRaw.
+func (raw *webhookDeliveryRaw) toProjectWebhookDelivery() *api.ProjectWebhookDelivery {
+	return &api.ProjectWebhookDelivery{
+		ID:               raw.ID,
+		CreatedTs:        raw.CreatedTs,
+		UpdatedTs:        raw.UpdatedTs,
+		ProjectWebhookID: raw.WebhookID,
+		ActivityType:     raw.ActivityType,
+		Status:           raw.Status,
+		AttemptCount:     raw.AttemptCount,
+		NextAttemptTs:    raw.NextAttemptTs,
+		ResponseCode:     raw.ResponseCode,
+		ResponseBody:     raw.ResponseBody,
+		Error:            raw.Error,
+		Payload:          raw.Payload,
+	}
+}
+
+// toWorkspaceWebhookDelivery creates an instance of WorkspaceWebhookDelivery based on the webhookDeliveryRaw.
+func (raw *webhookDeliveryRaw) toWorkspaceWebhookDelivery() *api.WorkspaceWebhookDelivery {
+	return &api.WorkspaceWebhookDelivery{
+		ID:                 raw.ID,
+		CreatedTs:          raw.CreatedTs,
+		UpdatedTs:          raw.UpdatedTs,
+		WorkspaceWebhookID: raw.WebhookID,
+		ActivityType:       raw.ActivityType,
+		Status:             raw.Status,
+		AttemptCount:       raw.AttemptCount,
+		NextAttemptTs:      raw.NextAttemptTs,
+		ResponseCode:       raw.ResponseCode,
+		ResponseBody:       raw.ResponseBody,
+		Error:              raw.Error,
+		Payload:            raw.Payload,
+	}
+}
+
+// webhookDeliveryCreate is the store model for creating a project or workspace webhook delivery.
+type webhookDeliveryCreate struct {
+	WebhookID    int
+	ActivityType api.ActivityType
+	Payload      string
+}
+
+// webhookDeliveryFind is the store model for finding the project or workspace webhook deliveries.
+type webhookDeliveryFind struct {
+	ID        *int
+	WebhookID *int
+
+	Status           *api.ProjectWebhookDeliveryStatus
+	MaxNextAttemptTs *int64
+	Limit            *int
+}
+
+func (s *Store) createWebhookDeliveryRaw(ctx context.Context, table webhookDeliveryTable, create *webhookDeliveryCreate) (*webhookDeliveryRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	delivery, err := createWebhookDeliveryImpl(ctx, tx, table, create)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return delivery, nil
+}
+
+func (s *Store) findWebhookDeliveryRaw(ctx context.Context, table webhookDeliveryTable, find *webhookDeliveryFind) ([]*webhookDeliveryRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	list, err := findWebhookDeliveryImpl(ctx, tx, table, find)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return list, nil
+}
+
+func (s *Store) claimWebhookDeliveryRaw(ctx context.Context, table webhookDeliveryTable, claim *api.WebhookDeliveryClaim) ([]*webhookDeliveryRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	list, err := claimWebhookDeliveryImpl(ctx, tx, table, claim)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return list, nil
+}
+
+func (s *Store) patchWebhookDeliveryRaw(ctx context.Context, table webhookDeliveryTable, patch *api.WebhookDeliveryPatch) (*webhookDeliveryRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	delivery, err := patchWebhookDeliveryImpl(ctx, tx, table, patch)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return delivery, nil
+}
+
+//
+// private functions
+//
+
+func getWebhookDeliveryColumns(table webhookDeliveryTable) string {
+	return `
+			id,
+			created_ts,
+			updated_ts,
+			` + table.webhookIDColumn + `,
+			activity_type,
+			status,
+			attempt_count,
+			next_attempt_ts,
+			response_code,
+			response_body,
+			error,
+			payload`
+}
+
+func scanWebhookDelivery(scanner interface{ Scan(...interface{}) error }) (*webhookDeliveryRaw, error) {
+	var delivery webhookDeliveryRaw
+	if err := scanner.Scan(
+		&delivery.ID,
+		&delivery.CreatedTs,
+		&delivery.UpdatedTs,
+		&delivery.WebhookID,
+		&delivery.ActivityType,
+		&delivery.Status,
+		&delivery.AttemptCount,
+		&delivery.NextAttemptTs,
+		&delivery.ResponseCode,
+		&delivery.ResponseBody,
+		&delivery.Error,
+		&delivery.Payload,
+	); err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func createWebhookDeliveryImpl(ctx context.Context, tx *Tx, table webhookDeliveryTable, create *webhookDeliveryCreate) (*webhookDeliveryRaw, error) {
+	payload := create.Payload
+	if payload == "" {
+		payload = "{}"
+	}
+	query := `
+		INSERT INTO ` + table.name + ` (
+			` + table.webhookIDColumn + `,
+			activity_type,
+			status,
+			payload
+		)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + getWebhookDeliveryColumns(table)
+	delivery, err := scanWebhookDelivery(tx.QueryRowContext(ctx, query,
+		create.WebhookID,
+		create.ActivityType,
+		api.ProjectWebhookDeliveryPending,
+		payload,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return delivery, nil
+}
+
+func findWebhookDeliveryImpl(ctx context.Context, tx *Tx, table webhookDeliveryTable, find *webhookDeliveryFind) ([]*webhookDeliveryRaw, error) {
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.WebhookID; v != nil {
+		where, args = append(where, fmt.Sprintf("%s = $%d", table.webhookIDColumn, len(args)+1)), append(args, *v)
+	}
+	if v := find.Status; v != nil {
+		where, args = append(where, fmt.Sprintf("status = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.MaxNextAttemptTs; v != nil {
+		where, args = append(where, fmt.Sprintf("next_attempt_ts <= $%d", len(args)+1)), append(args, *v)
+	}
+
+	query := `
+		SELECT` + getWebhookDeliveryColumns(table) + `
+		FROM ` + table.name + `
+		WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY id DESC`
+	if v := find.Limit; v != nil {
+		query += fmt.Sprintf(" LIMIT %d", *v)
+	}
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	var list []*webhookDeliveryRaw
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, FormatError(err)
+		}
+		list = append(list, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+	return list, nil
+}
+
+// claimWebhookDeliveryImpl transits the due deliveries to SENDING and returns them sorted by ID in ascending order.
+// The rows locked by a concurrent claim are skipped, so that a delivery is claimed by at most one of them.
+func claimWebhookDeliveryImpl(ctx context.Context, tx *Tx, table webhookDeliveryTable, claim *api.WebhookDeliveryClaim) ([]*webhookDeliveryRaw, error) {
+	query := `
+		UPDATE ` + table.name + `
+		SET status = $1
+		WHERE id IN (
+			SELECT id FROM ` + table.name + `
+			WHERE (status = $2 AND next_attempt_ts <= $3) OR (status = $1 AND updated_ts < $4)
+			ORDER BY id
+			LIMIT $5
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + getWebhookDeliveryColumns(table)
+	rows, err := tx.QueryContext(ctx, query,
+		api.ProjectWebhookDeliverySending,
+		api.ProjectWebhookDeliveryPending,
+		claim.MaxNextAttemptTs,
+		claim.MaxSendingUpdatedTs,
+		claim.Limit,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	var list []*webhookDeliveryRaw
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, FormatError(err)
+		}
+		list = append(list, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ID < list[j].ID
+	})
+	return list, nil
+}
+
+func patchWebhookDeliveryImpl(ctx context.Context, tx *Tx, table webhookDeliveryTable, patch *api.WebhookDeliveryPatch) (*webhookDeliveryRaw, error) {
+	query := `
+		UPDATE ` + table.name + `
+		SET status = $1, attempt_count = $2, next_attempt_ts = $3, response_code = $4, response_body = $5, error = $6
+		WHERE id = $7
+		RETURNING ` + getWebhookDeliveryColumns(table)
+	delivery, err := scanWebhookDelivery(tx.QueryRowContext(ctx, query,
+		patch.Status,
+		patch.AttemptCount,
+		patch.NextAttemptTs,
+		patch.ResponseCode,
+		patch.ResponseBody,
+		patch.Error,
+		patch.ID,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("%s ID not found: %d", strings.ReplaceAll(table.name, "_", " "), patch.ID)}
+		}
+		return nil, FormatError(err)
+	}
+	return delivery, nil
+}
@@ -0,0 +1,374 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgtype"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// workspaceWebhookRaw is the store model for a WorkspaceWebhook.
+// Fields have exactly the same meanings as WorkspaceWebhook.
+type workspaceWebhookRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Domain specific fields
+	Type         string
+	Name         string
+	URL          string
+	ActivityList []string
+	LevelList    []string
+	Secret       string
+}
+
+// toWorkspaceWebhook creates an instance of WorkspaceWebhook based on the workspaceWebhookRaw.
+// This is intended to be called when we need to compose a WorkspaceWebhook relationship.
+func (raw *workspaceWebhookRaw) toWorkspaceWebhook() *api.WorkspaceWebhook {
+	workspaceWebhook := api.WorkspaceWebhook{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Domain specific fields
+		Type:   raw.Type,
+		Name:   raw.Name,
+		URL:    raw.URL,
+		Secret: raw.Secret,
+	}
+	workspaceWebhook.ActivityList = append([]string{}, raw.ActivityList...)
+	workspaceWebhook.LevelList = append([]string{}, raw.LevelList...)
+	return &workspaceWebhook
+}
+
+// match returns true if the workspace webhook receives the activity type and level in find.
+// The webhook receives all levels if its level list is empty.
+func (raw *workspaceWebhookRaw) match(find *api.WorkspaceWebhookFind) bool {
+	if v := find.ActivityType; v != nil {
+		found := false
+		for _, activity := range raw.ActivityList {
+			if api.ActivityType(activity) == *v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if v := find.Level; v != nil && len(raw.LevelList) > 0 {
+		found := false
+		for _, level := range raw.LevelList {
+			if api.ActivityLevel(level) == *v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// CreateWorkspaceWebhook creates an instance of WorkspaceWebhook.
+func (s *Store) CreateWorkspaceWebhook(ctx context.Context, create *api.WorkspaceWebhookCreate) (*api.WorkspaceWebhook, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	workspaceWebhookRaw, err := createWorkspaceWebhookImpl(ctx, tx, create)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create WorkspaceWebhook with WorkspaceWebhookCreate[%+v]", create)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	workspaceWebhook, err := s.composeWorkspaceWebhook(ctx, workspaceWebhookRaw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compose WorkspaceWebhook with workspaceWebhookRaw[%+v]", workspaceWebhookRaw)
+	}
+	return workspaceWebhook, nil
+}
+
+// GetWorkspaceWebhookByID gets an instance of WorkspaceWebhook.
+// Returns nil if the webhook is not found.
+func (s *Store) GetWorkspaceWebhookByID(ctx context.Context, id int) (*api.WorkspaceWebhook, error) {
+	list, err := s.FindWorkspaceWebhook(ctx, &api.WorkspaceWebhookFind{ID: &id})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get WorkspaceWebhook with ID %d", id)
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+	return list[0], nil
+}
+
+// FindWorkspaceWebhook finds a list of WorkspaceWebhook instances.
+func (s *Store) FindWorkspaceWebhook(ctx context.Context, find *api.WorkspaceWebhookFind) ([]*api.WorkspaceWebhook, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	workspaceWebhookRawList, err := findWorkspaceWebhookImpl(ctx, tx, find)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find WorkspaceWebhook list with WorkspaceWebhookFind[%+v]", find)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	var workspaceWebhookList []*api.WorkspaceWebhook
+	for _, raw := range workspaceWebhookRawList {
+		workspaceWebhook, err := s.composeWorkspaceWebhook(ctx, raw)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compose WorkspaceWebhook with workspaceWebhookRaw[%+v]", raw)
+		}
+		workspaceWebhookList = append(workspaceWebhookList, workspaceWebhook)
+	}
+	return workspaceWebhookList, nil
+}
+
+// PatchWorkspaceWebhook patches an instance of WorkspaceWebhook.
+func (s *Store) PatchWorkspaceWebhook(ctx context.Context, patch *api.WorkspaceWebhookPatch) (*api.WorkspaceWebhook, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	workspaceWebhookRaw, err := patchWorkspaceWebhookImpl(ctx, tx, patch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to patch WorkspaceWebhook with WorkspaceWebhookPatch[%+v]", patch)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	workspaceWebhook, err := s.composeWorkspaceWebhook(ctx, workspaceWebhookRaw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compose WorkspaceWebhook with workspaceWebhookRaw[%+v]", workspaceWebhookRaw)
+	}
+	return workspaceWebhook, nil
+}
+
+// DeleteWorkspaceWebhook deletes an existing workspaceWebhook by ID.
+func (s *Store) DeleteWorkspaceWebhook(ctx context.Context, delete *api.WorkspaceWebhookDelete) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM workspace_webhook WHERE id = $1`, delete.ID); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+//
+// private functions
+//
+
+func (s *Store) composeWorkspaceWebhook(ctx context.Context, raw *workspaceWebhookRaw) (*api.WorkspaceWebhook, error) {
+	webhook := raw.toWorkspaceWebhook()
+
+	creator, err := s.GetPrincipalByID(ctx, webhook.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	webhook.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, webhook.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	webhook.Updater = updater
+
+	return webhook, nil
+}
+
+const workspaceWebhookColumns = `
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			type,
+			name,
+			url,
+			activity_list,
+			level_list,
+			secret`
+
+func scanWorkspaceWebhook(scanner interface{ Scan(...interface{}) error }) (*workspaceWebhookRaw, error) {
+	var workspaceWebhookRaw workspaceWebhookRaw
+	var activityArray, levelArray pgtype.TextArray
+	if err := scanner.Scan(
+		&workspaceWebhookRaw.ID,
+		&workspaceWebhookRaw.CreatorID,
+		&workspaceWebhookRaw.CreatedTs,
+		&workspaceWebhookRaw.UpdaterID,
+		&workspaceWebhookRaw.UpdatedTs,
+		&workspaceWebhookRaw.Type,
+		&workspaceWebhookRaw.Name,
+		&workspaceWebhookRaw.URL,
+		&activityArray,
+		&levelArray,
+		&workspaceWebhookRaw.Secret,
+	); err != nil {
+		return nil, err
+	}
+	if err := activityArray.AssignTo(&workspaceWebhookRaw.ActivityList); err != nil {
+		return nil, err
+	}
+	if err := levelArray.AssignTo(&workspaceWebhookRaw.LevelList); err != nil {
+		return nil, err
+	}
+	return &workspaceWebhookRaw, nil
+}
+
+// createWorkspaceWebhookImpl creates a new workspaceWebhook.
+func createWorkspaceWebhookImpl(ctx context.Context, tx *Tx, create *api.WorkspaceWebhookCreate) (*workspaceWebhookRaw, error) {
+	levelList := create.LevelList
+	if levelList == nil {
+		levelList = []string{}
+	}
+	query := `
+		INSERT INTO workspace_webhook (
+			creator_id,
+			updater_id,
+			type,
+			name,
+			url,
+			activity_list,
+			level_list,
+			secret
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING ` + workspaceWebhookColumns
+	workspaceWebhookRaw, err := scanWorkspaceWebhook(tx.QueryRowContext(ctx, query,
+		create.CreatorID,
+		create.CreatorID,
+		create.Type,
+		create.Name,
+		create.URL,
+		create.ActivityList,
+		levelList,
+		create.Secret,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return nil, FormatError(err)
+	}
+	return workspaceWebhookRaw, nil
+}
+
+func findWorkspaceWebhookImpl(ctx context.Context, tx *Tx, find *api.WorkspaceWebhookFind) ([]*workspaceWebhookRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT`+workspaceWebhookColumns+`
+		FROM workspace_webhook
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into workspaceWebhookRawList.
+	var workspaceWebhookRawList []*workspaceWebhookRaw
+	for rows.Next() {
+		workspaceWebhookRaw, err := scanWorkspaceWebhook(rows)
+		if err != nil {
+			return nil, FormatError(err)
+		}
+		// The activity type and level are filtered here, since they are stored in arrays.
+		if workspaceWebhookRaw.match(find) {
+			workspaceWebhookRawList = append(workspaceWebhookRawList, workspaceWebhookRaw)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return workspaceWebhookRawList, nil
+}
+
+// patchWorkspaceWebhookImpl updates a workspaceWebhook by ID. Returns the new state of the workspaceWebhook after update.
+func patchWorkspaceWebhookImpl(ctx context.Context, tx *Tx, patch *api.WorkspaceWebhookPatch) (*workspaceWebhookRaw, error) {
+	// Build UPDATE clause.
+	set, args := []string{"updater_id = $1"}, []interface{}{patch.UpdaterID}
+	if v := patch.Name; v != nil {
+		set, args = append(set, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.URL; v != nil {
+		set, args = append(set, fmt.Sprintf("url = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.ActivityList; v != nil {
+		activities := strings.Split(*v, ",")
+		set, args = append(set, fmt.Sprintf("activity_list = $%d", len(args)+1)), append(args, activities)
+	}
+	if v := patch.LevelList; v != nil {
+		levels := []string{}
+		if *v != "" {
+			levels = strings.Split(*v, ",")
+		}
+		set, args = append(set, fmt.Sprintf("level_list = $%d", len(args)+1)), append(args, levels)
+	}
+	if v := patch.Secret; v != nil {
+		set, args = append(set, fmt.Sprintf("secret = $%d", len(args)+1)), append(args, *v)
+	}
+
+	args = append(args, patch.ID)
+
+	// Execute update query with RETURNING.
+	workspaceWebhookRaw, err := scanWorkspaceWebhook(tx.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE workspace_webhook
+		SET `+strings.Join(set, ", ")+`
+		WHERE id = $%d
+		RETURNING `+workspaceWebhookColumns, len(args)),
+		args...,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("workspace webhook ID not found: %d", patch.ID)}
+		}
+		return nil, FormatError(err)
+	}
+	return workspaceWebhookRaw, nil
+}
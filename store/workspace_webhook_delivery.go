@@ -0,0 +1,76 @@
+package store
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+// CreateWorkspaceWebhookDelivery creates a pending WorkspaceWebhookDelivery which is due to attempt immediately.
+func (s *Store) CreateWorkspaceWebhookDelivery(ctx context.Context, create *api.WorkspaceWebhookDeliveryCreate) (*api.WorkspaceWebhookDelivery, error) {
+	raw, err := s.createWebhookDeliveryRaw(ctx, workspaceWebhookDeliveryTable, &webhookDeliveryCreate{
+		WebhookID:    create.WorkspaceWebhookID,
+		ActivityType: create.ActivityType,
+		Payload:      create.Payload,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create WorkspaceWebhookDelivery with WorkspaceWebhookDeliveryCreate[%+v]", create)
+	}
+	return raw.toWorkspaceWebhookDelivery(), nil
+}
+
+// FindWorkspaceWebhookDelivery finds a list of WorkspaceWebhookDelivery by find.
+func (s *Store) FindWorkspaceWebhookDelivery(ctx context.Context, find *api.WorkspaceWebhookDeliveryFind) ([]*api.WorkspaceWebhookDelivery, error) {
+	rawList, err := s.findWebhookDeliveryRaw(ctx, workspaceWebhookDeliveryTable, &webhookDeliveryFind{
+		ID:               find.ID,
+		WebhookID:        find.WorkspaceWebhookID,
+		Status:           find.Status,
+		MaxNextAttemptTs: find.MaxNextAttemptTs,
+		Limit:            find.Limit,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find WorkspaceWebhookDelivery with WorkspaceWebhookDeliveryFind[%+v]", find)
+	}
+	var list []*api.WorkspaceWebhookDelivery
+	for _, raw := range rawList {
+		list = append(list, raw.toWorkspaceWebhookDelivery())
+	}
+	return list, nil
+}
+
+// GetWorkspaceWebhookDeliveryByID gets a WorkspaceWebhookDelivery by ID.
+// Returns nil if the delivery is not found.
+func (s *Store) GetWorkspaceWebhookDeliveryByID(ctx context.Context, id int) (*api.WorkspaceWebhookDelivery, error) {
+	list, err := s.FindWorkspaceWebhookDelivery(ctx, &api.WorkspaceWebhookDeliveryFind{ID: &id})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+	return list[0], nil
+}
+
+// ClaimWorkspaceWebhookDelivery claims the due WorkspaceWebhookDeliveries to attempt by transiting them to SENDING.
+func (s *Store) ClaimWorkspaceWebhookDelivery(ctx context.Context, claim *api.WebhookDeliveryClaim) ([]*api.WorkspaceWebhookDelivery, error) {
+	rawList, err := s.claimWebhookDeliveryRaw(ctx, workspaceWebhookDeliveryTable, claim)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to claim WorkspaceWebhookDelivery with WebhookDeliveryClaim[%+v]", claim)
+	}
+	var list []*api.WorkspaceWebhookDelivery
+	for _, raw := range rawList {
+		list = append(list, raw.toWorkspaceWebhookDelivery())
+	}
+	return list, nil
+}
+
+// PatchWorkspaceWebhookDelivery patches a WorkspaceWebhookDelivery with the result of an attempt.
+func (s *Store) PatchWorkspaceWebhookDelivery(ctx context.Context, patch *api.WebhookDeliveryPatch) (*api.WorkspaceWebhookDelivery, error) {
+	raw, err := s.patchWebhookDeliveryRaw(ctx, workspaceWebhookDeliveryTable, patch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to patch WorkspaceWebhookDelivery with WebhookDeliveryPatch[%+v]", patch)
+	}
+	return raw.toWorkspaceWebhookDelivery(), nil
+}
@@ -0,0 +1,24 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestWorkspaceWebhookMatch(t *testing.T) {
+	a := require.New(t)
+	instanceCreate, anomalyCreate := api.ActivityInstanceCreate, api.ActivityAnomalyCreate
+	info, warn := api.ActivityInfo, api.ActivityWarn
+
+	raw := &workspaceWebhookRaw{ActivityList: []string{string(api.ActivityInstanceCreate)}}
+	a.True(raw.match(&api.WorkspaceWebhookFind{}))
+	a.True(raw.match(&api.WorkspaceWebhookFind{ActivityType: &instanceCreate, Level: &warn}))
+	a.False(raw.match(&api.WorkspaceWebhookFind{ActivityType: &anomalyCreate}))
+
+	raw.LevelList = []string{string(api.ActivityWarn), string(api.ActivityError)}
+	a.True(raw.match(&api.WorkspaceWebhookFind{ActivityType: &instanceCreate, Level: &warn}))
+	a.False(raw.match(&api.WorkspaceWebhookFind{ActivityType: &instanceCreate, Level: &info}))
+}
@@ -0,0 +1,523 @@
+package fake
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/plugin/vcs"
+	"github.com/bytebase/bytebase/plugin/vcs/azure"
+)
+
+// AzureDevOps is a fake implementation of Azure DevOps VCS provider.
+type AzureDevOps struct {
+	port int
+	echo *echo.Echo
+
+	client *http.Client
+
+	nextWebhookID int
+	repositories  map[string]*azureRepositoryData
+}
+
+type azureRepositoryData struct {
+	webhooks []*azure.WebhookCreateOrUpdate
+	// files is a map that the full file path is the key and the file content is the
+	// value.
+	files map[string]string
+	// branches is the map for repository branch.
+	// the map key is the branch name, like "main".
+	branches map[string]*azure.Ref
+	// pullRequests is the map for repository pull request.
+	// the map key is the pull request id.
+	pullRequests map[int]*azure.PullRequest
+	// commitsDiff is the map for commits compare.
+	// The map key has the format "base..target" which is the commit ID.
+	commitsDiff map[string][]azure.Change
+}
+
+// NewAzureDevOps creates a new fake implementation of Azure DevOps VCS provider.
+func NewAzureDevOps(port int) VCSProvider {
+	e := newEchoServer()
+	ad := &AzureDevOps{
+		port:          port,
+		echo:          e,
+		client:        &http.Client{},
+		nextWebhookID: 20221213,
+		repositories:  make(map[string]*azureRepositoryData),
+	}
+
+	e.POST("/:organization/_apis/hooks/subscriptions", ad.createSubscription)
+	g := e.Group("/:organization/:project/_apis/git/repositories/:repo")
+	g.GET("", ad.getRepository)
+	g.GET("/commits/:commitID", ad.getRepositoryCommit)
+	g.GET("/diffs/commits", ad.getCommitsDiff)
+	g.GET("/items", ad.getRepositoryItems)
+	g.POST("/pushes", ad.createRepositoryPush)
+	g.GET("/refs", ad.listRepositoryRefs)
+	g.POST("/refs", ad.updateRepositoryRefs)
+	g.POST("/pullrequests", ad.createRepositoryPullRequest)
+	g.GET("/pullrequests/:prID", ad.getRepositoryPullRequest)
+	return ad
+}
+
+// json writes the JSON response with the given status code.
+func (*AzureDevOps) json(c echo.Context, code int, value interface{}) error {
+	buf, err := json.Marshal(value)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("failed to marshal response body: %v", err))
+	}
+	return c.String(code, string(buf))
+}
+
+// list writes the Azure DevOps list response.
+func (ad *AzureDevOps) list(c echo.Context, values interface{}) error {
+	return ad.json(c, http.StatusOK, map[string]interface{}{"value": values})
+}
+
+func (ad *AzureDevOps) createSubscription(c echo.Context) error {
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("failed to read request body for creating subscription: %v", err))
+	}
+
+	var webhookCreate azure.WebhookCreateOrUpdate
+	if err = json.Unmarshal(body, &webhookCreate); err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("failed to unmarshal request body for creating subscription: %v", err))
+	}
+
+	// The repository GUID is the repository ID in the fake.
+	r, ok := ad.repositories[webhookCreate.PublisherInputs.Repository]
+	if !ok {
+		return c.String(http.StatusNotFound, fmt.Sprintf("Azure DevOps repository %q does not exist", webhookCreate.PublisherInputs.Repository))
+	}
+	r.webhooks = append(r.webhooks, &webhookCreate)
+
+	ad.nextWebhookID++
+	return ad.json(c, http.StatusOK, azure.WebhookInfo{ID: strconv.Itoa(ad.nextWebhookID)})
+}
+
+func (ad *AzureDevOps) getRepository(c echo.Context) error {
+	if _, err := ad.validRepository(c); err != nil {
+		return err
+	}
+
+	repositoryID := ad.repositoryID(c)
+	return ad.json(c, http.StatusOK, azure.Repository{
+		ID:   repositoryID,
+		Name: c.Param("repo"),
+		Project: azure.Project{
+			ID:   c.Param("project"),
+			Name: c.Param("project"),
+		},
+	})
+}
+
+func (ad *AzureDevOps) getRepositoryCommit(c echo.Context) error {
+	if _, err := ad.validRepository(c); err != nil {
+		return err
+	}
+
+	return ad.json(c, http.StatusOK, azure.Commit{
+		CommitID: c.Param("commitID"),
+		Comment:  "Fake Azure DevOps commit message",
+		Author: azure.GitUserDate{
+			Name:  "fake_azure_author",
+			Email: "fake_azure_author@localhost",
+			Date:  time.Now(),
+		},
+	})
+}
+
+func (ad *AzureDevOps) getCommitsDiff(c echo.Context) error {
+	r, err := ad.validRepository(c)
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("%s..%s", c.QueryParam("baseVersion"), c.QueryParam("targetVersion"))
+	changes, ok := r.commitsDiff[key]
+	if !ok {
+		return c.String(http.StatusNotFound, fmt.Sprintf("Cannot find the diff key %s", key))
+	}
+	return ad.json(c, http.StatusOK, azure.CommitDiffs{
+		AllChangesIncluded: true,
+		Changes:            changes,
+	})
+}
+
+func (ad *AzureDevOps) getRepositoryItems(c echo.Context) error {
+	r, err := ad.validRepository(c)
+	if err != nil {
+		return err
+	}
+
+	// Read a single file with its content.
+	if filePath := c.QueryParam("path"); filePath != "" {
+		filePath = strings.TrimPrefix(filePath, "/")
+		content, ok := r.files[filePath]
+		if !ok {
+			return c.String(http.StatusNotFound, fmt.Sprintf("file %q not found", filePath))
+		}
+		return ad.json(c, http.StatusOK, azure.Item{
+			GitObjectType: "blob",
+			CommitID:      "fake_azure_commit_id",
+			Path:          "/" + filePath,
+			Content:       content,
+		})
+	}
+
+	// List the files under the scope path recursively.
+	prefix := strings.Trim(c.QueryParam("scopePath"), "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	items := []azure.Item{}
+	for filePath := range r.files {
+		if strings.HasPrefix(filePath, prefix) {
+			items = append(items, azure.Item{
+				GitObjectType: "blob",
+				Path:          "/" + filePath,
+			})
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Path < items[j].Path
+	})
+	return ad.list(c, items)
+}
+
+func (ad *AzureDevOps) createRepositoryPush(c echo.Context) error {
+	r, err := ad.validRepository(c)
+	if err != nil {
+		return err
+	}
+
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("failed to read request body for creating push: %v", err))
+	}
+
+	var push azure.Push
+	if err = json.Unmarshal(body, &push); err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("failed to unmarshal request body for creating push: %v", err))
+	}
+
+	for _, commit := range push.Commits {
+		for _, change := range commit.Changes {
+			filePath := strings.TrimPrefix(change.Item.Path, "/")
+			_, exists := r.files[filePath]
+			if change.ChangeType == "add" && exists {
+				return c.String(http.StatusConflict, fmt.Sprintf("file %q already exists", filePath))
+			}
+			if change.ChangeType == "edit" && !exists {
+				return c.String(http.StatusNotFound, fmt.Sprintf("file %q not found", filePath))
+			}
+			if change.NewContent != nil {
+				r.files[filePath] = change.NewContent.Content
+			}
+		}
+	}
+	return c.String(http.StatusCreated, "{}")
+}
+
+func (ad *AzureDevOps) listRepositoryRefs(c echo.Context) error {
+	r, err := ad.validRepository(c)
+	if err != nil {
+		return err
+	}
+
+	refs := []*azure.Ref{}
+	filter := "refs/" + c.QueryParam("filter")
+	for _, ref := range r.branches {
+		if strings.HasPrefix(ref.Name, filter) {
+			refs = append(refs, ref)
+		}
+	}
+	return ad.list(c, refs)
+}
+
+func (ad *AzureDevOps) updateRepositoryRefs(c echo.Context) error {
+	r, err := ad.validRepository(c)
+	if err != nil {
+		return err
+	}
+
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("failed to read request body for updating refs: %v", err))
+	}
+
+	var refUpdates []azure.RefUpdate
+	if err = json.Unmarshal(body, &refUpdates); err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("failed to unmarshal request body for updating refs: %v", err))
+	}
+
+	var results []azure.RefUpdateResult
+	for _, refUpdate := range refUpdates {
+		branchName := strings.TrimPrefix(refUpdate.Name, "refs/heads/")
+		if _, ok := r.branches[branchName]; ok {
+			results = append(results, azure.RefUpdateResult{Name: refUpdate.Name, UpdateStatus: "staleOldObjectId"})
+			continue
+		}
+		r.branches[branchName] = &azure.Ref{
+			Name:     refUpdate.Name,
+			ObjectID: refUpdate.NewObjectID,
+		}
+		results = append(results, azure.RefUpdateResult{Name: refUpdate.Name, Success: true, UpdateStatus: "succeeded"})
+	}
+	return ad.list(c, results)
+}
+
+func (ad *AzureDevOps) createRepositoryPullRequest(c echo.Context) error {
+	r, err := ad.validRepository(c)
+	if err != nil {
+		return err
+	}
+
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("failed to read request body for creating repository pull request: %v", err))
+	}
+
+	var pullRequestCreate azure.PullRequest
+	if err = json.Unmarshal(body, &pullRequestCreate); err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("failed to unmarshal request body for creating repository pull request: %v", err))
+	}
+
+	head := strings.TrimPrefix(pullRequestCreate.SourceRefName, "refs/heads/")
+	if _, ok := r.branches[head]; !ok {
+		return c.String(http.StatusBadRequest, fmt.Sprintf("the head branch not exists: %v", head))
+	}
+
+	prID := len(r.pullRequests) + 1
+	pullRequest := ad.newPullRequest(ad.repositoryID(c), prID)
+	r.pullRequests[prID] = pullRequest
+	return ad.json(c, http.StatusCreated, pullRequest)
+}
+
+func (ad *AzureDevOps) newPullRequest(repositoryID string, prID int) *azure.PullRequest {
+	return &azure.PullRequest{
+		PullRequestID: prID,
+		Repository: &azure.Repository{
+			WebURL: fmt.Sprintf("http://localhost:%d/%s", ad.port, repositoryID),
+		},
+		LastMergeSourceCommit: &azure.Commit{CommitID: fmt.Sprintf("pr-%d-source", prID)},
+		LastMergeTargetCommit: &azure.Commit{CommitID: fmt.Sprintf("pr-%d-target", prID)},
+	}
+}
+
+func (ad *AzureDevOps) getRepositoryPullRequest(c echo.Context) error {
+	r, err := ad.validRepository(c)
+	if err != nil {
+		return err
+	}
+
+	prID, err := strconv.Atoi(c.Param("prID"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, fmt.Sprintf("The pull request id is invalid: %v", c.Param("prID")))
+	}
+	pullRequest, ok := r.pullRequests[prID]
+	if !ok {
+		return c.String(http.StatusNotFound, fmt.Sprintf("Cannot found the pull request: %v", c.Param("prID")))
+	}
+	return ad.json(c, http.StatusOK, pullRequest)
+}
+
+func (*AzureDevOps) repositoryID(c echo.Context) string {
+	return fmt.Sprintf("%s/%s/%s", c.Param("organization"), c.Param("project"), c.Param("repo"))
+}
+
+func (ad *AzureDevOps) validRepository(c echo.Context) (*azureRepositoryData, error) {
+	repositoryID := ad.repositoryID(c)
+	r, ok := ad.repositories[repositoryID]
+	if !ok {
+		return nil, c.String(http.StatusNotFound, fmt.Sprintf("Azure DevOps repository %q does not exist", repositoryID))
+	}
+
+	return r, nil
+}
+
+// Run starts the Azure DevOps VCS provider server.
+func (ad *AzureDevOps) Run() error {
+	return ad.echo.Start(fmt.Sprintf(":%d", ad.port))
+}
+
+// Close shuts down the Azure DevOps VCS provider server.
+func (ad *AzureDevOps) Close() error {
+	return ad.echo.Close()
+}
+
+// ListenerAddr returns the Azure DevOps VCS provider server listener address.
+func (ad *AzureDevOps) ListenerAddr() net.Addr {
+	return ad.echo.ListenerAddr()
+}
+
+// APIURL returns the Azure DevOps VCS provider API URL.
+func (*AzureDevOps) APIURL(instanceURL string) string {
+	return instanceURL
+}
+
+// CreateRepository creates an Azure DevOps repository with given ID in the
+// format of "{organization}/{project}/{repository}".
+func (ad *AzureDevOps) CreateRepository(id string) {
+	ad.repositories[id] = &azureRepositoryData{
+		files:        make(map[string]string),
+		branches:     make(map[string]*azure.Ref),
+		pullRequests: make(map[int]*azure.PullRequest),
+		commitsDiff:  make(map[string][]azure.Change),
+	}
+}
+
+// CreateBranch creates a new branch with the given name.
+func (ad *AzureDevOps) CreateBranch(id, branchName string) error {
+	r, ok := ad.repositories[id]
+	if !ok {
+		return errors.Errorf("Azure DevOps repository %q doesn't exist", id)
+	}
+
+	if _, ok := r.branches[branchName]; ok {
+		return errors.Errorf("branch %q already exists", branchName)
+	}
+
+	r.branches[branchName] = &azure.Ref{
+		Name:     fmt.Sprintf("refs/heads/%s", branchName),
+		ObjectID: "fake_azure_commit_id",
+	}
+	return nil
+}
+
+// AddCommitsDiff adds a commits diff.
+func (ad *AzureDevOps) AddCommitsDiff(repositoryID, fromCommit, toCommit string, fileDiffList []vcs.FileDiff) error {
+	r, ok := ad.repositories[repositoryID]
+	if !ok {
+		return errors.Errorf("Azure DevOps repository %s doesn't exist", repositoryID)
+	}
+	key := fmt.Sprintf("%s..%s", fromCommit, toCommit)
+	r.commitsDiff[key] = convertToAzureChanges(fileDiffList)
+	return nil
+}
+
+func convertToAzureChanges(fileDiffList []vcs.FileDiff) []azure.Change {
+	changes := []azure.Change{}
+	for _, fileDiff := range fileDiffList {
+		change := azure.Change{
+			Item: azure.Item{
+				GitObjectType: "blob",
+				Path:          "/" + fileDiff.Path,
+			},
+		}
+		switch fileDiff.Type {
+		case vcs.FileDiffTypeAdded:
+			change.ChangeType = "add"
+		case vcs.FileDiffTypeModified:
+			change.ChangeType = "edit"
+		case vcs.FileDiffTypeRemoved:
+			change.ChangeType = "delete"
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// SendWebhookPush sends out a webhook for a push event for the Azure DevOps
+// repository using given payload.
+func (ad *AzureDevOps) SendWebhookPush(repositoryID string, payload []byte) error {
+	r, ok := ad.repositories[repositoryID]
+	if !ok {
+		return errors.Errorf("Azure DevOps repository %q does not exist", repositoryID)
+	}
+
+	// Trigger all webhooks
+	for _, webhook := range r.webhooks {
+		req, err := http.NewRequest("POST", webhook.ConsumerInputs.URL, bytes.NewReader(payload))
+		if err != nil {
+			return errors.Wrapf(err, "failed to create a new POST request to %q", webhook.ConsumerInputs.URL)
+		}
+		for _, header := range strings.Split(webhook.ConsumerInputs.HTTPHeaders, "\n") {
+			if key, value, ok := strings.Cut(header, ":"); ok {
+				req.Header.Set(key, value)
+			}
+		}
+
+		resp, err := ad.client.Do(req)
+		if err != nil {
+			return errors.Wrapf(err, "failed to send POST request to %q", webhook.ConsumerInputs.URL)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return errors.Wrap(err, "failed to read response body")
+		}
+		if resp.StatusCode != http.StatusOK {
+			return errors.Errorf("unexpected response status code %d, body: %s", resp.StatusCode, body)
+		}
+		ad.echo.Logger.Infof("SendWebhookPush response body %s\n", body)
+	}
+	return nil
+}
+
+// AddFiles adds given files to the Azure DevOps repository.
+func (ad *AzureDevOps) AddFiles(repositoryID string, files map[string]string) error {
+	r, ok := ad.repositories[repositoryID]
+	if !ok {
+		return errors.Errorf("Azure DevOps repository %q does not exist", repositoryID)
+	}
+
+	// Save or overwrite files
+	for path, content := range files {
+		r.files[path] = content
+	}
+	return nil
+}
+
+// GetFiles returns files with given paths from the Azure DevOps repository.
+func (ad *AzureDevOps) GetFiles(repositoryID string, filePaths ...string) (map[string]string, error) {
+	r, ok := ad.repositories[repositoryID]
+	if !ok {
+		return nil, errors.Errorf("Azure DevOps repository %q does not exist", repositoryID)
+	}
+
+	// Get files
+	files := make(map[string]string)
+	for _, path := range filePaths {
+		if content, ok := r.files[path]; ok {
+			files[path] = content
+		}
+	}
+	return files, nil
+}
+
+// AddPullRequest creates a new pull request and add changed files to it.
+func (ad *AzureDevOps) AddPullRequest(repositoryID string, prID int, files []*vcs.PullRequestFile) error {
+	r, ok := ad.repositories[repositoryID]
+	if !ok {
+		return errors.Errorf("Azure DevOps repository %q does not exist", repositoryID)
+	}
+
+	var fileDiffList []vcs.FileDiff
+	for _, file := range files {
+		fileDiffType := vcs.FileDiffTypeAdded
+		if file.IsDeleted {
+			fileDiffType = vcs.FileDiffTypeRemoved
+		}
+		fileDiffList = append(fileDiffList, vcs.FileDiff{Path: file.Path, Type: fileDiffType})
+	}
+
+	pullRequest := ad.newPullRequest(repositoryID, prID)
+	r.pullRequests[prID] = pullRequest
+	// The pull request files are the diff between its target and source commits.
+	key := fmt.Sprintf("%s..%s", pullRequest.LastMergeTargetCommit.CommitID, pullRequest.LastMergeSourceCommit.CommitID)
+	r.commitsDiff[key] = convertToAzureChanges(fileDiffList)
+	return nil
+}
@@ -0,0 +1,561 @@
+package fake
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/plugin/vcs"
+	"github.com/bytebase/bytebase/plugin/vcs/bitbucket"
+)
+
+// BitbucketDataCenter is a fake implementation of Bitbucket Data Center VCS provider.
+type BitbucketDataCenter struct {
+	port int
+	echo *echo.Echo
+
+	client *http.Client
+
+	nextWebhookID int
+	repositories  map[string]*bitbucketRepositoryData
+}
+
+type bitbucketRepositoryData struct {
+	webhooks []*bitbucket.DataCenterWebhookCreateOrUpdate
+	// files is a map that the full file path is the key and the file content is the
+	// value.
+	files map[string]string
+	// branches is the map for repository branch.
+	// the map key is the branch name, like "main".
+	branches map[string]*bitbucket.DataCenterBranch
+	// pullRequests is the map for repository pull request.
+	// the map key is the pull request id.
+	pullRequests map[int]struct {
+		Changes []bitbucket.DataCenterChange
+		*bitbucket.DataCenterPullRequest
+	}
+	// commitsDiff is the map for commits compare.
+	// The map key has the format "since..until" which is the commit ID.
+	commitsDiff map[string][]bitbucket.DataCenterChange
+}
+
+// NewBitbucketDataCenter creates a new fake implementation of Bitbucket Data Center VCS provider.
+func NewBitbucketDataCenter(port int) VCSProvider {
+	e := newEchoServer()
+	bb := &BitbucketDataCenter{
+		port:          port,
+		echo:          e,
+		client:        &http.Client{},
+		nextWebhookID: 20221212,
+		repositories:  make(map[string]*bitbucketRepositoryData),
+	}
+
+	g := e.Group("/rest/api/1.0/projects/:project/repos/:repo")
+	g.POST("/webhooks", bb.createRepositoryWebhook)
+	g.GET("/commits", bb.listRepositoryCommits)
+	g.GET("/commits/:commitID", bb.getRepositoryCommit)
+	g.GET("/changes", bb.listChanges)
+	g.GET("/files/*", bb.listRepositoryFiles)
+	g.GET("/raw/*", bb.readRepositoryFile)
+	g.PUT("/browse/*", bb.createRepositoryFile)
+	g.GET("/branches", bb.listRepositoryBranches)
+	g.POST("/branches", bb.createRepositoryBranch)
+	g.POST("/pull-requests", bb.createRepositoryPullRequest)
+	g.GET("/pull-requests/:prID", bb.getRepositoryPullRequest)
+	g.GET("/pull-requests/:prID/changes", bb.listPullRequestChanges)
+	return bb
+}
+
+// page returns the single page response of the Bitbucket Data Center paginated API.
+func (*BitbucketDataCenter) page(c echo.Context, values interface{}) error {
+	buf, err := json.Marshal(
+		map[string]interface{}{
+			"values":     values,
+			"isLastPage": true,
+		},
+	)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("failed to marshal response body: %v", err))
+	}
+	return c.String(http.StatusOK, string(buf))
+}
+
+func (bb *BitbucketDataCenter) createRepositoryWebhook(c echo.Context) error {
+	r, err := bb.validRepository(c)
+	if err != nil {
+		return err
+	}
+
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("failed to read request body for creating repository webhook: %v", err))
+	}
+
+	var webhookCreate bitbucket.DataCenterWebhookCreateOrUpdate
+	if err = json.Unmarshal(body, &webhookCreate); err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("failed to unmarshal request body for creating repository webhook: %v", err))
+	}
+	r.webhooks = append(r.webhooks, &webhookCreate)
+
+	buf, err := json.Marshal(bitbucket.DataCenterWebhookInfo{ID: bb.nextWebhookID})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("failed to marshal response body for creating repository webhook: %v", err))
+	}
+	bb.nextWebhookID++
+	return c.String(http.StatusCreated, string(buf))
+}
+
+func (bb *BitbucketDataCenter) listRepositoryCommits(c echo.Context) error {
+	r, err := bb.validRepository(c)
+	if err != nil {
+		return err
+	}
+
+	commits := []bitbucket.DataCenterCommit{}
+	if _, ok := r.files[c.QueryParam("path")]; ok {
+		commits = append(commits, bb.newCommit("fake_bitbucket_commit_id"))
+	}
+	return bb.page(c, commits)
+}
+
+func (bb *BitbucketDataCenter) getRepositoryCommit(c echo.Context) error {
+	if _, err := bb.validRepository(c); err != nil {
+		return err
+	}
+
+	buf, err := json.Marshal(bb.newCommit(c.Param("commitID")))
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("failed to marshal response body for getting repository commit: %v", err))
+	}
+	return c.String(http.StatusOK, string(buf))
+}
+
+func (*BitbucketDataCenter) newCommit(commitID string) bitbucket.DataCenterCommit {
+	return bitbucket.DataCenterCommit{
+		ID:      commitID,
+		Message: "Fake Bitbucket commit message",
+		Author: bitbucket.DataCenterUser{
+			Name:         "fake_bitbucket_author",
+			EmailAddress: "fake_bitbucket_author@localhost",
+			DisplayName:  "fake_bitbucket_author",
+		},
+		AuthorTimestamp: time.Now().UnixMilli(),
+	}
+}
+
+func (bb *BitbucketDataCenter) listChanges(c echo.Context) error {
+	r, err := bb.validRepository(c)
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("%s..%s", c.QueryParam("since"), c.QueryParam("until"))
+	changes, ok := r.commitsDiff[key]
+	if !ok {
+		return c.String(http.StatusNotFound, fmt.Sprintf("Cannot find the diff key %s", key))
+	}
+	return bb.page(c, changes)
+}
+
+func (bb *BitbucketDataCenter) listRepositoryFiles(c echo.Context) error {
+	r, err := bb.validRepository(c)
+	if err != nil {
+		return err
+	}
+
+	directory, err := url.PathUnescape(c.Param("*"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, fmt.Sprintf("failed to unescape directory %q: %v", c.Param("*"), err))
+	}
+	prefix := ""
+	if directory != "" {
+		prefix = strings.TrimSuffix(directory, "/") + "/"
+	}
+
+	// The file paths are relative to the requested directory.
+	paths := []string{}
+	for filePath := range r.files {
+		if strings.HasPrefix(filePath, prefix) {
+			paths = append(paths, strings.TrimPrefix(filePath, prefix))
+		}
+	}
+	sort.Strings(paths)
+	return bb.page(c, paths)
+}
+
+func (bb *BitbucketDataCenter) readRepositoryFile(c echo.Context) error {
+	r, err := bb.validRepository(c)
+	if err != nil {
+		return err
+	}
+
+	filePath, err := url.PathUnescape(c.Param("*"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, fmt.Sprintf("failed to unescape file path %q: %v", c.Param("*"), err))
+	}
+
+	content, ok := r.files[filePath]
+	if !ok {
+		return c.String(http.StatusNotFound, fmt.Sprintf("file %q not found", filePath))
+	}
+	return c.String(http.StatusOK, content)
+}
+
+func (bb *BitbucketDataCenter) createRepositoryFile(c echo.Context) error {
+	r, err := bb.validRepository(c)
+	if err != nil {
+		return err
+	}
+
+	filePath, err := url.PathUnescape(c.Param("*"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, fmt.Sprintf("failed to unescape file path %q: %v", c.Param("*"), err))
+	}
+
+	content := c.FormValue("content")
+	if _, ok := r.files[filePath]; ok && c.FormValue("sourceCommitId") == "" {
+		return c.String(http.StatusConflict, fmt.Sprintf("file %q already exists", filePath))
+	}
+	r.files[filePath] = content
+	return c.String(http.StatusOK, "")
+}
+
+func (bb *BitbucketDataCenter) listRepositoryBranches(c echo.Context) error {
+	r, err := bb.validRepository(c)
+	if err != nil {
+		return err
+	}
+
+	branches := []*bitbucket.DataCenterBranch{}
+	for name, branch := range r.branches {
+		if strings.Contains(name, c.QueryParam("filterText")) {
+			branches = append(branches, branch)
+		}
+	}
+	return bb.page(c, branches)
+}
+
+func (bb *BitbucketDataCenter) createRepositoryBranch(c echo.Context) error {
+	r, err := bb.validRepository(c)
+	if err != nil {
+		return err
+	}
+
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("failed to read request body for creating repository branch: %v", err))
+	}
+
+	var branchCreate bitbucket.DataCenterBranchCreate
+	if err = json.Unmarshal(body, &branchCreate); err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("failed to unmarshal request body for creating repository branch: %v", err))
+	}
+
+	if _, ok := r.branches[branchCreate.Name]; ok {
+		return c.String(http.StatusBadRequest, fmt.Sprintf("the branch already exists: %v", branchCreate.Name))
+	}
+
+	r.branches[branchCreate.Name] = &bitbucket.DataCenterBranch{
+		ID:           fmt.Sprintf("refs/heads/%s", branchCreate.Name),
+		DisplayID:    branchCreate.Name,
+		LatestCommit: branchCreate.StartPoint,
+	}
+	return c.String(http.StatusOK, "")
+}
+
+func (bb *BitbucketDataCenter) createRepositoryPullRequest(c echo.Context) error {
+	r, err := bb.validRepository(c)
+	if err != nil {
+		return err
+	}
+
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("failed to read request body for creating repository pull request: %v", err))
+	}
+
+	var pullRequestCreate bitbucket.DataCenterPullRequest
+	if err = json.Unmarshal(body, &pullRequestCreate); err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("failed to unmarshal request body for creating repository pull request: %v", err))
+	}
+
+	head := strings.TrimPrefix(pullRequestCreate.FromRef.ID, "refs/heads/")
+	if _, ok := r.branches[head]; !ok {
+		return c.String(http.StatusBadRequest, fmt.Sprintf("the head branch not exists: %v", head))
+	}
+
+	prID := len(r.pullRequests) + 1
+	pullRequest := bb.newPullRequest(c.Param("project"), c.Param("repo"), prID)
+	r.pullRequests[prID] = struct {
+		Changes []bitbucket.DataCenterChange
+		*bitbucket.DataCenterPullRequest
+	}{
+		DataCenterPullRequest: pullRequest,
+	}
+
+	buf, err := json.Marshal(pullRequest)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("failed to marshal response body for creating repository pull request: %v", err))
+	}
+	return c.String(http.StatusCreated, string(buf))
+}
+
+func (bb *BitbucketDataCenter) newPullRequest(projectKey, repositorySlug string, prID int) *bitbucket.DataCenterPullRequest {
+	pullRequest := &bitbucket.DataCenterPullRequest{
+		ID: prID,
+		FromRef: bitbucket.DataCenterPullRequestRef{
+			LatestCommit: "fake_bitbucket_commit_id",
+		},
+	}
+	pullRequest.Links = &struct {
+		Self []bitbucket.DataCenterLink `json:"self"`
+	}{
+		Self: []bitbucket.DataCenterLink{
+			{Href: fmt.Sprintf("http://localhost:%d/projects/%s/repos/%s/pull-requests/%d", bb.port, projectKey, repositorySlug, prID)},
+		},
+	}
+	return pullRequest
+}
+
+func (bb *BitbucketDataCenter) getRepositoryPullRequest(c echo.Context) error {
+	r, err := bb.validRepository(c)
+	if err != nil {
+		return err
+	}
+
+	prID, err := strconv.Atoi(c.Param("prID"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, fmt.Sprintf("The pull request id is invalid: %v", c.Param("prID")))
+	}
+	pullRequest, ok := r.pullRequests[prID]
+	if !ok {
+		return c.String(http.StatusNotFound, fmt.Sprintf("Cannot found the pull request: %v", c.Param("prID")))
+	}
+
+	buf, err := json.Marshal(pullRequest.DataCenterPullRequest)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("failed to marshal response body: %v", err))
+	}
+	return c.String(http.StatusOK, string(buf))
+}
+
+func (bb *BitbucketDataCenter) listPullRequestChanges(c echo.Context) error {
+	r, err := bb.validRepository(c)
+	if err != nil {
+		return err
+	}
+
+	prID, err := strconv.Atoi(c.Param("prID"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, fmt.Sprintf("The pull request id is invalid: %v", c.Param("prID")))
+	}
+	pullRequest, ok := r.pullRequests[prID]
+	if !ok {
+		return c.String(http.StatusNotFound, fmt.Sprintf("Cannot found the pull request: %v", c.Param("prID")))
+	}
+	return bb.page(c, pullRequest.Changes)
+}
+
+func (bb *BitbucketDataCenter) validRepository(c echo.Context) (*bitbucketRepositoryData, error) {
+	repositoryID := fmt.Sprintf("%s/%s", c.Param("project"), c.Param("repo"))
+	r, ok := bb.repositories[repositoryID]
+	if !ok {
+		return nil, c.String(http.StatusNotFound, fmt.Sprintf("Bitbucket repository %q does not exist", repositoryID))
+	}
+
+	return r, nil
+}
+
+// Run starts the Bitbucket Data Center VCS provider server.
+func (bb *BitbucketDataCenter) Run() error {
+	return bb.echo.Start(fmt.Sprintf(":%d", bb.port))
+}
+
+// Close shuts down the Bitbucket Data Center VCS provider server.
+func (bb *BitbucketDataCenter) Close() error {
+	return bb.echo.Close()
+}
+
+// ListenerAddr returns the Bitbucket Data Center VCS provider server listener address.
+func (bb *BitbucketDataCenter) ListenerAddr() net.Addr {
+	return bb.echo.ListenerAddr()
+}
+
+// APIURL returns the Bitbucket Data Center VCS provider API URL.
+func (*BitbucketDataCenter) APIURL(instanceURL string) string {
+	return fmt.Sprintf("%s/rest/api/1.0", instanceURL)
+}
+
+// CreateRepository creates a Bitbucket Data Center repository with given ID
+// in the format of "{projectKey}/{repositorySlug}".
+func (bb *BitbucketDataCenter) CreateRepository(id string) {
+	bb.repositories[id] = &bitbucketRepositoryData{
+		files:    make(map[string]string),
+		branches: make(map[string]*bitbucket.DataCenterBranch),
+		pullRequests: map[int]struct {
+			Changes []bitbucket.DataCenterChange
+			*bitbucket.DataCenterPullRequest
+		}{},
+		commitsDiff: make(map[string][]bitbucket.DataCenterChange),
+	}
+}
+
+// CreateBranch creates a new branch with the given name.
+func (bb *BitbucketDataCenter) CreateBranch(id, branchName string) error {
+	r, ok := bb.repositories[id]
+	if !ok {
+		return errors.Errorf("Bitbucket repository %q doesn't exist", id)
+	}
+
+	if _, ok := r.branches[branchName]; ok {
+		return errors.Errorf("branch %q already exists", branchName)
+	}
+
+	r.branches[branchName] = &bitbucket.DataCenterBranch{
+		ID:           fmt.Sprintf("refs/heads/%s", branchName),
+		DisplayID:    branchName,
+		LatestCommit: "fake_bitbucket_commit_id",
+	}
+	return nil
+}
+
+// AddCommitsDiff adds a commits diff.
+func (bb *BitbucketDataCenter) AddCommitsDiff(repositoryID, fromCommit, toCommit string, fileDiffList []vcs.FileDiff) error {
+	r, ok := bb.repositories[repositoryID]
+	if !ok {
+		return errors.Errorf("Bitbucket repository %s doesn't exist", repositoryID)
+	}
+	key := fmt.Sprintf("%s..%s", fromCommit, toCommit)
+	r.commitsDiff[key] = convertToBitbucketChanges(fileDiffList)
+	return nil
+}
+
+func convertToBitbucketChanges(fileDiffList []vcs.FileDiff) []bitbucket.DataCenterChange {
+	changes := []bitbucket.DataCenterChange{}
+	for _, fileDiff := range fileDiffList {
+		var change bitbucket.DataCenterChange
+		change.Path.ToString = fileDiff.Path
+		switch fileDiff.Type {
+		case vcs.FileDiffTypeAdded:
+			change.Type = "ADD"
+		case vcs.FileDiffTypeModified:
+			change.Type = "MODIFY"
+		case vcs.FileDiffTypeRemoved:
+			change.Type = "DELETE"
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// SendWebhookPush sends out a webhook for a push event for the Bitbucket Data
+// Center repository using given payload.
+func (bb *BitbucketDataCenter) SendWebhookPush(repositoryID string, payload []byte) error {
+	r, ok := bb.repositories[repositoryID]
+	if !ok {
+		return errors.Errorf("Bitbucket repository %q does not exist", repositoryID)
+	}
+
+	// Trigger all webhooks
+	for _, webhook := range r.webhooks {
+		req, err := http.NewRequest("POST", webhook.URL, bytes.NewReader(payload))
+		if err != nil {
+			return errors.Wrapf(err, "failed to create a new POST request to %q", webhook.URL)
+		}
+
+		m := hmac.New(sha256.New, []byte(webhook.Configuration.Secret))
+		if _, err := m.Write(payload); err != nil {
+			return errors.Wrap(err, "failed to calculate SHA256 of the webhook secret")
+		}
+		signature := "sha256=" + hex.EncodeToString(m.Sum(nil))
+		req.Header.Set("X-Hub-Signature", signature)
+		req.Header.Set("X-Event-Key", string(bitbucket.WebhookRepoRefsChanged))
+
+		resp, err := bb.client.Do(req)
+		if err != nil {
+			return errors.Wrapf(err, "failed to send POST request to %q", webhook.URL)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return errors.Wrap(err, "failed to read response body")
+		}
+		if resp.StatusCode != http.StatusOK {
+			return errors.Errorf("unexpected response status code %d, body: %s", resp.StatusCode, body)
+		}
+		bb.echo.Logger.Infof("SendWebhookPush response body %s\n", body)
+	}
+	return nil
+}
+
+// AddFiles adds given files to the Bitbucket Data Center repository.
+func (bb *BitbucketDataCenter) AddFiles(repositoryID string, files map[string]string) error {
+	r, ok := bb.repositories[repositoryID]
+	if !ok {
+		return errors.Errorf("Bitbucket repository %q does not exist", repositoryID)
+	}
+
+	// Save or overwrite files
+	for path, content := range files {
+		r.files[path] = content
+	}
+	return nil
+}
+
+// GetFiles returns files with given paths from the Bitbucket Data Center repository.
+func (bb *BitbucketDataCenter) GetFiles(repositoryID string, filePaths ...string) (map[string]string, error) {
+	r, ok := bb.repositories[repositoryID]
+	if !ok {
+		return nil, errors.Errorf("Bitbucket repository %q does not exist", repositoryID)
+	}
+
+	// Get files
+	files := make(map[string]string)
+	for _, path := range filePaths {
+		if content, ok := r.files[path]; ok {
+			files[path] = content
+		}
+	}
+	return files, nil
+}
+
+// AddPullRequest creates a new pull request and add changed files to it.
+func (bb *BitbucketDataCenter) AddPullRequest(repositoryID string, prID int, files []*vcs.PullRequestFile) error {
+	r, ok := bb.repositories[repositoryID]
+	if !ok {
+		return errors.Errorf("Bitbucket repository %q does not exist", repositoryID)
+	}
+	projectKey, repositorySlug, _ := strings.Cut(repositoryID, "/")
+
+	var fileDiffList []vcs.FileDiff
+	for _, file := range files {
+		fileDiffType := vcs.FileDiffTypeAdded
+		if file.IsDeleted {
+			fileDiffType = vcs.FileDiffTypeRemoved
+		}
+		fileDiffList = append(fileDiffList, vcs.FileDiff{Path: file.Path, Type: fileDiffType})
+	}
+
+	r.pullRequests[prID] = struct {
+		Changes []bitbucket.DataCenterChange
+		*bitbucket.DataCenterPullRequest
+	}{
+		Changes:               convertToBitbucketChanges(fileDiffList),
+		DataCenterPullRequest: bb.newPullRequest(projectKey, repositorySlug, prID),
+	}
+	return nil
+}
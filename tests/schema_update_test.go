@@ -22,6 +22,8 @@ import (
 	"github.com/bytebase/bytebase/plugin/advisor"
 	"github.com/bytebase/bytebase/plugin/db"
 	"github.com/bytebase/bytebase/plugin/vcs"
+	"github.com/bytebase/bytebase/plugin/vcs/azure"
+	"github.com/bytebase/bytebase/plugin/vcs/bitbucket"
 	"github.com/bytebase/bytebase/plugin/vcs/github"
 	"github.com/bytebase/bytebase/plugin/vcs/gitlab"
 	"github.com/bytebase/bytebase/resources/postgres"
@@ -310,42 +312,88 @@ func TestVCS(t *testing.T) {
 			},
 		},
 		{
-			name:               "GitHub",
-			vcsProviderCreator: fake.NewGitHub,
-			vcsType:            vcs.GitHubCom,
-			externalID:         "octocat/Hello-World",
-			repositoryFullPath: "octocat/Hello-World",
-			newWebhookPushEvent: func(added, modified [][]string, beforeSHA, afterSHA string) interface{} {
-				var commits []github.WebhookCommit
-				for i := range added {
-					commits = append(commits, github.WebhookCommit{
-						ID:        "fake_github_commit_id",
-						Distinct:  true,
-						Message:   "Fake GitHub commit message",
-						Timestamp: time.Now(),
-						URL:       "https://api.github.com/octocat/Hello-World/commits/fake_github_commit_id",
-						Author: github.WebhookCommitAuthor{
-							Name:  "fake_github_author",
-							Email: "fake_github_author@localhost",
+			name:                "GitHub",
+			vcsProviderCreator:  fake.NewGitHub,
+			vcsType:             vcs.GitHubCom,
+			externalID:          "octocat/Hello-World",
+			repositoryFullPath:  "octocat/Hello-World",
+			newWebhookPushEvent: newGitHubWebhookPushEvent,
+		},
+		{
+			name:                "GitHubEnterprise",
+			vcsProviderCreator:  fake.NewGitHub,
+			vcsType:             vcs.GitHubEnterprise,
+			externalID:          "octocat/Hello-World",
+			repositoryFullPath:  "octocat/Hello-World",
+			newWebhookPushEvent: newGitHubWebhookPushEvent,
+		},
+		{
+			name:               "BitbucketDataCenter",
+			vcsProviderCreator: fake.NewBitbucketDataCenter,
+			vcsType:            vcs.BitbucketDataCenter,
+			externalID:         "TEST/schema-update",
+			repositoryFullPath: "TEST/schema-update",
+			// The Bitbucket push event doesn't contain the changed files, and they
+			// are fetched from the commits diff instead.
+			newWebhookPushEvent: func(_, _ [][]string, beforeSHA, afterSHA string) interface{} {
+				event := bitbucket.WebhookRefsChangedEvent{
+					EventKey: bitbucket.WebhookRepoRefsChanged,
+					Actor: bitbucket.DataCenterUser{
+						Name:         "fake_bitbucket_author",
+						EmailAddress: "fake_bitbucket_author@localhost",
+						DisplayName:  "fake_bitbucket_author",
+					},
+					Repository: bitbucket.DataCenterRepository{
+						Slug: "schema-update",
+						Project: bitbucket.DataCenterProject{
+							Key: "TEST",
 						},
-						Added:    added[i],
-						Modified: modified[i],
-					})
+					},
 				}
-				return github.WebhookPushEvent{
-					Ref:    "refs/heads/feature/foo",
-					Before: beforeSHA,
-					After:  afterSHA,
-					Repository: github.WebhookRepository{
-						ID:       211,
-						FullName: "octocat/Hello-World",
-						HTMLURL:  "https://github.com/octocat/Hello-World",
+				change := bitbucket.DataCenterRefChange{
+					RefID:    "refs/heads/feature/foo",
+					FromHash: beforeSHA,
+					ToHash:   afterSHA,
+					Type:     "UPDATE",
+				}
+				change.Ref.ID = "refs/heads/feature/foo"
+				change.Ref.DisplayID = "feature/foo"
+				change.Ref.Type = "BRANCH"
+				event.Changes = append(event.Changes, change)
+				return event
+			},
+		},
+		{
+			name:               "AzureDevOps",
+			vcsProviderCreator: fake.NewAzureDevOps,
+			vcsType:            vcs.AzureDevOps,
+			externalID:         "test/schema-update/schema-update",
+			repositoryFullPath: "test/schema-update/schema-update",
+			// The Azure DevOps push event doesn't contain the changed files, and
+			// they are fetched from the commits diff instead.
+			newWebhookPushEvent: func(_, _ [][]string, beforeSHA, afterSHA string) interface{} {
+				event := azure.WebhookPushEvent{
+					EventType: "git.push",
+				}
+				event.Resource.RefUpdates = []azure.RefUpdate{
+					{
+						Name:        "refs/heads/feature/foo",
+						OldObjectID: beforeSHA,
+						NewObjectID: afterSHA,
 					},
-					Sender: github.WebhookSender{
-						Login: "fake_github_author",
+				}
+				event.Resource.Repository = azure.Repository{
+					ID:        "test/schema-update/schema-update",
+					Name:      "schema-update",
+					URL:       "https://dev.azure.com/test/_apis/git/repositories/schema-update",
+					RemoteURL: "https://dev.azure.com/test/schema-update/_git/schema-update",
+					Project: azure.Project{
+						ID:   "schema-update",
+						Name: "schema-update",
 					},
-					Commits: commits,
 				}
+				event.Resource.PushedBy.DisplayName = "fake_azure_author"
+				return event
 			},
 		},
 	}
@@ -658,6 +706,40 @@ func TestVCS(t *testing.T) {
 	}
 }
 
+// newGitHubWebhookPushEvent returns a GitHub push event with the given added and modified files.
+func newGitHubWebhookPushEvent(added, modified [][]string, beforeSHA, afterSHA string) interface{} {
+	var commits []github.WebhookCommit
+	for i := range added {
+		commits = append(commits, github.WebhookCommit{
+			ID:        "fake_github_commit_id",
+			Distinct:  true,
+			Message:   "Fake GitHub commit message",
+			Timestamp: time.Now(),
+			URL:       "https://api.github.com/octocat/Hello-World/commits/fake_github_commit_id",
+			Author: github.WebhookCommitAuthor{
+				Name:  "fake_github_author",
+				Email: "fake_github_author@localhost",
+			},
+			Added:    added[i],
+			Modified: modified[i],
+		})
+	}
+	return github.WebhookPushEvent{
+		Ref:    "refs/heads/feature/foo",
+		Before: beforeSHA,
+		After:  afterSHA,
+		Repository: github.WebhookRepository{
+			ID:       211,
+			FullName: "octocat/Hello-World",
+			HTMLURL:  "https://github.com/octocat/Hello-World",
+		},
+		Sender: github.WebhookSender{
+			Login: "fake_github_author",
+		},
+		Commits: commits,
+	}
+}
+
 func TestVCS_SDL(t *testing.T) {
 	tests := []struct {
 		name                string